@@ -16,6 +16,17 @@ func (APICallerFunc) BestFacadeVersion(facade string) int {
 	return 0
 }
 
+// BestVersionCaller is an APICallerFunc that reports the given version
+// as the best version of every facade.
+type BestVersionCaller struct {
+	APICallerFunc
+	BestVersion int
+}
+
+func (c BestVersionCaller) BestFacadeVersion(facade string) int {
+	return c.BestVersion
+}
+
 func (APICallerFunc) EnvironTag() (names.EnvironTag, error) {
 	return names.NewEnvironTag(""), nil
 }
@@ -4,6 +4,8 @@
 package block
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 
@@ -49,9 +51,24 @@ func (c *Client) List() ([]params.Block, error) {
 // SwitchBlockOn switches desired block on for the current environment.
 // Valid block types are "BlockDestroy", "BlockRemove" and "BlockChange".
 func (c *Client) SwitchBlockOn(blockType, msg string) error {
+	return c.SwitchEntityBlockOn(blockType, msg, "", nil)
+}
+
+// SwitchEntityBlockOn switches desired block on for the service or
+// machine with the given tag, or for the current environment if entity
+// is empty. If expiry is not nil, the block no longer applies after
+// that time.
+func (c *Client) SwitchEntityBlockOn(blockType, msg, entity string, expiry *time.Time) error {
+	if (entity != "" || expiry != nil) && c.BestAPIVersion() < 2 {
+		// Older servers ignore the entity and expiry, and would
+		// block the whole environment indefinitely.
+		return errors.NotSupportedf("service, machine or expiring blocks on this API server")
+	}
 	args := params.BlockSwitchParams{
 		Type:    blockType,
 		Message: msg,
+		Entity:  entity,
+		Expiry:  expiry,
 	}
 	result := params.ErrorResult{}
 	if err := c.facade.FacadeCall("SwitchBlockOn", args, &result); err != nil {
@@ -66,8 +83,21 @@ func (c *Client) SwitchBlockOn(blockType, msg string) error {
 // SwitchBlockOff switches desired block off for the current environment.
 // Valid block types are "BlockDestroy", "BlockRemove" and "BlockChange".
 func (c *Client) SwitchBlockOff(blockType string) error {
+	return c.SwitchEntityBlockOff(blockType, "")
+}
+
+// SwitchEntityBlockOff switches desired block off for the service or
+// machine with the given tag, or for the current environment if entity
+// is empty.
+func (c *Client) SwitchEntityBlockOff(blockType, entity string) error {
+	if entity != "" && c.BestAPIVersion() < 2 {
+		// Older servers ignore the entity, and would remove
+		// the block on the whole environment.
+		return errors.NotSupportedf("service or machine blocks on this API server")
+	}
 	args := params.BlockSwitchParams{
-		Type: blockType,
+		Type:   blockType,
+		Entity: entity,
 	}
 	result := params.ErrorResult{}
 	if err := c.facade.FacadeCall("SwitchBlockOff", args, &result); err != nil {
@@ -4,6 +4,8 @@
 package block_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -54,6 +56,100 @@ func (s *blockMockSuite) TestSwitchBlockOn(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 }
 
+func (s *blockMockSuite) TestSwitchEntityBlockOn(c *gc.C) {
+	blockType := state.RemoveBlock.String()
+	expiry := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Block")
+			c.Check(request, gc.Equals, "SwitchBlockOn")
+			c.Check(a, jc.DeepEquals, params.BlockSwitchParams{
+				Type:    blockType,
+				Message: "keep the data",
+				Entity:  "service-postgresql",
+				Expiry:  &expiry,
+			})
+			return nil
+		})
+	blockClient := block.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: apiCaller,
+		BestVersion:   2,
+	})
+	err := blockClient.SwitchEntityBlockOn(blockType, "keep the data", "service-postgresql", &expiry)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *blockMockSuite) TestSwitchEntityBlockOnOldServer(c *gc.C) {
+	expiry := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		})
+	blockClient := block.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: apiCaller,
+		BestVersion:   1,
+	})
+	blockType := state.RemoveBlock.String()
+
+	err := blockClient.SwitchEntityBlockOn(blockType, "", "service-postgresql", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = blockClient.SwitchEntityBlockOn(blockType, "", "", &expiry)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *blockMockSuite) TestSwitchEntityBlockOff(c *gc.C) {
+	blockType := state.ChangeBlock.String()
+
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Block")
+			c.Check(request, gc.Equals, "SwitchBlockOff")
+			c.Check(a, jc.DeepEquals, params.BlockSwitchParams{
+				Type:   blockType,
+				Entity: "machine-0",
+			})
+			return nil
+		})
+	blockClient := block.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: apiCaller,
+		BestVersion:   2,
+	})
+	err := blockClient.SwitchEntityBlockOff(blockType, "machine-0")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *blockMockSuite) TestSwitchEntityBlockOffOldServer(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		})
+	blockClient := block.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: apiCaller,
+		BestVersion:   1,
+	})
+	err := blockClient.SwitchEntityBlockOff(state.ChangeBlock.String(), "machine-0")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *blockMockSuite) TestSwitchBlockOnError(c *gc.C) {
 	called := false
 	errmsg := "test error"
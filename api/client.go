@@ -195,6 +195,207 @@ func (c *Client) UnitStatusHistory(kind params.HistoryKind, unitName string, siz
 	return &results, nil
 }
 
+// EntityStatusHistory retrieves the last <size> results of
+// <kind:combined|agent|workload|instance|service> status for the
+// unit, machine or service with the given tag.
+func (c *Client) EntityStatusHistory(kind params.HistoryKind, tag names.Tag, size int) (*UnitStatusHistory, error) {
+	var results UnitStatusHistory
+	args := params.EntityStatusHistory{
+		Kind: kind,
+		Size: size,
+		Tag:  tag.String(),
+	}
+	err := c.facade.FacadeCall("EntityStatusHistory", args, &results)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return &UnitStatusHistory{}, errors.NotImplementedf("EntityStatusHistory")
+		}
+		return &UnitStatusHistory{}, errors.Trace(err)
+	}
+	return &results, nil
+}
+
+// FirewallStatus retrieves, for each machine (or, in global firewall
+// mode, for the environment), the ingress rules that Juju expected to
+// be open and those the provider reported as open when last checked.
+func (c *Client) FirewallStatus() ([]params.FirewallStatus, error) {
+	var results params.FirewallStatusResults
+	err := c.facade.FacadeCall("FirewallStatus", nil, &results)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return nil, errors.NotImplementedf("FirewallStatus")
+		}
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// EnvironmentEvents retrieves a timeline of the status changes, hook
+// failures and finished actions in the environment, filtered by the
+// given arguments and ordered from oldest to newest.
+func (c *Client) EnvironmentEvents(args params.EnvironmentEventsArgs) ([]params.EnvironmentEvent, error) {
+	var results params.EnvironmentEventsResults
+	err := c.facade.FacadeCall("EnvironmentEvents", args, &results)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return nil, errors.NotImplementedf("EnvironmentEvents")
+		}
+		return nil, errors.Trace(err)
+	}
+	return results.Events, nil
+}
+
+// AddWebhook registers a webhook to be notified of status transitions
+// in the environment.
+func (c *Client) AddWebhook(args params.WebhookParams) (params.Webhook, error) {
+	var result params.Webhook
+	err := c.facade.FacadeCall("AddWebhook", args, &result)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return result, errors.NotImplementedf("AddWebhook")
+		}
+		return result, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// RemoveWebhook removes the webhook with the given id.
+func (c *Client) RemoveWebhook(id string) error {
+	err := c.facade.FacadeCall("RemoveWebhook", params.WebhookId{Id: id}, nil)
+	if params.IsCodeNotImplemented(err) {
+		return errors.NotImplementedf("RemoveWebhook")
+	}
+	return err
+}
+
+// Webhooks returns the webhooks registered in the environment.
+func (c *Client) Webhooks() ([]params.Webhook, error) {
+	var results params.WebhooksResults
+	err := c.facade.FacadeCall("Webhooks", nil, &results)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return nil, errors.NotImplementedf("Webhooks")
+		}
+		return nil, errors.Trace(err)
+	}
+	return results.Webhooks, nil
+}
+
+// WebhookDeliveries returns at most size of the most recent deliveries
+// to the webhook with the given id, newest first.
+func (c *Client) WebhookDeliveries(id string, size int) ([]params.WebhookDelivery, error) {
+	var results params.WebhookDeliveriesResults
+	args := params.WebhookDeliveriesArgs{Id: id, Size: size}
+	err := c.facade.FacadeCall("WebhookDeliveries", args, &results)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return nil, errors.NotImplementedf("WebhookDeliveries")
+		}
+		return nil, errors.Trace(err)
+	}
+	return results.Deliveries, nil
+}
+
+// SetAutoscalePolicy sets the policy by which the number of units of
+// the service is adjusted according to the metrics its units report.
+func (c *Client) SetAutoscalePolicy(service string, policy params.AutoscalePolicy) error {
+	args := params.SetAutoscalePolicy{ServiceName: service, Policy: policy}
+	err := c.facade.FacadeCall("SetAutoscalePolicy", args, nil)
+	if params.IsCodeNotImplemented(err) {
+		return errors.NotImplementedf("SetAutoscalePolicy")
+	}
+	return err
+}
+
+// ClearAutoscalePolicy removes the service's autoscale policy.
+func (c *Client) ClearAutoscalePolicy(service string) error {
+	args := params.ClearAutoscalePolicy{ServiceName: service}
+	err := c.facade.FacadeCall("ClearAutoscalePolicy", args, nil)
+	if params.IsCodeNotImplemented(err) {
+		return errors.NotImplementedf("ClearAutoscalePolicy")
+	}
+	return err
+}
+
+// Autoscale returns the service's autoscale policy, which is nil if
+// the service is not autoscaled, and at most historySize of its most
+// recent scaling decisions, newest first.
+func (c *Client) Autoscale(service string, historySize int) (params.AutoscaleResult, error) {
+	var result params.AutoscaleResult
+	args := params.AutoscaleArgs{ServiceName: service, HistorySize: historySize}
+	err := c.facade.FacadeCall("Autoscale", args, &result)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return result, errors.NotImplementedf("Autoscale")
+		}
+		return result, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// SetPlacementPolicy sets the policy restricting the number of units
+// of the service and the machines they may be assigned to.
+func (c *Client) SetPlacementPolicy(service string, policy params.PlacementPolicy) error {
+	args := params.SetPlacementPolicy{ServiceName: service, Policy: policy}
+	err := c.facade.FacadeCall("SetPlacementPolicy", args, nil)
+	if params.IsCodeNotImplemented(err) {
+		return errors.NotImplementedf("SetPlacementPolicy")
+	}
+	return err
+}
+
+// ClearPlacementPolicy removes the service's placement policy.
+func (c *Client) ClearPlacementPolicy(service string) error {
+	args := params.ClearPlacementPolicy{ServiceName: service}
+	err := c.facade.FacadeCall("ClearPlacementPolicy", args, nil)
+	if params.IsCodeNotImplemented(err) {
+		return errors.NotImplementedf("ClearPlacementPolicy")
+	}
+	return err
+}
+
+// PlacementPolicy returns the service's placement policy, which is nil
+// if the service has none.
+func (c *Client) PlacementPolicy(service string) (*params.PlacementPolicy, error) {
+	var result params.PlacementPolicyResult
+	args := params.PlacementPolicyArgs{ServiceName: service}
+	err := c.facade.FacadeCall("PlacementPolicy", args, &result)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return nil, errors.NotImplementedf("PlacementPolicy")
+		}
+		return nil, errors.Trace(err)
+	}
+	return result.Policy, nil
+}
+
+// SetServiceStateless marks the service as stateless, or not, allowing
+// its units to be moved to replacement machines when the machines they
+// are assigned to fail.
+func (c *Client) SetServiceStateless(service string, stateless bool) error {
+	args := params.SetServiceStateless{ServiceName: service, Stateless: stateless}
+	err := c.facade.FacadeCall("SetServiceStateless", args, nil)
+	if params.IsCodeNotImplemented(err) {
+		return errors.NotImplementedf("SetServiceStateless")
+	}
+	return err
+}
+
+// SelfHealHistory returns at most size of the most recent replacements
+// of failed machines, newest first.
+func (c *Client) SelfHealHistory(size int) ([]params.SelfHealEvent, error) {
+	var result params.SelfHealHistoryResult
+	args := params.SelfHealHistoryArgs{Size: size}
+	err := c.facade.FacadeCall("SelfHealHistory", args, &result)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return nil, errors.NotImplementedf("SelfHealHistory")
+		}
+		return nil, errors.Trace(err)
+	}
+	return result.Events, nil
+}
+
 // LegacyMachineStatus holds just the instance-id of a machine.
 type LegacyMachineStatus struct {
 	InstanceId string // Not type instance.Id just to match original api.
@@ -363,9 +564,15 @@ func (c *Client) ForceDestroyMachines(machines ...string) error {
 }
 
 // ServiceExpose changes the juju-managed firewall to expose any ports that
-// were also explicitly marked by units as open.
-func (c *Client) ServiceExpose(service string) error {
-	params := params.ServiceExpose{ServiceName: service}
+// were also explicitly marked by units as open. If source CIDRs are
+// specified, the ports are only exposed to addresses within them.
+func (c *Client) ServiceExpose(service string, sourceCIDRs ...string) error {
+	if len(sourceCIDRs) > 0 && c.BestAPIVersion() < 1 {
+		// Older servers ignore the CIDRs and would expose the
+		// service to any address.
+		return errors.NotSupportedf("exposing a service to specific source CIDRs on this API server")
+	}
+	params := params.ServiceExpose{ServiceName: service, SourceCIDRs: sourceCIDRs}
 	return c.facade.FacadeCall("ServiceExpose", params, nil)
 }
 
@@ -376,6 +583,30 @@ func (c *Client) ServiceUnexpose(service string) error {
 	return c.facade.FacadeCall("ServiceUnexpose", params, nil)
 }
 
+// ServiceOffer offers the named endpoints of a service, or all of them
+// if none are named, for relating to services in other environments.
+// If offerName is empty, the offer is named after the service.
+func (c *Client) ServiceOffer(service string, endpoints []string, offerName string) error {
+	params := params.ServiceOffer{
+		ServiceName: service,
+		Endpoints:   endpoints,
+		OfferName:   offerName,
+	}
+	return c.facade.FacadeCall("ServiceOffer", params, nil)
+}
+
+// ServiceConsume adds a remote service, consuming the named offer of the
+// environment with the given name or UUID. If service is empty, the
+// remote service is named after the offer.
+func (c *Client) ServiceConsume(sourceEnv, offerName, service string) error {
+	params := params.ServiceConsume{
+		SourceEnvironment: sourceEnv,
+		OfferName:         offerName,
+		ServiceName:       service,
+	}
+	return c.facade.FacadeCall("ServiceConsume", params, nil)
+}
+
 // ServiceDeployWithNetworks works exactly like ServiceDeploy, but
 // allows the specification of requested networks that must be present
 // on the machines where the service is deployed. Another way to specify
@@ -456,6 +687,27 @@ func (c *Client) AddServiceUnits(service string, numUnits int, machineSpec strin
 	return results.Units, err
 }
 
+// AddServiceUnitWithStorage adds a unit to a service, attaching the
+// existing, detached storage instances with the specified IDs to it.
+func (c *Client) AddServiceUnitWithStorage(service string, storageIds []string) (string, error) {
+	args := params.AddServiceUnits{
+		ServiceName:   service,
+		NumUnits:      1,
+		AttachStorage: make([]string, len(storageIds)),
+	}
+	for i, id := range storageIds {
+		args.AttachStorage[i] = names.NewStorageTag(id).String()
+	}
+	results := new(params.AddServiceUnitsResults)
+	if err := c.facade.FacadeCall("AddServiceUnits", args, results); err != nil {
+		return "", err
+	}
+	if len(results.Units) != 1 {
+		return "", errors.Errorf("expected 1 unit, got %d", len(results.Units))
+	}
+	return results.Units[0], nil
+}
+
 // DestroyServiceUnits decreases the number of units dedicated to a service.
 func (c *Client) DestroyServiceUnits(unitNames ...string) error {
 	params := params.DestroyServiceUnits{unitNames}
@@ -501,6 +753,65 @@ func (c *Client) SetEnvironmentConstraints(constraints constraints.Value) error
 	return c.facade.FacadeCall("SetEnvironmentConstraints", params, nil)
 }
 
+// PlanServiceDeploy reports what deploying a service with the given
+// parameters would do, without deploying it. The charm must already
+// have been added to the environment.
+func (c *Client) PlanServiceDeploy(args params.ServiceDeploy) (params.ChangePlan, error) {
+	return c.plan("PlanServiceDeploy", args)
+}
+
+// PlanAddRelation reports what adding a relation between the specified
+// endpoints would do, without adding it.
+func (c *Client) PlanAddRelation(endpoints ...string) (params.ChangePlan, error) {
+	return c.plan("PlanAddRelation", params.AddRelation{Endpoints: endpoints})
+}
+
+// PlanServiceDestroy reports what destroying the service would do,
+// without destroying it.
+func (c *Client) PlanServiceDestroy(service string) (params.ChangePlan, error) {
+	return c.plan("PlanServiceDestroy", params.ServiceDestroy{ServiceName: service})
+}
+
+// PlanServiceSetCharm reports what setting the charm for the service
+// would do, without setting it.
+func (c *Client) PlanServiceSetCharm(serviceName string, charmUrl string, force bool) (params.ChangePlan, error) {
+	args := params.ServiceSetCharm{
+		ServiceName: serviceName,
+		CharmUrl:    charmUrl,
+		Force:       force,
+	}
+	return c.plan("PlanServiceSetCharm", args)
+}
+
+// PlanSetServiceConstraints reports what specifying the constraints
+// for the given service would do, without specifying them.
+func (c *Client) PlanSetServiceConstraints(service string, constraints constraints.Value) (params.ChangePlan, error) {
+	args := params.SetConstraints{
+		ServiceName: service,
+		Constraints: constraints,
+	}
+	return c.plan("PlanSetServiceConstraints", args)
+}
+
+// PlanSetEnvironmentConstraints reports what specifying the constraints
+// for the environment would do, without specifying them.
+func (c *Client) PlanSetEnvironmentConstraints(constraints constraints.Value) (params.ChangePlan, error) {
+	return c.plan("PlanSetEnvironmentConstraints", params.SetConstraints{Constraints: constraints})
+}
+
+// plan calls the named Plan method of the Client facade, which reports
+// what the corresponding call would do without making it.
+func (c *Client) plan(method string, args interface{}) (params.ChangePlan, error) {
+	var result params.ChangePlan
+	if err := c.facade.FacadeCall(method, args, &result); err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return params.ChangePlan{}, errors.NotImplementedf("%s", method)
+		}
+		return params.ChangePlan{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
 // CharmInfo holds information about a charm.
 type CharmInfo struct {
 	Revision int
@@ -539,6 +539,23 @@ func (s *clientSuite) TestEnvironmentUnset(c *gc.C) {
 	c.Assert(found, jc.IsFalse)
 }
 
+func (s *clientSuite) TestServiceExposeSourceCIDRsOldServer(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.PatchValue(api.FacadeVersions, map[string]int{"Client": 0})
+	client := s.APIState.Client()
+	c.Assert(client.BestAPIVersion(), gc.Equals, 0)
+
+	err := client.ServiceExpose("wordpress", "10.0.0.0/8")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	svc, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.IsExposed(), jc.IsFalse)
+
+	// Exposing to any address still works.
+	err = client.ServiceExpose("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 // badReader raises err when Read is called.
 type badReader struct {
 	err error
@@ -4,6 +4,7 @@
 package diskmanager
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/names"
 
 	"github.com/juju/juju/api/base"
@@ -43,3 +44,35 @@ func (st *State) SetMachineBlockDevices(devices []storage.BlockDevice) error {
 	}
 	return results.OneError()
 }
+
+// FilesystemAttachments returns the provisioned filesystem attachments
+// of the machine identified by the authenticated machine tag.
+func (st *State) FilesystemAttachments() ([]params.FilesystemAttachment, error) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: st.tag.String()}},
+	}
+	var results params.FilesystemAttachmentsResults
+	err := st.facade.FacadeCall("FilesystemAttachments", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Result, nil
+}
+
+// SetFilesystemUsage records the usage of filesystems attached to the
+// machine identified by the authenticated machine tag.
+func (st *State) SetFilesystemUsage(usage []params.FilesystemUsage) error {
+	args := params.FilesystemUsages{Usages: usage}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetFilesystemUsage", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.Combine()
+}
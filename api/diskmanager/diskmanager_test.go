@@ -124,3 +124,58 @@ func (s *DiskManagerSuite) TestSetMachineBlockDevicesResultCountInvalid(c *gc.C)
 		c.Check(err, gc.ErrorMatches, fmt.Sprintf("expected 1 result, got %d", n))
 	}
 }
+
+func (s *DiskManagerSuite) TestFilesystemAttachments(c *gc.C) {
+	attachments := []params.FilesystemAttachment{{
+		FilesystemTag: "filesystem-123-0",
+		MachineTag:    "machine-123",
+		MountPoint:    "/srv/data",
+	}}
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "DiskManager")
+		c.Check(request, gc.Equals, "FilesystemAttachments")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-123"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.FilesystemAttachmentsResults{})
+		*(result.(*params.FilesystemAttachmentsResults)) = params.FilesystemAttachmentsResults{
+			Results: []params.FilesystemAttachmentsResult{{Result: attachments}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := diskmanager.NewState(apiCaller, names.NewMachineTag("123"))
+	result, err := st.FilesystemAttachments()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, attachments)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsage(c *gc.C) {
+	usage := []params.FilesystemUsage{{
+		FilesystemTag: "filesystem-123-0",
+		MachineTag:    "machine-123",
+		Usage:         params.StorageUsage{UsedBytes: 1, FreeBytes: 2},
+	}}
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "DiskManager")
+		c.Check(request, gc.Equals, "SetFilesystemUsage")
+		c.Check(arg, gc.DeepEquals, params.FilesystemUsages{Usages: usage})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "boom"},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := diskmanager.NewState(apiCaller, names.NewMachineTag("123"))
+	err := st.SetFilesystemUsage(usage)
+	c.Check(err, gc.ErrorMatches, "boom")
+	c.Check(callCount, gc.Equals, 1)
+}
@@ -16,16 +16,16 @@ var facadeVersions = map[string]int{
 	"AllWatcher":                   0,
 	"Annotations":                  1,
 	"Backups":                      0,
-	"Block":                        1,
+	"Block":                        2,
 	"Charms":                       1,
 	"CharmRevisionUpdater":         0,
-	"Client":                       0,
+	"Client":                       1,
 	"Deployer":                     0,
 	"DiskManager":                  1,
 	"Environment":                  0,
 	"EnvironmentManager":           1,
 	"FilesystemAttachmentsWatcher": 1,
-	"Firewaller":                   1,
+	"Firewaller":                   2,
 	"HighAvailability":             1,
 	"ImageManager":                 1,
 	"KeyManager":                   0,
@@ -41,13 +41,14 @@ var facadeVersions = map[string]int{
 	"Provisioner":                  0,
 	"Reboot":                       1,
 	"RelationUnitsWatcher":         0,
+	"RemoteRelations":              1,
 	"Rsyslog":                      0,
 	"Service":                      1,
 	"Storage":                      1,
 	"StorageProvisioner":           1,
 	"StringsWatcher":               0,
 	"Upgrader":                     0,
-	"Uniter":                       2,
+	"Uniter":                       3,
 	"UserManager":                  0,
 	"VolumeAttachmentsWatcher":     1,
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
 )
 
 const firewallerFacade = "Firewaller"
@@ -114,3 +115,22 @@ func (st *State) WatchOpenedPorts() (watcher.StringsWatcher, error) {
 	w := watcher.NewStringsWatcher(st.facade.RawAPICaller(), result)
 	return w, nil
 }
+
+// SetFirewallStatus records the ingress rules that the firewaller
+// expected to be open for the machine or environment with the given
+// tag, and those that the provider reported as open.
+func (st *State) SetFirewallStatus(tag names.Tag, expected, actual []network.IngressRule) error {
+	var results params.ErrorResults
+	args := params.FirewallStatusArgs{
+		Args: []params.FirewallStatusArg{{
+			Tag:      tag.String(),
+			Expected: params.FromNetworkIngressRules(expected),
+			Actual:   params.FromNetworkIngressRules(actual),
+		}},
+	}
+	err := st.facade.FacadeCall("SetFirewallStatus", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
@@ -75,3 +75,30 @@ func (s *Service) IsExposed() (bool, error) {
 	}
 	return result.Result, nil
 }
+
+// ExposedSourceCIDRs returns the CIDRs from which the service may be
+// accessed when exposed. If no CIDRs are returned, it may be accessed
+// from any address.
+func (s *Service) ExposedSourceCIDRs() ([]string, error) {
+	if s.st.BestAPIVersion() < 2 {
+		// Older servers cannot restrict exposed services to
+		// source CIDRs.
+		return nil, nil
+	}
+	var results params.StringsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetExposedSourceCIDRs", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
@@ -94,3 +94,19 @@ func (s *serviceSuite) TestIsExposed(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(isExposed, jc.IsFalse)
 }
+
+func (s *serviceSuite) TestExposedSourceCIDRs(c *gc.C) {
+	err := s.service.SetExposedTo([]string{"10.0.0.0/8", "203.0.113.4/32"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cidrs, err := s.apiService.ExposedSourceCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, []string{"10.0.0.0/8", "203.0.113.4/32"})
+
+	err = s.service.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cidrs, err = s.apiService.ExposedSourceCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.HasLen, 0)
+}
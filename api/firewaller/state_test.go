@@ -9,6 +9,7 @@ import (
 
 	apitesting "github.com/juju/juju/api/testing"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 )
@@ -106,3 +107,16 @@ func (s *stateSuite) TestWatchOpenedPorts(c *gc.C) {
 	statetesting.AssertStop(c, w)
 	wc.AssertClosed()
 }
+
+func (s *stateSuite) TestSetFirewallStatus(c *gc.C) {
+	http := network.IngressRule{network.MustParsePortRange("80/tcp"), network.AnySourceCIDR}
+	err := s.firewaller.SetFirewallStatus(s.machines[0].Tag(), []network.IngressRule{http}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := s.State.FirewallStatus(s.machines[0].Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Missing(), jc.DeepEquals, []network.IngressRule{http})
+
+	err = s.firewaller.SetFirewallStatus(s.units[0].Tag(), nil, nil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package remoterelations
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const remoteRelationsFacade = "RemoteRelations"
+
+// State provides access to the RemoteRelations API facade.
+type State struct {
+	facade base.FacadeCaller
+}
+
+// NewState creates a new client-side RemoteRelations API facade.
+func NewState(caller base.APICaller) *State {
+	return &State{base.NewFacadeCaller(caller, remoteRelationsFacade)}
+}
+
+// RemoteRelations returns the relations between local services and
+// services consumed from other environments, along with the local
+// units in scope in each.
+func (st *State) RemoteRelations() ([]params.RemoteRelation, error) {
+	var result params.RemoteRelationsResults
+	if err := st.facade.FacadeCall("RemoteRelations", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Relations, nil
+}
+
+// PublishRelations publishes the relations, and the local units in scope
+// in each, to the environments offering their remote services. For each
+// relation, the units of the remote service in scope are returned. The
+// relations must include all of the environment's relations with remote
+// services, as the counterparts of any others are destroyed.
+func (st *State) PublishRelations(relations []params.RemoteRelation) ([]params.RemoteRelationUnitsResult, error) {
+	args := params.PublishRelationArgs{Relations: relations}
+	var results params.RemoteRelationUnitsResults
+	if err := st.facade.FacadeCall("PublishRelations", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(relations) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(relations), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// SetRemoteUnits places the supplied units of each relation's remote
+// service in scope, and removes from scope any others.
+func (st *State) SetRemoteUnits(args []params.RemoteRelationUnits) ([]params.ErrorResult, error) {
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetRemoteUnits", params.RemoteRelationUnitsArgs{Args: args}, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(args) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(args), len(results.Results))
+	}
+	return results.Results, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package remoterelations_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/remoterelations"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type remoteRelationsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&remoteRelationsSuite{})
+
+func (s *remoteRelationsSuite) TestRemoteRelations(c *gc.C) {
+	relations := []params.RemoteRelation{{Key: "wordpress:db mysql:server"}}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "RemoteRelations")
+		c.Check(request, gc.Equals, "RemoteRelations")
+		c.Check(arg, gc.IsNil)
+		c.Assert(result, gc.FitsTypeOf, &params.RemoteRelationsResults{})
+		*(result.(*params.RemoteRelationsResults)) = params.RemoteRelationsResults{Relations: relations}
+		return nil
+	})
+	st := remoterelations.NewState(apiCaller)
+	result, err := st.RemoteRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, relations)
+}
+
+func (s *remoteRelationsSuite) TestPublishRelations(c *gc.C) {
+	relations := []params.RemoteRelation{{Key: "wordpress:db mysql:server"}}
+	units := []params.RemoteRelationUnit{{Unit: "mysql/0", Settings: params.Settings{"host": "10.0.0.1"}}}
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "RemoteRelations")
+		c.Check(request, gc.Equals, "PublishRelations")
+		c.Check(arg, jc.DeepEquals, params.PublishRelationArgs{Relations: relations})
+		c.Assert(result, gc.FitsTypeOf, &params.RemoteRelationUnitsResults{})
+		*(result.(*params.RemoteRelationUnitsResults)) = params.RemoteRelationUnitsResults{
+			Results: []params.RemoteRelationUnitsResult{{Units: units}},
+		}
+		return nil
+	})
+	st := remoterelations.NewState(apiCaller)
+	results, err := st.PublishRelations(relations)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.RemoteRelationUnitsResult{{Units: units}})
+}
+
+func (s *remoteRelationsSuite) TestSetRemoteUnitsResultCountMismatch(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "SetRemoteUnits")
+		return nil
+	})
+	st := remoterelations.NewState(apiCaller)
+	_, err := st.SetRemoteUnits([]params.RemoteRelationUnits{{Key: "wordpress:db mysql:server"}})
+	c.Assert(err, gc.ErrorMatches, `expected 1 result\(s\), got 0`)
+}
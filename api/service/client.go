@@ -44,6 +44,24 @@ func (c *Client) SetMetricCredentials(service string, credentials []byte) error
 	return errors.Trace(results.OneError())
 }
 
+// SetStorageDefaults sets the storage pool defaults for the service
+// specified. Empty pool names defer to the environment's defaults.
+func (c *Client) SetStorageDefaults(service, blockPool, filesystemPool string) error {
+	args := params.ServicesStorageDefaults{
+		Defaults: []params.ServiceStorageDefaults{{
+			ServiceName:    service,
+			BlockPool:      blockPool,
+			FilesystemPool: filesystemPool,
+		}},
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("SetStorageDefaults", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(results.OneError())
+}
+
 // ServiceDeploy obtains the charm, either locally or from
 // the charm store, and deploys it. It allows the specification of
 // requested networks that must be present on the machines where the
@@ -73,6 +73,28 @@ func (s *serviceSuite) TestSetServiceMetricCredentialsNoMocks(c *gc.C) {
 	c.Assert(service.MetricCredentials(), gc.DeepEquals, []byte("creds"))
 }
 
+func (s *serviceSuite) TestSetStorageDefaults(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "SetStorageDefaults")
+		args, ok := a.(params.ServicesStorageDefaults)
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(args.Defaults, jc.DeepEquals, []params.ServiceStorageDefaults{{
+			ServiceName:    "serviceA",
+			BlockPool:      "ebs-ssd",
+			FilesystemPool: "rootfs",
+		}})
+
+		result := response.(*params.ErrorResults)
+		result.Results = make([]params.ErrorResult, 1)
+		return nil
+	})
+	err := s.client.SetStorageDefaults("serviceA", "ebs-ssd", "rootfs")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
 func (s *serviceSuite) TestSetServiceDeploy(c *gc.C) {
 	var called bool
 	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
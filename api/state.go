@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/api/networker"
 	"github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/api/reboot"
+	"github.com/juju/juju/api/remoterelations"
 	"github.com/juju/juju/api/rsyslog"
 	"github.com/juju/juju/api/storageprovisioner"
 	"github.com/juju/juju/api/uniter"
@@ -331,6 +332,11 @@ func (st *State) CharmRevisionUpdater() *charmrevisionupdater.State {
 	return charmrevisionupdater.NewState(st)
 }
 
+// RemoteRelations returns access to the RemoteRelations API
+func (st *State) RemoteRelations() *remoterelations.State {
+	return remoterelations.NewState(st)
+}
+
 // Rsyslog returns access to the Rsyslog API
 func (st *State) Rsyslog() *rsyslog.State {
 	return rsyslog.NewState(st)
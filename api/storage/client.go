@@ -87,6 +87,77 @@ func (c *Client) CreatePool(pname, provider string, attrs map[string]interface{}
 	return c.facade.FacadeCall("CreatePool", args, nil)
 }
 
+// Resize requests that the storage instance with the specified ID be
+// grown to the specified size, in MiB.
+func (c *Client) Resize(storageId string, size uint64) error {
+	args := params.StorageResizeArgs{
+		Storage: []params.StorageResize{{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			Size:       size,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("Resize", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// CreateSnapshot requests a snapshot of the volume backing the storage
+// instance with the specified ID, returning the ID of the new snapshot.
+func (c *Client) CreateSnapshot(storageId string) (string, error) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewStorageTag(storageId).String()}},
+	}
+	var results params.StringResults
+	if err := c.facade.FacadeCall("CreateSnapshots", args, &results); err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return "", err
+	}
+	return results.Results[0].Result, nil
+}
+
+// ListSnapshots lists all volume snapshots in the environment.
+func (c *Client) ListSnapshots() ([]params.SnapshotDetails, error) {
+	var results params.SnapshotDetailsResults
+	if err := c.facade.FacadeCall("ListSnapshots", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// DestroySnapshots destroys the volume snapshots with the specified IDs.
+func (c *Client) DestroySnapshots(ids []string) ([]params.ErrorResult, error) {
+	args := params.SnapshotIds{Ids: ids}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("DestroySnapshots", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// Detach detaches the storage instances with the specified IDs from
+// the units that own them, preserving the storage so that it may be
+// attached to new units.
+func (c *Client) Detach(storageIds []string) ([]params.ErrorResult, error) {
+	args := params.Entities{
+		Entities: make([]params.Entity, len(storageIds)),
+	}
+	for i, id := range storageIds {
+		args.Entities[i].Tag = names.NewStorageTag(id).String()
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("Detach", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
 // ListVolumes lists volumes for desired machines.
 // If no machines provided, a list of all volumes is returned.
 func (c *Client) ListVolumes(machines []string) ([]params.VolumeItem, error) {
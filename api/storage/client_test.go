@@ -295,6 +295,123 @@ func (s *storageMockSuite) TestCreatePoolFacadeCallError(c *gc.C) {
 	c.Assert(errors.Cause(err), gc.ErrorMatches, msg)
 }
 
+func (s *storageMockSuite) TestResize(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Resize")
+
+			c.Assert(a, jc.DeepEquals, params.StorageResizeArgs{
+				Storage: []params.StorageResize{{StorageTag: "storage-data-0", Size: 2048}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: &params.Error{Message: "not big enough"}}},
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	err := storageClient.Resize("data/0", 2048)
+	c.Assert(err, gc.ErrorMatches, "not big enough")
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *storageMockSuite) TestCreateSnapshot(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "CreateSnapshots")
+
+			c.Assert(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "storage-data-0"}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.StringResults{})
+			*(result.(*params.StringResults)) = params.StringResults{
+				Results: []params.StringResult{{Result: "0/3"}},
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	snapshotId, err := storageClient.CreateSnapshot("data/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotId, gc.Equals, "0/3")
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *storageMockSuite) TestDestroySnapshots(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "DestroySnapshots")
+
+			c.Assert(a, jc.DeepEquals, params.SnapshotIds{Ids: []string{"3", "4"}})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}, {Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	results, err := storageClient.DestroySnapshots([]string{"3", "4"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.ErrorMatches, "boom")
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *storageMockSuite) TestDetach(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Storage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Detach")
+
+			c.Assert(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{"storage-data-0"}, {"storage-data-1"}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}, {Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		})
+	storageClient := storage.NewClient(apiCaller)
+	results, err := storageClient.Detach([]string{"data/0", "data/1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.ErrorMatches, "boom")
+	c.Assert(called, jc.IsTrue)
+}
+
 func (s *storageMockSuite) TestListVolumes(c *gc.C) {
 	var called bool
 	machines := []string{"one", "two"}
@@ -72,6 +72,18 @@ func (st *State) WatchFilesystems() (watcher.StringsWatcher, error) {
 	return st.watchStorageEntities("WatchFilesystems")
 }
 
+// WatchVolumeResizes watches for requests to resize volumes scoped
+// to the entity with the tag passed to NewState.
+func (st *State) WatchVolumeResizes() (watcher.StringsWatcher, error) {
+	return st.watchStorageEntities("WatchVolumeResizes")
+}
+
+// WatchSnapshots watches for changes to snapshots of volumes scoped
+// to the entity with the tag passed to NewState.
+func (st *State) WatchSnapshots() (watcher.StringsWatcher, error) {
+	return st.watchStorageEntities("WatchSnapshots")
+}
+
 func (st *State) watchStorageEntities(method string) (watcher.StringsWatcher, error) {
 	var results params.StringsWatchResults
 	args := params.Entities{
@@ -194,6 +206,26 @@ func (st *State) VolumeBlockDevices(ids []params.MachineStorageId) ([]params.Blo
 	return results.Results, nil
 }
 
+// MachineBlockDevices returns details of all block devices on the
+// specified machines.
+func (st *State) MachineBlockDevices(tags []names.MachineTag) ([]params.BlockDevicesResult, error) {
+	args := params.Entities{
+		Entities: make([]params.Entity, len(tags)),
+	}
+	for i, tag := range tags {
+		args.Entities[i].Tag = tag.String()
+	}
+	var results params.BlockDevicesResults
+	err := st.facade.FacadeCall("MachineBlockDevices", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(tags) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(tags), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
 // FilesystemAttachments returns details of filesystem attachments with the specified IDs.
 func (st *State) FilesystemAttachments(ids []params.MachineStorageId) ([]params.FilesystemAttachmentResult, error) {
 	args := params.MachineStorageIds{ids}
@@ -228,6 +260,56 @@ func (st *State) VolumeParams(tags []names.VolumeTag) ([]params.VolumeParamsResu
 	return results.Results, nil
 }
 
+// VolumeResizeParams returns the parameters for resizing the volumes
+// with the specified tags.
+func (st *State) VolumeResizeParams(tags []names.VolumeTag) ([]params.VolumeResizeParamsResult, error) {
+	args := params.Entities{
+		Entities: make([]params.Entity, len(tags)),
+	}
+	for i, tag := range tags {
+		args.Entities[i].Tag = tag.String()
+	}
+	var results params.VolumeResizeParamsResults
+	err := st.facade.FacadeCall("VolumeResizeParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(tags) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(tags), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// SnapshotParams returns the parameters for taking, or destroying,
+// the snapshots with the specified IDs.
+func (st *State) SnapshotParams(ids []string) ([]params.SnapshotParamsResult, error) {
+	args := params.SnapshotIds{Ids: ids}
+	var results params.SnapshotParamsResults
+	err := st.facade.FacadeCall("SnapshotParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// FailVolumeResizes clears the pending requests to resize volumes,
+// recording the reasons that they could not be carried out.
+func (st *State) FailVolumeResizes(failures []params.VolumeResizeFailure) ([]params.ErrorResult, error) {
+	args := params.VolumeResizeFailures{Failures: failures}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("FailVolumeResizes", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(failures) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(failures), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
 // FilesystemParams returns the parameters for creating the filesystems
 // with the specified tags.
 func (st *State) FilesystemParams(tags []names.FilesystemTag) ([]params.FilesystemParamsResult, error) {
@@ -292,6 +374,50 @@ func (st *State) SetVolumeInfo(volumes []params.Volume) ([]params.ErrorResult, e
 	return results.Results, nil
 }
 
+// SetSnapshotInfo records the details of newly taken snapshots.
+func (st *State) SetSnapshotInfo(snapshots []params.Snapshot) ([]params.ErrorResult, error) {
+	args := params.Snapshots{Snapshots: snapshots}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetSnapshotInfo", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(snapshots) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(snapshots), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// SetSnapshotFailures records the reasons that snapshots could not be
+// taken or destroyed.
+func (st *State) SetSnapshotFailures(failures []params.SnapshotFailure) ([]params.ErrorResult, error) {
+	args := params.SnapshotFailures{Failures: failures}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetSnapshotFailures", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(failures) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(failures), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
+// RemoveSnapshots removes the snapshots with the specified IDs from
+// state, once they have been destroyed.
+func (st *State) RemoveSnapshots(ids []string) ([]params.ErrorResult, error) {
+	args := params.SnapshotIds{Ids: ids}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("RemoveSnapshots", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		panic(errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results)))
+	}
+	return results.Results, nil
+}
+
 // SetFilesystemInfo records the details of newly provisioned filesystems.
 func (st *State) SetFilesystemInfo(filesystems []params.Filesystem) ([]params.ErrorResult, error) {
 	args := params.Filesystems{Filesystems: filesystems}
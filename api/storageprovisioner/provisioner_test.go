@@ -290,6 +290,36 @@ func (s *provisionerSuite) TestVolumeBlockDevices(c *gc.C) {
 	c.Assert(volumes, jc.DeepEquals, blockDeviceResults)
 }
 
+func (s *provisionerSuite) TestMachineBlockDevices(c *gc.C) {
+	blockDevicesResults := []params.BlockDevicesResult{{
+		Result: []storage.BlockDevice{{
+			DeviceName:  "sdb",
+			Size:        1024,
+			Partitioned: true,
+		}},
+	}}
+
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "MachineBlockDevices")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-100"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.BlockDevicesResults{})
+		*(result.(*params.BlockDevicesResults)) = params.BlockDevicesResults{
+			Results: blockDevicesResults,
+		}
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	results, err := st.MachineBlockDevices([]names.MachineTag{names.NewMachineTag("100")})
+	c.Check(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, blockDevicesResults)
+}
+
 func (s *provisionerSuite) TestFilesystemAttachments(c *gc.C) {
 	filesystemAttachmentResults := []params.FilesystemAttachmentResult{{
 		Result: params.FilesystemAttachment{
@@ -360,6 +390,160 @@ func (s *provisionerSuite) TestVolumeParams(c *gc.C) {
 	}})
 }
 
+func (s *provisionerSuite) TestVolumeResizeParams(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "VolumeResizeParams")
+		c.Check(arg, gc.DeepEquals, params.Entities{Entities: []params.Entity{{"volume-100"}}})
+		c.Assert(result, gc.FitsTypeOf, &params.VolumeResizeParamsResults{})
+		*(result.(*params.VolumeResizeParamsResults)) = params.VolumeResizeParamsResults{
+			Results: []params.VolumeResizeParamsResult{{
+				Result: params.VolumeResizeParams{
+					VolumeTag: "volume-100",
+					VolumeId:  "vol-100",
+					Size:      2048,
+					Provider:  "loop",
+				},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	resizeParams, err := st.VolumeResizeParams([]names.VolumeTag{names.NewVolumeTag("100")})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(resizeParams, jc.DeepEquals, []params.VolumeResizeParamsResult{{
+		Result: params.VolumeResizeParams{
+			VolumeTag: "volume-100", VolumeId: "vol-100", Size: 2048, Provider: "loop",
+		},
+	}})
+}
+
+func (s *provisionerSuite) TestFailVolumeResizes(c *gc.C) {
+	failures := []params.VolumeResizeFailure{{
+		VolumeTag: "volume-100", Reason: "no can do",
+	}}
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "FailVolumeResizes")
+		c.Check(arg, gc.DeepEquals, params.VolumeResizeFailures{Failures: failures})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "FAIL"}}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	errorResults, err := st.FailVolumeResizes(failures)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, jc.DeepEquals, []params.ErrorResult{
+		{Error: &params.Error{Message: "FAIL"}},
+	})
+}
+
+func (s *provisionerSuite) TestSnapshotParams(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SnapshotParams")
+		c.Check(arg, gc.DeepEquals, params.SnapshotIds{Ids: []string{"123/0"}})
+		c.Assert(result, gc.FitsTypeOf, &params.SnapshotParamsResults{})
+		*(result.(*params.SnapshotParamsResults)) = params.SnapshotParamsResults{
+			Results: []params.SnapshotParamsResult{{
+				Result: params.SnapshotParams{
+					Id:        "123/0",
+					Life:      params.Alive,
+					VolumeTag: "volume-123-100",
+					VolumeId:  "vol-100",
+					Size:      1024,
+					Provider:  "loop",
+				},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	snapshotParams, err := st.SnapshotParams([]string{"123/0"})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(snapshotParams, jc.DeepEquals, []params.SnapshotParamsResult{{
+		Result: params.SnapshotParams{
+			Id: "123/0", Life: params.Alive, VolumeTag: "volume-123-100",
+			VolumeId: "vol-100", Size: 1024, Provider: "loop",
+		},
+	}})
+}
+
+func (s *provisionerSuite) TestSetSnapshotInfo(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetSnapshotInfo")
+		c.Check(arg, gc.DeepEquals, params.Snapshots{
+			Snapshots: []params.Snapshot{{
+				Id: "123/0", VolumeTag: "volume-123-100", SnapshotId: "snap-0",
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: nil}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	errorResults, err := st.SetSnapshotInfo([]params.Snapshot{{
+		Id: "123/0", VolumeTag: "volume-123-100", SnapshotId: "snap-0",
+	}})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 1)
+	c.Assert(errorResults[0].Error, gc.IsNil)
+}
+
+func (s *provisionerSuite) TestSetSnapshotFailures(c *gc.C) {
+	failures := []params.SnapshotFailure{{Id: "123/0", Reason: "no can do"}}
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetSnapshotFailures")
+		c.Check(arg, gc.DeepEquals, params.SnapshotFailures{Failures: failures})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: nil}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	errorResults, err := st.SetSnapshotFailures(failures)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 1)
+	c.Assert(errorResults[0].Error, gc.IsNil)
+}
+
 func (s *provisionerSuite) TestFilesystemParams(c *gc.C) {
 	var callCount int
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
@@ -70,6 +70,25 @@ func (u *Unit) SetUnitStatus(status params.Status, info string, data map[string]
 	return result.OneError()
 }
 
+// SetServiceStatus sets the status of the unit's service. Only the
+// service's leader unit may set its status.
+func (u *Unit) SetServiceStatus(status params.Status, info string, data map[string]interface{}) error {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("SetServiceStatus")
+	}
+	var result params.ErrorResults
+	args := params.SetStatus{
+		Entities: []params.EntityStatus{
+			{Tag: u.tag.String(), Status: status, Info: info, Data: data},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetServiceStatus", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
 // UnitStatus gets the status details of the unit.
 func (u *Unit) UnitStatus() (params.StatusResult, error) {
 	var results params.StatusResults
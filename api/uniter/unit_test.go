@@ -129,6 +129,36 @@ func (s *unitSuite) TestSetUnitStatusOldServer(c *gc.C) {
 	c.Assert(err.Error(), gc.Equals, "SetUnitStatus not implemented")
 }
 
+func (s *unitSuite) TestSetServiceStatus(c *gc.C) {
+	var called bool
+	uniter.PatchUnitFacadeCall(s, s.apiUnit, func(request string, args, response interface{}) error {
+		called = true
+		c.Check(request, gc.Equals, "SetServiceStatus")
+		c.Check(args, gc.DeepEquals, params.SetStatus{
+			Entities: []params.EntityStatus{{
+				Tag:    "unit-wordpress-0",
+				Status: params.StatusBlocked,
+				Info:   "need db",
+			}},
+		})
+		*(response.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		return nil
+	})
+	err := s.apiUnit.SetServiceStatus(params.StatusBlocked, "need db", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *unitSuite) TestSetServiceStatusOldServer(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+
+	err := s.apiUnit.SetServiceStatus(params.StatusActive, "blah", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	c.Assert(err.Error(), gc.Equals, "SetServiceStatus not implemented")
+}
+
 func (s *unitSuite) TestSetAgentStatusOldServer(c *gc.C) {
 	s.patchNewState(c, uniter.NewStateV1)
 
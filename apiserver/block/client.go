@@ -5,6 +5,7 @@ package block
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -13,6 +14,8 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Block", 1, NewAPI)
+	// Version 2 adds service and machine blocks, and block expiry.
+	common.RegisterStandardFacade("Block", 2, NewAPI)
 }
 
 // Block defines the methods on the block API end point.
@@ -21,11 +24,11 @@ type Block interface {
 	List() (params.BlockResults, error)
 
 	// SwitchBlockOn switches desired block type on for this
-	// environment.
+	// environment, or for a service or machine in it.
 	SwitchBlockOn(params.BlockSwitchParams) params.ErrorResult
 
 	// SwitchBlockOff switches desired block type off for this
-	// environment.
+	// environment, or for a service or machine in it.
 	SwitchBlockOff(params.BlockSwitchParams) params.ErrorResult
 }
 
@@ -77,22 +80,57 @@ func convertBlock(b state.Block) params.BlockResult {
 		result.Error = common.ServerError(err)
 	}
 	result.Result = params.Block{
-		Id:      b.Id(),
-		Tag:     tag.String(),
-		Type:    b.Type().String(),
-		Message: b.Message(),
+		Id:        b.Id(),
+		Tag:       tag.String(),
+		Type:      b.Type().String(),
+		Message:   b.Message(),
+		CreatedBy: b.CreatedBy(),
+	}
+	if expiry := b.Expiry(); !expiry.IsZero() {
+		result.Result.Expiry = &expiry
 	}
 	return result
 }
 
 // SwitchBlockOn implements Block.SwitchBlockOn().
 func (a *API) SwitchBlockOn(args params.BlockSwitchParams) params.ErrorResult {
-	err := a.access.SwitchBlockOn(state.ParseBlockType(args.Type), args.Message)
+	blockArgs := state.BlockArgs{Message: args.Message}
+	if args.Expiry != nil {
+		blockArgs.Expiry = *args.Expiry
+	}
+	if user, ok := a.authorizer.GetAuthTag().(names.UserTag); ok {
+		blockArgs.CreatedBy = user.Username()
+	}
+	var err error
+	blockArgs.Entity, err = blockEntity(args)
+	if err == nil {
+		err = a.access.SwitchBlockOnWithArgs(state.ParseBlockType(args.Type), blockArgs)
+	}
 	return params.ErrorResult{Error: common.ServerError(err)}
 }
 
 // SwitchBlockOff implements Block.SwitchBlockOff().
 func (a *API) SwitchBlockOff(args params.BlockSwitchParams) params.ErrorResult {
-	err := a.access.SwitchBlockOff(state.ParseBlockType(args.Type))
+	entity, err := blockEntity(args)
+	switch {
+	case err != nil:
+	case entity == nil:
+		err = a.access.SwitchBlockOff(state.ParseBlockType(args.Type))
+	default:
+		err = a.access.SwitchBlockOffForEntity(state.ParseBlockType(args.Type), entity)
+	}
 	return params.ErrorResult{Error: common.ServerError(err)}
 }
+
+// blockEntity returns the tag of the entity the block switch applies
+// to, or nil if it applies to the whole environment.
+func blockEntity(args params.BlockSwitchParams) (names.Tag, error) {
+	if args.Entity == "" {
+		return nil, nil
+	}
+	tag, err := names.ParseTag(args.Entity)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return tag, nil
+}
@@ -4,6 +4,8 @@
 package block_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -79,3 +81,45 @@ func (s *blockSuite) TestSwitchBlockOff(c *gc.C) {
 	c.Assert(err.Error, gc.IsNil)
 	s.assertBlockList(c, 0)
 }
+
+func (s *blockSuite) TestSwitchEntityBlock(c *gc.C) {
+	expiry := time.Now().Add(time.Hour).Round(time.Second).UTC()
+	on := params.BlockSwitchParams{
+		Type:    state.RemoveBlock.String(),
+		Message: "for TestSwitchEntityBlock",
+		Entity:  "service-postgresql",
+		Expiry:  &expiry,
+	}
+	err := s.api.SwitchBlockOn(on)
+	c.Assert(err.Error, gc.IsNil)
+
+	all, listErr := s.api.List()
+	c.Assert(listErr, jc.ErrorIsNil)
+	c.Assert(all.Results, gc.HasLen, 1)
+	result := all.Results[0]
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Result.Tag, gc.Equals, "service-postgresql")
+	c.Assert(result.Result.Type, gc.Equals, state.RemoveBlock.String())
+	c.Assert(result.Result.CreatedBy, gc.Equals, s.AdminUserTag(c).Username())
+	c.Assert(result.Result.Expiry, gc.NotNil)
+	c.Assert(result.Result.Expiry.Equal(expiry), jc.IsTrue)
+
+	// Switching off the environment block leaves the service blocked.
+	off := params.BlockSwitchParams{Type: state.RemoveBlock.String()}
+	err = s.api.SwitchBlockOff(off)
+	c.Assert(err.Error, gc.ErrorMatches, "block BlockRemove is already OFF")
+	off.Entity = "service-postgresql"
+	err = s.api.SwitchBlockOff(off)
+	c.Assert(err.Error, gc.IsNil)
+	s.assertBlockList(c, 0)
+}
+
+func (s *blockSuite) TestSwitchEntityBlockInvalidEntity(c *gc.C) {
+	on := params.BlockSwitchParams{
+		Type:   state.ChangeBlock.String(),
+		Entity: "postgresql",
+	}
+	err := s.api.SwitchBlockOn(on)
+	c.Assert(err.Error, gc.ErrorMatches, `"postgresql" is not a valid tag`)
+	s.assertBlockList(c, 0)
+}
@@ -3,12 +3,17 @@
 
 package block
 
-import "github.com/juju/juju/state"
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+)
 
 type blockAccess interface {
 	AllBlocks() ([]state.Block, error)
-	SwitchBlockOn(t state.BlockType, msg string) error
+	SwitchBlockOnWithArgs(t state.BlockType, args state.BlockArgs) error
 	SwitchBlockOff(t state.BlockType) error
+	SwitchBlockOffForEntity(t state.BlockType, entity names.Tag) error
 }
 
 type stateShim struct {
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// SetAutoscalePolicy sets the policy by which the number of units of
+// a service is adjusted according to the metrics reported by its units.
+func (c *Client) SetAutoscalePolicy(args params.SetAutoscalePolicy) error {
+	if err := c.check.ChangeAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
+		return errors.Trace(err)
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return service.SetAutoscalePolicy(state.AutoscalePolicy{
+		Metric:    args.Policy.Metric,
+		Threshold: args.Policy.Threshold,
+		Window:    args.Policy.Window,
+		MinUnits:  args.Policy.MinUnits,
+		MaxUnits:  args.Policy.MaxUnits,
+		Cooldown:  args.Policy.Cooldown,
+	})
+}
+
+// ClearAutoscalePolicy removes the autoscale policy of a service.
+func (c *Client) ClearAutoscalePolicy(args params.ClearAutoscalePolicy) error {
+	if err := c.check.ChangeAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
+		return errors.Trace(err)
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return service.ClearAutoscalePolicy()
+}
+
+// Autoscale returns the autoscale policy of a service, if it has one,
+// and its most recent scaling decisions, newest first.
+func (c *Client) Autoscale(args params.AutoscaleArgs) (params.AutoscaleResult, error) {
+	if args.HistorySize <= 0 {
+		return params.AutoscaleResult{}, errors.Errorf("invalid history size: %d", args.HistorySize)
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return params.AutoscaleResult{}, errors.Trace(err)
+	}
+	var result params.AutoscaleResult
+	policy, err := service.AutoscalePolicy()
+	if err == nil {
+		result.Policy = &params.AutoscalePolicy{
+			Metric:    policy.Metric,
+			Threshold: policy.Threshold,
+			Window:    policy.Window,
+			MinUnits:  policy.MinUnits,
+			MaxUnits:  policy.MaxUnits,
+			Cooldown:  policy.Cooldown,
+		}
+	} else if !errors.IsNotFound(err) {
+		return params.AutoscaleResult{}, errors.Trace(err)
+	}
+	history, err := service.AutoscaleHistory(args.HistorySize)
+	if err != nil {
+		return params.AutoscaleResult{}, errors.Trace(err)
+	}
+	result.History = make([]params.AutoscaleDecision, len(history))
+	for i, d := range history {
+		result.History[i] = params.AutoscaleDecision{
+			Time:      d.Time,
+			Metric:    d.Metric,
+			Value:     d.Value,
+			FromUnits: d.FromUnits,
+			ToUnits:   d.ToUnits,
+			Reason:    d.Reason,
+		}
+	}
+	return result, nil
+}
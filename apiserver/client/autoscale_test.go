@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type autoscaleSuite struct {
+	baseSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&autoscaleSuite{})
+
+func (s *autoscaleSuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "metered", s.AddTestingCharm(c, "metered"))
+}
+
+var testAutoscalePolicy = params.AutoscalePolicy{
+	Metric:    "pings",
+	Threshold: 100,
+	Window:    5 * time.Minute,
+	MinUnits:  1,
+	MaxUnits:  5,
+	Cooldown:  10 * time.Minute,
+}
+
+func (s *autoscaleSuite) TestSetAutoscalePolicy(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.SetAutoscalePolicy("metered", testAutoscalePolicy)
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err := s.service.AutoscalePolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy.Metric, gc.Equals, "pings")
+	c.Assert(policy.Window, gc.Equals, 5*time.Minute)
+
+	decision := state.AutoscaleDecision{
+		Time:      time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC),
+		Metric:    "pings",
+		Value:     150,
+		FromUnits: 1,
+		ToUnits:   2,
+		Reason:    "average pings 150 above threshold 100",
+	}
+	err = s.service.RecordAutoscaleDecision(decision)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := client.Autoscale("metered", 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Policy, jc.DeepEquals, &testAutoscalePolicy)
+	c.Assert(result.History, jc.DeepEquals, []params.AutoscaleDecision{{
+		Time:      decision.Time,
+		Metric:    "pings",
+		Value:     150,
+		FromUnits: 1,
+		ToUnits:   2,
+		Reason:    "average pings 150 above threshold 100",
+	}})
+
+	err = client.ClearAutoscalePolicy("metered")
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = client.Autoscale("metered", 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Policy, gc.IsNil)
+	c.Assert(result.History, gc.HasLen, 1)
+}
+
+func (s *autoscaleSuite) TestSetAutoscalePolicyInvalid(c *gc.C) {
+	policy := testAutoscalePolicy
+	policy.Metric = "foo"
+	err := s.APIState.Client().SetAutoscalePolicy("metered", policy)
+	c.Assert(err, gc.ErrorMatches, `cannot set autoscale policy for service "metered": charm "local:quantal/metered-1" does not declare metric "foo"`)
+
+	err = s.APIState.Client().SetAutoscalePolicy("unknown", testAutoscalePolicy)
+	c.Assert(err, gc.ErrorMatches, `service "unknown" not found`)
+}
+
+func (s *autoscaleSuite) TestAutoscaleInvalidSize(c *gc.C) {
+	_, err := s.APIState.Client().Autoscale("metered", 0)
+	c.Assert(err, gc.ErrorMatches, "invalid history size: 0")
+}
+
+func (s *autoscaleSuite) TestBlockSetAutoscalePolicy(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockSetAutoscalePolicy")
+	err := s.APIState.Client().SetAutoscalePolicy("metered", testAutoscalePolicy)
+	s.AssertBlocked(c, err, "TestBlockSetAutoscalePolicy")
+}
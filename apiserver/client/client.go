@@ -28,6 +28,8 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Client", 0, NewClient)
+	// Version 1 adds source CIDRs to ServiceExpose.
+	common.RegisterStandardFacade("Client", 1, NewClient)
 }
 
 var logger = loggo.GetLogger("juju.apiserver.client")
@@ -82,7 +84,7 @@ func (c *Client) WatchAll() (params.AllWatcherId, error) {
 // (Deprecated) Use NewServiceSetForClientAPI instead, to preserve values set to
 // an empty string, and use ServiceUnset to unset values.
 func (c *Client) ServiceSet(p params.ServiceSet) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(names.NewServiceTag(p.ServiceName)); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(p.ServiceName)
@@ -111,7 +113,7 @@ func (c *Client) NewServiceSetForClientAPI(p params.ServiceSet) error {
 // ServiceUnset implements the server side of Client.ServiceUnset.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceUnset(p params.ServiceUnset) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(names.NewServiceTag(p.ServiceName)); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(p.ServiceName)
@@ -128,7 +130,7 @@ func (c *Client) ServiceUnset(p params.ServiceUnset) error {
 // ServiceSetYAML implements the server side of Client.ServerSetYAML.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceSetYAML(p params.ServiceSetYAML) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(names.NewServiceTag(p.ServiceName)); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(p.ServiceName)
@@ -159,7 +161,7 @@ func (c *Client) ServiceCharmRelations(p params.ServiceCharmRelations) (params.S
 
 // Resolved implements the server side of Client.Resolved.
 func (c *Client) Resolved(p params.Resolved) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(unitTags(p.UnitName)...); err != nil {
 		return errors.Trace(err)
 	}
 	unit, err := c.api.state.Unit(p.UnitName)
@@ -226,24 +228,25 @@ func (c *Client) PrivateAddress(p params.PrivateAddress) (results params.Private
 }
 
 // ServiceExpose changes the juju-managed firewall to expose any ports that
-// were also explicitly marked by units as open.
+// were also explicitly marked by units as open. If source CIDRs are
+// specified, the ports are only exposed to addresses within them.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceExpose(args params.ServiceExpose) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(args.ServiceName)
 	if err != nil {
 		return err
 	}
-	return svc.SetExposed()
+	return svc.SetExposedTo(args.SourceCIDRs)
 }
 
 // ServiceUnexpose changes the juju-managed firewall to unexpose any ports that
 // were also explicitly marked by units as open.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceUnexpose(args params.ServiceUnexpose) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(args.ServiceName)
@@ -253,12 +256,81 @@ func (c *Client) ServiceUnexpose(args params.ServiceUnexpose) error {
 	return svc.ClearExposed()
 }
 
+// ServiceOffer offers the endpoints of a service for relating to services
+// in other environments hosted by the same state server.
+func (c *Client) ServiceOffer(args params.ServiceOffer) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := c.api.state.AddServiceOffer(state.ServiceOfferParams{
+		OfferName:   args.OfferName,
+		ServiceName: args.ServiceName,
+		Endpoints:   args.Endpoints,
+	})
+	return errors.Trace(err)
+}
+
+// ServiceConsume adds a remote service to the environment, consuming a
+// service offered by another environment to which the user has access.
+// Local services may then be related to the remote service.
+func (c *Client) ServiceConsume(args params.ServiceConsume) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	user, ok := c.api.auth.GetAuthTag().(names.UserTag)
+	if !ok {
+		return common.ErrPerm
+	}
+	envs, err := c.api.state.EnvironmentsForUser(user)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var source *state.Environment
+	for _, env := range envs {
+		if env.UUID() == args.SourceEnvironment || env.Name() == args.SourceEnvironment {
+			source = env
+			break
+		}
+	}
+	if source == nil {
+		return errors.NotFoundf("environment %q", args.SourceEnvironment)
+	}
+	st, err := c.api.state.ForEnviron(source.EnvironTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Close()
+	offer, err := st.ServiceOffer(args.OfferName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	eps, err := offer.Endpoints()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	relations := make([]charm.Relation, len(eps))
+	for i, ep := range eps {
+		relations[i] = ep.Relation
+	}
+	serviceName := args.ServiceName
+	if serviceName == "" {
+		serviceName = args.OfferName
+	}
+	_, err = c.api.state.AddRemoteService(state.AddRemoteServiceParams{
+		Name:          serviceName,
+		SourceEnvUUID: source.UUID(),
+		OfferName:     args.OfferName,
+		Endpoints:     relations,
+	})
+	return errors.Trace(err)
+}
+
 // ServiceDeploy fetches the charm from the charm store and deploys it.
 // AddCharm or AddLocalCharm should be called to add the charm
 // before calling ServiceDeploy, although for backward compatibility
 // this is not necessary until 1.16 support is removed.
 func (c *Client) ServiceDeploy(args params.ServiceDeploy) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(placementMachineTags(args.ToMachineSpec)...); err != nil {
 		return errors.Trace(err)
 	}
 	return service.DeployService(c.api.state, c.api.auth.GetAuthTag().String(), args)
@@ -277,7 +349,7 @@ func (c *Client) ServiceDeployWithNetworks(args params.ServiceDeploy) error {
 // All parameters in params.ServiceUpdate except the service name are optional.
 func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 	if !args.ForceCharmUrl {
-		if err := c.check.ChangeAllowed(); err != nil {
+		if err := c.check.ChangeAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
 			return errors.Trace(err)
 		}
 	}
@@ -394,7 +466,7 @@ func newServiceSetSettingsStringsForClientAPI(service *state.Service, settings m
 func (c *Client) ServiceSetCharm(args params.ServiceSetCharm) error {
 	// when forced, don't block
 	if !args.Force {
-		if err := c.check.ChangeAllowed(); err != nil {
+		if err := c.check.ChangeAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
 			return errors.Trace(err)
 		}
 	}
@@ -414,10 +486,31 @@ func addServiceUnits(state *state.State, args params.AddServiceUnits) ([]*state.
 	if args.NumUnits < 1 {
 		return nil, fmt.Errorf("must add at least one unit")
 	}
-	if args.NumUnits > 1 && args.ToMachineSpec != "" {
+	if args.NumUnits > 1 && args.ToMachineSpec != "" && !instance.IsZonePlacements(args.ToMachineSpec) {
 		return nil, fmt.Errorf("cannot use NumUnits with ToMachineSpec")
 	}
 
+	if len(args.AttachStorage) > 0 {
+		if args.NumUnits != 1 {
+			return nil, fmt.Errorf("cannot attach existing storage to more than one unit")
+		}
+		if args.ToMachineSpec != "" {
+			return nil, fmt.Errorf("cannot use AttachStorage with ToMachineSpec")
+		}
+		storageTags := make([]names.StorageTag, len(args.AttachStorage))
+		for i, tag := range args.AttachStorage {
+			storageTags[i], err = names.ParseStorageTag(tag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		unit, err := jjj.AddUnitWithStorage(state, service, storageTags)
+		if err != nil {
+			return nil, err
+		}
+		return []*state.Unit{unit}, nil
+	}
+
 	if args.ToMachineSpec != "" && names.IsValidMachine(args.ToMachineSpec) {
 		_, err = state.Machine(args.ToMachineSpec)
 		if err != nil {
@@ -429,7 +522,8 @@ func addServiceUnits(state *state.State, args params.AddServiceUnits) ([]*state.
 
 // AddServiceUnits adds a given number of units to a service.
 func (c *Client) AddServiceUnits(args params.AddServiceUnits) (params.AddServiceUnitsResults, error) {
-	if err := c.check.ChangeAllowed(); err != nil {
+	tags := append([]names.Tag{names.NewServiceTag(args.ServiceName)}, placementMachineTags(args.ToMachineSpec)...)
+	if err := c.check.ChangeAllowedFor(tags...); err != nil {
 		return params.AddServiceUnitsResults{}, errors.Trace(err)
 	}
 	units, err := addServiceUnits(c.api.state, args)
@@ -445,7 +539,7 @@ func (c *Client) AddServiceUnits(args params.AddServiceUnits) (params.AddService
 
 // DestroyServiceUnits removes a given set of service units.
 func (c *Client) DestroyServiceUnits(args params.DestroyServiceUnits) error {
-	if err := c.check.RemoveAllowed(); err != nil {
+	if err := c.check.RemoveAllowedFor(unitTags(args.UnitNames...)...); err != nil {
 		return errors.Trace(err)
 	}
 	var errs []string
@@ -472,7 +566,7 @@ func (c *Client) DestroyServiceUnits(args params.DestroyServiceUnits) error {
 // ServiceDestroy destroys a given service.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceDestroy(args params.ServiceDestroy) error {
-	if err := c.check.RemoveAllowed(); err != nil {
+	if err := c.check.RemoveAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(args.ServiceName)
@@ -505,7 +599,7 @@ func (c *Client) GetEnvironmentConstraints() (params.GetConstraintsResults, erro
 // SetServiceConstraints sets the constraints for a given service.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) SetServiceConstraints(args params.SetConstraints) error {
-	if err := c.check.ChangeAllowed(); err != nil {
+	if err := c.check.ChangeAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
 		return errors.Trace(err)
 	}
 	svc, err := c.api.state.Service(args.ServiceName)
@@ -532,6 +626,9 @@ func (c *Client) AddRelation(args params.AddRelation) (params.AddRelationResults
 	if err != nil {
 		return params.AddRelationResults{}, err
 	}
+	if err := c.check.ChangeAllowedFor(endpointServiceTags(inEps)...); err != nil {
+		return params.AddRelationResults{}, errors.Trace(err)
+	}
 	rel, err := c.api.state.AddRelation(inEps...)
 	if err != nil {
 		return params.AddRelationResults{}, err
@@ -556,6 +653,9 @@ func (c *Client) DestroyRelation(args params.DestroyRelation) error {
 	if err != nil {
 		return err
 	}
+	if err := c.check.RemoveAllowedFor(endpointServiceTags(eps)...); err != nil {
+		return errors.Trace(err)
+	}
 	rel, err := c.api.state.EndpointsRelation(eps...)
 	if err != nil {
 		return err
@@ -577,6 +677,11 @@ func (c *Client) AddMachinesV2(args params.AddMachines) (params.AddMachinesResul
 		return results, errors.Trace(err)
 	}
 	for i, p := range args.MachineParams {
+		// Containers may not be added to a blocked machine.
+		if err := c.check.ChangeAllowedFor(parentMachineTags(p)...); err != nil {
+			results.Machines[i].Error = common.ServerError(err)
+			continue
+		}
 		m, err := c.addOneMachine(p)
 		results.Machines[i].Error = common.ServerError(err)
 		if err == nil {
@@ -707,7 +812,7 @@ func (c *Client) DestroyMachines(args params.DestroyMachines) error {
 			continue
 		default:
 			{
-				if err := c.check.RemoveAllowed(); err != nil {
+				if err := c.check.RemoveAllowedFor(machine.Tag()); err != nil {
 					return errors.Trace(err)
 				}
 				err = machine.Destroy()
@@ -977,6 +1082,56 @@ func destroyErr(desc string, ids, errs []string) error {
 	return fmt.Errorf("%s: %s", msg, strings.Join(errs, "; "))
 }
 
+// unitTags returns the tags of the named units, for checking blocks
+// on their services. Invalid unit names are skipped, to be reported
+// when the units are looked up.
+func unitTags(unitNames ...string) []names.Tag {
+	var tags []names.Tag
+	for _, name := range unitNames {
+		if names.IsValidUnit(name) {
+			tags = append(tags, names.NewUnitTag(name))
+		}
+	}
+	return tags
+}
+
+// placementMachineTags returns the tag of the existing machine named by
+// a placement directive, such as "3" or "lxc:3", for checking blocks
+// on it.
+func placementMachineTags(spec string) []names.Tag {
+	placement, err := instance.ParsePlacement(spec)
+	if err != nil || placement == nil || !names.IsValidMachine(placement.Directive) {
+		return nil
+	}
+	if _, err := instance.ParseContainerType(placement.Scope); err != nil && placement.Scope != instance.MachineScope {
+		return nil
+	}
+	return []names.Tag{names.NewMachineTag(placement.Directive)}
+}
+
+// parentMachineTags returns the tag of the machine that will host the
+// machine described by p, if it is a container, for checking blocks
+// on it.
+func parentMachineTags(p params.AddMachineParams) []names.Tag {
+	if p.ParentId != "" && names.IsValidMachine(p.ParentId) {
+		return []names.Tag{names.NewMachineTag(p.ParentId)}
+	}
+	if p.Placement != nil {
+		return placementMachineTags(p.Placement.Scope + ":" + p.Placement.Directive)
+	}
+	return nil
+}
+
+// endpointServiceTags returns the tags of the endpoints' services, for
+// checking blocks on them.
+func endpointServiceTags(eps []state.Endpoint) []names.Tag {
+	tags := make([]names.Tag, len(eps))
+	for i, ep := range eps {
+		tags[i] = names.NewServiceTag(ep.ServiceName)
+	}
+	return tags
+}
+
 func (c *Client) AddCharm(args params.CharmURL) error {
 	return service.AddCharmWithAuthorization(c.api.state, params.AddCharmWithAuthorization{
 		URL: args.URL,
@@ -1185,6 +1185,58 @@ func (s *clientSuite) TestClientServiceExpose(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestClientServiceExposeTo(c *gc.C) {
+	charm := s.AddTestingCharm(c, "dummy")
+	s.AddTestingService(c, "dummy-service", charm)
+	err := s.APIState.Client().ServiceExpose("dummy-service", "10.0.0.0/8", "203.0.113.4/32")
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := s.State.Service("dummy-service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.IsExposed(), jc.IsTrue)
+	c.Assert(service.ExposedSourceCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8", "203.0.113.4/32"})
+
+	err = s.APIState.Client().ServiceExpose("dummy-service", "10.0.0.0")
+	c.Assert(err, gc.ErrorMatches, `cannot set exposed flag for service "dummy-service": invalid source CIDR "10.0.0.0"`)
+}
+
+func (s *clientSuite) TestClientServiceOffer(c *gc.C) {
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	err := s.APIState.Client().ServiceOffer("mysql", nil, "db")
+	c.Assert(err, jc.ErrorIsNil)
+	offer, err := s.State.ServiceOffer("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offer.ServiceName(), gc.Equals, "mysql")
+	c.Assert(offer.EndpointNames(), jc.DeepEquals, []string{"server"})
+
+	err = s.APIState.Client().ServiceOffer("mysql", nil, "db")
+	c.Assert(err, gc.ErrorMatches, `cannot add offer "db": offer "db" already exists`)
+}
+
+func (s *clientSuite) TestClientServiceConsume(c *gc.C) {
+	otherState := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "shared"})
+	defer otherState.Close()
+	otherFactory := factory.NewFactory(otherState)
+	otherFactory.MakeService(c, &factory.ServiceParams{
+		Name:  "mysql",
+		Charm: otherFactory.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	_, err := otherState.AddServiceOffer(state.ServiceOfferParams{
+		OfferName:   "db",
+		ServiceName: "mysql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.APIState.Client().ServiceConsume("shared", "db", "database")
+	c.Assert(err, jc.ErrorIsNil)
+	remote, err := s.State.RemoteService("database")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remote.SourceEnvironTag(), gc.Equals, otherState.EnvironTag())
+	c.Assert(remote.OfferName(), gc.Equals, "db")
+
+	err = s.APIState.Client().ServiceConsume("unknown", "db", "")
+	c.Assert(err, gc.ErrorMatches, `environment "unknown" not found`)
+}
+
 func (s *clientSuite) setupServiceExpose(c *gc.C) {
 	charm := s.AddTestingCharm(c, "dummy")
 	serviceNames := []string{"dummy-service", "exposed-service"}
@@ -3412,6 +3464,121 @@ func (s *serverSuite) TestBlockServiceDestroy(c *gc.C) {
 	}
 }
 
+func (s *serverSuite) TestBlockEntityServiceDestroy(c *gc.C) {
+	s.AddTestingService(c, "postgresql", s.AddTestingCharm(c, "mysql"))
+	other := s.AddTestingService(c, "dummy-service", s.AddTestingCharm(c, "dummy"))
+	err := s.State.SwitchBlockOnWithArgs(state.RemoveBlock, state.BlockArgs{
+		Message: "TestBlockEntityServiceDestroy",
+		Entity:  names.NewServiceTag("postgresql"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.APIState.Client().ServiceDestroy("postgresql")
+	s.AssertBlocked(c, err, "TestBlockEntityServiceDestroy")
+	service, err := s.State.Service("postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+	assertLife(c, service, state.Alive)
+
+	// Changes to the service and removal of other services are allowed.
+	err = s.APIState.Client().ServiceExpose("postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.APIState.Client().ServiceDestroy("dummy-service")
+	c.Assert(err, jc.ErrorIsNil)
+	assertLife(c, other, state.Dying)
+}
+
+func (s *clientSuite) TestBlockEntityDestroyMachines(c *gc.C) {
+	_, m1, m2, u := s.setupDestroyMachinesTest(c)
+	err := u.UnassignFromMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SwitchBlockOnWithArgs(state.ChangeBlock, state.BlockArgs{
+		Message: "TestBlockEntityDestroyMachines",
+		Entity:  m1.Tag(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.APIState.Client().DestroyMachines("2", "1")
+	s.AssertBlocked(c, err, "TestBlockEntityDestroyMachines")
+	assertLife(c, m1, state.Alive)
+	assertLife(c, m2, state.Dying)
+}
+
+func (s *clientSuite) blockMachine(c *gc.C, msg string) *state.Machine {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SwitchBlockOnWithArgs(state.ChangeBlock, state.BlockArgs{
+		Message: msg,
+		Entity:  m.Tag(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return m
+}
+
+func (s *clientSuite) TestBlockEntityServiceDeployToMachine(c *gc.C) {
+	m := s.blockMachine(c, "TestBlockEntityServiceDeployToMachine")
+	for _, spec := range []string{m.Id(), "lxc:" + m.Id()} {
+		err := s.APIState.Client().ServiceDeploy(
+			"cs:precise/service-name-1", "service-name", 1, "", constraints.Value{}, spec,
+		)
+		s.AssertBlocked(c, err, "TestBlockEntityServiceDeployToMachine")
+	}
+	_, err := s.State.Service("service-name")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientSuite) TestBlockEntityAddServiceUnitsToMachine(c *gc.C) {
+	s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	m := s.blockMachine(c, "TestBlockEntityAddServiceUnitsToMachine")
+	for _, spec := range []string{m.Id(), "lxc:" + m.Id()} {
+		_, err := s.APIState.Client().AddServiceUnits("dummy", 1, spec)
+		s.AssertBlocked(c, err, "TestBlockEntityAddServiceUnitsToMachine")
+	}
+
+	// Units may still be added elsewhere.
+	_, err := s.APIState.Client().AddServiceUnits("dummy", 1, "")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientSuite) TestBlockEntityAddContainerToMachine(c *gc.C) {
+	m := s.blockMachine(c, "TestBlockEntityAddContainerToMachine")
+	machines, err := s.APIState.Client().AddMachines([]params.AddMachineParams{{
+		Jobs:          []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		ContainerType: instance.LXC,
+		ParentId:      m.Id(),
+		Series:        "quantal",
+	}, {
+		Jobs:      []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		Placement: instance.MustParsePlacement("lxc:" + m.Id()),
+		Series:    "quantal",
+	}, {
+		Jobs:   []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		Series: "quantal",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 3)
+	s.AssertBlocked(c, machines[0].Error, "TestBlockEntityAddContainerToMachine")
+	s.AssertBlocked(c, machines[1].Error, "TestBlockEntityAddContainerToMachine")
+	c.Assert(machines[2].Error, gc.IsNil)
+	containers, err := m.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *clientSuite) TestBlockEntityDestroyPrincipalUnits(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	u, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SwitchBlockOnWithArgs(state.RemoveBlock, state.BlockArgs{
+		Message: "TestBlockEntityDestroyPrincipalUnits",
+		Entity:  wordpress.Tag(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.APIState.Client().DestroyServiceUnits(u.Name())
+	s.AssertBlocked(c, err, "TestBlockEntityDestroyPrincipalUnits")
+	assertLife(c, u, state.Alive)
+}
+
 func (s *clientSuite) assertDestroyMachineSuccess(c *gc.C, u *state.Unit, m0, m1, m2 *state.Machine) {
 	err := s.APIState.Client().DestroyMachines("0", "1", "2")
 	c.Assert(err, gc.ErrorMatches, `some machines were not destroyed: machine 0 is required by the environment; machine 1 has unit "wordpress/0" assigned`)
@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// SetPlacementPolicy sets the policy restricting the number of units
+// of a service and the machines they may be assigned to.
+func (c *Client) SetPlacementPolicy(args params.SetPlacementPolicy) error {
+	if err := c.check.ChangeAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
+		return errors.Trace(err)
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return service.SetPlacementPolicy(state.PlacementPolicy{
+		MaxUnits:        args.Policy.MaxUnits,
+		AntiAffinity:    state.AntiAffinity(args.Policy.AntiAffinity),
+		AffinityService: args.Policy.AffinityService,
+	})
+}
+
+// ClearPlacementPolicy removes the placement policy of a service.
+func (c *Client) ClearPlacementPolicy(args params.ClearPlacementPolicy) error {
+	if err := c.check.ChangeAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
+		return errors.Trace(err)
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return service.ClearPlacementPolicy()
+}
+
+// PlacementPolicy returns the placement policy of a service, if it
+// has one.
+func (c *Client) PlacementPolicy(args params.PlacementPolicyArgs) (params.PlacementPolicyResult, error) {
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return params.PlacementPolicyResult{}, errors.Trace(err)
+	}
+	policy, err := service.PlacementPolicy()
+	if errors.IsNotFound(err) {
+		return params.PlacementPolicyResult{}, nil
+	} else if err != nil {
+		return params.PlacementPolicyResult{}, errors.Trace(err)
+	}
+	return params.PlacementPolicyResult{
+		Policy: &params.PlacementPolicy{
+			MaxUnits:        policy.MaxUnits,
+			AntiAffinity:    string(policy.AntiAffinity),
+			AffinityService: policy.AffinityService,
+		},
+	}, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type placementPolicySuite struct {
+	baseSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&placementPolicySuite{})
+
+func (s *placementPolicySuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+var testPlacementPolicy = params.PlacementPolicy{
+	MaxUnits:        3,
+	AntiAffinity:    "machine",
+	AffinityService: "mysql",
+}
+
+func (s *placementPolicySuite) TestSetPlacementPolicy(c *gc.C) {
+	client := s.APIState.Client()
+	policy, err := client.PlacementPolicy("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.IsNil)
+
+	err = client.SetPlacementPolicy("wordpress", testPlacementPolicy)
+	c.Assert(err, jc.ErrorIsNil)
+	statePolicy, err := s.service.PlacementPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statePolicy, jc.DeepEquals, state.PlacementPolicy{
+		MaxUnits:        3,
+		AntiAffinity:    state.AntiAffinityMachine,
+		AffinityService: "mysql",
+	})
+	policy, err = client.PlacementPolicy("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, &testPlacementPolicy)
+
+	err = client.ClearPlacementPolicy("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err = client.PlacementPolicy("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.IsNil)
+}
+
+func (s *placementPolicySuite) TestSetPlacementPolicyInvalid(c *gc.C) {
+	err := s.APIState.Client().SetPlacementPolicy("wordpress", params.PlacementPolicy{AntiAffinity: "rack"})
+	c.Assert(err, gc.ErrorMatches, `cannot set placement policy for service "wordpress": anti-affinity "rack" not valid`)
+
+	err = s.APIState.Client().SetPlacementPolicy("unknown", testPlacementPolicy)
+	c.Assert(err, gc.ErrorMatches, `service "unknown" not found`)
+}
+
+func (s *placementPolicySuite) TestAddUnitsExceedingMaxUnits(c *gc.C) {
+	err := s.APIState.Client().SetPlacementPolicy("wordpress", params.PlacementPolicy{MaxUnits: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.APIState.Client().AddServiceUnits("wordpress", 3, "")
+	c.Assert(err, gc.ErrorMatches, `cannot add unit 3/3 to service "wordpress": cannot add unit to service "wordpress": placement policy allows at most 2 units`)
+}
+
+func (s *placementPolicySuite) TestBlockSetPlacementPolicy(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockSetPlacementPolicy")
+	err := s.APIState.Client().SetPlacementPolicy("wordpress", testPlacementPolicy)
+	s.AssertBlocked(c, err, "TestBlockSetPlacementPolicy")
+}
@@ -0,0 +1,250 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/service"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+)
+
+// The Plan methods below each run the same checks as the Client
+// method they are named after, including whether changes are blocked,
+// without changing the environment, and describe what the call would
+// do. A call that would fail returns the error it would fail with.
+
+// PlanServiceDeploy describes what ServiceDeploy would do, including
+// the units that would be added and the machines provisioned for them.
+// The charm must already have been added to the environment, unless
+// its metadata is included in args.
+func (c *Client) PlanServiceDeploy(args params.ServiceDeploy) (params.ChangePlan, error) {
+	if err := c.check.ChangeAllowedFor(placementMachineTags(args.ToMachineSpec)...); err != nil {
+		return params.ChangePlan{}, errors.Trace(err)
+	}
+	plan, err := service.PlanDeployService(c.api.state, c.api.auth.GetAuthTag().String(), args)
+	if err != nil {
+		return params.ChangePlan{}, err
+	}
+	result := params.ChangePlan{
+		Changes: []string{fmt.Sprintf("add service %q using charm %q", args.ServiceName, args.CharmUrl)},
+		Units:   plan.Units,
+	}
+	machines := make(map[string]params.PlannedMachine)
+	for _, m := range plan.Machines {
+		pm := params.PlannedMachine{
+			Unit:        m.Unit,
+			Series:      m.Series,
+			Constraints: m.Constraints,
+			Placement:   m.Placement,
+		}
+		machines[m.Unit] = pm
+		result.Machines = append(result.Machines, pm)
+	}
+	for _, unit := range plan.Units {
+		m, ok := machines[unit]
+		switch {
+		case !ok:
+			result.Changes = append(result.Changes, fmt.Sprintf("add unit %s to machine %s", unit, args.ToMachineSpec))
+		case m.Placement == "":
+			result.Changes = append(result.Changes, fmt.Sprintf("add unit %s to a new machine", unit))
+		default:
+			result.Changes = append(result.Changes, fmt.Sprintf("add unit %s to a new machine (%s)", unit, m.Placement))
+		}
+	}
+	if len(plan.Unsupported) > 0 {
+		result.Warnings = append(result.Warnings, unsupportedWarning(plan.Unsupported))
+	}
+	return result, nil
+}
+
+// PlanAddRelation describes what AddRelation would do. The units of
+// the related services are reported as affected, as they would join
+// the relation.
+func (c *Client) PlanAddRelation(args params.AddRelation) (params.ChangePlan, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.ChangePlan{}, errors.Trace(err)
+	}
+	st := c.api.state
+	eps, err := st.InferEndpoints(args.Endpoints...)
+	if err != nil {
+		return params.ChangePlan{}, err
+	}
+	if err := c.check.ChangeAllowedFor(endpointServiceTags(eps)...); err != nil {
+		return params.ChangePlan{}, errors.Trace(err)
+	}
+	if _, err := st.DryRun().AddRelation(eps...); err != nil {
+		return params.ChangePlan{}, err
+	}
+	epNames := make([]string, len(eps))
+	for i, ep := range eps {
+		epNames[i] = ep.String()
+	}
+	result := params.ChangePlan{
+		Changes: []string{fmt.Sprintf("add relation %s", strings.Join(epNames, " "))},
+	}
+	for _, ep := range eps {
+		svc, err := st.Service(ep.ServiceName)
+		if errors.IsNotFound(err) {
+			// Remote services have no units here.
+			continue
+		} else if err != nil {
+			return params.ChangePlan{}, errors.Trace(err)
+		}
+		units, err := unitNames(svc)
+		if err != nil {
+			return params.ChangePlan{}, errors.Trace(err)
+		}
+		result.Units = append(result.Units, units...)
+	}
+	return result, nil
+}
+
+// PlanServiceDestroy describes what ServiceDestroy would do, including
+// the relations that would be removed and the units destroyed.
+func (c *Client) PlanServiceDestroy(args params.ServiceDestroy) (params.ChangePlan, error) {
+	if err := c.check.RemoveAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
+		return params.ChangePlan{}, errors.Trace(err)
+	}
+	svc, err := c.api.state.DryRun().Service(args.ServiceName)
+	if err != nil {
+		return params.ChangePlan{}, err
+	}
+	relations, err := svc.Relations()
+	if err != nil {
+		return params.ChangePlan{}, errors.Trace(err)
+	}
+	units, err := unitNames(svc)
+	if err != nil {
+		return params.ChangePlan{}, errors.Trace(err)
+	}
+	if err := svc.Destroy(); err != nil {
+		return params.ChangePlan{}, err
+	}
+	result := params.ChangePlan{
+		Changes: []string{fmt.Sprintf("destroy service %q", args.ServiceName)},
+		Units:   units,
+	}
+	for _, rel := range relations {
+		result.Changes = append(result.Changes, fmt.Sprintf("remove relation %s", rel))
+	}
+	for _, unit := range units {
+		result.Changes = append(result.Changes, fmt.Sprintf("destroy unit %s", unit))
+	}
+	return result, nil
+}
+
+// PlanServiceSetCharm describes what ServiceSetCharm would do. The
+// units of the service are reported as affected, as they would be
+// upgraded. The charm must already have been added to the environment.
+func (c *Client) PlanServiceSetCharm(args params.ServiceSetCharm) (params.ChangePlan, error) {
+	if !args.Force {
+		if err := c.check.ChangeAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
+			return params.ChangePlan{}, errors.Trace(err)
+		}
+	}
+	dry := c.api.state.DryRun()
+	svc, err := dry.Service(args.ServiceName)
+	if err != nil {
+		return params.ChangePlan{}, err
+	}
+	curl, err := charm.ParseURL(args.CharmUrl)
+	if err != nil {
+		return params.ChangePlan{}, err
+	}
+	ch, err := dry.Charm(curl)
+	if err != nil {
+		return params.ChangePlan{}, err
+	}
+	oldURL, _ := svc.CharmURL()
+	if err := svc.SetCharm(ch, args.Force); err != nil {
+		return params.ChangePlan{}, err
+	}
+	units, err := unitNames(svc)
+	if err != nil {
+		return params.ChangePlan{}, errors.Trace(err)
+	}
+	result := params.ChangePlan{
+		Changes: []string{fmt.Sprintf("upgrade service %q from charm %q to %q", args.ServiceName, oldURL, curl)},
+		Units:   units,
+	}
+	for _, unit := range units {
+		result.Changes = append(result.Changes, fmt.Sprintf("upgrade unit %s", unit))
+	}
+	return result, nil
+}
+
+// PlanSetServiceConstraints describes what SetServiceConstraints
+// would do. The constraints apply to machines provisioned for units
+// added later, so no units are affected.
+func (c *Client) PlanSetServiceConstraints(args params.SetConstraints) (params.ChangePlan, error) {
+	if err := c.check.ChangeAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
+		return params.ChangePlan{}, errors.Trace(err)
+	}
+	st := c.api.state
+	svc, err := st.DryRun().Service(args.ServiceName)
+	if err != nil {
+		return params.ChangePlan{}, err
+	}
+	if err := svc.SetConstraints(args.Constraints); err != nil {
+		return params.ChangePlan{}, err
+	}
+	result := params.ChangePlan{
+		Changes: []string{fmt.Sprintf("set constraints of service %q to %q", args.ServiceName, args.Constraints)},
+	}
+	return addUnsupportedWarning(st, result, args.Constraints)
+}
+
+// PlanSetEnvironmentConstraints describes what SetEnvironmentConstraints
+// would do.
+func (c *Client) PlanSetEnvironmentConstraints(args params.SetConstraints) (params.ChangePlan, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.ChangePlan{}, errors.Trace(err)
+	}
+	st := c.api.state
+	if err := st.DryRun().SetEnvironConstraints(args.Constraints); err != nil {
+		return params.ChangePlan{}, errors.Trace(err)
+	}
+	result := params.ChangePlan{
+		Changes: []string{fmt.Sprintf("set environment constraints to %q", args.Constraints)},
+	}
+	return addUnsupportedWarning(st, result, args.Constraints)
+}
+
+// addUnsupportedWarning adds a warning to the plan if any of the
+// given constraints are not supported by the environment.
+func addUnsupportedWarning(st *state.State, plan params.ChangePlan, cons constraints.Value) (params.ChangePlan, error) {
+	_, unsupported, err := st.CheckConstraints(cons)
+	if err != nil {
+		return params.ChangePlan{}, errors.Trace(err)
+	}
+	if len(unsupported) > 0 {
+		plan.Warnings = append(plan.Warnings, unsupportedWarning(unsupported))
+	}
+	return plan, nil
+}
+
+func unsupportedWarning(unsupported []string) string {
+	return fmt.Sprintf("unsupported constraints would be ignored: %s", strings.Join(unsupported, ","))
+}
+
+// unitNames returns the names of the service's units.
+func unitNames(svc *state.Service) ([]string, error) {
+	units, err := svc.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]string, len(units))
+	for i, unit := range units {
+		result[i] = unit.Name()
+	}
+	return result, nil
+}
@@ -0,0 +1,205 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testcharms"
+	"github.com/juju/juju/testing/factory"
+)
+
+type planSuite struct {
+	baseSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&planSuite{})
+
+func (s *planSuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *planSuite) TestPlanServiceDeploy(c *gc.C) {
+	ch := s.AddTestingCharm(c, "mysql")
+	plan, err := s.APIState.Client().PlanServiceDeploy(params.ServiceDeploy{
+		ServiceName:   "mysql",
+		CharmUrl:      ch.URL().String(),
+		NumUnits:      2,
+		Constraints:   constraints.MustParse("mem=4G"),
+		ToMachineSpec: "zone=zone1,zone=zone2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan, jc.DeepEquals, params.ChangePlan{
+		Changes: []string{
+			`add service "mysql" using charm "` + ch.URL().String() + `"`,
+			"add unit mysql/0 to a new machine (zone=zone1)",
+			"add unit mysql/1 to a new machine (zone=zone2)",
+		},
+		Machines: []params.PlannedMachine{{
+			Unit:        "mysql/0",
+			Series:      "quantal",
+			Constraints: constraints.MustParse("mem=4G"),
+			Placement:   "zone=zone1",
+		}, {
+			Unit:        "mysql/1",
+			Series:      "quantal",
+			Constraints: constraints.MustParse("mem=4G"),
+			Placement:   "zone=zone2",
+		}},
+		Units: []string{"mysql/0", "mysql/1"},
+	})
+	_, err = s.State.Service("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.APIState.Client().PlanServiceDeploy(params.ServiceDeploy{
+		ServiceName: "wordpress",
+		CharmUrl:    ch.URL().String(),
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "wordpress": service already exists`)
+}
+
+func (s *planSuite) TestPlanServiceDeployCharmNotAdded(c *gc.C) {
+	dir := testcharms.Repo.CharmDir("mysql")
+	args := params.ServiceDeploy{
+		ServiceName: "mysql",
+		CharmUrl:    "cs:quantal/mysql-1",
+		NumUnits:    1,
+		CharmMeta:   dir.Meta(),
+		CharmConfig: dir.Config(),
+	}
+	plan, err := s.APIState.Client().PlanServiceDeploy(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan.Changes, jc.DeepEquals, []string{
+		`add service "mysql" using charm "cs:quantal/mysql-1"`,
+		"add unit mysql/0 to a new machine",
+	})
+	_, err = s.State.Charm(charm.MustParseURL(args.CharmUrl))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// The deployment is checked against the charm's metadata.
+	args.ServiceName = "wordpress"
+	_, err = s.APIState.Client().PlanServiceDeploy(args)
+	c.Assert(err, gc.ErrorMatches, `cannot add service "wordpress": service already exists`)
+
+	// Without it, the charm must be in the environment.
+	args.ServiceName = "mysql"
+	args.CharmMeta = nil
+	args.CharmConfig = nil
+	_, err = s.APIState.Client().PlanServiceDeploy(args)
+	c.Assert(err, gc.ErrorMatches, `charm "cs:quantal/mysql-1" not found`)
+}
+
+func (s *planSuite) TestPlanAddRelation(c *gc.C) {
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service})
+	plan, err := s.APIState.Client().PlanAddRelation("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan, jc.DeepEquals, params.ChangePlan{
+		Changes: []string{"add relation wordpress:db mysql:server"},
+		Units:   []string{unit.Name()},
+	})
+	_, err = s.State.KeyRelation("wordpress:db mysql:server")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *planSuite) TestPlanServiceDestroy(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service})
+	plan, err := s.APIState.Client().PlanServiceDestroy("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan, jc.DeepEquals, params.ChangePlan{
+		Changes: []string{`destroy service "wordpress"`, "destroy unit " + unit.Name()},
+		Units:   []string{unit.Name()},
+	})
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.Life(), gc.Equals, state.Alive)
+
+	_, err = s.APIState.Client().PlanServiceDestroy("unknown")
+	c.Assert(err, gc.ErrorMatches, `service "unknown" not found`)
+}
+
+func (s *planSuite) TestPlanServiceSetCharm(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service})
+	oldURL, _ := s.service.CharmURL()
+	ch := s.AddTestingCharm(c, "mysql")
+	plan, err := s.APIState.Client().PlanServiceSetCharm("wordpress", ch.URL().String(), false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan, jc.DeepEquals, params.ChangePlan{
+		Changes: []string{
+			`upgrade service "wordpress" from charm "` + oldURL.String() + `" to "` + ch.URL().String() + `"`,
+			"upgrade unit " + unit.Name(),
+		},
+		Units: []string{unit.Name()},
+	})
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := s.service.CharmURL()
+	c.Assert(curl, gc.DeepEquals, oldURL)
+
+	subordinate := s.AddTestingCharm(c, "logging")
+	_, err = s.APIState.Client().PlanServiceSetCharm("wordpress", subordinate.URL().String(), false)
+	c.Assert(err, gc.ErrorMatches, `cannot change a service's subordinacy`)
+}
+
+func (s *planSuite) TestPlanSetServiceConstraints(c *gc.C) {
+	cons := constraints.MustParse("mem=8G")
+	plan, err := s.APIState.Client().PlanSetServiceConstraints("wordpress", cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan, jc.DeepEquals, params.ChangePlan{
+		Changes: []string{`set constraints of service "wordpress" to "mem=8192M"`},
+	})
+	serviceCons, err := s.service.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(&serviceCons, jc.Satisfies, constraints.IsEmpty)
+}
+
+func (s *planSuite) TestPlanSetEnvironmentConstraints(c *gc.C) {
+	cons := constraints.MustParse("mem=8G")
+	plan, err := s.APIState.Client().PlanSetEnvironmentConstraints(cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan.Changes, jc.DeepEquals, []string{`set environment constraints to "mem=8192M"`})
+	envCons, err := s.State.EnvironConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(&envCons, jc.Satisfies, constraints.IsEmpty)
+}
+
+func (s *planSuite) TestBlockPlanChanges(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockPlanChanges")
+	_, err := s.APIState.Client().PlanSetServiceConstraints("wordpress", constraints.Value{})
+	s.AssertBlocked(c, err, "TestBlockPlanChanges")
+	_, err = s.APIState.Client().PlanAddRelation("wordpress", "mysql")
+	s.AssertBlocked(c, err, "TestBlockPlanChanges")
+}
+
+func (s *planSuite) TestBlockPlanServiceDestroy(c *gc.C) {
+	s.BlockRemoveObject(c, "TestBlockPlanServiceDestroy")
+	_, err := s.APIState.Client().PlanServiceDestroy("wordpress")
+	s.AssertBlocked(c, err, "TestBlockPlanServiceDestroy")
+}
+
+func (s *planSuite) TestBlockPlanServiceDeployToMachine(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SwitchBlockOnWithArgs(state.ChangeBlock, state.BlockArgs{
+		Message: "TestBlockPlanServiceDeployToMachine",
+		Entity:  m.Tag(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	ch := s.AddTestingCharm(c, "mysql")
+	_, err = s.APIState.Client().PlanServiceDeploy(params.ServiceDeploy{
+		ServiceName:   "mysql",
+		CharmUrl:      ch.URL().String(),
+		NumUnits:      1,
+		ToMachineSpec: "lxc:" + m.Id(),
+	})
+	s.AssertBlocked(c, err, "TestBlockPlanServiceDeployToMachine")
+}
@@ -0,0 +1,51 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// SetServiceStateless marks a service as stateless, or not. The units
+// of stateless services are moved to replacement machines when the
+// machines they are assigned to fail, if the environment's self-heal
+// setting is enabled.
+func (c *Client) SetServiceStateless(args params.SetServiceStateless) error {
+	if err := c.check.ChangeAllowedFor(names.NewServiceTag(args.ServiceName)); err != nil {
+		return errors.Trace(err)
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return service.SetStateless(args.Stateless)
+}
+
+// SelfHealHistory returns the most recent replacements of failed
+// machines, newest first.
+func (c *Client) SelfHealHistory(args params.SelfHealHistoryArgs) (params.SelfHealHistoryResult, error) {
+	if args.Size < 1 {
+		return params.SelfHealHistoryResult{}, errors.Errorf("invalid history size %d", args.Size)
+	}
+	history, err := c.api.state.SelfHealHistory(args.Size)
+	if err != nil {
+		return params.SelfHealHistoryResult{}, errors.Trace(err)
+	}
+	result := params.SelfHealHistoryResult{
+		Events: make([]params.SelfHealEvent, len(history)),
+	}
+	for i, event := range history {
+		result.Events[i] = params.SelfHealEvent{
+			Time:          event.Time,
+			MachineId:     event.MachineId,
+			ReplacementId: event.ReplacementId,
+			Units:         event.Units,
+			Reason:        event.Reason,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type selfHealSuite struct {
+	baseSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&selfHealSuite{})
+
+func (s *selfHealSuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *selfHealSuite) TestSetServiceStateless(c *gc.C) {
+	err := s.APIState.Client().SetServiceStateless("wordpress", true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.IsStateless(), jc.IsTrue)
+
+	err = s.APIState.Client().SetServiceStateless("wordpress", false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.IsStateless(), jc.IsFalse)
+
+	err = s.APIState.Client().SetServiceStateless("unknown", true)
+	c.Assert(err, gc.ErrorMatches, `service "unknown" not found`)
+}
+
+func (s *selfHealSuite) TestSelfHealHistory(c *gc.C) {
+	err := s.service.SetStateless(true)
+	c.Assert(err, jc.ErrorIsNil)
+	machine := s.Factory.MakeMachine(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, Machine: machine})
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	replacement, err := s.State.ReplaceMachine(machine, "agent is lost")
+	c.Assert(err, jc.ErrorIsNil)
+
+	events, err := s.APIState.Client().SelfHealHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 1)
+	c.Assert(events[0].MachineId, gc.Equals, machine.Id())
+	c.Assert(events[0].ReplacementId, gc.Equals, replacement.Id())
+	c.Assert(events[0].Units, jc.DeepEquals, []string{unit.Name()})
+	c.Assert(events[0].Reason, gc.Equals, "agent is lost")
+
+	_, err = s.APIState.Client().SelfHealHistory(0)
+	c.Assert(err, gc.ErrorMatches, "invalid history size 0")
+}
+
+func (s *selfHealSuite) TestBlockSetServiceStateless(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockSetServiceStateless")
+	err := s.APIState.Client().SetServiceStateless("wordpress", true)
+	s.AssertBlocked(c, err, "TestBlockSetServiceStateless")
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v5"
 	"gopkg.in/juju/charm.v5/hooks"
@@ -106,6 +107,173 @@ func (c *Client) UnitStatusHistory(args params.StatusHistory) (api.UnitStatusHis
 	return statuses, nil
 }
 
+// EntityStatusHistory returns a slice of past statuses for a given
+// unit, machine or service, identified by its tag.
+func (c *Client) EntityStatusHistory(args params.EntityStatusHistory) (api.UnitStatusHistory, error) {
+	tag, err := names.ParseTag(args.Tag)
+	if err != nil {
+		return api.UnitStatusHistory{}, errors.Trace(err)
+	}
+	switch tag := tag.(type) {
+	case names.UnitTag:
+		return c.UnitStatusHistory(params.StatusHistory{
+			Kind: args.Kind,
+			Size: args.Size,
+			Name: tag.Id(),
+		})
+	case names.MachineTag:
+		return c.machineStatusHistory(tag.Id(), args.Kind, args.Size)
+	case names.ServiceTag:
+		return c.serviceStatusHistory(tag.Id(), args.Kind, args.Size)
+	}
+	return api.UnitStatusHistory{}, errors.NotSupportedf("status history for %q", args.Tag)
+}
+
+func (c *Client) machineStatusHistory(id string, kind params.HistoryKind, size int) (api.UnitStatusHistory, error) {
+	if size < 2 {
+		return api.UnitStatusHistory{}, errors.Errorf("invalid history size: %d", size)
+	}
+	switch kind {
+	case params.KindCombined, params.KindAgent, params.KindInstance:
+	default:
+		return api.UnitStatusHistory{}, errors.NotValidf("machine status history kind %q", kind)
+	}
+	machine, err := c.api.state.Machine(id)
+	if err != nil {
+		return api.UnitStatusHistory{}, errors.Trace(err)
+	}
+	statuses := api.UnitStatusHistory{}
+	if kind == params.KindCombined || kind == params.KindAgent {
+		agentStatuses, err := machine.StatusHistory(size - 1)
+		if err != nil {
+			return api.UnitStatusHistory{}, errors.Trace(err)
+		}
+		current, err := machine.Status()
+		if err != nil {
+			return api.UnitStatusHistory{}, errors.Trace(err)
+		}
+		agentStatuses = append(agentStatuses, current)
+		statuses.Statuses = append(statuses.Statuses, agentStatusFromStatusInfo(agentStatuses, params.KindAgent)...)
+	}
+	if kind == params.KindCombined || kind == params.KindInstance {
+		// The instance status history includes the current status.
+		instanceStatuses, err := machine.InstanceStatusHistory(size)
+		if err != nil {
+			return api.UnitStatusHistory{}, errors.Trace(err)
+		}
+		statuses.Statuses = append(statuses.Statuses, agentStatusFromStatusInfo(instanceStatuses, params.KindInstance)...)
+	}
+	sort.Sort(sortableStatuses(statuses.Statuses))
+	if len(statuses.Statuses) > size {
+		statuses.Statuses = statuses.Statuses[len(statuses.Statuses)-size:]
+	}
+	return statuses, nil
+}
+
+func (c *Client) serviceStatusHistory(name string, kind params.HistoryKind, size int) (api.UnitStatusHistory, error) {
+	if size < 2 {
+		return api.UnitStatusHistory{}, errors.Errorf("invalid history size: %d", size)
+	}
+	switch kind {
+	case params.KindCombined, params.KindService:
+	default:
+		return api.UnitStatusHistory{}, errors.NotValidf("service status history kind %q", kind)
+	}
+	service, err := c.api.state.Service(name)
+	if err != nil {
+		return api.UnitStatusHistory{}, errors.Trace(err)
+	}
+	serviceStatuses, err := service.StatusHistory(size - 1)
+	if err != nil {
+		return api.UnitStatusHistory{}, errors.Trace(err)
+	}
+	current, err := service.Status()
+	if err != nil {
+		return api.UnitStatusHistory{}, errors.Trace(err)
+	}
+	// A status derived from units that have never reported
+	// one has no time, and cannot be placed in the history.
+	if current.Since != nil {
+		serviceStatuses = append(serviceStatuses, current)
+	}
+	statuses := api.UnitStatusHistory{
+		Statuses: agentStatusFromStatusInfo(serviceStatuses, params.KindService),
+	}
+	sort.Sort(sortableStatuses(statuses.Statuses))
+	return statuses, nil
+}
+
+// EnvironmentEvents returns a timeline of the status changes, hook
+// failures and finished actions in the environment, restricted by
+// the given filter.
+func (c *Client) EnvironmentEvents(args params.EnvironmentEventsArgs) (params.EnvironmentEventsResults, error) {
+	if args.Limit < 0 {
+		return params.EnvironmentEventsResults{}, errors.Errorf("invalid limit: %d", args.Limit)
+	}
+	filter := state.EventFilter{
+		EntityPrefixes: args.EntityPrefixes,
+		Limit:          args.Limit,
+	}
+	if args.From != nil {
+		filter.From = *args.From
+	}
+	if args.To != nil {
+		filter.To = *args.To
+	}
+	events, err := c.api.state.EnvironmentEvents(filter)
+	if err != nil {
+		return params.EnvironmentEventsResults{}, errors.Trace(err)
+	}
+	results := params.EnvironmentEventsResults{
+		Events: make([]params.EnvironmentEvent, len(events)),
+	}
+	for i, event := range events {
+		results.Events[i] = params.EnvironmentEvent{
+			Time:    event.Time,
+			Kind:    string(event.Kind),
+			Entity:  event.Entity.String(),
+			Status:  event.Status,
+			Message: event.Message,
+		}
+	}
+	return results, nil
+}
+
+// FirewallStatus returns, for each machine (or, in global firewall
+// mode, for the environment), the ingress rules that the firewaller
+// expected to be open and those the provider reported as open when
+// it last checked.
+func (c *Client) FirewallStatus() (params.FirewallStatusResults, error) {
+	statuses, err := c.api.state.AllFirewallStatuses()
+	if err != nil {
+		return params.FirewallStatusResults{}, errors.Trace(err)
+	}
+	results := params.FirewallStatusResults{
+		Results: make([]params.FirewallStatus, len(statuses)),
+	}
+	for i, status := range statuses {
+		tag, err := status.Entity()
+		if err != nil {
+			return params.FirewallStatusResults{}, errors.Trace(err)
+		}
+		result := params.FirewallStatus{
+			Tag:        tag.String(),
+			Checked:    status.Checked(),
+			Expected:   params.FromNetworkIngressRules(status.Expected()),
+			Actual:     params.FromNetworkIngressRules(status.Actual()),
+			Unexpected: params.FromNetworkIngressRules(status.Unexpected()),
+			Missing:    params.FromNetworkIngressRules(status.Missing()),
+		}
+		if detected := status.DriftDetected(); !detected.IsZero() {
+			result.DriftDetected = &detected
+			result.DriftUnexpected = params.FromNetworkIngressRules(status.DriftUnexpected())
+			result.DriftMissing = params.FromNetworkIngressRules(status.DriftMissing())
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
 // FullStatus gives the information needed for juju status over the api
 func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 	cfg, err := c.api.state.EnvironConfig()
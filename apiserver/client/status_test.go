@@ -8,7 +8,9 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
 )
@@ -61,6 +63,93 @@ func (s *statusSuite) TestLegacyStatus(c *gc.C) {
 	c.Check(resultMachine.InstanceId, gc.Equals, instanceId)
 }
 
+func (s *statusSuite) TestMachineStatusHistory(c *gc.C) {
+	machine := s.addMachine(c)
+	err := machine.SetProvisioned("i-fake", "fakenonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetInstanceStatus("running")
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	history, err := client.EntityStatusHistory(params.KindAgent, machine.Tag(), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history.Statuses, gc.HasLen, 2)
+	c.Check(history.Statuses[0].Status, gc.Equals, params.StatusPending)
+	c.Check(history.Statuses[1].Status, gc.Equals, params.StatusStarted)
+
+	history, err = client.EntityStatusHistory(params.KindInstance, machine.Tag(), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history.Statuses, gc.HasLen, 1)
+	c.Check(history.Statuses[0].Status, gc.Equals, params.Status("running"))
+	c.Check(history.Statuses[0].Kind, gc.Equals, params.KindInstance)
+
+	_, err = client.EntityStatusHistory(params.KindWorkload, machine.Tag(), 10)
+	c.Assert(err, gc.ErrorMatches, `machine status history kind "workload" not valid`)
+}
+
+func (s *statusSuite) TestServiceStatusHistory(c *gc.C) {
+	f := factory.NewFactory(s.State)
+	service := f.MakeService(c, nil)
+	err := service.SetStatus(state.StatusMaintenance, "installing", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.SetStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	history, err := client.EntityStatusHistory(params.KindCombined, service.Tag(), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history.Statuses, gc.HasLen, 2)
+	c.Check(history.Statuses[0].Status, gc.Equals, params.StatusMaintenance)
+	c.Check(history.Statuses[0].Info, gc.Equals, "installing")
+	c.Check(history.Statuses[1].Status, gc.Equals, params.StatusActive)
+	c.Check(history.Statuses[1].Kind, gc.Equals, params.KindService)
+}
+
+func (s *statusSuite) TestEnvironmentEvents(c *gc.C) {
+	machine := s.addMachine(c)
+	err := machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	client := s.APIState.Client()
+	events, err := client.EnvironmentEvents(params.EnvironmentEventsArgs{
+		EntityPrefixes: []string{machine.Tag().String()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.Not(gc.HasLen), 0)
+	last := events[len(events)-1]
+	c.Check(last.Kind, gc.Equals, "machine")
+	c.Check(last.Entity, gc.Equals, "machine-"+machine.Id())
+	c.Check(last.Status, gc.Equals, "started")
+}
+
+func (s *statusSuite) TestEnvironmentEventsInvalidLimit(c *gc.C) {
+	client := s.APIState.Client()
+	_, err := client.EnvironmentEvents(params.EnvironmentEventsArgs{Limit: -1})
+	c.Assert(err, gc.ErrorMatches, "invalid limit: -1")
+}
+
+func (s *statusSuite) TestFirewallStatus(c *gc.C) {
+	machine := s.addMachine(c)
+	http := network.IngressRule{network.MustParsePortRange("80/tcp"), network.AnySourceCIDR}
+	ssh := network.IngressRule{network.MustParsePortRange("22/tcp"), network.AnySourceCIDR}
+	err := s.State.SetFirewallStatus(machine.Tag(), []network.IngressRule{http}, []network.IngressRule{ssh})
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	statuses, err := client.FirewallStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses, gc.HasLen, 1)
+	status := statuses[0]
+	c.Check(status.Tag, gc.Equals, machine.Tag().String())
+	c.Check(status.Expected, jc.DeepEquals, params.FromNetworkIngressRules([]network.IngressRule{http}))
+	c.Check(status.Actual, jc.DeepEquals, params.FromNetworkIngressRules([]network.IngressRule{ssh}))
+	c.Check(status.Unexpected, jc.DeepEquals, status.Actual)
+	c.Check(status.Missing, jc.DeepEquals, status.Expected)
+	c.Assert(status.DriftDetected, gc.NotNil)
+	c.Check(status.DriftDetected.Equal(status.Checked), jc.IsTrue)
+}
+
 var _ = gc.Suite(&statusUnitTestSuite{})
 
 type statusUnitTestSuite struct {
@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// AddWebhook registers a webhook to be notified of status transitions
+// in the environment.
+func (c *Client) AddWebhook(args params.WebhookParams) (params.Webhook, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.Webhook{}, errors.Trace(err)
+	}
+	statuses := make([]state.Status, len(args.Statuses))
+	for i, status := range args.Statuses {
+		statuses[i] = state.Status(status)
+	}
+	webhook, err := c.api.state.AddWebhook(state.WebhookParams{
+		URL:           args.URL,
+		EntityPattern: args.EntityPattern,
+		Statuses:      statuses,
+	})
+	if err != nil {
+		return params.Webhook{}, errors.Trace(err)
+	}
+	return webhookParams(webhook), nil
+}
+
+// RemoveWebhook removes a webhook from the environment.
+func (c *Client) RemoveWebhook(args params.WebhookId) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	return c.api.state.RemoveWebhook(args.Id)
+}
+
+// Webhooks returns the webhooks registered in the environment.
+func (c *Client) Webhooks() (params.WebhooksResults, error) {
+	webhooks, err := c.api.state.AllWebhooks()
+	if err != nil {
+		return params.WebhooksResults{}, errors.Trace(err)
+	}
+	results := params.WebhooksResults{
+		Webhooks: make([]params.Webhook, len(webhooks)),
+	}
+	for i, webhook := range webhooks {
+		results.Webhooks[i] = webhookParams(webhook)
+	}
+	return results, nil
+}
+
+// WebhookDeliveries returns the most recent deliveries to a webhook,
+// newest first.
+func (c *Client) WebhookDeliveries(args params.WebhookDeliveriesArgs) (params.WebhookDeliveriesResults, error) {
+	if args.Size <= 0 {
+		return params.WebhookDeliveriesResults{}, errors.Errorf("invalid size: %d", args.Size)
+	}
+	webhook, err := c.api.state.Webhook(args.Id)
+	if err != nil {
+		return params.WebhookDeliveriesResults{}, errors.Trace(err)
+	}
+	deliveries, err := webhook.Deliveries(args.Size)
+	if err != nil {
+		return params.WebhookDeliveriesResults{}, errors.Trace(err)
+	}
+	results := params.WebhookDeliveriesResults{
+		Deliveries: make([]params.WebhookDelivery, len(deliveries)),
+	}
+	for i, d := range deliveries {
+		results.Deliveries[i] = params.WebhookDelivery{
+			Entity:       d.Entity,
+			Status:       params.Status(d.Status),
+			Time:         d.Time,
+			Attempts:     d.Attempts,
+			ResponseCode: d.ResponseCode,
+			Error:        d.Error,
+		}
+	}
+	return results, nil
+}
+
+func webhookParams(webhook *state.Webhook) params.Webhook {
+	statuses := make([]params.Status, len(webhook.Statuses()))
+	for i, status := range webhook.Statuses() {
+		statuses[i] = params.Status(status)
+	}
+	return params.Webhook{
+		Id:            webhook.Id(),
+		URL:           webhook.URL(),
+		EntityPattern: webhook.EntityPattern(),
+		Statuses:      statuses,
+		Created:       webhook.Created(),
+	}
+}
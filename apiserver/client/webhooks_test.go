@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type webhooksSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&webhooksSuite{})
+
+func (s *webhooksSuite) TestAddWebhook(c *gc.C) {
+	client := s.APIState.Client()
+	webhook, err := client.AddWebhook(params.WebhookParams{
+		URL:           "https://example.com/hook",
+		EntityPattern: "unit-*",
+		Statuses:      []params.Status{"error"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhook.URL, gc.Equals, "https://example.com/hook")
+
+	stored, err := s.State.Webhook(webhook.Id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stored.EntityPattern(), gc.Equals, "unit-*")
+	c.Assert(stored.Statuses(), jc.DeepEquals, []state.Status{state.StatusError})
+
+	webhooks, err := client.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhooks, gc.HasLen, 1)
+	c.Assert(webhooks[0].Id, gc.Equals, webhook.Id)
+	c.Assert(webhooks[0].Statuses, jc.DeepEquals, []params.Status{"error"})
+}
+
+func (s *webhooksSuite) TestAddWebhookInvalid(c *gc.C) {
+	_, err := s.APIState.Client().AddWebhook(params.WebhookParams{URL: "ftp://example.com"})
+	c.Assert(err, gc.ErrorMatches, `cannot add webhook: webhook URL "ftp://example.com" not valid`)
+}
+
+func (s *webhooksSuite) TestBlockAddWebhook(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockAddWebhook")
+	_, err := s.APIState.Client().AddWebhook(params.WebhookParams{URL: "http://example.com"})
+	s.AssertBlocked(c, err, "TestBlockAddWebhook")
+}
+
+func (s *webhooksSuite) TestRemoveWebhook(c *gc.C) {
+	webhook, err := s.State.AddWebhook(state.WebhookParams{URL: "http://example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.APIState.Client().RemoveWebhook(webhook.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	all, err := s.State.AllWebhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 0)
+}
+
+func (s *webhooksSuite) TestWebhookDeliveries(c *gc.C) {
+	webhook, err := s.State.AddWebhook(state.WebhookParams{URL: "http://example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = webhook.RecordDelivery(state.WebhookDelivery{
+		Entity:       "machine-0",
+		Status:       state.StatusDown,
+		Attempts:     2,
+		ResponseCode: 200,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	deliveries, err := s.APIState.Client().WebhookDeliveries(webhook.Id(), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deliveries, gc.HasLen, 1)
+	c.Assert(deliveries[0].Entity, gc.Equals, "machine-0")
+	c.Assert(deliveries[0].Status, gc.Equals, params.Status("down"))
+	c.Assert(deliveries[0].Attempts, gc.Equals, 2)
+
+	_, err = s.APIState.Client().WebhookDeliveries("42", 10)
+	c.Assert(err, gc.ErrorMatches, `webhook "42" not found`)
+}
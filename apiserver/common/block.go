@@ -5,12 +5,14 @@ package common
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/state"
 )
 
 type BlockGetter interface {
 	GetBlockForType(t state.BlockType) (state.Block, bool, error)
+	GetBlockForEntity(t state.BlockType, entity names.Tag) (state.Block, bool, error)
 }
 
 // BlockChecker checks for current blocks if any.
@@ -54,6 +56,63 @@ func (c *BlockChecker) DestroyAllowed() error {
 	return c.checkBlock(state.ChangeBlock)
 }
 
+// ChangeAllowedFor checks if change block is in place for the
+// environment, or for any of the given entities. Blocks on a
+// service apply to its units too.
+func (c *BlockChecker) ChangeAllowedFor(entities ...names.Tag) error {
+	if err := c.ChangeAllowed(); err != nil {
+		return err
+	}
+	return c.checkEntityBlocks(entities, state.ChangeBlock)
+}
+
+// RemoveAllowedFor checks if remove block is in place for the
+// environment, or for any of the given entities. Blocks on a
+// service apply to its units too.
+func (c *BlockChecker) RemoveAllowedFor(entities ...names.Tag) error {
+	if err := c.RemoveAllowed(); err != nil {
+		return err
+	}
+	return c.checkEntityBlocks(entities, state.RemoveBlock, state.ChangeBlock)
+}
+
+// checkEntityBlocks checks if any of the specified blocks are in
+// place for any of the given entities.
+func (c *BlockChecker) checkEntityBlocks(entities []names.Tag, blockTypes ...state.BlockType) error {
+	for _, entity := range entities {
+		blocked := blockedEntity(entity)
+		if blocked == nil {
+			continue
+		}
+		for _, blockType := range blockTypes {
+			aBlock, isEnabled, err := c.getter.GetBlockForEntity(blockType, blocked)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if isEnabled {
+				return ErrOperationBlocked(aBlock.Message())
+			}
+		}
+	}
+	return nil
+}
+
+// blockedEntity returns the entity whose blocks apply to the given
+// one, or nil if it cannot be blocked individually.
+func blockedEntity(entity names.Tag) names.Tag {
+	switch entity := entity.(type) {
+	case names.ServiceTag, names.MachineTag:
+		return entity
+	case names.UnitTag:
+		service, err := names.UnitService(entity.Id())
+		if err != nil {
+			return nil
+		}
+		return names.NewServiceTag(service)
+	}
+	return nil
+}
+
 // checkBlock checks if specified operation must be blocked.
 // If it does, the method throws specific error that can be examined
 // to stop operation execution.
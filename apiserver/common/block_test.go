@@ -4,6 +4,8 @@
 package common_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -18,16 +20,26 @@ import (
 type mockBlock struct {
 	t state.BlockType
 	m string
+	e names.Tag
 }
 
 func (m mockBlock) Id() string { return "" }
 
-func (m mockBlock) Tag() (names.Tag, error) { return names.NewEnvironTag("mocktesting"), nil }
+func (m mockBlock) Tag() (names.Tag, error) {
+	if m.e != nil {
+		return m.e, nil
+	}
+	return names.NewEnvironTag("mocktesting"), nil
+}
 
 func (m mockBlock) Type() state.BlockType { return m.t }
 
 func (m mockBlock) Message() string { return m.m }
 
+func (m mockBlock) Expiry() time.Time { return time.Time{} }
+
+func (m mockBlock) CreatedBy() string { return "" }
+
 type blockCheckerSuite struct {
 	testing.FakeJujuHomeSuite
 	aBlock                  state.Block
@@ -47,13 +59,20 @@ func (s *blockCheckerSuite) SetUpTest(c *gc.C) {
 }
 
 func (mock *blockCheckerSuite) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
-	if mock.aBlock.Type() == t {
+	if mock.aBlock.Type() == t && mock.aBlock.(mockBlock).e == nil {
 		return mock.aBlock, true, nil
 	} else {
 		return nil, false, nil
 	}
 }
 
+func (mock *blockCheckerSuite) GetBlockForEntity(t state.BlockType, entity names.Tag) (state.Block, bool, error) {
+	if mock.aBlock.Type() == t && mock.aBlock.(mockBlock).e == entity {
+		return mock.aBlock, true, nil
+	}
+	return nil, false, nil
+}
+
 func (s *blockCheckerSuite) TestDestroyBlockChecker(c *gc.C) {
 	s.aBlock = s.destroy
 	s.assertErrorBlocked(c, true, s.blockchecker.DestroyAllowed(), s.destroy.Message())
@@ -87,6 +106,30 @@ func (s *blockCheckerSuite) TestChangeBlockChecker(c *gc.C) {
 	s.assertErrorBlocked(c, true, s.blockchecker.ChangeAllowed(), s.change.Message())
 }
 
+func (s *blockCheckerSuite) TestEntityBlockChecker(c *gc.C) {
+	service := names.NewServiceTag("postgresql")
+	unit := names.NewUnitTag("postgresql/0")
+	other := names.NewServiceTag("mysql")
+	machine := names.NewMachineTag("1")
+
+	s.aBlock = mockBlock{t: state.RemoveBlock, m: "Mock BLOCK testing: REMOVE postgresql", e: service}
+	s.assertErrorBlocked(c, false, s.blockchecker.RemoveAllowed(), s.aBlock.Message())
+	s.assertErrorBlocked(c, true, s.blockchecker.RemoveAllowedFor(service), s.aBlock.Message())
+	s.assertErrorBlocked(c, true, s.blockchecker.RemoveAllowedFor(other, unit), s.aBlock.Message())
+	s.assertErrorBlocked(c, false, s.blockchecker.RemoveAllowedFor(other, machine), s.aBlock.Message())
+	s.assertErrorBlocked(c, false, s.blockchecker.ChangeAllowedFor(service), s.aBlock.Message())
+
+	s.aBlock = mockBlock{t: state.ChangeBlock, m: "Mock BLOCK testing: CHANGE machine 1", e: machine}
+	s.assertErrorBlocked(c, true, s.blockchecker.ChangeAllowedFor(machine), s.aBlock.Message())
+	s.assertErrorBlocked(c, true, s.blockchecker.RemoveAllowedFor(machine), s.aBlock.Message())
+	s.assertErrorBlocked(c, false, s.blockchecker.ChangeAllowedFor(service, unit), s.aBlock.Message())
+
+	// Environment blocks still apply.
+	s.aBlock = s.change
+	s.assertErrorBlocked(c, true, s.blockchecker.ChangeAllowedFor(service), s.change.Message())
+	s.assertErrorBlocked(c, true, s.blockchecker.RemoveAllowedFor(), s.change.Message())
+}
+
 func (s *blockCheckerSuite) assertErrorBlocked(c *gc.C, blocked bool, err error, msg string) {
 	if blocked {
 		c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
@@ -20,6 +20,7 @@ func BlockDeviceFromState(in state.BlockDeviceInfo) storage.BlockDevice {
 		in.FilesystemType,
 		in.InUse,
 		in.MountPoint,
+		in.Partitioned,
 	}
 }
 
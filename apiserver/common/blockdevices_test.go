@@ -0,0 +1,51 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type blockDevicesSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&blockDevicesSuite{})
+
+func (s *blockDevicesSuite) TestMatchingBlockDeviceSerial(c *gc.C) {
+	// A GCE persistent disk has no device name in its attachment; the
+	// volume is identified by the serial udev reports for the disk.
+	blockDevices := []state.BlockDeviceInfo{{
+		DeviceName: "sda",
+		Serial:     "0Google_PersistentDisk_persistent-disk-0",
+	}, {
+		DeviceName: "sdb",
+		Serial:     "0Google_PersistentDisk_juju-env-volume-0",
+	}}
+	volumeInfo := state.VolumeInfo{
+		VolumeId: "zone--juju-env-volume-0",
+		Serial:   "0Google_PersistentDisk_juju-env-volume-0",
+	}
+	dev, ok := common.MatchingBlockDevice(blockDevices, volumeInfo, state.VolumeAttachmentInfo{})
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(dev.DeviceName, gc.Equals, "sdb")
+
+	volumeInfo.Serial = "0Google_PersistentDisk_juju-env-volume-1"
+	_, ok = common.MatchingBlockDevice(blockDevices, volumeInfo, state.VolumeAttachmentInfo{})
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *blockDevicesSuite) TestMatchingBlockDeviceName(c *gc.C) {
+	blockDevices := []state.BlockDeviceInfo{{DeviceName: "sda"}, {DeviceName: "xvdf"}}
+	dev, ok := common.MatchingBlockDevice(blockDevices, state.VolumeInfo{}, state.VolumeAttachmentInfo{
+		DeviceName: "xvdf",
+	})
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(dev.DeviceName, gc.Equals, "xvdf")
+}
@@ -96,8 +96,9 @@ func volumeStorageAttachmentInfo(
 		return nil, errors.Trace(err)
 	}
 	return &storage.StorageAttachmentInfo{
-		storage.StorageKindBlock,
-		devicePath,
+		Kind:     storage.StorageKindBlock,
+		Location: devicePath,
+		Size:     volumeInfo.Size,
 	}, nil
 }
 
@@ -120,8 +121,8 @@ func filesystemStorageAttachmentInfo(
 		return nil, errors.Annotate(err, "getting filesystem attachment info")
 	}
 	return &storage.StorageAttachmentInfo{
-		storage.StorageKindFilesystem,
-		filesystemAttachmentInfo.MountPoint,
+		Kind:     storage.StorageKindFilesystem,
+		Location: filesystemAttachmentInfo.MountPoint,
 	}, nil
 }
 
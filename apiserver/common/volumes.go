@@ -42,11 +42,38 @@ func VolumeParams(v state.Volume, poolManager poolmanager.PoolManager) (params.V
 		return params.VolumeParams{}, errors.Trace(err)
 	}
 	return params.VolumeParams{
-		v.Tag().String(),
-		stateVolumeParams.Size,
-		string(providerType),
-		cfg.Attrs(),
-		nil, // attachment params set by the caller
+		VolumeTag:  v.Tag().String(),
+		Size:       stateVolumeParams.Size,
+		Provider:   string(providerType),
+		Attributes: cfg.Attrs(),
+		// snapshot and attachment params set by the caller
+	}, nil
+}
+
+// VolumeResizeParams returns the parameters for resizing the given
+// volume. An error satisfying errors.IsNotFound is returned if the
+// volume has no pending resize.
+func VolumeResizeParams(v state.Volume, poolManager poolmanager.PoolManager) (params.VolumeResizeParams, error) {
+	size, ok := v.PendingResize()
+	if !ok {
+		return params.VolumeResizeParams{}, errors.NotFoundf(
+			"pending resize for volume %q", v.Tag().Id(),
+		)
+	}
+	info, err := v.Info()
+	if err != nil {
+		return params.VolumeResizeParams{}, errors.Trace(err)
+	}
+	providerType, cfg, err := StoragePoolConfig(info.Pool, poolManager)
+	if err != nil {
+		return params.VolumeResizeParams{}, errors.Trace(err)
+	}
+	return params.VolumeResizeParams{
+		VolumeTag:  v.Tag().String(),
+		VolumeId:   info.VolumeId,
+		Size:       size,
+		Provider:   string(providerType),
+		Attributes: cfg.Attrs(),
 	}, nil
 }
 
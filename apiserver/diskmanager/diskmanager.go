@@ -4,6 +4,10 @@
 package diskmanager
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 
@@ -92,6 +96,155 @@ func (d *DiskManagerAPI) SetMachineBlockDevices(args params.SetMachineBlockDevic
 	return result, nil
 }
 
+// FilesystemAttachments returns the provisioned filesystem attachments
+// of each of the specified machines, so that the machine agent may
+// report their usage.
+func (d *DiskManagerAPI) FilesystemAttachments(args params.Entities) (params.FilesystemAttachmentsResults, error) {
+	result := params.FilesystemAttachmentsResults{
+		Results: make([]params.FilesystemAttachmentsResult, len(args.Entities)),
+	}
+	canAccess, err := d.getAuthFunc()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Entities {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		attachments, err := d.machineFilesystemAttachments(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = attachments
+	}
+	return result, nil
+}
+
+func (d *DiskManagerAPI) machineFilesystemAttachments(tag names.MachineTag) ([]params.FilesystemAttachment, error) {
+	attachments, err := d.st.MachineFilesystemAttachments(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []params.FilesystemAttachment
+	for _, att := range attachments {
+		if att.Life() != state.Alive {
+			continue
+		}
+		info, err := att.Info()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if info.MountPoint == "" {
+			continue
+		}
+		result = append(result, params.FilesystemAttachment{
+			FilesystemTag: att.Filesystem().String(),
+			MachineTag:    att.Machine().String(),
+			MountPoint:    info.MountPoint,
+		})
+	}
+	return result, nil
+}
+
+// SetFilesystemUsage records the usage of the specified filesystem
+// attachments. If the environment has a storage usage threshold, the
+// workload status of the units that the filesystems' storage is
+// attached to is set to blocked while usage exceeds the threshold.
+func (d *DiskManagerAPI) SetFilesystemUsage(args params.FilesystemUsages) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Usages)),
+	}
+	if len(args.Usages) == 0 {
+		return result, nil
+	}
+	canAccess, err := d.getAuthFunc()
+	if err != nil {
+		return result, err
+	}
+	envConfig, err := d.st.EnvironConfig()
+	if err != nil {
+		return result, err
+	}
+	threshold, haveThreshold := envConfig.StorageUsageThreshold()
+	for i, arg := range args.Usages {
+		machineTag, err := names.ParseMachineTag(arg.MachineTag)
+		if err != nil || !canAccess(machineTag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		filesystemTag, err := names.ParseFilesystemTag(arg.FilesystemTag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		usage := state.FilesystemUsage{
+			UsedBytes:  arg.Usage.UsedBytes,
+			FreeBytes:  arg.Usage.FreeBytes,
+			UsedInodes: arg.Usage.UsedInodes,
+			FreeInodes: arg.Usage.FreeInodes,
+		}
+		if haveThreshold && usage.PercentUsed() >= threshold {
+			logger.Debugf("filesystem %s usage exceeds %d%%", filesystemTag.Id(), threshold)
+			usage.ThresholdExceeded = true
+		}
+		err = d.st.SetFilesystemAttachmentUsage(machineTag, filesystemTag, usage)
+		if err == nil && haveThreshold {
+			err = d.updateUsageStatus(filesystemTag, usage, threshold)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// updateUsageStatus sets the workload status of the units that the
+// storage assigned to the specified filesystem is attached to to
+// blocked if its usage exceeds the threshold. Once usage no longer
+// exceeds the threshold, the status is cleared only if it is still
+// the one set here, so statuses set by the charm are left alone.
+func (d *DiskManagerAPI) updateUsageStatus(
+	filesystemTag names.FilesystemTag,
+	usage state.FilesystemUsage,
+	threshold int,
+) error {
+	storageTag, err := d.st.FilesystemStorage(filesystemTag)
+	if errors.IsNotAssigned(err) {
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "getting storage for filesystem")
+	}
+	units, err := d.st.StorageUnits(storageTag)
+	if err != nil {
+		return errors.Annotate(err, "getting units for storage")
+	}
+	// The threshold is not part of the prefix, so that a status set
+	// before the threshold was changed is still recognised.
+	prefix := fmt.Sprintf("storage %s usage exceeds ", storageTag.Id())
+	message := fmt.Sprintf("%s%d%%", prefix, threshold)
+	for _, u := range units {
+		status, err := u.Status()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		blocked := status.Status == state.StatusBlocked && strings.HasPrefix(status.Message, prefix)
+		switch {
+		case usage.ThresholdExceeded && (!blocked || status.Message != message):
+			logger.Warningf("%s", message)
+			err = u.SetStatus(state.StatusBlocked, message, nil)
+		case !usage.ThresholdExceeded && blocked:
+			err = u.SetStatus(state.StatusUnknown, "", nil)
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 func stateBlockDeviceInfo(devices []storage.BlockDevice) []state.BlockDeviceInfo {
 	result := make([]state.BlockDeviceInfo, len(devices))
 	for i, dev := range devices {
@@ -104,6 +257,7 @@ func stateBlockDeviceInfo(devices []storage.BlockDevice) []state.BlockDeviceInfo
 			dev.FilesystemType,
 			dev.InUse,
 			dev.MountPoint,
+			dev.Partitioned,
 		}
 	}
 	return result
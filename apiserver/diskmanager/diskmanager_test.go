@@ -6,6 +6,7 @@ package diskmanager_test
 import (
 	"errors"
 
+	jujuerrors "github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -14,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/diskmanager"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
@@ -34,7 +36,7 @@ func (s *DiskManagerSuite) SetUpTest(c *gc.C) {
 	s.resources = common.NewResources()
 	tag := names.NewMachineTag("0")
 	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: tag}
-	s.st = &mockState{}
+	s.st = &mockState{config: coretesting.EnvironConfig(c).AllAttrs()}
 	diskmanager.PatchState(s, s.st)
 
 	var err error
@@ -107,10 +109,190 @@ func (s *DiskManagerSuite) TestSetMachineBlockDevicesStateError(c *gc.C) {
 	})
 }
 
+func (s *DiskManagerSuite) TestFilesystemAttachments(c *gc.C) {
+	s.st.filesystemAttachments = []state.FilesystemAttachment{
+		&mockFilesystemAttachment{
+			filesystem: names.NewFilesystemTag("0/0"),
+			machine:    names.NewMachineTag("0"),
+			life:       state.Alive,
+			info:       &state.FilesystemAttachmentInfo{MountPoint: "/srv/data"},
+		},
+		&mockFilesystemAttachment{
+			// Not yet provisioned.
+			filesystem: names.NewFilesystemTag("1"),
+			machine:    names.NewMachineTag("0"),
+			life:       state.Alive,
+		},
+		&mockFilesystemAttachment{
+			filesystem: names.NewFilesystemTag("2"),
+			machine:    names.NewMachineTag("0"),
+			life:       state.Dying,
+			info:       &state.FilesystemAttachmentInfo{MountPoint: "/srv/old"},
+		},
+	}
+	results, err := s.api.FilesystemAttachments(params.Entities{
+		Entities: []params.Entity{{"machine-0"}, {"machine-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.FilesystemAttachmentsResults{
+		Results: []params.FilesystemAttachmentsResult{{
+			Result: []params.FilesystemAttachment{{
+				FilesystemTag: "filesystem-0-0",
+				MachineTag:    "machine-0",
+				MountPoint:    "/srv/data",
+			}},
+		}, {
+			Error: &params.Error{"permission denied", "unauthorized access"},
+		}},
+	})
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsage(c *gc.C) {
+	results, err := s.api.SetFilesystemUsage(params.FilesystemUsages{
+		Usages: []params.FilesystemUsage{{
+			FilesystemTag: "filesystem-0-0",
+			MachineTag:    "machine-0",
+			Usage:         params.StorageUsage{UsedBytes: 95, FreeBytes: 5},
+		}, {
+			FilesystemTag: "filesystem-1",
+			MachineTag:    "machine-1",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{
+			Error: nil,
+		}, {
+			Error: &params.Error{"permission denied", "unauthorized access"},
+		}},
+	})
+	// No threshold is set, so it is not reported as exceeded
+	// and unit status is not checked.
+	c.Assert(s.st.usage, jc.DeepEquals, map[string]state.FilesystemUsage{
+		"0/0": {UsedBytes: 95, FreeBytes: 5},
+	})
+	c.Assert(s.st.unit.setStatusCalls, gc.Equals, 0)
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsageThreshold(c *gc.C) {
+	s.st.config["storage-usage-threshold"] = 90
+	setUsage := func(used, free uint64) {
+		results, err := s.api.SetFilesystemUsage(params.FilesystemUsages{
+			Usages: []params.FilesystemUsage{{
+				FilesystemTag: "filesystem-0-0",
+				MachineTag:    "machine-0",
+				Usage:         params.StorageUsage{UsedBytes: used, FreeBytes: free},
+			}},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(results.OneError(), jc.ErrorIsNil)
+	}
+
+	setUsage(50, 50)
+	c.Assert(s.st.usage["0/0"], jc.DeepEquals, state.FilesystemUsage{
+		UsedBytes: 50, FreeBytes: 50,
+	})
+
+	setUsage(95, 5)
+	c.Assert(s.st.usage["0/0"], jc.DeepEquals, state.FilesystemUsage{
+		UsedBytes: 95, FreeBytes: 5, ThresholdExceeded: true,
+	})
+}
+
+func (s *DiskManagerSuite) setFilesystemUsage(c *gc.C, used, free uint64) {
+	results, err := s.api.SetFilesystemUsage(params.FilesystemUsages{
+		Usages: []params.FilesystemUsage{{
+			FilesystemTag: "filesystem-0-0",
+			MachineTag:    "machine-0",
+			Usage:         params.StorageUsage{UsedBytes: used, FreeBytes: free},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsageThresholdUnitStatus(c *gc.C) {
+	s.st.config["storage-usage-threshold"] = 90
+	s.st.unit.status = state.StatusInfo{Status: state.StatusActive}
+
+	s.setFilesystemUsage(c, 50, 50)
+	c.Assert(s.st.unit.setStatusCalls, gc.Equals, 0)
+
+	s.setFilesystemUsage(c, 95, 5)
+	c.Assert(s.st.unit.setStatusCalls, gc.Equals, 1)
+	c.Assert(s.st.unit.status, jc.DeepEquals, state.StatusInfo{
+		Status:  state.StatusBlocked,
+		Message: "storage data/0 usage exceeds 90%",
+	})
+
+	// The unit is already blocked, so its status is not set again.
+	s.setFilesystemUsage(c, 96, 4)
+	c.Assert(s.st.unit.setStatusCalls, gc.Equals, 1)
+
+	// Usage dropping back below the threshold clears the status.
+	s.setFilesystemUsage(c, 50, 50)
+	c.Assert(s.st.unit.setStatusCalls, gc.Equals, 2)
+	c.Assert(s.st.unit.status, jc.DeepEquals, state.StatusInfo{
+		Status: state.StatusUnknown,
+	})
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsageThresholdCharmStatus(c *gc.C) {
+	s.st.config["storage-usage-threshold"] = 90
+	s.st.unit.status = state.StatusInfo{
+		Status:  state.StatusBlocked,
+		Message: "waiting for database",
+	}
+
+	// A status set by the charm is not cleared when usage is
+	// below the threshold.
+	s.setFilesystemUsage(c, 50, 50)
+	c.Assert(s.st.unit.setStatusCalls, gc.Equals, 0)
+
+	s.setFilesystemUsage(c, 95, 5)
+	c.Assert(s.st.unit.setStatusCalls, gc.Equals, 1)
+	c.Assert(s.st.unit.status.Message, gc.Equals, "storage data/0 usage exceeds 90%")
+
+	// Once the charm replaces the status, it is left alone.
+	s.st.unit.status = state.StatusInfo{Status: state.StatusActive}
+	s.setFilesystemUsage(c, 50, 50)
+	c.Assert(s.st.unit.setStatusCalls, gc.Equals, 1)
+	c.Assert(s.st.unit.status, jc.DeepEquals, state.StatusInfo{Status: state.StatusActive})
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsageThresholdChanged(c *gc.C) {
+	s.st.config["storage-usage-threshold"] = 90
+	s.setFilesystemUsage(c, 95, 5)
+	c.Assert(s.st.unit.status.Message, gc.Equals, "storage data/0 usage exceeds 90%")
+
+	// The status set for the old threshold is still recognised.
+	s.st.config["storage-usage-threshold"] = 80
+	s.setFilesystemUsage(c, 85, 15)
+	c.Assert(s.st.unit.status.Message, gc.Equals, "storage data/0 usage exceeds 80%")
+	s.st.config["storage-usage-threshold"] = 99
+	s.setFilesystemUsage(c, 85, 15)
+	c.Assert(s.st.unit.status, jc.DeepEquals, state.StatusInfo{
+		Status: state.StatusUnknown,
+	})
+}
+
+func (s *DiskManagerSuite) TestSetFilesystemUsageThresholdNoStorage(c *gc.C) {
+	s.st.config["storage-usage-threshold"] = 90
+	s.st.storageErr = jujuerrors.NotAssignedf("filesystem 0/0")
+	s.setFilesystemUsage(c, 95, 5)
+	c.Assert(s.st.unit.setStatusCalls, gc.Equals, 0)
+}
+
 type mockState struct {
 	calls   int
 	devices map[string][]state.BlockDeviceInfo
 	err     error
+
+	filesystemAttachments []state.FilesystemAttachment
+	usage                 map[string]state.FilesystemUsage
+	config                map[string]interface{}
+	storageErr            error
+	unit                  mockUnit
 }
 
 func (st *mockState) SetMachineBlockDevices(machineId string, devices []state.BlockDeviceInfo) error {
@@ -121,3 +303,72 @@ func (st *mockState) SetMachineBlockDevices(machineId string, devices []state.Bl
 	st.devices[machineId] = devices
 	return st.err
 }
+
+func (st *mockState) MachineFilesystemAttachments(names.MachineTag) ([]state.FilesystemAttachment, error) {
+	return st.filesystemAttachments, st.err
+}
+
+func (st *mockState) SetFilesystemAttachmentUsage(machine names.MachineTag, filesystem names.FilesystemTag, usage state.FilesystemUsage) error {
+	if st.usage == nil {
+		st.usage = make(map[string]state.FilesystemUsage)
+	}
+	st.usage[filesystem.Id()] = usage
+	return st.err
+}
+
+func (st *mockState) EnvironConfig() (*config.Config, error) {
+	return config.New(config.NoDefaults, st.config)
+}
+
+func (st *mockState) FilesystemStorage(names.FilesystemTag) (names.StorageTag, error) {
+	if st.storageErr != nil {
+		return names.StorageTag{}, st.storageErr
+	}
+	return names.NewStorageTag("data/0"), nil
+}
+
+func (st *mockState) StorageUnits(names.StorageTag) ([]diskmanager.Unit, error) {
+	return []diskmanager.Unit{&st.unit}, st.err
+}
+
+type mockUnit struct {
+	status         state.StatusInfo
+	setStatusCalls int
+}
+
+func (u *mockUnit) Status() (state.StatusInfo, error) {
+	return u.status, nil
+}
+
+func (u *mockUnit) SetStatus(status state.Status, info string, data map[string]interface{}) error {
+	u.setStatusCalls++
+	u.status = state.StatusInfo{Status: status, Message: info, Data: data}
+	return nil
+}
+
+type mockFilesystemAttachment struct {
+	state.FilesystemAttachment
+	filesystem names.FilesystemTag
+	machine    names.MachineTag
+	life       state.Life
+	info       *state.FilesystemAttachmentInfo
+}
+
+func (a *mockFilesystemAttachment) Filesystem() names.FilesystemTag {
+	return a.filesystem
+}
+
+func (a *mockFilesystemAttachment) Machine() names.MachineTag {
+	return a.machine
+}
+
+func (a *mockFilesystemAttachment) Life() state.Life {
+	return a.life
+}
+
+func (a *mockFilesystemAttachment) Info() (state.FilesystemAttachmentInfo, error) {
+	if a.info == nil {
+		return state.FilesystemAttachmentInfo{}, jujuerrors.NotProvisionedf("filesystem attachment")
+	}
+	return *a.info, nil
+}
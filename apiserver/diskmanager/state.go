@@ -3,10 +3,28 @@
 
 package diskmanager
 
-import "github.com/juju/juju/state"
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
 
 type stateInterface interface {
 	SetMachineBlockDevices(machineId string, devices []state.BlockDeviceInfo) error
+	MachineFilesystemAttachments(names.MachineTag) ([]state.FilesystemAttachment, error)
+	SetFilesystemAttachmentUsage(names.MachineTag, names.FilesystemTag, state.FilesystemUsage) error
+	EnvironConfig() (*config.Config, error)
+	FilesystemStorage(names.FilesystemTag) (names.StorageTag, error)
+	StorageUnits(names.StorageTag) ([]Unit, error)
+}
+
+// Unit is the subset of *state.Unit used for reporting
+// storage usage in the units' workload status.
+type Unit interface {
+	Status() (state.StatusInfo, error)
+	SetStatus(state.Status, string, map[string]interface{}) error
 }
 
 type stateShim struct {
@@ -20,3 +38,32 @@ func (s stateShim) SetMachineBlockDevices(machineId string, devices []state.Bloc
 	}
 	return m.SetMachineBlockDevices(devices...)
 }
+
+// FilesystemStorage returns the tag of the storage instance assigned
+// to the specified filesystem. If the filesystem is not assigned to a
+// storage instance, an error satisfying errors.IsNotAssigned is returned.
+func (s stateShim) FilesystemStorage(tag names.FilesystemTag) (names.StorageTag, error) {
+	filesystem, err := s.State.Filesystem(tag)
+	if err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	return filesystem.Storage()
+}
+
+// StorageUnits returns the units to which the specified
+// storage instance is attached.
+func (s stateShim) StorageUnits(tag names.StorageTag) ([]Unit, error) {
+	attachments, err := s.State.StorageAttachments(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units := make([]Unit, len(attachments))
+	for i, att := range attachments {
+		u, err := s.State.Unit(att.Unit().Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		units[i] = u
+	}
+	return units, nil
+}
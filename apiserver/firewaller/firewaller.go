@@ -17,6 +17,8 @@ import (
 func init() {
 	// Version 0 is no longer supported.
 	common.RegisterStandardFacade("Firewaller", 1, NewFirewallerAPI)
+	// Version 2 adds GetExposedSourceCIDRs.
+	common.RegisterStandardFacade("Firewaller", 2, NewFirewallerAPI)
 }
 
 // FirewallerAPI provides access to the Firewaller API facade.
@@ -263,6 +265,32 @@ func (f *FirewallerAPI) GetExposed(args params.Entities) (params.BoolResults, er
 	return result, nil
 }
 
+// GetExposedSourceCIDRs returns the CIDRs from which each given
+// service may be accessed when exposed. An empty result means the
+// service may be accessed from any address.
+func (f *FirewallerAPI) GetExposedSourceCIDRs(args params.Entities) (params.StringsResults, error) {
+	result := params.StringsResults{
+		Results: make([]params.StringsResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessService()
+	if err != nil {
+		return params.StringsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := f.getService(canAccess, tag)
+		if err == nil {
+			result.Results[i].Result = service.ExposedSourceCIDRs()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // GetAssignedMachine returns the assigned machine tag (if any) for
 // each given unit.
 func (f *FirewallerAPI) GetAssignedMachine(args params.Entities) (params.StringResults, error) {
@@ -292,6 +320,36 @@ func (f *FirewallerAPI) GetAssignedMachine(args params.Entities) (params.StringR
 	return result, nil
 }
 
+// SetFirewallStatus records, for each given machine or environment,
+// the ingress rules that the firewaller expected to be open and those
+// that the provider reported as open.
+func (f *FirewallerAPI) SetFirewallStatus(args params.FirewallStatusArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := common.AuthEither(f.accessMachine, f.accessEnviron)()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		tag, err := names.ParseTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		_, err = f.getEntity(canAccess, tag)
+		if err == nil {
+			err = f.st.SetFirewallStatus(
+				tag,
+				params.NetworkIngressRules(arg.Expected),
+				params.NetworkIngressRules(arg.Actual),
+			)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 func (f *FirewallerAPI) getEntity(canAccess common.AuthFunc, tag names.Tag) (state.Entity, error) {
 	if !canAccess(tag) {
 		return nil, common.ErrPerm
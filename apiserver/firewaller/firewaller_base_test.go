@@ -13,6 +13,7 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 )
@@ -365,6 +366,93 @@ func (s *firewallerBaseSuite) testGetExposed(
 	})
 }
 
+func (s *firewallerBaseSuite) testGetExposedSourceCIDRs(
+	c *gc.C,
+	facade interface {
+		GetExposedSourceCIDRs(args params.Entities) (params.StringsResults, error)
+	},
+) {
+	err := s.service.SetExposedTo([]string{"10.0.0.0/8"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := addFakeEntities(params.Entities{Entities: []params.Entity{
+		{Tag: s.service.Tag().String()},
+	}})
+	result, err := facade.GetExposedSourceCIDRs(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Result: []string{"10.0.0.0/8"}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`service "bar"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Exposing the service to any address clears the CIDRs.
+	err = s.service.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args = params.Entities{Entities: []params.Entity{
+		{Tag: s.service.Tag().String()},
+	}}
+	result, err = facade.GetExposedSourceCIDRs(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{{}},
+	})
+}
+
+func (s *firewallerBaseSuite) testSetFirewallStatus(
+	c *gc.C,
+	facade interface {
+		SetFirewallStatus(args params.FirewallStatusArgs) (params.ErrorResults, error)
+	},
+) {
+	http := network.IngressRule{network.MustParsePortRange("80/tcp"), network.AnySourceCIDR}
+	ssh := network.IngressRule{network.MustParsePortRange("22/tcp"), network.AnySourceCIDR}
+	args := params.FirewallStatusArgs{Args: []params.FirewallStatusArg{{
+		Tag:      s.machines[0].Tag().String(),
+		Expected: params.FromNetworkIngressRules([]network.IngressRule{http}),
+		Actual:   params.FromNetworkIngressRules([]network.IngressRule{ssh}),
+	}, {
+		Tag: s.State.EnvironTag().String(),
+	}, {
+		Tag: "machine-42",
+	}, {
+		Tag: s.units[0].Tag().String(),
+	}, {
+		Tag: s.service.Tag().String(),
+	}, {
+		Tag: "environment-deadbeef-0bad-400d-8000-4b1d0d06f00d",
+	}, {
+		Tag: "invalid-tag",
+	}}}
+	result, err := facade.SetFirewallStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{},
+			{Error: apiservertesting.NotFoundError("machine 42")},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`environment "deadbeef-0bad-400d-8000-4b1d0d06f00d"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	status, err := s.State.FirewallStatus(s.machines[0].Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Unexpected(), jc.DeepEquals, []network.IngressRule{ssh})
+	c.Assert(status.Missing(), jc.DeepEquals, []network.IngressRule{http})
+	_, err = s.State.FirewallStatus(s.State.EnvironTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *firewallerBaseSuite) testGetAssignedMachine(
 	c *gc.C,
 	facade interface {
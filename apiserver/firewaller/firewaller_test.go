@@ -75,6 +75,14 @@ func (s *firewallerSuite) TestGetExposed(c *gc.C) {
 	s.testGetExposed(c, s.firewaller)
 }
 
+func (s *firewallerSuite) TestGetExposedSourceCIDRs(c *gc.C) {
+	s.testGetExposedSourceCIDRs(c, s.firewaller)
+}
+
+func (s *firewallerSuite) TestSetFirewallStatus(c *gc.C) {
+	s.testSetFirewallStatus(c, s.firewaller)
+}
+
 func (s *firewallerSuite) TestOpenedPortsNotImplemented(c *gc.C) {
 	apiservertesting.AssertNotImplemented(c, s.firewaller, "OpenedPorts")
 }
@@ -5,6 +5,7 @@ package machinemanager_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -135,6 +136,10 @@ func (st *mockState) GetBlockForType(t state.BlockType) (state.Block, bool, erro
 	return &mockBlock{}, false, nil
 }
 
+func (st *mockState) GetBlockForEntity(t state.BlockType, entity names.Tag) (state.Block, bool, error) {
+	return &mockBlock{}, false, nil
+}
+
 func (st *mockState) EnvironConfig() (*config.Config, error) {
 	panic("not implemented")
 }
@@ -168,3 +173,11 @@ func (st *mockBlock) Type() state.BlockType {
 func (st *mockBlock) Message() string {
 	return "not allowed"
 }
+
+func (st *mockBlock) Expiry() time.Time {
+	return time.Time{}
+}
+
+func (st *mockBlock) CreatedBy() string {
+	return ""
+}
@@ -3,6 +3,8 @@
 
 package params
 
+import "time"
+
 // Block describes a Juju block that protects environment from
 // corruption.
 type Block struct {
@@ -19,6 +21,14 @@ type Block struct {
 	// Message is a descriptive or an explanatory message
 	// that the block was created with.
 	Message string `json:"message,omitempty"`
+
+	// Expiry, if set, is the time after which the block
+	// no longer applies.
+	Expiry *time.Time `json:"expiry,omitempty"`
+
+	// CreatedBy is the name of the user who switched the
+	// block on, if known.
+	CreatedBy string `json:"created-by,omitempty"`
 }
 
 // BlockSwitchParams holds the parameters for switching
@@ -31,6 +41,15 @@ type BlockSwitchParams struct {
 	// Message is a descriptive or an explanatory message
 	// that accompanies the switch.
 	Message string `json:"message,omitempty"`
+
+	// Entity, if set, is the tag of the service or machine
+	// that the block applies to, rather than the whole
+	// environment.
+	Entity string `json:"entity,omitempty"`
+
+	// Expiry, if set, is the time after which a block being
+	// switched on no longer applies.
+	Expiry *time.Time `json:"expiry,omitempty"`
 }
 
 // BlockResult holds the result of an API call to retrieve details
@@ -351,6 +351,8 @@ const (
 	KindCombined HistoryKind = "combined"
 	KindAgent    HistoryKind = "agent"
 	KindWorkload HistoryKind = "workload"
+	KindInstance HistoryKind = "instance"
+	KindService  HistoryKind = "service"
 )
 
 // StatusHistory holds the parameters to filter a status history query.
@@ -360,6 +362,228 @@ type StatusHistory struct {
 	Name string
 }
 
+// EntityStatusHistory holds the parameters to filter a status history
+// query for a unit, machine or service.
+type EntityStatusHistory struct {
+	Kind HistoryKind
+	Size int
+	Tag  string
+}
+
+// EnvironmentEventsArgs holds the parameters to filter an environment
+// event timeline query.
+type EnvironmentEventsArgs struct {
+	// EntityPrefixes restricts the events to those for the
+	// entities named by the prefixes, and the entities beneath
+	// them: a machine's containers, or a service's units when
+	// given as "unit-<service>".
+	EntityPrefixes []string
+	From           *time.Time
+	To             *time.Time
+	Limit          int
+}
+
+// EnvironmentEvent describes something that happened to an entity
+// in the environment.
+type EnvironmentEvent struct {
+	Time    time.Time
+	Kind    string
+	Entity  string
+	Status  string
+	Message string
+}
+
+// EnvironmentEventsResults holds the result of an environment event
+// timeline query, ordered from oldest to newest.
+type EnvironmentEventsResults struct {
+	Events []EnvironmentEvent
+}
+
+// WebhookParams holds the parameters for registering a webhook.
+type WebhookParams struct {
+	URL           string
+	EntityPattern string
+	Statuses      []Status
+}
+
+// Webhook describes a webhook registered in the environment.
+type Webhook struct {
+	Id            string
+	URL           string
+	EntityPattern string
+	Statuses      []Status
+	Created       time.Time
+}
+
+// WebhooksResults holds the webhooks registered in the environment.
+type WebhooksResults struct {
+	Webhooks []Webhook
+}
+
+// WebhookId identifies a webhook.
+type WebhookId struct {
+	Id string
+}
+
+// WebhookDeliveriesArgs holds the parameters for retrieving the
+// delivery log of a webhook.
+type WebhookDeliveriesArgs struct {
+	Id   string
+	Size int
+}
+
+// WebhookDelivery records an attempt to notify a webhook of a status
+// transition.
+type WebhookDelivery struct {
+	Entity       string
+	Status       Status
+	Time         time.Time
+	Attempts     int
+	ResponseCode int
+	Error        string
+}
+
+// WebhookDeliveriesResults holds the delivery log of a webhook,
+// newest first.
+type WebhookDeliveriesResults struct {
+	Deliveries []WebhookDelivery
+}
+
+// AutoscalePolicy holds the policy by which the number of units of a
+// service is adjusted according to a metric reported by its units.
+type AutoscalePolicy struct {
+	Metric    string
+	Threshold float64
+	Window    time.Duration
+	MinUnits  int
+	MaxUnits  int
+	Cooldown  time.Duration
+}
+
+// SetAutoscalePolicy holds the parameters for setting the autoscale
+// policy of a service.
+type SetAutoscalePolicy struct {
+	ServiceName string
+	Policy      AutoscalePolicy
+}
+
+// ClearAutoscalePolicy holds the parameters for removing the
+// autoscale policy of a service.
+type ClearAutoscalePolicy struct {
+	ServiceName string
+}
+
+// AutoscaleArgs holds the parameters for retrieving the autoscale
+// policy and history of a service.
+type AutoscaleArgs struct {
+	ServiceName string
+	HistorySize int
+}
+
+// AutoscaleDecision records a change to the number of units of a
+// service made by its autoscale policy.
+type AutoscaleDecision struct {
+	Time      time.Time
+	Metric    string
+	Value     float64
+	FromUnits int
+	ToUnits   int
+	Reason    string
+}
+
+// AutoscaleResult holds the autoscale policy of a service, which is
+// nil if the service is not autoscaled, and its most recent scaling
+// decisions, newest first.
+type AutoscaleResult struct {
+	Policy  *AutoscalePolicy
+	History []AutoscaleDecision
+}
+
+// PlacementPolicy holds the policy restricting the number of units of
+// a service and the machines they may be assigned to.
+type PlacementPolicy struct {
+	MaxUnits        int
+	AntiAffinity    string
+	AffinityService string
+}
+
+// SetPlacementPolicy holds the parameters for setting the placement
+// policy of a service.
+type SetPlacementPolicy struct {
+	ServiceName string
+	Policy      PlacementPolicy
+}
+
+// ClearPlacementPolicy holds the parameters for removing the placement
+// policy of a service.
+type ClearPlacementPolicy struct {
+	ServiceName string
+}
+
+// PlacementPolicyArgs holds the parameters for retrieving the
+// placement policy of a service.
+type PlacementPolicyArgs struct {
+	ServiceName string
+}
+
+// PlacementPolicyResult holds the placement policy of a service, which
+// is nil if the service has none.
+type PlacementPolicyResult struct {
+	Policy *PlacementPolicy
+}
+
+// SetServiceStateless holds the parameters for marking a service as
+// stateless, or not.
+type SetServiceStateless struct {
+	ServiceName string
+	Stateless   bool
+}
+
+// SelfHealEvent records the replacement of a failed machine.
+type SelfHealEvent struct {
+	Time          time.Time
+	MachineId     string
+	ReplacementId string
+	Units         []string
+	Reason        string
+}
+
+// SelfHealHistoryArgs holds the parameters for retrieving the most
+// recent machine replacements.
+type SelfHealHistoryArgs struct {
+	Size int
+}
+
+// SelfHealHistoryResult holds the most recent machine replacements,
+// newest first.
+type SelfHealHistoryResult struct {
+	Events []SelfHealEvent
+}
+
+// PlannedMachine describes a machine that a change would provision.
+type PlannedMachine struct {
+	Unit        string
+	Series      string
+	Constraints constraints.Value
+	Placement   string
+}
+
+// ChangePlan describes the changes a mutating Client call would make,
+// as reported by its Plan counterpart, which checks that the call
+// would succeed without making it.
+type ChangePlan struct {
+	// Changes describes each change, in the order it would be made.
+	Changes []string
+	// Machines holds the machines that would be provisioned.
+	Machines []PlannedMachine
+	// Units holds the names of the units that would be added or
+	// affected.
+	Units []string
+	// Warnings holds anything that would not prevent the change,
+	// but may not be what was intended.
+	Warnings []string
+}
+
 // StatusResult holds an entity status, extra information, or an
 // error.
 type StatusResult struct {
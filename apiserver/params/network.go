@@ -4,6 +4,8 @@
 package params
 
 import (
+	"time"
+
 	"github.com/juju/juju/network"
 )
 
@@ -177,6 +179,45 @@ func (pr PortRange) NetworkPortRange() network.PortRange {
 	}
 }
 
+// IngressRule represents a range of ports that may be accessed
+// from addresses in a source CIDR.
+type IngressRule struct {
+	PortRange  PortRange `json:"PortRange"`
+	SourceCIDR string    `json:"SourceCIDR"`
+}
+
+// FromNetworkIngressRules is a convenience helper to create
+// parameters out of the network type, here for IngressRule.
+func FromNetworkIngressRules(rules []network.IngressRule) []IngressRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := make([]IngressRule, len(rules))
+	for i, rule := range rules {
+		result[i] = IngressRule{
+			PortRange:  FromNetworkPortRange(rule.PortRange),
+			SourceCIDR: rule.SourceCIDR,
+		}
+	}
+	return result
+}
+
+// NetworkIngressRules is a convenience helper to return the
+// parameters as network type, here for IngressRule.
+func NetworkIngressRules(rules []IngressRule) []network.IngressRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := make([]network.IngressRule, len(rules))
+	for i, rule := range rules {
+		result[i] = network.IngressRule{
+			PortRange:  rule.PortRange.NetworkPortRange(),
+			SourceCIDR: rule.SourceCIDR,
+		}
+	}
+	return result
+}
+
 // EntityPort holds an entity's tag, a protocol and a port.
 type EntityPort struct {
 	Tag      string `json:"Tag"`
@@ -400,6 +441,44 @@ type MachinePortsResults struct {
 	Results []MachinePortsResult `json:"Results"`
 }
 
+// FirewallStatusArg holds the ingress rules that the firewaller
+// expected to be open for a machine or environment, and those the
+// provider reported as open.
+type FirewallStatusArg struct {
+	Tag      string        `json:"Tag"`
+	Expected []IngressRule `json:"Expected"`
+	Actual   []IngressRule `json:"Actual"`
+}
+
+// FirewallStatusArgs holds the arguments of the
+// FirewallerAPIV1.SetFirewallStatus() API call.
+type FirewallStatusArgs struct {
+	Args []FirewallStatusArg `json:"Args"`
+}
+
+// FirewallStatus holds the most recent comparison of the ingress rules
+// expected and actually open for a machine or environment.
+type FirewallStatus struct {
+	Tag        string        `json:"Tag"`
+	Checked    time.Time     `json:"Checked"`
+	Expected   []IngressRule `json:"Expected"`
+	Actual     []IngressRule `json:"Actual"`
+	Unexpected []IngressRule `json:"Unexpected"`
+	Missing    []IngressRule `json:"Missing"`
+
+	// DriftDetected, if not nil, holds when drift was most recently
+	// detected, and DriftUnexpected and DriftMissing what was found.
+	DriftDetected   *time.Time    `json:"DriftDetected,omitempty"`
+	DriftUnexpected []IngressRule `json:"DriftUnexpected,omitempty"`
+	DriftMissing    []IngressRule `json:"DriftMissing,omitempty"`
+}
+
+// FirewallStatusResults holds the result of the
+// Client.FirewallStatus() API call.
+type FirewallStatusResults struct {
+	Results []FirewallStatus `json:"Results"`
+}
+
 // APIHostPortsResult holds the result of an APIHostPorts
 // call. Each element in the top level slice holds
 // the addresses for one API server.
@@ -202,6 +202,12 @@ type ServiceDeploy struct {
 	ToMachineSpec string
 	Networks      []string
 	Storage       map[string]storage.Constraints
+
+	// CharmMeta and CharmConfig describe a charm that has not been
+	// added to the environment. They are used only when planning the
+	// deployment, which is then checked against them.
+	CharmMeta   *charm.Meta   `json:",omitempty"`
+	CharmConfig *charm.Config `json:",omitempty"`
 }
 
 // ServiceUpdate holds the parameters for making the ServiceUpdate call.
@@ -225,6 +231,10 @@ type ServiceSetCharm struct {
 // ServiceExpose holds the parameters for making the ServiceExpose call.
 type ServiceExpose struct {
 	ServiceName string
+
+	// SourceCIDRs, if specified, restricts access to the service's
+	// open ports to addresses within the CIDRs.
+	SourceCIDRs []string `json:",omitempty"`
 }
 
 // ServiceSet holds the parameters for a ServiceSet
@@ -290,6 +300,19 @@ type ServiceMetricCredentials struct {
 	Creds []ServiceMetricCredential
 }
 
+// ServiceStorageDefaults holds parameters for the SetStorageDefaults call.
+// Empty pool names defer to the environment's storage pool defaults.
+type ServiceStorageDefaults struct {
+	ServiceName    string
+	BlockPool      string
+	FilesystemPool string
+}
+
+// ServicesStorageDefaults holds multiple ServiceStorageDefaults parameters.
+type ServicesStorageDefaults struct {
+	Defaults []ServiceStorageDefaults
+}
+
 // PublicAddress holds parameters for the PublicAddress call.
 type PublicAddress struct {
 	Target string
@@ -334,6 +357,10 @@ type AddServiceUnits struct {
 	ServiceName   string
 	NumUnits      int
 	ToMachineSpec string
+	// AttachStorage holds the tags of existing, detached storage
+	// instances to attach to the new unit. If non-empty, NumUnits
+	// must be 1 and ToMachineSpec must be empty.
+	AttachStorage []string `json:",omitempty"`
 }
 
 // DestroyServiceUnits holds parameters for the DestroyUnits call.
@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"gopkg.in/juju/charm.v5"
+)
+
+// ServiceOffer holds the parameters for offering a service's
+// endpoints to other environments hosted by the same state server.
+type ServiceOffer struct {
+	ServiceName string
+
+	// Endpoints holds the names of the offered endpoints. If empty,
+	// all of the service's provider and requirer endpoints are offered.
+	Endpoints []string `json:",omitempty"`
+
+	// OfferName is the name by which the offer is consumed. If empty,
+	// the service name is used.
+	OfferName string `json:",omitempty"`
+}
+
+// ServiceConsume holds the parameters for consuming a service
+// offered by another environment.
+type ServiceConsume struct {
+	// SourceEnvironment holds the name or UUID of the environment
+	// making the offer.
+	SourceEnvironment string
+
+	// OfferName is the name of the offer.
+	OfferName string
+
+	// ServiceName is the name by which the remote service is known
+	// in the consuming environment. If empty, the offer name is used.
+	ServiceName string `json:",omitempty"`
+}
+
+// RemoteEndpoint describes a relation endpoint of a service that
+// takes part in a relation between environments.
+type RemoteEndpoint struct {
+	Name      string
+	Role      charm.RelationRole
+	Interface string
+	Limit     int
+	Scope     charm.RelationScope
+}
+
+// RemoteRelationUnit holds the name of a unit in scope in a relation
+// between environments, along with its relation settings.
+type RemoteRelationUnit struct {
+	Unit     string
+	Settings Settings
+}
+
+// RemoteRelation describes a relation between a local service and a
+// service consumed from another environment, along with the local
+// units in scope.
+type RemoteRelation struct {
+	Key            string
+	Life           Life
+	SourceEnvUUID  string
+	OfferName      string
+	RemoteService  string
+	RemoteEndpoint string
+	LocalService   string
+	LocalEndpoint  RemoteEndpoint
+	Units          []RemoteRelationUnit
+}
+
+// RemoteRelationsResults holds the relations between local services
+// and services consumed from other environments.
+type RemoteRelationsResults struct {
+	Relations []RemoteRelation
+}
+
+// PublishRelationArgs holds the relations to publish to the
+// environments offering their remote services.
+type PublishRelationArgs struct {
+	Relations []RemoteRelation
+}
+
+// RemoteRelationUnitsResult holds the units in scope in a relation
+// between environments, or an error.
+type RemoteRelationUnitsResult struct {
+	Error *Error
+	Units []RemoteRelationUnit
+}
+
+// RemoteRelationUnitsResults holds the results of an API call
+// returning the units in scope in multiple relations.
+type RemoteRelationUnitsResults struct {
+	Results []RemoteRelationUnitsResult
+}
+
+// RemoteRelationUnits holds the units of a remote service to place
+// in scope in the relation with the given key.
+type RemoteRelationUnits struct {
+	Key   string
+	Units []RemoteRelationUnit
+}
+
+// RemoteRelationUnitsArgs holds the arguments for setting the remote
+// units in scope in multiple relations.
+type RemoteRelationUnitsArgs struct {
+	Args []RemoteRelationUnits
+}
@@ -94,6 +94,10 @@ type StorageAttachment struct {
 	Kind     StorageKind
 	Location string
 	Life     Life
+
+	// Size is the size of a block-kind storage attachment's
+	// volume, in MiB.
+	Size uint64
 }
 
 // StorageAttachmentId identifies a storage attachment by the tags of the
@@ -183,6 +187,7 @@ type VolumeParams struct {
 	Size       uint64                  `json:"size"`
 	Provider   string                  `json:"provider"`
 	Attributes map[string]interface{}  `json:"attributes,omitempty"`
+	SnapshotId string                  `json:"snapshotid,omitempty"`
 	Attachment *VolumeAttachmentParams `json:"attachment,omitempty"`
 }
 
@@ -242,6 +247,128 @@ type VolumeParamsResults struct {
 	Results []VolumeParamsResult `json:"results,omitempty"`
 }
 
+// VolumeResizeParams holds the parameters for resizing a storage volume.
+type VolumeResizeParams struct {
+	VolumeTag  string                 `json:"volumetag"`
+	VolumeId   string                 `json:"volumeid"`
+	Size       uint64                 `json:"size"`
+	Provider   string                 `json:"provider"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// VolumeResizeParamsResult holds resize parameters for a volume.
+type VolumeResizeParamsResult struct {
+	Result VolumeResizeParams `json:"result"`
+	Error  *Error             `json:"error,omitempty"`
+}
+
+// VolumeResizeParamsResults holds resize parameters for multiple volumes.
+type VolumeResizeParamsResults struct {
+	Results []VolumeResizeParamsResult `json:"results,omitempty"`
+}
+
+// StorageResize identifies a storage instance, and the size in MiB
+// that it should be grown to.
+type StorageResize struct {
+	StorageTag string `json:"storagetag"`
+	Size       uint64 `json:"size"`
+}
+
+// StorageResizeArgs holds the arguments for resizing storage instances.
+type StorageResizeArgs struct {
+	Storage []StorageResize `json:"storage"`
+}
+
+// VolumeResizeFailure identifies a volume that could not be resized,
+// and the reason why.
+type VolumeResizeFailure struct {
+	VolumeTag string `json:"volumetag"`
+	Reason    string `json:"reason"`
+}
+
+// VolumeResizeFailures holds the failures of multiple volume resizes.
+type VolumeResizeFailures struct {
+	Failures []VolumeResizeFailure `json:"failures"`
+}
+
+// SnapshotIds holds the IDs of volume snapshots.
+type SnapshotIds struct {
+	Ids []string `json:"ids"`
+}
+
+// Snapshot describes a volume snapshot that has been taken.
+type Snapshot struct {
+	Id         string `json:"id"`
+	VolumeTag  string `json:"volumetag"`
+	SnapshotId string `json:"snapshotid"`
+}
+
+// Snapshots describes a set of volume snapshots.
+type Snapshots struct {
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+// SnapshotFailure identifies a volume snapshot that could not be
+// taken or destroyed, and the reason why.
+type SnapshotFailure struct {
+	Id     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// SnapshotFailures holds the failures of multiple volume snapshots.
+type SnapshotFailures struct {
+	Failures []SnapshotFailure `json:"failures"`
+}
+
+// SnapshotParams holds the parameters for taking, or destroying,
+// a volume snapshot.
+type SnapshotParams struct {
+	Id         string                 `json:"id"`
+	Life       Life                   `json:"life"`
+	VolumeTag  string                 `json:"volumetag"`
+	VolumeId   string                 `json:"volumeid"`
+	Size       uint64                 `json:"size"`
+	Provider   string                 `json:"provider"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+
+	// SnapshotId is the provider-supplied ID of the snapshot,
+	// if it has been taken.
+	SnapshotId string `json:"snapshotid,omitempty"`
+
+	// Failure is the reason that the snapshot most recently
+	// failed to be taken or destroyed, if it did.
+	Failure string `json:"failure,omitempty"`
+}
+
+// SnapshotParamsResult holds the parameters for a volume snapshot.
+type SnapshotParamsResult struct {
+	Result SnapshotParams `json:"result"`
+	Error  *Error         `json:"error,omitempty"`
+}
+
+// SnapshotParamsResults holds the parameters for multiple volume
+// snapshots.
+type SnapshotParamsResults struct {
+	Results []SnapshotParamsResult `json:"results,omitempty"`
+}
+
+// SnapshotDetails describes a volume snapshot, for display to users.
+type SnapshotDetails struct {
+	Id         string `json:"id"`
+	VolumeTag  string `json:"volumetag"`
+	StorageTag string `json:"storagetag,omitempty"`
+	Pool       string `json:"pool"`
+	Size       uint64 `json:"size"`
+	Life       Life   `json:"life"`
+	SnapshotId string `json:"snapshotid,omitempty"`
+	Failure    string `json:"failure,omitempty"`
+}
+
+// SnapshotDetailsResults holds the details of volume snapshots.
+type SnapshotDetailsResults struct {
+	Results []SnapshotDetails `json:"results,omitempty"`
+}
+
 // VolumeAttachmentParamsResults holds provisioning parameters for a volume
 // attachment.
 type VolumeAttachmentParamsResult struct {
@@ -348,6 +475,43 @@ type FilesystemAttachmentParamsResults struct {
 	Results []FilesystemAttachmentParamsResult `json:"results,omitempty"`
 }
 
+// FilesystemAttachmentsResult holds the filesystem attachments relating
+// to some entity, or an error.
+type FilesystemAttachmentsResult struct {
+	Result []FilesystemAttachment `json:"result,omitempty"`
+	Error  *Error                 `json:"error,omitempty"`
+}
+
+// FilesystemAttachmentsResults holds the filesystem attachments relating
+// to multiple entities.
+type FilesystemAttachmentsResults struct {
+	Results []FilesystemAttachmentsResult `json:"results,omitempty"`
+}
+
+// StorageUsage describes the space and inode usage of filesystem storage.
+type StorageUsage struct {
+	UsedBytes  uint64 `json:"usedbytes"`
+	FreeBytes  uint64 `json:"freebytes"`
+	UsedInodes uint64 `json:"usedinodes"`
+	FreeInodes uint64 `json:"freeinodes"`
+
+	// ThresholdExceeded records whether the space used exceeded
+	// the environment's storage usage threshold when reported.
+	ThresholdExceeded bool `json:"thresholdexceeded,omitempty"`
+}
+
+// FilesystemUsage holds the usage of a filesystem attached to a machine.
+type FilesystemUsage struct {
+	FilesystemTag string       `json:"filesystemtag"`
+	MachineTag    string       `json:"machinetag"`
+	Usage         StorageUsage `json:"usage"`
+}
+
+// FilesystemUsages holds the usage of multiple filesystem attachments.
+type FilesystemUsages struct {
+	Usages []FilesystemUsage `json:"usages"`
+}
+
 // StorageDetails holds information about storage.
 type StorageDetails struct {
 
@@ -371,6 +535,10 @@ type StorageDetails struct {
 
 	// Persistent indicates whether the storage is persistent or not.
 	Persistent bool `json:"persistent"`
+
+	// Usage holds the most recently reported usage of attached
+	// filesystem storage, if any.
+	Usage *StorageUsage `json:"usage,omitempty"`
 }
 
 // StorageDetailsResult holds information about a storage instance
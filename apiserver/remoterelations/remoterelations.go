@@ -0,0 +1,467 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package remoterelations provides the API used by the remote relations
+// worker to synchronise relations between services in different
+// environments hosted by the same state server.
+package remoterelations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.remoterelations")
+
+func init() {
+	common.RegisterStandardFacade("RemoteRelations", 1, NewRemoteRelationsAPI)
+}
+
+// RemoteRelationsAPI provides access to the RemoteRelations API facade.
+type RemoteRelationsAPI struct {
+	st         *state.State
+	resources  *common.Resources
+	authorizer common.Authorizer
+}
+
+// NewRemoteRelationsAPI creates a new server-side RemoteRelationsAPI facade.
+func NewRemoteRelationsAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*RemoteRelationsAPI, error) {
+	if !authorizer.AuthEnvironManager() {
+		return nil, common.ErrPerm
+	}
+	return &RemoteRelationsAPI{
+		st:         st,
+		resources:  resources,
+		authorizer: authorizer,
+	}, nil
+}
+
+// RemoteRelations returns the relations between local services and
+// services consumed from other environments, along with the local
+// units in scope in each and their settings.
+func (api *RemoteRelationsAPI) RemoteRelations() (params.RemoteRelationsResults, error) {
+	var result params.RemoteRelationsResults
+	remoteServices, err := api.st.AllRemoteServices()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, remote := range remoteServices {
+		if remote.OfferName() == "" {
+			// This is a proxy for a service of an environment consuming
+			// one of ours; its relations are maintained by that
+			// environment's worker.
+			continue
+		}
+		rels, err := remote.Relations()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		for _, rel := range rels {
+			remoteEp, err := rel.Endpoint(remote.Name())
+			if err != nil {
+				return result, errors.Trace(err)
+			}
+			localEps, err := rel.RelatedEndpoints(remote.Name())
+			if err != nil {
+				return result, errors.Trace(err)
+			}
+			localEp := localEps[0]
+			units, err := localUnitsInScope(api.st, rel, localEp.ServiceName, "")
+			if err != nil {
+				return result, errors.Trace(err)
+			}
+			result.Relations = append(result.Relations, params.RemoteRelation{
+				Key:            rel.String(),
+				Life:           params.Life(rel.Life().String()),
+				SourceEnvUUID:  remote.SourceEnvironTag().Id(),
+				OfferName:      remote.OfferName(),
+				RemoteService:  remote.Name(),
+				RemoteEndpoint: remoteEp.Name,
+				LocalService:   localEp.ServiceName,
+				LocalEndpoint: params.RemoteEndpoint{
+					Name:      localEp.Name,
+					Role:      localEp.Role,
+					Interface: localEp.Interface,
+					Limit:     localEp.Limit,
+					Scope:     localEp.Scope,
+				},
+				Units: units,
+			})
+		}
+	}
+	return result, nil
+}
+
+// PublishRelations publishes each relation, and the local units in scope
+// in it, to the environment offering its remote service. There, a proxy
+// remote service standing in for the local service is related to the
+// offered service, and the local units are placed in scope as its units.
+// The units of the offered service in scope in that relation are
+// returned, named as units of the remote service.
+//
+// The relations must include all of the environment's relations with
+// remote services: any relation of a proxy in another environment that
+// is not published is destroyed there, as its counterpart has been
+// removed from this environment.
+func (api *RemoteRelationsAPI) PublishRelations(args params.PublishRelationArgs) (params.RemoteRelationUnitsResults, error) {
+	result := params.RemoteRelationUnitsResults{
+		Results: make([]params.RemoteRelationUnitsResult, len(args.Relations)),
+	}
+	sources := make(map[string]*state.State)
+	defer func() {
+		for _, st := range sources {
+			st.Close()
+		}
+	}()
+	// published holds the keys, in each source environment, of the
+	// relations published there. An environment to which any relation
+	// could not be published is not reconciled.
+	published := make(map[string]set.Strings)
+	failed := set.NewStrings()
+	for i, arg := range args.Relations {
+		source, ok := sources[arg.SourceEnvUUID]
+		if !ok {
+			var err error
+			source, err = api.sourceState(arg.SourceEnvUUID)
+			if err != nil {
+				failed.Add(arg.SourceEnvUUID)
+				result.Results[i].Error = common.ServerError(err)
+				continue
+			}
+			sources[arg.SourceEnvUUID] = source
+			published[arg.SourceEnvUUID] = set.NewStrings()
+		}
+		units, err := api.publishRelation(source, arg, published[arg.SourceEnvUUID])
+		if err != nil {
+			failed.Add(arg.SourceEnvUUID)
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Units = units
+	}
+
+	envUUIDs, err := api.st.ProxyEnvironUUIDs()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, envUUID := range envUUIDs {
+		if failed.Contains(envUUID) {
+			continue
+		}
+		source, ok := sources[envUUID]
+		if !ok {
+			source, err = api.sourceState(envUUID)
+			if err != nil {
+				logger.Errorf("cannot remove unpublished relations from environment %s: %v", envUUID, err)
+				continue
+			}
+			sources[envUUID] = source
+		}
+		if err := api.removeUnpublishedRelations(source, published[envUUID]); err != nil {
+			logger.Errorf("cannot remove unpublished relations from environment %s: %v", envUUID, err)
+		}
+	}
+	return result, nil
+}
+
+// removeUnpublishedRelations destroys the relations of the proxies
+// standing in, in the source environment, for this environment's
+// services, unless they were published; and destroys each proxy once
+// it has no relations left. A relation is not published once it has
+// been removed from this environment, whether because it was destroyed
+// with no units in scope, or because the remote service it related to
+// was removed.
+func (api *RemoteRelationsAPI) removeUnpublishedRelations(source *state.State, published set.Strings) error {
+	remoteServices, err := source.AllRemoteServices()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, proxy := range remoteServices {
+		if proxy.OfferName() != "" || proxy.SourceEnvironTag().Id() != api.st.EnvironUUID() {
+			continue
+		}
+		rels, err := proxy.Relations()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, rel := range rels {
+			if published.Contains(rel.String()) {
+				continue
+			}
+			logger.Infof("destroying unpublished relation %q in environment %s", rel, source.EnvironUUID())
+			if err := rel.Destroy(); err != nil {
+				return errors.Trace(err)
+			}
+			if err := rel.Refresh(); errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			if err := syncRemoteUnits(rel, proxy.Name(), nil); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if err := destroyIfUnrelated(proxy); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// sourceState returns a State for the environment with the given UUID.
+func (api *RemoteRelationsAPI) sourceState(envUUID string) (*state.State, error) {
+	if !names.IsValidEnvironment(envUUID) {
+		return nil, errors.NotValidf("environment UUID %q", envUUID)
+	}
+	st, err := api.st.ForEnviron(names.NewEnvironTag(envUUID))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := st.Environment(); err != nil {
+		st.Close()
+		return nil, errors.Trace(err)
+	}
+	return st, nil
+}
+
+// publishRelation publishes the relation to the source environment,
+// adding the key of its counterpart there to published.
+func (api *RemoteRelationsAPI) publishRelation(source *state.State, arg params.RemoteRelation, published set.Strings) ([]params.RemoteRelationUnit, error) {
+	offer, err := source.ServiceOffer(arg.OfferName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	offerEp, err := offer.Endpoint(arg.RemoteEndpoint)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	proxyName := proxyServiceName(arg.LocalService, api.st.EnvironUUID())
+	proxy, err := source.RemoteService(proxyName)
+	if errors.IsNotFound(err) {
+		if arg.Life != params.Alive {
+			return nil, nil
+		}
+		proxy, err = source.AddRemoteService(state.AddRemoteServiceParams{
+			Name:          proxyName,
+			SourceEnvUUID: api.st.EnvironUUID(),
+			Endpoints: []charm.Relation{{
+				Name:      arg.LocalEndpoint.Name,
+				Role:      arg.LocalEndpoint.Role,
+				Interface: arg.LocalEndpoint.Interface,
+				Limit:     arg.LocalEndpoint.Limit,
+				Scope:     arg.LocalEndpoint.Scope,
+			}},
+		})
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	proxyEp, err := proxy.Endpoint(arg.LocalEndpoint.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rel, err := source.EndpointsRelation(offerEp, proxyEp)
+	if errors.IsNotFound(err) {
+		if arg.Life != params.Alive {
+			return nil, nil
+		}
+		logger.Infof("relating %q to %q in environment %s", proxyEp, offerEp, source.EnvironUUID())
+		rel, err = source.AddRelation(offerEp, proxyEp)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	published.Add(rel.String())
+	units := make([]params.RemoteRelationUnit, len(arg.Units))
+	for i, unit := range arg.Units {
+		units[i] = params.RemoteRelationUnit{
+			Unit:     translateUnitName(unit.Unit, proxyName),
+			Settings: unit.Settings,
+		}
+	}
+	if arg.Life != params.Alive {
+		// The relation is being destroyed in the consuming environment;
+		// its counterpart here goes with it, and so does the proxy once
+		// it has no relations left.
+		units = nil
+		if err := rel.Destroy(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if err := syncRemoteUnits(rel, proxyName, units); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if arg.Life != params.Alive {
+		if err := destroyIfUnrelated(proxy); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return nil, nil
+	}
+	return localUnitsInScope(source, rel, offer.ServiceName(), arg.RemoteService)
+}
+
+// destroyIfUnrelated destroys the remote service if it takes part in
+// no relations other than those being destroyed.
+func destroyIfUnrelated(remote *state.RemoteService) error {
+	rels, err := remote.Relations()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, rel := range rels {
+		if rel.Life() == state.Alive {
+			return nil
+		}
+	}
+	return remote.Destroy()
+}
+
+// SetRemoteUnits places the supplied units of each relation's remote
+// service in scope, with the supplied settings, and removes from scope
+// any others.
+func (api *RemoteRelationsAPI) SetRemoteUnits(args params.RemoteRelationUnitsArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		err := api.setRemoteUnits(arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *RemoteRelationsAPI) setRemoteUnits(arg params.RemoteRelationUnits) error {
+	rel, err := api.st.KeyRelation(arg.Key)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, ep := range rel.Endpoints() {
+		if _, err := api.st.RemoteService(ep.ServiceName); errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		return syncRemoteUnits(rel, ep.ServiceName, arg.Units)
+	}
+	return errors.Errorf("relation %q has no remote service", arg.Key)
+}
+
+// syncRemoteUnits ensures that exactly the supplied units of the named
+// remote service are in scope in the relation, with the supplied
+// settings. No units enter scope if the relation is not alive.
+func syncRemoteUnits(rel *state.Relation, remoteService string, units []params.RemoteRelationUnit) error {
+	inScope, err := rel.UnitsInScope(remoteService)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	wanted := make(map[string]bool)
+	if rel.Life() == state.Alive {
+		for _, unit := range units {
+			if svcName, err := names.UnitService(unit.Unit); err != nil {
+				return errors.Trace(err)
+			} else if svcName != remoteService {
+				return errors.Errorf("unit %q is not a unit of remote service %q", unit.Unit, remoteService)
+			}
+			wanted[unit.Unit] = true
+			ru, err := rel.RemoteUnit(unit.Unit)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			settings := make(map[string]interface{})
+			for k, v := range unit.Settings {
+				settings[k] = v
+			}
+			if ok, err := ru.InScope(); err != nil {
+				return errors.Trace(err)
+			} else if ok {
+				err = ru.ReplaceSettings(settings)
+			} else {
+				err = ru.EnterScope(settings)
+			}
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	for _, unitName := range inScope {
+		if wanted[unitName] {
+			continue
+		}
+		ru, err := rel.RemoteUnit(unitName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := ru.LeaveScope(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// localUnitsInScope returns the units of the named local service in
+// scope in the relation, with their settings. If asService is not
+// empty, the units are named as units of that service instead.
+func localUnitsInScope(st *state.State, rel *state.Relation, serviceName, asService string) ([]params.RemoteRelationUnit, error) {
+	unitNames, err := rel.UnitsInScope(serviceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []params.RemoteRelationUnit
+	for _, unitName := range unitNames {
+		unit, err := st.Unit(unitName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ru, err := rel.Unit(unit)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		node, err := ru.Settings()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		settings := make(params.Settings)
+		for k, v := range node.Map() {
+			// All relation settings should be strings.
+			sval, ok := v.(string)
+			if !ok {
+				return nil, errors.Errorf("unexpected relation setting %q: expected string, got %T", k, v)
+			}
+			settings[k] = sval
+		}
+		if asService != "" {
+			unitName = translateUnitName(unitName, asService)
+		}
+		result = append(result, params.RemoteRelationUnit{
+			Unit:     unitName,
+			Settings: settings,
+		})
+	}
+	return result, nil
+}
+
+// proxyServiceName returns the name of the remote service standing in,
+// in the environment offering a service, for the named service of the
+// consuming environment with the given UUID.
+func proxyServiceName(serviceName, envUUID string) string {
+	return fmt.Sprintf("%s-env%s", serviceName, strings.Replace(envUUID, "-", "", -1)[:8])
+}
+
+// translateUnitName returns the name of the unit with the same number
+// as the named unit, belonging to the named service.
+func translateUnitName(unitName, serviceName string) string {
+	return serviceName + unitName[strings.Index(unitName, "/"):]
+}
@@ -0,0 +1,300 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package remoterelations_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/remoterelations"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type remoteRelationsSuite struct {
+	jujutesting.JujuConnSuite
+
+	// otherState is the environment consuming the mysql service
+	// offered by the initial environment.
+	otherState   *state.State
+	otherFactory *factory.Factory
+	relation     *state.Relation
+	api          *remoterelations.RemoteRelationsAPI
+}
+
+var _ = gc.Suite(&remoteRelationsSuite{})
+
+func (s *remoteRelationsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+
+	s.Factory.MakeService(c, &factory.ServiceParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	_, err := s.State.AddServiceOffer(state.ServiceOfferParams{
+		OfferName:   "db",
+		ServiceName: "mysql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.otherState = s.Factory.MakeEnvironment(c, nil)
+	s.AddCleanup(func(*gc.C) { s.otherState.Close() })
+	s.otherFactory = factory.NewFactory(s.otherState)
+	s.otherFactory.MakeService(c, &factory.ServiceParams{
+		Charm: s.otherFactory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	_, err = s.otherState.AddRemoteService(state.AddRemoteServiceParams{
+		Name:          "database",
+		SourceEnvUUID: s.State.EnvironUUID(),
+		OfferName:     "db",
+		Endpoints: []charm.Relation{{
+			Name:      "server",
+			Role:      charm.RoleProvider,
+			Interface: "mysql",
+			Scope:     charm.ScopeGlobal,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	eps, err := s.otherState.InferEndpoints("wordpress", "database")
+	c.Assert(err, jc.ErrorIsNil)
+	s.relation, err = s.otherState.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resources := common.NewResources()
+	s.AddCleanup(func(*gc.C) { resources.StopAll() })
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	}
+	s.api, err = remoterelations.NewRemoteRelationsAPI(s.otherState, resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *remoteRelationsSuite) TestNewRemoteRelationsAPIRequiresEnvironManager(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("1")}
+	_, err := remoterelations.NewRemoteRelationsAPI(s.otherState, common.NewResources(), authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *remoteRelationsSuite) enterScope(c *gc.C, f *factory.Factory, st *state.State, rel *state.Relation, serviceName string, settings map[string]interface{}) {
+	svc, err := st.Service(serviceName)
+	c.Assert(err, jc.ErrorIsNil)
+	unit := f.MakeUnit(c, &factory.UnitParams{Service: svc})
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(settings)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *remoteRelationsSuite) TestRemoteRelations(c *gc.C) {
+	s.enterScope(c, s.otherFactory, s.otherState, s.relation, "wordpress", map[string]interface{}{"url": "blog"})
+
+	result, err := s.api.RemoteRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Relations, jc.DeepEquals, []params.RemoteRelation{{
+		Key:            "wordpress:db database:server",
+		Life:           params.Alive,
+		SourceEnvUUID:  s.State.EnvironUUID(),
+		OfferName:      "db",
+		RemoteService:  "database",
+		RemoteEndpoint: "server",
+		LocalService:   "wordpress",
+		LocalEndpoint: params.RemoteEndpoint{
+			Name:      "db",
+			Role:      charm.RoleRequirer,
+			Interface: "mysql",
+			Limit:     1,
+			Scope:     charm.ScopeGlobal,
+		},
+		Units: []params.RemoteRelationUnit{{
+			Unit:     "wordpress/0",
+			Settings: params.Settings{"url": "blog"},
+		}},
+	}})
+}
+
+func (s *remoteRelationsSuite) publish(c *gc.C) []params.RemoteRelationUnit {
+	relations, err := s.api.RemoteRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := s.api.PublishRelations(params.PublishRelationArgs{Relations: relations.Relations})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	return result.Results[0].Units
+}
+
+func (s *remoteRelationsSuite) TestPublishRelations(c *gc.C) {
+	s.enterScope(c, s.otherFactory, s.otherState, s.relation, "wordpress", map[string]interface{}{"url": "blog"})
+	units := s.publish(c)
+	c.Assert(units, gc.HasLen, 0)
+
+	// The wordpress service is represented by a proxy in the
+	// offering environment, whose unit is in scope.
+	proxyName := remoterelations.ProxyServiceName("wordpress", s.otherState.EnvironUUID())
+	proxy, err := s.State.RemoteService(proxyName)
+	c.Assert(err, jc.ErrorIsNil)
+	rels, err := proxy.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 1)
+	ru, err := rels[0].RemoteUnit(proxyName + "/0")
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := ru.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{"url": "blog"})
+
+	// Units of the offered service are returned as units of the
+	// consuming environment's remote service.
+	s.enterScope(c, s.Factory, s.State, rels[0], "mysql", map[string]interface{}{"host": "10.0.0.1"})
+	units = s.publish(c)
+	c.Assert(units, jc.DeepEquals, []params.RemoteRelationUnit{{
+		Unit:     "database/0",
+		Settings: params.Settings{"host": "10.0.0.1"},
+	}})
+}
+
+func (s *remoteRelationsSuite) TestPublishRelationsUnknownOffer(c *gc.C) {
+	result, err := s.api.PublishRelations(params.PublishRelationArgs{
+		Relations: []params.RemoteRelation{{
+			SourceEnvUUID: s.State.EnvironUUID(),
+			OfferName:     "foo",
+		}, {
+			SourceEnvUUID: "foo",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `offer "foo" not found`)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `environment UUID "foo" not valid`)
+}
+
+func (s *remoteRelationsSuite) TestPublishDyingRelation(c *gc.C) {
+	s.publish(c)
+	err := s.relation.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The relation has been removed, and so is not published; but
+	// once published as dying, the proxy and its relation go too.
+	relations := []params.RemoteRelation{{
+		Life:           params.Dying,
+		SourceEnvUUID:  s.State.EnvironUUID(),
+		OfferName:      "db",
+		RemoteService:  "database",
+		RemoteEndpoint: "server",
+		LocalService:   "wordpress",
+		LocalEndpoint:  params.RemoteEndpoint{Name: "db"},
+	}}
+	result, err := s.api.PublishRelations(params.PublishRelationArgs{Relations: relations})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	proxyName := remoterelations.ProxyServiceName("wordpress", s.otherState.EnvironUUID())
+	_, err = s.State.RemoteService(proxyName)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+// assertProxyRemoved publishes the consuming environment's remote
+// relations, and checks that the proxy for the wordpress service has
+// been removed from the offering environment.
+func (s *remoteRelationsSuite) assertProxyRemoved(c *gc.C) {
+	relations, err := s.api.RemoteRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(relations.Relations, gc.HasLen, 0)
+	result, err := s.api.PublishRelations(params.PublishRelationArgs{Relations: relations.Relations})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 0)
+
+	proxyName := remoterelations.ProxyServiceName("wordpress", s.otherState.EnvironUUID())
+	_, err = s.State.RemoteService(proxyName)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	rels, err := s.State.AllRelations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 0)
+}
+
+func (s *remoteRelationsSuite) TestPublishRemovedRelation(c *gc.C) {
+	s.publish(c)
+
+	// With no units in scope, the relation is removed at once, and
+	// is never published as dying.
+	err := s.relation.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.relation.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	s.assertProxyRemoved(c)
+}
+
+func (s *remoteRelationsSuite) TestPublishRemovedRemoteService(c *gc.C) {
+	s.publish(c)
+
+	database, err := s.otherState.RemoteService("database")
+	c.Assert(err, jc.ErrorIsNil)
+	err = database.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.otherState.RemoteService("database")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	s.assertProxyRemoved(c)
+}
+
+func (s *remoteRelationsSuite) TestPublishRemovedRelationUnitsInScope(c *gc.C) {
+	s.publish(c)
+	proxyName := remoterelations.ProxyServiceName("wordpress", s.otherState.EnvironUUID())
+	proxy, err := s.State.RemoteService(proxyName)
+	c.Assert(err, jc.ErrorIsNil)
+	rels, err := proxy.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 1)
+	s.enterScope(c, s.Factory, s.State, rels[0], "mysql", nil)
+
+	err = s.relation.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := s.api.PublishRelations(params.PublishRelationArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 0)
+
+	// The counterpart relation is dying until the offered service's
+	// units leave scope, and the proxy with it.
+	err = rels[0].Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels[0].Life(), gc.Equals, state.Dying)
+	err = proxy.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(proxy.Life(), gc.Equals, state.Dying)
+}
+
+func (s *remoteRelationsSuite) TestSetRemoteUnits(c *gc.C) {
+	args := params.RemoteRelationUnitsArgs{Args: []params.RemoteRelationUnits{{
+		Key: s.relation.String(),
+		Units: []params.RemoteRelationUnit{{
+			Unit:     "database/0",
+			Settings: params.Settings{"host": "10.0.0.1"},
+		}},
+	}, {
+		Key: "foo:bar baz:qux",
+	}}}
+	result, err := s.api.SetRemoteUnits(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `relation "foo:bar baz:qux" not found`)
+
+	ru, err := s.relation.RemoteUnit("database/0")
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := ru.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{"host": "10.0.0.1"})
+
+	// Remote units no longer supplied leave scope.
+	args.Args = args.Args[:1]
+	args.Args[0].Units = nil
+	_, err = s.api.SetRemoteUnits(args)
+	c.Assert(err, jc.ErrorIsNil)
+	inScope, err := ru.InScope()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(inScope, jc.IsFalse)
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
 	jjj "github.com/juju/juju/juju"
 	"github.com/juju/juju/state"
 	statestorage "github.com/juju/juju/state/storage"
@@ -34,6 +35,7 @@ func init() {
 // Service defines the methods on the service API end point.
 type Service interface {
 	SetMetricCredentials(args params.ServiceMetricCredentials) (params.ErrorResults, error)
+	SetStorageDefaults(args params.ServicesStorageDefaults) (params.ErrorResults, error)
 }
 
 // API implements the service interface and is the concrete
@@ -83,6 +85,37 @@ func (api *API) SetMetricCredentials(args params.ServiceMetricCredentials) (para
 	return result, nil
 }
 
+// SetStorageDefaults sets the storage pool defaults for services, which
+// are used in place of the environment's defaults when storage constraints
+// do not specify a pool.
+func (api *API) SetStorageDefaults(args params.ServicesStorageDefaults) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Defaults)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, a := range args.Defaults {
+		if err := api.check.ChangeAllowedFor(names.NewServiceTag(a.ServiceName)); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		service, err := api.state.Service(a.ServiceName)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		err = service.SetStorageDefaults(state.StoragePoolDefaults{
+			Block:      a.BlockPool,
+			Filesystem: a.FilesystemPool,
+		})
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
 // ServicesDeploy fetches the charms from the charm store and deploys them.
 func (api *API) ServicesDeploy(args params.ServicesDeploy) (params.ErrorResults, error) {
 	result := params.ErrorResults{
@@ -103,36 +136,67 @@ func (api *API) ServicesDeploy(args params.ServicesDeploy) (params.ErrorResults,
 // The logic has been factored out into a common function which is called by
 // both the legacy API on the client facade, as well as the new service facade.
 func DeployService(st *state.State, owner string, args params.ServiceDeploy) error {
+	deployParams, err := deployServiceParams(st, owner, args, true)
+	if err != nil {
+		return err
+	}
+	_, err = jjj.DeployService(st, deployParams)
+	return err
+}
+
+// PlanDeployService runs the same checks as DeployService without
+// changing the environment, and returns what deploying the service
+// would do. Unlike DeployService, it does not add a charm store charm
+// that is not already in state. If args includes the metadata of a
+// charm that is not in state, the checks are run against it.
+func PlanDeployService(st *state.State, owner string, args params.ServiceDeploy) (*jjj.DeployPlan, error) {
+	deployParams, err := deployServiceParams(st, owner, args, false)
+	if err != nil {
+		return nil, err
+	}
+	return jjj.PlanDeployService(st, deployParams)
+}
+
+// deployServiceParams validates the arguments to DeployService, and
+// converts them to those needed to deploy the service. If addCharm is
+// true, charm store charms not yet in state are added to it.
+func deployServiceParams(st *state.State, owner string, args params.ServiceDeploy, addCharm bool) (jjj.DeployServiceParams, error) {
+	var none jjj.DeployServiceParams
 	curl, err := charm.ParseURL(args.CharmUrl)
 	if err != nil {
-		return errors.Trace(err)
+		return none, errors.Trace(err)
 	}
 	if curl.Revision < 0 {
-		return errors.Errorf("charm url must include revision")
+		return none, errors.Errorf("charm url must include revision")
 	}
 
 	if args.ToMachineSpec != "" && names.IsValidMachine(args.ToMachineSpec) {
 		_, err = st.Machine(args.ToMachineSpec)
 		if err != nil {
-			return errors.Annotatef(err, `cannot deploy "%v" to machine %v`, args.ServiceName, args.ToMachineSpec)
+			return none, errors.Annotatef(err, `cannot deploy "%v" to machine %v`, args.ServiceName, args.ToMachineSpec)
 		}
 	}
 
 	// Try to find the charm URL in state first.
 	ch, err := st.Charm(curl)
-	if errors.IsNotFound(err) {
+	switch {
+	case errors.IsNotFound(err) && addCharm:
 		// Clients written to expect 1.16 compatibility require this next block.
 		if curl.Schema != "cs" {
-			return errors.Errorf(`charm url has unsupported schema %q`, curl.Schema)
+			return none, errors.Errorf(`charm url has unsupported schema %q`, curl.Schema)
 		}
 		if err = AddCharmWithAuthorization(st, params.AddCharmWithAuthorization{
 			URL: args.CharmUrl,
 		}); err == nil {
 			ch, err = st.Charm(curl)
 		}
+	case errors.IsNotFound(err) && args.CharmMeta != nil:
+		// The deployment is planned with a charm the client has
+		// not yet added, which is checked against its metadata.
+		ch, err = st.PlannedCharm(curl, args.CharmMeta, args.CharmConfig), nil
 	}
 	if err != nil {
-		return errors.Trace(err)
+		return none, errors.Trace(err)
 	}
 
 	storageConstraints := args.Storage
@@ -142,7 +206,11 @@ func DeployService(st *state.State, owner string, args params.ServiceDeploy) err
 	// Validate the storage parameters against the charm metadata,
 	// and ensure there are no conflicting parameters.
 	if err := validateCharmStorage(args, ch); err != nil {
-		return err
+		return none, err
+	}
+	envConfig, err := st.EnvironConfig()
+	if err != nil {
+		return none, errors.Trace(err)
 	}
 	// Handle stores with no corresponding constraints.
 	for store, charmStorage := range ch.Meta().Storage {
@@ -154,7 +222,7 @@ func DeployService(st *state.State, owner string, args params.ServiceDeploy) err
 		if charmStorage.Shared {
 			// TODO(axw) get the environment's default shared storage
 			// pool, and create constraints here.
-			return errors.Errorf(
+			return none, errors.Errorf(
 				"no constraints specified for shared charm storage %q",
 				store,
 			)
@@ -165,15 +233,22 @@ func DeployService(st *state.State, owner string, args params.ServiceDeploy) err
 		if charmStorage.Type != charm.StorageFilesystem {
 			// TODO(axw) clarify what the rules are for "block" kind when
 			// no constraints are specified. For "filesystem" we use rootfs.
-			return errors.Errorf(
+			return none, errors.Errorf(
 				"no constraints specified for %v charm storage %q",
 				charmStorage.Type,
 				store,
 			)
 		}
+		// If the environment has a default filesystem pool, leave
+		// the pool unspecified so that the default is applied.
+		// Otherwise, the pool is the provider type since rootfs
+		// provider has no configuration.
+		pool := string(provider.RootfsProviderType)
+		if _, ok := envConfig.StorageDefaultFilesystemSource(); ok {
+			pool = ""
+		}
 		storageConstraints[store] = storage.Constraints{
-			// The pool is the provider type since rootfs provider has no configuration.
-			Pool:  string(provider.RootfsProviderType),
+			Pool:  pool,
 			Count: uint64(charmStorage.CountMin),
 		}
 	}
@@ -186,28 +261,26 @@ func DeployService(st *state.State, owner string, args params.ServiceDeploy) err
 		settings, err = parseSettingsCompatible(ch, args.Config)
 	}
 	if err != nil {
-		return errors.Trace(err)
+		return none, errors.Trace(err)
 	}
 	// Convert network tags to names for any given networks.
 	requestedNetworks, err := networkTagsToNames(args.Networks)
 	if err != nil {
-		return errors.Trace(err)
+		return none, errors.Trace(err)
 	}
 
-	_, err = jjj.DeployService(st,
-		jjj.DeployServiceParams{
-			ServiceName: args.ServiceName,
-			// TODO(dfc) ServiceOwner should be a tag
-			ServiceOwner:   owner,
-			Charm:          ch,
-			NumUnits:       args.NumUnits,
-			ConfigSettings: settings,
-			Constraints:    args.Constraints,
-			ToMachineSpec:  args.ToMachineSpec,
-			Networks:       requestedNetworks,
-			Storage:        storageConstraints,
-		})
-	return err
+	return jjj.DeployServiceParams{
+		ServiceName: args.ServiceName,
+		// TODO(dfc) ServiceOwner should be a tag
+		ServiceOwner:   owner,
+		Charm:          ch,
+		NumUnits:       args.NumUnits,
+		ConfigSettings: settings,
+		Constraints:    args.Constraints,
+		ToMachineSpec:  args.ToMachineSpec,
+		Networks:       requestedNetworks,
+		Storage:        storageConstraints,
+	}, nil
 }
 
 // ServiceSetSettingsStrings updates the settings for the given service,
@@ -229,7 +302,10 @@ func validateCharmStorage(args params.ServiceDeploy, ch *state.Charm) error {
 	if len(args.Storage) == 0 {
 		return nil
 	}
-	if len(args.ToMachineSpec) != 0 {
+	if len(args.ToMachineSpec) != 0 && !instance.IsZonePlacements(args.ToMachineSpec) {
+		// Units placed in availability zones are assigned to new
+		// machines, on which storage may be provisioned.
+		//
 		// TODO(axw) when we support dynamic disk provisioning, we can
 		// relax this. We will need to consult the storage provider to
 		// decide whether or not this is allowable.
@@ -321,6 +321,78 @@ func (s *serviceSuite) TestClientServiceDeployDefaultFilesystemStorage(c *gc.C)
 	})
 }
 
+func (s *serviceSuite) TestClientServiceDeployEnvironDefaultFilesystemStorage(c *gc.C) {
+	setupStoragePool(c, s.State)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"storage-default-filesystem-source": "loop-pool",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	curl, ch := s.UploadCharm(c, "trusty/storage-filesystem-1", "storage-filesystem")
+	var cons constraints.Value
+	args := params.ServiceDeploy{
+		ServiceName: "service",
+		CharmUrl:    curl.String(),
+		NumUnits:    1,
+		Constraints: cons,
+	}
+	results, err := s.serviceApi.ServicesDeploy(params.ServicesDeploy{
+		Services: []params.ServiceDeploy{args}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{Error: nil}},
+	})
+	svc := apiservertesting.AssertPrincipalServiceDeployed(c, s.State, "service", curl, false, ch, cons)
+	storageConstraintsOut, err := svc.StorageConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageConstraintsOut, gc.DeepEquals, map[string]state.StorageConstraints{
+		"data": {
+			Count: 1,
+			Size:  1024,
+			Pool:  "loop-pool",
+		},
+	})
+}
+
+func (s *serviceSuite) TestSetStorageDefaults(c *gc.C) {
+	setupStoragePool(c, s.State)
+	results, err := s.serviceApi.SetStorageDefaults(params.ServicesStorageDefaults{
+		Defaults: []params.ServiceStorageDefaults{{
+			ServiceName:    s.service.Name(),
+			BlockPool:      "loop-pool",
+			FilesystemPool: "rootfs",
+		}, {
+			ServiceName: s.service.Name(),
+			BlockPool:   "rootfs",
+		}, {
+			ServiceName: "not-a-service",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `.*"rootfs" provider does not support "block" storage`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `service "not-a-service" not found`)
+
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.StorageDefaults(), jc.DeepEquals, &state.StoragePoolDefaults{
+		Block:      "loop-pool",
+		Filesystem: "rootfs",
+	})
+}
+
+func (s *serviceSuite) TestBlockSetStorageDefaults(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockSetStorageDefaults")
+	_, err := s.serviceApi.SetStorageDefaults(params.ServicesStorageDefaults{
+		Defaults: []params.ServiceStorageDefaults{{
+			ServiceName: s.service.Name(),
+			BlockPool:   "loop",
+		}},
+	})
+	s.AssertBlocked(c, err, "TestBlockSetStorageDefaults")
+}
+
 // TODO(wallyworld) - the following charm tests have been moved from the apiserver/client
 // package in order to use the fake charm store testing infrastructure. They are legacy tests
 // written to use the api client instead of the apiserver logic. They need to be rewritten and
@@ -0,0 +1,76 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type detachSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&detachSuite{})
+
+func (s *detachSuite) TestDetach(c *gc.C) {
+	s.state.storageInstance = func(tag names.StorageTag) (state.StorageInstance, error) {
+		return &mockStorageInstance{storageTag: tag, owner: s.unitTag}, nil
+	}
+	var detached []names.StorageTag
+	s.state.detachStorage = func(tag names.StorageTag) error {
+		if tag.Id() == "data/1" {
+			return errors.New("storage is owned by service-mysql, not a unit")
+		}
+		detached = append(detached, tag)
+		return nil
+	}
+
+	results, err := s.api.Detach(params.Entities{
+		Entities: []params.Entity{
+			{s.storageTag.String()},
+			{"storage-data-1"},
+			{"volume-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "storage is owned by service-mysql, not a unit")
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"volume-0" is not a valid storage tag`)
+	c.Assert(detached, jc.DeepEquals, []names.StorageTag{s.storageTag})
+}
+
+func (s *detachSuite) TestDetachBlocked(c *gc.C) {
+	s.state.blocks = map[state.BlockType]string{
+		state.RemoveBlock: "TestDetachBlocked",
+	}
+	_, err := s.api.Detach(params.Entities{
+		Entities: []params.Entity{{s.storageTag.String()}},
+	})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
+	c.Assert(err, gc.ErrorMatches, "TestDetachBlocked")
+}
+
+func (s *detachSuite) TestDetachBlockedForService(c *gc.C) {
+	s.state.entityBlocks = map[names.Tag]map[state.BlockType]string{
+		names.NewServiceTag("mysql"): {state.RemoveBlock: "keep mysql's data"},
+	}
+	s.state.detachStorage = func(tag names.StorageTag) error {
+		c.Fatalf("unexpected detach of %s", tag.Id())
+		return nil
+	}
+	results, err := s.api.Detach(params.Entities{
+		Entities: []params.Entity{{s.storageTag.String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(params.IsCodeOperationBlocked(results.Results[0].Error), jc.IsTrue)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "keep mysql's data")
+}
@@ -37,6 +37,8 @@ type baseStorageSuite struct {
 	unitTag         names.UnitTag
 	machineTag      names.MachineTag
 
+	filesystemAttachment *mockFilesystemAttachment
+
 	volumeTag        names.VolumeTag
 	volume           state.Volume
 	volumeAttachment state.VolumeAttachment
@@ -76,7 +78,7 @@ func (s *baseStorageSuite) constructState(c *gc.C) *mockState {
 	filesystemTag := names.NewFilesystemTag("104")
 	s.volumeTag = names.NewVolumeTag("22")
 	filesystem := &mockFilesystem{tag: filesystemTag}
-	filesystemAttachment := &mockFilesystemAttachment{}
+	s.filesystemAttachment = &mockFilesystemAttachment{}
 	s.volume = &mockVolume{tag: s.volumeTag, storage: s.storageTag}
 	s.volumeAttachment = &mockVolumeAttachment{
 		VolumeTag:  s.volumeTag,
@@ -107,7 +109,7 @@ func (s *baseStorageSuite) constructState(c *gc.C) *mockState {
 			s.calls = append(s.calls, storageInstanceFilesystemAttachmentCall)
 			c.Assert(m, gc.DeepEquals, s.machineTag)
 			c.Assert(f, gc.DeepEquals, filesystemTag)
-			return filesystemAttachment, nil
+			return s.filesystemAttachment, nil
 		},
 		storageInstanceVolume: func(t names.StorageTag) (state.Volume, error) {
 			s.calls = append(s.calls, storageInstanceVolumeCall)
@@ -138,6 +140,14 @@ func (s *baseStorageSuite) constructState(c *gc.C) *mockState {
 			s.calls = append(s.calls, allVolumesCall)
 			return []state.Volume{s.volume}, nil
 		},
+		resizeVolume: func(tag names.VolumeTag, size uint64) error {
+			s.calls = append(s.calls, resizeVolumeCall)
+			c.Assert(tag, gc.DeepEquals, s.volumeTag)
+			return nil
+		},
+		snapshot: func(id string) (state.Snapshot, error) {
+			return &mockSnapshot{id: id, volume: s.volumeTag}, nil
+		},
 		envName: "storagetest",
 	}
 }
@@ -210,6 +220,17 @@ type mockState struct {
 	machineVolumeAttachments            func(machine names.MachineTag) ([]state.VolumeAttachment, error)
 	volumeAttachments                   func(volume names.VolumeTag) ([]state.VolumeAttachment, error)
 	allVolumes                          func() ([]state.Volume, error)
+	resizeVolume                        func(tag names.VolumeTag, size uint64) error
+	addSnapshot                         func(volume names.VolumeTag) (state.Snapshot, error)
+	allSnapshots                        func() ([]state.Snapshot, error)
+	destroySnapshot                     func(id string) error
+	snapshot                            func(id string) (state.Snapshot, error)
+	detachStorage                       func(tag names.StorageTag) error
+
+	// blocks holds the messages of the blocks switched on for the
+	// environment, and entityBlocks those for services and machines.
+	blocks       map[state.BlockType]string
+	entityBlocks map[names.Tag]map[state.BlockType]string
 }
 
 func (st *mockState) StorageInstance(s names.StorageTag) (state.StorageInstance, error) {
@@ -272,6 +293,54 @@ func (st *mockState) Volume(tag names.VolumeTag) (state.Volume, error) {
 	return st.volume(tag)
 }
 
+func (st *mockState) ResizeVolume(tag names.VolumeTag, size uint64) error {
+	return st.resizeVolume(tag, size)
+}
+
+func (st *mockState) AddSnapshot(volume names.VolumeTag) (state.Snapshot, error) {
+	return st.addSnapshot(volume)
+}
+
+func (st *mockState) AllSnapshots() ([]state.Snapshot, error) {
+	return st.allSnapshots()
+}
+
+func (st *mockState) DestroySnapshot(id string) error {
+	return st.destroySnapshot(id)
+}
+
+func (st *mockState) Snapshot(id string) (state.Snapshot, error) {
+	return st.snapshot(id)
+}
+
+func (st *mockState) DetachStorage(tag names.StorageTag) error {
+	return st.detachStorage(tag)
+}
+
+func (st *mockState) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
+	msg, ok := st.blocks[t]
+	return &mockBlock{t: t, msg: msg}, ok, nil
+}
+
+func (st *mockState) GetBlockForEntity(t state.BlockType, entity names.Tag) (state.Block, bool, error) {
+	msg, ok := st.entityBlocks[entity][t]
+	return &mockBlock{t: t, msg: msg}, ok, nil
+}
+
+type mockBlock struct {
+	state.Block
+	t   state.BlockType
+	msg string
+}
+
+func (m *mockBlock) Type() state.BlockType {
+	return m.t
+}
+
+func (m *mockBlock) Message() string {
+	return m.msg
+}
+
 type mockNotifyWatcher struct {
 	state.NotifyWatcher
 	changes chan struct{}
@@ -321,7 +390,9 @@ func (m *mockFilesystem) FilesystemTag() names.FilesystemTag {
 
 type mockFilesystemAttachment struct {
 	state.FilesystemAttachment
-	tag names.FilesystemTag
+	tag        names.FilesystemTag
+	mountPoint string
+	usage      *state.FilesystemUsage
 }
 
 func (m *mockFilesystemAttachment) Filesystem() names.FilesystemTag {
@@ -329,7 +400,14 @@ func (m *mockFilesystemAttachment) Filesystem() names.FilesystemTag {
 }
 
 func (m *mockFilesystemAttachment) Info() (state.FilesystemAttachmentInfo, error) {
-	return state.FilesystemAttachmentInfo{}, nil
+	return state.FilesystemAttachmentInfo{MountPoint: m.mountPoint}, nil
+}
+
+func (m *mockFilesystemAttachment) Usage() (state.FilesystemUsage, bool) {
+	if m.usage == nil {
+		return state.FilesystemUsage{}, false
+	}
+	return *m.usage, true
 }
 
 type mockStorageInstance struct {
@@ -343,8 +421,8 @@ func (m *mockStorageInstance) Kind() state.StorageKind {
 	return m.kind
 }
 
-func (m *mockStorageInstance) Owner() names.Tag {
-	return m.owner
+func (m *mockStorageInstance) Owner() (names.Tag, bool) {
+	return m.owner, m.owner != nil
 }
 
 func (m *mockStorageInstance) Tag() names.Tag {
@@ -365,7 +443,7 @@ func (m *mockStorageAttachment) StorageInstance() names.StorageTag {
 }
 
 func (m *mockStorageAttachment) Unit() names.UnitTag {
-	return m.storage.Owner().(names.UnitTag)
+	return m.storage.owner.(names.UnitTag)
 }
 
 type mockVolumeAttachment struct {
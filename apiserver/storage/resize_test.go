@@ -0,0 +1,100 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type resizeSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&resizeSuite{})
+
+func (s *resizeSuite) TestResizeBlockStorage(c *gc.C) {
+	s.storageInstance.kind = state.StorageKindBlock
+	var resized uint64
+	s.state.resizeVolume = func(tag names.VolumeTag, size uint64) error {
+		s.calls = append(s.calls, resizeVolumeCall)
+		c.Assert(tag, gc.Equals, s.volumeTag)
+		resized = size
+		return nil
+	}
+
+	results, err := s.api.Resize(params.StorageResizeArgs{
+		Storage: []params.StorageResize{{StorageTag: s.storageTag.String(), Size: 2048}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(resized, gc.Equals, uint64(2048))
+	s.assertCalls(c, []string{
+		storageInstanceCall,
+		storageInstanceVolumeCall,
+		resizeVolumeCall,
+	})
+}
+
+func (s *resizeSuite) TestResizeFilesystemStorage(c *gc.C) {
+	results, err := s.api.Resize(params.StorageResizeArgs{
+		Storage: []params.StorageResize{{StorageTag: s.storageTag.String(), Size: 2048}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `resizing non-block storage "data/0" not supported`)
+	s.assertCalls(c, []string{storageInstanceCall})
+}
+
+func (s *resizeSuite) TestResizeErrors(c *gc.C) {
+	s.storageInstance.kind = state.StorageKindBlock
+	s.state.resizeVolume = func(tag names.VolumeTag, size uint64) error {
+		return errors.New("cannot resize volume")
+	}
+
+	results, err := s.api.Resize(params.StorageResizeArgs{
+		Storage: []params.StorageResize{
+			{StorageTag: "volume-0", Size: 2048},
+			{StorageTag: s.storageTag.String(), Size: 2048},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `"volume-0" is not a valid storage tag`)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "cannot resize volume")
+}
+
+func (s *resizeSuite) TestResizeBlocked(c *gc.C) {
+	s.state.blocks = map[state.BlockType]string{
+		state.ChangeBlock: "TestResizeBlocked",
+	}
+	_, err := s.api.Resize(params.StorageResizeArgs{
+		Storage: []params.StorageResize{{StorageTag: s.storageTag.String(), Size: 2048}},
+	})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
+	c.Assert(err, gc.ErrorMatches, "TestResizeBlocked")
+	s.assertCalls(c, []string{})
+}
+
+func (s *resizeSuite) TestResizeBlockedForService(c *gc.C) {
+	s.storageInstance.kind = state.StorageKindBlock
+	s.state.entityBlocks = map[names.Tag]map[state.BlockType]string{
+		names.NewServiceTag("mysql"): {state.ChangeBlock: "mysql is frozen"},
+	}
+
+	results, err := s.api.Resize(params.StorageResizeArgs{
+		Storage: []params.StorageResize{{StorageTag: s.storageTag.String(), Size: 2048}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(params.IsCodeOperationBlocked(results.Results[0].Error), jc.IsTrue)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "mysql is frozen")
+	s.assertCalls(c, []string{storageInstanceCall})
+}
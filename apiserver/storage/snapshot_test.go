@@ -0,0 +1,191 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type snapshotSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&snapshotSuite{})
+
+type mockSnapshot struct {
+	state.Snapshot
+	id      string
+	volume  names.VolumeTag
+	info    *state.SnapshotInfo
+	failure string
+}
+
+func (m *mockSnapshot) Id() string {
+	return m.id
+}
+
+func (m *mockSnapshot) Volume() names.VolumeTag {
+	return m.volume
+}
+
+func (m *mockSnapshot) Life() state.Life {
+	return state.Alive
+}
+
+func (m *mockSnapshot) Pool() string {
+	return "ebs"
+}
+
+func (m *mockSnapshot) Size() uint64 {
+	return 1024
+}
+
+func (m *mockSnapshot) Info() (state.SnapshotInfo, error) {
+	if m.info == nil {
+		return state.SnapshotInfo{}, errors.NotProvisionedf("snapshot %q", m.id)
+	}
+	return *m.info, nil
+}
+
+func (m *mockSnapshot) Failure() string {
+	return m.failure
+}
+
+func (s *snapshotSuite) TestCreateSnapshots(c *gc.C) {
+	s.storageInstance.kind = state.StorageKindBlock
+	s.state.addSnapshot = func(volume names.VolumeTag) (state.Snapshot, error) {
+		c.Assert(volume, gc.Equals, s.volumeTag)
+		return &mockSnapshot{id: "7", volume: volume}, nil
+	}
+
+	results, err := s.api.CreateSnapshots(params.Entities{
+		Entities: []params.Entity{{s.storageTag.String()}, {"volume-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result, gc.Equals, "7")
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `"volume-0" is not a valid storage tag`)
+	s.assertCalls(c, []string{
+		storageInstanceCall,
+		storageInstanceVolumeCall,
+	})
+}
+
+func (s *snapshotSuite) TestCreateSnapshotsFilesystemStorage(c *gc.C) {
+	results, err := s.api.CreateSnapshots(params.Entities{
+		Entities: []params.Entity{{s.storageTag.String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `snapshotting non-block storage "data/0" not supported`)
+}
+
+func (s *snapshotSuite) TestListSnapshots(c *gc.C) {
+	s.state.allSnapshots = func() ([]state.Snapshot, error) {
+		return []state.Snapshot{
+			&mockSnapshot{
+				id:     "7",
+				volume: s.volumeTag,
+				info:   &state.SnapshotInfo{SnapshotId: "snap-123"},
+			},
+			&mockSnapshot{
+				id:      "8",
+				volume:  s.volumeTag,
+				failure: "no can do",
+			},
+		}, nil
+	}
+
+	results, err := s.api.ListSnapshots()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.SnapshotDetailsResults{
+		Results: []params.SnapshotDetails{{
+			Id:         "7",
+			VolumeTag:  s.volumeTag.String(),
+			StorageTag: s.storageTag.String(),
+			Pool:       "ebs",
+			Size:       1024,
+			Life:       params.Alive,
+			SnapshotId: "snap-123",
+		}, {
+			Id:         "8",
+			VolumeTag:  s.volumeTag.String(),
+			StorageTag: s.storageTag.String(),
+			Pool:       "ebs",
+			Size:       1024,
+			Life:       params.Alive,
+			Failure:    "no can do",
+		}},
+	})
+	s.assertCalls(c, []string{volumeCall, volumeCall})
+}
+
+func (s *snapshotSuite) TestDestroySnapshots(c *gc.C) {
+	var destroyed []string
+	s.state.destroySnapshot = func(id string) error {
+		if id == "bad" {
+			return errors.New("cannot destroy snapshot")
+		}
+		destroyed = append(destroyed, id)
+		return nil
+	}
+
+	results, err := s.api.DestroySnapshots(params.SnapshotIds{Ids: []string{"7", "bad"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "cannot destroy snapshot")
+	c.Assert(destroyed, jc.DeepEquals, []string{"7"})
+}
+
+func (s *snapshotSuite) TestCreateSnapshotsBlockedForService(c *gc.C) {
+	s.storageInstance.kind = state.StorageKindBlock
+	s.state.entityBlocks = map[names.Tag]map[state.BlockType]string{
+		names.NewServiceTag("mysql"): {state.ChangeBlock: "mysql is frozen"},
+	}
+	s.state.addSnapshot = func(volume names.VolumeTag) (state.Snapshot, error) {
+		c.Fatalf("unexpected snapshot of %s", volume.Id())
+		return nil, nil
+	}
+
+	results, err := s.api.CreateSnapshots(params.Entities{
+		Entities: []params.Entity{{s.storageTag.String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(params.IsCodeOperationBlocked(results.Results[0].Error), jc.IsTrue)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "mysql is frozen")
+}
+
+func (s *snapshotSuite) TestDestroySnapshotsBlocked(c *gc.C) {
+	s.state.blocks = map[state.BlockType]string{
+		state.ChangeBlock: "TestDestroySnapshotsBlocked",
+	}
+	_, err := s.api.DestroySnapshots(params.SnapshotIds{Ids: []string{"7"}})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
+	c.Assert(err, gc.ErrorMatches, "TestDestroySnapshotsBlocked")
+}
+
+func (s *snapshotSuite) TestDestroySnapshotsBlockedForService(c *gc.C) {
+	s.state.entityBlocks = map[names.Tag]map[state.BlockType]string{
+		names.NewServiceTag("mysql"): {state.RemoveBlock: "keep mysql's snapshots"},
+	}
+	s.state.destroySnapshot = func(id string) error {
+		c.Fatalf("unexpected destruction of snapshot %s", id)
+		return nil
+	}
+
+	results, err := s.api.DestroySnapshots(params.SnapshotIds{Ids: []string{"7"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(params.IsCodeOperationBlocked(results.Results[0].Error), jc.IsTrue)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "keep mysql's snapshots")
+}
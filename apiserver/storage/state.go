@@ -55,6 +55,31 @@ type storageAccess interface {
 
 	// Volume is required for volume functionality.
 	Volume(tag names.VolumeTag) (state.Volume, error)
+
+	// ResizeVolume is required for volume resize functionality.
+	ResizeVolume(tag names.VolumeTag, size uint64) error
+
+	// AddSnapshot is required for snapshot functionality.
+	AddSnapshot(volume names.VolumeTag) (state.Snapshot, error)
+
+	// AllSnapshots is required for snapshot functionality.
+	AllSnapshots() ([]state.Snapshot, error)
+
+	// DestroySnapshot is required for snapshot functionality.
+	DestroySnapshot(id string) error
+
+	// Snapshot is required for snapshot functionality.
+	Snapshot(id string) (state.Snapshot, error)
+
+	// DetachStorage is required for storage detach functionality.
+	DetachStorage(tag names.StorageTag) error
+
+	// GetBlockForType is required to block operations.
+	GetBlockForType(t state.BlockType) (state.Block, bool, error)
+
+	// GetBlockForEntity is required to block operations
+	// on storage owned by a service or unit.
+	GetBlockForEntity(t state.BlockType, entity names.Tag) (state.Block, bool, error)
 }
 
 var getState = func(st *state.State) storageAccess {
@@ -26,6 +26,7 @@ type API struct {
 	storage     storageAccess
 	poolManager poolmanager.PoolManager
 	authorizer  common.Authorizer
+	check       *common.BlockChecker
 }
 
 // createAPI returns a new storage API facade.
@@ -43,6 +44,7 @@ func createAPI(
 		storage:     st,
 		poolManager: pm,
 		authorizer:  authorizer,
+		check:       common.NewBlockChecker(st),
 	}, nil
 }
 
@@ -188,9 +190,42 @@ func (api *API) createParamsStorageAttachment(si params.StorageDetails, sa state
 	if result.Location != "" {
 		result.Status = "attached"
 	}
+	if result.Status == "attached" && result.Kind == params.StorageKindFilesystem {
+		usage, err := api.filesystemUsage(sa.StorageInstance(), machineTag)
+		if err != nil {
+			return params.StorageDetails{}, errors.Annotate(err, "getting filesystem usage")
+		}
+		result.Usage = usage
+	}
 	return result, nil
 }
 
+// filesystemUsage returns the most recently reported usage of the
+// filesystem backing the specified storage instance, as attached to
+// the specified machine, or nil if no usage has been reported.
+func (api *API) filesystemUsage(storageTag names.StorageTag, machineTag names.MachineTag) (*params.StorageUsage, error) {
+	filesystem, err := api.storage.StorageInstanceFilesystem(storageTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	att, err := api.storage.FilesystemAttachment(machineTag, filesystem.FilesystemTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	usage, ok := att.Usage()
+	if !ok {
+		return nil, nil
+	}
+	return &params.StorageUsage{
+		UsedBytes:  usage.UsedBytes,
+		FreeBytes:  usage.FreeBytes,
+		UsedInodes: usage.UsedInodes,
+		FreeInodes: usage.FreeInodes,
+
+		ThresholdExceeded: usage.ThresholdExceeded,
+	}, nil
+}
+
 func (api *API) getStorageInstance(tag names.StorageTag) (bool, params.StorageDetails, *params.Error) {
 	nothing := params.StorageDetails{}
 	serverError := func(err error) *params.Error {
@@ -212,12 +247,14 @@ func (api *API) getStorageInstance(tag names.StorageTag) (bool, params.StorageDe
 
 func createParamsStorageInstance(si state.StorageInstance, persistent bool) params.StorageDetails {
 	result := params.StorageDetails{
-		OwnerTag:   si.Owner().String(),
 		StorageTag: si.Tag().String(),
 		Kind:       params.StorageKind(si.Kind()),
 		Status:     "pending",
 		Persistent: persistent,
 	}
+	if owner, ok := si.Owner(); ok {
+		result.OwnerTag = owner.String()
+	}
 	return result
 }
 
@@ -352,6 +389,222 @@ func (a *API) CreatePool(p params.StoragePool) error {
 	return err
 }
 
+// Resize requests that the specified storage instances be grown to the
+// specified sizes, in MiB. Only block storage may be resized; the
+// charm is notified once the underlying volume has been grown.
+func (a *API) Resize(args params.StorageResizeArgs) (params.ErrorResults, error) {
+	if err := a.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Storage)),
+	}
+	for i, arg := range args.Storage {
+		err := a.resizeStorage(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (a *API) resizeStorage(arg params.StorageResize) error {
+	storageTag, err := names.ParseStorageTag(arg.StorageTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	storageInstance, err := a.storage.StorageInstance(storageTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if storageInstance.Kind() != state.StorageKindBlock {
+		return errors.NotSupportedf("resizing non-block storage %q", storageTag.Id())
+	}
+	if err := a.check.ChangeAllowedFor(storageOwners(storageInstance)...); err != nil {
+		return errors.Trace(err)
+	}
+	volume, err := a.storage.StorageInstanceVolume(storageTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return a.storage.ResizeVolume(volume.VolumeTag(), arg.Size)
+}
+
+// CreateSnapshots requests snapshots of the volumes backing the specified
+// storage instances, returning the IDs of the new snapshots. Only block
+// storage may be snapshotted; the snapshots are taken asynchronously by
+// the storage provisioner.
+func (a *API) CreateSnapshots(args params.Entities) (params.StringResults, error) {
+	if err := a.check.ChangeAllowed(); err != nil {
+		return params.StringResults{}, errors.Trace(err)
+	}
+	results := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		id, err := a.createSnapshot(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = id
+	}
+	return results, nil
+}
+
+func (a *API) createSnapshot(tag string) (string, error) {
+	storageTag, err := names.ParseStorageTag(tag)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	storageInstance, err := a.storage.StorageInstance(storageTag)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if storageInstance.Kind() != state.StorageKindBlock {
+		return "", errors.NotSupportedf("snapshotting non-block storage %q", storageTag.Id())
+	}
+	if err := a.check.ChangeAllowedFor(storageOwners(storageInstance)...); err != nil {
+		return "", errors.Trace(err)
+	}
+	volume, err := a.storage.StorageInstanceVolume(storageTag)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	snapshot, err := a.storage.AddSnapshot(volume.VolumeTag())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return snapshot.Id(), nil
+}
+
+// ListSnapshots returns the details of all volume snapshots in the
+// environment.
+func (a *API) ListSnapshots() (params.SnapshotDetailsResults, error) {
+	snapshots, err := a.storage.AllSnapshots()
+	if err != nil {
+		return params.SnapshotDetailsResults{}, common.ServerError(err)
+	}
+	results := params.SnapshotDetailsResults{
+		Results: make([]params.SnapshotDetails, len(snapshots)),
+	}
+	for i, snapshot := range snapshots {
+		details := params.SnapshotDetails{
+			Id:        snapshot.Id(),
+			VolumeTag: snapshot.Volume().String(),
+			Pool:      snapshot.Pool(),
+			Size:      snapshot.Size(),
+			Life:      params.Life(snapshot.Life().String()),
+			Failure:   snapshot.Failure(),
+		}
+		if info, err := snapshot.Info(); err == nil {
+			details.SnapshotId = info.SnapshotId
+		}
+		// The volume may have been removed since the snapshot
+		// was taken, in which case there is no storage to show.
+		if volume, err := a.storage.Volume(snapshot.Volume()); err == nil {
+			if storageTag, err := volume.StorageInstance(); err == nil {
+				details.StorageTag = storageTag.String()
+			}
+		}
+		results.Results[i] = details
+	}
+	return results, nil
+}
+
+// DestroySnapshots destroys the snapshots with the specified IDs.
+func (a *API) DestroySnapshots(args params.SnapshotIds) (params.ErrorResults, error) {
+	if err := a.check.RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		err := a.destroySnapshot(id)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (a *API) destroySnapshot(id string) error {
+	owners, err := a.snapshotOwners(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := a.check.RemoveAllowedFor(owners...); err != nil {
+		return errors.Trace(err)
+	}
+	return a.storage.DestroySnapshot(id)
+}
+
+// snapshotOwners returns the owner of the storage backed by the
+// snapshot's volume, if the volume and storage still exist.
+func (a *API) snapshotOwners(id string) ([]names.Tag, error) {
+	snapshot, err := a.storage.Snapshot(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volume, err := a.storage.Volume(snapshot.Volume())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	storageTag, err := volume.StorageInstance()
+	if errors.IsNotAssigned(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	storageInstance, err := a.storage.StorageInstance(storageTag)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return storageOwners(storageInstance), nil
+}
+
+// storageOwners returns the unit or service that owns the storage
+// instance, so that blocks on them may be checked.
+func storageOwners(storageInstance state.StorageInstance) []names.Tag {
+	if owner, ok := storageInstance.Owner(); ok {
+		return []names.Tag{owner}
+	}
+	return nil
+}
+
+// Detach detaches the specified storage instances from the units that
+// own them. The storage instances, and their underlying volumes or
+// filesystems, are preserved so that they may later be attached to
+// new units.
+func (a *API) Detach(args params.Entities) (params.ErrorResults, error) {
+	if err := a.check.RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	one := func(tag string) error {
+		storageTag, err := names.ParseStorageTag(tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		storageInstance, err := a.storage.StorageInstance(storageTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := a.check.RemoveAllowedFor(storageOwners(storageInstance)...); err != nil {
+			return errors.Trace(err)
+		}
+		return a.storage.DetachStorage(storageTag)
+	}
+	for i, arg := range args.Entities {
+		err := one(arg.Tag)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
 func (a *API) ListVolumes(filter params.VolumeFilter) (params.VolumeItemsResult, error) {
 	if !filter.IsEmpty() {
 		return params.VolumeItemsResult{Results: a.filterVolumes(filter)}, nil
@@ -434,7 +687,7 @@ func (a *API) convertStateVolumeToParams(st state.Volume) (params.VolumeInstance
 				storage, volume.VolumeTag)
 			return params.VolumeInstance{}, err
 		}
-		owner := storageInstance.Owner()
+		owner, _ := storageInstance.Owner()
 		// only interested in Unit for now
 		if unitTag, ok := owner.(names.UnitTag); ok {
 			volume.UnitTag = unitTag.String()
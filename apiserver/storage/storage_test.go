@@ -33,6 +33,7 @@ const (
 	machineVolumeAttachmentsCall            = "machineVolumeAttachments"
 	volumeAttachmentsCall                   = "volumeAttachments"
 	allVolumesCall                          = "allVolumes"
+	resizeVolumeCall                        = "resizeVolume"
 )
 
 func (s *storageSuite) TestStorageListEmpty(c *gc.C) {
@@ -241,7 +242,7 @@ func (s *storageSuite) createTestStorageInfo() params.StorageInfo {
 	}
 }
 
-func (s *storageSuite) assertCalls(c *gc.C, expectedCalls []string) {
+func (s *baseStorageSuite) assertCalls(c *gc.C, expectedCalls []string) {
 	c.Assert(s.calls, jc.SameContents, expectedCalls)
 }
 
@@ -288,6 +289,44 @@ func (s *storageSuite) TestShowStorage(c *gc.C) {
 	c.Assert(one.Result, gc.DeepEquals, expected)
 }
 
+func (s *storageSuite) TestShowStorageFilesystemUsage(c *gc.C) {
+	s.filesystemAttachment.mountPoint = "/srv/data"
+	s.filesystemAttachment.usage = &state.FilesystemUsage{
+		UsedBytes:  1024,
+		FreeBytes:  3072,
+		UsedInodes: 10,
+		FreeInodes: 90,
+
+		ThresholdExceeded: true,
+	}
+	entity := params.Entity{Tag: s.storageTag.String()}
+
+	found, err := s.api.Show(params.Entities{Entities: []params.Entity{entity}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 1)
+
+	one := found.Results[0]
+	c.Assert(one.Error, gc.IsNil)
+
+	expected := params.StorageDetails{
+		StorageTag: s.storageTag.String(),
+		OwnerTag:   s.unitTag.String(),
+		Kind:       params.StorageKindFilesystem,
+		UnitTag:    s.unitTag.String(),
+		Status:     "attached",
+		Location:   "/srv/data",
+		Usage: &params.StorageUsage{
+			UsedBytes:  1024,
+			FreeBytes:  3072,
+			UsedInodes: 10,
+			FreeInodes: 90,
+
+			ThresholdExceeded: true,
+		},
+	}
+	c.Assert(one.Result, gc.DeepEquals, expected)
+}
+
 func (s *storageSuite) TestShowStorageInvalidId(c *gc.C) {
 	storageTag := "foo"
 	entity := params.Entity{Tag: storageTag}
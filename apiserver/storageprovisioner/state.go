@@ -27,6 +27,10 @@ type provisionerState interface {
 	WatchEnvironVolumeAttachments() state.StringsWatcher
 	WatchMachineVolumes(names.MachineTag) state.StringsWatcher
 	WatchMachineVolumeAttachments(names.MachineTag) state.StringsWatcher
+	WatchEnvironVolumeResizes() state.StringsWatcher
+	WatchMachineVolumeResizes(names.MachineTag) state.StringsWatcher
+	WatchEnvironSnapshots() state.StringsWatcher
+	WatchMachineSnapshots(names.MachineTag) state.StringsWatcher
 	WatchVolumeAttachment(names.MachineTag, names.VolumeTag) state.NotifyWatcher
 
 	Filesystem(names.FilesystemTag) (state.Filesystem, error)
@@ -36,10 +40,19 @@ type provisionerState interface {
 	VolumeAttachment(names.MachineTag, names.VolumeTag) (state.VolumeAttachment, error)
 	VolumeAttachments(names.VolumeTag) ([]state.VolumeAttachment, error)
 
+	Snapshot(string) (state.Snapshot, error)
+	SetSnapshotInfo(string, state.SnapshotInfo) error
+	SetSnapshotFailure(string, string) error
+	RemoveSnapshot(string) error
+
 	SetFilesystemInfo(names.FilesystemTag, state.FilesystemInfo) error
 	SetFilesystemAttachmentInfo(names.MachineTag, names.FilesystemTag, state.FilesystemAttachmentInfo) error
 	SetVolumeInfo(names.VolumeTag, state.VolumeInfo) error
 	SetVolumeAttachmentInfo(names.MachineTag, names.VolumeTag, state.VolumeAttachmentInfo) error
+	FailVolumeResize(names.VolumeTag, string) error
+
+	RemoveFilesystemAttachment(names.MachineTag, names.FilesystemTag) error
+	RemoveVolumeAttachment(names.MachineTag, names.VolumeTag) error
 }
 
 type stateShim struct {
@@ -250,6 +250,18 @@ func (s *StorageProvisionerAPI) watchStorageEntities(
 	return results, nil
 }
 
+// WatchVolumeResizes watches for requests to resize volumes scoped
+// to the entity with the tag passed to NewState.
+func (s *StorageProvisionerAPI) WatchVolumeResizes(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.st.WatchEnvironVolumeResizes, s.st.WatchMachineVolumeResizes)
+}
+
+// WatchSnapshots watches for changes to snapshots of volumes scoped
+// to the entity with the tag passed to NewState.
+func (s *StorageProvisionerAPI) WatchSnapshots(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.st.WatchEnvironSnapshots, s.st.WatchMachineSnapshots)
+}
+
 // WatchVolumeAttachments watches for changes to volume attachments scoped to
 // the entity with the tag passed to NewState.
 func (s *StorageProvisionerAPI) WatchVolumeAttachments(args params.Entities) (params.MachineStorageIdsWatchResults, error) {
@@ -449,6 +461,47 @@ func (s *StorageProvisionerAPI) VolumeBlockDevices(args params.MachineStorageIds
 	return results, nil
 }
 
+// MachineBlockDevices returns details of all block devices on the
+// specified machines, as reported by their disk managers.
+func (s *StorageProvisionerAPI) MachineBlockDevices(args params.Entities) (params.BlockDevicesResults, error) {
+	canAccess, err := s.getMachineAuthFunc()
+	if err != nil {
+		return params.BlockDevicesResults{}, common.ServerError(common.ErrPerm)
+	}
+	results := params.BlockDevicesResults{
+		Results: make([]params.BlockDevicesResult, len(args.Entities)),
+	}
+	one := func(arg params.Entity) ([]storage.BlockDevice, error) {
+		machineTag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil {
+			return nil, err
+		}
+		if !canAccess(machineTag) {
+			return nil, common.ErrPerm
+		}
+		stateBlockDevices, err := s.st.BlockDevices(machineTag)
+		if err != nil {
+			return nil, err
+		}
+		blockDevices := make([]storage.BlockDevice, len(stateBlockDevices))
+		for i, dev := range stateBlockDevices {
+			blockDevices[i] = common.BlockDeviceFromState(dev)
+		}
+		return blockDevices, nil
+	}
+	for i, arg := range args.Entities {
+		var result params.BlockDevicesResult
+		blockDevices, err := one(arg)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = blockDevices
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
 // FilesystemAttachments returns details of filesystem attachments with the specified IDs.
 func (s *StorageProvisionerAPI) FilesystemAttachments(args params.MachineStorageIds) (params.FilesystemAttachmentResults, error) {
 	canAccess, err := s.getAttachmentAuthFunc()
@@ -508,6 +561,17 @@ func (s *StorageProvisionerAPI) VolumeParams(args params.Entities) (params.Volum
 		if err != nil {
 			return params.VolumeParams{}, err
 		}
+		if stateVolumeParams, _ := volume.Params(); stateVolumeParams.Snapshot != "" {
+			snapshot, err := s.st.Snapshot(stateVolumeParams.Snapshot)
+			if err != nil {
+				return params.VolumeParams{}, err
+			}
+			snapshotInfo, err := snapshot.Info()
+			if err != nil {
+				return params.VolumeParams{}, err
+			}
+			volumeParams.SnapshotId = snapshotInfo.SnapshotId
+		}
 		if len(volumeAttachments) == 1 {
 			machineTag := volumeAttachments[0].Machine()
 			instanceId, err := s.st.MachineInstanceId(machineTag)
@@ -540,6 +604,137 @@ func (s *StorageProvisionerAPI) VolumeParams(args params.Entities) (params.Volum
 	return results, nil
 }
 
+// VolumeResizeParams returns the parameters for resizing the volumes
+// with the specified tags.
+func (s *StorageProvisionerAPI) VolumeResizeParams(args params.Entities) (params.VolumeResizeParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.VolumeResizeParamsResults{}, err
+	}
+	results := params.VolumeResizeParamsResults{
+		Results: make([]params.VolumeResizeParamsResult, len(args.Entities)),
+	}
+	poolManager := poolmanager.New(s.settings)
+	one := func(arg params.Entity) (params.VolumeResizeParams, error) {
+		tag, err := names.ParseVolumeTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			return params.VolumeResizeParams{}, common.ErrPerm
+		}
+		volume, err := s.st.Volume(tag)
+		if errors.IsNotFound(err) {
+			return params.VolumeResizeParams{}, common.ErrPerm
+		} else if err != nil {
+			return params.VolumeResizeParams{}, err
+		}
+		return common.VolumeResizeParams(volume, poolManager)
+	}
+	for i, arg := range args.Entities {
+		var result params.VolumeResizeParamsResult
+		resizeParams, err := one(arg)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = resizeParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// FailVolumeResizes clears the pending requests to resize the specified
+// volumes, recording the reasons that they could not be carried out.
+func (s *StorageProvisionerAPI) FailVolumeResizes(args params.VolumeResizeFailures) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Failures)),
+	}
+	one := func(arg params.VolumeResizeFailure) error {
+		tag, err := names.ParseVolumeTag(arg.VolumeTag)
+		if err != nil || !canAccess(tag) {
+			return common.ErrPerm
+		}
+		err = s.st.FailVolumeResize(tag, arg.Reason)
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		}
+		return errors.Trace(err)
+	}
+	for i, arg := range args.Failures {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// SnapshotParams returns the parameters for taking, or destroying,
+// the snapshots with the specified IDs.
+func (s *StorageProvisionerAPI) SnapshotParams(args params.SnapshotIds) (params.SnapshotParamsResults, error) {
+	canAccess, err := s.getSnapshotAuthFunc()
+	if err != nil {
+		return params.SnapshotParamsResults{}, err
+	}
+	results := params.SnapshotParamsResults{
+		Results: make([]params.SnapshotParamsResult, len(args.Ids)),
+	}
+	poolManager := poolmanager.New(s.settings)
+	one := func(id string) (params.SnapshotParams, error) {
+		if !canAccess(id) {
+			return params.SnapshotParams{}, common.ErrPerm
+		}
+		// Snapshots are removed once destroyed, so a
+		// NotFound error is returned rather than ErrPerm,
+		// to indicate that there is nothing to do.
+		snapshot, err := s.st.Snapshot(id)
+		if err != nil {
+			return params.SnapshotParams{}, err
+		}
+		providerType, cfg, err := common.StoragePoolConfig(snapshot.Pool(), poolManager)
+		if err != nil {
+			return params.SnapshotParams{}, err
+		}
+		result := params.SnapshotParams{
+			Id:         snapshot.Id(),
+			Life:       params.Life(snapshot.Life().String()),
+			VolumeTag:  snapshot.Volume().String(),
+			Size:       snapshot.Size(),
+			Provider:   string(providerType),
+			Attributes: cfg.Attrs(),
+			Failure:    snapshot.Failure(),
+		}
+		if info, err := snapshot.Info(); err == nil {
+			result.SnapshotId = info.SnapshotId
+		} else if !errors.IsNotProvisioned(err) {
+			return params.SnapshotParams{}, err
+		}
+		// The volume may have been removed since the
+		// snapshot was taken, in which case the snapshot
+		// may only be destroyed.
+		volume, err := s.st.Volume(snapshot.Volume())
+		if err == nil {
+			if volumeInfo, err := volume.Info(); err == nil {
+				result.VolumeId = volumeInfo.VolumeId
+			}
+		} else if !errors.IsNotFound(err) {
+			return params.SnapshotParams{}, err
+		}
+		return result, nil
+	}
+	for i, id := range args.Ids {
+		var result params.SnapshotParamsResult
+		snapshotParams, err := one(id)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = snapshotParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
 // FilesystemParams returns the parameters for creating the filesystems
 // with the specified tags.
 func (s *StorageProvisionerAPI) FilesystemParams(args params.Entities) (params.FilesystemParamsResults, error) {
@@ -826,6 +1021,18 @@ func (s *StorageProvisionerAPI) SetVolumeInfo(args params.Volumes) (params.Error
 		} else if !canAccessVolume(volumeTag) {
 			return common.ErrPerm
 		}
+		volume, err := s.st.Volume(volumeTag)
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if oldInfo, err := volume.Info(); err == nil {
+			// The volume is already provisioned, and its info
+			// is being updated (e.g. after it was resized). The
+			// pool is recorded by state, and may not change.
+			volumeInfo.Pool = oldInfo.Pool
+		}
 		err = s.st.SetVolumeInfo(volumeTag, volumeInfo)
 		if errors.IsNotFound(err) {
 			return common.ErrPerm
@@ -839,6 +1046,101 @@ func (s *StorageProvisionerAPI) SetVolumeInfo(args params.Volumes) (params.Error
 	return results, nil
 }
 
+// SetSnapshotInfo records the details of newly taken snapshots.
+func (s *StorageProvisionerAPI) SetSnapshotInfo(args params.Snapshots) (params.ErrorResults, error) {
+	canAccess, err := s.getSnapshotAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Snapshots)),
+	}
+	one := func(arg params.Snapshot) error {
+		if !canAccess(arg.Id) {
+			return common.ErrPerm
+		}
+		err := s.st.SetSnapshotInfo(arg.Id, state.SnapshotInfo{
+			SnapshotId: arg.SnapshotId,
+		})
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		}
+		return errors.Trace(err)
+	}
+	for i, arg := range args.Snapshots {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// SetSnapshotFailures records the reasons that the specified snapshots
+// could not be taken or destroyed.
+func (s *StorageProvisionerAPI) SetSnapshotFailures(args params.SnapshotFailures) (params.ErrorResults, error) {
+	canAccess, err := s.getSnapshotAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Failures)),
+	}
+	one := func(arg params.SnapshotFailure) error {
+		if !canAccess(arg.Id) {
+			return common.ErrPerm
+		}
+		err := s.st.SetSnapshotFailure(arg.Id, arg.Reason)
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		}
+		return errors.Trace(err)
+	}
+	for i, arg := range args.Failures {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// RemoveSnapshots removes the snapshots with the specified IDs from
+// state, once they have been destroyed.
+func (s *StorageProvisionerAPI) RemoveSnapshots(args params.SnapshotIds) (params.ErrorResults, error) {
+	canAccess, err := s.getSnapshotAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	one := func(id string) error {
+		if !canAccess(id) {
+			return common.ErrPerm
+		}
+		return s.st.RemoveSnapshot(id)
+	}
+	for i, id := range args.Ids {
+		err := one(id)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// getSnapshotAuthFunc returns a function that validates access by
+// the authenticated user to the snapshot with a given ID. Snapshots
+// are scoped the same as the volumes they are taken of, and have IDs
+// of the same form.
+func (s *StorageProvisionerAPI) getSnapshotAuthFunc() (func(string) bool, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return nil, err
+	}
+	return func(id string) bool {
+		if !names.IsValidVolume(id) {
+			return false
+		}
+		return canAccess(names.NewVolumeTag(id))
+	}, nil
+}
+
 // SetFilesystemInfo records the details of newly provisioned filesystems.
 func (s *StorageProvisionerAPI) SetFilesystemInfo(args params.Filesystems) (params.ErrorResults, error) {
 	canAccessFilesystem, err := s.getStorageEntityAuthFunc()
@@ -980,3 +1282,40 @@ func (s *StorageProvisionerAPI) AttachmentLife(args params.MachineStorageIds) (p
 	}
 	return results, nil
 }
+
+// RemoveAttachment removes the specified machine storage attachments
+// from state, once they have been detached.
+func (s *StorageProvisionerAPI) RemoveAttachment(args params.MachineStorageIds) (params.ErrorResults, error) {
+	canAccess, err := s.getAttachmentAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	one := func(arg params.MachineStorageId) error {
+		machineTag, err := names.ParseMachineTag(arg.MachineTag)
+		if err != nil {
+			return err
+		}
+		attachmentTag, err := names.ParseTag(arg.AttachmentTag)
+		if err != nil {
+			return err
+		}
+		if !canAccess(machineTag, attachmentTag) {
+			return common.ErrPerm
+		}
+		switch attachmentTag := attachmentTag.(type) {
+		case names.VolumeTag:
+			return s.st.RemoveVolumeAttachment(machineTag, attachmentTag)
+		case names.FilesystemTag:
+			return s.st.RemoveFilesystemAttachment(machineTag, attachmentTag)
+		}
+		return common.ErrPerm
+	}
+	for i, arg := range args.Ids {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
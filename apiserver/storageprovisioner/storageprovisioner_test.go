@@ -333,6 +333,181 @@ func (s *provisionerSuite) TestVolumeParams(c *gc.C) {
 	})
 }
 
+func (s *provisionerSuite) TestVolumeResizeParams(c *gc.C) {
+	s.setupVolumes(c)
+	err := s.State.ResizeVolume(names.NewVolumeTag("2"), 8192)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.VolumeResizeParams(params.Entities{
+		Entities: []params.Entity{{"volume-2"}, {"volume-0-0"}, {"volume-42"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	result := results.Results[0].Result
+	c.Assert(result.VolumeTag, gc.Equals, "volume-2")
+	c.Assert(result.VolumeId, gc.Equals, "def")
+	c.Assert(result.Size, gc.Equals, uint64(8192))
+	c.Assert(result.Provider, gc.Equals, "environscoped")
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `pending resize for volume "0/0" not found`)
+	c.Assert(results.Results[2].Error, jc.DeepEquals, &params.Error{"permission denied", "unauthorized access"})
+}
+
+func (s *provisionerSuite) TestFailVolumeResizes(c *gc.C) {
+	s.setupVolumes(c)
+	err := s.State.ResizeVolume(names.NewVolumeTag("2"), 8192)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.FailVolumeResizes(params.VolumeResizeFailures{
+		Failures: []params.VolumeResizeFailure{
+			{VolumeTag: "volume-2", Reason: "no can do"},
+			{VolumeTag: "volume-42", Reason: "no can do"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+	volume, err := s.State.Volume(names.NewVolumeTag("2"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := volume.PendingResize()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(volume.ResizeFailure(), gc.Equals, "no can do")
+}
+
+func (s *provisionerSuite) TestSetVolumeInfoResized(c *gc.C) {
+	s.setupVolumes(c)
+	err := s.State.ResizeVolume(names.NewVolumeTag("2"), 8192)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The pool is not supplied by the provisioner, and is
+	// preserved when updating the info of a provisioned volume.
+	results, err := s.api.SetVolumeInfo(params.Volumes{
+		Volumes: []params.Volume{{
+			VolumeTag: "volume-2",
+			VolumeId:  "def",
+			Serial:    "456",
+			Size:      8192,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	volume, err := s.State.Volume(names.NewVolumeTag("2"))
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := volume.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Size, gc.Equals, uint64(8192))
+	c.Assert(info.Pool, gc.Equals, "environscoped")
+	_, ok := volume.PendingResize()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *provisionerSuite) TestSnapshotParams(c *gc.C) {
+	s.setupVolumes(c)
+	snapshot, err := s.State.AddSnapshot(names.NewVolumeTag("2"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetSnapshotInfo(snapshot.Id(), state.SnapshotInfo{SnapshotId: "snap-abc"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSnapshot(names.NewVolumeTag("0/0"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.SnapshotParams(params.SnapshotIds{
+		Ids: []string{"0", "0/1", "1/2", "42", "invalid"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.SnapshotParamsResults{
+		Results: []params.SnapshotParamsResult{
+			{Result: params.SnapshotParams{
+				Id:         "0",
+				Life:       params.Alive,
+				VolumeTag:  "volume-2",
+				VolumeId:   "def",
+				Size:       4096,
+				Provider:   "environscoped",
+				SnapshotId: "snap-abc",
+			}},
+			{Result: params.SnapshotParams{
+				Id:        "0/1",
+				Life:      params.Alive,
+				VolumeTag: "volume-0-0",
+				VolumeId:  "abc",
+				Size:      1024,
+				Provider:  "machinescoped",
+			}},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{`snapshot "42" not found`, "not found"}},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+}
+
+func (s *provisionerSuite) TestSetSnapshotInfoAndRemove(c *gc.C) {
+	s.setupVolumes(c)
+	snapshot, err := s.State.AddSnapshot(names.NewVolumeTag("2"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.SetSnapshotInfo(params.Snapshots{
+		Snapshots: []params.Snapshot{{
+			Id:         snapshot.Id(),
+			VolumeTag:  "volume-2",
+			SnapshotId: "snap-abc",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	snapshot, err = s.State.Snapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := snapshot.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.SnapshotId, gc.Equals, "snap-abc")
+
+	results, err = s.api.RemoveSnapshots(params.SnapshotIds{Ids: []string{snapshot.Id()}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `cannot remove snapshot "0": snapshot is alive`)
+
+	err = s.State.DestroySnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	results, err = s.api.RemoveSnapshots(params.SnapshotIds{Ids: []string{snapshot.Id()}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	_, err = s.State.Snapshot(snapshot.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *provisionerSuite) TestSetSnapshotFailures(c *gc.C) {
+	s.setupVolumes(c)
+	snapshot, err := s.State.AddSnapshot(names.NewVolumeTag("2"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.SetSnapshotFailures(params.SnapshotFailures{
+		Failures: []params.SnapshotFailure{
+			{Id: snapshot.Id(), Reason: "no can do"},
+			{Id: "1/2", Reason: "no can do"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{"permission denied", "unauthorized access"}},
+		},
+	})
+
+	paramsResults, err := s.api.SnapshotParams(params.SnapshotIds{Ids: []string{snapshot.Id()}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(paramsResults.Results[0].Error, gc.IsNil)
+	c.Assert(paramsResults.Results[0].Result.Failure, gc.Equals, "no can do")
+}
+
 func (s *provisionerSuite) TestVolumeParamsEmptyArgs(c *gc.C) {
 	results, err := s.api.VolumeParams(params.Entities{})
 	c.Assert(err, jc.ErrorIsNil)
@@ -813,6 +988,36 @@ func (s *provisionerSuite) TestVolumeBlockDevices(c *gc.C) {
 	})
 }
 
+func (s *provisionerSuite) TestMachineBlockDevices(c *gc.C) {
+	s.setupVolumes(c)
+
+	machine0, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine0.SetMachineBlockDevices(state.BlockDeviceInfo{
+		DeviceName:  "sdb",
+		Size:        123,
+		Partitioned: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{"machine-0"}, {"machine-1"}, {"service-mysql"},
+	}}
+	results, err := s.api.MachineBlockDevices(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.BlockDevicesResults{
+		Results: []params.BlockDevicesResult{
+			{Result: []storage.BlockDevice{{
+				DeviceName:  "sdb",
+				Size:        123,
+				Partitioned: true,
+			}}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: &params.Error{Message: `"service-mysql" is not a valid machine tag`}},
+		},
+	})
+}
+
 func (s *provisionerSuite) TestLife(c *gc.C) {
 	s.setupVolumes(c)
 	args := params.Entities{Entities: []params.Entity{{"volume-0-0"}, {"volume-1"}, {"volume-42"}}}
@@ -860,6 +1065,29 @@ func (s *provisionerSuite) TestAttachmentLife(c *gc.C) {
 	})
 }
 
+func (s *provisionerSuite) TestRemoveAttachment(c *gc.C) {
+	s.setupVolumes(c)
+	s.authorizer.EnvironManager = true
+
+	results, err := s.api.RemoveAttachment(params.MachineStorageIds{
+		Ids: []params.MachineStorageId{{
+			MachineTag:    "machine-0",
+			AttachmentTag: "volume-0-0",
+		}, {
+			MachineTag:    "machine-0",
+			AttachmentTag: "volume-42",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: &params.Error{Message: `cannot remove attachment of volume "0/0" to machine "0": volume attachment is alive`}},
+			// Removing a non-existent attachment is a no-op.
+			{Error: nil},
+		},
+	})
+}
+
 func (s *provisionerSuite) TestEnsureDead(c *gc.C) {
 	s.setupVolumes(c)
 	args := params.Entities{Entities: []params.Entity{{"volume-0-0"}, {"volume-1"}, {"volume-42"}}}
@@ -18,3 +18,9 @@ func NewStorageAPI(
 ) (*StorageAPI, error) {
 	return newStorageAPI(storageStateInterface(st), resources, accessUnit)
 }
+
+// PatchIsLeader replaces the function used by api to determine
+// whether a unit is the leader of its service.
+func PatchIsLeader(api *UniterAPIV3, isLeader func(serviceId, unitId string) bool) {
+	api.isLeader = isLeader
+}
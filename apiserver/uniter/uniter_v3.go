@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The uniter package implements the API interface used by the uniter
+// worker. This file contains the API facade version 3.
+
+package uniter
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/lease"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Uniter", 3, NewUniterAPIV3)
+}
+
+// UniterAPIV3 implements the API version 3, used by the uniter worker.
+type UniterAPIV3 struct {
+	UniterAPIV2
+
+	isLeader func(serviceId, unitId string) bool
+}
+
+// NewUniterAPIV3 creates a new instance of the Uniter API, version 3.
+func NewUniterAPIV3(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*UniterAPIV3, error) {
+	baseAPI, err := NewUniterAPIV2(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	ldrMgr := leadership.NewLeadershipManager(lease.Manager())
+	return &UniterAPIV3{
+		UniterAPIV2: *baseAPI,
+		isLeader:    ldrMgr.Leader,
+	}, nil
+}
+
+// SetServiceStatus sets the status of the service of each unit passed
+// in args. Only the leader unit of a service may set its status.
+func (u *UniterAPIV3) SetServiceStatus(args params.SetStatus) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Entities {
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = u.setServiceStatus(tag, arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV3) setServiceStatus(tag names.UnitTag, arg params.EntityStatus) error {
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return err
+	}
+	serviceName := unit.ServiceName()
+	if !u.isLeader(serviceName, unit.Name()) {
+		return common.ErrPerm
+	}
+	service, err := u.st.Service(serviceName)
+	if err != nil {
+		return err
+	}
+	return service.SetStatus(state.Status(arg.Status), arg.Info, arg.Data)
+}
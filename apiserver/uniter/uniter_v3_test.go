@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/uniter"
+	"github.com/juju/juju/state"
+)
+
+type uniterV3Suite struct {
+	uniterBaseSuite
+	uniter *uniter.UniterAPIV3
+	leader bool
+}
+
+var _ = gc.Suite(&uniterV3Suite{})
+
+func (s *uniterV3Suite) SetUpTest(c *gc.C) {
+	s.uniterBaseSuite.setUpTest(c)
+
+	uniterAPIV3, err := uniter.NewUniterAPIV3(
+		s.State,
+		s.resources,
+		s.authorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.leader = true
+	uniter.PatchIsLeader(uniterAPIV3, func(serviceId, unitId string) bool {
+		c.Check(serviceId, gc.Equals, "wordpress")
+		c.Check(unitId, gc.Equals, "wordpress/0")
+		return s.leader
+	})
+	s.uniter = uniterAPIV3
+}
+
+func (s *uniterV3Suite) TestSetServiceStatus(c *gc.C) {
+	args := params.SetStatus{
+		Entities: []params.EntityStatus{
+			{Tag: "unit-mysql-0", Status: params.StatusBlocked, Info: "not really"},
+			{Tag: "unit-wordpress-0", Status: params.StatusBlocked, Info: "need db"},
+			{Tag: "service-wordpress", Status: params.StatusActive},
+		}}
+	result, err := s.uniter.SetServiceStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	statusInfo, err := s.wordpress.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Equals, state.StatusBlocked)
+	c.Assert(statusInfo.Message, gc.Equals, "need db")
+	statusInfo, err = s.mysql.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Not(gc.Equals), state.StatusBlocked)
+}
+
+func (s *uniterV3Suite) TestSetServiceStatusNotLeader(c *gc.C) {
+	s.leader = false
+	args := params.SetStatus{
+		Entities: []params.EntityStatus{
+			{Tag: "unit-wordpress-0", Status: params.StatusBlocked, Info: "need db"},
+		}}
+	result, err := s.uniter.SetServiceStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	statusInfo, err := s.wordpress.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Not(gc.Equals), state.StatusBlocked)
+}
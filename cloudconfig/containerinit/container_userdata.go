@@ -38,7 +38,7 @@ func WriteUserData(
 	networkConfig *container.NetworkConfig,
 	directory string,
 ) (string, error) {
-	userData, err := cloudInitUserData(instanceConfig, networkConfig)
+	userData, err := CloudInitUserData(instanceConfig, networkConfig)
 	if err != nil {
 		logger.Errorf("failed to create user data: %v", err)
 		return "", err
@@ -125,7 +125,9 @@ func newCloudInitConfigWithNetworks(series string, networkConfig *container.Netw
 	return cloudConfig, nil
 }
 
-func cloudInitUserData(
+// CloudInitUserData returns the serialized cloud-init user-data for a
+// container, generated using the specified machine and network config.
+func CloudInitUserData(
 	instanceConfig *instancecfg.InstanceConfig,
 	networkConfig *container.NetworkConfig,
 ) ([]byte, error) {
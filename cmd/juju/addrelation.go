@@ -7,15 +7,18 @@ import (
 	"fmt"
 
 	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
 )
 
 // AddRelationCommand adds a relation between two service endpoints.
 type AddRelationCommand struct {
 	envcmd.EnvCommandBase
 	Endpoints []string
+	DryRun    bool
 }
 
 func (c *AddRelationCommand) Info() *cmd.Info {
@@ -23,9 +26,19 @@ func (c *AddRelationCommand) Info() *cmd.Info {
 		Name:    "add-relation",
 		Args:    "<service1>[:<relation name1>] <service2>[:<relation name2>]",
 		Purpose: "add a relation between two services",
+		Doc:     addRelationDoc,
 	}
 }
 
+const addRelationDoc = `
+With --dry-run, the relation is checked, and the units that would join it are
+reported, but the relation is not added.
+`
+
+func (c *AddRelationCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.DryRun, "dry-run", false, common.DryRunDoc)
+}
+
 func (c *AddRelationCommand) Init(args []string) error {
 	if len(args) != 2 {
 		return fmt.Errorf("a relation must involve two services")
@@ -34,12 +47,19 @@ func (c *AddRelationCommand) Init(args []string) error {
 	return nil
 }
 
-func (c *AddRelationCommand) Run(_ *cmd.Context) error {
+func (c *AddRelationCommand) Run(ctx *cmd.Context) error {
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return err
 	}
 	defer client.Close()
+	if c.DryRun {
+		plan, err := client.PlanAddRelation(c.Endpoints...)
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		return common.WritePlan(ctx, plan)
+	}
 	_, err = client.AddRelation(c.Endpoints...)
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -161,6 +162,25 @@ func (s *AddRelationSuite) TestAddRelation(c *gc.C) {
 	}
 }
 
+func (s *AddRelationSuite) TestDryRun(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	err := runDeploy(c, "local:wordpress", "wp")
+	c.Assert(err, jc.ErrorIsNil)
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql")
+	err = runDeploy(c, "local:mysql", "ms")
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AddRelationCommand{}), "wp", "ms", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"dry run, no changes made; the command would:\n"+
+		"  add relation wp:db ms:server\n"+
+		"units affected: wp/0, ms/0\n",
+	)
+	_, err = s.State.KeyRelation("wp:db ms:server")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *AddRelationSuite) TestBlockAddRelation(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
 	err := runDeploy(c, "local:wordpress", "wp")
@@ -0,0 +1,56 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscale
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const autoscaleCommandDoc = `
+"juju autoscale" manages the policies by which the number of units of a
+service is adjusted according to a metric its units report with
+add-metric.
+
+A policy tracks one metric declared by the service's charm, averaged
+across the service's units over a window of time. While the average is
+above the policy's threshold, units are added; while fewer units could
+carry the load without exceeding it, units are destroyed, newest first.
+The number of units is kept between the policy's minimum and maximum,
+and no change is made within the cooldown period of the previous one.
+
+Each change is recorded, and may be inspected with "juju autoscale show".
+`
+
+const autoscaleCommandPurpose = "manage service autoscaling policies"
+
+// NewSuperCommand creates the autoscale supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	autoscaleCmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "autoscale",
+		Doc:         autoscaleCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     autoscaleCommandPurpose,
+	})
+	autoscaleCmd.Register(envcmd.Wrap(&SetCommand{}))
+	autoscaleCmd.Register(envcmd.Wrap(&ShowCommand{}))
+	autoscaleCmd.Register(envcmd.Wrap(&ClearCommand{}))
+	return autoscaleCmd
+}
+
+// AutoscaleAPI defines the client API methods used by the autoscale
+// commands.
+type AutoscaleAPI interface {
+	Close() error
+	SetAutoscalePolicy(service string, policy params.AutoscalePolicy) error
+	ClearAutoscalePolicy(service string) error
+	Autoscale(service string, historySize int) (params.AutoscaleResult, error)
+}
+
+var getAutoscaleAPI = func(c *envcmd.EnvCommandBase) (AutoscaleAPI, error) {
+	return c.NewAPIClient()
+}
@@ -0,0 +1,211 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscale_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/autoscale"
+	"github.com/juju/juju/testing"
+)
+
+type AutoscaleSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeAutoscaleAPI
+}
+
+var _ = gc.Suite(&AutoscaleSuite{})
+
+func (s *AutoscaleSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeAutoscaleAPI{}
+	s.PatchValue(autoscale.GetAutoscaleAPI, func(*envcmd.EnvCommandBase) (autoscale.AutoscaleAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *AutoscaleSuite) run(c *gc.C, command cmd.Command, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *AutoscaleSuite) TestSetInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no service name specified",
+	}, {
+		args: []string{"web!", "--metric", "pings", "--threshold", "10", "--max", "3"},
+		err:  `invalid service name "web!"`,
+	}, {
+		args: []string{"web", "--threshold", "10", "--max", "3"},
+		err:  "no metric specified",
+	}, {
+		args: []string{"web", "--metric", "pings", "--max", "3"},
+		err:  "threshold must be positive",
+	}, {
+		args: []string{"web", "--metric", "pings", "--threshold", "10"},
+		err:  "maximum units must be specified",
+	}, {
+		args: []string{"web", "--metric", "pings", "--threshold", "10", "--min", "4", "--max", "3"},
+		err:  "minimum units must be between 0 and 3",
+	}, {
+		args: []string{"web", "--metric", "pings", "--threshold", "10", "--max", "3", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"web", "--metric", "pings", "--threshold", "10", "--max", "3"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&autoscale.SetCommand{}), test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *AutoscaleSuite) TestSet(c *gc.C) {
+	_, err := s.run(c, &autoscale.SetCommand{}, "web",
+		"--metric", "pings", "--threshold", "200", "--min", "2", "--max", "10", "--window", "1m", "--cooldown", "90s")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.service, gc.Equals, "web")
+	c.Assert(s.api.policy, jc.DeepEquals, params.AutoscalePolicy{
+		Metric:    "pings",
+		Threshold: 200,
+		Window:    time.Minute,
+		MinUnits:  2,
+		MaxUnits:  10,
+		Cooldown:  90 * time.Second,
+	})
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *AutoscaleSuite) TestSetDefaults(c *gc.C) {
+	_, err := s.run(c, &autoscale.SetCommand{}, "web", "--metric", "pings", "--threshold", "200", "--max", "10")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.policy.Window, gc.Equals, 5*time.Minute)
+	c.Assert(s.api.policy.Cooldown, gc.Equals, 5*time.Minute)
+	c.Assert(s.api.policy.MinUnits, gc.Equals, 1)
+}
+
+func (s *AutoscaleSuite) TestClear(c *gc.C) {
+	_, err := s.run(c, &autoscale.ClearCommand{}, "web")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.cleared, gc.Equals, "web")
+
+	_, err = s.run(c, &autoscale.ClearCommand{})
+	c.Assert(err, gc.ErrorMatches, "no service name specified")
+}
+
+func (s *AutoscaleSuite) TestShow(c *gc.C) {
+	s.api.result = params.AutoscaleResult{
+		Policy: &params.AutoscalePolicy{
+			Metric:    "pings",
+			Threshold: 100,
+			Window:    5 * time.Minute,
+			MinUnits:  1,
+			MaxUnits:  5,
+			Cooldown:  10 * time.Minute,
+		},
+		History: []params.AutoscaleDecision{{
+			Time:      time.Date(2015, 4, 1, 13, 0, 0, 0, time.UTC),
+			Metric:    "pings",
+			Value:     20,
+			FromUnits: 3,
+			ToUnits:   1,
+			Reason:    "average pings 20 below threshold 100",
+		}, {
+			Time:      time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC),
+			Metric:    "pings",
+			Value:     250,
+			FromUnits: 1,
+			ToUnits:   3,
+			Reason:    "average pings 250 above threshold 100",
+		}},
+	}
+	ctx, err := s.run(c, &autoscale.ShowCommand{}, "web", "-n", "5")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"SERVICE METRIC THRESHOLD WINDOW MIN MAX COOLDOWN\n"+
+		"web     pings  100       5m0s   1   5   10m0s\n"+
+		"\n"+
+		"TIME                 UNITS REASON\n"+
+		"2015-04-01T13:00:00Z 3->1  average pings 20 below threshold 100\n"+
+		"2015-04-01T12:00:00Z 1->3  average pings 250 above threshold 100\n",
+	)
+	c.Assert(s.api.service, gc.Equals, "web")
+	c.Assert(s.api.historySize, gc.Equals, 5)
+}
+
+func (s *AutoscaleSuite) TestShowNotAutoscaled(c *gc.C) {
+	ctx, err := s.run(c, &autoscale.ShowCommand{}, "web")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "service \"web\" is not autoscaled\n")
+	c.Assert(s.api.historySize, gc.Equals, 10)
+}
+
+func (s *AutoscaleSuite) TestShowYAML(c *gc.C) {
+	s.api.result = params.AutoscaleResult{
+		Policy: &params.AutoscalePolicy{
+			Metric:    "pings",
+			Threshold: 100,
+			Window:    5 * time.Minute,
+			MinUnits:  1,
+			MaxUnits:  5,
+		},
+	}
+	ctx, err := s.run(c, &autoscale.ShowCommand{}, "web", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"service: web\n"+
+		"policy:\n"+
+		"  metric: pings\n"+
+		"  threshold: 100\n"+
+		"  window: 5m0s\n"+
+		"  min-units: 1\n"+
+		"  max-units: 5\n"+
+		"  cooldown: 0s\n",
+	)
+}
+
+func (s *AutoscaleSuite) TestShowInvalidSize(c *gc.C) {
+	_, err := s.run(c, &autoscale.ShowCommand{}, "web", "-n", "0")
+	c.Assert(err, gc.ErrorMatches, "invalid history size 0")
+}
+
+type fakeAutoscaleAPI struct {
+	service     string
+	policy      params.AutoscalePolicy
+	cleared     string
+	historySize int
+	result      params.AutoscaleResult
+	closed      bool
+}
+
+func (f *fakeAutoscaleAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeAutoscaleAPI) SetAutoscalePolicy(service string, policy params.AutoscalePolicy) error {
+	f.service, f.policy = service, policy
+	return nil
+}
+
+func (f *fakeAutoscaleAPI) ClearAutoscalePolicy(service string) error {
+	f.cleared = service
+	return nil
+}
+
+func (f *fakeAutoscaleAPI) Autoscale(service string, historySize int) (params.AutoscaleResult, error) {
+	f.service, f.historySize = service, historySize
+	return f.result, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscale
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// ClearCommand removes the autoscale policy of a service.
+type ClearCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+}
+
+// Info implements Command.Info.
+func (c *ClearCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "clear",
+		Args:    "<service>",
+		Purpose: "remove the autoscale policy of a service",
+		Doc:     "Stop autoscaling a service. Its units, and its autoscale history, are kept.",
+	}
+}
+
+// Init implements Command.Init.
+func (c *ClearCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	c.ServiceName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *ClearCommand) Run(_ *cmd.Context) error {
+	api, err := getAutoscaleAPI(&c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return block.ProcessBlockedError(api.ClearAutoscalePolicy(c.ServiceName), block.BlockChange)
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscale
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const setCommandDoc = `
+Set the policy by which the number of units of a service is adjusted,
+replacing any existing policy. The metric must be declared by the
+service's charm, and the threshold is the value of the metric, averaged
+across the service's units, that each unit should be kept at.
+
+Example:
+
+    juju autoscale set web --metric requests-per-second --threshold 200 \
+        --min 2 --max 10 --window 5m --cooldown 10m
+`
+
+// SetCommand sets the autoscale policy of a service.
+type SetCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Policy      params.AutoscalePolicy
+}
+
+// Info implements Command.Info.
+func (c *SetCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set",
+		Args:    "<service>",
+		Purpose: "set the autoscale policy of a service",
+		Doc:     setCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *SetCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Policy.Metric, "metric", "", "the metric to track")
+	f.Float64Var(&c.Policy.Threshold, "threshold", 0, "the average value of the metric to keep each unit at")
+	f.DurationVar(&c.Policy.Window, "window", 5*time.Minute, "the period over which the metric is averaged")
+	f.IntVar(&c.Policy.MinUnits, "min", 1, "the minimum number of units")
+	f.IntVar(&c.Policy.MaxUnits, "max", 0, "the maximum number of units")
+	f.DurationVar(&c.Policy.Cooldown, "cooldown", 5*time.Minute, "the minimum time between changes")
+}
+
+// Init implements Command.Init.
+func (c *SetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	c.ServiceName = args[0]
+	if !names.IsValidService(c.ServiceName) {
+		return errors.Errorf("invalid service name %q", c.ServiceName)
+	}
+	switch {
+	case c.Policy.Metric == "":
+		return errors.New("no metric specified")
+	case c.Policy.Threshold <= 0:
+		return errors.New("threshold must be positive")
+	case c.Policy.MaxUnits < 1:
+		return errors.New("maximum units must be specified")
+	case c.Policy.MinUnits < 0 || c.Policy.MinUnits > c.Policy.MaxUnits:
+		return errors.Errorf("minimum units must be between 0 and %d", c.Policy.MaxUnits)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *SetCommand) Run(_ *cmd.Context) error {
+	api, err := getAutoscaleAPI(&c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	err = api.SetAutoscalePolicy(c.ServiceName, c.Policy)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
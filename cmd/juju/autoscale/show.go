@@ -0,0 +1,154 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscale
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const showCommandDoc = `
+Show the autoscale policy of a service, if it has one, and the most
+recent changes made to its number of units under its policy, newest
+first.
+`
+
+// ShowCommand shows the autoscale policy and history of a service.
+type ShowCommand struct {
+	envcmd.EnvCommandBase
+	out         cmd.Output
+	ServiceName string
+	Size        int
+}
+
+// Info implements Command.Info.
+func (c *ShowCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show",
+		Args:    "<service>",
+		Purpose: "show the autoscale policy and history of a service",
+		Doc:     showCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ShowCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.IntVar(&c.Size, "n", 10, "size of the history to show")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAutoscaleTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *ShowCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	if c.Size < 1 {
+		return errors.Errorf("invalid history size %d", c.Size)
+	}
+	c.ServiceName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *ShowCommand) Run(ctx *cmd.Context) error {
+	api, err := getAutoscaleAPI(&c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	result, err := api.Autoscale(c.ServiceName, c.Size)
+	if err != nil {
+		return err
+	}
+	output := AutoscaleInfo{Service: c.ServiceName}
+	if p := result.Policy; p != nil {
+		output.Policy = &PolicyInfo{
+			Metric:    p.Metric,
+			Threshold: p.Threshold,
+			Window:    p.Window.String(),
+			MinUnits:  p.MinUnits,
+			MaxUnits:  p.MaxUnits,
+			Cooldown:  p.Cooldown.String(),
+		}
+	}
+	for _, d := range result.History {
+		output.History = append(output.History, DecisionInfo{
+			Time:      d.Time.Format(time.RFC3339),
+			Metric:    d.Metric,
+			Value:     d.Value,
+			FromUnits: d.FromUnits,
+			ToUnits:   d.ToUnits,
+			Reason:    d.Reason,
+		})
+	}
+	return c.out.Write(ctx, output)
+}
+
+// AutoscaleInfo defines the serialization behaviour of the autoscale
+// policy and history of a service.
+type AutoscaleInfo struct {
+	Service string         `yaml:"service" json:"service"`
+	Policy  *PolicyInfo    `yaml:"policy,omitempty" json:"policy,omitempty"`
+	History []DecisionInfo `yaml:"history,omitempty" json:"history,omitempty"`
+}
+
+// PolicyInfo defines the serialization behaviour of an autoscale
+// policy.
+type PolicyInfo struct {
+	Metric    string  `yaml:"metric" json:"metric"`
+	Threshold float64 `yaml:"threshold" json:"threshold"`
+	Window    string  `yaml:"window" json:"window"`
+	MinUnits  int     `yaml:"min-units" json:"min-units"`
+	MaxUnits  int     `yaml:"max-units" json:"max-units"`
+	Cooldown  string  `yaml:"cooldown" json:"cooldown"`
+}
+
+// DecisionInfo defines the serialization behaviour of an autoscale
+// decision.
+type DecisionInfo struct {
+	Time      string  `yaml:"time" json:"time"`
+	Metric    string  `yaml:"metric" json:"metric"`
+	Value     float64 `yaml:"value" json:"value"`
+	FromUnits int     `yaml:"from-units" json:"from-units"`
+	ToUnits   int     `yaml:"to-units" json:"to-units"`
+	Reason    string  `yaml:"reason" json:"reason"`
+}
+
+func formatAutoscaleTabular(value interface{}) ([]byte, error) {
+	info, ok := value.(AutoscaleInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", info, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	if p := info.Policy; p != nil {
+		fmt.Fprintln(tw, "SERVICE\tMETRIC\tTHRESHOLD\tWINDOW\tMIN\tMAX\tCOOLDOWN")
+		fmt.Fprintf(tw, "%s\t%s\t%v\t%s\t%d\t%d\t%s\n",
+			info.Service, p.Metric, p.Threshold, p.Window, p.MinUnits, p.MaxUnits, p.Cooldown)
+	} else {
+		fmt.Fprintf(tw, "service %q is not autoscaled\n", info.Service)
+	}
+	if len(info.History) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "TIME\tUNITS\tREASON")
+		for _, d := range info.History {
+			fmt.Fprintf(tw, "%s\t%d->%d\t%s\n", d.Time, d.FromUnits, d.ToUnits, d.Reason)
+		}
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
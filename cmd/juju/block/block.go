@@ -4,6 +4,8 @@
 package block
 
 import (
+	"time"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"launchpad.net/gnuflag"
@@ -15,7 +17,10 @@ import (
 // commands that enable blocks.
 type BaseBlockCommand struct {
 	envcmd.EnvCommandBase
-	desc string
+	desc    string
+	scope   blockScope
+	entity  string
+	expires time.Duration
 }
 
 // Init initializes the command.
@@ -28,7 +33,12 @@ func (c *BaseBlockCommand) Init(args []string) error {
 	if len(args) == 1 {
 		c.desc = args[0]
 	}
-	return nil
+	if c.expires < 0 {
+		return errors.New("--expires must be positive")
+	}
+	var err error
+	c.entity, err = c.scope.entity()
+	return err
 }
 
 // internalRun blocks commands from running successfully.
@@ -39,18 +49,31 @@ func (c *BaseBlockCommand) internalRun(operation string) error {
 	}
 	defer client.Close()
 
-	return client.SwitchBlockOn(TypeFromOperation(operation), c.desc)
+	var expiry *time.Time
+	if c.expires > 0 {
+		t := time.Now().Add(c.expires).UTC()
+		expiry = &t
+	}
+	return client.SwitchEntityBlockOn(TypeFromOperation(operation), c.desc, c.entity, expiry)
 }
 
 // SetFlags implements Command.SetFlags.
 func (c *BaseBlockCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.EnvCommandBase.SetFlags(f)
+	f.DurationVar(&c.expires, "expires", 0, "switch the block off automatically after this long, e.g. 2h")
+}
+
+// setScopeFlags adds the flags that restrict the block to
+// a single service or machine.
+func (c *BaseBlockCommand) setScopeFlags(f *gnuflag.FlagSet) {
+	c.SetFlags(f)
+	c.scope.setFlags(f)
 }
 
 // BlockClientAPI defines the client API methods that block command uses.
 type BlockClientAPI interface {
 	Close() error
-	SwitchBlockOn(blockType, msg string) error
+	SwitchEntityBlockOn(blockType, msg, entity string, expiry *time.Time) error
 }
 
 var getBlockClientAPI = func(p *BaseBlockCommand) (BlockClientAPI, error) {
@@ -70,11 +93,17 @@ To disable the block, run unblock command - see "juju help unblock".
 To by-pass the block, run destroy-enviornment with --force option.
 
 "juju block destroy-environment" only blocks destroy-environment command.
+
+With --expires, the block is switched off automatically after the
+given duration.
    
 Examples:
    To prevent the environment from being destroyed:
    juju block destroy-environment
 
+   To prevent the environment from being destroyed for the next day:
+   juju block destroy-environment --expires 24h
+
 `
 
 // Info provides information about command.
@@ -111,11 +140,22 @@ To by-pass the block, where available, run desired remove command with --force o
     remove-relation
     remove-service
     remove-unit
+
+With --service or --machine, only removal of the given service, its
+units and relations, or of the given machine, is blocked. With
+--expires, the block is switched off automatically after the given
+duration.
    
 Examples:
    To prevent the machines, services, units and relations from being removed:
    juju block remove-object
 
+   To prevent the postgresql service from being removed:
+   juju block remove-object --service postgresql "holds the customer data"
+
+   To prevent machine 3 from being removed for the next two hours:
+   juju block remove-object --machine 3 --expires 2h
+
 `
 
 // Info provides information about command.
@@ -128,6 +168,11 @@ func (c *RemoveCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *RemoveCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.setScopeFlags(f)
+}
+
 // Satisfying Command interface.
 func (c *RemoveCommand) Run(_ *cmd.Context) error {
 	return c.internalRun(c.Info().Name)
@@ -177,11 +222,19 @@ To by-pass the block, where available, run desired remove command with --force o
     user change-password
     user disable
     user enable
+
+With --service or --machine, only the operations above that change
+the given service, its units and relations, or the given machine,
+are blocked. With --expires, the block is switched off automatically
+after the given duration.
    
 Examples:
    To prevent changes to the environment:
    juju block all-changes
 
+   To prevent changes to the mysql service during a maintenance window:
+   juju block all-changes --service mysql --expires 1h
+
 `
 
 // Info provides information about command.
@@ -194,6 +247,11 @@ func (c *ChangeCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *ChangeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.setScopeFlags(f)
+}
+
 // Satisfying Command interface.
 func (c *ChangeCommand) Run(_ *cmd.Context) error {
 	return c.internalRun(c.Info().Name)
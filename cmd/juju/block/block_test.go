@@ -5,6 +5,7 @@ package block_test
 
 import (
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -74,6 +75,40 @@ func (s *BlockCommandSuite) TestBlockChangeOperations(c *gc.C) {
 	s.assertBlock(c, command.Info().Name, "TestBlockChangeOperations")
 }
 
+func (s *BlockCommandSuite) TestBlockServiceRemoveOperations(c *gc.C) {
+	command := block.RemoveCommand{}
+	_, err := testing.RunCommand(c, envcmd.Wrap(&command), "--service", "postgresql", "keep the data")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertBlock(c, command.Info().Name, "keep the data")
+	c.Assert(s.mockClient.Entity, gc.Equals, "service-postgresql")
+	c.Assert(s.mockClient.Expiry, gc.IsNil)
+}
+
+func (s *BlockCommandSuite) TestBlockMachineChangeOperationsExpires(c *gc.C) {
+	command := block.ChangeCommand{}
+	before := time.Now()
+	_, err := testing.RunCommand(c, envcmd.Wrap(&command), "--machine", "3", "--expires", "2h")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertBlock(c, command.Info().Name, "")
+	c.Assert(s.mockClient.Entity, gc.Equals, "machine-3")
+	c.Assert(s.mockClient.Expiry, gc.NotNil)
+	c.Assert(s.mockClient.Expiry.Before(before.Add(2*time.Hour)), jc.IsFalse)
+	c.Assert(s.mockClient.Expiry.After(time.Now().Add(2*time.Hour)), jc.IsFalse)
+}
+
+func (s *BlockCommandSuite) TestBlockCmdInvalidScope(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&block.RemoveCommand{}), "--service", "postgresql", "--machine", "0")
+	c.Assert(err, gc.ErrorMatches, "cannot specify both --service and --machine")
+	_, err = testing.RunCommand(c, envcmd.Wrap(&block.RemoveCommand{}), "--service", "Bad_Name")
+	c.Assert(err, gc.ErrorMatches, `invalid service name "Bad_Name"`)
+	_, err = testing.RunCommand(c, envcmd.Wrap(&block.ChangeCommand{}), "--machine", "x")
+	c.Assert(err, gc.ErrorMatches, `invalid machine id "x"`)
+	_, err = testing.RunCommand(c, envcmd.Wrap(&block.DestroyCommand{}), "--service", "postgresql")
+	c.Assert(err, gc.ErrorMatches, "flag provided but not defined: --service")
+	_, err = testing.RunCommand(c, envcmd.Wrap(&block.DestroyCommand{}), "--expires", "-1h")
+	c.Assert(err, gc.ErrorMatches, "--expires must be positive")
+}
+
 func (s *BlockCommandSuite) processErrorTest(c *gc.C, tstError error, blockType block.Block, expectedError error, expectedWarning string) {
 	if tstError != nil {
 		c.Assert(errors.Cause(block.ProcessBlockedError(tstError, blockType)), gc.Equals, expectedError)
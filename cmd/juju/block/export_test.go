@@ -3,7 +3,11 @@
 
 package block
 
-import "github.com/juju/juju/apiserver/params"
+import (
+	"time"
+
+	"github.com/juju/juju/apiserver/params"
+)
 
 var (
 	BlockClient   = &getBlockClientAPI
@@ -14,6 +18,9 @@ var (
 type MockBlockClient struct {
 	BlockType string
 	Msg       string
+	Entity    string
+	Expiry    *time.Time
+	CreatedBy string
 }
 
 func (c *MockBlockClient) Close() error {
@@ -21,14 +28,26 @@ func (c *MockBlockClient) Close() error {
 }
 
 func (c *MockBlockClient) SwitchBlockOn(blockType, msg string) error {
+	return c.SwitchEntityBlockOn(blockType, msg, "", nil)
+}
+
+func (c *MockBlockClient) SwitchEntityBlockOn(blockType, msg, entity string, expiry *time.Time) error {
 	c.BlockType = blockType
 	c.Msg = msg
+	c.Entity = entity
+	c.Expiry = expiry
 	return nil
 }
 
 func (c *MockBlockClient) SwitchBlockOff(blockType string) error {
+	return c.SwitchEntityBlockOff(blockType, "")
+}
+
+func (c *MockBlockClient) SwitchEntityBlockOff(blockType, entity string) error {
 	c.BlockType = blockType
 	c.Msg = ""
+	c.Entity = entity
+	c.Expiry = nil
 	return nil
 }
 
@@ -39,8 +58,11 @@ func (c *MockBlockClient) List() ([]params.Block, error) {
 
 	return []params.Block{
 		params.Block{
-			Type:    c.BlockType,
-			Message: c.Msg,
+			Tag:       c.Entity,
+			Type:      c.BlockType,
+			Message:   c.Msg,
+			Expiry:    c.Expiry,
+			CreatedBy: c.CreatedBy,
 		},
 	}, nil
 }
@@ -6,10 +6,13 @@ package block
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
@@ -20,6 +23,9 @@ const listCommandDoc = `
 List blocks for Juju environment.
 This command shows if each block type is enabled. 
 For enabled blocks, block message is shown if it was specified.
+Blocks on individual services and machines are listed after
+the environment blocks. The user who switched each block on,
+and when it expires, are shown if known.
 `
 
 // ListCommand list blocks.
@@ -80,24 +86,40 @@ var getBlockListAPI = func(p *ListCommand) (BlockListAPI, error) {
 // BlockInfo defines the serialization behaviour of the block information.
 type BlockInfo struct {
 	Operation string  `yaml:"block" json:"block"`
+	Entity    string  `yaml:"entity,omitempty" json:"entity,omitempty"`
 	Enabled   bool    `yaml:"enabled" json:"enabled"`
 	Message   *string `yaml:"message,omitempty" json:"message,omitempty"`
+	CreatedBy string  `yaml:"created-by,omitempty" json:"created-by,omitempty"`
+	Expires   string  `yaml:"expires,omitempty" json:"expires,omitempty"`
 }
 
 // formatBlockInfo takes a set of Block and creates a
-// mapping to information structures.
+// mapping to information structures. Blocks on the
+// environment come first, one for each operation,
+// followed by blocks on individual services and machines.
 func formatBlockInfo(all []params.Block) []BlockInfo {
 	output := make([]BlockInfo, len(blockArgs))
 
 	info := make(map[string]BlockInfo, len(all))
+	entityInfo := make(map[string][]BlockInfo)
 	// not all block types may be returned from client
 	for _, one := range all {
 		op := OperationFromType(one.Type)
+		msg := one.Message
 		bi := BlockInfo{
 			Operation: op,
 			// If client returned it, it means that it is enabled
-			Enabled: true,
-			Message: &one.Message,
+			Enabled:   true,
+			Message:   &msg,
+			CreatedBy: one.CreatedBy,
+		}
+		if one.Expiry != nil {
+			bi.Expires = one.Expiry.UTC().Format(time.RFC3339)
+		}
+		if entity := blockEntityName(one.Tag); entity != "" {
+			bi.Entity = entity
+			entityInfo[op] = append(entityInfo[op], bi)
+			continue
 		}
 		info[op] = bi
 	}
@@ -109,10 +131,28 @@ func formatBlockInfo(all []params.Block) []BlockInfo {
 		}
 		output[i] = BlockInfo{Operation: aType}
 	}
+	for _, aType := range blockArgs {
+		output = append(output, entityInfo[aType]...)
+	}
 
 	return output
 }
 
+// blockEntityName returns a description of the service or
+// machine that a block with the given tag applies to, or an
+// empty string if the block applies to the whole environment.
+func blockEntityName(tagString string) string {
+	tag, err := names.ParseTag(tagString)
+	if err != nil {
+		return ""
+	}
+	switch tag.(type) {
+	case names.ServiceTag, names.MachineTag:
+		return fmt.Sprintf("%s %s", tag.Kind(), tag.Id())
+	}
+	return ""
+}
+
 // formatBlocks returns block list representation.
 func formatBlocks(value interface{}) ([]byte, error) {
 	blocks, ok := value.([]BlockInfo)
@@ -129,12 +169,26 @@ func formatBlocks(value interface{}) ([]byte, error) {
 		if ablock.Enabled {
 			switched = "on"
 		}
-		fmt.Fprintf(tw, "%v\t", ablock.Operation)
-		if ablock.Message != nil {
-			fmt.Fprintf(tw, "\t=%v, %v", switched, *ablock.Message)
+		if ablock.Entity != "" {
+			fmt.Fprintf(tw, "%v (%v)\t", ablock.Operation, ablock.Entity)
+		} else {
+			fmt.Fprintf(tw, "%v\t", ablock.Operation)
+		}
+		if ablock.Message == nil {
+			fmt.Fprintf(tw, "\t=%v", switched)
 			continue
 		}
-		fmt.Fprintf(tw, "\t=%v", switched)
+		fmt.Fprintf(tw, "\t=%v, %v", switched, *ablock.Message)
+		var details []string
+		if ablock.CreatedBy != "" {
+			details = append(details, "set by "+ablock.CreatedBy)
+		}
+		if ablock.Expires != "" {
+			details = append(details, "expires "+ablock.Expires)
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(tw, " [%v]", strings.Join(details, ", "))
+		}
 	}
 
 	tw.Flush()
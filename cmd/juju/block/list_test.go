@@ -4,6 +4,8 @@
 package block_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -71,3 +73,35 @@ func (s *listCommandSuite) TestListJson(c *gc.C) {
 	c.Assert(testing.Stdout(ctx), gc.Equals, `[{"block":"destroy-environment","enabled":false},{"block":"remove-object","enabled":true,"message":"Test this one"},{"block":"all-changes","enabled":false}]
 `)
 }
+
+func (s *listCommandSuite) TestListEntityBlock(c *gc.C) {
+	expiry := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.mockClient.SwitchEntityBlockOn(string(multiwatcher.BlockRemove), "keep the data", "service-postgresql", &expiry)
+	s.mockClient.CreatedBy = "bob"
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&block.ListCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+destroy-environment                 =off
+remove-object                       =off
+all-changes                         =off
+remove-object (service postgresql)  =on, keep the data [set by bob, expires 2015-06-01T12:00:00Z]
+`)
+}
+
+func (s *listCommandSuite) TestListEntityBlockYaml(c *gc.C) {
+	s.mockClient.SwitchEntityBlockOn(string(multiwatcher.BlockChange), "upgrading", "machine-3", nil)
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&block.ListCommand{}), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+- block: destroy-environment
+  enabled: false
+- block: remove-object
+  enabled: false
+- block: all-changes
+  enabled: false
+- block: all-changes
+  entity: machine 3
+  enabled: true
+  message: upgrading
+`[1:])
+}
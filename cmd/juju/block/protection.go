@@ -8,7 +8,10 @@ import (
 	//	"strings"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
 	apiblock "github.com/juju/juju/api/block"
 	"github.com/juju/juju/apiserver/params"
@@ -58,6 +61,39 @@ func getBlockAPI(c *envcmd.EnvCommandBase) (*apiblock.Client, error) {
 	return apiblock.NewClient(root), nil
 }
 
+// blockScope holds the flags that restrict a block to
+// a single service or machine.
+type blockScope struct {
+	service string
+	machine string
+}
+
+// setFlags adds the scope flags to the flag set.
+func (s *blockScope) setFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&s.service, "service", "", "apply to the named service and its units only")
+	f.StringVar(&s.machine, "machine", "", "apply to the given machine only")
+}
+
+// entity returns the tag of the service or machine given
+// in the flags, or an empty string if neither was given.
+func (s *blockScope) entity() (string, error) {
+	switch {
+	case s.service != "" && s.machine != "":
+		return "", errors.New("cannot specify both --service and --machine")
+	case s.service != "":
+		if !names.IsValidService(s.service) {
+			return "", errors.Errorf("invalid service name %q", s.service)
+		}
+		return names.NewServiceTag(s.service).String(), nil
+	case s.machine != "":
+		if !names.IsValidMachine(s.machine) {
+			return "", errors.Errorf("invalid machine id %q", s.machine)
+		}
+		return names.NewMachineTag(s.machine).String(), nil
+	}
+	return "", nil
+}
+
 // Block describes block type
 type Block int8
 
@@ -18,6 +18,8 @@ import (
 type UnblockCommand struct {
 	envcmd.EnvCommandBase
 	operation string
+	scope     blockScope
+	entity    string
 }
 
 var (
@@ -75,6 +77,9 @@ all-changes includes all alteration commands
     user disable
     user enable
 
+With --service or --machine, the block on the given service or machine
+is removed, rather than the block on the whole environment.
+
 Examples:
    To allow the environment to be destroyed:
    juju unblock destroy-environment
@@ -85,6 +90,9 @@ Examples:
    To allow changes to the environment:
    juju unblock all-changes
 
+   To allow the postgresql service to be removed:
+   juju unblock remove-object --service postgresql
+
 See Also:
    juju help block
 `
@@ -133,12 +141,18 @@ func (c *UnblockCommand) Init(args []string) error {
 		return errors.Trace(errors.New("can only specify block type"))
 	}
 
-	return c.assignValidOperation("unblock", args)
+	if err := c.assignValidOperation("unblock", args); err != nil {
+		return err
+	}
+	var err error
+	c.entity, err = c.scope.entity()
+	return err
 }
 
 // SetFlags implements Command.SetFlags.
 func (c *UnblockCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.EnvCommandBase.SetFlags(f)
+	c.scope.setFlags(f)
 }
 
 // Run unblocks previously blocked commands.
@@ -150,13 +164,13 @@ func (c *UnblockCommand) Run(_ *cmd.Context) error {
 	}
 	defer client.Close()
 
-	return client.SwitchBlockOff(TypeFromOperation(c.operation))
+	return client.SwitchEntityBlockOff(TypeFromOperation(c.operation), c.entity)
 }
 
 // UnblockClientAPI defines the client API methods that unblock command uses.
 type UnblockClientAPI interface {
 	Close() error
-	SwitchBlockOff(blockType string) error
+	SwitchEntityBlockOff(blockType, entity string) error
 }
 
 var getUnblockClientAPI = func(p *UnblockCommand) (UnblockClientAPI, error) {
@@ -69,3 +69,21 @@ func (s *UnblockCommandSuite) TestUnblockCmdValidDestroyEnvOperationUpperCase(c
 func (s *UnblockCommandSuite) TestUnblockCmdValidDestroyEnvOperation(c *gc.C) {
 	s.assertRunUnblock(c, "destroy-environment")
 }
+
+func (s *UnblockCommandSuite) TestUnblockCmdService(c *gc.C) {
+	err := runUnblockCommand(c, "remove-object", "--service", "postgresql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockClient.BlockType, gc.Equals, block.TypeFromOperation("remove-object"))
+	c.Assert(s.mockClient.Entity, gc.Equals, "service-postgresql")
+}
+
+func (s *UnblockCommandSuite) TestUnblockCmdMachine(c *gc.C) {
+	err := runUnblockCommand(c, "all-changes", "--machine", "0/lxc/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockClient.BlockType, gc.Equals, block.TypeFromOperation("all-changes"))
+	c.Assert(s.mockClient.Entity, gc.Equals, "machine-0-lxc-1")
+}
+
+func (s *UnblockCommandSuite) TestUnblockCmdInvalidScope(c *gc.C) {
+	s.assertErrorMatches(c, runUnblockCommand(c, "all-changes", "--service", "a", "--machine", "0"), `.*cannot specify both --service and --machine.*`)
+}
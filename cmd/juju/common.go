@@ -195,6 +195,49 @@ func addCharmViaAPI(client *api.Client, ctx *cmd.Context, curl *charm.URL, repo
 	return curl, nil
 }
 
+// charmInEnvironment reports whether the charm with the given URL is
+// already in the environment, so that a dry run can be checked against
+// it without adding it. Local charms are always added with a new
+// revision, so they are never considered to be in the environment.
+func charmInEnvironment(client *api.Client, curl *charm.URL) (bool, error) {
+	if curl.Schema == "local" {
+		return false, nil
+	}
+	_, err := client.CharmInfo(curl.String())
+	if params.IsCodeNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
+// charmNotAddedChange describes the addition of a charm that a dry run
+// found not to be in the environment.
+func charmNotAddedChange(curl *charm.URL) string {
+	return fmt.Sprintf("add charm %q to the environment", curl)
+}
+
+// charmNotAddedPlan returns the plan reported by a dry run for a charm
+// that is not in the environment. The API server cannot check changes
+// against a charm it does not have, so only the changes are listed.
+// The revision of a local charm is chosen when it is added, so it is
+// not reported.
+func charmNotAddedPlan(curl *charm.URL, change func(curl *charm.URL) string) params.ChangePlan {
+	if curl.Schema == "local" {
+		curl = curl.WithRevision(-1)
+	}
+	return params.ChangePlan{
+		Changes: []string{
+			charmNotAddedChange(curl),
+			change(curl),
+		},
+		Warnings: []string{
+			"the charm is not in the environment, so the changes could not be checked",
+		},
+	}
+}
+
 // csClient gives access to the charm store server and provides parameters
 // for connecting to the charm store.
 type csClient struct {
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/constraints"
@@ -41,6 +42,9 @@ Examples:
    set-constraints mem=8G                         (all new machines in the environment must have at least 8GB of RAM)
    set-constraints --service wordpress mem=4G     (all new wordpress machines can ignore the 8G constraint above, and require only 4G)
 
+With --dry-run, the constraints are checked, and any the environment does
+not support are reported, but they are not set.
+
 See Also:
    juju help constraints
    juju help get-constraints
@@ -65,6 +69,8 @@ type ConstraintsAPI interface {
 	GetServiceConstraints(string) (constraints.Value, error)
 	SetEnvironmentConstraints(constraints.Value) error
 	SetServiceConstraints(string, constraints.Value) error
+	PlanSetEnvironmentConstraints(constraints.Value) (params.ChangePlan, error)
+	PlanSetServiceConstraints(string, constraints.Value) (params.ChangePlan, error)
 }
 
 func (c *GetConstraintsCommand) getAPI() (ConstraintsAPI, error) {
@@ -130,6 +136,7 @@ type SetConstraintsCommand struct {
 	ServiceName string
 	api         ConstraintsAPI
 	Constraints constraints.Value
+	DryRun      bool
 }
 
 func (c *SetConstraintsCommand) getAPI() (ConstraintsAPI, error) {
@@ -151,6 +158,7 @@ func (c *SetConstraintsCommand) Info() *cmd.Info {
 func (c *SetConstraintsCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.ServiceName, "s", "", "set service constraints")
 	f.StringVar(&c.ServiceName, "service", "", "")
+	f.BoolVar(&c.DryRun, "dry-run", false, DryRunDoc)
 }
 
 func (c *SetConstraintsCommand) Init(args []string) (err error) {
@@ -161,13 +169,25 @@ func (c *SetConstraintsCommand) Init(args []string) (err error) {
 	return err
 }
 
-func (c *SetConstraintsCommand) Run(_ *cmd.Context) (err error) {
+func (c *SetConstraintsCommand) Run(ctx *cmd.Context) (err error) {
 	apiclient, err := c.getAPI()
 	if err != nil {
 		return err
 	}
 	defer apiclient.Close()
 
+	if c.DryRun {
+		var plan params.ChangePlan
+		if c.ServiceName == "" {
+			plan, err = apiclient.PlanSetEnvironmentConstraints(c.Constraints)
+		} else {
+			plan, err = apiclient.PlanSetServiceConstraints(c.ServiceName, c.Constraints)
+		}
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		return WritePlan(ctx, plan)
+	}
 	if c.ServiceName == "" {
 		err = apiclient.SetEnvironmentConstraints(c.Constraints)
 	} else {
@@ -14,6 +14,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	// TODO(dimitern): Don't ever import "." unless there's a GOOD
 	// reason to do it.
@@ -87,6 +88,28 @@ func (f *fakeConstraintsClient) SetServiceConstraints(name string, cons constrai
 	return nil
 }
 
+func (f *fakeConstraintsClient) PlanSetEnvironmentConstraints(cons constraints.Value) (params.ChangePlan, error) {
+	if f.err != nil {
+		return params.ChangePlan{}, f.err
+	}
+	return params.ChangePlan{
+		Changes: []string{"set environment constraints to " + cons.String()},
+	}, nil
+}
+
+func (f *fakeConstraintsClient) PlanSetServiceConstraints(name string, cons constraints.Value) (params.ChangePlan, error) {
+	if f.err != nil {
+		return params.ChangePlan{}, f.err
+	}
+	if _, ok := f.servCons[name]; !ok {
+		return params.ChangePlan{}, errors.NotFoundf("service %q", name)
+	}
+	return params.ChangePlan{
+		Changes:  []string{"set constraints of service " + name + " to " + cons.String()},
+		Warnings: []string{"unsupported constraints would be ignored: cpu-power"},
+	}, nil
+}
+
 func runCmdLine(c *gc.C, com cmd.Command, args ...string) (code int, stdout, stderr string) {
 	ctx := testing.Context(c)
 	code = cmd.Main(com, ctx, args)
@@ -175,6 +198,34 @@ func (s *ConstraintsCommandsSuite) TestBlockSetService(c *gc.C) {
 	s.assertSetBlocked(c, "-s", "svc", "mem=4G", "cpu-power=250")
 }
 
+func (s *ConstraintsCommandsSuite) TestSetDryRun(c *gc.C) {
+	command := NewSetConstraintsCommand(s.fake)
+	rcode, rstdout, _ := runCmdLine(c, envcmd.Wrap(command), "--dry-run", "mem=4G")
+	c.Assert(rcode, gc.Equals, 0)
+	c.Assert(rstdout, gc.Equals, ""+
+		"dry run, no changes made; the command would:\n"+
+		"  set environment constraints to mem=4096M\n",
+	)
+	c.Assert(&s.fake.envCons, jc.Satisfies, constraints.IsEmpty)
+
+	s.fake.addTestingService("svc")
+	command = NewSetConstraintsCommand(s.fake)
+	rcode, rstdout, _ = runCmdLine(c, envcmd.Wrap(command), "--dry-run", "-s", "svc", "cpu-power=250")
+	c.Assert(rcode, gc.Equals, 0)
+	c.Assert(rstdout, gc.Equals, ""+
+		"dry run, no changes made; the command would:\n"+
+		"  set constraints of service svc to cpu-power=250\n"+
+		"warning: unsupported constraints would be ignored: cpu-power\n",
+	)
+	cons := s.fake.servCons["svc"]
+	c.Assert(&cons, jc.Satisfies, constraints.IsEmpty)
+}
+
+func (s *ConstraintsCommandsSuite) TestBlockSetDryRun(c *gc.C) {
+	s.fake.err = common.ErrOperationBlocked("TestBlockSetDryRun")
+	s.assertSetBlocked(c, "--dry-run", "mem=4G")
+}
+
 func (s *ConstraintsCommandsSuite) assertSetError(c *gc.C, code int, stderr string, args ...string) {
 	command := NewSetConstraintsCommand(s.fake)
 	rcode, rstdout, rstderr := runCmdLine(c, envcmd.Wrap(command), args...)
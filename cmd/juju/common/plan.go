@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// DryRunDoc describes the --dry-run flag, for inclusion in the help
+// of commands that support it.
+const DryRunDoc = "check the command would succeed, and report what it would do, without doing it"
+
+// WritePlan writes the changes a command would make, as reported by
+// the API server for --dry-run, to the context's standard output.
+func WritePlan(ctx *cmd.Context, plan params.ChangePlan) error {
+	var out []string
+	out = append(out, "dry run, no changes made; the command would:")
+	for _, change := range plan.Changes {
+		out = append(out, "  "+change)
+	}
+	if len(plan.Machines) > 0 {
+		out = append(out, "machines to provision:")
+		for _, m := range plan.Machines {
+			line := fmt.Sprintf("  for %s: series %s", m.Unit, m.Series)
+			if cons := m.Constraints.String(); cons != "" {
+				line += fmt.Sprintf(", constraints %q", cons)
+			}
+			if m.Placement != "" {
+				line += ", placement " + m.Placement
+			}
+			out = append(out, line)
+		}
+	}
+	if len(plan.Units) > 0 {
+		out = append(out, "units affected: "+strings.Join(plan.Units, ", "))
+	}
+	for _, warning := range plan.Warnings {
+		out = append(out, "warning: "+warning)
+	}
+	_, err := fmt.Fprintln(ctx.Stdout, strings.Join(out, "\n"))
+	return err
+}
@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// ConsumeCommand adds a remote service to the environment, consuming
+// a service offered by another environment.
+type ConsumeCommand struct {
+	envcmd.EnvCommandBase
+	SourceEnvironment string
+	OfferName         string
+	ServiceName       string
+}
+
+var jujuConsumeHelp = `
+Consumes a service offered, with "juju offer", by another environment
+hosted by the same state server. The offer is identified by the name or
+UUID of the offering environment and the name of the offer, separated
+by a dot.
+
+The offered service appears in this environment as a remote service,
+named after the offer unless another name is given, and local services
+may be related to it with "juju add-relation" as to any other service.
+
+Examples:
+
+    juju consume shared.db
+    juju consume shared.db mysql
+`
+
+func (c *ConsumeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "consume",
+		Args:    "<environment>.<offer name> [<service name>]",
+		Purpose: "consume a service offered by another environment",
+		Doc:     jujuConsumeHelp,
+	}
+}
+
+func (c *ConsumeCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no offer specified")
+	}
+	i := strings.LastIndex(args[0], ".")
+	if i <= 0 || i == len(args[0])-1 {
+		return errors.Errorf("invalid offer %q, expected <environment>.<offer name>", args[0])
+	}
+	c.SourceEnvironment, c.OfferName = args[0][:i], args[0][i+1:]
+	if !names.IsValidService(c.OfferName) {
+		return errors.Errorf("invalid offer name %q", c.OfferName)
+	}
+	if len(args) > 1 {
+		c.ServiceName = args[1]
+		if !names.IsValidService(c.ServiceName) {
+			return errors.Errorf("invalid service name %q", c.ServiceName)
+		}
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+type consumeAPI interface {
+	ServiceConsume(sourceEnv, offerName, service string) error
+	Close() error
+}
+
+var newConsumeAPI = func(c *ConsumeCommand) (consumeAPI, error) {
+	return c.NewAPIClient()
+}
+
+// Run adds the remote service.
+func (c *ConsumeCommand) Run(_ *cmd.Context) error {
+	client, err := newConsumeAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+	err = client.ServiceConsume(c.SourceEnvironment, c.OfferName, c.ServiceName)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
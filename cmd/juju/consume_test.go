@@ -0,0 +1,109 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type ConsumeSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeConsumeAPI
+}
+
+var _ = gc.Suite(&ConsumeSuite{})
+
+func (s *ConsumeSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeConsumeAPI{}
+	s.PatchValue(&newConsumeAPI, func(_ *ConsumeCommand) (consumeAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *ConsumeSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args      []string
+		sourceEnv string
+		offerName string
+		service   string
+		err       string
+	}{{
+		err: "no offer specified",
+	}, {
+		args:      []string{"shared.db"},
+		sourceEnv: "shared",
+		offerName: "db",
+	}, {
+		args:      []string{"shared.env.db", "mysql"},
+		sourceEnv: "shared.env",
+		offerName: "db",
+		service:   "mysql",
+	}, {
+		args: []string{"db"},
+		err:  `invalid offer "db", expected <environment>.<offer name>`,
+	}, {
+		args: []string{"shared."},
+		err:  `invalid offer "shared.", expected <environment>.<offer name>`,
+	}, {
+		args: []string{"shared.999"},
+		err:  `invalid offer name "999"`,
+	}, {
+		args: []string{"shared.db", "my sql"},
+		err:  `invalid service name "my sql"`,
+	}, {
+		args: []string{"shared.db", "mysql", "foo"},
+		err:  `unrecognized args: \["foo"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &ConsumeCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.SourceEnvironment, gc.Equals, test.sourceEnv)
+		c.Check(command.OfferName, gc.Equals, test.offerName)
+		c.Check(command.ServiceName, gc.Equals, test.service)
+	}
+}
+
+func (s *ConsumeSuite) TestRun(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&ConsumeCommand{}), "shared.db", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.sourceEnv, gc.Equals, "shared")
+	c.Assert(s.api.offerName, gc.Equals, "db")
+	c.Assert(s.api.service, gc.Equals, "mysql")
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *ConsumeSuite) TestRunError(c *gc.C) {
+	s.api.err = errors.New(`environment "shared" not found`)
+	_, err := testing.RunCommand(c, envcmd.Wrap(&ConsumeCommand{}), "shared.db")
+	c.Assert(err, gc.ErrorMatches, `environment "shared" not found`)
+}
+
+type fakeConsumeAPI struct {
+	sourceEnv string
+	offerName string
+	service   string
+	err       error
+	closed    bool
+}
+
+func (f *fakeConsumeAPI) ServiceConsume(sourceEnv, offerName, service string) error {
+	f.sourceEnv, f.offerName, f.service = sourceEnv, offerName, service
+	return f.err
+}
+
+func (f *fakeConsumeAPI) Close() error {
+	f.closed = true
+	return nil
+}
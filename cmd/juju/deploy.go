@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/juju/service"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/juju/osenv"
@@ -34,6 +35,7 @@ type DeployCommand struct {
 	Networks     string
 	BumpRevision bool   // Remove this once the 1.16 support is dropped.
 	RepoPath     string // defaults to JUJU_REPOSITORY
+	DryRun       bool
 
 	// TODO(axw) move this to UnitCommandBase once we support --storage
 	// on add-unit too.
@@ -72,6 +74,9 @@ machines provisioned with add-unit will use the same constraints (unless changed
 by set-constraints).
 
 Charms can be deployed to a specific machine using the --to argument.
+Alternatively, the units of a service can be spread across a list of
+availability zones, each unit being deployed to a new machine in the
+zone with the fewest of the service's units.
 If the destination is an LXC container the default is to use lxc-clone
 to create the container where possible. For Ubuntu deployments, lxc-clone
 is supported for the trusty OS series and later. A 'template' container is
@@ -94,6 +99,8 @@ Examples:
    juju deploy mysql --to 23       (deploy to machine 23)
    juju deploy mysql --to 24/lxc/3 (deploy to lxc container 3 on host machine 24)
    juju deploy mysql --to lxc:25   (deploy to a new lxc container on host machine 25)
+   juju deploy mysql -n 4 --to zone=us-east-1a,zone=us-east-1b
+   (deploy 4 instances of mysql on new machines, 2 in each zone)
 
    juju deploy mysql -n 5 --constraints mem=8G
    (deploy 5 instances of mysql with at least 8 GB of RAM each)
@@ -111,6 +118,14 @@ to configure on it. The --networks argument instructs juju to add all the
 networks specified with it to all new machines deployed to host units of
 the service. Not supported on all providers.
 
+With --dry-run, the deployment is checked, and the units that would be added,
+and the machines that would be provisioned for them, are reported, but the
+service is not deployed. Units without a --to placement are reported as
+needing new machines, although a clean, empty machine may be used when the
+service is deployed. The charm is not added to the environment: if it is not
+there already, as is always the case for local charms, the deployment cannot
+be checked, and only the charm and service that would be added are reported.
+
 See Also:
    juju help constraints
    juju help set-constraints
@@ -136,6 +151,7 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Networks, "networks", "", "bind the service to specific networks")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
 	f.Var(storageFlag{&c.Storage}, "storage", "charm storage constraints")
+	f.BoolVar(&c.DryRun, "dry-run", false, common.DryRunDoc)
 }
 
 func (c *DeployCommand) Init(args []string) error {
@@ -193,9 +209,29 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 
-	curl, err = addCharmViaAPI(client, ctx, curl, repo, csClient)
-	if err != nil {
-		return block.ProcessBlockedError(err, block.BlockChange)
+	// notAdded holds the charm when a dry run is made with a charm
+	// that is not in the environment; the deployment is checked
+	// against its metadata.
+	var notAdded charm.Charm
+	if c.DryRun {
+		inEnvironment, err := charmInEnvironment(client, curl)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !inEnvironment {
+			if notAdded, err = repo.Get(curl); err != nil {
+				return errors.Trace(err)
+			}
+			if curl.Revision < 0 {
+				// A local charm would be added with its own revision.
+				curl = curl.WithRevision(notAdded.Revision())
+			}
+		}
+	} else {
+		curl, err = addCharmViaAPI(client, ctx, curl, repo, csClient)
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
 	}
 
 	if c.BumpRevision {
@@ -217,13 +253,19 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 	}
 	haveNetworks := len(requestedNetworks) > 0 || c.Constraints.HaveNetworks()
 
-	charmInfo, err := client.CharmInfo(curl.String())
-	if err != nil {
-		return err
+	var meta *charm.Meta
+	if notAdded != nil {
+		meta = notAdded.Meta()
+	} else {
+		charmInfo, err := client.CharmInfo(curl.String())
+		if err != nil {
+			return err
+		}
+		meta = charmInfo.Meta
 	}
 
 	numUnits := c.NumUnits
-	if charmInfo.Meta.Subordinate {
+	if meta.Subordinate {
 		if !constraints.IsEmpty(&c.Constraints) {
 			return errors.New("cannot use --constraints with subordinate service")
 		}
@@ -235,7 +277,7 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 	}
 	serviceName := c.ServiceName
 	if serviceName == "" {
-		serviceName = charmInfo.Meta.Name
+		serviceName = meta.Name
 	}
 
 	var configYAML []byte
@@ -246,6 +288,31 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
+	if c.DryRun {
+		args := params.ServiceDeploy{
+			ServiceName:   serviceName,
+			CharmUrl:      curl.String(),
+			NumUnits:      numUnits,
+			ConfigYAML:    string(configYAML),
+			Constraints:   c.Constraints,
+			ToMachineSpec: c.ToMachineSpec,
+			Networks:      requestedNetworks,
+			Storage:       c.Storage,
+		}
+		if notAdded != nil {
+			args.CharmMeta = notAdded.Meta()
+			args.CharmConfig = notAdded.Config()
+		}
+		plan, err := client.PlanServiceDeploy(args)
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		if notAdded != nil {
+			plan.Changes = append([]string{charmNotAddedChange(curl)}, plan.Changes...)
+		}
+		return common.WritePlan(ctx, plan)
+	}
+
 	// If storage is specified, we attempt to use a new API on the service facade.
 	if len(c.Storage) > 0 {
 		notSupported := errors.New("cannot deploy charms with storage: not supported by the API server")
@@ -258,6 +258,27 @@ func (s *DeploySuite) TestNumUnits(c *gc.C) {
 	s.AssertService(c, "dummy", curl, 13, 0)
 }
 
+func (s *DeploySuite) TestDryRun(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), "local:dummy", "-n", "2", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"dry run, no changes made; the command would:\n"+
+		"  add charm \"local:trusty/dummy-1\" to the environment\n"+
+		"  add service \"dummy\" using charm \"local:trusty/dummy-1\"\n"+
+		"  add unit dummy/0 to a new machine\n"+
+		"  add unit dummy/1 to a new machine\n"+
+		"machines to provision:\n"+
+		"  for dummy/0: series trusty\n"+
+		"  for dummy/1: series trusty\n"+
+		"units affected: dummy/0, dummy/1\n",
+	)
+	_, err = s.State.Service("dummy")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.Charm(charm.MustParseURL("local:trusty/dummy-1"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *DeploySuite) TestNumUnitsSubordinate(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "logging")
 	err := runDeploy(c, "--num-units", "3", "local:logging")
@@ -441,6 +462,54 @@ func (s *DeployCharmStoreSuite) TestDeployAuthorization(c *gc.C) {
 	}
 }
 
+func (s *DeployCharmStoreSuite) TestDryRunCharmInEnvironment(c *gc.C) {
+	s.uploadCharm(c, "cs:~bob/trusty/wordpress-10", "wordpress")
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), "cs:~bob/trusty/wordpress-10")
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), "cs:~bob/trusty/wordpress-10", "other", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"dry run, no changes made; the command would:\n"+
+		"  add service \"other\" using charm \"cs:~bob/trusty/wordpress-10\"\n"+
+		"  add unit other/0 to a new machine\n"+
+		"machines to provision:\n"+
+		"  for other/0: series trusty\n"+
+		"units affected: other/0\n",
+	)
+	_, err = s.State.Service("other")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *DeployCharmStoreSuite) TestDryRunCharmNotInEnvironment(c *gc.C) {
+	s.uploadCharm(c, "cs:~bob/trusty/wordpress-10", "wordpress")
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), "cs:~bob/trusty/wordpress-10", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"dry run, no changes made; the command would:\n"+
+		"  add charm \"cs:~bob/trusty/wordpress-10\" to the environment\n"+
+		"  add service \"wordpress\" using charm \"cs:~bob/trusty/wordpress-10\"\n"+
+		"  add unit wordpress/0 to a new machine\n"+
+		"machines to provision:\n"+
+		"  for wordpress/0: series trusty\n"+
+		"units affected: wordpress/0\n",
+	)
+	_, err = s.State.Charm(charm.MustParseURL("cs:~bob/trusty/wordpress-10"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *DeployCharmStoreSuite) TestDryRunCharmNotInEnvironmentChecked(c *gc.C) {
+	s.uploadCharm(c, "cs:~bob/trusty/wordpress-10", "wordpress")
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), "cs:~bob/trusty/wordpress-10", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `cannot add service "wordpress": service already exists`)
+
+	_, err = coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), "cs:~bob/trusty/wordpress-10", "other", "--to", "42", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `cannot deploy "other" to machine 42: machine 42 not found`)
+	_, err = s.State.Charm(charm.MustParseURL("cs:~bob/trusty/wordpress-10"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 const (
 	// clientUserCookie is the name of the cookie which is
 	// used to signal to the charmStoreSuite macaroon discharger
@@ -0,0 +1,200 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju/osenv"
+)
+
+// EventsCommand shows a timeline of what happened in an environment.
+type EventsCommand struct {
+	envcmd.EnvCommandBase
+	out      cmd.Output
+	entities string
+	from     string
+	to       string
+	limit    int
+	isoTime  bool
+
+	fromTime *time.Time
+	toTime   *time.Time
+}
+
+var eventsDoc = `
+This command reports a timeline of events in the environment, merging
+the status history of machines, units and services with hook failures
+and finished actions, ordered from oldest to newest.
+
+Events may be restricted to particular entities by giving a comma
+separated list of tags with --entity. A tag selects its entity and
+those beneath it; for example "--entity unit-mysql,machine-3" shows
+events for all mysql units and for machine 3 and its containers,
+but not for machine 30.
+
+The --from and --to flags restrict the events to a time range. Each
+accepts either an RFC3339 timestamp (2015-04-01T12:00:00Z) or a
+duration (30m, 2h) measured back from now.
+
+Examples:
+    juju events --from 2h
+    juju events --entity unit-wordpress- --from 2015-04-01T12:00:00Z -n 0
+`
+
+func (c *EventsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "events",
+		Purpose: "output a timeline of events in the environment",
+		Doc:     eventsDoc,
+	}
+}
+
+func (c *EventsCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.entities, "entity", "", "only show events for these comma separated entity tags and the entities beneath them")
+	f.StringVar(&c.from, "from", "", "only show events after this time or duration ago")
+	f.StringVar(&c.to, "to", "", "only show events before this time or duration ago")
+	f.IntVar(&c.limit, "n", 100, "show at most this many of the most recent events (0 for all)")
+	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatEventsTabular,
+	})
+}
+
+func (c *EventsCommand) Init(args []string) error {
+	if err := cmd.CheckEmpty(args); err != nil {
+		return err
+	}
+	if c.limit < 0 {
+		return errors.Errorf("invalid number of events: %d", c.limit)
+	}
+	now := time.Now()
+	var err error
+	if c.fromTime, err = parseEventTime(c.from, now); err != nil {
+		return errors.Annotate(err, "invalid --from value")
+	}
+	if c.toTime, err = parseEventTime(c.to, now); err != nil {
+		return errors.Annotate(err, "invalid --to value")
+	}
+	if c.fromTime != nil && c.toTime != nil && c.toTime.Before(*c.fromTime) {
+		return errors.New("--to must not be before --from")
+	}
+	// If use of ISO time not specified on command line,
+	// check env var.
+	if !c.isoTime {
+		envVarValue := os.Getenv(osenv.JujuStatusIsoTimeEnvKey)
+		if envVarValue != "" {
+			if c.isoTime, err = strconv.ParseBool(envVarValue); err != nil {
+				return errors.Annotatef(err, "invalid %s env var, expected true|false", osenv.JujuStatusIsoTimeEnvKey)
+			}
+		}
+	}
+	return nil
+}
+
+// parseEventTime parses either an RFC3339 timestamp or a duration
+// before now. An empty value yields a nil time.
+func parseEventTime(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return nil, errors.Errorf("negative duration %q", value)
+		}
+		t := now.Add(-d)
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.Errorf("expected RFC3339 time or duration, got %q", value)
+	}
+	return &t, nil
+}
+
+type eventsAPI interface {
+	EnvironmentEvents(args params.EnvironmentEventsArgs) ([]params.EnvironmentEvent, error)
+	Close() error
+}
+
+var newEventsAPI = func(c *EventsCommand) (eventsAPI, error) {
+	return c.NewAPIClient()
+}
+
+// formattedEvent is the representation of an event used for output.
+type formattedEvent struct {
+	Time    string `json:"time" yaml:"time"`
+	Kind    string `json:"kind" yaml:"kind"`
+	Entity  string `json:"entity" yaml:"entity"`
+	Status  string `json:"status,omitempty" yaml:"status,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+func (c *EventsCommand) Run(ctx *cmd.Context) error {
+	apiclient, err := newEventsAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer apiclient.Close()
+
+	args := params.EnvironmentEventsArgs{
+		From:  c.fromTime,
+		To:    c.toTime,
+		Limit: c.limit,
+	}
+	for _, prefix := range strings.Split(c.entities, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			args.EntityPrefixes = append(args.EntityPrefixes, prefix)
+		}
+	}
+	events, err := apiclient.EnvironmentEvents(args)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(events) == 0 {
+		ctx.Infof("no events to display")
+		return nil
+	}
+	formatted := make([]formattedEvent, len(events))
+	for i, event := range events {
+		formatted[i] = formattedEvent{
+			Time:    formatStatusTime(&event.Time, c.isoTime),
+			Kind:    event.Kind,
+			Entity:  event.Entity,
+			Status:  event.Status,
+			Message: event.Message,
+		}
+	}
+	return c.out.Write(ctx, formatted)
+}
+
+// formatEventsTabular returns a tabular summary of events.
+func formatEventsTabular(value interface{}) ([]byte, error) {
+	events, ok := value.([]formattedEvent)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", events, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tKIND\tENTITY\tSTATUS\tMESSAGE")
+	for _, event := range events {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", event.Time, event.Kind, event.Entity, event.Status, event.Message)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
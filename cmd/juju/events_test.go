@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type EventsSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeEventsAPI
+}
+
+var _ = gc.Suite(&EventsSuite{})
+
+func (s *EventsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeEventsAPI{}
+	s.PatchValue(&newEventsAPI, func(_ *EventsCommand) (eventsAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *EventsSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"foo"},
+		err:  `unrecognized args: \["foo"\]`,
+	}, {
+		args: []string{"-n", "-1"},
+		err:  "invalid number of events: -1",
+	}, {
+		args: []string{"--from", "yesterday"},
+		err:  `invalid --from value: expected RFC3339 time or duration, got "yesterday"`,
+	}, {
+		args: []string{"--to", "-5m"},
+		err:  `invalid --to value: negative duration "-5m"`,
+	}, {
+		args: []string{"--from", "1h", "--to", "2h"},
+		err:  "--to must not be before --from",
+	}, {
+		args: []string{"--from", "2015-04-01T00:00:00Z", "--to", "1h"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&EventsCommand{}), test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *EventsSuite) TestRun(c *gc.C) {
+	s.api.events = []params.EnvironmentEvent{{
+		Time:    time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC),
+		Kind:    "hook-failed",
+		Entity:  "unit-mysql-0",
+		Status:  "error",
+		Message: `hook failed: "install"`,
+	}, {
+		Time:    time.Date(2015, 4, 1, 12, 5, 0, 0, time.UTC),
+		Kind:    "action",
+		Entity:  "unit-mysql-0",
+		Status:  "completed",
+		Message: "backup",
+	}}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&EventsCommand{}),
+		"--utc", "--entity", "unit-mysql-, machine-0", "--from", "2015-04-01T00:00:00Z", "-n", "10",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"TIME                 KIND        ENTITY       STATUS    MESSAGE\n"+
+		"2015-04-01T12:00:00Z hook-failed unit-mysql-0 error     hook failed: \"install\"\n"+
+		"2015-04-01T12:05:00Z action      unit-mysql-0 completed backup\n",
+	)
+	from := time.Date(2015, 4, 1, 0, 0, 0, 0, time.UTC)
+	c.Assert(s.api.args, jc.DeepEquals, params.EnvironmentEventsArgs{
+		EntityPrefixes: []string{"unit-mysql-", "machine-0"},
+		From:           &from,
+		Limit:          10,
+	})
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *EventsSuite) TestRunNoEvents(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&EventsCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "no events to display\n")
+	c.Assert(s.api.args.Limit, gc.Equals, 100)
+}
+
+type fakeEventsAPI struct {
+	args   params.EnvironmentEventsArgs
+	events []params.EnvironmentEvent
+	closed bool
+}
+
+func (f *fakeEventsAPI) EnvironmentEvents(args params.EnvironmentEventsArgs) ([]params.EnvironmentEvent, error) {
+	f.args = args
+	return f.events, nil
+}
+
+func (f *fakeEventsAPI) Close() error {
+	f.closed = true
+	return nil
+}
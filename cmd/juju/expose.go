@@ -7,21 +7,32 @@ import (
 	"errors"
 
 	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/network"
 )
 
 // ExposeCommand is responsible exposing services.
 type ExposeCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	SourceCIDRs []string
+	sourceCIDRs string
 }
 
 var jujuExposeHelp = `
 Adjusts firewall rules and similar security mechanisms of the provider, to
 allow the service to be accessed on its public address.
 
+By default the service is accessible from any address. The --to option
+restricts access to addresses within a comma-separated list of CIDRs,
+so that internal services need not be exposed to the internet:
+
+    juju expose mysql --to 10.0.0.0/8,203.0.113.4/32
+
+Exposing an already exposed service replaces its source CIDRs.
 `
 
 func (c *ExposeCommand) Info() *cmd.Info {
@@ -33,11 +44,22 @@ func (c *ExposeCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *ExposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.sourceCIDRs, "to", "", "comma-separated CIDRs from which the service may be accessed")
+}
+
 func (c *ExposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no service name specified")
 	}
 	c.ServiceName = args[0]
+	if c.sourceCIDRs != "" {
+		cidrs, err := network.ParseSourceCIDRs(c.sourceCIDRs)
+		if err != nil {
+			return err
+		}
+		c.SourceCIDRs = cidrs
+	}
 	return cmd.CheckEmpty(args[1:])
 }
 
@@ -49,5 +71,5 @@ func (c *ExposeCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	return block.ProcessBlockedError(client.ServiceExpose(c.ServiceName), block.BlockChange)
+	return block.ProcessBlockedError(client.ServiceExpose(c.ServiceName, c.SourceCIDRs...), block.BlockChange)
 }
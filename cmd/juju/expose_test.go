@@ -55,6 +55,22 @@ func (s *ExposeSuite) TestExpose(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `service "nonexistent-service" not found`)
 }
 
+func (s *ExposeSuite) TestExposeTo(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runExpose(c, "some-service-name", "--to", "10.0.0.0/8, 203.0.113.4/32")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-service-name")
+	svc, err := s.State.Service("some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.ExposedSourceCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8", "203.0.113.4/32"})
+
+	err = runExpose(c, "some-service-name", "--to", "10.0.0.0")
+	c.Assert(err, gc.ErrorMatches, `invalid source CIDR "10.0.0.0"`)
+}
+
 func (s *ExposeSuite) TestBlockExpose(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
 	err := runDeploy(c, "local:dummy", "some-service-name")
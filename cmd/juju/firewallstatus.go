@@ -0,0 +1,195 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/network"
+)
+
+// FirewallStatusCommand shows the ports Juju expects to be open,
+// compared with those the provider reports as open.
+type FirewallStatusCommand struct {
+	envcmd.EnvCommandBase
+	out     cmd.Output
+	isoTime bool
+}
+
+var firewallStatusDoc = `
+This command reports, for each machine (or, when firewall-mode is
+"global", for the whole environment), the ports that Juju expects to
+be open and those that the provider reported as open when the
+firewaller last checked.
+
+The firewaller checks periodically, every firewall-reconcile-interval
+seconds, correcting any drift it finds: ports opened outside of Juju
+are closed, and ports closed outside of Juju are reopened. Unexpected
+and missing ports are those found by the last check; the most recent
+check that found any drift is also reported.
+
+Ports accessible from any address are shown as, for example, "80/tcp";
+ports restricted to a source CIDR as "80/tcp from 10.0.0.0/8".
+`
+
+func (c *FirewallStatusCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "firewall-status",
+		Purpose: "compare the ports Juju expects to be open with those the provider reports",
+		Doc:     firewallStatusDoc,
+	}
+}
+
+func (c *FirewallStatusCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatFirewallStatusTabular,
+	})
+}
+
+func (c *FirewallStatusCommand) Init(args []string) error {
+	if err := cmd.CheckEmpty(args); err != nil {
+		return err
+	}
+	// If use of ISO time not specified on command line,
+	// check env var.
+	if !c.isoTime {
+		envVarValue := os.Getenv(osenv.JujuStatusIsoTimeEnvKey)
+		if envVarValue != "" {
+			var err error
+			if c.isoTime, err = strconv.ParseBool(envVarValue); err != nil {
+				return errors.Annotatef(err, "invalid %s env var, expected true|false", osenv.JujuStatusIsoTimeEnvKey)
+			}
+		}
+	}
+	return nil
+}
+
+type firewallStatusAPI interface {
+	FirewallStatus() ([]params.FirewallStatus, error)
+	Close() error
+}
+
+var newFirewallStatusAPI = func(c *FirewallStatusCommand) (firewallStatusAPI, error) {
+	return c.NewAPIClient()
+}
+
+// formattedFirewallStatus is the representation of a firewall
+// status used for output.
+type formattedFirewallStatus struct {
+	Entity     string                  `json:"entity" yaml:"entity"`
+	Checked    string                  `json:"checked" yaml:"checked"`
+	Expected   []string                `json:"expected,omitempty" yaml:"expected,omitempty"`
+	Actual     []string                `json:"actual,omitempty" yaml:"actual,omitempty"`
+	Unexpected []string                `json:"unexpected,omitempty" yaml:"unexpected,omitempty"`
+	Missing    []string                `json:"missing,omitempty" yaml:"missing,omitempty"`
+	LastDrift  *formattedFirewallDrift `json:"last-drift,omitempty" yaml:"last-drift,omitempty"`
+}
+
+// formattedFirewallDrift describes the most recently detected
+// firewall drift.
+type formattedFirewallDrift struct {
+	Detected   string   `json:"detected" yaml:"detected"`
+	Unexpected []string `json:"unexpected,omitempty" yaml:"unexpected,omitempty"`
+	Missing    []string `json:"missing,omitempty" yaml:"missing,omitempty"`
+}
+
+func (c *FirewallStatusCommand) Run(ctx *cmd.Context) error {
+	apiclient, err := newFirewallStatusAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer apiclient.Close()
+
+	statuses, err := apiclient.FirewallStatus()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(statuses) == 0 {
+		ctx.Infof("the firewall has not yet been checked")
+		return nil
+	}
+	formatted := make([]formattedFirewallStatus, len(statuses))
+	for i, status := range statuses {
+		formatted[i] = formattedFirewallStatus{
+			Entity:     status.Tag,
+			Checked:    formatStatusTime(&status.Checked, c.isoTime),
+			Expected:   formatIngressRules(status.Expected),
+			Actual:     formatIngressRules(status.Actual),
+			Unexpected: formatIngressRules(status.Unexpected),
+			Missing:    formatIngressRules(status.Missing),
+		}
+		if status.DriftDetected != nil {
+			formatted[i].LastDrift = &formattedFirewallDrift{
+				Detected:   formatStatusTime(status.DriftDetected, c.isoTime),
+				Unexpected: formatIngressRules(status.DriftUnexpected),
+				Missing:    formatIngressRules(status.DriftMissing),
+			}
+		}
+	}
+	return c.out.Write(ctx, formatted)
+}
+
+// formatIngressRules returns a readable representation of each
+// rule, omitting the source CIDR of rules allowing any address.
+func formatIngressRules(rules []params.IngressRule) []string {
+	var result []string
+	for _, rule := range params.NetworkIngressRules(rules) {
+		if rule.SourceCIDR == network.AnySourceCIDR {
+			result = append(result, rule.PortRange.String())
+		} else {
+			result = append(result, rule.String())
+		}
+	}
+	return result
+}
+
+// formatFirewallStatusTabular returns a tabular summary of firewall
+// statuses.
+func formatFirewallStatusTabular(value interface{}) ([]byte, error) {
+	statuses, ok := value.([]formattedFirewallStatus)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", statuses, value)
+	}
+	list := func(values []string) string {
+		if len(values) == 0 {
+			return "-"
+		}
+		return strings.Join(values, ",")
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "ENTITY\tCHECKED\tEXPECTED\tACTUAL\tUNEXPECTED\tMISSING\tLAST-DRIFT")
+	for _, status := range statuses {
+		lastDrift := "-"
+		if status.LastDrift != nil {
+			lastDrift = status.LastDrift.Detected
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			status.Entity,
+			status.Checked,
+			list(status.Expected),
+			list(status.Actual),
+			list(status.Unexpected),
+			list(status.Missing),
+			lastDrift,
+		)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type FirewallStatusSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeFirewallStatusAPI
+}
+
+var _ = gc.Suite(&FirewallStatusSuite{})
+
+func (s *FirewallStatusSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeFirewallStatusAPI{}
+	s.PatchValue(&newFirewallStatusAPI, func(_ *FirewallStatusCommand) (firewallStatusAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *FirewallStatusSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&FirewallStatusCommand{}), []string{"foo"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
+
+func ingressRuleParams(rules ...string) []params.IngressRule {
+	var result []network.IngressRule
+	for _, rule := range rules {
+		parts := append(strings.SplitN(rule, " from ", 2), network.AnySourceCIDR)
+		result = append(result, network.IngressRule{network.MustParsePortRange(parts[0]), parts[1]})
+	}
+	return params.FromNetworkIngressRules(result)
+}
+
+func (s *FirewallStatusSuite) TestRun(c *gc.C) {
+	checked := time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC)
+	detected := time.Date(2015, 4, 1, 11, 55, 0, 0, time.UTC)
+	s.api.statuses = []params.FirewallStatus{{
+		Tag:             "machine-1",
+		Checked:         checked,
+		Expected:        ingressRuleParams("80/tcp", "443/tcp from 10.0.0.0/8"),
+		Actual:          ingressRuleParams("22/tcp", "80/tcp"),
+		Unexpected:      ingressRuleParams("22/tcp"),
+		Missing:         ingressRuleParams("443/tcp from 10.0.0.0/8"),
+		DriftDetected:   &checked,
+		DriftUnexpected: ingressRuleParams("22/tcp"),
+		DriftMissing:    ingressRuleParams("443/tcp from 10.0.0.0/8"),
+	}, {
+		Tag:           "machine-2",
+		Checked:       checked,
+		Expected:      ingressRuleParams("3306/tcp"),
+		Actual:        ingressRuleParams("3306/tcp"),
+		DriftDetected: &detected,
+		DriftMissing:  ingressRuleParams("3306/tcp"),
+	}}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&FirewallStatusCommand{}), "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"ENTITY    CHECKED              EXPECTED                       ACTUAL        UNEXPECTED MISSING                 LAST-DRIFT\n"+
+		"machine-1 2015-04-01T12:00:00Z 80/tcp,443/tcp from 10.0.0.0/8 22/tcp,80/tcp 22/tcp     443/tcp from 10.0.0.0/8 2015-04-01T12:00:00Z\n"+
+		"machine-2 2015-04-01T12:00:00Z 3306/tcp                       3306/tcp      -          -                       2015-04-01T11:55:00Z\n",
+	)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *FirewallStatusSuite) TestRunJSON(c *gc.C) {
+	checked := time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC)
+	s.api.statuses = []params.FirewallStatus{{
+		Tag:             "environment-deadbeef-0bad-400d-8000-4b1d0d06f00d",
+		Checked:         checked,
+		Expected:        ingressRuleParams("80/tcp"),
+		Actual:          ingressRuleParams("80/tcp"),
+		DriftDetected:   &checked,
+		DriftUnexpected: ingressRuleParams("22/tcp"),
+	}}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&FirewallStatusCommand{}), "--utc", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `[{`+
+		`"entity":"environment-deadbeef-0bad-400d-8000-4b1d0d06f00d",`+
+		`"checked":"2015-04-01T12:00:00Z",`+
+		`"expected":["80/tcp"],`+
+		`"actual":["80/tcp"],`+
+		`"last-drift":{"detected":"2015-04-01T12:00:00Z","unexpected":["22/tcp"]}`+
+		"}]\n",
+	)
+}
+
+func (s *FirewallStatusSuite) TestRunNotChecked(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&FirewallStatusCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "the firewall has not yet been checked\n")
+}
+
+type fakeFirewallStatusAPI struct {
+	statuses []params.FirewallStatus
+	closed   bool
+}
+
+func (f *fakeFirewallStatusAPI) FirewallStatus() ([]params.FirewallStatus, error) {
+	return f.statuses, nil
+}
+
+func (f *fakeFirewallStatusAPI) Close() error {
+	f.closed = true
+	return nil
+}
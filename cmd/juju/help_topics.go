@@ -400,6 +400,20 @@ instance-type
    conflict with other constraints depending on the provider (since the instance
    type my determine things like memory size etc.)
 
+instance-lifecycle
+   Instance-lifecycle defines the pricing model under which the machine's
+   instance is started: either on-demand (the default) or spot.  Spot
+   instances are cheaper, but may be terminated by the provider at any time,
+   in which case juju starts a new instance for the machine and deploys its
+   units again.  Spot instances are currently only supported by the Amazon
+   EC2 environment, and by GCE, where they are known as preemptible
+   instances.
+
+max-price
+   Max-price is a decimal number that defines the maximum hourly price, in US
+   dollars, to pay for a spot instance.  It is required by the Amazon EC2
+   environment when instance-lifecycle=spot is given.
+
 Example:
 
    juju add-machine --constraints "arch=amd64 mem=8G tags=foo,^bar"
@@ -14,15 +14,19 @@ import (
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/cmd/juju/autoscale"
 	"github.com/juju/juju/cmd/juju/backups"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/juju/cachedimages"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/juju/environment"
 	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/cmd/juju/placement"
+	"github.com/juju/juju/cmd/juju/selfheal"
 	"github.com/juju/juju/cmd/juju/service"
 	"github.com/juju/juju/cmd/juju/storage"
 	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/cmd/juju/webhook"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/juju/osenv"
@@ -118,6 +122,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&BootstrapCommand{}))
 	r.Register(wrapEnvCommand(&DeployCommand{}))
 	r.Register(wrapEnvCommand(&AddRelationCommand{}))
+	r.Register(wrapEnvCommand(&OfferCommand{}))
+	r.Register(wrapEnvCommand(&ConsumeCommand{}))
 
 	// Destruction commands.
 	r.Register(wrapEnvCommand(&RemoveRelationCommand{}))
@@ -131,6 +137,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&EndpointCommand{}))
 	r.Register(wrapEnvCommand(&APIInfoCommand{}))
 	r.Register(wrapEnvCommand(&StatusHistoryCommand{}))
+	r.Register(wrapEnvCommand(&EventsCommand{}))
+	r.Register(wrapEnvCommand(&FirewallStatusCommand{}))
 
 	// Error resolution and debugging commands.
 	r.Register(wrapEnvCommand(&RunCommand{}))
@@ -206,6 +214,18 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Manage storage
 	r.Register(storage.NewSuperCommand())
+
+	// Manage status notification webhooks
+	r.Register(webhook.NewSuperCommand())
+
+	// Manage service autoscaling policies
+	r.Register(autoscale.NewSuperCommand())
+
+	// Manage service placement policies
+	r.Register(placement.NewSuperCommand())
+
+	// Manage the replacement of failed machines
+	r.Register(selfheal.NewSuperCommand())
 }
 
 // envCmdWrapper is a struct that wraps an environment command and lets us handle
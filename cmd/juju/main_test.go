@@ -196,10 +196,12 @@ var commandNames = []string{
 	"api-info",
 	"authorised-keys", // alias for authorized-keys
 	"authorized-keys",
+	"autoscale",
 	"backups",
 	"block",
 	"bootstrap",
 	"cached-images",
+	"consume",
 	"debug-hooks",
 	"debug-log",
 	"deploy",
@@ -211,7 +213,9 @@ var commandNames = []string{
 	"ensure-availability",
 	"env", // alias for switch
 	"environment",
+	"events",
 	"expose",
+	"firewall-status",
 	"generate-config", // alias for init
 	"get",
 	"get-constraints",
@@ -221,6 +225,8 @@ var commandNames = []string{
 	"help-tool",
 	"init",
 	"machine",
+	"offer",
+	"placement",
 	"publish",
 	"remove-machine",  // alias for destroy-machine
 	"remove-relation", // alias for destroy-relation
@@ -230,6 +236,7 @@ var commandNames = []string{
 	"retry-provisioning",
 	"run",
 	"scp",
+	"self-heal",
 	"service",
 	"set",
 	"set-constraints",
@@ -252,6 +259,7 @@ var commandNames = []string{
 	"upgrade-juju",
 	"user",
 	"version",
+	"webhook",
 }
 
 func (s *MainSuite) TestHelpCommands(c *gc.C) {
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// OfferCommand offers a service's endpoints to other environments
+// hosted by the same state server.
+type OfferCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Endpoints   []string
+	OfferName   string
+}
+
+var jujuOfferHelp = `
+Offers the endpoints of a service so that services in other environments
+hosted by the same state server may relate to it. The offer may then be
+consumed in another environment with "juju consume".
+
+By default all of the service's provider and requirer endpoints are
+offered; a comma-separated list of endpoint names may be given instead.
+Peer and container-scoped endpoints cannot be offered.
+
+The offer is named after the service, unless another name is given.
+
+Examples:
+
+    juju offer mysql
+    juju offer mysql:db shared-db
+`
+
+func (c *OfferCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "offer",
+		Args:    "<service>[:<endpoint>[,<endpoint>...]] [<offer name>]",
+		Purpose: "offer a service's endpoints to other environments",
+		Doc:     jujuOfferHelp,
+	}
+}
+
+func (c *OfferCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	parts := strings.SplitN(args[0], ":", 2)
+	c.ServiceName = parts[0]
+	if !names.IsValidService(c.ServiceName) {
+		return errors.Errorf("invalid service name %q", c.ServiceName)
+	}
+	if len(parts) == 2 {
+		for _, ep := range strings.Split(parts[1], ",") {
+			if ep = strings.TrimSpace(ep); ep == "" {
+				return errors.Errorf("invalid endpoints %q", parts[1])
+			}
+			c.Endpoints = append(c.Endpoints, ep)
+		}
+	}
+	if len(args) > 1 {
+		c.OfferName = args[1]
+		if !names.IsValidService(c.OfferName) {
+			return errors.Errorf("invalid offer name %q", c.OfferName)
+		}
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+type offerAPI interface {
+	ServiceOffer(service string, endpoints []string, offerName string) error
+	Close() error
+}
+
+var newOfferAPI = func(c *OfferCommand) (offerAPI, error) {
+	return c.NewAPIClient()
+}
+
+// Run offers the service's endpoints.
+func (c *OfferCommand) Run(_ *cmd.Context) error {
+	client, err := newOfferAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+	err = client.ServiceOffer(c.ServiceName, c.Endpoints, c.OfferName)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
@@ -0,0 +1,105 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type OfferSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeOfferAPI
+}
+
+var _ = gc.Suite(&OfferSuite{})
+
+func (s *OfferSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeOfferAPI{}
+	s.PatchValue(&newOfferAPI, func(_ *OfferCommand) (offerAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *OfferSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args      []string
+		service   string
+		endpoints []string
+		offerName string
+		err       string
+	}{{
+		err: "no service name specified",
+	}, {
+		args:    []string{"mysql"},
+		service: "mysql",
+	}, {
+		args:      []string{"mysql:db, admin", "shared-db"},
+		service:   "mysql",
+		endpoints: []string{"db", "admin"},
+		offerName: "shared-db",
+	}, {
+		args: []string{"mysql:"},
+		err:  `invalid endpoints ""`,
+	}, {
+		args: []string{"999"},
+		err:  `invalid service name "999"`,
+	}, {
+		args: []string{"mysql", "shared db"},
+		err:  `invalid offer name "shared db"`,
+	}, {
+		args: []string{"mysql", "db", "foo"},
+		err:  `unrecognized args: \["foo"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &OfferCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.ServiceName, gc.Equals, test.service)
+		c.Check(command.Endpoints, jc.DeepEquals, test.endpoints)
+		c.Check(command.OfferName, gc.Equals, test.offerName)
+	}
+}
+
+func (s *OfferSuite) TestRun(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&OfferCommand{}), "mysql:db", "shared-db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.service, gc.Equals, "mysql")
+	c.Assert(s.api.endpoints, jc.DeepEquals, []string{"db"})
+	c.Assert(s.api.offerName, gc.Equals, "shared-db")
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *OfferSuite) TestRunError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&OfferCommand{}), "mysql")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type fakeOfferAPI struct {
+	service   string
+	endpoints []string
+	offerName string
+	err       error
+	closed    bool
+}
+
+func (f *fakeOfferAPI) ServiceOffer(service string, endpoints []string, offerName string) error {
+	f.service, f.endpoints, f.offerName = service, endpoints, offerName
+	return f.err
+}
+
+func (f *fakeOfferAPI) Close() error {
+	f.closed = true
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package placement
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const placementCommandDoc = `
+"juju placement" manages the policies restricting the number of units of
+a service and the machines they may be assigned to.
+
+A policy may limit the number of units of the service, prevent two of
+its units from being placed on the same machine or in the same
+availability zone, and require its units to be placed on machines that
+host a unit of another service. Policies are enforced when units are
+added and assigned to machines, including with "juju add-unit --to";
+units that are already assigned are not moved.
+
+Availability zone anti-affinity can only take account of machines that
+have been provisioned. Units assigned to new machines are spread across
+availability zones by the provider, where it supports doing so.
+`
+
+const placementCommandPurpose = "manage service placement policies"
+
+// NewSuperCommand creates the placement supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	placementCmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "placement",
+		Doc:         placementCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     placementCommandPurpose,
+	})
+	placementCmd.Register(envcmd.Wrap(&SetCommand{}))
+	placementCmd.Register(envcmd.Wrap(&ShowCommand{}))
+	placementCmd.Register(envcmd.Wrap(&ClearCommand{}))
+	return placementCmd
+}
+
+// PlacementAPI defines the client API methods used by the placement
+// commands.
+type PlacementAPI interface {
+	Close() error
+	SetPlacementPolicy(service string, policy params.PlacementPolicy) error
+	ClearPlacementPolicy(service string) error
+	PlacementPolicy(service string) (*params.PlacementPolicy, error)
+}
+
+var getPlacementAPI = func(c *envcmd.EnvCommandBase) (PlacementAPI, error) {
+	return c.NewAPIClient()
+}
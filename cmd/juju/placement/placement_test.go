@@ -0,0 +1,149 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package placement_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/placement"
+	"github.com/juju/juju/testing"
+)
+
+type PlacementSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakePlacementAPI
+}
+
+var _ = gc.Suite(&PlacementSuite{})
+
+func (s *PlacementSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakePlacementAPI{}
+	s.PatchValue(placement.GetPlacementAPI, func(*envcmd.EnvCommandBase) (placement.PlacementAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *PlacementSuite) run(c *gc.C, command cmd.Command, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *PlacementSuite) TestSetInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no service name specified",
+	}, {
+		args: []string{"web!", "--max-units", "3"},
+		err:  `invalid service name "web!"`,
+	}, {
+		args: []string{"web"},
+		err:  "no placement policy specified",
+	}, {
+		args: []string{"web", "--anti-affinity", "rack"},
+		err:  `anti-affinity must be "machine" or "zone", not "rack"`,
+	}, {
+		args: []string{"web", "--max-units", "-1"},
+		err:  "maximum units must not be negative",
+	}, {
+		args: []string{"web", "--affinity", "db!"},
+		err:  `invalid affinity service name "db!"`,
+	}, {
+		args: []string{"web", "--max-units", "3", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"web", "--anti-affinity", "zone"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&placement.SetCommand{}), test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *PlacementSuite) TestSet(c *gc.C) {
+	_, err := s.run(c, &placement.SetCommand{}, "cassandra",
+		"--max-units", "5", "--anti-affinity", "machine", "--affinity", "spark")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.service, gc.Equals, "cassandra")
+	c.Assert(s.api.policy, jc.DeepEquals, params.PlacementPolicy{
+		MaxUnits:        5,
+		AntiAffinity:    "machine",
+		AffinityService: "spark",
+	})
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *PlacementSuite) TestClear(c *gc.C) {
+	_, err := s.run(c, &placement.ClearCommand{}, "cassandra")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.cleared, gc.Equals, "cassandra")
+
+	_, err = s.run(c, &placement.ClearCommand{})
+	c.Assert(err, gc.ErrorMatches, "no service name specified")
+}
+
+func (s *PlacementSuite) TestShow(c *gc.C) {
+	s.api.result = &params.PlacementPolicy{MaxUnits: 5, AntiAffinity: "zone"}
+	ctx, err := s.run(c, &placement.ShowCommand{}, "cassandra")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"SERVICE   MAX-UNITS ANTI-AFFINITY AFFINITY\n"+
+		"cassandra 5         zone          -\n",
+	)
+	c.Assert(s.api.service, gc.Equals, "cassandra")
+}
+
+func (s *PlacementSuite) TestShowNoPolicy(c *gc.C) {
+	ctx, err := s.run(c, &placement.ShowCommand{}, "cassandra")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "service \"cassandra\" has no placement policy\n")
+}
+
+func (s *PlacementSuite) TestShowYAML(c *gc.C) {
+	s.api.result = &params.PlacementPolicy{AffinityService: "spark"}
+	ctx, err := s.run(c, &placement.ShowCommand{}, "cassandra", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"service: cassandra\n"+
+		"policy:\n"+
+		"  affinity: spark\n",
+	)
+}
+
+type fakePlacementAPI struct {
+	service string
+	policy  params.PlacementPolicy
+	cleared string
+	result  *params.PlacementPolicy
+	closed  bool
+}
+
+func (f *fakePlacementAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakePlacementAPI) SetPlacementPolicy(service string, policy params.PlacementPolicy) error {
+	f.service, f.policy = service, policy
+	return nil
+}
+
+func (f *fakePlacementAPI) ClearPlacementPolicy(service string) error {
+	f.cleared = service
+	return nil
+}
+
+func (f *fakePlacementAPI) PlacementPolicy(service string) (*params.PlacementPolicy, error) {
+	f.service = service
+	return f.result, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package placement
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const setCommandDoc = `
+Set the policy restricting the placement of a service's units, replacing
+any existing policy.
+
+--max-units limits the number of units of the service.
+--anti-affinity prevents two units of the service from being placed on
+the same machine ("machine") or in the same availability zone ("zone").
+--affinity requires units of the service to be placed on machines, or
+containers on machines, that host a unit of the named service.
+
+Example:
+
+    juju placement set cassandra --anti-affinity machine --max-units 5
+`
+
+// SetCommand sets the placement policy of a service.
+type SetCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Policy      params.PlacementPolicy
+}
+
+// Info implements Command.Info.
+func (c *SetCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set",
+		Args:    "<service>",
+		Purpose: "set the placement policy of a service",
+		Doc:     setCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *SetCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.IntVar(&c.Policy.MaxUnits, "max-units", 0, "the maximum number of units, or 0 for no limit")
+	f.StringVar(&c.Policy.AntiAffinity, "anti-affinity", "", `the scope, "machine" or "zone", within which units may not be co-located`)
+	f.StringVar(&c.Policy.AffinityService, "affinity", "", "the service whose machines units must be placed on")
+}
+
+// Init implements Command.Init.
+func (c *SetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	c.ServiceName = args[0]
+	if !names.IsValidService(c.ServiceName) {
+		return errors.Errorf("invalid service name %q", c.ServiceName)
+	}
+	switch c.Policy.AntiAffinity {
+	case "", "machine", "zone":
+	default:
+		return errors.Errorf(`anti-affinity must be "machine" or "zone", not %q`, c.Policy.AntiAffinity)
+	}
+	if c.Policy.MaxUnits < 0 {
+		return errors.New("maximum units must not be negative")
+	}
+	if c.Policy.AffinityService != "" && !names.IsValidService(c.Policy.AffinityService) {
+		return errors.Errorf("invalid affinity service name %q", c.Policy.AffinityService)
+	}
+	if c.Policy == (params.PlacementPolicy{}) {
+		return errors.New("no placement policy specified")
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *SetCommand) Run(_ *cmd.Context) error {
+	api, err := getPlacementAPI(&c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	err = api.SetPlacementPolicy(c.ServiceName, c.Policy)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
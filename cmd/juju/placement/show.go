@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package placement
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// ShowCommand shows the placement policy of a service.
+type ShowCommand struct {
+	envcmd.EnvCommandBase
+	out         cmd.Output
+	ServiceName string
+}
+
+// Info implements Command.Info.
+func (c *ShowCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show",
+		Args:    "<service>",
+		Purpose: "show the placement policy of a service",
+		Doc:     "Show the placement policy of a service, if it has one.",
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ShowCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatPlacementTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *ShowCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	c.ServiceName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *ShowCommand) Run(ctx *cmd.Context) error {
+	api, err := getPlacementAPI(&c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	policy, err := api.PlacementPolicy(c.ServiceName)
+	if err != nil {
+		return err
+	}
+	output := PlacementInfo{Service: c.ServiceName}
+	if policy != nil {
+		output.Policy = &PolicyInfo{
+			MaxUnits:        policy.MaxUnits,
+			AntiAffinity:    policy.AntiAffinity,
+			AffinityService: policy.AffinityService,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// PlacementInfo defines the serialization behaviour of the placement
+// policy of a service.
+type PlacementInfo struct {
+	Service string      `yaml:"service" json:"service"`
+	Policy  *PolicyInfo `yaml:"policy,omitempty" json:"policy,omitempty"`
+}
+
+// PolicyInfo defines the serialization behaviour of a placement policy.
+type PolicyInfo struct {
+	MaxUnits        int    `yaml:"max-units,omitempty" json:"max-units,omitempty"`
+	AntiAffinity    string `yaml:"anti-affinity,omitempty" json:"anti-affinity,omitempty"`
+	AffinityService string `yaml:"affinity,omitempty" json:"affinity,omitempty"`
+}
+
+func formatPlacementTabular(value interface{}) ([]byte, error) {
+	info, ok := value.(PlacementInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", info, value)
+	}
+	var out bytes.Buffer
+	p := info.Policy
+	if p == nil {
+		fmt.Fprintf(&out, "service %q has no placement policy\n", info.Service)
+		return out.Bytes(), nil
+	}
+	maxUnits := "-"
+	if p.MaxUnits > 0 {
+		maxUnits = fmt.Sprint(p.MaxUnits)
+	}
+	antiAffinity := p.AntiAffinity
+	if antiAffinity == "" {
+		antiAffinity = "-"
+	}
+	affinity := p.AffinityService
+	if affinity == "" {
+		affinity = "-"
+	}
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "SERVICE\tMAX-UNITS\tANTI-AFFINITY\tAFFINITY")
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Service, maxUnits, antiAffinity, affinity)
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -8,15 +8,18 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
 )
 
 // RemoveServiceCommand causes an existing service to be destroyed.
 type RemoveServiceCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	DryRun      bool
 }
 
 func (c *RemoveServiceCommand) Info() *cmd.Info {
@@ -24,11 +27,22 @@ func (c *RemoveServiceCommand) Info() *cmd.Info {
 		Name:    "remove-service",
 		Args:    "<service>",
 		Purpose: "remove a service from the environment",
-		Doc:     "Removing a service will remove all its units and relations.",
+		Doc:     removeServiceDoc,
 		Aliases: []string{"destroy-service"},
 	}
 }
 
+const removeServiceDoc = `
+Removing a service will remove all its units and relations.
+
+With --dry-run, the removal is checked, and the relations and units that would
+be removed are reported, but the service is not removed.
+`
+
+func (c *RemoveServiceCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.DryRun, "dry-run", false, common.DryRunDoc)
+}
+
 func (c *RemoveServiceCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("no service specified")
@@ -40,11 +54,18 @@ func (c *RemoveServiceCommand) Init(args []string) error {
 	return cmd.CheckEmpty(args)
 }
 
-func (c *RemoveServiceCommand) Run(_ *cmd.Context) error {
+func (c *RemoveServiceCommand) Run(ctx *cmd.Context) error {
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return err
 	}
 	defer client.Close()
+	if c.DryRun {
+		plan, err := client.PlanServiceDestroy(c.ServiceName)
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockRemove)
+		}
+		return common.WritePlan(ctx, plan)
+	}
 	return block.ProcessBlockedError(client.ServiceDestroy(c.ServiceName), block.BlockRemove)
 }
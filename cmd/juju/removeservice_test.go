@@ -61,6 +61,29 @@ func (s *RemoveServiceSuite) TestBlockRemoveService(c *gc.C) {
 	c.Assert(riak.Life(), gc.Equals, state.Alive)
 }
 
+func (s *RemoveServiceSuite) TestDryRun(c *gc.C) {
+	s.setupTestService(c)
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&RemoveServiceCommand{}), "riak", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"dry run, no changes made; the command would:\n"+
+		"  destroy service \"riak\"\n"+
+		"  remove relation riak:ring\n"+
+		"  destroy unit riak/0\n"+
+		"units affected: riak/0\n",
+	)
+	riak, err := s.State.Service("riak")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(riak.Life(), gc.Equals, state.Alive)
+}
+
+func (s *RemoveServiceSuite) TestBlockRemoveServiceDryRun(c *gc.C) {
+	s.setupTestService(c)
+	s.BlockRemoveObject(c, "TestBlockRemoveServiceDryRun")
+	err := runRemoveService(c, "riak", "--dry-run")
+	s.AssertBlocked(c, err, ".*TestBlockRemoveServiceDryRun.*")
+}
+
 func (s *RemoveServiceSuite) TestFailure(c *gc.C) {
 	// Destroy a service that does not exist.
 	err := runRemoveService(c, "gargleblaster")
@@ -0,0 +1,111 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package selfheal
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const historyCommandDoc = `
+Show the most recent replacements of failed machines, newest first.
+`
+
+// HistoryCommand shows the most recent replacements of failed machines.
+type HistoryCommand struct {
+	envcmd.EnvCommandBase
+	out  cmd.Output
+	Size int
+}
+
+// Info implements Command.Info.
+func (c *HistoryCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "history",
+		Purpose: "show the replacements of failed machines",
+		Doc:     historyCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *HistoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.IntVar(&c.Size, "n", 10, "size of the history to show")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatHistoryTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *HistoryCommand) Init(args []string) error {
+	if c.Size < 1 {
+		return errors.Errorf("invalid history size %d", c.Size)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *HistoryCommand) Run(ctx *cmd.Context) error {
+	api, err := getSelfHealAPI(&c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	events, err := api.SelfHealHistory(c.Size)
+	if err != nil {
+		return err
+	}
+	output := make([]EventInfo, len(events))
+	for i, e := range events {
+		output[i] = EventInfo{
+			Time:        e.Time.Format(time.RFC3339),
+			Machine:     e.MachineId,
+			Replacement: e.ReplacementId,
+			Units:       e.Units,
+			Reason:      e.Reason,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// EventInfo defines the serialization behaviour of the replacement of
+// a failed machine.
+type EventInfo struct {
+	Time        string   `yaml:"time" json:"time"`
+	Machine     string   `yaml:"machine" json:"machine"`
+	Replacement string   `yaml:"replacement" json:"replacement"`
+	Units       []string `yaml:"units" json:"units"`
+	Reason      string   `yaml:"reason" json:"reason"`
+}
+
+func formatHistoryTabular(value interface{}) ([]byte, error) {
+	events, ok := value.([]EventInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", events, value)
+	}
+	var out bytes.Buffer
+	if len(events) == 0 {
+		fmt.Fprintln(&out, "no machines have been replaced")
+		return out.Bytes(), nil
+	}
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tMACHINE\tREPLACEMENT\tUNITS\tREASON")
+	for _, e := range events {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			e.Time, e.Machine, e.Replacement, strings.Join(e.Units, ","), e.Reason)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package selfheal
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const selfHealCommandDoc = `
+"juju self-heal" manages the replacement of failed machines.
+
+When the environment's self-heal setting is true, a machine whose
+instance is no longer reported by the provider, or whose agent has been
+lost, for longer than the environment's self-heal-grace-period (600
+seconds by default) is replaced. A new machine is provisioned with the
+same constraints, the units of the failed machine are moved to it, and
+the failed machine is destroyed.
+
+Only machines hosting nothing but units of services marked stateless,
+with "juju self-heal enable", are replaced: the units are deployed
+afresh on the new machine, and any data held on the failed machine is
+lost. Containers, machines hosting containers, manually provisioned
+machines and units with storage are never moved.
+
+To enable self-healing in the environment:
+
+    juju environment set self-heal=true
+
+Each replacement is recorded, and may be inspected with
+"juju self-heal history".
+`
+
+const selfHealCommandPurpose = "manage the replacement of failed machines"
+
+// NewSuperCommand creates the self-heal supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	selfHealCmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "self-heal",
+		Doc:         selfHealCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     selfHealCommandPurpose,
+	})
+	selfHealCmd.Register(envcmd.Wrap(&EnableCommand{}))
+	selfHealCmd.Register(envcmd.Wrap(&DisableCommand{}))
+	selfHealCmd.Register(envcmd.Wrap(&HistoryCommand{}))
+	return selfHealCmd
+}
+
+// SelfHealAPI defines the client API methods used by the self-heal
+// commands.
+type SelfHealAPI interface {
+	Close() error
+	SetServiceStateless(service string, stateless bool) error
+	SelfHealHistory(size int) ([]params.SelfHealEvent, error)
+}
+
+var getSelfHealAPI = func(c *envcmd.EnvCommandBase) (SelfHealAPI, error) {
+	return c.NewAPIClient()
+}
@@ -0,0 +1,146 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package selfheal_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/selfheal"
+	"github.com/juju/juju/testing"
+)
+
+type SelfHealSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeSelfHealAPI
+}
+
+var _ = gc.Suite(&SelfHealSuite{})
+
+func (s *SelfHealSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeSelfHealAPI{}
+	s.PatchValue(selfheal.GetSelfHealAPI, func(*envcmd.EnvCommandBase) (selfheal.SelfHealAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *SelfHealSuite) run(c *gc.C, command cmd.Command, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *SelfHealSuite) TestEnableInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no service name specified",
+	}, {
+		args: []string{"web!"},
+		err:  `invalid service name "web!"`,
+	}, {
+		args: []string{"web", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"web"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&selfheal.EnableCommand{}), test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *SelfHealSuite) TestEnable(c *gc.C) {
+	_, err := s.run(c, &selfheal.EnableCommand{}, "frontend")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.service, gc.Equals, "frontend")
+	c.Assert(s.api.stateless, jc.IsTrue)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *SelfHealSuite) TestDisable(c *gc.C) {
+	s.api.stateless = true
+	_, err := s.run(c, &selfheal.DisableCommand{}, "frontend")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.service, gc.Equals, "frontend")
+	c.Assert(s.api.stateless, jc.IsFalse)
+}
+
+var testEvents = []params.SelfHealEvent{{
+	Time:          time.Date(2015, 9, 1, 12, 0, 0, 0, time.UTC),
+	MachineId:     "3",
+	ReplacementId: "7",
+	Units:         []string{"frontend/0", "frontend/2"},
+	Reason:        `instance "i-123" is missing`,
+}}
+
+func (s *SelfHealSuite) TestHistory(c *gc.C) {
+	s.api.events = testEvents
+	ctx, err := s.run(c, &selfheal.HistoryCommand{}, "-n", "5")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"TIME                 MACHINE REPLACEMENT UNITS                 REASON\n"+
+		"2015-09-01T12:00:00Z 3       7           frontend/0,frontend/2 instance \"i-123\" is missing\n",
+	)
+	c.Assert(s.api.size, gc.Equals, 5)
+}
+
+func (s *SelfHealSuite) TestHistoryEmpty(c *gc.C) {
+	ctx, err := s.run(c, &selfheal.HistoryCommand{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "no machines have been replaced\n")
+	c.Assert(s.api.size, gc.Equals, 10)
+}
+
+func (s *SelfHealSuite) TestHistoryYAML(c *gc.C) {
+	s.api.events = testEvents
+	ctx, err := s.run(c, &selfheal.HistoryCommand{}, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"- time: \"2015-09-01T12:00:00Z\"\n"+
+		"  machine: \"3\"\n"+
+		"  replacement: \"7\"\n"+
+		"  units:\n"+
+		"  - frontend/0\n"+
+		"  - frontend/2\n"+
+		"  reason: instance \"i-123\" is missing\n",
+	)
+}
+
+func (s *SelfHealSuite) TestHistoryInvalidSize(c *gc.C) {
+	_, err := s.run(c, &selfheal.HistoryCommand{}, "-n", "0")
+	c.Assert(err, gc.ErrorMatches, "invalid history size 0")
+}
+
+type fakeSelfHealAPI struct {
+	service   string
+	stateless bool
+	size      int
+	events    []params.SelfHealEvent
+	closed    bool
+}
+
+func (f *fakeSelfHealAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeSelfHealAPI) SetServiceStateless(service string, stateless bool) error {
+	f.service, f.stateless = service, stateless
+	return nil
+}
+
+func (f *fakeSelfHealAPI) SelfHealHistory(size int) ([]params.SelfHealEvent, error) {
+	f.size = size
+	return f.events, nil
+}
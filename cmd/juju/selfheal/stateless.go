@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package selfheal
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const enableCommandDoc = `
+Mark a service as stateless, so that its units are moved to replacement
+machines when the machines they are assigned to fail. Machines are only
+replaced if the environment's self-heal setting is true.
+
+Example:
+
+    juju self-heal enable frontend
+`
+
+// EnableCommand marks a service as stateless.
+type EnableCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+}
+
+// Info implements Command.Info.
+func (c *EnableCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "enable",
+		Args:    "<service>",
+		Purpose: "allow the units of a stateless service to be moved from failed machines",
+		Doc:     enableCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *EnableCommand) Init(args []string) (err error) {
+	c.ServiceName, err = serviceNameArg(args)
+	return err
+}
+
+// Run implements Command.Run.
+func (c *EnableCommand) Run(_ *cmd.Context) error {
+	return setStateless(&c.EnvCommandBase, c.ServiceName, true)
+}
+
+// DisableCommand marks a service as not stateless.
+type DisableCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+}
+
+// Info implements Command.Info.
+func (c *DisableCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "disable",
+		Args:    "<service>",
+		Purpose: "stop moving the units of a service from failed machines",
+		Doc:     "Leave the units of the service on their machines should they fail.",
+	}
+}
+
+// Init implements Command.Init.
+func (c *DisableCommand) Init(args []string) (err error) {
+	c.ServiceName, err = serviceNameArg(args)
+	return err
+}
+
+// Run implements Command.Run.
+func (c *DisableCommand) Run(_ *cmd.Context) error {
+	return setStateless(&c.EnvCommandBase, c.ServiceName, false)
+}
+
+// serviceNameArg returns the service name given as the only argument.
+func serviceNameArg(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("no service name specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return "", errors.Errorf("invalid service name %q", args[0])
+	}
+	return args[0], cmd.CheckEmpty(args[1:])
+}
+
+func setStateless(c *envcmd.EnvCommandBase, service string, stateless bool) error {
+	api, err := getSelfHealAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return block.ProcessBlockedError(api.SetServiceStateless(service, stateless), block.BlockChange)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider"
 )
 
@@ -27,14 +28,18 @@ type UnitCommandBase struct {
 
 func (c *UnitCommandBase) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumUnits, "num-units", 1, "")
-	f.StringVar(&c.ToMachineSpec, "to", "", "the machine or container to deploy the unit in, bypasses constraints")
+	f.StringVar(&c.ToMachineSpec, "to", "", "the machine or container to deploy the unit in, bypasses constraints, or the availability zones to spread units across")
 }
 
 func (c *UnitCommandBase) Init(args []string) error {
 	if c.NumUnits < 1 {
 		return errors.New("--num-units must be a positive integer")
 	}
-	if c.ToMachineSpec != "" {
+	if instance.IsZonePlacements(c.ToMachineSpec) {
+		if _, err := instance.ParseZonePlacements(c.ToMachineSpec); err != nil {
+			return fmt.Errorf("invalid --to parameter: %v", err)
+		}
+	} else if c.ToMachineSpec != "" {
 		if c.NumUnits > 1 {
 			return errors.New("cannot use --num-units > 1 with --to")
 		}
@@ -74,8 +79,9 @@ var GetClientConfig = func(client ServiceAddUnitAPI) (*config.Config, error) {
 type AddUnitCommand struct {
 	envcmd.EnvCommandBase
 	UnitCommandBase
-	ServiceName string
-	api         ServiceAddUnitAPI
+	ServiceName   string
+	AttachStorage []string
+	api           ServiceAddUnitAPI
 }
 
 const addUnitDoc = `
@@ -85,13 +91,23 @@ have already been deployed via juju deploy.
 
 By default, services are deployed to newly provisioned machines.  Alternatively,
 service units can be added to a specific existing machine using the --to
-argument.
+argument, or spread across a list of availability zones, each unit being
+added to a new machine in the zone with the fewest of the service's units.
+
+Storage instances that have been detached from other units with
+"juju storage detach" may be attached to a new unit using the
+--attach-storage argument. The unit is always deployed to a newly
+provisioned machine, and only one unit may be added at a time.
 
 Examples:
  juju service add-unit mysql -n 5          (Add 5 mysql units on 5 new machines)
  juju service add-unit mysql --to 23       (Add a mysql unit to machine 23)
  juju service add-unit mysql --to 24/lxc/3 (Add unit to lxc container 3 on host machine 24)
  juju service add-unit mysql --to lxc:25   (Add unit to a new lxc container on host machine 25)
+ juju service add-unit mysql -n 2 --to zone=us-east-1a,zone=us-east-1b
+                                           (Add 2 mysql units on new machines, spread across the zones)
+ juju service add-unit mysql --attach-storage data/0
+                                           (Add a mysql unit, attaching the detached storage data/0)
 `
 
 func (c *AddUnitCommand) Info() *cmd.Info {
@@ -106,6 +122,7 @@ func (c *AddUnitCommand) Info() *cmd.Info {
 func (c *AddUnitCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.UnitCommandBase.SetFlags(f)
 	f.IntVar(&c.NumUnits, "n", 1, "number of service units to add")
+	f.Var(cmd.NewStringsValue(nil, &c.AttachStorage), "attach-storage", "existing storage instances to attach to the new unit")
 }
 
 func (c *AddUnitCommand) Init(args []string) error {
@@ -118,6 +135,19 @@ func (c *AddUnitCommand) Init(args []string) error {
 	if err := cmd.CheckEmpty(args[1:]); err != nil {
 		return err
 	}
+	if len(c.AttachStorage) > 0 {
+		if c.NumUnits > 1 {
+			return errors.New("cannot use --num-units > 1 with --attach-storage")
+		}
+		if c.ToMachineSpec != "" {
+			return errors.New("cannot use --to with --attach-storage")
+		}
+		for _, id := range c.AttachStorage {
+			if !names.IsValidStorage(id) {
+				return fmt.Errorf("invalid storage id %q", id)
+			}
+		}
+	}
 	return c.UnitCommandBase.Init(args)
 }
 
@@ -126,6 +156,7 @@ func (c *AddUnitCommand) Init(args []string) error {
 type ServiceAddUnitAPI interface {
 	Close() error
 	AddServiceUnits(service string, numUnits int, machineSpec string) ([]string, error)
+	AddServiceUnitWithStorage(service string, storageIds []string) (string, error)
 	EnvironmentGet() (map[string]interface{}, error)
 }
 
@@ -154,7 +185,11 @@ func (c *AddUnitCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 
-	_, err = apiclient.AddServiceUnits(c.ServiceName, c.NumUnits, c.ToMachineSpec)
+	if len(c.AttachStorage) > 0 {
+		_, err = apiclient.AddServiceUnitWithStorage(c.ServiceName, c.AttachStorage)
+	} else {
+		_, err = apiclient.AddServiceUnits(c.ServiceName, c.NumUnits, c.ToMachineSpec)
+	}
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
 
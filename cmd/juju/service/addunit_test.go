@@ -27,6 +27,7 @@ type fakeServiceAddUnitAPI struct {
 	service     string
 	numUnits    int
 	machineSpec string
+	storageIds  []string
 	err         error
 }
 
@@ -50,6 +51,20 @@ func (f *fakeServiceAddUnitAPI) AddServiceUnits(service string, numUnits int, ma
 	return nil, nil
 }
 
+func (f *fakeServiceAddUnitAPI) AddServiceUnitWithStorage(service string, storageIds []string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+
+	if service != f.service {
+		return "", errors.NotFoundf("service %q", service)
+	}
+
+	f.numUnits++
+	f.storageIds = storageIds
+	return "", nil
+}
+
 func (f *fakeServiceAddUnitAPI) EnvironmentGet() (map[string]interface{}, error) {
 	cfg, err := config.New(config.UseDefaults, map[string]interface{}{
 		"type": f.envType,
@@ -85,6 +100,21 @@ var initAddUnitErrorTests = []struct {
 	}, {
 		args: []string{"some-service-name", "-n", "2", "--to", "123"},
 		err:  `cannot use --num-units > 1 with --to`,
+	}, {
+		args: []string{"some-service-name", "--to", "zone=a,b"},
+		err:  `invalid --to parameter: invalid zone placement "b": expected zone=<availability-zone-name>`,
+	}, {
+		args: []string{"some-service-name", "--to", "zone=a", "--attach-storage", "data/0"},
+		err:  `cannot use --to with --attach-storage`,
+	}, {
+		args: []string{"some-service-name", "-n", "2", "--attach-storage", "data/0"},
+		err:  `cannot use --num-units > 1 with --attach-storage`,
+	}, {
+		args: []string{"some-service-name", "--to", "1", "--attach-storage", "data/0"},
+		err:  `cannot use --to with --attach-storage`,
+	}, {
+		args: []string{"some-service-name", "--attach-storage", "data"},
+		err:  `invalid storage id "data"`,
 	},
 }
 
@@ -111,6 +141,13 @@ func (s *AddUnitSuite) TestAddUnit(c *gc.C) {
 	c.Assert(s.fake.numUnits, gc.Equals, 4)
 }
 
+func (s *AddUnitSuite) TestAddUnitAttachStorage(c *gc.C) {
+	err := s.runAddUnit(c, "some-service-name", "--attach-storage", "data/0,logs/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.numUnits, gc.Equals, 2)
+	c.Assert(s.fake.storageIds, jc.DeepEquals, []string{"data/0", "logs/1"})
+}
+
 func (s *AddUnitSuite) TestBlockAddUnit(c *gc.C) {
 	// Block operation
 	s.fake.err = common.ErrOperationBlocked("TestBlockAddUnit")
@@ -151,6 +188,13 @@ func (s *AddUnitSuite) TestForceMachineNewContainer(c *gc.C) {
 	c.Assert(s.fake.machineSpec, gc.Equals, "lxc:1")
 }
 
+func (s *AddUnitSuite) TestForceZones(c *gc.C) {
+	err := s.runAddUnit(c, "some-service-name", "-n", "3", "--to", "zone=us-east-1a,zone=us-east-1b")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.numUnits, gc.Equals, 4)
+	c.Assert(s.fake.machineSpec, gc.Equals, "zone=us-east-1a,zone=us-east-1b")
+}
+
 func (s *AddUnitSuite) TestNameChecks(c *gc.C) {
 	assertMachineOrNewContainer := func(s string, expect bool) {
 		c.Logf("%s -> %v", s, expect)
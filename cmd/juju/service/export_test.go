@@ -30,3 +30,11 @@ func NewAddUnitCommand(api ServiceAddUnitAPI) *AddUnitCommand {
 		api: api,
 	}
 }
+
+// NewSetStorageDefaultsCommand returns a SetStorageDefaultsCommand with the
+// api provided as specified.
+func NewSetStorageDefaultsCommand(api SetStorageDefaultsAPI) *SetStorageDefaultsCommand {
+	return &SetStorageDefaultsCommand{
+		api: api,
+	}
+}
@@ -26,6 +26,7 @@ var expectedCommmandNames = []string{
 	"help",
 	"set",
 	"set-constraints",
+	"set-storage-defaults",
 	"unset",
 }
 
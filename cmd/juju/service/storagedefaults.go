@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	apiservice "github.com/juju/juju/api/service"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const setStorageDefaultsDoc = `
+Sets the storage pools from which the specified service's storage is
+provisioned when storage constraints do not specify a pool. The default
+pool may be set for each kind of storage: "block" and "filesystem".
+
+Service storage defaults take precedence over the environment's defaults,
+which are set with the "storage-default-block-source" and
+"storage-default-filesystem-source" environment config keys. Specifying
+an empty pool name removes the service's default for that kind of storage,
+so that the environment's default applies.
+
+Example:
+
+    set-storage-defaults postgresql block=ebs-ssd      (use the "ebs-ssd" pool for block storage)
+    set-storage-defaults postgresql block= filesystem= (use the environment's defaults)
+
+See Also:
+   juju help deploy
+   juju storage help add
+   juju storage pool help list
+`
+
+// SetStorageDefaultsCommand sets the storage pool defaults for a service.
+type SetStorageDefaultsCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName    string
+	BlockPool      string
+	FilesystemPool string
+	api            SetStorageDefaultsAPI
+}
+
+func (c *SetStorageDefaultsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-storage-defaults",
+		Args:    "<service> [block=<pool>] [filesystem=<pool>]",
+		Purpose: "set default storage pools for a service",
+		Doc:     setStorageDefaultsDoc,
+	}
+}
+
+func (c *SetStorageDefaultsCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return errors.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName, args = args[0], args[1:]
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 {
+			return errors.Errorf(`expected "kind=pool", got %q`, arg)
+		}
+		switch kv[0] {
+		case "block":
+			c.BlockPool = kv[1]
+		case "filesystem":
+			c.FilesystemPool = kv[1]
+		default:
+			return errors.Errorf("unknown storage kind %q", kv[0])
+		}
+	}
+	return nil
+}
+
+// SetStorageDefaultsAPI defines the methods on the service API
+// that the set-storage-defaults command calls.
+type SetStorageDefaultsAPI interface {
+	Close() error
+	SetStorageDefaults(service, blockPool, filesystemPool string) error
+}
+
+func (c *SetStorageDefaultsCommand) getAPI() (SetStorageDefaultsAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return apiservice.NewClient(root), nil
+}
+
+// Run sets the storage pool defaults for the service.
+func (c *SetStorageDefaultsCommand) Run(ctx *cmd.Context) error {
+	apiclient, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer apiclient.Close()
+	err = apiclient.SetStorageDefaults(c.ServiceName, c.BlockPool, c.FilesystemPool)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
@@ -0,0 +1,105 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/service"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type SetStorageDefaultsSuite struct {
+	coretesting.FakeJujuHomeSuite
+	fake *fakeStorageDefaultsAPI
+}
+
+var _ = gc.Suite(&SetStorageDefaultsSuite{})
+
+func (s *SetStorageDefaultsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeStorageDefaultsAPI{}
+}
+
+func (s *SetStorageDefaultsSuite) runSetStorageDefaults(c *gc.C, args ...string) error {
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(service.NewSetStorageDefaultsCommand(s.fake)), args...)
+	return err
+}
+
+func (s *SetStorageDefaultsSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no service name specified",
+	}, {
+		args: []string{"invalid/service"},
+		err:  `invalid service name "invalid/service"`,
+	}, {
+		args: []string{"mysql", "block"},
+		err:  `expected "kind=pool", got "block"`,
+	}, {
+		args: []string{"mysql", "shared=ebs"},
+		err:  `unknown storage kind "shared"`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := coretesting.InitCommand(&service.SetStorageDefaultsCommand{}, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *SetStorageDefaultsSuite) TestSetStorageDefaults(c *gc.C) {
+	err := s.runSetStorageDefaults(c, "mysql", "block=ebs-ssd", "filesystem=rootfs")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.service, gc.Equals, "mysql")
+	c.Assert(s.fake.blockPool, gc.Equals, "ebs-ssd")
+	c.Assert(s.fake.filesystemPool, gc.Equals, "rootfs")
+}
+
+func (s *SetStorageDefaultsSuite) TestClearStorageDefaults(c *gc.C) {
+	s.fake.blockPool = "ebs-ssd"
+	err := s.runSetStorageDefaults(c, "mysql", "block=")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.service, gc.Equals, "mysql")
+	c.Assert(s.fake.blockPool, gc.Equals, "")
+	c.Assert(s.fake.filesystemPool, gc.Equals, "")
+}
+
+func (s *SetStorageDefaultsSuite) TestBlockSetStorageDefaults(c *gc.C) {
+	s.fake.err = common.ErrOperationBlocked("TestBlockSetStorageDefaults")
+	err := s.runSetStorageDefaults(c, "mysql", "block=ebs-ssd")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*TestBlockSetStorageDefaults.*")
+}
+
+type fakeStorageDefaultsAPI struct {
+	service        string
+	blockPool      string
+	filesystemPool string
+	err            error
+}
+
+func (f *fakeStorageDefaultsAPI) Close() error {
+	return nil
+}
+
+func (f *fakeStorageDefaultsAPI) SetStorageDefaults(service, blockPool, filesystemPool string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.service = service
+	f.blockPool = blockPool
+	f.filesystemPool = filesystemPool
+	return nil
+}
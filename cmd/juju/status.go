@@ -29,6 +29,7 @@ type StatusCommand struct {
 	out      cmd.Output
 	patterns []string
 	isoTime  bool
+	watch    bool
 }
 
 var statusDoc = `
@@ -60,6 +61,12 @@ will be displayed.
 Wildcards ('*') may be specified in service/unit names to match any sequence
 of characters. For example, 'nova-*' will match any service whose name begins
 with 'nova-': 'nova-compute', 'nova-volume', etc.
+
+The --watch flag keeps the command running: the tabular status is rendered
+once (regardless of --format), and then rows are redrawn as the environment
+changes. Alternatively,
+--format=json-stream (which implies --watch) emits one JSON-encoded change
+per line, suitable for consumption by dashboards and other tools.
 `
 
 func (c *StatusCommand) Info() *cmd.Info {
@@ -74,6 +81,7 @@ func (c *StatusCommand) Info() *cmd.Info {
 
 func (c *StatusCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	f.BoolVar(&c.watch, "watch", false, "keep running and redraw status as the environment changes")
 
 	defaultFormat := "yaml"
 	if featureflag.Enabled(feature.NewStatus) {
@@ -87,11 +95,17 @@ func (c *StatusCommand) SetFlags(f *gnuflag.FlagSet) {
 		"line":    FormatOneline,
 		"tabular": FormatTabular,
 		"summary": FormatSummary,
+		// json-stream is only meaningful when watching;
+		// see runWatch.
+		jsonStreamFormat: cmd.FormatJson,
 	})
 }
 
 func (c *StatusCommand) Init(args []string) error {
 	c.patterns = args
+	if c.out.Name() == jsonStreamFormat {
+		c.watch = true
+	}
 	// If use of ISO time not specified on command line,
 	// check env var.
 	if !c.isoTime {
@@ -123,6 +137,9 @@ var newApiClientForStatus = func(c *StatusCommand) (statusAPI, error) {
 }
 
 func (c *StatusCommand) Run(ctx *cmd.Context) error {
+	if c.watch {
+		return c.runWatch(ctx)
+	}
 
 	apiclient, err := newApiClientForStatus(c)
 	if err != nil {
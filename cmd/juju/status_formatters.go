@@ -110,26 +110,12 @@ func FormatTabular(value interface{}) ([]byte, error) {
 		for un, u := range svc.Units {
 			units[un] = u
 		}
-		p(svcName, svc.StatusInfo.Current, fmt.Sprintf("%t", svc.Exposed), svc.Charm)
+		p(tabularServiceRow(svcName, svc)...)
 	}
 	tw.Flush()
 
 	pUnit := func(name string, u unitStatus, level int) {
-		message := u.WorkloadStatusInfo.Message
-		agentDoing := agentDoing(u.AgentStatusInfo)
-		if agentDoing != "" {
-			message = fmt.Sprintf("(%s) %s", agentDoing, message)
-		}
-		p(
-			indent("", level*2, name),
-			u.WorkloadStatusInfo.Current,
-			u.AgentStatusInfo.Current,
-			u.AgentStatusInfo.Version,
-			u.Machine,
-			strings.Join(u.OpenedPorts, ","),
-			u.PublicAddress,
-			message,
-		)
+		p(tabularUnitRow(indent("", level*2, name), u)...)
 	}
 
 	// See if we have new or old data; that determines what data we can display.
@@ -161,13 +147,45 @@ func FormatTabular(value interface{}) ([]byte, error) {
 	p("ID\tSTATE\tVERSION\tDNS\tINS-ID\tSERIES\tHARDWARE")
 	for _, name := range sortStringsNaturally(stringKeysFromMap(fs.Machines)) {
 		m := fs.Machines[name]
-		p(m.Id, m.AgentState, m.AgentVersion, m.DNSName, m.InstanceId, m.Series, m.Hardware)
+		p(tabularMachineRow(m)...)
 	}
 	tw.Flush()
 
 	return out.Bytes(), nil
 }
 
+// tabularServiceRow returns the values displayed for a service
+// in the [Services] section of the tabular format.
+func tabularServiceRow(name string, svc serviceStatus) []interface{} {
+	return []interface{}{name, svc.StatusInfo.Current, fmt.Sprintf("%t", svc.Exposed), svc.Charm}
+}
+
+// tabularUnitRow returns the values displayed for a unit
+// in the [Units] section of the tabular format.
+func tabularUnitRow(name string, u unitStatus) []interface{} {
+	message := u.WorkloadStatusInfo.Message
+	agentDoing := agentDoing(u.AgentStatusInfo)
+	if agentDoing != "" {
+		message = fmt.Sprintf("(%s) %s", agentDoing, message)
+	}
+	return []interface{}{
+		name,
+		u.WorkloadStatusInfo.Current,
+		u.AgentStatusInfo.Current,
+		u.AgentStatusInfo.Version,
+		u.Machine,
+		strings.Join(u.OpenedPorts, ","),
+		u.PublicAddress,
+		message,
+	}
+}
+
+// tabularMachineRow returns the values displayed for a machine
+// in the [Machines] section of the tabular format.
+func tabularMachineRow(m machineStatus) []interface{} {
+	return []interface{}{m.Id, m.AgentState, m.AgentVersion, m.DNSName, m.InstanceId, m.Series, m.Hardware}
+}
+
 // FormatSummary returns a summary of the current environment
 // including the following information:
 // - Headers:
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
@@ -24,44 +25,57 @@ type StatusHistoryCommand struct {
 	outputContent string
 	backlogSize   int
 	isoTime       bool
-	unitName      string
+	entity        names.Tag
 }
 
 var statusHistoryDoc = `
 This command will report the history of status changes for
-a given unit.
+a given unit, machine or service.
+
+The entity may be given as a unit name (mysql/0), a machine id (3),
+a service name (mysql), a tag (unit-mysql-0, machine-3,
+service-mysql), or as a kind followed by a name (unit mysql/0,
+machine 3, service mysql). A name that is also a valid tag is taken
+to be a tag; use the kind to name such a service.
+
 The statuses for the unit workload and/or agent are available.
+For machines, the statuses of the machine agent and/or the
+provider specific statuses of its instance are available.
 -type supports:
-    agent: will show statuses for the unit's agent
+    agent: will show statuses for the unit's or machine's agent
     workload: will show statuses for the unit's workload
-    combined: will show agent and workload statuses combined
+    instance: will show statuses for the machine's instance
+    combined: will show all statuses for the entity combined
  and sorted by time of occurence.
 `
 
 func (c *StatusHistoryCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "status-history",
-		Args:    "[-n N] <unit>",
-		Purpose: "output past statuses for a unit",
+		Args:    "[-n N] <unit>|<machine-id>|<service-name>",
+		Purpose: "output past statuses for a unit, machine or service",
 		Doc:     statusHistoryDoc,
 	}
 }
 
 func (c *StatusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
-	f.StringVar(&c.outputContent, "type", "combined", "type of statuses to be displayed [agent|workload|combined].")
+	f.StringVar(&c.outputContent, "type", "combined", "type of statuses to be displayed [agent|workload|instance|combined].")
 	f.IntVar(&c.backlogSize, "n", 20, "size of logs backlog.")
 	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
 }
 
 func (c *StatusHistoryCommand) Init(args []string) error {
 	switch {
-	case len(args) > 1:
-		return errors.Errorf("unexpected arguments after unit name.")
+	case len(args) > 2:
+		return errors.Errorf("unexpected arguments after entity name.")
 	case len(args) == 0:
 		return errors.Errorf("unit name is missing.")
-	default:
-		c.unitName = args[0]
 	}
+	entity, err := parseStatusHistoryEntity(args)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.entity = entity
 	// If use of ISO time not specified on command line,
 	// check env var.
 	if !c.isoTime {
@@ -75,13 +89,60 @@ func (c *StatusHistoryCommand) Init(args []string) error {
 	}
 	kind := params.HistoryKind(c.outputContent)
 	switch kind {
-	case params.KindCombined, params.KindAgent, params.KindWorkload:
+	case params.KindCombined:
 		return nil
-
+	case params.KindAgent:
+		if c.entity.Kind() != names.ServiceTagKind {
+			return nil
+		}
+	case params.KindWorkload:
+		if c.entity.Kind() == names.UnitTagKind {
+			return nil
+		}
+	case params.KindInstance:
+		if c.entity.Kind() == names.MachineTagKind {
+			return nil
+		}
 	}
 	return errors.Errorf("unexpected status type %q", c.outputContent)
 }
 
+// parseStatusHistoryEntity returns the tag of the entity identified by
+// the command line arguments: a unit name, machine id, tag or service
+// name, or a kind followed by a name.
+func parseStatusHistoryEntity(args []string) (names.Tag, error) {
+	if len(args) == 2 {
+		kind, id := args[0], args[1]
+		switch {
+		case kind == names.UnitTagKind && names.IsValidUnit(id):
+			return names.NewUnitTag(id), nil
+		case kind == names.MachineTagKind && names.IsValidMachine(id):
+			return names.NewMachineTag(id), nil
+		case kind == names.ServiceTagKind && names.IsValidService(id):
+			return names.NewServiceTag(id), nil
+		}
+		return nil, errors.Errorf("invalid %s %q", kind, id)
+	}
+	switch {
+	case names.IsValidUnit(args[0]):
+		return names.NewUnitTag(args[0]), nil
+	case names.IsValidMachine(args[0]):
+		return names.NewMachineTag(args[0]), nil
+	}
+	tag, err := names.ParseTag(args[0])
+	if err != nil {
+		if names.IsValidService(args[0]) {
+			return names.NewServiceTag(args[0]), nil
+		}
+		return nil, errors.Errorf("%q is not a valid unit, machine or service name or entity tag", args[0])
+	}
+	switch tag.(type) {
+	case names.UnitTag, names.MachineTag, names.ServiceTag:
+		return tag, nil
+	}
+	return nil, errors.Errorf("status history is not available for %q", args[0])
+}
+
 func (c *StatusHistoryCommand) Run(ctx *cmd.Context) error {
 	apiclient, err := c.NewAPIClient()
 	if err != nil {
@@ -90,7 +151,12 @@ func (c *StatusHistoryCommand) Run(ctx *cmd.Context) error {
 	defer apiclient.Close()
 	var statuses *api.UnitStatusHistory
 	kind := params.HistoryKind(c.outputContent)
-	statuses, err = apiclient.UnitStatusHistory(kind, c.unitName, c.backlogSize)
+	if c.entity.Kind() == names.UnitTagKind {
+		// Use the original API call so older servers are supported.
+		statuses, err = apiclient.UnitStatusHistory(kind, c.entity.Id(), c.backlogSize)
+	} else {
+		statuses, err = apiclient.EntityStatusHistory(kind, c.entity, c.backlogSize)
+	}
 	if err != nil {
 		if len(statuses.Statuses) == 0 {
 			return errors.Trace(err)
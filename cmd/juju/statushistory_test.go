@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type StatusHistorySuite struct {
+	testing.FakeJujuHomeSuite
+}
+
+var _ = gc.Suite(&StatusHistorySuite{})
+
+func (s *StatusHistorySuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args   []string
+		entity names.Tag
+		err    string
+	}{{
+		err: "unit name is missing.",
+	}, {
+		args:   []string{"mysql/0"},
+		entity: names.NewUnitTag("mysql/0"),
+	}, {
+		args:   []string{"unit-mysql-0"},
+		entity: names.NewUnitTag("mysql/0"),
+	}, {
+		args:   []string{"machine-3"},
+		entity: names.NewMachineTag("3"),
+	}, {
+		args:   []string{"machine", "3/lxc/0"},
+		entity: names.NewMachineTag("3/lxc/0"),
+	}, {
+		args:   []string{"service", "mysql"},
+		entity: names.NewServiceTag("mysql"),
+	}, {
+		args:   []string{"--type", "instance", "machine-3"},
+		entity: names.NewMachineTag("3"),
+	}, {
+		args: []string{"--type", "instance", "mysql/0"},
+		err:  `unexpected status type "instance"`,
+	}, {
+		args: []string{"--type", "agent", "service", "mysql"},
+		err:  `unexpected status type "agent"`,
+	}, {
+		args: []string{"service", "mysql/0"},
+		err:  `invalid service "mysql/0"`,
+	}, {
+		args: []string{"user-bob"},
+		err:  `status history is not available for "user-bob"`,
+	}, {
+		args:   []string{"3"},
+		entity: names.NewMachineTag("3"),
+	}, {
+		args:   []string{"3/lxc/0"},
+		entity: names.NewMachineTag("3/lxc/0"),
+	}, {
+		args:   []string{"mysql"},
+		entity: names.NewServiceTag("mysql"),
+	}, {
+		args: []string{"--type", "agent", "mysql"},
+		err:  `unexpected status type "agent"`,
+	}, {
+		args: []string{"mysql/x"},
+		err:  `"mysql/x" is not a valid unit, machine or service name or entity tag`,
+	}, {
+		args: []string{"service", "mysql", "extra"},
+		err:  "unexpected arguments after entity name.",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &StatusHistoryCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.entity, gc.Equals, test.entity)
+	}
+}
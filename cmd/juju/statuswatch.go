@@ -0,0 +1,417 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// jsonStreamFormat is the name of the status output format that
+// writes each AllWatcher delta as a single line of JSON.
+const jsonStreamFormat = "json-stream"
+
+// allWatcher is the subset of *api.AllWatcher used by
+// "juju status --watch".
+type allWatcher interface {
+	Next() ([]multiwatcher.Delta, error)
+	Stop() error
+}
+
+// statusWatchAPI is the API used by "juju status --watch".
+type statusWatchAPI interface {
+	statusAPI
+	WatchAll() (allWatcher, error)
+}
+
+// statusWatchClient adapts an *api.Client to statusWatchAPI.
+type statusWatchClient struct {
+	*api.Client
+}
+
+// WatchAll is part of the statusWatchAPI interface.
+func (c statusWatchClient) WatchAll() (allWatcher, error) {
+	watcher, err := c.Client.WatchAll()
+	if err != nil {
+		return nil, err
+	}
+	return watcher, nil
+}
+
+var newAPIClientForStatusWatch = func(c *StatusCommand) (statusWatchAPI, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return statusWatchClient{client}, nil
+}
+
+// runWatch renders the status and then keeps it up to date using
+// the deltas reported by the environment's AllWatcher. It only
+// returns when the watcher fails or is stopped.
+func (c *StatusCommand) runWatch(ctx *cmd.Context) error {
+	apiclient, err := newAPIClientForStatusWatch(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer apiclient.Close()
+
+	if c.out.Name() == jsonStreamFormat {
+		watcher, err := apiclient.WatchAll()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer watcher.Stop()
+		return streamDeltas(ctx.Stdout, watcher)
+	}
+
+	status, err := apiclient.Status(c.patterns)
+	if err != nil {
+		if status == nil {
+			return err
+		}
+		fmt.Fprintf(ctx.Stderr, "%v\n", err)
+	} else if status == nil {
+		return errors.Errorf("unable to obtain the current status")
+	}
+	sw := newStatusWatchFormatter(newStatusFormatter(status, c.isoTime).format(), c.isoTime)
+	// When filtering, only entities in the initial status are tracked.
+	sw.onlyKnown = len(c.patterns) > 0
+
+	out, err := FormatTabular(sw.status)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := ctx.Stdout.Write(out); err != nil {
+		return errors.Trace(err)
+	}
+
+	watcher, err := apiclient.WatchAll()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer watcher.Stop()
+	for {
+		deltas, err := watcher.Next()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if out := sw.update(deltas); len(out) > 0 {
+			if _, err := ctx.Stdout.Write(out); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// streamDeltas writes every delta reported by the watcher to w as
+// a line of JSON, until the watcher fails.
+func streamDeltas(w io.Writer, watcher allWatcher) error {
+	for {
+		deltas, err := watcher.Next()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, d := range deltas {
+			data, err := json.Marshal(&d)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if _, err := fmt.Fprintf(w, "%s\n", data); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// statusWatchFormatter maintains a formattedStatus from AllWatcher
+// deltas and reports the tabular rows that changed.
+type statusWatchFormatter struct {
+	status    formattedStatus
+	isoTime   bool
+	onlyKnown bool
+	// now is used to timestamp each redraw.
+	now func() time.Time
+}
+
+func newStatusWatchFormatter(status formattedStatus, isoTime bool) *statusWatchFormatter {
+	if status.Machines == nil {
+		status.Machines = make(map[string]machineStatus)
+	}
+	if status.Services == nil {
+		status.Services = make(map[string]serviceStatus)
+	}
+	return &statusWatchFormatter{
+		status:  status,
+		isoTime: isoTime,
+		now:     time.Now,
+	}
+}
+
+// update applies the deltas to the tracked status and returns the
+// tabular rows that were changed or removed by them. If nothing
+// visible changed, update returns nil.
+func (sw *statusWatchFormatter) update(deltas []multiwatcher.Delta) []byte {
+	before := tabularRows(sw.status)
+	for _, d := range deltas {
+		sw.apply(d)
+	}
+	after := tabularRows(sw.status)
+
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	changed := false
+	for _, section := range tabularSections {
+		var lines []string
+		for _, key := range sortStringsNaturally(stringKeysFromMap(after[section])) {
+			if row := after[section][key]; row != before[section][key] {
+				lines = append(lines, row)
+			}
+		}
+		for _, key := range sortStringsNaturally(stringKeysFromMap(before[section])) {
+			if _, ok := after[section][key]; !ok {
+				lines = append(lines, key+"\t(removed)\t")
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		if !changed {
+			t := sw.now()
+			fmt.Fprintf(tw, "\n[Changes at %s]\n", formatStatusTime(&t, sw.isoTime))
+			changed = true
+		}
+		fmt.Fprintf(tw, "[%s]\n", section)
+		for _, line := range lines {
+			fmt.Fprintln(tw, line)
+		}
+	}
+	if !changed {
+		return nil
+	}
+	tw.Flush()
+	return out.Bytes()
+}
+
+var tabularSections = []string{"Services", "Units", "Machines"}
+
+// tabularRows returns the tab-separated rows of the tabular format,
+// keyed first by section and then by entity name.
+func tabularRows(fs formattedStatus) map[string]map[string]string {
+	join := func(values []interface{}) string {
+		var buf bytes.Buffer
+		for _, v := range values {
+			fmt.Fprintf(&buf, "%s\t", v)
+		}
+		return buf.String()
+	}
+	rows := map[string]map[string]string{
+		"Services": make(map[string]string),
+		"Units":    make(map[string]string),
+		"Machines": make(map[string]string),
+	}
+	for name, svc := range fs.Services {
+		rows["Services"][name] = join(tabularServiceRow(name, svc))
+		addUnit := func(name string, u unitStatus, level int) {
+			rows["Units"][name] = join(tabularUnitRow(name, u))
+		}
+		for name, u := range svc.Units {
+			addUnit(name, u, 0)
+			recurseUnits(u, 1, addUnit)
+		}
+	}
+	for _, m := range fs.Machines {
+		rows["Machines"][m.Id] = join(tabularMachineRow(m))
+	}
+	return rows
+}
+
+// apply updates the tracked status with a single delta.
+func (sw *statusWatchFormatter) apply(d multiwatcher.Delta) {
+	switch info := d.Entity.(type) {
+	case *multiwatcher.MachineInfo:
+		sw.applyMachine(info, d.Removed)
+	case *multiwatcher.ServiceInfo:
+		sw.applyService(info, d.Removed)
+	case *multiwatcher.UnitInfo:
+		sw.applyUnit(info, d.Removed)
+	}
+}
+
+func (sw *statusWatchFormatter) applyMachine(info *multiwatcher.MachineInfo, removed bool) {
+	// Containers are nested within their host machine.
+	machines := sw.status.Machines
+	if parentId := parentMachineId(info.Id); parentId != "" {
+		parent, ok := sw.status.Machines[parentId]
+		if !ok {
+			return
+		}
+		if parent.Containers == nil {
+			parent.Containers = make(map[string]machineStatus)
+			sw.status.Machines[parentId] = parent
+		}
+		machines = parent.Containers
+	}
+	m, known := machines[info.Id]
+	if removed {
+		delete(machines, info.Id)
+		return
+	}
+	if !known && sw.onlyKnown {
+		return
+	}
+	m.Id = info.Id
+	m.AgentState = params.Status(info.Status)
+	m.AgentStateInfo = info.StatusInfo
+	m.InstanceId = instance.Id(info.InstanceId)
+	m.Series = info.Series
+	m.Life = lifeString(info.Life)
+	m.DNSName = network.SelectPublicAddress(info.Addresses)
+	if info.HardwareCharacteristics != nil {
+		m.Hardware = info.HardwareCharacteristics.String()
+	}
+	m.HAStatus = ""
+	for _, job := range info.Jobs {
+		if job == multiwatcher.JobManageEnviron {
+			m.HAStatus = makeHAStatus(info.HasVote, info.WantsVote)
+			break
+		}
+	}
+	machines[info.Id] = m
+}
+
+func (sw *statusWatchFormatter) applyService(info *multiwatcher.ServiceInfo, removed bool) {
+	svc, known := sw.status.Services[info.Name]
+	if removed {
+		delete(sw.status.Services, info.Name)
+		return
+	}
+	if !known && sw.onlyKnown {
+		return
+	}
+	svc.Charm = info.CharmURL
+	svc.Exposed = info.Exposed
+	svc.Life = lifeString(info.Life)
+	svc.StatusInfo = sw.statusInfo(info.Status)
+	if svc.Units == nil {
+		svc.Units = make(map[string]unitStatus)
+	}
+	sw.status.Services[info.Name] = svc
+}
+
+func (sw *statusWatchFormatter) applyUnit(info *multiwatcher.UnitInfo, removed bool) {
+	svc, ok := sw.status.Services[info.Service]
+	if !ok {
+		// We will learn about the unit again once its
+		// service has been reported.
+		return
+	}
+	if removed {
+		removeUnit(svc.Units, info.Name)
+		return
+	}
+	updated := updateUnit(svc.Units, info.Name, func(u *unitStatus) {
+		sw.setUnitFields(u, info)
+	})
+	if updated || sw.onlyKnown {
+		return
+	}
+	// Subordinates are listed beneath their principal, but the
+	// delta does not tell us which unit that is; the unit will be
+	// shown at the top level until the status is next fetched.
+	var u unitStatus
+	sw.setUnitFields(&u, info)
+	if svc.Units == nil {
+		svc.Units = make(map[string]unitStatus)
+		sw.status.Services[info.Service] = svc
+	}
+	svc.Units[info.Name] = u
+}
+
+func (sw *statusWatchFormatter) setUnitFields(u *unitStatus, info *multiwatcher.UnitInfo) {
+	u.WorkloadStatusInfo = sw.statusInfo(info.WorkloadStatus)
+	u.AgentStatusInfo = sw.statusInfo(info.AgentStatus)
+	u.AgentState = params.Status(info.Status)
+	u.AgentStateInfo = info.StatusInfo
+	u.Machine = info.MachineId
+	u.PublicAddress = info.PublicAddress
+	u.OpenedPorts = nil
+	for _, portRange := range info.PortRanges {
+		u.OpenedPorts = append(u.OpenedPorts, portRange.String())
+	}
+}
+
+func (sw *statusWatchFormatter) statusInfo(info multiwatcher.StatusInfo) statusInfoContents {
+	result := statusInfoContents{
+		Err:     info.Err,
+		Current: params.Status(info.Current),
+		Message: info.Message,
+		Version: info.Version,
+	}
+	if info.Since != nil {
+		result.Since = formatStatusTime(info.Since, sw.isoTime)
+	}
+	return result
+}
+
+// updateUnit finds the named unit amongst the given units and their
+// subordinates, and updates it with the given function. It reports
+// whether the unit was found.
+func updateUnit(units map[string]unitStatus, name string, update func(*unitStatus)) bool {
+	if u, ok := units[name]; ok {
+		update(&u)
+		units[name] = u
+		return true
+	}
+	for _, u := range units {
+		if updateUnit(u.Subordinates, name, update) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeUnit removes the named unit from the given units and their
+// subordinates.
+func removeUnit(units map[string]unitStatus, name string) {
+	if _, ok := units[name]; ok {
+		delete(units, name)
+		return
+	}
+	for _, u := range units {
+		removeUnit(u.Subordinates, name)
+	}
+}
+
+// parentMachineId returns the id of the top level machine hosting
+// the container with the given id, or "" if the id is not that of
+// a container.
+func parentMachineId(id string) string {
+	if i := strings.Index(id, "/"); i >= 0 {
+		return id[:i]
+	}
+	return ""
+}
+
+func lifeString(life multiwatcher.Life) string {
+	// Alive is the default, and is not shown.
+	if life == multiwatcher.Life("alive") {
+		return ""
+	}
+	return strings.ToLower(string(life))
+}
@@ -0,0 +1,173 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state/multiwatcher"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type StatusWatchSuite struct {
+	coretesting.FakeJujuHomeSuite
+	client *fakeStatusWatchClient
+}
+
+var _ = gc.Suite(&StatusWatchSuite{})
+
+func (s *StatusWatchSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.client = &fakeStatusWatchClient{
+		fakeApiClient: newFakeApiClient(&api.Status{
+			EnvironmentName: "dummyenv",
+			Machines: map[string]api.MachineStatus{
+				"0": {
+					Id:         "0",
+					InstanceId: instance.Id("dummyenv-0"),
+					AgentState: "started",
+					Series:     "quantal",
+				},
+			},
+			Services: map[string]api.ServiceStatus{
+				"mysql": {
+					Charm: "cs:quantal/mysql-1",
+					Units: map[string]api.UnitStatus{
+						"mysql/0": {
+							AgentState: "started",
+							Machine:    "0",
+						},
+					},
+				},
+			},
+		}),
+	}
+	s.PatchValue(&newAPIClientForStatusWatch, func(_ *StatusCommand) (statusWatchAPI, error) {
+		return s.client, nil
+	})
+}
+
+func (s *StatusWatchSuite) runStatus(c *gc.C, args ...string) (*cmd.Context, error) {
+	return coretesting.RunCommand(c, envcmd.Wrap(&StatusCommand{}), args...)
+}
+
+func (s *StatusWatchSuite) TestJSONStreamImpliesWatch(c *gc.C) {
+	com, err := initStatusCommand("--format", "json-stream")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(com.watch, jc.IsTrue)
+}
+
+func (s *StatusWatchSuite) TestJSONStream(c *gc.C) {
+	s.client.watcher.deltas = [][]multiwatcher.Delta{{{
+		Entity: &multiwatcher.MachineInfo{Id: "0", Status: "started"},
+	}}, {{
+		Removed: true,
+		Entity:  &multiwatcher.ServiceInfo{Name: "mysql"},
+	}}}
+	ctx, err := s.runStatus(c, "--format", "json-stream")
+	c.Assert(err, gc.ErrorMatches, "watcher stopped")
+	lines := coretesting.Stdout(ctx)
+	c.Assert(lines, jc.HasPrefix, `["machine","change",{"Id":"0",`)
+	c.Assert(lines, jc.Contains, "\n"+`["service","remove",{"Name":"mysql",`)
+	c.Assert(s.client.watcher.stopped, jc.IsTrue)
+	c.Assert(s.client.closeCalled, jc.IsTrue)
+}
+
+func (s *StatusWatchSuite) TestWatchRedrawsChangedRows(c *gc.C) {
+	s.client.watcher.deltas = [][]multiwatcher.Delta{{{
+		Entity: &multiwatcher.UnitInfo{
+			Name:      "mysql/0",
+			Service:   "mysql",
+			MachineId: "0",
+			Status:    "error",
+			WorkloadStatus: multiwatcher.StatusInfo{
+				Current: "error",
+				Message: "hook failed",
+			},
+		},
+	}}}
+	ctx, err := s.runStatus(c, "--watch")
+	c.Assert(err, gc.ErrorMatches, "watcher stopped")
+	out := coretesting.Stdout(ctx)
+	c.Assert(out, jc.HasPrefix, "[Services]")
+	c.Assert(out, jc.Contains, "[Changes at ")
+	c.Assert(out, gc.Matches, `(?s).*\[Changes at [^\]]*\]\n\[Units\]\nmysql/0 +error +0 +hook failed *\n$`)
+	c.Assert(out, gc.Not(jc.Contains), "[Machines]\n0")
+}
+
+func (s *StatusWatchSuite) TestUpdateUnchanged(c *gc.C) {
+	sw := newStatusWatchFormatter(newStatusFormatter(s.client.statusReturn, false).format(), false)
+	out := sw.update([]multiwatcher.Delta{{
+		Entity: &multiwatcher.AnnotationInfo{Tag: "machine-0"},
+	}})
+	c.Assert(out, gc.IsNil)
+}
+
+func (s *StatusWatchSuite) TestUpdateRemoved(c *gc.C) {
+	sw := newStatusWatchFormatter(newStatusFormatter(s.client.statusReturn, true).format(), true)
+	sw.now = func() time.Time {
+		return time.Date(2015, 4, 1, 1, 23, 0, 0, time.UTC)
+	}
+	out := sw.update([]multiwatcher.Delta{{
+		Removed: true,
+		Entity:  &multiwatcher.UnitInfo{Name: "mysql/0", Service: "mysql"},
+	}, {
+		Removed: true,
+		Entity:  &multiwatcher.MachineInfo{Id: "0"},
+	}})
+	c.Assert(string(out), gc.Equals, ""+
+		"\n[Changes at 2015-04-01T01:23:00Z]\n"+
+		"[Units]\n"+
+		"mysql/0 (removed) \n"+
+		"[Machines]\n"+
+		"0 (removed) \n",
+	)
+}
+
+func (s *StatusWatchSuite) TestUpdateOnlyKnown(c *gc.C) {
+	sw := newStatusWatchFormatter(newStatusFormatter(s.client.statusReturn, false).format(), false)
+	sw.onlyKnown = true
+	out := sw.update([]multiwatcher.Delta{{
+		Entity: &multiwatcher.ServiceInfo{Name: "wordpress"},
+	}, {
+		Entity: &multiwatcher.MachineInfo{Id: "1"},
+	}})
+	c.Assert(out, gc.IsNil)
+}
+
+type fakeStatusWatchClient struct {
+	fakeApiClient
+	watcher fakeAllWatcher
+}
+
+func (c *fakeStatusWatchClient) WatchAll() (allWatcher, error) {
+	return &c.watcher, nil
+}
+
+type fakeAllWatcher struct {
+	deltas  [][]multiwatcher.Delta
+	stopped bool
+}
+
+func (w *fakeAllWatcher) Next() ([]multiwatcher.Delta, error) {
+	if len(w.deltas) == 0 {
+		return nil, errors.New("watcher stopped")
+	}
+	deltas := w.deltas[0]
+	w.deltas = w.deltas[1:]
+	return deltas, nil
+}
+
+func (w *fakeAllWatcher) Stop() error {
+	w.stopped = true
+	return nil
+}
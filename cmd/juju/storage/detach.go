@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const DetachCommandDoc = `
+Detach one or more storage instances from the units that own them.
+
+The storage-detaching hook is run for each unit, after which the
+underlying volume or filesystem is detached from the unit's machine.
+The storage instance and its volume or filesystem are preserved,
+even if the unit is later removed, and may be attached to a new
+unit with "juju add-unit --attach-storage".
+
+If a live unit would be left with fewer instances of the storage than
+its charm requires, the storage remains attached to the unit until the
+unit is removed, and is then preserved rather than destroyed.
+
+options:
+    -e, --environment (= "")
+        juju environment to operate in
+    <storage id> ...
+        the IDs of the storage instances to detach, e.g. data/0
+`
+
+// DetachCommand detaches storage instances from their owning units.
+type DetachCommand struct {
+	StorageCommandBase
+	storageIds []string
+}
+
+// Init implements Command.Init.
+func (c *DetachCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("storage detach requires at least one storage id")
+	}
+	for _, id := range args {
+		if !names.IsValidStorage(id) {
+			return errors.NotValidf("storage id %q", id)
+		}
+	}
+	c.storageIds = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *DetachCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "detach",
+		Args:    "<storage id> ...",
+		Purpose: "detach storage instances from their units",
+		Doc:     DetachCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *DetachCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+}
+
+// Run implements Command.Run.
+func (c *DetachCommand) Run(ctx *cmd.Context) error {
+	api, err := getStorageDetachAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.Detach(c.storageIds)
+	if err != nil {
+		return err
+	}
+	var failed bool
+	for i, result := range results {
+		if result.Error != nil {
+			failed = true
+			fmt.Fprintf(ctx.Stderr, "cannot detach storage %s: %v\n", c.storageIds[i], result.Error)
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+var (
+	getStorageDetachAPI = (*DetachCommand).getStorageDetachAPI
+)
+
+// StorageDetachAPI defines the API methods that the storage detach
+// command uses.
+type StorageDetachAPI interface {
+	Close() error
+	Detach(storageIds []string) ([]params.ErrorResult, error)
+}
+
+func (c *DetachCommand) getStorageDetachAPI() (StorageDetachAPI, error) {
+	return c.NewStorageAPI()
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	_ "github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/testing"
+)
+
+type DetachSuite struct {
+	SubStorageSuite
+	mockAPI *mockDetachAPI
+}
+
+var _ = gc.Suite(&DetachSuite{})
+
+func (s *DetachSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+
+	s.mockAPI = &mockDetachAPI{}
+	s.PatchValue(storage.GetStorageDetachAPI, func(c *storage.DetachCommand) (storage.StorageDetachAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func runDetach(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&storage.DetachCommand{}), args...)
+}
+
+func (s *DetachSuite) TestDetach(c *gc.C) {
+	_, err := runDetach(c, "data/0", "data/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.storageIds, jc.DeepEquals, []string{"data/0", "data/1"})
+}
+
+func (s *DetachSuite) TestDetachResultErrors(c *gc.C) {
+	s.mockAPI.results = []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "storage is owned by service-mysql, not a unit"}},
+	}
+	ctx, err := runDetach(c, "data/0", "data/1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(ctx), gc.Equals,
+		"cannot detach storage data/1: storage is owned by service-mysql, not a unit\n",
+	)
+}
+
+func (s *DetachSuite) TestDetachError(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := runDetach(c, "data/0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *DetachSuite) TestDetachInvalidArgs(c *gc.C) {
+	_, err := runDetach(c)
+	c.Assert(err, gc.ErrorMatches, "storage detach requires at least one storage id")
+	_, err = runDetach(c, "data/0", "data")
+	c.Assert(err, gc.ErrorMatches, `storage id "data" not valid`)
+}
+
+type mockDetachAPI struct {
+	storageIds []string
+	results    []params.ErrorResult
+	err        error
+}
+
+func (s *mockDetachAPI) Detach(storageIds []string) ([]params.ErrorResult, error) {
+	s.storageIds = storageIds
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.results == nil {
+		return make([]params.ErrorResult, len(storageIds)), nil
+	}
+	return s.results, nil
+}
+
+func (s *mockDetachAPI) Close() error {
+	return nil
+}
@@ -10,5 +10,9 @@ var (
 	GetPoolCreateAPI  = &getPoolCreateAPI
 	GetVolumeListAPI  = &getVolumeListAPI
 
+	GetStorageResizeAPI = &getStorageResizeAPI
+	GetStorageDetachAPI = &getStorageDetachAPI
+	GetSnapshotAPI      = &getSnapshotAPI
+
 	ConvertToVolumeInfo = convertToVolumeInfo
 )
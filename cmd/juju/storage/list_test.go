@@ -43,12 +43,12 @@ func (s *ListSuite) TestList(c *gc.C) {
 		// Default format is tabular
 		`
 [Storage]    
-UNIT         ID          LOCATION STATUS  PERSISTENT 
-postgresql/0 db-dir/1100          pending false      
-transcode/0  db-dir/1000          pending true       
-transcode/0  db-dir/1100          pending false      
-transcode/0  shared-fs/0          pending false      
-transcode/1  shared-fs/0          pending false      
+UNIT         ID          LOCATION STATUS  PERSISTENT USED 
+postgresql/0 db-dir/1100          pending false      25%  
+transcode/0  db-dir/1000          pending true            
+transcode/0  db-dir/1100          pending false           
+transcode/0  shared-fs/0          pending false           
+transcode/1  shared-fs/0          pending false           
 
 `[1:],
 		"",
@@ -66,6 +66,11 @@ postgresql/0:
     kind: filesystem
     status: pending
     persistent: false
+    usage:
+      used-bytes: 1024
+      free-bytes: 3072
+      used-inodes: 10
+      free-inodes: 90
 transcode/0:
   db-dir/1000:
     storage: db-dir
@@ -101,14 +106,14 @@ func (s *ListSuite) TestListOwnerStorageIdSort(c *gc.C) {
 		// Default format is tabular
 		`
 [Storage]    
-UNIT         ID          LOCATION STATUS  PERSISTENT 
-postgresql/0 db-dir/1100          pending false      
-transcode/0  db-dir/1000          pending true       
-transcode/0  db-dir/1100          pending false      
-transcode/0  shared-fs/0          pending false      
-transcode/0  shared-fs/5          pending false      
-transcode/1  db-dir/1000          pending true       
-transcode/1  shared-fs/0          pending false      
+UNIT         ID          LOCATION STATUS  PERSISTENT USED 
+postgresql/0 db-dir/1100          pending false           
+transcode/0  db-dir/1000          pending true            
+transcode/0  db-dir/1100          pending false           
+transcode/0  shared-fs/0          pending false           
+transcode/0  shared-fs/5          pending false           
+transcode/1  db-dir/1000          pending true            
+transcode/1  shared-fs/0          pending false           
 
 `[1:],
 		`
@@ -224,6 +229,12 @@ func getTestInstances(chaos bool) []params.StorageInfo {
 				UnitTag:    "unit-postgresql-0",
 				Kind:       params.StorageKindFilesystem,
 				Status:     "pending",
+				Usage: &params.StorageUsage{
+					UsedBytes:  1024,
+					FreeBytes:  3072,
+					UsedInodes: 10,
+					FreeInodes: 90,
+				},
 			}, nil},
 		{
 			params.StorageDetails{
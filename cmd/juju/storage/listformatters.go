@@ -30,7 +30,7 @@ func formatListTabular(value interface{}) ([]byte, error) {
 		fmt.Fprintln(tw)
 	}
 	p("[Storage]")
-	p("UNIT\tID\tLOCATION\tSTATUS\tPERSISTENT")
+	p("UNIT\tID\tLOCATION\tSTATUS\tPERSISTENT\tUSED")
 
 	// First sort by units
 	units := make([]string, 0, len(storageInfo))
@@ -50,7 +50,14 @@ func formatListTabular(value interface{}) ([]byte, error) {
 
 		for _, storageId := range storageIds {
 			info := all[storageId]
-			p(unit, storageId, info.Location, info.Status, info.Persistent)
+			var used string
+			if info.Usage != nil {
+				used = fmt.Sprintf("%d%%", info.Usage.PercentUsed())
+				if info.Usage.ThresholdExceeded {
+					used += " (over threshold)"
+				}
+			}
+			p(unit, storageId, info.Location, info.Status, info.Persistent, used)
 		}
 	}
 	tw.Flush()
@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+	"launchpad.net/gnuflag"
+)
+
+const ResizeCommandDoc = `
+Grow a block storage instance to the specified size.
+
+The size is specified as a number with an optional multiplier
+suffix (M, G, T, P, E, Z, Y); the default is megabytes. The new
+size must be larger than the current size of the storage.
+
+Once the underlying volume has been grown, the storage-attached
+hook is run again for the unit that owns the storage, so that
+the charm may grow any filesystem on the volume.
+
+options:
+    -e, --environment (= "")
+        juju environment to operate in
+    <storage id>
+        the ID of the storage instance to resize, e.g. data/0
+    <size>
+        the new size of the storage instance, e.g. 100G
+`
+
+// ResizeCommand requests that a storage instance be grown.
+type ResizeCommand struct {
+	StorageCommandBase
+	storageId string
+	size      uint64
+}
+
+// Init implements Command.Init.
+func (c *ResizeCommand) Init(args []string) (err error) {
+	if len(args) != 2 {
+		return errors.New("storage resize requires a storage id and size")
+	}
+	if !names.IsValidStorage(args[0]) {
+		return errors.NotValidf("storage id %q", args[0])
+	}
+	c.storageId = args[0]
+	c.size, err = utils.ParseSize(args[1])
+	if err != nil {
+		return errors.Annotate(err, "cannot parse size")
+	}
+	if c.size == 0 {
+		return errors.New("size must be greater than zero")
+	}
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *ResizeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "resize",
+		Args:    "<storage id> <size>",
+		Purpose: "grow a storage instance",
+		Doc:     ResizeCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ResizeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+}
+
+// Run implements Command.Run.
+func (c *ResizeCommand) Run(ctx *cmd.Context) (err error) {
+	api, err := getStorageResizeAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	return api.Resize(c.storageId, c.size)
+}
+
+var (
+	getStorageResizeAPI = (*ResizeCommand).getStorageResizeAPI
+)
+
+// StorageResizeAPI defines the API methods that the storage resize
+// command uses.
+type StorageResizeAPI interface {
+	Close() error
+	Resize(storageId string, size uint64) error
+}
+
+func (c *ResizeCommand) getStorageResizeAPI() (StorageResizeAPI, error) {
+	return c.NewStorageAPI()
+}
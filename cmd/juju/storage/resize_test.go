@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	_ "github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/testing"
+)
+
+type ResizeSuite struct {
+	SubStorageSuite
+	mockAPI *mockResizeAPI
+}
+
+var _ = gc.Suite(&ResizeSuite{})
+
+func (s *ResizeSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+
+	s.mockAPI = &mockResizeAPI{}
+	s.PatchValue(storage.GetStorageResizeAPI, func(c *storage.ResizeCommand) (storage.StorageResizeAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func runResize(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&storage.ResizeCommand{}), args...)
+}
+
+func (s *ResizeSuite) TestResize(c *gc.C) {
+	_, err := runResize(c, "data/0", "10G")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.storageId, gc.Equals, "data/0")
+	c.Assert(s.mockAPI.size, gc.Equals, uint64(10*1024))
+}
+
+func (s *ResizeSuite) TestResizeDefaultUnit(c *gc.C) {
+	_, err := runResize(c, "data/0", "2048")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.size, gc.Equals, uint64(2048))
+}
+
+func (s *ResizeSuite) TestResizeError(c *gc.C) {
+	s.mockAPI.err = errors.New("requested size is not larger than current size")
+	_, err := runResize(c, "data/0", "1G")
+	c.Assert(err, gc.ErrorMatches, "requested size is not larger than current size")
+}
+
+func (s *ResizeSuite) TestResizeInvalidArgs(c *gc.C) {
+	_, err := runResize(c, "data/0")
+	c.Assert(err, gc.ErrorMatches, "storage resize requires a storage id and size")
+	_, err = runResize(c, "data", "1G")
+	c.Assert(err, gc.ErrorMatches, `storage id "data" not valid`)
+	_, err = runResize(c, "data/0", "big")
+	c.Assert(err, gc.ErrorMatches, "cannot parse size: .*")
+	_, err = runResize(c, "data/0", "0")
+	c.Assert(err, gc.ErrorMatches, "size must be greater than zero")
+}
+
+type mockResizeAPI struct {
+	storageId string
+	size      uint64
+	err       error
+}
+
+func (s *mockResizeAPI) Resize(storageId string, size uint64) error {
+	s.storageId = storageId
+	s.size = size
+	return s.err
+}
+
+func (s *mockResizeAPI) Close() error {
+	return nil
+}
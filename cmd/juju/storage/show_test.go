@@ -69,6 +69,11 @@ transcode/0:
     status: attached
     persistent: false
     location: a location
+    usage:
+      used-bytes: 1024
+      free-bytes: 3072
+      used-inodes: 10
+      free-inodes: 90
 `[1:],
 	)
 }
@@ -82,7 +87,7 @@ func (s *ShowSuite) TestShowJSON(c *gc.C) {
 	s.assertValidShow(
 		c,
 		[]string{"shared-fs/0", "--format", "json"},
-		`{"postgresql/0":{"shared-fs/0":{"storage":"shared-fs","kind":"block","status":"pending","persistent":false}},"transcode/0":{"shared-fs/0":{"storage":"shared-fs","kind":"filesystem","status":"attached","persistent":false,"location":"a location"}}}
+		`{"postgresql/0":{"shared-fs/0":{"storage":"shared-fs","kind":"block","status":"pending","persistent":false}},"transcode/0":{"shared-fs/0":{"storage":"shared-fs","kind":"filesystem","status":"attached","persistent":false,"location":"a location","usage":{"used-bytes":1024,"free-bytes":3072,"used-inodes":10,"free-inodes":90}}}}
 `,
 	)
 }
@@ -110,6 +115,11 @@ transcode/0:
     status: attached
     persistent: false
     location: a location
+    usage:
+      used-bytes: 1024
+      free-bytes: 3072
+      used-inodes: 10
+      free-inodes: 90
 `[1:],
 	)
 }
@@ -155,6 +165,12 @@ func (s mockShowAPI) Show(tags []names.StorageTag) ([]params.StorageDetails, err
 				Kind:       params.StorageKindFilesystem,
 				Location:   "a location",
 				Status:     "attached",
+				Usage: &params.StorageUsage{
+					UsedBytes:  1024,
+					FreeBytes:  3072,
+					UsedInodes: 10,
+					FreeInodes: 90,
+				},
 			})
 		}
 	}
@@ -0,0 +1,277 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const snapshotCmdDoc = `
+"juju storage snapshot" is used to manage snapshots of storage
+ volumes in the Juju environment.
+
+Snapshots may be used to create new storage instances by
+specifying "snap:<snapshot id>" in the storage constraints
+passed to "juju deploy --storage".
+`
+
+const snapshotCmdPurpose = "manage storage volume snapshots"
+
+// NewSnapshotSuperCommand creates the storage snapshot super subcommand
+// and registers the subcommands that it supports.
+func NewSnapshotSuperCommand() cmd.Command {
+	snapshotcmd := Command{
+		SuperCommand: *jujucmd.NewSubSuperCommand(cmd.SuperCommandParams{
+			Name:        "snapshot",
+			Doc:         snapshotCmdDoc,
+			UsagePrefix: "juju storage",
+			Purpose:     snapshotCmdPurpose,
+		})}
+	snapshotcmd.Register(envcmd.Wrap(&SnapshotCreateCommand{}))
+	snapshotcmd.Register(envcmd.Wrap(&SnapshotListCommand{}))
+	snapshotcmd.Register(envcmd.Wrap(&SnapshotDestroyCommand{}))
+	return &snapshotcmd
+}
+
+const SnapshotCreateCommandDoc = `
+Take a snapshot of the volume backing a block storage instance.
+
+The ID of the new snapshot is printed. The snapshot is taken
+asynchronously; use "juju storage snapshot list" to determine
+when the snapshot has been taken.
+
+options:
+    -e, --environment (= "")
+        juju environment to operate in
+    <storage id>
+        the ID of the storage instance to snapshot, e.g. data/0
+`
+
+// SnapshotCreateCommand requests that a storage instance's volume be
+// snapshotted.
+type SnapshotCreateCommand struct {
+	StorageCommandBase
+	storageId string
+}
+
+// Init implements Command.Init.
+func (c *SnapshotCreateCommand) Init(args []string) error {
+	if len(args) != 1 {
+		return errors.New("storage snapshot create requires a storage id")
+	}
+	if !names.IsValidStorage(args[0]) {
+		return errors.NotValidf("storage id %q", args[0])
+	}
+	c.storageId = args[0]
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *SnapshotCreateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create",
+		Args:    "<storage id>",
+		Purpose: "snapshot a storage instance",
+		Doc:     SnapshotCreateCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *SnapshotCreateCommand) Run(ctx *cmd.Context) error {
+	api, err := getSnapshotAPI(&c.StorageCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	snapshotId, err := api.CreateSnapshot(c.storageId)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(ctx.Stdout, snapshotId)
+	return nil
+}
+
+const SnapshotListCommandDoc = `
+List the volume snapshots in the environment.
+
+options:
+-e, --environment (= "")
+   juju environment to operate in
+-o, --output (= "")
+   specify an output file
+--format (= yaml)
+   specify output format (json|yaml)
+`
+
+// SnapshotListCommand lists volume snapshots.
+type SnapshotListCommand struct {
+	StorageCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *SnapshotListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list volume snapshots",
+		Doc:     SnapshotListCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *SnapshotListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Run implements Command.Run.
+func (c *SnapshotListCommand) Run(ctx *cmd.Context) error {
+	api, err := getSnapshotAPI(&c.StorageCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	result, err := api.ListSnapshots()
+	if err != nil {
+		return err
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	output, err := formatSnapshotInfo(result)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, output)
+}
+
+// SnapshotInfo defines the serialization behaviour of volume snapshot
+// information.
+type SnapshotInfo struct {
+	Volume     string `yaml:"volume" json:"volume"`
+	Storage    string `yaml:"storage,omitempty" json:"storage,omitempty"`
+	Pool       string `yaml:"pool" json:"pool"`
+	Size       uint64 `yaml:"size" json:"size"`
+	Life       string `yaml:"life" json:"life"`
+	SnapshotId string `yaml:"snapshot-id,omitempty" json:"snapshot-id,omitempty"`
+	Failure    string `yaml:"failure,omitempty" json:"failure,omitempty"`
+}
+
+// formatSnapshotInfo returns a map of snapshot information keyed
+// on snapshot ID.
+func formatSnapshotInfo(all []params.SnapshotDetails) (map[string]SnapshotInfo, error) {
+	output := make(map[string]SnapshotInfo, len(all))
+	for _, one := range all {
+		volume, err := idFromTag(one.VolumeTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		info := SnapshotInfo{
+			Volume:     volume,
+			Pool:       one.Pool,
+			Size:       one.Size,
+			Life:       string(one.Life),
+			SnapshotId: one.SnapshotId,
+			Failure:    one.Failure,
+		}
+		if one.StorageTag != "" {
+			if info.Storage, err = idFromTag(one.StorageTag); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		output[one.Id] = info
+	}
+	return output, nil
+}
+
+const SnapshotDestroyCommandDoc = `
+Destroy one or more volume snapshots.
+
+options:
+    -e, --environment (= "")
+        juju environment to operate in
+    <snapshot id> ...
+        the IDs of the snapshots to destroy
+`
+
+// SnapshotDestroyCommand destroys volume snapshots.
+type SnapshotDestroyCommand struct {
+	StorageCommandBase
+	snapshotIds []string
+}
+
+// Init implements Command.Init.
+func (c *SnapshotDestroyCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("storage snapshot destroy requires at least one snapshot id")
+	}
+	c.snapshotIds = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *SnapshotDestroyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "destroy",
+		Args:    "<snapshot id> ...",
+		Purpose: "destroy volume snapshots",
+		Doc:     SnapshotDestroyCommandDoc,
+	}
+}
+
+// Run implements Command.Run.
+func (c *SnapshotDestroyCommand) Run(ctx *cmd.Context) error {
+	api, err := getSnapshotAPI(&c.StorageCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.DestroySnapshots(c.snapshotIds)
+	if err != nil {
+		return err
+	}
+	var failed bool
+	for i, result := range results {
+		if result.Error != nil {
+			failed = true
+			fmt.Fprintf(ctx.Stderr, "cannot destroy snapshot %s: %v\n", c.snapshotIds[i], result.Error)
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+var (
+	getSnapshotAPI = (*StorageCommandBase).getSnapshotAPI
+)
+
+// SnapshotAPI defines the API methods that the storage snapshot
+// commands use.
+type SnapshotAPI interface {
+	Close() error
+	CreateSnapshot(storageId string) (string, error)
+	ListSnapshots() ([]params.SnapshotDetails, error)
+	DestroySnapshots(ids []string) ([]params.ErrorResult, error)
+}
+
+func (c *StorageCommandBase) getSnapshotAPI() (SnapshotAPI, error) {
+	return c.NewStorageAPI()
+}
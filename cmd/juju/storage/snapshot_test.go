@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	_ "github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/testing"
+)
+
+type SnapshotSuite struct {
+	SubStorageSuite
+	mockAPI *mockSnapshotAPI
+}
+
+var _ = gc.Suite(&SnapshotSuite{})
+
+func (s *SnapshotSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+
+	s.mockAPI = &mockSnapshotAPI{}
+	s.PatchValue(storage.GetSnapshotAPI, func(c *storage.StorageCommandBase) (storage.SnapshotAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func (s *SnapshotSuite) TestCreate(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotCreateCommand{}), "data/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.storageId, gc.Equals, "data/0")
+	c.Assert(testing.Stdout(ctx), gc.Equals, "0/3\n")
+}
+
+func (s *SnapshotSuite) TestCreateInvalidArgs(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotCreateCommand{}))
+	c.Assert(err, gc.ErrorMatches, "storage snapshot create requires a storage id")
+	_, err = testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotCreateCommand{}), "data")
+	c.Assert(err, gc.ErrorMatches, `storage id "data" not valid`)
+}
+
+func (s *SnapshotSuite) TestList(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotListCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+"0/3":
+  volume: 0/1
+  storage: data/0
+  pool: loop
+  size: 1024
+  life: alive
+  snapshot-id: snapshot-0-3
+"4":
+  volume: "2"
+  pool: ebs
+  size: 2048
+  life: dying
+`[1:])
+}
+
+func (s *SnapshotSuite) TestDestroy(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotDestroyCommand{}), "0/3", "4")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.destroyed, jc.DeepEquals, []string{"0/3", "4"})
+}
+
+func (s *SnapshotSuite) TestDestroyError(c *gc.C) {
+	s.mockAPI.err = errors.New("snapshot not found")
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&storage.SnapshotDestroyCommand{}), "99")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "cannot destroy snapshot 99: snapshot not found\n")
+}
+
+type mockSnapshotAPI struct {
+	storageId string
+	destroyed []string
+	err       error
+}
+
+func (s *mockSnapshotAPI) Close() error {
+	return nil
+}
+
+func (s *mockSnapshotAPI) CreateSnapshot(storageId string) (string, error) {
+	s.storageId = storageId
+	return "0/3", nil
+}
+
+func (s *mockSnapshotAPI) ListSnapshots() ([]params.SnapshotDetails, error) {
+	return []params.SnapshotDetails{{
+		Id:         "0/3",
+		VolumeTag:  "volume-0-1",
+		StorageTag: "storage-data-0",
+		Pool:       "loop",
+		Size:       1024,
+		Life:       params.Alive,
+		SnapshotId: "snapshot-0-3",
+	}, {
+		Id:        "4",
+		VolumeTag: "volume-2",
+		Pool:      "ebs",
+		Size:      2048,
+		Life:      params.Dying,
+	}}, nil
+}
+
+func (s *mockSnapshotAPI) DestroySnapshots(ids []string) ([]params.ErrorResult, error) {
+	results := make([]params.ErrorResult, len(ids))
+	for i, id := range ids {
+		if s.err != nil {
+			results[i].Error = &params.Error{Message: s.err.Error()}
+			continue
+		}
+		s.destroyed = append(s.destroyed, id)
+	}
+	return results, nil
+}
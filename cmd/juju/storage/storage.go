@@ -41,8 +41,11 @@ func NewSuperCommand() cmd.Command {
 			})}
 	storagecmd.Register(envcmd.Wrap(&ShowCommand{}))
 	storagecmd.Register(envcmd.Wrap(&ListCommand{}))
+	storagecmd.Register(envcmd.Wrap(&ResizeCommand{}))
+	storagecmd.Register(envcmd.Wrap(&DetachCommand{}))
 	storagecmd.Register(NewPoolSuperCommand())
 	storagecmd.Register(NewVolumeSuperCommand())
+	storagecmd.Register(NewSnapshotSuperCommand())
 	return &storagecmd
 }
 
@@ -64,11 +67,33 @@ func (c *StorageCommandBase) NewStorageAPI() (*storage.Client, error) {
 
 // StorageInfo defines the serialization behaviour of the storage information.
 type StorageInfo struct {
-	StorageName string `yaml:"storage" json:"storage"`
-	Kind        string `yaml:"kind" json:"kind"`
-	Status      string `yaml:"status,omitempty" json:"status,omitempty"`
-	Persistent  bool   `yaml:"persistent" json:"persistent"`
-	Location    string `yaml:"location,omitempty" json:"location,omitempty"`
+	StorageName string     `yaml:"storage" json:"storage"`
+	Kind        string     `yaml:"kind" json:"kind"`
+	Status      string     `yaml:"status,omitempty" json:"status,omitempty"`
+	Persistent  bool       `yaml:"persistent" json:"persistent"`
+	Location    string     `yaml:"location,omitempty" json:"location,omitempty"`
+	Usage       *UsageInfo `yaml:"usage,omitempty" json:"usage,omitempty"`
+}
+
+// UsageInfo defines the serialization behaviour of the most
+// recently reported usage of a filesystem.
+type UsageInfo struct {
+	UsedBytes  uint64 `yaml:"used-bytes" json:"used-bytes"`
+	FreeBytes  uint64 `yaml:"free-bytes" json:"free-bytes"`
+	UsedInodes uint64 `yaml:"used-inodes" json:"used-inodes"`
+	FreeInodes uint64 `yaml:"free-inodes" json:"free-inodes"`
+
+	ThresholdExceeded bool `yaml:"threshold-exceeded,omitempty" json:"threshold-exceeded,omitempty"`
+}
+
+// PercentUsed returns the percentage of the filesystem's space
+// that is used.
+func (u *UsageInfo) PercentUsed() int {
+	total := u.UsedBytes + u.FreeBytes
+	if total == 0 {
+		return 0
+	}
+	return int(u.UsedBytes * 100 / total)
 }
 
 // formatStorageDetails takes a set of StorageDetail and creates a
@@ -99,6 +124,16 @@ func formatStorageDetails(storages []params.StorageDetails) (map[string]map[stri
 			Location:    one.Location,
 			Persistent:  one.Persistent,
 		}
+		if one.Usage != nil {
+			si.Usage = &UsageInfo{
+				UsedBytes:  one.Usage.UsedBytes,
+				FreeBytes:  one.Usage.FreeBytes,
+				UsedInodes: one.Usage.UsedInodes,
+				FreeInodes: one.Usage.FreeInodes,
+
+				ThresholdExceeded: one.Usage.ThresholdExceeded,
+			}
+		}
 		unit := unitTag.Id()
 		unitColl, ok := output[unit]
 		if !ok {
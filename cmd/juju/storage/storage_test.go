@@ -10,10 +10,13 @@ import (
 )
 
 var expectedSubCommmandNames = []string{
+	"detach",
 	"help",
 	"list",
 	"pool",
+	"resize",
 	"show",
+	"snapshot",
 	"volume",
 }
 
@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/juju/service"
 )
 
@@ -26,6 +27,7 @@ type UpgradeCharmCommand struct {
 	RepoPath    string // defaults to JUJU_REPOSITORY
 	SwitchURL   string
 	Revision    int // defaults to -1 (latest)
+	DryRun      bool
 }
 
 const upgradeCharmDoc = `
@@ -64,6 +66,12 @@ would specify revision number 5 of the wordpress charm.
 Use of the --force flag is not generally recommended; units upgraded while in an
 error state will not have upgrade-charm hooks executed, and may cause unexpected
 behavior.
+
+With --dry-run, the upgrade is checked, and the units that would be upgraded
+are reported, but the service's charm is not changed. The new charm is not
+added to the environment: if it is not there already, as is always the case
+for local charms, the upgrade cannot be checked, and only the charm that would
+be added and the upgrade of the service are reported.
 `
 
 func (c *UpgradeCharmCommand) Info() *cmd.Info {
@@ -80,6 +88,7 @@ func (c *UpgradeCharmCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.RepoPath, "repository", os.Getenv("JUJU_REPOSITORY"), "local charm repository path")
 	f.StringVar(&c.SwitchURL, "switch", "", "crossgrade to a different charm")
 	f.IntVar(&c.Revision, "revision", -1, "explicit revision of current charm")
+	f.BoolVar(&c.DryRun, "dry-run", false, common.DryRunDoc)
 }
 
 func (c *UpgradeCharmCommand) Init(args []string) error {
@@ -153,10 +162,27 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
+	if c.DryRun {
+		inEnvironment, err := charmInEnvironment(client, newURL)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !inEnvironment {
+			change := func(curl *charm.URL) string {
+				return fmt.Sprintf("upgrade service %q from charm %q to %q", c.ServiceName, oldURL, curl)
+			}
+			return common.WritePlan(ctx, charmNotAddedPlan(newURL, change))
+		}
+		plan, err := client.PlanServiceSetCharm(c.ServiceName, newURL.String(), c.Force)
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		return common.WritePlan(ctx, plan)
+	}
+
 	addedURL, err := addCharmViaAPI(client, ctx, newURL, repo, csClient)
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
-
 	return block.ProcessBlockedError(client.ServiceSetCharm(c.ServiceName, addedURL.String(), c.Force), block.BlockChange)
 }
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5"
@@ -221,6 +222,25 @@ func (s *UpgradeCharmSuccessSuite) TestForcedUpgrade(c *gc.C) {
 	s.assertLocalRevision(c, 7, s.path)
 }
 
+func (s *UpgradeCharmSuccessSuite) TestDryRun(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&UpgradeCharmCommand{}), "riak", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"dry run, no changes made; the command would:\n"+
+		"  add charm \"local:trusty/riak\" to the environment\n"+
+		"  upgrade service \"riak\" from charm \"local:trusty/riak-7\" to \"local:trusty/riak\"\n"+
+		"warning: the charm is not in the environment, so the changes could not be checked\n",
+	)
+	err = s.riak.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	ch, _, err := s.riak.Charm()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ch.Revision(), gc.Equals, 7)
+	_, err = s.State.Charm(charm.MustParseURL("local:trusty/riak-8"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	s.assertLocalRevision(c, 7, s.path)
+}
+
 func (s *UpgradeCharmSuccessSuite) TestBlockForcedUpgrade(c *gc.C) {
 	// Block operation
 	s.BlockAllChanges(c, "TestBlockForcedUpgrade")
@@ -0,0 +1,119 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const addCommandDoc = `
+Register a URL to be notified when the status of machines, units or
+services in the environment changes. The new webhook's id is printed.
+
+By default every status transition is notified. The --entity option
+restricts notifications to entities whose tags match a shell pattern,
+and the --status option to transitions into one of the given statuses.
+
+Examples:
+    # Page on-call when any unit's agent or workload enters error.
+    $ juju webhook add https://pager.example.com/juju --entity "unit-*" --status error
+
+    # Be told about every transition of machines.
+    $ juju webhook add http://10.0.0.1:8080/ --entity "machine-*"
+`
+
+// AddCommand registers a webhook.
+type AddCommand struct {
+	envcmd.EnvCommandBase
+	URL           string
+	EntityPattern string
+	Statuses      []string
+}
+
+// Info implements Command.Info.
+func (c *AddCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add",
+		Args:    "<url>",
+		Purpose: "register a status notification webhook",
+		Doc:     addCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *AddCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.EntityPattern, "entity", "", "only notify entities whose tags match this pattern")
+	f.Var(&statusesValue{&c.Statuses}, "status", "comma-separated statuses to notify transitions into")
+}
+
+// Init implements Command.Init.
+func (c *AddCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no webhook URL specified")
+	}
+	u, err := url.Parse(args[0])
+	if err != nil || u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return errors.Errorf("invalid webhook URL %q", args[0])
+	}
+	c.URL = args[0]
+	if _, err := path.Match(c.EntityPattern, ""); err != nil {
+		return errors.Errorf("invalid entity pattern %q", c.EntityPattern)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *AddCommand) Run(ctx *cmd.Context) error {
+	api, err := getWebhookAPI(&c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	args := params.WebhookParams{
+		URL:           c.URL,
+		EntityPattern: c.EntityPattern,
+	}
+	for _, status := range c.Statuses {
+		args.Statuses = append(args.Statuses, params.Status(status))
+	}
+	webhook, err := api.AddWebhook(args)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("added webhook %s", webhook.Id)
+	return nil
+}
+
+// statusesValue implements gnuflag.Value for a comma-separated
+// list of statuses.
+type statusesValue struct {
+	statuses *[]string
+}
+
+func (v *statusesValue) Set(s string) error {
+	*v.statuses = nil
+	for _, status := range strings.Split(s, ",") {
+		status = strings.TrimSpace(status)
+		if status == "" {
+			return errors.Errorf("invalid status list %q", s)
+		}
+		*v.statuses = append(*v.statuses, status)
+	}
+	return nil
+}
+
+func (v *statusesValue) String() string {
+	return strings.Join(*v.statuses, ",")
+}
@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const deliveriesCommandDoc = `
+Show the most recent attempts to notify a webhook of status transitions,
+newest first. Each delivery records the number of POST requests made,
+the HTTP status code of the last response and, if delivery was
+abandoned, the reason for the final failure.
+`
+
+// DeliveriesCommand shows the delivery log of a webhook.
+type DeliveriesCommand struct {
+	envcmd.EnvCommandBase
+	out  cmd.Output
+	Id   string
+	Size int
+}
+
+// Info implements Command.Info.
+func (c *DeliveriesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "deliveries",
+		Args:    "<id>",
+		Purpose: "show the delivery log of a webhook",
+		Doc:     deliveriesCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *DeliveriesCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.IntVar(&c.Size, "n", 20, "size of the log to show")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatDeliveriesTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *DeliveriesCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no webhook id specified")
+	}
+	if c.Size < 1 {
+		return errors.Errorf("invalid log size %d", c.Size)
+	}
+	c.Id = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *DeliveriesCommand) Run(ctx *cmd.Context) error {
+	api, err := getWebhookAPI(&c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	deliveries, err := api.WebhookDeliveries(c.Id, c.Size)
+	if err != nil {
+		return err
+	}
+	if len(deliveries) == 0 {
+		ctx.Infof("no deliveries to display")
+		return nil
+	}
+	output := make([]DeliveryInfo, len(deliveries))
+	for i, d := range deliveries {
+		output[i] = DeliveryInfo{
+			Time:         d.Time.Format(time.RFC3339),
+			Entity:       d.Entity,
+			Status:       string(d.Status),
+			Attempts:     d.Attempts,
+			ResponseCode: d.ResponseCode,
+			Error:        d.Error,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// DeliveryInfo defines the serialization behaviour of a webhook
+// delivery.
+type DeliveryInfo struct {
+	Time         string `yaml:"time" json:"time"`
+	Entity       string `yaml:"entity" json:"entity"`
+	Status       string `yaml:"status" json:"status"`
+	Attempts     int    `yaml:"attempts" json:"attempts"`
+	ResponseCode int    `yaml:"response-code,omitempty" json:"response-code,omitempty"`
+	Error        string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+func formatDeliveriesTabular(value interface{}) ([]byte, error) {
+	deliveries, ok := value.([]DeliveryInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", deliveries, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tENTITY\tSTATUS\tATTEMPTS\tRESULT")
+	for _, d := range deliveries {
+		result := "ok"
+		if d.Error != "" {
+			result = d.Error
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", d.Time, d.Entity, d.Status, d.Attempts, result)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
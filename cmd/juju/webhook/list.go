@@ -0,0 +1,111 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// ListCommand lists the webhooks registered in the environment.
+type ListCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list status notification webhooks",
+		Doc:     "List the webhooks registered in the environment.",
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatWebhooksTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	api, err := getWebhookAPI(&c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	webhooks, err := api.Webhooks()
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		ctx.Infof("no webhooks to display")
+		return nil
+	}
+	output := make([]WebhookInfo, len(webhooks))
+	for i, webhook := range webhooks {
+		output[i] = WebhookInfo{
+			Id:            webhook.Id,
+			URL:           webhook.URL,
+			EntityPattern: webhook.EntityPattern,
+			Created:       webhook.Created.Format(time.RFC3339),
+		}
+		for _, status := range webhook.Statuses {
+			output[i].Statuses = append(output[i].Statuses, string(status))
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// WebhookInfo defines the serialization behaviour of a webhook.
+type WebhookInfo struct {
+	Id            string   `yaml:"id" json:"id"`
+	URL           string   `yaml:"url" json:"url"`
+	EntityPattern string   `yaml:"entity,omitempty" json:"entity,omitempty"`
+	Statuses      []string `yaml:"statuses,omitempty" json:"statuses,omitempty"`
+	Created       string   `yaml:"created" json:"created"`
+}
+
+func formatWebhooksTabular(value interface{}) ([]byte, error) {
+	webhooks, ok := value.([]WebhookInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", webhooks, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "ID\tURL\tENTITY\tSTATUSES")
+	for _, webhook := range webhooks {
+		entity := webhook.EntityPattern
+		if entity == "" {
+			entity = "*"
+		}
+		statuses := strings.Join(webhook.Statuses, ",")
+		if statuses == "" {
+			statuses = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", webhook.Id, webhook.URL, entity, statuses)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
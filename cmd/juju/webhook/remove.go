@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// RemoveCommand removes a webhook.
+type RemoveCommand struct {
+	envcmd.EnvCommandBase
+	Id string
+}
+
+// Info implements Command.Info.
+func (c *RemoveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<id>",
+		Purpose: "remove a status notification webhook",
+		Doc:     "Remove a webhook, along with its delivery log.",
+	}
+}
+
+// Init implements Command.Init.
+func (c *RemoveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no webhook id specified")
+	}
+	c.Id = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *RemoveCommand) Run(_ *cmd.Context) error {
+	api, err := getWebhookAPI(&c.EnvCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return block.ProcessBlockedError(api.RemoveWebhook(c.Id), block.BlockChange)
+}
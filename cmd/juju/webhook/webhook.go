@@ -0,0 +1,66 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const webhookCommandDoc = `
+"juju webhook" manages the webhooks that are notified when the status of
+a machine, unit or service in the environment changes.
+
+Each notification is POSTed to the webhook's URL as a JSON document:
+
+    {
+      "webhook-id": "0",
+      "env-uuid": "...",
+      "entity": "unit-mysql-0",
+      "kind": "agent",
+      "status": "error",
+      "previous-status": "executing",
+      "message": "hook failed: \"install\"",
+      "time": "2015-04-01T12:00:00Z"
+    }
+
+where kind is one of "machine", "agent", "workload" or "service".
+Failed deliveries are retried with exponential backoff; the outcome of
+each delivery is recorded and may be inspected with
+"juju webhook deliveries".
+`
+
+const webhookCommandPurpose = "manage status notification webhooks"
+
+// NewSuperCommand creates the webhook supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	webhookCmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "webhook",
+		Doc:         webhookCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     webhookCommandPurpose,
+	})
+	webhookCmd.Register(envcmd.Wrap(&AddCommand{}))
+	webhookCmd.Register(envcmd.Wrap(&RemoveCommand{}))
+	webhookCmd.Register(envcmd.Wrap(&ListCommand{}))
+	webhookCmd.Register(envcmd.Wrap(&DeliveriesCommand{}))
+	return webhookCmd
+}
+
+// WebhookAPI defines the client API methods used by the webhook
+// commands.
+type WebhookAPI interface {
+	Close() error
+	AddWebhook(args params.WebhookParams) (params.Webhook, error)
+	RemoveWebhook(id string) error
+	Webhooks() ([]params.Webhook, error)
+	WebhookDeliveries(id string, size int) ([]params.WebhookDelivery, error)
+}
+
+var getWebhookAPI = func(c *envcmd.EnvCommandBase) (WebhookAPI, error) {
+	return c.NewAPIClient()
+}
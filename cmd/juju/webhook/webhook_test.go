@@ -0,0 +1,182 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/webhook"
+	"github.com/juju/juju/testing"
+)
+
+type WebhookSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeWebhookAPI
+}
+
+var _ = gc.Suite(&WebhookSuite{})
+
+func (s *WebhookSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeWebhookAPI{}
+	s.PatchValue(webhook.GetWebhookAPI, func(*envcmd.EnvCommandBase) (webhook.WebhookAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *WebhookSuite) run(c *gc.C, command cmd.Command, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *WebhookSuite) TestAddInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no webhook URL specified",
+	}, {
+		args: []string{"example.com"},
+		err:  `invalid webhook URL "example.com"`,
+	}, {
+		args: []string{"http://example.com", "--entity", "unit-["},
+		err:  `invalid entity pattern "unit-\["`,
+	}, {
+		args: []string{"http://example.com", "--status", "error,"},
+		err:  `invalid value "error," for flag --status: invalid status list "error,"`,
+	}, {
+		args: []string{"http://example.com", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"http://example.com", "--entity", "unit-*", "--status", "error, lost"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&webhook.AddCommand{}), test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *WebhookSuite) TestAdd(c *gc.C) {
+	ctx, err := s.run(c, &webhook.AddCommand{},
+		"https://pager.example.com/juju", "--entity", "unit-*", "--status", "error,lost")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "added webhook 7\n")
+	c.Assert(s.api.added, jc.DeepEquals, params.WebhookParams{
+		URL:           "https://pager.example.com/juju",
+		EntityPattern: "unit-*",
+		Statuses:      []params.Status{"error", "lost"},
+	})
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *WebhookSuite) TestRemove(c *gc.C) {
+	_, err := s.run(c, &webhook.RemoveCommand{}, "3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.removed, gc.Equals, "3")
+
+	_, err = s.run(c, &webhook.RemoveCommand{})
+	c.Assert(err, gc.ErrorMatches, "no webhook id specified")
+}
+
+func (s *WebhookSuite) TestList(c *gc.C) {
+	created := time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC)
+	s.api.webhooks = []params.Webhook{{
+		Id:            "0",
+		URL:           "https://pager.example.com/juju",
+		EntityPattern: "unit-*",
+		Statuses:      []params.Status{"error", "lost"},
+		Created:       created,
+	}, {
+		Id:      "1",
+		URL:     "http://10.0.0.1:8080/",
+		Created: created,
+	}}
+	ctx, err := s.run(c, &webhook.ListCommand{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"ID URL                            ENTITY STATUSES\n"+
+		"0  https://pager.example.com/juju unit-* error,lost\n"+
+		"1  http://10.0.0.1:8080/          *      *\n",
+	)
+}
+
+func (s *WebhookSuite) TestListNone(c *gc.C) {
+	ctx, err := s.run(c, &webhook.ListCommand{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "no webhooks to display\n")
+}
+
+func (s *WebhookSuite) TestDeliveries(c *gc.C) {
+	s.api.deliveries = []params.WebhookDelivery{{
+		Entity:   "unit-mysql-0",
+		Status:   "error",
+		Time:     time.Date(2015, 4, 1, 12, 5, 0, 0, time.UTC),
+		Attempts: 5,
+		Error:    "connection refused",
+	}, {
+		Entity:       "unit-mysql-0",
+		Status:       "lost",
+		Time:         time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC),
+		Attempts:     1,
+		ResponseCode: 200,
+	}}
+	ctx, err := s.run(c, &webhook.DeliveriesCommand{}, "0", "-n", "5")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"TIME                 ENTITY       STATUS ATTEMPTS RESULT\n"+
+		"2015-04-01T12:05:00Z unit-mysql-0 error  5        connection refused\n"+
+		"2015-04-01T12:00:00Z unit-mysql-0 lost   1        ok\n",
+	)
+	c.Assert(s.api.deliveriesId, gc.Equals, "0")
+	c.Assert(s.api.deliveriesSize, gc.Equals, 5)
+}
+
+func (s *WebhookSuite) TestDeliveriesInvalidSize(c *gc.C) {
+	_, err := s.run(c, &webhook.DeliveriesCommand{}, "0", "-n", "0")
+	c.Assert(err, gc.ErrorMatches, "invalid log size 0")
+}
+
+type fakeWebhookAPI struct {
+	added          params.WebhookParams
+	removed        string
+	webhooks       []params.Webhook
+	deliveries     []params.WebhookDelivery
+	deliveriesId   string
+	deliveriesSize int
+	closed         bool
+}
+
+func (f *fakeWebhookAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeWebhookAPI) AddWebhook(args params.WebhookParams) (params.Webhook, error) {
+	f.added = args
+	return params.Webhook{Id: "7", URL: args.URL}, nil
+}
+
+func (f *fakeWebhookAPI) RemoveWebhook(id string) error {
+	f.removed = id
+	return nil
+}
+
+func (f *fakeWebhookAPI) Webhooks() ([]params.Webhook, error) {
+	return f.webhooks, nil
+}
+
+func (f *fakeWebhookAPI) WebhookDeliveries(id string, size int) ([]params.WebhookDelivery, error) {
+	f.deliveriesId, f.deliveriesSize = id, size
+	return f.deliveries, nil
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
+	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/feature"
@@ -61,8 +62,10 @@ import (
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/addresser"
+	"github.com/juju/juju/worker/agentlost"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/autoscaler"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/charmrevisionworker"
 	"github.com/juju/juju/worker/cleaner"
@@ -83,13 +86,16 @@ import (
 	"github.com/juju/juju/worker/provisioner"
 	"github.com/juju/juju/worker/proxyupdater"
 	rebootworker "github.com/juju/juju/worker/reboot"
+	"github.com/juju/juju/worker/remoterelations"
 	"github.com/juju/juju/worker/resumer"
 	"github.com/juju/juju/worker/rsyslog"
+	"github.com/juju/juju/worker/selfhealer"
 	"github.com/juju/juju/worker/singular"
 	"github.com/juju/juju/worker/statushistorypruner"
 	"github.com/juju/juju/worker/storageprovisioner"
 	"github.com/juju/juju/worker/terminationworker"
 	"github.com/juju/juju/worker/upgrader"
+	"github.com/juju/juju/worker/webhooks"
 )
 
 const bootstrapMachineId = "0"
@@ -109,6 +115,7 @@ var (
 	newNetworker             = networker.NewNetworker
 	newFirewaller            = firewaller.NewFirewaller
 	newDiskManager           = diskmanager.NewWorker
+	newFilesystemUsageWorker = diskmanager.NewFilesystemUsageWorker
 	newStorageWorker         = storageprovisioner.NewStorageProvisioner
 	newCertificateUpdater    = certupdater.NewCertificateUpdater
 	reportOpenedState        = func(interface{}) {}
@@ -716,6 +723,13 @@ func (a *MachineAgent) postUpgradeAPIWorker(
 		}
 		return newDiskManager(diskmanager.DefaultListBlockDevices, api), nil
 	})
+	runner.StartWorker("filesystemusage", func() (worker.Worker, error) {
+		api, err := st.DiskManager()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return newFilesystemUsageWorker(diskmanager.DefaultFilesystemUsage, api), nil
+	})
 	runner.StartWorker("storageprovisioner-machine", func() (worker.Worker, error) {
 		scope := agentConfig.Tag()
 		api := st.StorageProvisioner(scope)
@@ -835,6 +849,10 @@ func (a *MachineAgent) setupContainerSupport(runner worker.Runner, st *api.State
 	if err == nil && supportsKvm {
 		supportedContainers = append(supportedContainers, instance.KVM)
 	}
+
+	if lxd.IsLXDSupported() {
+		supportedContainers = append(supportedContainers, instance.LXD)
+	}
 	return a.updateSupportedContainers(runner, st, entity.Tag(), supportedContainers, agentConfig)
 }
 
@@ -1066,6 +1084,18 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("addresserworker", func() (worker.Worker, error) {
 		return addresser.NewWorker(st)
 	})
+	singularRunner.StartWorker("webhooks", func() (worker.Worker, error) {
+		return webhooks.New(st, webhooks.NewParams()), nil
+	})
+	singularRunner.StartWorker("agentlost", func() (worker.Worker, error) {
+		return agentlost.New(st, agentlost.DefaultCheckInterval), nil
+	})
+	singularRunner.StartWorker("autoscaler", func() (worker.Worker, error) {
+		return autoscaler.New(st, autoscaler.DefaultCheckInterval), nil
+	})
+	singularRunner.StartWorker("selfhealer", func() (worker.Worker, error) {
+		return selfhealer.New(st, selfhealer.DefaultCheckInterval), nil
+	})
 
 	// Start workers that use an API connection.
 	singularRunner.StartWorker("environ-provisioner", func() (worker.Worker, error) {
@@ -1079,6 +1109,9 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("charm-revision-updater", func() (worker.Worker, error) {
 		return charmrevisionworker.NewRevisionUpdateWorker(apiSt.CharmRevisionUpdater()), nil
 	})
+	singularRunner.StartWorker("remoterelations", func() (worker.Worker, error) {
+		return remoterelations.New(apiSt.RemoteRelations(), remoterelations.DefaultSyncInterval), nil
+	})
 	runner.StartWorker("metricmanagerworker", func() (worker.Worker, error) {
 		return metricworker.NewMetricsManager(getMetricAPI(apiSt))
 	})
@@ -240,8 +240,13 @@ var perEnvSingularWorkers = []string{
 	"cleaner",
 	"minunitsworker",
 	"addresserworker",
+	"webhooks",
+	"agentlost",
+	"autoscaler",
+	"selfhealer",
 	"environ-provisioner",
 	"charm-revision-updater",
+	"remoterelations",
 	"firewaller",
 }
 
@@ -1174,6 +1179,28 @@ func (s *MachineSuite) TestMachineAgentRunsDiskManagerWorker(c *gc.C) {
 	}
 }
 
+func (s *MachineSuite) TestMachineAgentRunsFilesystemUsageWorker(c *gc.C) {
+	// Start the machine agent.
+	m, _, _ := s.primeAgent(c, version.Current, state.JobHostUnits)
+	a := s.newAgent(c, m)
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+
+	started := make(chan struct{})
+	newWorker := func(diskmanager.FilesystemUsageFunc, diskmanager.FilesystemUsageAccessor) worker.Worker {
+		close(started)
+		return worker.NewNoOpWorker()
+	}
+	s.PatchValue(&newFilesystemUsageWorker, newWorker)
+
+	// Wait for worker to be started.
+	select {
+	case <-started:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timeout while waiting for filesystemusage worker to start")
+	}
+}
+
 func (s *MachineSuite) TestDiskManagerWorkerUpdatesState(c *gc.C) {
 	expected := []storage.BlockDevice{{DeviceName: "whatever"}}
 	s.PatchValue(&diskmanager.DefaultListBlockDevices, func() ([]storage.BlockDevice, error) {
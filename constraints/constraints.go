@@ -29,6 +29,9 @@ const (
 	Tags         = "tags"
 	InstanceType = "instance-type"
 	Networks     = "networks"
+
+	InstanceLifecycle = "instance-lifecycle"
+	MaxPrice          = "max-price"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -78,6 +81,18 @@ type Value struct {
 	// negative values are accepted, and the difference is the latter
 	// have a "^" prefix to the name.
 	Networks *[]string `json:"networks,omitempty" yaml:"networks,omitempty"`
+
+	// InstanceLifecycle, if not nil or empty, indicates the pricing
+	// model under which a machine's instance should be started: either
+	// instance.LifecycleOnDemand or instance.LifecycleSpot. Spot
+	// instances are cheaper, but may be terminated by the provider at
+	// any time. Only valid for clouds which support spot instances.
+	InstanceLifecycle *string `json:"instance-lifecycle,omitempty" yaml:"instance-lifecycle,omitempty"`
+
+	// MaxPrice, if not nil or empty, holds the maximum hourly price,
+	// in US dollars, to pay for a spot instance. Only valid for clouds
+	// which allow a maximum price to be bid.
+	MaxPrice *string `json:"max-price,omitempty" yaml:"max-price,omitempty"`
 }
 
 // fieldNames records a mapping from the constraint tag to struct field name.
@@ -115,6 +130,12 @@ func (v *Value) HasInstanceType() bool {
 	return v.InstanceType != nil && *v.InstanceType != ""
 }
 
+// HasSpotLifecycle returns true if the constraints.Value specifies
+// that a spot instance is to be used.
+func (v *Value) HasSpotLifecycle() bool {
+	return v.InstanceLifecycle != nil && *v.InstanceLifecycle == instance.LifecycleSpot
+}
+
 // extractNetworks returns the list of networks to include or exclude
 // (without the "^" prefixes).
 func (v *Value) extractNetworks() (include, exclude []string) {
@@ -169,6 +190,12 @@ func (v Value) String() string {
 	if v.InstanceType != nil {
 		strs = append(strs, "instance-type="+string(*v.InstanceType))
 	}
+	if v.InstanceLifecycle != nil {
+		strs = append(strs, "instance-lifecycle="+*v.InstanceLifecycle)
+	}
+	if v.MaxPrice != nil {
+		strs = append(strs, "max-price="+*v.MaxPrice)
+	}
 	if v.Mem != nil {
 		s := uintStr(*v.Mem)
 		if s != "" {
@@ -330,6 +357,10 @@ func (v *Value) setRaw(raw string) error {
 		err = v.setInstanceType(str)
 	case Networks:
 		err = v.setNetworks(str)
+	case InstanceLifecycle:
+		err = v.setInstanceLifecycle(str)
+	case MaxPrice:
+		err = v.setMaxPrice(str)
 	default:
 		return fmt.Errorf("unknown constraint %q", name)
 	}
@@ -360,6 +391,10 @@ func (v *Value) SetYAML(tag string, value interface{}) bool {
 			v.Container = &ctype
 		case InstanceType:
 			v.InstanceType = &vstr
+		case InstanceLifecycle:
+			err = v.setInstanceLifecycle(vstr)
+		case MaxPrice:
+			err = v.setMaxPrice(vstr)
 		case CpuCores:
 			v.CpuCores, err = parseUint64(vstr)
 		case CpuPower:
@@ -443,6 +478,30 @@ func (v *Value) setInstanceType(str string) error {
 	return nil
 }
 
+func (v *Value) setInstanceLifecycle(str string) error {
+	if v.InstanceLifecycle != nil {
+		return fmt.Errorf("already set")
+	}
+	if str != "" && !instance.IsValidLifecycle(str) {
+		return fmt.Errorf("%q not recognized", str)
+	}
+	v.InstanceLifecycle = &str
+	return nil
+}
+
+func (v *Value) setMaxPrice(str string) error {
+	if v.MaxPrice != nil {
+		return fmt.Errorf("already set")
+	}
+	if str != "" {
+		if val, err := strconv.ParseFloat(str, 64); err != nil || val <= 0 {
+			return fmt.Errorf("must be a positive decimal number")
+		}
+	}
+	v.MaxPrice = &str
+	return nil
+}
+
 func (v *Value) setMem(str string) (err error) {
 	if v.Mem != nil {
 		return fmt.Errorf("already set")
@@ -289,6 +289,43 @@ var parseConstraintsTests = []struct {
 		args:    []string{"instance-type="},
 	},
 
+	// instance lifecycle
+	{
+		summary: "set instance lifecycle spot",
+		args:    []string{"instance-lifecycle=spot"},
+	}, {
+		summary: "set instance lifecycle on-demand",
+		args:    []string{"instance-lifecycle=on-demand"},
+	}, {
+		summary: "instance lifecycle empty",
+		args:    []string{"instance-lifecycle="},
+	}, {
+		summary: "instance lifecycle unknown",
+		args:    []string{"instance-lifecycle=reserved"},
+		err:     `bad "instance-lifecycle" constraint: "reserved" not recognized`,
+	}, {
+		summary: "double set instance lifecycle",
+		args:    []string{"instance-lifecycle=spot", "instance-lifecycle=on-demand"},
+		err:     `bad "instance-lifecycle" constraint: already set`,
+	},
+
+	// max price
+	{
+		summary: "set max price",
+		args:    []string{"max-price=0.05"},
+	}, {
+		summary: "max price empty",
+		args:    []string{"max-price="},
+	}, {
+		summary: "max price zero",
+		args:    []string{"max-price=0"},
+		err:     `bad "max-price" constraint: must be a positive decimal number`,
+	}, {
+		summary: "max price not a number",
+		args:    []string{"max-price=cheap"},
+		err:     `bad "max-price" constraint: must be a positive decimal number`,
+	},
+
 	// Everything at once.
 	{
 		summary: "kitchen sink together",
@@ -414,6 +451,19 @@ func (s *ConstraintsSuite) TestIsEmpty(c *gc.C) {
 	c.Check(&con, gc.Not(jc.Satisfies), constraints.IsEmpty)
 	con = constraints.MustParse("instance-type=")
 	c.Check(&con, gc.Not(jc.Satisfies), constraints.IsEmpty)
+	con = constraints.MustParse("instance-lifecycle=")
+	c.Check(&con, gc.Not(jc.Satisfies), constraints.IsEmpty)
+	con = constraints.MustParse("max-price=")
+	c.Check(&con, gc.Not(jc.Satisfies), constraints.IsEmpty)
+}
+
+func (s *ConstraintsSuite) TestHasSpotLifecycle(c *gc.C) {
+	con := constraints.MustParse("instance-lifecycle=spot")
+	c.Check(con.HasSpotLifecycle(), jc.IsTrue)
+	con = constraints.MustParse("instance-lifecycle=on-demand")
+	c.Check(con.HasSpotLifecycle(), jc.IsFalse)
+	con = constraints.MustParse("mem=4G")
+	c.Check(con.HasSpotLifecycle(), jc.IsFalse)
 }
 
 func uint64p(i uint64) *uint64 {
@@ -461,16 +511,22 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Networks3", constraints.Value{Networks: &[]string{"net1", "^net2"}}},
 	{"InstanceType1", constraints.Value{InstanceType: strp("")}},
 	{"InstanceType2", constraints.Value{InstanceType: strp("foo")}},
+	{"InstanceLifecycle1", constraints.Value{InstanceLifecycle: strp("")}},
+	{"InstanceLifecycle2", constraints.Value{InstanceLifecycle: strp("spot")}},
+	{"MaxPrice1", constraints.Value{MaxPrice: strp("")}},
+	{"MaxPrice2", constraints.Value{MaxPrice: strp("0.05")}},
 	{"All", constraints.Value{
-		Arch:         strp("i386"),
-		Container:    ctypep("lxc"),
-		CpuCores:     uint64p(4096),
-		CpuPower:     uint64p(9001),
-		Mem:          uint64p(18000000000),
-		RootDisk:     uint64p(24000000000),
-		Tags:         &[]string{"foo", "bar"},
-		Networks:     &[]string{"net1", "^net2"},
-		InstanceType: strp("foo"),
+		Arch:              strp("i386"),
+		Container:         ctypep("lxc"),
+		CpuCores:          uint64p(4096),
+		CpuPower:          uint64p(9001),
+		Mem:               uint64p(18000000000),
+		RootDisk:          uint64p(24000000000),
+		Tags:              &[]string{"foo", "bar"},
+		Networks:          &[]string{"net1", "^net2"},
+		InstanceType:      strp("foo"),
+		InstanceLifecycle: strp("spot"),
+		MaxPrice:          strp("0.05"),
 	}},
 }
 
@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxc
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+)
+
+const (
+	// cpuPeriod is the CFS scheduling period, in microseconds, over
+	// which a container's CPU quota is measured.
+	cpuPeriod = 100000
+
+	// defaultCpuShares and defaultBlkioWeight are the cgroup defaults
+	// that correspond to a cpu-power of 100, i.e. one standard core.
+	defaultCpuShares   = 1024
+	defaultBlkioWeight = 500
+
+	minCpuShares   = 2
+	minBlkioWeight = 100
+	maxBlkioWeight = 1000
+)
+
+// resourceLimits holds the cgroup resource limits derived from a
+// container's constraints. A zero value means no limit is applied.
+type resourceLimits struct {
+	// MemoryMB is the maximum memory available to the container.
+	MemoryMB uint64
+
+	// CpuCores is the number of cores' worth of CPU time that the
+	// container may use in each scheduling period.
+	CpuCores uint64
+
+	// CpuPower is the container's CPU and block I/O priority relative
+	// to other containers, where 100 is the priority of one core.
+	CpuPower uint64
+}
+
+// resourceLimitsFromConstraints returns the resource limits to apply
+// to a container with the specified constraints.
+func resourceLimitsFromConstraints(cons constraints.Value) resourceLimits {
+	var limits resourceLimits
+	if cons.Mem != nil {
+		limits.MemoryMB = *cons.Mem
+	}
+	if cons.CpuCores != nil {
+		limits.CpuCores = *cons.CpuCores
+	}
+	if cons.CpuPower != nil {
+		limits.CpuPower = *cons.CpuPower
+	}
+	if cons.RootDisk != nil {
+		logger.Infof("root-disk constraint of %vM being ignored as not supported", *cons.RootDisk)
+	}
+	return limits
+}
+
+// config returns the lxc config lines that apply the limits.
+func (l resourceLimits) config() string {
+	var buf bytes.Buffer
+	if l.MemoryMB > 0 {
+		fmt.Fprintf(&buf, "lxc.cgroup.memory.limit_in_bytes = %dM\n", l.MemoryMB)
+	}
+	if l.CpuCores > 0 {
+		fmt.Fprintf(&buf, "lxc.cgroup.cpu.cfs_period_us = %d\n", cpuPeriod)
+		fmt.Fprintf(&buf, "lxc.cgroup.cpu.cfs_quota_us = %d\n", l.CpuCores*cpuPeriod)
+	}
+	if l.CpuPower > 0 {
+		shares := l.CpuPower * defaultCpuShares / 100
+		if shares < minCpuShares {
+			shares = minCpuShares
+		}
+		weight := l.CpuPower * defaultBlkioWeight / 100
+		if weight < minBlkioWeight {
+			weight = minBlkioWeight
+		} else if weight > maxBlkioWeight {
+			weight = maxBlkioWeight
+		}
+		fmt.Fprintf(&buf, "lxc.cgroup.cpu.shares = %d\n", shares)
+		fmt.Fprintf(&buf, "lxc.cgroup.blkio.weight = %d\n", weight)
+	}
+	if buf.Len() == 0 {
+		return ""
+	}
+	return "\n" + buf.String()
+}
+
+// updateHardware records the effective limits in the specified
+// hardware characteristics.
+func (l resourceLimits) updateHardware(hc *instance.HardwareCharacteristics) {
+	if l.MemoryMB > 0 {
+		mem := l.MemoryMB
+		hc.Mem = &mem
+	}
+	if l.CpuCores > 0 {
+		cores := l.CpuCores
+		hc.CpuCores = &cores
+	}
+	if l.CpuPower > 0 {
+		power := l.CpuPower
+		hc.CpuPower = &power
+	}
+}
+
+// applyResourceLimits adds the cgroup limits derived from the
+// specified constraints to the named container's config.
+func applyResourceLimits(name string, cons constraints.Value) (resourceLimits, error) {
+	limits := resourceLimitsFromConstraints(cons)
+	config := limits.config()
+	if config == "" {
+		return limits, nil
+	}
+	if err := appendToContainerConfig(name, config); err != nil {
+		return resourceLimits{}, errors.Trace(err)
+	}
+	return limits, nil
+}
@@ -328,6 +328,10 @@ func (manager *containerManager) CreateContainer(
 			return nil, nil, errors.Annotate(err, "failed to configure the container for loopback devices")
 		}
 	}
+	limits, err := applyResourceLimits(name, instanceConfig.Constraints)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to configure the container's resource limits")
+	}
 	// Update the network settings inside the run-time config of the
 	// container (e.g. /var/lib/lxc/<name>/config) before starting it.
 	netConfig := generateNetworkConfig(networkConfig)
@@ -399,6 +403,7 @@ func (manager *containerManager) CreateContainer(
 	hardware := &instance.HardwareCharacteristics{
 		Arch: &version.Current.Arch,
 	}
+	limits.updateHardware(hardware)
 
 	return &lxcInstance{lxcContainer, name}, hardware, nil
 }
@@ -27,6 +27,7 @@ import (
 	"launchpad.net/golxc"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/lxc"
 	"github.com/juju/juju/container/lxc/mock"
@@ -985,6 +986,63 @@ lxc.cgroup.devices.allow = c 10:237 rwm
 	c.Assert(autostartLink, jc.DoesNotExist)
 }
 
+func (s *LxcSuite) TestCreateContainerWithResourceLimits(c *gc.C) {
+	err := os.Remove(s.RestartDir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	manager := s.makeManager(c, "test")
+	machineConfig, err := containertesting.MockMachineConfig("1/lxc/0")
+	c.Assert(err, jc.ErrorIsNil)
+	machineConfig.Constraints = constraints.MustParse("mem=2G cpu-cores=2 cpu-power=200 root-disk=8G")
+	storageConfig := &container.StorageConfig{}
+	networkConfig := container.BridgeNetworkConfig("nic42", nil)
+	inst, hardware, err := manager.CreateContainer(machineConfig, "quantal", networkConfig, storageConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	config, err := ioutil.ReadFile(lxc.ContainerConfigFilename(string(inst.Id())))
+	c.Assert(err, jc.ErrorIsNil)
+	expected := fmt.Sprintf(`
+# network config
+# interface "eth0"
+lxc.network.type = veth
+lxc.network.link = nic42
+lxc.network.flags = up
+lxc.network.mtu = 4321
+
+lxc.start.auto = 1
+lxc.mount.entry = %s var/log/juju none defaults,bind 0 0
+
+lxc.cgroup.memory.limit_in_bytes = 2048M
+lxc.cgroup.cpu.cfs_period_us = 100000
+lxc.cgroup.cpu.cfs_quota_us = 200000
+lxc.cgroup.cpu.shares = 2048
+lxc.cgroup.blkio.weight = 1000
+`, s.logDir)
+	c.Assert(string(config), gc.Equals, expected)
+
+	// The effective limits are reported as hardware characteristics;
+	// root-disk is not limited, so it is not reported.
+	c.Assert(*hardware.Mem, gc.Equals, uint64(2048))
+	c.Assert(*hardware.CpuCores, gc.Equals, uint64(2))
+	c.Assert(*hardware.CpuPower, gc.Equals, uint64(200))
+	c.Assert(hardware.RootDisk, gc.IsNil)
+}
+
+func (s *LxcSuite) TestCreateContainerNoResourceLimits(c *gc.C) {
+	manager := s.makeManager(c, "test")
+	machineConfig, err := containertesting.MockMachineConfig("1/lxc/0")
+	c.Assert(err, jc.ErrorIsNil)
+	storageConfig := &container.StorageConfig{}
+	networkConfig := container.BridgeNetworkConfig("nic42", nil)
+	inst, hardware, err := manager.CreateContainer(machineConfig, "quantal", networkConfig, storageConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	config, err := ioutil.ReadFile(lxc.ContainerConfigFilename(string(inst.Id())))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(config), gc.Not(jc.Contains), "lxc.cgroup")
+	c.Assert(hardware.Mem, gc.IsNil)
+	c.Assert(hardware.CpuCores, gc.IsNil)
+	c.Assert(hardware.CpuPower, gc.IsNil)
+}
+
 func (s *LxcSuite) TestDestroyContainerRemovesAutostartLink(c *gc.C) {
 	manager := s.makeManager(c, "test")
 	instance := containertesting.CreateContainer(c, manager, "1/lxc/0")
@@ -0,0 +1,280 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+const (
+	apiVersion       = "1.0"
+	containersPrefix = "/" + apiVersion + "/containers/"
+)
+
+// Container states reported by the LXD daemon.
+const (
+	StatusRunning = "Running"
+	StatusStopped = "Stopped"
+)
+
+// Client is a minimal client for the LXD REST API, which is served
+// by the LXD daemon on a unix socket on the local host.
+type Client struct {
+	http *http.Client
+}
+
+// NewClient returns a new Client that talks to the LXD daemon
+// listening on the unix socket at the specified path.
+func NewClient(socketPath string) *Client {
+	transport := &http.Transport{
+		Dial: func(string, string) (net.Conn, error) {
+			return net.Dial("unix", socketPath)
+		},
+	}
+	return &Client{&http.Client{Transport: transport}}
+}
+
+// ContainerSpec describes a container to be created by the LXD daemon.
+type ContainerSpec struct {
+	Name       string
+	ImageAlias string
+	Profiles   []string
+	Config     map[string]string
+	Devices    map[string]map[string]string
+}
+
+// ContainerState describes the state of an existing container.
+type ContainerState struct {
+	Status  string                      `json:"status"`
+	Network map[string]ContainerNetwork `json:"network"`
+}
+
+// ContainerNetwork describes a network interface of a container.
+type ContainerNetwork struct {
+	Addresses []ContainerAddress `json:"addresses"`
+}
+
+// ContainerAddress describes an address of a container's
+// network interface.
+type ContainerAddress struct {
+	Family  string `json:"family"`
+	Address string `json:"address"`
+	Scope   string `json:"scope"`
+}
+
+// response is the envelope in which the LXD daemon returns the
+// result of every request.
+type response struct {
+	Type       string          `json:"type"`
+	StatusCode int             `json:"status_code"`
+	Operation  string          `json:"operation"`
+	ErrorCode  int             `json:"error_code"`
+	Error      string          `json:"error"`
+	Metadata   json.RawMessage `json:"metadata"`
+}
+
+// operation describes a background operation, as returned when
+// waiting for an asynchronous request to complete.
+type operation struct {
+	Status   string                 `json:"status"`
+	Metadata map[string]interface{} `json:"metadata"`
+	Err      string                 `json:"err"`
+}
+
+// ContainerNames returns the names of all containers known to the
+// LXD daemon.
+func (c *Client) ContainerNames() ([]string, error) {
+	var urls []string
+	if err := c.get("/containers", &urls); err != nil {
+		return nil, errors.Annotate(err, "listing containers")
+	}
+	names := make([]string, len(urls))
+	for i, url := range urls {
+		names[i] = strings.TrimPrefix(url, containersPrefix)
+	}
+	return names, nil
+}
+
+// ContainerState returns the state of the named container.
+func (c *Client) ContainerState(name string) (*ContainerState, error) {
+	var state ContainerState
+	if err := c.get("/containers/"+name+"/state", &state); err != nil {
+		return nil, errors.Annotatef(err, "getting state of container %q", name)
+	}
+	return &state, nil
+}
+
+// CreateContainer creates a container from a local image, and
+// waits for the creation to complete. The container is not started.
+func (c *Client) CreateContainer(spec ContainerSpec) error {
+	body := map[string]interface{}{
+		"name":     spec.Name,
+		"profiles": spec.Profiles,
+		"config":   spec.Config,
+		"devices":  spec.Devices,
+		"source": map[string]string{
+			"type":  "image",
+			"alias": spec.ImageAlias,
+		},
+	}
+	if _, err := c.run("POST", "/containers", body); err != nil {
+		return errors.Annotatef(err, "creating container %q", spec.Name)
+	}
+	return nil
+}
+
+// StartContainer starts the named container.
+func (c *Client) StartContainer(name string) error {
+	if err := c.changeState(name, "start", false); err != nil {
+		return errors.Annotatef(err, "starting container %q", name)
+	}
+	return nil
+}
+
+// StopContainer forcibly stops the named container.
+func (c *Client) StopContainer(name string) error {
+	if err := c.changeState(name, "stop", true); err != nil {
+		return errors.Annotatef(err, "stopping container %q", name)
+	}
+	return nil
+}
+
+func (c *Client) changeState(name, action string, force bool) error {
+	body := map[string]interface{}{
+		"action":  action,
+		"timeout": -1,
+		"force":   force,
+	}
+	_, err := c.run("PUT", "/containers/"+name+"/state", body)
+	return err
+}
+
+// DeleteContainer removes the named container, which must be stopped.
+func (c *Client) DeleteContainer(name string) error {
+	if _, err := c.run("DELETE", "/containers/"+name, nil); err != nil {
+		return errors.Annotatef(err, "deleting container %q", name)
+	}
+	return nil
+}
+
+// HasImageAlias reports whether the LXD daemon has an image
+// with the specified alias.
+func (c *Client) HasImageAlias(alias string) (bool, error) {
+	err := c.get("/images/aliases/"+alias, nil)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Annotatef(err, "getting image alias %q", alias)
+	}
+	return true, nil
+}
+
+// CopyImage copies the image with the specified alias from the
+// simplestreams server with the specified URL into the LXD daemon's
+// image store, and gives it the specified local alias.
+func (c *Client) CopyImage(server, remoteAlias, localAlias string) error {
+	body := map[string]interface{}{
+		"source": map[string]string{
+			"type":     "image",
+			"mode":     "pull",
+			"server":   server,
+			"protocol": "simplestreams",
+			"alias":    remoteAlias,
+		},
+	}
+	op, err := c.run("POST", "/images", body)
+	if err != nil {
+		return errors.Annotatef(err, "copying image %q from %s", remoteAlias, server)
+	}
+	fingerprint, _ := op.Metadata["fingerprint"].(string)
+	if fingerprint == "" {
+		return errors.Errorf("copying image %q from %s: no fingerprint returned", remoteAlias, server)
+	}
+	alias := map[string]string{
+		"name":   localAlias,
+		"target": fingerprint,
+	}
+	if _, err := c.run("POST", "/images/aliases", alias); err != nil {
+		return errors.Annotatef(err, "creating image alias %q", localAlias)
+	}
+	return nil
+}
+
+// get issues a GET request for the specified path, and decodes the
+// response metadata into the value pointed to by out, if non-nil.
+func (c *Client) get(path string, out interface{}) error {
+	resp, err := c.do("GET", path, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if out == nil {
+		return nil
+	}
+	return errors.Trace(json.Unmarshal(resp.Metadata, out))
+}
+
+// run issues a request which may be processed by the LXD daemon in
+// the background, and waits for the resulting operation to complete.
+func (c *Client) run(method, path string, body interface{}) (*operation, error) {
+	resp, err := c.do(method, path, body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if resp.Type != "async" {
+		return &operation{}, nil
+	}
+	resp, err = c.do("GET", strings.TrimPrefix(resp.Operation, "/"+apiVersion)+"/wait", nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "waiting for operation")
+	}
+	var op operation
+	if err := json.Unmarshal(resp.Metadata, &op); err != nil {
+		return nil, errors.Annotate(err, "decoding operation")
+	}
+	if op.Status != "Success" {
+		return nil, errors.Errorf("operation %s: %s", strings.ToLower(op.Status), op.Err)
+	}
+	return &op, nil
+}
+
+// do issues a request to the LXD daemon, and decodes the response.
+// Error responses are returned as errors; a 404 response is returned
+// as an error satisfying errors.IsNotFound.
+func (c *Client) do(method, path string, body interface{}) (*response, error) {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	// The host is ignored, as all requests are made on the unix socket.
+	req, err := http.NewRequest(method, "http://lxd/"+apiVersion+path, &buf)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	httpResp, err := c.http.Do(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer httpResp.Body.Close()
+
+	var resp response
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, errors.Annotate(err, "decoding response")
+	}
+	if resp.Type == "error" {
+		if resp.ErrorCode == http.StatusNotFound {
+			return nil, errors.NewNotFound(nil, resp.Error)
+		}
+		return nil, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
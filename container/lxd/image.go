@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/imagemetadata"
+)
+
+// imageServerURL is the simplestreams server from which
+// LXD images are copied, for the released image stream.
+var imageServerURL = "https://cloud-images.ubuntu.com/releases"
+
+// imageAlias returns the local alias of the LXD image used to
+// create containers of the specified series and architecture.
+func imageAlias(series, arch string) string {
+	return fmt.Sprintf("juju-%s-%s", series, arch)
+}
+
+// imageServer returns the URL of the simplestreams server from which
+// images for the specified image stream are copied.
+func imageServer(stream string) string {
+	if stream == "" || stream == imagemetadata.ReleasedStream {
+		return imageServerURL
+	}
+	return imagemetadata.UbuntuCloudImagesURL + "/" + stream
+}
+
+// ensureImage ensures that the LXD daemon has an image for the
+// specified series and architecture, copying it from the cloud
+// images server if necessary, and returns the image's local alias.
+func ensureImage(client *Client, series, arch, stream string) (string, error) {
+	alias := imageAlias(series, arch)
+	found, err := client.HasImageAlias(alias)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if found {
+		return alias, nil
+	}
+	server := imageServer(stream)
+	logger.Infof("copying image for %s/%s from %s", series, arch, server)
+	remoteAlias := fmt.Sprintf("%s/%s", series, arch)
+	if err := client.CopyImage(server, remoteAlias, alias); err != nil {
+		return "", errors.Trace(err)
+	}
+	return alias, nil
+}
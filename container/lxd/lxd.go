@@ -0,0 +1,197 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/cloudconfig/containerinit"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/version"
+)
+
+var (
+	logger = loggo.GetLogger("juju.container.lxd")
+
+	// SocketPath is the path of the unix socket on which the
+	// LXD daemon serves its REST API.
+	SocketPath = "/var/lib/lxd/unix.socket"
+
+	// DefaultLxdBridge is the bridge device that LXD containers
+	// are attached to, if no other is specified.
+	DefaultLxdBridge = "lxcbr0"
+
+	// DefaultProfile is the LXD profile applied to all containers.
+	DefaultProfile = "default"
+)
+
+// IsLXDSupported reports whether the LXD daemon is available
+// on the local host.
+func IsLXDSupported() bool {
+	_, err := os.Stat(SocketPath)
+	return err == nil
+}
+
+// NewContainerManager returns a manager object that can start and stop lxd
+// containers. The containers that are created are namespaced by the name
+// parameter.
+func NewContainerManager(conf container.ManagerConfig) (container.Manager, error) {
+	name := conf.PopValue(container.ConfigName)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	// The log dir is not used, as container logs are kept by LXD.
+	conf.PopValue(container.ConfigLogDir)
+	conf.WarnAboutUnused()
+	return &containerManager{
+		name:   name,
+		client: NewClient(SocketPath),
+	}, nil
+}
+
+// containerManager handles all of the business logic at the juju specific
+// level. It makes sure that the necessary images are available, and that
+// the user-data is passed to the containers it creates.
+type containerManager struct {
+	name   string
+	client *Client
+}
+
+var _ container.Manager = (*containerManager)(nil)
+
+// CreateContainer is specified on the container.Manager interface.
+func (manager *containerManager) CreateContainer(
+	instanceConfig *instancecfg.InstanceConfig,
+	series string,
+	networkConfig *container.NetworkConfig,
+	storageConfig *container.StorageConfig,
+) (instance.Instance, *instance.HardwareCharacteristics, error) {
+
+	name := names.NewMachineTag(instanceConfig.MachineId).String()
+	if manager.name != "" {
+		name = fmt.Sprintf("%s-%s", manager.name, name)
+	}
+	instanceConfig.MachineContainerHostname = name
+
+	arch := version.Current.Arch
+	imageAlias, err := ensureImage(manager.client, series, arch, instanceConfig.ImageStream)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to ensure LXD image")
+	}
+
+	logger.Tracef("generate cloud-init user data")
+	userData, err := containerinit.CloudInitUserData(instanceConfig, networkConfig)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to generate user data")
+	}
+
+	spec := ContainerSpec{
+		Name:       name,
+		ImageAlias: imageAlias,
+		Profiles:   []string{DefaultProfile},
+		Config: map[string]string{
+			"user.user-data": string(userData),
+			"boot.autostart": "true",
+		},
+		Devices: networkDevices(networkConfig),
+	}
+	logger.Tracef("create the container, constraints: %v", instanceConfig.Constraints)
+	if err := manager.client.CreateContainer(spec); err != nil {
+		return nil, nil, errors.Annotate(err, "lxd container creation failed")
+	}
+	if err := manager.client.StartContainer(name); err != nil {
+		if err := manager.client.DeleteContainer(name); err != nil {
+			logger.Errorf("cannot remove container %q after failed start: %v", name, err)
+		}
+		return nil, nil, errors.Annotate(err, "lxd container start failed")
+	}
+	logger.Tracef("lxd container created")
+	hardware := &instance.HardwareCharacteristics{Arch: &arch}
+	return &lxdInstance{manager.client, name}, hardware, nil
+}
+
+// networkDevices returns the LXD devices for the container's
+// network interfaces. If no bridge is specified, the devices
+// of the default profile are used.
+func networkDevices(networkConfig *container.NetworkConfig) map[string]map[string]string {
+	if networkConfig == nil || networkConfig.Device == "" {
+		return nil
+	}
+	devices := make(map[string]map[string]string)
+	if len(networkConfig.Interfaces) == 0 {
+		devices["eth0"] = map[string]string{
+			"type":    "nic",
+			"nictype": "bridged",
+			"parent":  networkConfig.Device,
+			"name":    "eth0",
+		}
+		return devices
+	}
+	for _, iface := range networkConfig.Interfaces {
+		device := map[string]string{
+			"type":    "nic",
+			"nictype": "bridged",
+			"parent":  networkConfig.Device,
+			"name":    iface.InterfaceName,
+		}
+		if iface.MACAddress != "" {
+			device["hwaddr"] = iface.MACAddress
+		}
+		devices[iface.InterfaceName] = device
+	}
+	return devices
+}
+
+// IsInitialized is specified on the container.Manager interface.
+func (manager *containerManager) IsInitialized() bool {
+	return IsLXDSupported()
+}
+
+// DestroyContainer is specified on the container.Manager interface.
+func (manager *containerManager) DestroyContainer(id instance.Id) error {
+	name := string(id)
+	state, err := manager.client.ContainerState(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if state.Status != StatusStopped {
+		if err := manager.client.StopContainer(name); err != nil {
+			logger.Errorf("failed to stop lxd container: %v", err)
+			return err
+		}
+	}
+	return manager.client.DeleteContainer(name)
+}
+
+// ListContainers is specified on the container.Manager interface.
+func (manager *containerManager) ListContainers() (result []instance.Instance, err error) {
+	containers, err := manager.client.ContainerNames()
+	if err != nil {
+		logger.Errorf("failed getting all instances: %v", err)
+		return
+	}
+	managerPrefix := fmt.Sprintf("%s-", manager.name)
+	for _, name := range containers {
+		// Filter out those not starting with our name.
+		if !strings.HasPrefix(name, managerPrefix) {
+			continue
+		}
+		state, err := manager.client.ContainerState(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if state.Status == StatusRunning {
+			result = append(result, &lxdInstance{manager.client, name})
+		}
+	}
+	return
+}
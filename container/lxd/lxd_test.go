@@ -0,0 +1,142 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"fmt"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	containertesting "github.com/juju/juju/container/testing"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/version"
+)
+
+type LxdSuite struct {
+	lxdtesting.TestSuite
+	manager container.Manager
+}
+
+var _ = gc.Suite(&LxdSuite{})
+
+func (s *LxdSuite) SetUpTest(c *gc.C) {
+	s.TestSuite.SetUpTest(c)
+	var err error
+	s.manager, err = lxd.NewContainerManager(container.ManagerConfig{container.ConfigName: "test"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (*LxdSuite) TestManagerNameNeeded(c *gc.C) {
+	manager, err := lxd.NewContainerManager(container.ManagerConfig{container.ConfigName: ""})
+	c.Assert(err, gc.ErrorMatches, "name is required")
+	c.Assert(manager, gc.IsNil)
+}
+
+func (*LxdSuite) TestManagerWarnsAboutUnknownOption(c *gc.C) {
+	_, err := lxd.NewContainerManager(container.ManagerConfig{
+		container.ConfigName: "BillyBatson",
+		"shazam":             "Captain Marvel",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(c.GetTestLog(), jc.Contains, `WARNING juju.container unused config option: "shazam" -> "Captain Marvel"`)
+}
+
+func (s *LxdSuite) TestIsInitialized(c *gc.C) {
+	c.Assert(s.manager.IsInitialized(), jc.IsTrue)
+	s.PatchValue(&lxd.SocketPath, "/does/not/exist")
+	c.Assert(s.manager.IsInitialized(), jc.IsFalse)
+}
+
+func (s *LxdSuite) TestListInitiallyEmpty(c *gc.C) {
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *LxdSuite) TestListMatchesManagerName(c *gc.C) {
+	s.Server.AddContainer("test-match1", lxd.StatusRunning)
+	s.Server.AddContainer("test-match2", lxd.StatusRunning)
+	s.Server.AddContainer("testNoMatch", lxd.StatusRunning)
+	s.Server.AddContainer("other", lxd.StatusRunning)
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 2)
+	expectedIds := []instance.Id{"test-match1", "test-match2"}
+	ids := []instance.Id{containers[0].Id(), containers[1].Id()}
+	c.Assert(ids, jc.SameContents, expectedIds)
+}
+
+func (s *LxdSuite) TestListMatchesRunningContainers(c *gc.C) {
+	s.Server.AddContainer("test-running", lxd.StatusRunning)
+	s.Server.AddContainer("test-stopped", lxd.StatusStopped)
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 1)
+	c.Assert(string(containers[0].Id()), gc.Equals, "test-running")
+}
+
+func (s *LxdSuite) TestCreateContainer(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+	name := string(inst.Id())
+	c.Assert(name, gc.Equals, "test-machine-1-lxd-0")
+	c.Assert(inst.Status(), gc.Equals, "running")
+
+	alias := fmt.Sprintf("juju-quantal-%s", version.Current.Arch)
+	ctr := s.Server.Container(name)
+	c.Assert(ctr, gc.NotNil)
+	c.Assert(ctr.ImageAlias, gc.Equals, alias)
+	c.Assert(ctr.Profiles, jc.DeepEquals, []string{"default"})
+	c.Assert(ctr.Config["user.user-data"], jc.HasPrefix, "#cloud-config\n")
+	c.Assert(ctr.Devices, jc.DeepEquals, map[string]map[string]string{
+		"eth0": {
+			"type":    "nic",
+			"nictype": "bridged",
+			"parent":  "nic42",
+			"name":    "eth0",
+		},
+	})
+
+	// The image was copied from the cloud images server,
+	// and is reused for subsequent containers.
+	c.Assert(s.Server.CopiedImages(), jc.DeepEquals, []string{"quantal/" + version.Current.Arch})
+	containertesting.CreateContainer(c, s.manager, "1/lxd/1")
+	c.Assert(s.Server.CopiedImages(), gc.HasLen, 1)
+}
+
+func (s *LxdSuite) TestCreateContainerExistingImage(c *gc.C) {
+	s.Server.AddImage(fmt.Sprintf("juju-quantal-%s", version.Current.Arch))
+	containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+	c.Assert(s.Server.CopiedImages(), gc.HasLen, 0)
+}
+
+func (s *LxdSuite) TestDestroyContainer(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+
+	err := s.manager.DestroyContainer(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.Container(string(inst.Id())), gc.IsNil)
+
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *LxdSuite) TestDestroyContainerNotFound(c *gc.C) {
+	err := s.manager.DestroyContainer("test-machine-1-lxd-0")
+	c.Assert(err, gc.ErrorMatches, `getting state of container "test-machine-1-lxd-0": not found`)
+}
+
+func (s *LxdSuite) TestInstanceAddresses(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+	s.Server.SetAddresses(string(inst.Id()), "10.0.3.42")
+
+	addresses, err := inst.Addresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, []network.Address{network.NewAddress("10.0.3.42")})
+}
@@ -0,0 +1,300 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Functions defined in this file should *ONLY* be used for testing.  These
+// functions are exported for testing purposes only, and shouldn't be called
+// from code that isn't in a test file.
+
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/testing"
+)
+
+// TestSuite starts a fake LXD server for each test, and points
+// the lxd package at its socket.
+type TestSuite struct {
+	testing.BaseSuite
+	Server *FakeServer
+}
+
+func (s *TestSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.Server = NewFakeServer(c)
+	s.AddCleanup(func(*gc.C) { s.Server.Close() })
+	s.PatchValue(&lxd.SocketPath, s.Server.SocketPath)
+}
+
+// FakeContainer records the state of a container created
+// in a FakeServer.
+type FakeContainer struct {
+	Status     string
+	ImageAlias string
+	Profiles   []string
+	Config     map[string]string
+	Devices    map[string]map[string]string
+	Addresses  []string
+}
+
+// FakeServer is an in-memory implementation of the subset of the
+// LXD REST API used by the lxd package, served on a unix socket.
+type FakeServer struct {
+	// SocketPath is the path of the unix socket on
+	// which the server is listening.
+	SocketPath string
+
+	mu         sync.Mutex
+	server     *httptest.Server
+	containers map[string]*FakeContainer
+	images     map[string]string
+	copied     []string
+	operations map[string]map[string]interface{}
+}
+
+// NewFakeServer starts a new FakeServer listening on a unix
+// socket in a temporary directory.
+func NewFakeServer(c *gc.C) *FakeServer {
+	s := &FakeServer{
+		SocketPath: filepath.Join(c.MkDir(), "unix.socket"),
+		containers: make(map[string]*FakeContainer),
+		images:     make(map[string]string),
+		operations: make(map[string]map[string]interface{}),
+	}
+	listener, err := net.Listen("unix", s.SocketPath)
+	c.Assert(err, jc.ErrorIsNil)
+	s.server = httptest.NewUnstartedServer(http.HandlerFunc(s.serveHTTP))
+	s.server.Listener = listener
+	s.server.Start()
+	return s
+}
+
+// Close stops the server.
+func (s *FakeServer) Close() {
+	s.server.Close()
+}
+
+// AddImage adds an image with the specified alias to the server.
+func (s *FakeServer) AddImage(alias string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.images[alias] = "fingerprint-" + alias
+}
+
+// AddContainer adds a container with the specified name
+// and status to the server.
+func (s *FakeServer) AddContainer(name, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.containers[name] = &FakeContainer{Status: status}
+}
+
+// SetAddresses sets the addresses of the named container.
+func (s *FakeServer) SetAddresses(name string, addresses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.containers[name].Addresses = addresses
+}
+
+// Container returns the named container, or nil if there
+// is no such container.
+func (s *FakeServer) Container(name string) *FakeContainer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.containers[name]
+}
+
+// CopiedImages returns the remote aliases of the images that
+// have been copied into the server, in the order they were copied.
+func (s *FakeServer) CopiedImages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.copied...)
+}
+
+func (s *FakeServer) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, "/1.0")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	var body map[string]interface{}
+	if req.Method == "POST" || req.Method == "PUT" {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	switch {
+	case len(parts) == 1 && parts[0] == "containers" && req.Method == "GET":
+		var urls []string
+		for name := range s.containers {
+			urls = append(urls, "/1.0/containers/"+name)
+		}
+		writeSync(w, urls)
+	case len(parts) == 1 && parts[0] == "containers" && req.Method == "POST":
+		s.createContainer(w, body)
+	case len(parts) >= 2 && parts[0] == "containers":
+		ctr, ok := s.containers[parts[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		switch {
+		case len(parts) == 3 && parts[2] == "state" && req.Method == "GET":
+			writeSync(w, containerState(ctr))
+		case len(parts) == 3 && parts[2] == "state" && req.Method == "PUT":
+			switch body["action"] {
+			case "start":
+				ctr.Status = lxd.StatusRunning
+			case "stop":
+				ctr.Status = lxd.StatusStopped
+			}
+			s.writeAsync(w, nil)
+		case len(parts) == 2 && req.Method == "DELETE":
+			if ctr.Status != lxd.StatusStopped {
+				writeError(w, http.StatusBadRequest, "container is running")
+				return
+			}
+			delete(s.containers, parts[1])
+			s.writeAsync(w, nil)
+		default:
+			writeError(w, http.StatusNotFound, "not found")
+		}
+	case len(parts) > 2 && parts[0] == "images" && parts[1] == "aliases" && req.Method == "GET":
+		alias := strings.Join(parts[2:], "/")
+		fingerprint, ok := s.images[alias]
+		if !ok {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		writeSync(w, map[string]string{"name": alias, "target": fingerprint})
+	case len(parts) == 2 && parts[0] == "images" && parts[1] == "aliases" && req.Method == "POST":
+		name, _ := body["name"].(string)
+		target, _ := body["target"].(string)
+		s.images[name] = target
+		writeSync(w, map[string]string{})
+	case len(parts) == 1 && parts[0] == "images" && req.Method == "POST":
+		source, _ := body["source"].(map[string]interface{})
+		alias, _ := source["alias"].(string)
+		s.copied = append(s.copied, alias)
+		s.writeAsync(w, map[string]interface{}{"fingerprint": "fingerprint-" + alias})
+	case len(parts) == 3 && parts[0] == "operations" && parts[2] == "wait":
+		op, ok := s.operations[parts[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		writeSync(w, op)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *FakeServer) createContainer(w http.ResponseWriter, body map[string]interface{}) {
+	name, _ := body["name"].(string)
+	if _, ok := s.containers[name]; ok {
+		writeError(w, http.StatusBadRequest, "container exists")
+		return
+	}
+	source, _ := body["source"].(map[string]interface{})
+	alias, _ := source["alias"].(string)
+	if _, ok := s.images[alias]; !ok {
+		writeError(w, http.StatusNotFound, "image not found")
+		return
+	}
+	ctr := &FakeContainer{
+		Status:     lxd.StatusStopped,
+		ImageAlias: alias,
+		Config:     make(map[string]string),
+		Devices:    make(map[string]map[string]string),
+	}
+	if profiles, ok := body["profiles"].([]interface{}); ok {
+		for _, p := range profiles {
+			ctr.Profiles = append(ctr.Profiles, fmt.Sprint(p))
+		}
+	}
+	if config, ok := body["config"].(map[string]interface{}); ok {
+		for k, v := range config {
+			ctr.Config[k] = fmt.Sprint(v)
+		}
+	}
+	if devices, ok := body["devices"].(map[string]interface{}); ok {
+		for name, device := range devices {
+			attrs := make(map[string]string)
+			for k, v := range device.(map[string]interface{}) {
+				attrs[k] = fmt.Sprint(v)
+			}
+			ctr.Devices[name] = attrs
+		}
+	}
+	s.containers[name] = ctr
+	s.writeAsync(w, nil)
+}
+
+func containerState(ctr *FakeContainer) map[string]interface{} {
+	var addresses []map[string]string
+	for _, addr := range ctr.Addresses {
+		addresses = append(addresses, map[string]string{
+			"family":  "inet",
+			"address": addr,
+			"scope":   "global",
+		})
+	}
+	return map[string]interface{}{
+		"status": ctr.Status,
+		"network": map[string]interface{}{
+			"eth0": map[string]interface{}{"addresses": addresses},
+		},
+	}
+}
+
+// writeAsync records a successful operation, and writes a response
+// referring to it.
+func (s *FakeServer) writeAsync(w http.ResponseWriter, metadata map[string]interface{}) {
+	id := fmt.Sprint(len(s.operations))
+	s.operations[id] = map[string]interface{}{
+		"status":   "Success",
+		"metadata": metadata,
+	}
+	writeResponse(w, http.StatusAccepted, map[string]interface{}{
+		"type":        "async",
+		"status_code": 100,
+		"operation":   "/1.0/operations/" + id,
+	})
+}
+
+func writeSync(w http.ResponseWriter, metadata interface{}) {
+	writeResponse(w, http.StatusOK, map[string]interface{}{
+		"type":        "sync",
+		"status_code": 200,
+		"metadata":    metadata,
+	})
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeResponse(w, code, map[string]interface{}{
+		"type":       "error",
+		"error_code": code,
+		"error":      message,
+	})
+}
+
+func writeResponse(w http.ResponseWriter, code int, resp map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(resp)
+}
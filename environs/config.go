@@ -59,6 +59,7 @@ func validateEnvironmentKind(rawEnviron map[string]interface{}) error {
 // validation can be performed).
 var disallowedWithBootstrap = []string{
 	config.StorageDefaultBlockSourceKey,
+	config.StorageDefaultFilesystemSourceKey,
 }
 
 // Config returns the environment configuration for the environment
@@ -65,6 +65,21 @@ const (
 	// refresh addresses from the provider each time.
 	DefaultBootstrapSSHAddressesDelay int = 10
 
+	// DefaultAgentLostGracePeriod is the amount of time an agent may
+	// fail to signal its presence before it is considered lost, in
+	// seconds.
+	DefaultAgentLostGracePeriod int = 300
+
+	// DefaultFirewallReconcileInterval is the amount of time between
+	// comparisons of the ports Juju expects to be open with those the
+	// provider reports as open, in seconds.
+	DefaultFirewallReconcileInterval int = 300
+
+	// DefaultSelfHealGracePeriod is the amount of time a machine's
+	// instance may be missing, or its agent lost, before the machine
+	// is replaced when self-healing is enabled, in seconds.
+	DefaultSelfHealGracePeriod int = 600
+
 	// fallbackLtsSeries is the latest LTS series we'll use, if we fail to
 	// obtain this information from the system.
 	fallbackLtsSeries string = "trusty"
@@ -150,11 +165,42 @@ const (
 	// The default block storage source.
 	StorageDefaultBlockSourceKey = "storage-default-block-source"
 
+	// The default filesystem storage source.
+	StorageDefaultFilesystemSourceKey = "storage-default-filesystem-source"
+
 	// For LXC containers, is the container allowed to mount block
 	// devices. A theoretical security issue, so must be explicitly
 	// allowed by the user.
 	AllowLXCLoopMounts = "allow-lxc-loop-mounts"
 
+	// AgentLostGracePeriodKey stores the number of seconds an agent
+	// may fail to signal its presence before it is marked lost.
+	AgentLostGracePeriodKey = "agent-lost-grace-period"
+
+	// FirewallReconcileIntervalKey stores the number of seconds between
+	// comparisons, by the firewaller, of the ports Juju expects to be
+	// open with those the provider reports as open.
+	FirewallReconcileIntervalKey = "firewall-reconcile-interval"
+
+	// AgentLostDegradesServiceKey stores whether a service is marked
+	// as degraded while any of its units' agents are lost.
+	AgentLostDegradesServiceKey = "agent-lost-degrades-service"
+
+	// SelfHealKey stores whether machines hosting only units of
+	// stateless services are replaced when their instances disappear
+	// or their agents are lost.
+	SelfHealKey = "self-heal"
+
+	// SelfHealGracePeriodKey stores the number of seconds a machine's
+	// instance may be missing, or its agent lost, before the machine
+	// is replaced.
+	SelfHealGracePeriodKey = "self-heal-grace-period"
+
+	// StorageUsageThresholdKey stores the percentage of an attached
+	// filesystem's space that may be used before the workload status
+	// of the units its storage is attached to is set to blocked.
+	StorageUsageThresholdKey = "storage-usage-threshold"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -582,6 +628,22 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[AgentLostGracePeriodKey].(int); ok && v <= 0 {
+		return fmt.Errorf("%s must be positive, got %d", AgentLostGracePeriodKey, v)
+	}
+
+	if v, ok := cfg.defined[FirewallReconcileIntervalKey].(int); ok && v <= 0 {
+		return fmt.Errorf("%s must be positive, got %d", FirewallReconcileIntervalKey, v)
+	}
+
+	if v, ok := cfg.defined[SelfHealGracePeriodKey].(int); ok && v <= 0 {
+		return fmt.Errorf("%s must be positive, got %d", SelfHealGracePeriodKey, v)
+	}
+
+	if v, ok := cfg.defined[StorageUsageThresholdKey].(int); ok && (v <= 0 || v > 100) {
+		return fmt.Errorf("%s must be between 1 and 100, got %d", StorageUsageThresholdKey, v)
+	}
+
 	// Ensure that the given harvesting method is valid.
 	if hvstMeth, ok := cfg.defined[ProvisionerHarvestModeKey].(string); ok {
 		if _, err := ParseHarvestMode(hvstMeth); err != nil {
@@ -1114,6 +1176,13 @@ func (c *Config) StorageDefaultBlockSource() (string, bool) {
 	return bs, bs != ""
 }
 
+// StorageDefaultFilesystemSource returns the default filesystem
+// storage source for the environment.
+func (c *Config) StorageDefaultFilesystemSource() (string, bool) {
+	fs := c.asString(StorageDefaultFilesystemSourceKey)
+	return fs, fs != ""
+}
+
 // AllowLXCLoopMounts returns whether loop devices are allowed
 // to be mounted inside lxc containers.
 func (c *Config) AllowLXCLoopMounts() (bool, bool) {
@@ -1121,6 +1190,57 @@ func (c *Config) AllowLXCLoopMounts() (bool, bool) {
 	return v, ok
 }
 
+// AgentLostGracePeriod returns how long an agent may fail to signal
+// its presence before it is considered lost.
+func (c *Config) AgentLostGracePeriod() time.Duration {
+	if v, ok := c.defined[AgentLostGracePeriodKey].(int); ok && v > 0 {
+		return time.Duration(v) * time.Second
+	}
+	return time.Duration(DefaultAgentLostGracePeriod) * time.Second
+}
+
+// FirewallReconcileInterval returns how often the firewaller compares
+// the ports Juju expects to be open with those the provider reports
+// as open, correcting and recording any drift.
+func (c *Config) FirewallReconcileInterval() time.Duration {
+	if v, ok := c.defined[FirewallReconcileIntervalKey].(int); ok && v > 0 {
+		return time.Duration(v) * time.Second
+	}
+	return time.Duration(DefaultFirewallReconcileInterval) * time.Second
+}
+
+// StorageUsageThreshold returns the percentage of an attached filesystem's
+// space that may be used before the units its storage is attached to are
+// blocked, and whether a threshold has been set.
+func (c *Config) StorageUsageThreshold() (int, bool) {
+	v, ok := c.defined[StorageUsageThresholdKey].(int)
+	return v, ok && v > 0
+}
+
+// AgentLostDegradesService reports whether a service should be marked
+// as degraded while the agents of any of its units are lost.
+func (c *Config) AgentLostDegradesService() bool {
+	v, _ := c.defined[AgentLostDegradesServiceKey].(bool)
+	return v
+}
+
+// SelfHeal reports whether machines hosting only units of stateless
+// services should be replaced when their instances disappear or their
+// agents are lost.
+func (c *Config) SelfHeal() bool {
+	v, _ := c.defined[SelfHealKey].(bool)
+	return v
+}
+
+// SelfHealGracePeriod returns how long a machine's instance may be
+// missing, or its agent lost, before the machine is replaced.
+func (c *Config) SelfHealGracePeriod() time.Duration {
+	if v, ok := c.defined[SelfHealGracePeriodKey].(int); ok && v > 0 {
+		return time.Duration(v) * time.Second
+	}
+	return time.Duration(DefaultSelfHealGracePeriod) * time.Second
+}
+
 // UnknownAttrs returns a copy of the raw configuration attributes
 // that are supposedly specific to the environment type. They could
 // also be wrong attributes, though. Only the specific environment
@@ -1161,57 +1281,64 @@ func (c *Config) Apply(attrs map[string]interface{}) (*Config, error) {
 }
 
 var fields = schema.Fields{
-	"type":                       schema.String(),
-	"name":                       schema.String(),
-	"uuid":                       schema.UUID(),
-	"default-series":             schema.String(),
-	AgentMetadataURLKey:          schema.String(),
-	"image-metadata-url":         schema.String(),
-	"image-stream":               schema.String(),
-	AgentStreamKey:               schema.String(),
-	"authorized-keys":            schema.String(),
-	"authorized-keys-path":       schema.String(),
-	"firewall-mode":              schema.String(),
-	"agent-version":              schema.String(),
-	"development":                schema.Bool(),
-	"admin-secret":               schema.String(),
-	"ca-cert":                    schema.String(),
-	"ca-cert-path":               schema.String(),
-	"ca-private-key":             schema.String(),
-	"ca-private-key-path":        schema.String(),
-	"ssl-hostname-verification":  schema.Bool(),
-	"state-port":                 schema.ForceInt(),
-	"api-port":                   schema.ForceInt(),
-	"syslog-port":                schema.ForceInt(),
-	"rsyslog-ca-cert":            schema.String(),
-	"rsyslog-ca-key":             schema.String(),
-	"logging-config":             schema.String(),
-	ProvisionerHarvestModeKey:    schema.String(),
-	HttpProxyKey:                 schema.String(),
-	HttpsProxyKey:                schema.String(),
-	FtpProxyKey:                  schema.String(),
-	NoProxyKey:                   schema.String(),
-	AptHttpProxyKey:              schema.String(),
-	AptHttpsProxyKey:             schema.String(),
-	AptFtpProxyKey:               schema.String(),
-	"apt-mirror":                 schema.String(),
-	"bootstrap-timeout":          schema.ForceInt(),
-	"bootstrap-retry-delay":      schema.ForceInt(),
-	"bootstrap-addresses-delay":  schema.ForceInt(),
-	"test-mode":                  schema.Bool(),
-	"proxy-ssh":                  schema.Bool(),
-	LxcClone:                     schema.Bool(),
-	"lxc-clone-aufs":             schema.Bool(),
-	"prefer-ipv6":                schema.Bool(),
-	"enable-os-refresh-update":   schema.Bool(),
-	"enable-os-upgrade":          schema.Bool(),
-	"disable-network-management": schema.Bool(),
-	SetNumaControlPolicyKey:      schema.Bool(),
-	PreventDestroyEnvironmentKey: schema.Bool(),
-	PreventRemoveObjectKey:       schema.Bool(),
-	PreventAllChangesKey:         schema.Bool(),
-	StorageDefaultBlockSourceKey: schema.String(),
-	AllowLXCLoopMounts:           schema.Bool(),
+	"type":                            schema.String(),
+	"name":                            schema.String(),
+	"uuid":                            schema.UUID(),
+	"default-series":                  schema.String(),
+	AgentMetadataURLKey:               schema.String(),
+	"image-metadata-url":              schema.String(),
+	"image-stream":                    schema.String(),
+	AgentStreamKey:                    schema.String(),
+	"authorized-keys":                 schema.String(),
+	"authorized-keys-path":            schema.String(),
+	"firewall-mode":                   schema.String(),
+	"agent-version":                   schema.String(),
+	"development":                     schema.Bool(),
+	"admin-secret":                    schema.String(),
+	"ca-cert":                         schema.String(),
+	"ca-cert-path":                    schema.String(),
+	"ca-private-key":                  schema.String(),
+	"ca-private-key-path":             schema.String(),
+	"ssl-hostname-verification":       schema.Bool(),
+	"state-port":                      schema.ForceInt(),
+	"api-port":                        schema.ForceInt(),
+	"syslog-port":                     schema.ForceInt(),
+	"rsyslog-ca-cert":                 schema.String(),
+	"rsyslog-ca-key":                  schema.String(),
+	"logging-config":                  schema.String(),
+	ProvisionerHarvestModeKey:         schema.String(),
+	HttpProxyKey:                      schema.String(),
+	HttpsProxyKey:                     schema.String(),
+	FtpProxyKey:                       schema.String(),
+	NoProxyKey:                        schema.String(),
+	AptHttpProxyKey:                   schema.String(),
+	AptHttpsProxyKey:                  schema.String(),
+	AptFtpProxyKey:                    schema.String(),
+	"apt-mirror":                      schema.String(),
+	"bootstrap-timeout":               schema.ForceInt(),
+	"bootstrap-retry-delay":           schema.ForceInt(),
+	"bootstrap-addresses-delay":       schema.ForceInt(),
+	"test-mode":                       schema.Bool(),
+	"proxy-ssh":                       schema.Bool(),
+	LxcClone:                          schema.Bool(),
+	"lxc-clone-aufs":                  schema.Bool(),
+	"prefer-ipv6":                     schema.Bool(),
+	"enable-os-refresh-update":        schema.Bool(),
+	"enable-os-upgrade":               schema.Bool(),
+	"disable-network-management":      schema.Bool(),
+	SetNumaControlPolicyKey:           schema.Bool(),
+	PreventDestroyEnvironmentKey:      schema.Bool(),
+	PreventRemoveObjectKey:            schema.Bool(),
+	PreventAllChangesKey:              schema.Bool(),
+	StorageDefaultBlockSourceKey:      schema.String(),
+	StorageDefaultFilesystemSourceKey: schema.String(),
+	AllowLXCLoopMounts:                schema.Bool(),
+	AgentLostGracePeriodKey:           schema.ForceInt(),
+	AgentLostDegradesServiceKey:       schema.Bool(),
+	FirewallReconcileIntervalKey:      schema.ForceInt(),
+	SelfHealKey:                       schema.Bool(),
+	SelfHealGracePeriodKey:            schema.ForceInt(),
+	StorageUsageThresholdKey:          schema.ForceInt(),
 
 	// Deprecated fields, retain for backwards compatibility.
 	ToolsMetadataURLKey:    schema.String(),
@@ -1255,10 +1382,17 @@ var alwaysOptional = schema.Defaults{
 	AgentStreamKey:               schema.Omit,
 	SetNumaControlPolicyKey:      DefaultNumaControlPolicy,
 	AllowLXCLoopMounts:           false,
+	AgentLostGracePeriodKey:      schema.Omit,
+	AgentLostDegradesServiceKey:  schema.Omit,
+	FirewallReconcileIntervalKey: schema.Omit,
+	SelfHealKey:                  schema.Omit,
+	SelfHealGracePeriodKey:       schema.Omit,
+	StorageUsageThresholdKey:     schema.Omit,
 
 	// Storage related config.
 	// Environ providers will specify their own defaults.
-	StorageDefaultBlockSourceKey: schema.Omit,
+	StorageDefaultBlockSourceKey:      schema.Omit,
+	StorageDefaultFilesystemSourceKey: schema.Omit,
 
 	// Deprecated fields, retain for backwards compatibility.
 	ToolsMetadataURLKey:          "",
@@ -729,6 +729,76 @@ var configTests = []configTest{
 			"bootstrap-timeout": "illegal",
 		},
 		err: `bootstrap-timeout: expected number, got string\("illegal"\)`,
+	}, {
+		about:       "Explicit agent lost grace period",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                        "my-type",
+			"name":                        "my-name",
+			"agent-lost-grace-period":     60,
+			"agent-lost-degrades-service": true,
+		},
+	}, {
+		about:       "Invalid agent lost grace period",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                    "my-type",
+			"name":                    "my-name",
+			"agent-lost-grace-period": 0,
+		},
+		err: `agent-lost-grace-period must be positive, got 0`,
+	}, {
+		about:       "Explicit firewall reconcile interval",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                        "my-type",
+			"name":                        "my-name",
+			"firewall-reconcile-interval": 60,
+		},
+	}, {
+		about:       "Invalid firewall reconcile interval",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                        "my-type",
+			"name":                        "my-name",
+			"firewall-reconcile-interval": -1,
+		},
+		err: `firewall-reconcile-interval must be positive, got -1`,
+	}, {
+		about:       "Explicit self-heal settings",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"self-heal":              true,
+			"self-heal-grace-period": 120,
+		},
+	}, {
+		about:       "Invalid self-heal grace period",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"self-heal-grace-period": 0,
+		},
+		err: `self-heal-grace-period must be positive, got 0`,
+	}, {
+		about:       "Explicit storage usage threshold",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                    "my-type",
+			"name":                    "my-name",
+			"storage-usage-threshold": 90,
+		},
+	}, {
+		about:       "Invalid storage usage threshold",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                    "my-type",
+			"name":                    "my-name",
+			"storage-usage-threshold": 101,
+		},
+		err: `storage-usage-threshold must be between 1 and 100, got 101`,
 	}, {
 		about:       "Explicit bootstrap retry delay",
 		useDefaults: config.UseDefaults,
@@ -1214,6 +1284,42 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 		sshOpts.AddressesDelay,
 		config.DefaultBootstrapSSHAddressesDelay,
 	)
+	test.assertDuration(
+		c,
+		"agent-lost-grace-period",
+		cfg.AgentLostGracePeriod(),
+		config.DefaultAgentLostGracePeriod,
+	)
+	test.assertDuration(
+		c,
+		"firewall-reconcile-interval",
+		cfg.FirewallReconcileInterval(),
+		config.DefaultFirewallReconcileInterval,
+	)
+	test.assertDuration(
+		c,
+		"self-heal-grace-period",
+		cfg.SelfHealGracePeriod(),
+		config.DefaultSelfHealGracePeriod,
+	)
+	if v, ok := test.attrs["storage-usage-threshold"]; ok {
+		threshold, ok := cfg.StorageUsageThreshold()
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(threshold, gc.Equals, v)
+	} else {
+		_, ok := cfg.StorageUsageThreshold()
+		c.Assert(ok, jc.IsFalse)
+	}
+	if v, ok := test.attrs["agent-lost-degrades-service"]; ok {
+		c.Assert(cfg.AgentLostDegradesService(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.AgentLostDegradesService(), jc.IsFalse)
+	}
+	if v, ok := test.attrs["self-heal"]; ok {
+		c.Assert(cfg.SelfHeal(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.SelfHeal(), jc.IsFalse)
+	}
 
 	if v, ok := test.attrs["image-stream"]; ok {
 		c.Assert(cfg.ImageStream(), gc.Equals, v)
@@ -325,7 +325,8 @@ environments:
         state-server: false
 `
 	for key, value := range map[string]interface{}{
-		"storage-default-block-source": "loop",
+		"storage-default-block-source":      "loop",
+		"storage-default-filesystem-source": "rootfs",
 	} {
 		envContent := fmt.Sprintf("%s\n        %s: %s", content, key, value)
 		envs, err := environs.ReadEnvironsBytes([]byte(envContent))
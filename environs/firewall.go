@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/juju/network"
+)
+
+// IngressRules interface defines methods that environments capable
+// of restricting the source addresses from which globally opened
+// ports may be accessed must implement.
+type IngressRules interface {
+	// OpenIngressRules opens the given ingress rules for the whole
+	// environment. Must only be used if the environment was setup
+	// with the FwGlobal firewall mode.
+	OpenIngressRules(rules []network.IngressRule) error
+
+	// CloseIngressRules closes the given ingress rules for the whole
+	// environment. Must only be used if the environment was setup
+	// with the FwGlobal firewall mode.
+	CloseIngressRules(rules []network.IngressRule) error
+
+	// IngressRules returns the ingress rules opened for the whole
+	// environment. Ports opened with OpenPorts are reported as rules
+	// with network.AnySourceCIDR as their source. Must only be used
+	// if the environment was setup with the FwGlobal firewall mode.
+	IngressRules() ([]network.IngressRule, error)
+}
+
+// IngressRulesEnviron combines the standard Environ interface with the
+// functionality for opening ingress rules.
+type IngressRulesEnviron interface {
+	// Environ represents a juju environment.
+	Environ
+
+	// IngressRules defines the methods of environments that
+	// support ingress rules.
+	IngressRules
+}
+
+// SupportsIngressRules is a convenience helper to check if an
+// environment supports ingress rules. It returns an interface
+// containing Environ and IngressRules in this case.
+func SupportsIngressRules(environ Environ) (IngressRulesEnviron, bool) {
+	ie, ok := environ.(IngressRulesEnviron)
+	return ie, ok
+}
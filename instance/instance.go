@@ -49,6 +49,27 @@ type Instance interface {
 	Ports(machineId string) ([]network.PortRange, error)
 }
 
+// IngressRulesInstance is implemented by instances that can restrict
+// the source addresses from which their opened ports may be accessed.
+type IngressRulesInstance interface {
+	Instance
+
+	// OpenIngressRules opens the given ingress rules on the instance,
+	// which should have been started with the given machine id.
+	OpenIngressRules(machineId string, rules []network.IngressRule) error
+
+	// CloseIngressRules closes the given ingress rules on the instance,
+	// which should have been started with the given machine id.
+	CloseIngressRules(machineId string, rules []network.IngressRule) error
+
+	// IngressRules returns the ingress rules opened on the instance,
+	// which should have been started with the given machine id. Ports
+	// opened with OpenPorts are reported as rules with
+	// network.AnySourceCIDR as their source. The rules are returned
+	// as sorted by network.SortIngressRules().
+	IngressRules(machineId string) ([]network.IngressRule, error)
+}
+
 // HardwareCharacteristics represents the characteristics of the instance (if known).
 // Attributes that are nil are unknown or not supported.
 type HardwareCharacteristics struct {
@@ -60,6 +81,33 @@ type HardwareCharacteristics struct {
 	Tags     *[]string `json:",omitempty" yaml:"tags,omitempty"`
 
 	AvailabilityZone *string `json:",omitempty" yaml:"availabilityzone,omitempty"`
+
+	// InstanceLifecycle holds the pricing model under which the
+	// instance was started; see LifecycleOnDemand and LifecycleSpot.
+	InstanceLifecycle *string `json:",omitempty" yaml:"instancelifecycle,omitempty"`
+}
+
+const (
+	// LifecycleOnDemand identifies instances paid for at the
+	// provider's regular price, which run until they are stopped.
+	LifecycleOnDemand = "on-demand"
+
+	// LifecycleSpot identifies instances paid for at a reduced
+	// price, which the provider may terminate at any time. These
+	// are known as preemptible instances on some clouds.
+	LifecycleSpot = "spot"
+)
+
+// IsValidLifecycle reports whether the given string names a known
+// instance lifecycle.
+func IsValidLifecycle(lifecycle string) bool {
+	return lifecycle == LifecycleOnDemand || lifecycle == LifecycleSpot
+}
+
+// IsSpot reports whether the characteristics describe a spot
+// instance, which may be terminated by the provider at any time.
+func (hc HardwareCharacteristics) IsSpot() bool {
+	return hc.InstanceLifecycle != nil && *hc.InstanceLifecycle == LifecycleSpot
 }
 
 func uintStr(i uint64) string {
@@ -113,6 +161,9 @@ func (hc HardwareCharacteristics) String() string {
 	if hc.AvailabilityZone != nil && *hc.AvailabilityZone != "" {
 		strs = append(strs, fmt.Sprintf("availability-zone=%s", *hc.AvailabilityZone))
 	}
+	if hc.InstanceLifecycle != nil && *hc.InstanceLifecycle != "" {
+		strs = append(strs, fmt.Sprintf("instance-lifecycle=%s", *hc.InstanceLifecycle))
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -178,6 +229,8 @@ func (hc *HardwareCharacteristics) setRaw(raw string) error {
 		err = hc.setTags(str)
 	case "availability-zone":
 		err = hc.setAvailabilityZone(str)
+	case "instance-lifecycle":
+		err = hc.setInstanceLifecycle(str)
 	default:
 		return fmt.Errorf("unknown characteristic %q", name)
 	}
@@ -248,6 +301,19 @@ func (hc *HardwareCharacteristics) setAvailabilityZone(str string) error {
 	return nil
 }
 
+func (hc *HardwareCharacteristics) setInstanceLifecycle(str string) error {
+	if hc.InstanceLifecycle != nil {
+		return fmt.Errorf("already set")
+	}
+	if str != "" {
+		if !IsValidLifecycle(str) {
+			return fmt.Errorf("%q not recognized", str)
+		}
+		hc.InstanceLifecycle = &str
+	}
+	return nil
+}
+
 // parseTags returns the tags in the value s
 func parseTags(s string) *[]string {
 	if s == "" {
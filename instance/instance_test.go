@@ -261,6 +261,26 @@ var parseHardwareTests = []parseHardwareTestSpec{
 		err:     `bad "availability-zone" characteristic: already set`,
 	},
 
+	// "instance-lifecycle" in detail.
+	{
+		summary: "set instance-lifecycle empty",
+		args:    []string{"instance-lifecycle="},
+	}, {
+		summary: "set instance-lifecycle spot",
+		args:    []string{"instance-lifecycle=spot"},
+	}, {
+		summary: "set instance-lifecycle on-demand",
+		args:    []string{"instance-lifecycle=on-demand"},
+	}, {
+		summary: "set instance-lifecycle unknown",
+		args:    []string{"instance-lifecycle=reserved"},
+		err:     `bad "instance-lifecycle" characteristic: "reserved" not recognized`,
+	}, {
+		summary: "double set instance-lifecycle together",
+		args:    []string{"instance-lifecycle=spot instance-lifecycle=spot"},
+		err:     `bad "instance-lifecycle" characteristic: already set`,
+	},
+
 	// Everything at once.
 	{
 		summary: "kitchen sink together",
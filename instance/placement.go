@@ -83,3 +83,33 @@ func MustParsePlacement(directive string) *Placement {
 	}
 	return placement
 }
+
+// zonePlacementPrefix prefixes a placement directive that requests a
+// new machine in a named availability zone.
+const zonePlacementPrefix = "zone="
+
+// IsZonePlacements reports whether spec is a list of availability zone
+// placement directives, as accepted by ParseZonePlacements.
+func IsZonePlacements(spec string) bool {
+	return strings.HasPrefix(spec, zonePlacementPrefix)
+}
+
+// ParseZonePlacements parses a comma-separated list of availability
+// zone placement directives, such as "zone=us-east-1a,zone=us-east-1b",
+// and returns the names of the zones in the order given.
+func ParseZonePlacements(spec string) ([]string, error) {
+	var zones []string
+	seen := make(map[string]bool)
+	for _, directive := range strings.Split(spec, ",") {
+		zone := strings.TrimPrefix(directive, zonePlacementPrefix)
+		if zone == directive || zone == "" {
+			return nil, fmt.Errorf("invalid zone placement %q: expected zone=<availability-zone-name>", directive)
+		}
+		if seen[zone] {
+			return nil, fmt.Errorf("availability zone %q specified more than once", zone)
+		}
+		seen[zone] = true
+		zones = append(zones, zone)
+	}
+	return zones, nil
+}
@@ -75,3 +75,39 @@ func (s *PlacementSuite) TestParsePlacement(c *gc.C) {
 		}
 	}
 }
+
+func (s *PlacementSuite) TestParseZonePlacements(c *gc.C) {
+	parseZonePlacementsTests := []struct {
+		arg    string
+		expect []string
+		err    string
+	}{{
+		arg:    "zone=us-east-1a",
+		expect: []string{"us-east-1a"},
+	}, {
+		arg:    "zone=us-east-1b,zone=us-east-1a",
+		expect: []string{"us-east-1b", "us-east-1a"},
+	}, {
+		arg: "zone=",
+		err: `invalid zone placement "zone=": expected zone=<availability-zone-name>`,
+	}, {
+		arg: "zone=us-east-1a,us-east-1b",
+		err: `invalid zone placement "us-east-1b": expected zone=<availability-zone-name>`,
+	}, {
+		arg: "zone=us-east-1a,zone=us-east-1a",
+		err: `availability zone "us-east-1a" specified more than once`,
+	}}
+
+	for i, t := range parseZonePlacementsTests {
+		c.Logf("test %d: %s", i, t.arg)
+		c.Assert(instance.IsZonePlacements(t.arg), jc.IsTrue)
+		zones, err := instance.ParseZonePlacements(t.arg)
+		if t.err != "" {
+			c.Assert(err, gc.ErrorMatches, t.err)
+		} else {
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(zones, jc.DeepEquals, t.expect)
+		}
+	}
+	c.Assert(instance.IsZonePlacements("lxc:1"), jc.IsFalse)
+}
@@ -5,6 +5,7 @@ package juju
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/juju/errors"
@@ -30,6 +31,8 @@ type DeployServiceParams struct {
 	// ToMachineSpec is either:
 	// - an existing machine/container id eg "1" or "1/lxc/2"
 	// - a new container on an existing machine eg "lxc:1"
+	// - a list of availability zones for new machines eg
+	//   "zone=us-east-1a,zone=us-east-1b"
 	// Use string to avoid ambiguity around machine 0.
 	ToMachineSpec string
 	// Networks holds a list of networks to required to start on boot.
@@ -39,7 +42,46 @@ type DeployServiceParams struct {
 
 // DeployService takes a charm and various parameters and deploys it.
 func DeployService(st *state.State, args DeployServiceParams) (*state.Service, error) {
-	if args.NumUnits > 1 && args.ToMachineSpec != "" {
+	settings, err := checkDeployService(st, &args)
+	if err != nil {
+		return nil, err
+	}
+	service, err := st.AddService(
+		args.ServiceName,
+		args.ServiceOwner,
+		args.Charm,
+		args.Networks,
+		stateStorageConstraints(args.Storage),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) > 0 {
+		if err := service.UpdateConfigSettings(settings); err != nil {
+			return nil, err
+		}
+	}
+	if args.Charm.Meta().Subordinate {
+		return service, nil
+	}
+	if !constraints.IsEmpty(&args.Constraints) {
+		if err := service.SetConstraints(args.Constraints); err != nil {
+			return nil, err
+		}
+	}
+	if args.NumUnits > 0 {
+		if _, err := AddUnits(st, service, args.NumUnits, args.ToMachineSpec); err != nil {
+			return nil, err
+		}
+	}
+	return service, nil
+}
+
+// checkDeployService checks the arguments to DeployService before
+// anything is added to state, filling in the service owner if it was
+// not specified, and returns the validated charm settings.
+func checkDeployService(st *state.State, args *DeployServiceParams) (charm.Settings, error) {
+	if args.NumUnits > 1 && args.ToMachineSpec != "" && !instance.IsZonePlacements(args.ToMachineSpec) {
 		return nil, fmt.Errorf("cannot use --num-units with --to")
 	}
 	settings, err := args.Charm.Config().ValidateSettings(args.ConfigSettings)
@@ -76,39 +118,181 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 			return nil, fmt.Errorf("cannot deploy with networks: not suppored by the environment")
 		}
 	}
-	service, err := st.AddService(
+	return settings, nil
+}
+
+// DeployPlan describes the changes DeployService would make.
+type DeployPlan struct {
+	// Units holds the names of the units that would be added.
+	Units []string
+	// Machines holds the machines that would be provisioned for
+	// the units.
+	Machines []PlannedMachine
+	// Unsupported holds the constraint attributes not supported by
+	// the environment, which would be ignored.
+	Unsupported []string
+}
+
+// PlannedMachine describes a machine that would be provisioned.
+type PlannedMachine struct {
+	// Unit holds the name of the unit the machine would host.
+	Unit        string
+	Series      string
+	Constraints constraints.Value
+	// Placement holds the availability zone of the machine, eg
+	// "zone=us-east-1a", or, for a new container, the machine that
+	// would host it, eg "lxc:1".
+	Placement string
+}
+
+// PlanDeployService runs the same checks as DeployService, without
+// changing the environment, and returns what deploying the service
+// would do. Units without a placement directive are planned on new
+// machines, although a clean, empty machine may be used instead when
+// the service is deployed.
+func PlanDeployService(st *state.State, args DeployServiceParams) (*DeployPlan, error) {
+	if _, err := checkDeployService(st, &args); err != nil {
+		return nil, err
+	}
+	dry := st.DryRun()
+	if _, err := dry.AddService(
 		args.ServiceName,
 		args.ServiceOwner,
 		args.Charm,
 		args.Networks,
 		stateStorageConstraints(args.Storage),
-	)
-	if err != nil {
+	); err != nil {
 		return nil, err
 	}
-	if len(settings) > 0 {
-		if err := service.UpdateConfigSettings(settings); err != nil {
-			return nil, err
+	plan := &DeployPlan{}
+	if args.Charm.Meta().Subordinate {
+		return plan, nil
+	}
+	cons, unsupported, err := st.CheckConstraints(args.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	plan.Unsupported = unsupported
+	var zones []string
+	if instance.IsZonePlacements(args.ToMachineSpec) {
+		if zones, err = instance.ParseZonePlacements(args.ToMachineSpec); err != nil {
+			return nil, errors.Trace(err)
 		}
 	}
-	if args.Charm.Meta().Subordinate {
-		return service, nil
+	series := args.Charm.URL().Series
+	population := make(map[string]int)
+	for i := 0; i < args.NumUnits; i++ {
+		unitName := fmt.Sprintf("%s/%d", args.ServiceName, i)
+		plan.Units = append(plan.Units, unitName)
+		machine := PlannedMachine{
+			Unit:        unitName,
+			Series:      series,
+			Constraints: cons,
+		}
+		if zones != nil {
+			zone, err := planZone(st, series, cons, zones, population)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"cannot assign unit %q to new machine in zones %s: %v",
+					unitName, strings.Join(zones, ", "), err,
+				)
+			}
+			population[zone]++
+			machine.Placement = "zone=" + zone
+		} else if args.ToMachineSpec != "" {
+			mid, containerType, err := parseMachineIdSpec(args.ToMachineSpec)
+			if err != nil {
+				return nil, err
+			}
+			m, err := st.Machine(mid)
+			if err != nil {
+				return nil, fmt.Errorf("cannot assign unit %q to machine: %v", unitName, err)
+			}
+			if containerType != "" {
+				// Check that the container could be created, as
+				// AddUnits would, and the unit assigned to it.
+				template := state.MachineTemplate{
+					Series:            series,
+					Jobs:              []state.MachineJob{state.JobHostUnits},
+					Dirty:             true,
+					Constraints:       args.Constraints,
+					RequestedNetworks: args.Networks,
+				}
+				m, err = dry.AddMachineInsideMachine(template, mid, containerType)
+				if err != nil {
+					return nil, fmt.Errorf("cannot assign unit %q to machine: %v", unitName, err)
+				}
+			}
+			if err := st.CheckAssignToMachine(unitName, series, m); err != nil {
+				return nil, err
+			}
+			if containerType == "" {
+				// The unit would be placed on an existing machine.
+				continue
+			}
+			machine.Placement = args.ToMachineSpec
+		}
+		plan.Machines = append(plan.Machines, machine)
 	}
-	if !constraints.IsEmpty(&args.Constraints) {
-		if err := service.SetConstraints(args.Constraints); err != nil {
-			return nil, err
+	return plan, nil
+}
+
+// planZone returns the zone, of those given, in which a new unit's
+// machine would be placed: the first zone, of those hosting the fewest
+// of the service's units, in which the environment can provision it.
+func planZone(st *state.State, series string, cons constraints.Value, zones []string, population map[string]int) (string, error) {
+	candidates := make([]string, len(zones))
+	copy(candidates, zones)
+	sort.Stable(byPopulation{candidates, population})
+	var lastErr error
+	for _, zone := range candidates {
+		if err := st.CheckPlacement(series, cons, "zone="+zone); err != nil {
+			lastErr = err
+			continue
 		}
+		return zone, nil
 	}
-	if args.NumUnits > 0 {
-		if _, err := AddUnits(st, service, args.NumUnits, args.ToMachineSpec); err != nil {
-			return nil, err
+	return "", lastErr
+}
+
+// byPopulation sorts zone names by the number of units in each.
+type byPopulation struct {
+	zones      []string
+	population map[string]int
+}
+
+func (b byPopulation) Len() int      { return len(b.zones) }
+func (b byPopulation) Swap(i, j int) { b.zones[i], b.zones[j] = b.zones[j], b.zones[i] }
+func (b byPopulation) Less(i, j int) bool {
+	return b.population[b.zones[i]] < b.population[b.zones[j]]
+}
+
+// parseMachineIdSpec parses a placement directive naming an existing
+// machine or container, eg "3/lxc/2", or a new container on a machine,
+// eg "lxc:3".
+func parseMachineIdSpec(machineIdSpec string) (string, instance.ContainerType, error) {
+	mid := machineIdSpec
+	var containerType instance.ContainerType
+	specParts := strings.SplitN(machineIdSpec, ":", 2)
+	if len(specParts) > 1 {
+		firstPart := specParts[0]
+		var err error
+		if containerType, err = instance.ParseContainerType(firstPart); err == nil {
+			mid = specParts[1]
+		} else {
+			mid = machineIdSpec
 		}
 	}
-	return service, nil
+	if !names.IsValidMachine(mid) {
+		return "", "", fmt.Errorf("invalid force machine id %q", mid)
+	}
+	return mid, containerType, nil
 }
 
 // AddUnits starts n units of the given service and allocates machines
-// to them as necessary.
+// to them as necessary. If machineIdSpec lists availability zones, each
+// unit is assigned to a new machine in one of them, spreading the units
+// across the zones.
 func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string) ([]*state.Unit, error) {
 	units := make([]*state.Unit, n)
 	// Hard code for now till we implement a different approach.
@@ -118,32 +302,31 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 	if err != nil {
 		return nil, fmt.Errorf("cannot get service %q networks: %v", svc.Name(), err)
 	}
+	var zones []string
+	if instance.IsZonePlacements(machineIdSpec) {
+		if zones, err = instance.ParseZonePlacements(machineIdSpec); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	// TODO what do we do if we fail half-way through this process?
 	for i := 0; i < n; i++ {
 		unit, err := svc.AddUnit()
 		if err != nil {
 			return nil, fmt.Errorf("cannot add unit %d/%d to service %q: %v", i+1, n, svc.Name(), err)
 		}
-		if machineIdSpec != "" {
+		if zones != nil {
+			if err := unit.AssignToNewMachineInZones(zones); err != nil {
+				return nil, err
+			}
+		} else if machineIdSpec != "" {
 			if n != 1 {
 				return nil, fmt.Errorf("cannot add multiple units of service %q to a single machine", svc.Name())
 			}
 			// machineIdSpec may be an existing machine or container, eg 3/lxc/2
 			// or a new container on a machine, eg lxc:3
-			mid := machineIdSpec
-			var containerType instance.ContainerType
-			specParts := strings.SplitN(machineIdSpec, ":", 2)
-			if len(specParts) > 1 {
-				firstPart := specParts[0]
-				var err error
-				if containerType, err = instance.ParseContainerType(firstPart); err == nil {
-					mid = specParts[1]
-				} else {
-					mid = machineIdSpec
-				}
-			}
-			if !names.IsValidMachine(mid) {
-				return nil, fmt.Errorf("invalid force machine id %q", mid)
+			mid, containerType, err := parseMachineIdSpec(machineIdSpec)
+			if err != nil {
+				return nil, err
 			}
 			var unitCons *constraints.Value
 			unitCons, err = unit.Constraints()
@@ -151,7 +334,6 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 				return nil, err
 			}
 
-			var err error
 			var m *state.Machine
 			// If a container is to be used, create it.
 			if containerType != "" {
@@ -184,13 +366,30 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 	return units, nil
 }
 
+// AddUnitWithStorage adds a unit to the specified service, attaching
+// the existing, detached storage instances with the specified tags to
+// it, and assigns the unit to a new machine. Volumes and filesystems
+// can only be attached to machines that have none of their own, so the
+// unit is never placed on an existing machine.
+func AddUnitWithStorage(st *state.State, svc *state.Service, attachStorage []names.StorageTag) (*state.Unit, error) {
+	unit, err := svc.AddUnitWithStorage(attachStorage)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot add unit to service %q", svc.Name())
+	}
+	if err := st.AssignUnit(unit, state.AssignNew); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return unit, nil
+}
+
 func stateStorageConstraints(cons map[string]storage.Constraints) map[string]state.StorageConstraints {
 	result := make(map[string]state.StorageConstraints)
 	for name, cons := range cons {
 		result[name] = state.StorageConstraints{
-			Pool:  cons.Pool,
-			Size:  cons.Size,
-			Count: cons.Count,
+			Pool:     cons.Pool,
+			Size:     cons.Size,
+			Count:    cons.Count,
+			Snapshot: cons.Snapshot,
 		}
 	}
 	return result
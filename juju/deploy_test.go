@@ -214,6 +214,188 @@ func (s *DeployLocalSuite) TestDeployForceMachineIdWithContainer(c *gc.C) {
 	c.Assert(machineCons, gc.DeepEquals, *unitCons)
 }
 
+func (s *DeployLocalSuite) TestDeployForceZones(c *gc.C) {
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      3,
+			ToMachineSpec: "zone=zone1,zone=zone2",
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := service.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 3)
+
+	// The units are spread across the zones given.
+	var placements []string
+	for _, unit := range units {
+		id, err := unit.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		machine, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		placements = append(placements, machine.Placement())
+	}
+	c.Assert(placements, jc.SameContents, []string{"zone=zone1", "zone=zone2", "zone=zone1"})
+}
+
+func (s *DeployLocalSuite) TestDeployForceZonesUnavailable(c *gc.C) {
+	_, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: "zone=zone3",
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "bob/0" to new machine in zones zone3: availability zone "zone3" is unavailable`)
+}
+
+func (s *DeployLocalSuite) TestPlanDeployService(c *gc.C) {
+	err := s.State.SetEnvironConstraints(constraints.MustParse("mem=2G"))
+	c.Assert(err, jc.ErrorIsNil)
+	plan, err := juju.PlanDeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName: "bob",
+			Charm:       s.charm,
+			Constraints: constraints.MustParse("cpu-cores=2"),
+			NumUnits:    2,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan.Units, jc.DeepEquals, []string{"bob/0", "bob/1"})
+	cons := constraints.MustParse("mem=2G cpu-cores=2")
+	c.Assert(plan.Machines, jc.DeepEquals, []juju.PlannedMachine{
+		{Unit: "bob/0", Series: "quantal", Constraints: cons},
+		{Unit: "bob/1", Series: "quantal", Constraints: cons},
+	})
+
+	// Nothing was deployed.
+	_, err = s.State.Service("bob")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 0)
+}
+
+func (s *DeployLocalSuite) TestPlanDeployServiceExists(c *gc.C) {
+	_, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName: "bob",
+			Charm:       s.charm,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = juju.PlanDeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName: "bob",
+			Charm:       s.charm,
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "bob": service already exists`)
+}
+
+func (s *DeployLocalSuite) TestPlanDeployForceMachineId(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	plan, err := juju.PlanDeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: machine.Id(),
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan.Units, jc.DeepEquals, []string{"bob/0"})
+	c.Assert(plan.Machines, gc.HasLen, 0)
+
+	plan, err = juju.PlanDeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: fmt.Sprintf("%s:%s", instance.LXC, machine.Id()),
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan.Machines, gc.HasLen, 1)
+	c.Assert(plan.Machines[0].Placement, gc.Equals, "lxc:0")
+
+	_, err = juju.PlanDeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: "lxc:42",
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "bob/0" to machine: machine 42 not found`)
+}
+
+func (s *DeployLocalSuite) TestPlanDeployForceMachineIdChecks(c *gc.C) {
+	// The same checks are run as when deploying to the machine.
+	machine, err := s.State.AddMachine("precise", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = juju.PlanDeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: machine.Id(),
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "bob/0" to machine 0: series does not match`)
+
+	machine, err = s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = juju.PlanDeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: machine.Id(),
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "bob/0" to machine 1: machine "1" cannot host units`)
+
+	machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetSupportedContainers([]instance.ContainerType{instance.KVM})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = juju.PlanDeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: "lxc:2",
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "bob/0" to machine: cannot add a new machine: machine 2 cannot host lxc containers`)
+
+	// Nothing was deployed.
+	_, err = s.State.Service("bob")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	containers, err := machine.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *DeployLocalSuite) TestPlanDeployForceZones(c *gc.C) {
+	plan, err := juju.PlanDeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      3,
+			ToMachineSpec: "zone=zone1,zone=zone2",
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	var placements []string
+	for _, m := range plan.Machines {
+		placements = append(placements, m.Placement)
+	}
+	c.Assert(placements, jc.DeepEquals, []string{"zone=zone1", "zone=zone2", "zone=zone1"})
+
+	_, err = juju.PlanDeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: "zone=zone3",
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "bob/0" to new machine in zones zone3: availability zone "zone3" is unavailable`)
+}
+
 func (s *DeployLocalSuite) assertCharm(c *gc.C, service *state.Service, expect *charm.URL) {
 	curl, force := service.CharmURL()
 	c.Assert(curl, gc.DeepEquals, expect)
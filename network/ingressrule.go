@@ -0,0 +1,108 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// AnySourceCIDR is the source CIDR of an ingress rule that allows
+// access from any address.
+const AnySourceCIDR = "0.0.0.0/0"
+
+// IngressRule represents a range of ports that may be accessed
+// from addresses in a single source CIDR.
+type IngressRule struct {
+	PortRange
+	SourceCIDR string
+}
+
+// NewIngressRules returns the ingress rules allowing access to each
+// of the port ranges from each of the source CIDRs. If no source
+// CIDRs are specified, access is allowed from any address.
+func NewIngressRules(portRanges []PortRange, sourceCIDRs ...string) []IngressRule {
+	if len(sourceCIDRs) == 0 {
+		sourceCIDRs = []string{AnySourceCIDR}
+	}
+	rules := make([]IngressRule, 0, len(portRanges)*len(sourceCIDRs))
+	for _, portRange := range portRanges {
+		for _, cidr := range sourceCIDRs {
+			rules = append(rules, IngressRule{portRange, cidr})
+		}
+	}
+	return rules
+}
+
+// Validate determines if the ingress rule is valid.
+func (r IngressRule) Validate() error {
+	if err := r.PortRange.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	return ValidateSourceCIDRs([]string{r.SourceCIDR})
+}
+
+func (r IngressRule) String() string {
+	return fmt.Sprintf("%s from %s", r.PortRange, r.SourceCIDR)
+}
+
+func (r IngressRule) GoString() string {
+	return r.String()
+}
+
+// ValidateSourceCIDRs returns an error if any of the specified
+// source CIDRs is not a valid IPv4 or IPv6 CIDR.
+func ValidateSourceCIDRs(cidrs []string) error {
+	for _, cidr := range cidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.Errorf("invalid source CIDR %q", cidr)
+		}
+	}
+	return nil
+}
+
+// ParseSourceCIDRs splits the provided string on commas and
+// validates each part as a source CIDR. Whitespace is ignored.
+// Example strings: "10.0.0.0/8", "10.0.0.0/8, 203.0.113.4/32".
+func ParseSourceCIDRs(inCIDRs string) ([]string, error) {
+	var cidrs []string
+	for _, cidr := range strings.Split(inCIDRs, ",") {
+		cidrs = append(cidrs, strings.TrimSpace(cidr))
+	}
+	if err := ValidateSourceCIDRs(cidrs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cidrs, nil
+}
+
+type ingressRuleSlice []IngressRule
+
+func (r ingressRuleSlice) Len() int      { return len(r) }
+func (r ingressRuleSlice) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r ingressRuleSlice) Less(i, j int) bool {
+	if r[i].PortRange != r[j].PortRange {
+		return portRangeSlice{r[i].PortRange, r[j].PortRange}.Less(0, 1)
+	}
+	return r[i].SourceCIDR < r[j].SourceCIDR
+}
+
+// SortIngressRules sorts the given rules, first by port range,
+// then by source CIDR.
+func SortIngressRules(rules []IngressRule) {
+	sort.Sort(ingressRuleSlice(rules))
+}
+
+// GroupIngressRules returns the source CIDRs of the given rules,
+// keyed by port range.
+func GroupIngressRules(rules []IngressRule) map[PortRange][]string {
+	groups := make(map[PortRange][]string)
+	for _, rule := range rules {
+		groups[rule.PortRange] = append(groups[rule.PortRange], rule.SourceCIDR)
+	}
+	return groups
+}
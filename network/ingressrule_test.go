@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type IngressRuleSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&IngressRuleSuite{})
+
+func (*IngressRuleSuite) TestNewIngressRules(c *gc.C) {
+	ports := []network.PortRange{
+		network.MustParsePortRange("80/tcp"),
+		network.MustParsePortRange("443/tcp"),
+	}
+	rules := network.NewIngressRules(ports)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		{ports[0], network.AnySourceCIDR},
+		{ports[1], network.AnySourceCIDR},
+	})
+
+	rules = network.NewIngressRules(ports, "10.0.0.0/8", "203.0.113.4/32")
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		{ports[0], "10.0.0.0/8"},
+		{ports[0], "203.0.113.4/32"},
+		{ports[1], "10.0.0.0/8"},
+		{ports[1], "203.0.113.4/32"},
+	})
+}
+
+func (*IngressRuleSuite) TestValidate(c *gc.C) {
+	rule := network.IngressRule{network.MustParsePortRange("80/tcp"), "10.0.0.0/8"}
+	c.Assert(rule.Validate(), jc.ErrorIsNil)
+
+	rule.SourceCIDR = "10.0.0.0"
+	c.Assert(rule.Validate(), gc.ErrorMatches, `invalid source CIDR "10.0.0.0"`)
+
+	rule = network.IngressRule{network.PortRange{80, 80, "icmp"}, "10.0.0.0/8"}
+	c.Assert(rule.Validate(), gc.ErrorMatches, `invalid protocol "icmp", expected "tcp" or "udp"`)
+}
+
+func (*IngressRuleSuite) TestString(c *gc.C) {
+	rule := network.IngressRule{network.MustParsePortRange("80-90/tcp"), "10.0.0.0/8"}
+	c.Assert(rule.String(), gc.Equals, "80-90/tcp from 10.0.0.0/8")
+}
+
+func (*IngressRuleSuite) TestParseSourceCIDRs(c *gc.C) {
+	cidrs, err := network.ParseSourceCIDRs("10.0.0.0/8, 203.0.113.4/32,2001:db8::/32")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, []string{"10.0.0.0/8", "203.0.113.4/32", "2001:db8::/32"})
+
+	_, err = network.ParseSourceCIDRs("10.0.0.0/8,")
+	c.Assert(err, gc.ErrorMatches, `invalid source CIDR ""`)
+
+	_, err = network.ParseSourceCIDRs("10.0.0.0/33")
+	c.Assert(err, gc.ErrorMatches, `invalid source CIDR "10.0.0.0/33"`)
+}
+
+func (*IngressRuleSuite) TestSortIngressRules(c *gc.C) {
+	rules := []network.IngressRule{
+		{network.MustParsePortRange("443/tcp"), "10.0.0.0/8"},
+		{network.MustParsePortRange("80/tcp"), "203.0.113.4/32"},
+		{network.MustParsePortRange("53/udp"), network.AnySourceCIDR},
+		{network.MustParsePortRange("80/tcp"), "10.0.0.0/8"},
+	}
+	network.SortIngressRules(rules)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		{network.MustParsePortRange("80/tcp"), "10.0.0.0/8"},
+		{network.MustParsePortRange("80/tcp"), "203.0.113.4/32"},
+		{network.MustParsePortRange("443/tcp"), "10.0.0.0/8"},
+		{network.MustParsePortRange("53/udp"), network.AnySourceCIDR},
+	})
+}
+
+func (*IngressRuleSuite) TestGroupIngressRules(c *gc.C) {
+	http := network.MustParsePortRange("80/tcp")
+	https := network.MustParsePortRange("443/tcp")
+	groups := network.GroupIngressRules([]network.IngressRule{
+		{http, "10.0.0.0/8"},
+		{https, network.AnySourceCIDR},
+		{http, "203.0.113.4/32"},
+	})
+	c.Assert(groups, jc.DeepEquals, map[network.PortRange][]string{
+		http:  {"10.0.0.0/8", "203.0.113.4/32"},
+		https: {network.AnySourceCIDR},
+	})
+}
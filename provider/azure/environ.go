@@ -567,6 +567,8 @@ func (env *azureEnviron) selectInstanceTypeAndImage(constraint *instances.Instan
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
+	constraints.InstanceLifecycle,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -36,6 +36,8 @@ var unsupportedConstraints = []string{
 	constraints.Container,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.InstanceLifecycle,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator returns a Validator instance which
@@ -209,17 +209,19 @@ type OpStopInstances struct {
 }
 
 type OpOpenPorts struct {
-	Env        string
-	MachineId  string
-	InstanceId instance.Id
-	Ports      []network.PortRange
+	Env          string
+	MachineId    string
+	InstanceId   instance.Id
+	Ports        []network.PortRange
+	IngressRules []network.IngressRule
 }
 
 type OpClosePorts struct {
-	Env        string
-	MachineId  string
-	InstanceId instance.Id
-	Ports      []network.PortRange
+	Env          string
+	MachineId    string
+	InstanceId   instance.Id
+	Ports        []network.PortRange
+	IngressRules []network.IngressRule
 }
 
 type OpPutFile struct {
@@ -254,7 +256,7 @@ type environState struct {
 	maxId        int // maximum instance id allocated so far.
 	maxAddr      int // maximum allocated address last byte
 	insts        map[instance.Id]*dummyInstance
-	globalPorts  map[network.PortRange]bool
+	globalRules  map[network.IngressRule]bool
 	bootstrapped bool
 	storageDelay time.Duration
 	storage      *storageServer
@@ -369,7 +371,7 @@ func newState(name string, ops chan<- Operation, policy state.Policy) *environSt
 		ops:         ops,
 		statePolicy: policy,
 		insts:       make(map[instance.Id]*dummyInstance),
-		globalPorts: make(map[network.PortRange]bool),
+		globalRules: make(map[network.IngressRule]bool),
 	}
 	s.storage = newStorageServer(s, "/"+name+"/private")
 	s.listenStorage()
@@ -641,11 +643,82 @@ func (*environ) SupportedArchitectures() ([]string, error) {
 }
 
 // PrecheckInstance is specified in the state.Prechecker interface.
-func (*environ) PrecheckInstance(series string, cons constraints.Value, placement string) error {
-	if placement != "" && placement != "valid" {
-		return fmt.Errorf("%s placement is invalid", placement)
+func (e *environ) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+	_, err := e.parsePlacement(placement)
+	return err
+}
+
+// parsePlacement returns the availability zone named by the given
+// placement directive, or "" if it names none. The only directives
+// accepted are "valid" and "zone=<name>".
+func (e *environ) parsePlacement(placement string) (string, error) {
+	if placement == "" || placement == "valid" {
+		return "", nil
 	}
-	return nil
+	if !strings.HasPrefix(placement, "zone=") {
+		return "", fmt.Errorf("%s placement is invalid", placement)
+	}
+	name := strings.TrimPrefix(placement, "zone=")
+	zones, err := e.AvailabilityZones()
+	if err != nil {
+		return "", err
+	}
+	for _, zone := range zones {
+		if zone.Name() != name {
+			continue
+		}
+		if !zone.Available() {
+			return "", fmt.Errorf("availability zone %q is unavailable", name)
+		}
+		return name, nil
+	}
+	return "", fmt.Errorf("invalid availability zone %q", name)
+}
+
+// AvailabilityZones is specified in the common.ZonedEnviron interface.
+func (e *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+	if err := e.checkBroken("AvailabilityZones"); err != nil {
+		return nil, err
+	}
+	return availabilityZones, nil
+}
+
+// InstanceAvailabilityZoneNames is specified in the common.ZonedEnviron
+// interface.
+func (e *environ) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error) {
+	instances, err := e.Instances(ids)
+	if err != nil && err != environs.ErrPartialInstances {
+		return nil, err
+	}
+	zones := make([]string, len(instances))
+	for i, inst := range instances {
+		if inst != nil {
+			zones[i] = inst.(*dummyInstance).zone
+		}
+	}
+	return zones, err
+}
+
+// availabilityZones holds the availability zones of every dummy
+// environment. Instances are started in a zone only if one is
+// requested with a "zone=<name>" placement directive.
+var availabilityZones = []common.AvailabilityZone{
+	dummyAvailabilityZone{name: "zone1", available: true},
+	dummyAvailabilityZone{name: "zone2", available: true},
+	dummyAvailabilityZone{name: "zone3", available: false},
+}
+
+type dummyAvailabilityZone struct {
+	name      string
+	available bool
+}
+
+func (z dummyAvailabilityZone) Name() string {
+	return z.name
+}
+
+func (z dummyAvailabilityZone) Available() bool {
+	return z.available
 }
 
 func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.BootstrapParams) (arch, series string, _ environs.BootstrapFinalizer, _ error) {
@@ -691,7 +764,7 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 	i := &dummyInstance{
 		id:           BootstrapInstanceId,
 		addresses:    network.NewAddresses("localhost"),
-		ports:        make(map[network.PortRange]bool),
+		rules:        make(map[network.IngressRule]bool),
 		machineId:    agent.BootstrapMachineId,
 		series:       series,
 		firewallMode: e.Config().FirewallMode(),
@@ -840,6 +913,10 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	if err := e.checkBroken("StartInstance"); err != nil {
 		return nil, err
 	}
+	zone, err := e.parsePlacement(args.Placement)
+	if err != nil {
+		return nil, err
+	}
 	estate, err := e.state()
 	if err != nil {
 		return nil, err
@@ -878,10 +955,11 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	i := &dummyInstance{
 		id:           instance.Id(idString),
 		addresses:    addrs,
-		ports:        make(map[network.PortRange]bool),
+		rules:        make(map[network.IngressRule]bool),
 		machineId:    machineId,
 		series:       series,
 		firewallMode: e.Config().FirewallMode(),
+		zone:         zone,
 		state:        estate,
 	}
 
@@ -916,6 +994,9 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 			cores := uint64(1)
 			hc.CpuCores = &cores
 		}
+		if zone != "" {
+			hc.AvailabilityZone = &zone
+		}
 	}
 	// Simulate networks added when requested.
 	networks := append(args.Constraints.IncludeNetworks(), args.InstanceConfig.Networks...)
@@ -1278,6 +1359,23 @@ func (e *environ) AllInstances() ([]instance.Instance, error) {
 }
 
 func (e *environ) OpenPorts(ports []network.PortRange) error {
+	return e.OpenIngressRules(network.NewIngressRules(ports))
+}
+
+func (e *environ) ClosePorts(ports []network.PortRange) error {
+	return e.CloseIngressRules(network.NewIngressRules(ports))
+}
+
+func (e *environ) Ports() ([]network.PortRange, error) {
+	rules, err := e.IngressRules()
+	if err != nil {
+		return nil, err
+	}
+	return unrestrictedPorts(rules), nil
+}
+
+// OpenIngressRules is specified on environs.IngressRules.
+func (e *environ) OpenIngressRules(rules []network.IngressRule) error {
 	if mode := e.ecfg().FirewallMode(); mode != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on environment", mode)
 	}
@@ -1287,13 +1385,14 @@ func (e *environ) OpenPorts(ports []network.PortRange) error {
 	}
 	estate.mu.Lock()
 	defer estate.mu.Unlock()
-	for _, p := range ports {
-		estate.globalPorts[p] = true
+	for _, r := range rules {
+		estate.globalRules[r] = true
 	}
 	return nil
 }
 
-func (e *environ) ClosePorts(ports []network.PortRange) error {
+// CloseIngressRules is specified on environs.IngressRules.
+func (e *environ) CloseIngressRules(rules []network.IngressRule) error {
 	if mode := e.ecfg().FirewallMode(); mode != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on environment", mode)
 	}
@@ -1303,13 +1402,14 @@ func (e *environ) ClosePorts(ports []network.PortRange) error {
 	}
 	estate.mu.Lock()
 	defer estate.mu.Unlock()
-	for _, p := range ports {
-		delete(estate.globalPorts, p)
+	for _, r := range rules {
+		delete(estate.globalRules, r)
 	}
 	return nil
 }
 
-func (e *environ) Ports() (ports []network.PortRange, err error) {
+// IngressRules is specified on environs.IngressRules.
+func (e *environ) IngressRules() (rules []network.IngressRule, err error) {
 	if mode := e.ecfg().FirewallMode(); mode != config.FwGlobal {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment", mode)
 	}
@@ -1319,25 +1419,47 @@ func (e *environ) Ports() (ports []network.PortRange, err error) {
 	}
 	estate.mu.Lock()
 	defer estate.mu.Unlock()
-	for p := range estate.globalPorts {
-		ports = append(ports, p)
+	for r := range estate.globalRules {
+		rules = append(rules, r)
 	}
-	network.SortPortRanges(ports)
+	network.SortIngressRules(rules)
 	return
 }
 
+// unrestrictedPorts returns the port ranges of the rules
+// that allow access from any address.
+func unrestrictedPorts(rules []network.IngressRule) []network.PortRange {
+	var ports []network.PortRange
+	for _, r := range rules {
+		if r.SourceCIDR == network.AnySourceCIDR {
+			ports = append(ports, r.PortRange)
+		}
+	}
+	return ports
+}
+
+// portRanges returns the port ranges of the rules.
+func portRanges(rules []network.IngressRule) []network.PortRange {
+	ports := make([]network.PortRange, len(rules))
+	for i, r := range rules {
+		ports[i] = r.PortRange
+	}
+	return ports
+}
+
 func (*environ) Provider() environs.EnvironProvider {
 	return &providerInstance
 }
 
 type dummyInstance struct {
 	state        *environState
-	ports        map[network.PortRange]bool
+	rules        map[network.IngressRule]bool
 	id           instance.Id
 	status       string
 	machineId    string
 	series       string
 	firewallMode string
+	zone         string
 	stateServer  bool
 
 	mu        sync.Mutex
@@ -1382,8 +1504,25 @@ func (inst *dummyInstance) Addresses() ([]network.Address, error) {
 }
 
 func (inst *dummyInstance) OpenPorts(machineId string, ports []network.PortRange) error {
+	return inst.OpenIngressRules(machineId, network.NewIngressRules(ports))
+}
+
+func (inst *dummyInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+	return inst.CloseIngressRules(machineId, network.NewIngressRules(ports))
+}
+
+func (inst *dummyInstance) Ports(machineId string) ([]network.PortRange, error) {
+	rules, err := inst.IngressRules(machineId)
+	if err != nil {
+		return nil, err
+	}
+	return unrestrictedPorts(rules), nil
+}
+
+// OpenIngressRules is specified on instance.IngressRulesInstance.
+func (inst *dummyInstance) OpenIngressRules(machineId string, rules []network.IngressRule) error {
 	defer delay()
-	logger.Infof("openPorts %s, %#v", machineId, ports)
+	logger.Infof("openPorts %s, %#v", machineId, rules)
 	if inst.firewallMode != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on instance",
 			inst.firewallMode)
@@ -1394,18 +1533,20 @@ func (inst *dummyInstance) OpenPorts(machineId string, ports []network.PortRange
 	inst.state.mu.Lock()
 	defer inst.state.mu.Unlock()
 	inst.state.ops <- OpOpenPorts{
-		Env:        inst.state.name,
-		MachineId:  machineId,
-		InstanceId: inst.Id(),
-		Ports:      ports,
+		Env:          inst.state.name,
+		MachineId:    machineId,
+		InstanceId:   inst.Id(),
+		Ports:        portRanges(rules),
+		IngressRules: rules,
 	}
-	for _, p := range ports {
-		inst.ports[p] = true
+	for _, r := range rules {
+		inst.rules[r] = true
 	}
 	return nil
 }
 
-func (inst *dummyInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+// CloseIngressRules is specified on instance.IngressRulesInstance.
+func (inst *dummyInstance) CloseIngressRules(machineId string, rules []network.IngressRule) error {
 	defer delay()
 	if inst.firewallMode != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on instance",
@@ -1417,18 +1558,20 @@ func (inst *dummyInstance) ClosePorts(machineId string, ports []network.PortRang
 	inst.state.mu.Lock()
 	defer inst.state.mu.Unlock()
 	inst.state.ops <- OpClosePorts{
-		Env:        inst.state.name,
-		MachineId:  machineId,
-		InstanceId: inst.Id(),
-		Ports:      ports,
+		Env:          inst.state.name,
+		MachineId:    machineId,
+		InstanceId:   inst.Id(),
+		Ports:        portRanges(rules),
+		IngressRules: rules,
 	}
-	for _, p := range ports {
-		delete(inst.ports, p)
+	for _, r := range rules {
+		delete(inst.rules, r)
 	}
 	return nil
 }
 
-func (inst *dummyInstance) Ports(machineId string) (ports []network.PortRange, err error) {
+// IngressRules is specified on instance.IngressRulesInstance.
+func (inst *dummyInstance) IngressRules(machineId string) (rules []network.IngressRule, err error) {
 	defer delay()
 	if inst.firewallMode != config.FwInstance {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
@@ -1439,10 +1582,10 @@ func (inst *dummyInstance) Ports(machineId string) (ports []network.PortRange, e
 	}
 	inst.state.mu.Lock()
 	defer inst.state.mu.Unlock()
-	for p := range inst.ports {
-		ports = append(ports, p)
+	for r := range inst.rules {
+		rules = append(rules, r)
 	}
-	network.SortPortRanges(ports)
+	network.SortIngressRules(rules)
 	return
 }
 
@@ -13,6 +13,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/bootstrap"
 	"github.com/juju/juju/environs/config"
@@ -21,7 +22,9 @@ import (
 	"github.com/juju/juju/instance"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 )
 
@@ -133,6 +136,36 @@ func (s *suite) TestAvailabilityZone(c *gc.C) {
 	c.Check(hwc.AvailabilityZone, gc.IsNil)
 }
 
+func (s *suite) TestAvailabilityZonePlacement(c *gc.C) {
+	e := s.bootstrapTestEnviron(c, true)
+	defer func() {
+		err := e.Destroy()
+		c.Assert(err, jc.ErrorIsNil)
+	}()
+
+	zonedEnv := e.(common.ZonedEnviron)
+	zones, err := zonedEnv.AvailabilityZones()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 3)
+
+	result, err := jujutesting.StartInstanceWithParams(e, "1", environs.StartInstanceParams{
+		Placement: "zone=zone2",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*result.Hardware.AvailabilityZone, gc.Equals, "zone2")
+	instZones, err := zonedEnv.InstanceAvailabilityZoneNames([]instance.Id{result.Instance.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instZones, jc.DeepEquals, []string{"zone2"})
+
+	prechecker := e.(state.Prechecker)
+	err = prechecker.PrecheckInstance("quantal", constraints.Value{}, "zone=zone1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = prechecker.PrecheckInstance("quantal", constraints.Value{}, "zone=zone3")
+	c.Assert(err, gc.ErrorMatches, `availability zone "zone3" is unavailable`)
+	err = prechecker.PrecheckInstance("quantal", constraints.Value{}, "zone=zone4")
+	c.Assert(err, gc.ErrorMatches, `invalid availability zone "zone4"`)
+}
+
 func (s *suite) TestSupportsAddressAllocation(c *gc.C) {
 	e := s.bootstrapTestEnviron(c, false)
 	defer func() {
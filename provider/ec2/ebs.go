@@ -4,6 +4,10 @@
 package ec2
 
 import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
@@ -52,6 +56,7 @@ const (
 	deviceInUse        = "InvalidDevice.InUse"
 	volumeInUse        = "VolumeInUse"
 	attachmentNotFound = "InvalidAttachment.NotFound"
+	snapshotNotFound   = "InvalidSnapshot.NotFound"
 	incorrectState     = "IncorrectState"
 )
 
@@ -248,6 +253,7 @@ func (v *ebsVolumeSource) CreateVolumes(params []storage.VolumeParams) (_ []stor
 	for _, p := range params {
 		var instId string
 		vol, persistent, _ := parseVolumeOptions(p.Size, p.Attributes)
+		vol.SnapshotId = p.SnapshotId
 		if !persistent {
 			instId = string(p.Attachment.InstanceId)
 			vol.AvailZone = instances[instId].AvailZone
@@ -501,6 +507,132 @@ func (v *ebsVolumeSource) describeVolume(volumeId string) (*ec2.Volume, error) {
 	return &resp.Volumes[0], nil
 }
 
+var _ storage.VolumeResizer = (*ebsVolumeSource)(nil)
+
+// ResizeVolumes is specified on the storage.VolumeResizer interface.
+func (v *ebsVolumeSource) ResizeVolumes(params []storage.VolumeResizeParams) ([]storage.Volume, error) {
+	volumes := make([]storage.Volume, len(params))
+	for i, p := range params {
+		sizeInGib := mibToGib(p.Size)
+		if sizeInGib > volumeSizeMaxGiB {
+			return nil, errors.Errorf(
+				"resizing volume %s: %d GiB exceeds the maximum of %d GiB",
+				p.Tag.Id(), sizeInGib, volumeSizeMaxGiB,
+			)
+		}
+		if err := modifyVolume(v.ec2, p.VolumeId, int(sizeInGib)); err != nil {
+			return nil, errors.Annotatef(err, "resizing volume %s", p.Tag.Id())
+		}
+		volumes[i] = storage.Volume{
+			Tag:      p.Tag,
+			VolumeId: p.VolumeId,
+			Size:     gibToMib(sizeInGib),
+		}
+	}
+	return volumes, nil
+}
+
+var _ storage.VolumeSnapshotter = (*ebsVolumeSource)(nil)
+
+// CreateSnapshots is specified on the storage.VolumeSnapshotter interface.
+func (v *ebsVolumeSource) CreateSnapshots(params []storage.SnapshotParams) ([]storage.Snapshot, error) {
+	snapshots := make([]storage.Snapshot, len(params))
+	for i, p := range params {
+		description := fmt.Sprintf("juju snapshot %s of volume %s", p.Id, p.Volume.Id())
+		snapshotId, err := createSnapshot(v.ec2, p.VolumeId, description)
+		if err != nil {
+			return nil, errors.Annotatef(err, "snapshotting volume %s", p.Volume.Id())
+		}
+		snapshots[i] = storage.Snapshot{
+			Id:         p.Id,
+			Volume:     p.Volume,
+			SnapshotId: snapshotId,
+			Size:       p.Size,
+		}
+	}
+	return snapshots, nil
+}
+
+// DestroySnapshots is specified on the storage.VolumeSnapshotter interface.
+func (v *ebsVolumeSource) DestroySnapshots(snapshotIds []string) []error {
+	results := make([]error, len(snapshotIds))
+	for i, snapshotId := range snapshotIds {
+		err := deleteSnapshot(v.ec2, snapshotId)
+		if ec2Err, ok := err.(*ec2.Error); ok && ec2Err.Code == snapshotNotFound {
+			// The snapshot has already been deleted.
+			err = nil
+		}
+		if err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", snapshotId)
+		}
+	}
+	return results
+}
+
+// createSnapshot starts a snapshot of the EBS volume with the given ID,
+// and returns the ID of the snapshot. The snapshot may not be complete
+// when createSnapshot returns, but EC2 ensures that the snapshot contains
+// the volume's data at the time of the request.
+var createSnapshot = func(client *ec2.EC2, volumeId, description string) (string, error) {
+	resp, err := client.CreateSnapshot(volumeId, description)
+	if err != nil {
+		return "", err
+	}
+	return resp.Snapshot.Id, nil
+}
+
+// deleteSnapshot deletes the EBS snapshot with the given ID.
+var deleteSnapshot = func(client *ec2.EC2, snapshotId string) error {
+	_, err := client.DeleteSnapshots([]string{snapshotId})
+	return err
+}
+
+// modifyVolumeAPIVersion is the version of the EC2 API that introduced
+// the ModifyVolume action.
+const modifyVolumeAPIVersion = "2016-11-15"
+
+// modifyVolume requests that the size of the EBS volume with the given
+// ID be changed to the given number of GiB. The ec2 package does not
+// yet support the ModifyVolume action, so the request is made directly
+// using the client's credentials and signer.
+var modifyVolume = func(client *ec2.EC2, volumeId string, sizeInGib int) error {
+	req, err := http.NewRequest("GET", client.Region.EC2Endpoint, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	query := req.URL.Query()
+	query.Add("Action", "ModifyVolume")
+	query.Add("Version", modifyVolumeAPIVersion)
+	query.Add("VolumeId", volumeId)
+	query.Add("Size", strconv.Itoa(sizeInGib))
+	query.Add("Timestamp", time.Now().In(time.UTC).Format(time.RFC3339))
+	req.URL.RawQuery = query.Encode()
+	if err := client.Sign(req, client.Auth); err != nil {
+		return errors.Annotate(err, "signing request")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var errResp struct {
+		Errors []ec2.Error `xml:"Errors>Error"`
+	}
+	if err := xml.Unmarshal(body, &errResp); err != nil || len(errResp.Errors) == 0 {
+		return errors.Errorf("unexpected response %q", resp.Status)
+	}
+	ec2Err := errResp.Errors[0]
+	ec2Err.StatusCode = resp.StatusCode
+	return &ec2Err
+}
+
 // instances returns a mapping from the specified instance IDs to ec2.Instance
 // structures. If any of the specified IDs does not refer to a running instance,
 // it will cause an error to be returned.
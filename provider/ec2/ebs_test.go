@@ -4,12 +4,17 @@
 package ec2_test
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"sort"
 	"strconv"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
+	"gopkg.in/amz.v3/aws"
 	awsec2 "gopkg.in/amz.v3/ec2"
 	"gopkg.in/amz.v3/ec2/ec2test"
 	gc "gopkg.in/check.v1"
@@ -427,6 +432,154 @@ func (s *ebsVolumeSuite) TestDetachVolumes(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *ebsVolumeSuite) TestResizeVolumes(c *gc.C) {
+	var calls []string
+	s.PatchValue(ec2.ModifyVolume, func(_ *awsec2.EC2, volumeId string, sizeInGib int) error {
+		calls = append(calls, volumeId+":"+strconv.Itoa(sizeInGib))
+		return nil
+	})
+	vs := s.volumeSource(c, nil)
+	c.Assert(vs, gc.Implements, new(storage.VolumeResizer))
+	volumes, err := vs.(storage.VolumeResizer).ResizeVolumes([]storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "vol-0",
+		Size:     1500,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes, jc.DeepEquals, []storage.Volume{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "vol-0",
+		Size:     2048,
+	}})
+	c.Assert(calls, jc.DeepEquals, []string{"vol-0:2"})
+}
+
+func (s *ebsVolumeSuite) TestResizeVolumesErrors(c *gc.C) {
+	s.PatchValue(ec2.ModifyVolume, func(*awsec2.EC2, string, int) error {
+		return errors.New("IncorrectModificationState")
+	})
+	vs := s.volumeSource(c, nil).(storage.VolumeResizer)
+	_, err := vs.ResizeVolumes([]storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "vol-0",
+		Size:     1024,
+	}})
+	c.Assert(err, gc.ErrorMatches, "resizing volume 0: IncorrectModificationState")
+
+	_, err = vs.ResizeVolumes([]storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "vol-0",
+		Size:     1025 * 1024,
+	}})
+	c.Assert(err, gc.ErrorMatches, "resizing volume 0: 1025 GiB exceeds the maximum of 1024 GiB")
+}
+
+func (*storageSuite) TestModifyVolume(c *gc.C) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		c.Check(req.Method, gc.Equals, "GET")
+		query = req.URL.Query()
+		fmt.Fprint(w, `<ModifyVolumeResponse><requestId>req-0</requestId></ModifyVolumeResponse>`)
+	}))
+	defer srv.Close()
+
+	client := awsec2.New(
+		aws.Auth{AccessKey: "access-key", SecretKey: "secret-key"},
+		aws.Region{Name: "test", EC2Endpoint: srv.URL},
+		aws.SignV2,
+	)
+	err := (*ec2.ModifyVolume)(client, "vol-0", 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(query.Get("Action"), gc.Equals, "ModifyVolume")
+	c.Assert(query.Get("Version"), gc.Equals, "2016-11-15")
+	c.Assert(query.Get("VolumeId"), gc.Equals, "vol-0")
+	c.Assert(query.Get("Size"), gc.Equals, "2")
+	c.Assert(query.Get("Timestamp"), gc.Not(gc.Equals), "")
+	// The request is signed with the client's credentials.
+	c.Assert(query.Get("AWSAccessKeyId"), gc.Equals, "access-key")
+	c.Assert(query.Get("Signature"), gc.Not(gc.Equals), "")
+}
+
+func (*storageSuite) TestModifyVolumeError(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<Response><Errors><Error>`+
+			`<Code>IncorrectModificationState</Code>`+
+			`<Message>the volume is being modified</Message>`+
+			`</Error></Errors><RequestID>req-0</RequestID></Response>`)
+	}))
+	defer srv.Close()
+
+	client := awsec2.New(
+		aws.Auth{AccessKey: "access-key", SecretKey: "secret-key"},
+		aws.Region{Name: "test", EC2Endpoint: srv.URL},
+		aws.SignV2,
+	)
+	err := (*ec2.ModifyVolume)(client, "vol-0", 2)
+	c.Assert(err, gc.FitsTypeOf, &awsec2.Error{})
+	ec2Err := err.(*awsec2.Error)
+	c.Assert(ec2Err.Code, gc.Equals, "IncorrectModificationState")
+	c.Assert(ec2Err.Message, gc.Equals, "the volume is being modified")
+	c.Assert(ec2Err.StatusCode, gc.Equals, http.StatusBadRequest)
+}
+
+func (*storageSuite) TestModifyVolumeUnexpectedResponse(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "gateway timeout", http.StatusGatewayTimeout)
+	}))
+	defer srv.Close()
+
+	client := awsec2.New(
+		aws.Auth{AccessKey: "access-key", SecretKey: "secret-key"},
+		aws.Region{Name: "test", EC2Endpoint: srv.URL},
+		aws.SignV2,
+	)
+	err := (*ec2.ModifyVolume)(client, "vol-0", 2)
+	c.Assert(err, gc.ErrorMatches, `unexpected response "504 Gateway Timeout"`)
+}
+
+func (s *ebsVolumeSuite) TestCreateSnapshots(c *gc.C) {
+	var calls []string
+	s.PatchValue(ec2.CreateSnapshot, func(_ *awsec2.EC2, volumeId, description string) (string, error) {
+		calls = append(calls, volumeId+":"+description)
+		return "snap-0", nil
+	})
+	vs := s.volumeSource(c, nil)
+	c.Assert(vs, gc.Implements, new(storage.VolumeSnapshotter))
+	snapshots, err := vs.(storage.VolumeSnapshotter).CreateSnapshots([]storage.SnapshotParams{{
+		Id:       "1",
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "vol-0",
+		Size:     1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshots, jc.DeepEquals, []storage.Snapshot{{
+		Id:         "1",
+		Volume:     names.NewVolumeTag("0"),
+		SnapshotId: "snap-0",
+		Size:       1024,
+	}})
+	c.Assert(calls, jc.DeepEquals, []string{"vol-0:juju snapshot 1 of volume 0"})
+}
+
+func (s *ebsVolumeSuite) TestDestroySnapshots(c *gc.C) {
+	s.PatchValue(ec2.DeleteSnapshot, func(_ *awsec2.EC2, snapshotId string) error {
+		switch snapshotId {
+		case "snap-1":
+			return &awsec2.Error{Code: "InvalidSnapshot.NotFound"}
+		case "snap-2":
+			return errors.New("InvalidSnapshot.InUse")
+		}
+		return nil
+	})
+	vs := s.volumeSource(c, nil).(storage.VolumeSnapshotter)
+	errs := vs.DestroySnapshots([]string{"snap-0", "snap-1", "snap-2"})
+	c.Assert(errs, gc.HasLen, 3)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], jc.ErrorIsNil)
+	c.Assert(errs[2], gc.ErrorMatches, `destroying "snap-2": InvalidSnapshot.InUse`)
+}
+
 type blockDeviceMappingSuite struct {
 	testing.BaseSuite
 }
@@ -358,6 +358,9 @@ func (e *environ) PrecheckInstance(series string, cons constraints.Value, placem
 			return err
 		}
 	}
+	if err := validateLifecycleConstraints(cons); err != nil {
+		return err
+	}
 	if !cons.HasInstanceType() {
 		return nil
 	}
@@ -423,6 +426,9 @@ var availabilityZoneAllocations = common.AvailabilityZoneAllocations
 
 // StartInstance is specified in the InstanceBroker interface.
 func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	if err := validateLifecycleConstraints(args.Constraints); err != nil {
+		return nil, err
+	}
 	var availabilityZones []string
 	if args.Placement != "" {
 		placement, err := e.parsePlacement(args.Placement)
@@ -507,8 +513,12 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	}
 	rootDiskSize := uint64(blockDeviceMappings[0].VolumeSize) * 1024
 
+	lifecycle := instance.LifecycleOnDemand
+	if args.Constraints.HasSpotLifecycle() {
+		lifecycle = instance.LifecycleSpot
+	}
 	for _, availZone := range availabilityZones {
-		instResp, err = runInstances(e.ec2(), &ec2.RunInstances{
+		ri := &ec2.RunInstances{
 			AvailZone:           availZone,
 			ImageId:             spec.Image.Id,
 			MinCount:            1,
@@ -517,7 +527,12 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 			InstanceType:        spec.InstanceType.Name,
 			SecurityGroups:      groups,
 			BlockDeviceMappings: blockDeviceMappings,
-		})
+		}
+		if lifecycle == instance.LifecycleSpot {
+			instResp, err = runSpotInstance(e.ec2(), ri, *args.Constraints.MaxPrice)
+		} else {
+			instResp, err = runInstances(e.ec2(), ri)
+		}
 		if isZoneConstrainedError(err) {
 			logger.Infof("%q is constrained, trying another availability zone", availZone)
 		} else {
@@ -535,7 +550,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		e:        e,
 		Instance: &instResp.Instances[0],
 	}
-	logger.Infof("started instance %q in %q", inst.Id(), inst.Instance.AvailZone)
+	logger.Infof("started %s instance %q in %q", lifecycle, inst.Id(), inst.Instance.AvailZone)
 
 	// TODO(axw) tag all resources (instances and volumes), for accounting
 	// and identification.
@@ -553,7 +568,8 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		CpuPower: spec.InstanceType.CpuPower,
 		RootDisk: &rootDiskSize,
 		// Tags currently not supported by EC2
-		AvailabilityZone: &inst.Instance.AvailZone,
+		AvailabilityZone:  &inst.Instance.AvailZone,
+		InstanceLifecycle: &lifecycle,
 	}
 	return &environs.StartInstanceResult{
 		Instance: inst,
@@ -968,42 +984,50 @@ func (e *environ) Destroy() error {
 	return e.Storage().RemoveAll()
 }
 
-func portsToIPPerms(ports []network.PortRange) []ec2.IPPerm {
-	ipPerms := make([]ec2.IPPerm, len(ports))
-	for i, p := range ports {
-		ipPerms[i] = ec2.IPPerm{
-			Protocol:  p.Protocol,
-			FromPort:  p.FromPort,
-			ToPort:    p.ToPort,
-			SourceIPs: []string{"0.0.0.0/0"},
+// rulesToIPPerms returns the IP permissions granting access to the
+// port ranges of the rules from their source CIDRs. Rules for the
+// same port range are combined into a single permission.
+func rulesToIPPerms(rules []network.IngressRule) []ec2.IPPerm {
+	var ipPerms []ec2.IPPerm
+	index := make(map[network.PortRange]int)
+	for _, r := range rules {
+		i, ok := index[r.PortRange]
+		if !ok {
+			i = len(ipPerms)
+			index[r.PortRange] = i
+			ipPerms = append(ipPerms, ec2.IPPerm{
+				Protocol: r.Protocol,
+				FromPort: r.FromPort,
+				ToPort:   r.ToPort,
+			})
 		}
+		ipPerms[i].SourceIPs = append(ipPerms[i].SourceIPs, r.SourceCIDR)
 	}
 	return ipPerms
 }
 
-func (e *environ) openPortsInGroup(name string, ports []network.PortRange) error {
-	if len(ports) == 0 {
+func (e *environ) openRulesInGroup(name string, rules []network.IngressRule) error {
+	if len(rules) == 0 {
 		return nil
 	}
-	// Give permissions for anyone to access the given ports.
+	// Give permissions for the source CIDRs to access the given ports.
 	g, err := e.groupByName(name)
 	if err != nil {
 		return err
 	}
-	ipPerms := portsToIPPerms(ports)
-	_, err = e.ec2().AuthorizeSecurityGroup(g, ipPerms)
+	_, err = e.ec2().AuthorizeSecurityGroup(g, rulesToIPPerms(rules))
 	if err != nil && ec2ErrCode(err) == "InvalidPermission.Duplicate" {
-		if len(ports) == 1 {
+		if len(rules) == 1 {
 			return nil
 		}
-		// If there's more than one port and we get a duplicate error,
-		// then we go through authorizing each port individually,
-		// otherwise the ports that were *not* duplicates will have
+		// If there's more than one rule and we get a duplicate error,
+		// then we go through authorizing each rule individually,
+		// otherwise the rules that were *not* duplicates will have
 		// been ignored
-		for i := range ipPerms {
-			_, err := e.ec2().AuthorizeSecurityGroup(g, ipPerms[i:i+1])
+		for i := range rules {
+			_, err := e.ec2().AuthorizeSecurityGroup(g, rulesToIPPerms(rules[i:i+1]))
 			if err != nil && ec2ErrCode(err) != "InvalidPermission.Duplicate" {
-				return fmt.Errorf("cannot open port %v: %v", ipPerms[i], err)
+				return fmt.Errorf("cannot open port %v: %v", rules[i], err)
 			}
 		}
 		return nil
@@ -1014,74 +1038,109 @@ func (e *environ) openPortsInGroup(name string, ports []network.PortRange) error
 	return nil
 }
 
-func (e *environ) closePortsInGroup(name string, ports []network.PortRange) error {
-	if len(ports) == 0 {
+func (e *environ) closeRulesInGroup(name string, rules []network.IngressRule) error {
+	if len(rules) == 0 {
 		return nil
 	}
-	// Revoke permissions for anyone to access the given ports.
+	// Revoke permissions for the source CIDRs to access the given ports.
 	// Note that ec2 allows the revocation of permissions that aren't
 	// granted, so this is naturally idempotent.
 	g, err := e.groupByName(name)
 	if err != nil {
 		return err
 	}
-	_, err = e.ec2().RevokeSecurityGroup(g, portsToIPPerms(ports))
+	_, err = e.ec2().RevokeSecurityGroup(g, rulesToIPPerms(rules))
 	if err != nil {
 		return fmt.Errorf("cannot close ports: %v", err)
 	}
 	return nil
 }
 
-func (e *environ) portsInGroup(name string) (ports []network.PortRange, err error) {
+func (e *environ) rulesInGroup(name string) (rules []network.IngressRule, err error) {
 	group, err := e.groupInfoByName(name)
 	if err != nil {
 		return nil, err
 	}
 	for _, p := range group.IPPerms {
-		if len(p.SourceIPs) != 1 {
+		if len(p.SourceIPs) == 0 {
 			logger.Warningf("unexpected IP permission found: %v", p)
 			continue
 		}
-		ports = append(ports, network.PortRange{
+		portRange := network.PortRange{
 			Protocol: p.Protocol,
 			FromPort: p.FromPort,
 			ToPort:   p.ToPort,
-		})
+		}
+		for _, sourceIP := range p.SourceIPs {
+			rules = append(rules, network.IngressRule{PortRange: portRange, SourceCIDR: sourceIP})
+		}
+	}
+	network.SortIngressRules(rules)
+	return rules, nil
+}
+
+func (e *environ) portsInGroup(name string) (ports []network.PortRange, err error) {
+	rules, err := e.rulesInGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		if r.SourceCIDR == network.AnySourceCIDR {
+			ports = append(ports, r.PortRange)
+		}
 	}
-	network.SortPortRanges(ports)
 	return ports, nil
 }
 
 func (e *environ) OpenPorts(ports []network.PortRange) error {
+	return e.OpenIngressRules(network.NewIngressRules(ports))
+}
+
+func (e *environ) ClosePorts(ports []network.PortRange) error {
+	return e.CloseIngressRules(network.NewIngressRules(ports))
+}
+
+func (e *environ) Ports() ([]network.PortRange, error) {
+	if e.Config().FirewallMode() != config.FwGlobal {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment",
+			e.Config().FirewallMode())
+	}
+	return e.portsInGroup(e.globalGroupName())
+}
+
+// OpenIngressRules is specified on environs.IngressRules.
+func (e *environ) OpenIngressRules(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on environment",
 			e.Config().FirewallMode())
 	}
-	if err := e.openPortsInGroup(e.globalGroupName(), ports); err != nil {
+	if err := e.openRulesInGroup(e.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in global group: %v", ports)
+	logger.Infof("opened ports in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) ClosePorts(ports []network.PortRange) error {
+// CloseIngressRules is specified on environs.IngressRules.
+func (e *environ) CloseIngressRules(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on environment",
 			e.Config().FirewallMode())
 	}
-	if err := e.closePortsInGroup(e.globalGroupName(), ports); err != nil {
+	if err := e.closeRulesInGroup(e.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in global group: %v", ports)
+	logger.Infof("closed ports in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) Ports() ([]network.PortRange, error) {
+// IngressRules is specified on environs.IngressRules.
+func (e *environ) IngressRules() ([]network.IngressRule, error) {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment",
 			e.Config().FirewallMode())
 	}
-	return e.portsInGroup(e.globalGroupName())
+	return e.rulesInGroup(e.globalGroupName())
 }
 
 func (*environ) Provider() environs.EnvironProvider {
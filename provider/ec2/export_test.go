@@ -54,6 +54,10 @@ var (
 	EC2AvailabilityZones        = &ec2AvailabilityZones
 	AvailabilityZoneAllocations = &availabilityZoneAllocations
 	RunInstances                = &runInstances
+	RunSpotInstance             = &runSpotInstance
+	ModifyVolume                = &modifyVolume
+	CreateSnapshot              = &createSnapshot
+	DeleteSnapshot              = &deleteSnapshot
 	BlockDeviceNamer            = blockDeviceNamer
 	GetBlockDeviceMappings      = getBlockDeviceMappings
 )
@@ -88,40 +88,59 @@ func (inst *ec2Instance) Addresses() ([]network.Address, error) {
 }
 
 func (inst *ec2Instance) OpenPorts(machineId string, ports []network.PortRange) error {
+	return inst.OpenIngressRules(machineId, network.NewIngressRules(ports))
+}
+
+func (inst *ec2Instance) ClosePorts(machineId string, ports []network.PortRange) error {
+	return inst.CloseIngressRules(machineId, network.NewIngressRules(ports))
+}
+
+func (inst *ec2Instance) Ports(machineId string) ([]network.PortRange, error) {
+	if inst.e.Config().FirewallMode() != config.FwInstance {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
+			inst.e.Config().FirewallMode())
+	}
+	name := inst.e.machineGroupName(machineId)
+	ranges, err := inst.e.portsInGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
+
+// OpenIngressRules is specified on instance.IngressRulesInstance.
+func (inst *ec2Instance) OpenIngressRules(machineId string, rules []network.IngressRule) error {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
-	if err := inst.e.openPortsInGroup(name, ports); err != nil {
+	if err := inst.e.openRulesInGroup(name, rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in security group %s: %v", name, ports)
+	logger.Infof("opened ports in security group %s: %v", name, rules)
 	return nil
 }
 
-func (inst *ec2Instance) ClosePorts(machineId string, ports []network.PortRange) error {
+// CloseIngressRules is specified on instance.IngressRulesInstance.
+func (inst *ec2Instance) CloseIngressRules(machineId string, rules []network.IngressRule) error {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
-	if err := inst.e.closePortsInGroup(name, ports); err != nil {
+	if err := inst.e.closeRulesInGroup(name, rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in security group %s: %v", name, ports)
+	logger.Infof("closed ports in security group %s: %v", name, rules)
 	return nil
 }
 
-func (inst *ec2Instance) Ports(machineId string) ([]network.PortRange, error) {
+// IngressRules is specified on instance.IngressRulesInstance.
+func (inst *ec2Instance) IngressRules(machineId string) ([]network.IngressRule, error) {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
 			inst.e.Config().FirewallMode())
 	}
-	name := inst.e.machineGroupName(machineId)
-	ranges, err := inst.e.portsInGroup(name)
-	if err != nil {
-		return nil, err
-	}
-	return ranges, nil
+	return inst.e.rulesInGroup(inst.e.machineGroupName(machineId))
 }
@@ -577,6 +577,30 @@ func (t *localServerSuite) testStartInstanceAvailZoneOneConstrained(c *gc.C, run
 	c.Check(*hwc.AvailabilityZone, gc.Equals, "az2")
 }
 
+func (t *localServerSuite) TestStartInstanceSpot(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The test server does not support spot requests, so
+	// fulfil them with an ordinary instance.
+	var maxPrices []string
+	realRunInstances := *ec2.RunInstances
+	t.PatchValue(ec2.RunSpotInstance, func(e *amzec2.EC2, ri *amzec2.RunInstances, maxPrice string) (*amzec2.RunInstancesResp, error) {
+		maxPrices = append(maxPrices, maxPrice)
+		return realRunInstances(e, ri)
+	})
+	cons := constraints.MustParse("instance-lifecycle=spot max-price=0.05")
+	_, hwc := testing.AssertStartInstanceWithConstraints(c, env, "1", cons)
+	c.Assert(maxPrices, gc.DeepEquals, []string{"0.05"})
+	c.Assert(hwc.IsSpot(), jc.IsTrue)
+
+	// On-demand instances are started as usual.
+	_, hwc = testing.AssertStartInstance(c, env, "2")
+	c.Assert(maxPrices, gc.HasLen, 1)
+	c.Assert(*hwc.InstanceLifecycle, gc.Equals, instance.LifecycleOnDemand)
+}
+
 func (t *localServerSuite) TestAddresses(c *gc.C) {
 	env := t.Prepare(c)
 	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
@@ -661,6 +685,21 @@ func (t *localServerSuite) TestPrecheckInstanceUnsupportedArch(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid AWS instance type "cc1.4xlarge" and arch "i386" specified`)
 }
 
+func (t *localServerSuite) TestPrecheckInstanceLifecycle(c *gc.C) {
+	env := t.Prepare(c)
+	cons := constraints.MustParse("instance-lifecycle=spot max-price=0.05")
+	err := env.PrecheckInstance(coretesting.FakeDefaultSeries, cons, "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	cons = constraints.MustParse("instance-lifecycle=spot")
+	err = env.PrecheckInstance(coretesting.FakeDefaultSeries, cons, "")
+	c.Assert(err, gc.ErrorMatches, "spot instances require a max-price constraint")
+
+	cons = constraints.MustParse("max-price=0.05")
+	err = env.PrecheckInstance(coretesting.FakeDefaultSeries, cons, "")
+	c.Assert(err, gc.ErrorMatches, "max-price constraint requires instance-lifecycle=spot")
+}
+
 func (t *localServerSuite) TestPrecheckInstanceAvailZone(c *gc.C) {
 	env := t.Prepare(c)
 	placement := "zone=test-available"
@@ -0,0 +1,117 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/constraints"
+)
+
+// spotRequestAttempt governs how long we wait for a spot instance
+// request to be fulfilled before giving up on it.
+var spotRequestAttempt = utils.AttemptStrategy{
+	Total: 5 * time.Minute,
+	Delay: 5 * time.Second,
+}
+
+// validateLifecycleConstraints returns an error if the instance
+// lifecycle constraints cannot be satisfied by EC2. Spot instances
+// are requested with a bid, which must be given by the max-price
+// constraint.
+func validateLifecycleConstraints(cons constraints.Value) error {
+	hasMaxPrice := cons.MaxPrice != nil && *cons.MaxPrice != ""
+	switch {
+	case cons.HasSpotLifecycle() && !hasMaxPrice:
+		return errors.New("spot instances require a max-price constraint")
+	case hasMaxPrice && !cons.HasSpotLifecycle():
+		return errors.New("max-price constraint requires instance-lifecycle=spot")
+	}
+	return nil
+}
+
+var runSpotInstance = _runSpotInstance
+
+// runSpotInstance requests a spot instance with the parameters in ri,
+// bidding at most maxPrice US dollars per hour, and waits for the
+// request to be fulfilled. If the request is not fulfilled in time it
+// is cancelled. The response is that of ec2.RunInstances, so that
+// spot and on-demand instances may be handled alike.
+func _runSpotInstance(e *ec2.EC2, ri *ec2.RunInstances, maxPrice string) (*ec2.RunInstancesResp, error) {
+	resp, err := e.RequestSpotInstances(&ec2.RequestSpotInstances{
+		SpotPrice:      maxPrice,
+		InstanceCount:  1,
+		AvailZone:      ri.AvailZone,
+		ImageId:        ri.ImageId,
+		UserData:       ri.UserData,
+		InstanceType:   ri.InstanceType,
+		SecurityGroups: ri.SecurityGroups,
+		BlockDevices:   ri.BlockDeviceMappings,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.SpotRequestResults) != 1 {
+		return nil, errors.Errorf("expected 1 spot request, got %d", len(resp.SpotRequestResults))
+	}
+	requestId := resp.SpotRequestResults[0].SpotRequestId
+	logger.Infof("requested spot instance in %q with maximum price %s (request %q)", ri.AvailZone, maxPrice, requestId)
+
+	instanceId, err := waitSpotRequest(e, requestId)
+	if err != nil {
+		if _, cancelErr := e.CancelSpotRequests([]string{requestId}); cancelErr != nil {
+			logger.Errorf("cannot cancel spot request %q: %v", requestId, cancelErr)
+		}
+		return nil, errors.Annotatef(err, "spot request %q", requestId)
+	}
+	instResp, err := e.Instances([]string{instanceId}, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(instResp.Reservations) != 1 {
+		return nil, errors.Errorf("expected 1 reservation for spot instance %q, got %d", instanceId, len(instResp.Reservations))
+	}
+	reservation := instResp.Reservations[0]
+	return &ec2.RunInstancesResp{
+		RequestId:      instResp.RequestId,
+		ReservationId:  reservation.ReservationId,
+		OwnerId:        reservation.OwnerId,
+		SecurityGroups: reservation.SecurityGroups,
+		Instances:      reservation.Instances,
+	}, nil
+}
+
+// waitSpotRequest waits for the spot request with the given id to be
+// fulfilled, and returns the id of the instance started for it.
+func waitSpotRequest(e *ec2.EC2, requestId string) (string, error) {
+	var lastState string
+	for a := spotRequestAttempt.Start(); a.Next(); {
+		resp, err := e.DescribeSpotRequests([]string{requestId}, nil)
+		if ec2ErrCode(err) == "InvalidSpotInstanceRequestID.NotFound" {
+			// The request is not visible yet.
+			continue
+		} else if err != nil {
+			return "", errors.Trace(err)
+		}
+		if len(resp.SpotRequestResults) != 1 {
+			continue
+		}
+		result := resp.SpotRequestResults[0]
+		lastState = result.State
+		switch result.State {
+		case "active":
+			if result.InstanceId != "" {
+				return result.InstanceId, nil
+			}
+		case "open":
+		default:
+			return "", errors.Errorf("request is %s: %s", result.State, result.Status.Message)
+		}
+	}
+	return "", errors.Errorf("request not fulfilled (last state %q)", lastState)
+}
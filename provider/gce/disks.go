@@ -0,0 +1,298 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/provider/gce/google"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	storageProviderType = storage.ProviderType("gce")
+
+	// Config attributes
+
+	// The persistent disk type (default pd-standard):
+	//   "pd-standard" for standard (magnetic) persistent disks,
+	//   "pd-ssd" for SSD persistent disks.
+	diskTypeAttr = "disk-type"
+)
+
+// volumeIdSeparator separates the zone and disk name in the IDs of
+// the volumes created by the gce storage provider. Disk names are
+// only unique within a zone, and the zone is required to address the
+// disk in all API calls.
+const volumeIdSeparator = "--"
+
+// storageProvider creates volume sources which use GCE persistent
+// disks.
+type storageProvider struct{}
+
+var _ storage.Provider = (*storageProvider)(nil)
+
+// ValidateConfig is defined on the Provider interface.
+func (g *storageProvider) ValidateConfig(providerConfig *storage.Config) error {
+	for attr, value := range providerConfig.Attrs() {
+		if attr != diskTypeAttr {
+			return errors.Errorf("unknown provider config option %q", attr)
+		}
+		if err := validateDiskType(value); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Supports is defined on the Provider interface.
+func (g *storageProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (g *storageProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is defined on the Provider interface.
+func (g *storageProvider) Dynamic() bool {
+	return true
+}
+
+// VolumeSource is defined on the Provider interface.
+func (g *storageProvider) VolumeSource(environConfig *config.Config, providerConfig *storage.Config) (storage.VolumeSource, error) {
+	if err := g.ValidateConfig(providerConfig); err != nil {
+		return nil, errors.Trace(err)
+	}
+	env, err := newEnviron(environConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	source := &volumeSource{
+		env:     env,
+		envUUID: env.uuid,
+	}
+	return source, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (g *storageProvider) FilesystemSource(environConfig *config.Config, providerConfig *storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+func validateDiskType(value interface{}) error {
+	switch google.DiskType(fmt.Sprint(value)) {
+	case google.DiskPersistentStandard, google.DiskPersistentSSD:
+		return nil
+	}
+	return errors.Errorf("invalid %s %q: expected %q or %q",
+		diskTypeAttr, value, google.DiskPersistentStandard, google.DiskPersistentSSD,
+	)
+}
+
+type volumeSource struct {
+	env     *environ
+	envUUID string
+}
+
+var _ storage.VolumeSource = (*volumeSource)(nil)
+
+// CreateVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) CreateVolumes(params []storage.VolumeParams) (_ []storage.Volume, _ []storage.VolumeAttachment, err error) {
+	volumes := make([]storage.Volume, 0, len(params))
+
+	// If there's an error, we delete any ones that are created.
+	defer func() {
+		if err != nil && len(volumes) > 0 {
+			volIds := make([]string, len(volumes))
+			for i, vol := range volumes {
+				volIds[i] = vol.VolumeId
+			}
+			for i, volErr := range v.DestroyVolumes(volIds) {
+				if volErr != nil {
+					logger.Warningf("error cleaning up volume %v: %v", volumes[i].Tag, volErr)
+				}
+			}
+		}
+	}()
+
+	for _, p := range params {
+		if err := v.ValidateVolumeParams(p); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	for _, p := range params {
+		zone, err := v.volumeZone(p)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "choosing zone for volume %s", p.Tag.Id())
+		}
+		diskType := google.DiskPersistentStandard
+		if value, ok := p.Attributes[diskTypeAttr]; ok {
+			diskType = google.DiskType(fmt.Sprint(value))
+		}
+		disk, err := v.env.gce.CreateDisk(zone, google.DiskSpec{
+			Name:               fmt.Sprintf("juju-%s-%s", v.envUUID, p.Tag),
+			SizeHintGB:         common.MiBToGiB(p.Size),
+			PersistentDiskType: diskType,
+			Description:        v.envUUID,
+		})
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "creating volume %s", p.Tag.Id())
+		}
+		volumes = append(volumes, storage.Volume{
+			Tag:        p.Tag,
+			VolumeId:   zone + volumeIdSeparator + disk.Name,
+			Serial:     diskSerial(disk.Name),
+			Size:       disk.SizeGB * 1024,
+			Persistent: true,
+		})
+	}
+	// Persistent disks' attachments are created independently.
+	return volumes, nil, nil
+}
+
+// volumeZone returns the zone in which to create the volume. A volume
+// that is to be attached to an instance must be created in the same
+// zone as the instance; otherwise the first available zone is used.
+func (v *volumeSource) volumeZone(p storage.VolumeParams) (string, error) {
+	if p.Attachment != nil && p.Attachment.InstanceId != "" {
+		zones, err := v.env.InstanceAvailabilityZoneNames([]instance.Id{p.Attachment.InstanceId})
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		return zones[0], nil
+	}
+	zones, err := v.env.AvailabilityZones()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for _, zone := range zones {
+		if zone.Available() {
+			return zone.Name(), nil
+		}
+	}
+	return "", errors.New("no availability zones available")
+}
+
+// DescribeVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DescribeVolumes(volIds []string) ([]storage.Volume, error) {
+	vols := make([]storage.Volume, len(volIds))
+	for i, volumeId := range volIds {
+		zone, name, err := parseVolumeId(volumeId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		disk, err := v.env.gce.Disk(zone, name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "describing volume %q", volumeId)
+		}
+		vols[i] = storage.Volume{
+			VolumeId:   volumeId,
+			Serial:     diskSerial(disk.Name),
+			Size:       disk.SizeGB * 1024,
+			Persistent: true,
+		}
+	}
+	return vols, nil
+}
+
+// DestroyVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DestroyVolumes(volIds []string) []error {
+	results := make([]error, len(volIds))
+	for i, volumeId := range volIds {
+		zone, name, err := parseVolumeId(volumeId)
+		if err != nil {
+			results[i] = errors.Trace(err)
+			continue
+		}
+		if err := v.env.gce.RemoveDisk(zone, name); err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+		}
+	}
+	return results
+}
+
+// ValidateVolumeParams is specified on the storage.VolumeSource interface.
+func (v *volumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	if value, ok := params.Attributes[diskTypeAttr]; ok {
+		return validateDiskType(value)
+	}
+	return nil
+}
+
+// AttachVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) AttachVolumes(attachParams []storage.VolumeAttachmentParams) ([]storage.VolumeAttachment, error) {
+	attachments := make([]storage.VolumeAttachment, len(attachParams))
+	for i, p := range attachParams {
+		if p.InstanceId == "" {
+			return nil, errors.Errorf("attaching volume %s: machine %s not provisioned", p.Volume.Id(), p.Machine.Id())
+		}
+		if err := v.attachOneVolume(p.VolumeId, string(p.InstanceId)); err != nil {
+			return nil, errors.Annotatef(err, "attaching %v to %v", p.VolumeId, p.InstanceId)
+		}
+		// The disk is addressed by its serial, as the device name
+		// assigned by the kernel is not stable.
+		attachments[i] = storage.VolumeAttachment{
+			Volume:  p.Volume,
+			Machine: p.Machine,
+		}
+	}
+	return attachments, nil
+}
+
+func (v *volumeSource) attachOneVolume(volumeId, instanceId string) error {
+	zone, name, err := parseVolumeId(volumeId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	attached, err := v.env.gce.InstanceDisks(zone, instanceId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, disk := range attached {
+		if disk.DiskName == name {
+			return nil
+		}
+	}
+	_, err = v.env.gce.AttachDisk(zone, name, instanceId, false)
+	return errors.Trace(err)
+}
+
+// DetachVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DetachVolumes(attachParams []storage.VolumeAttachmentParams) error {
+	for _, p := range attachParams {
+		zone, name, err := parseVolumeId(p.VolumeId)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := v.env.gce.DetachDisk(zone, string(p.InstanceId), name); err != nil {
+			return errors.Annotatef(err, "detaching %v from %v", p.VolumeId, p.InstanceId)
+		}
+	}
+	return nil
+}
+
+// parseVolumeId splits the ID of a volume created by the gce storage
+// provider into its zone and disk name.
+func parseVolumeId(volumeId string) (zone, name string, _ error) {
+	parts := strings.SplitN(volumeId, volumeIdSeparator, 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("invalid volume ID %q", volumeId)
+	}
+	return parts[0], parts[1], nil
+}
+
+// diskSerial returns the serial number, as reported by udev, of a
+// persistent disk attached using its name as the device name. The
+// disk's links under /dev/disk/by-id are derived from it.
+func diskSerial(name string) string {
+	return "0Google_PersistentDisk_" + name
+}
@@ -0,0 +1,198 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/gce"
+	"github.com/juju/juju/provider/gce/google"
+	"github.com/juju/juju/storage"
+)
+
+type storageProviderSuite struct {
+	gce.BaseSuite
+}
+
+var _ = gc.Suite(&storageProviderSuite{})
+
+func (s *storageProviderSuite) volumeSource(c *gc.C, attrs map[string]interface{}) storage.VolumeSource {
+	cfg, err := storage.NewConfig("gce", gce.StorageProviderType, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := gce.StorageProvider().VolumeSource(s.Config, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	s.FakeConn.Calls = nil
+	return source
+}
+
+func (s *storageProviderSuite) TestValidateConfig(c *gc.C) {
+	p := gce.StorageProvider()
+	cfg, err := storage.NewConfig("foo", gce.StorageProviderType, map[string]interface{}{
+		"disk-type": "pd-ssd",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("foo", gce.StorageProviderType, map[string]interface{}{
+		"disk-type": "floppy",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), gc.ErrorMatches, `invalid disk-type "floppy": expected "pd-standard" or "pd-ssd"`)
+
+	cfg, err = storage.NewConfig("foo", gce.StorageProviderType, map[string]interface{}{
+		"invalid": "config",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), gc.ErrorMatches, `unknown provider config option "invalid"`)
+}
+
+func (s *storageProviderSuite) TestSupports(c *gc.C) {
+	p := gce.StorageProvider()
+	c.Check(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Check(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+	c.Check(p.Scope(), gc.Equals, storage.ScopeEnviron)
+	c.Check(p.Dynamic(), jc.IsTrue)
+}
+
+func (s *storageProviderSuite) TestCreateVolumesInInstanceZone(c *gc.C) {
+	s.FakeEnviron.Insts = []instance.Instance{s.Instance}
+	source := s.volumeSource(c, map[string]interface{}{"disk-type": "pd-ssd"})
+
+	volumes, attachments, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0"),
+		Size:       20 * 1024,
+		Provider:   gce.StorageProviderType,
+		Attributes: map[string]interface{}{"disk-type": "pd-ssd"},
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				Machine:    names.NewMachineTag("1"),
+				InstanceId: instance.Id("spam"),
+			},
+			Volume: names.NewVolumeTag("0"),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(attachments, gc.HasLen, 0)
+
+	diskName := "juju-2d02eeac-9dbb-11e4-89d3-123b93f75cba-volume-0"
+	c.Check(volumes, jc.DeepEquals, []storage.Volume{{
+		Tag:        names.NewVolumeTag("0"),
+		VolumeId:   "home-zone--" + diskName,
+		Serial:     "0Google_PersistentDisk_" + diskName,
+		Size:       20 * 1024,
+		Persistent: true,
+	}})
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "CreateDisk")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[0].DiskSpec, jc.DeepEquals, google.DiskSpec{
+		Name:               diskName,
+		SizeHintGB:         20,
+		PersistentDiskType: google.DiskPersistentSSD,
+		Description:        "2d02eeac-9dbb-11e4-89d3-123b93f75cba",
+	})
+}
+
+func (s *storageProviderSuite) TestCreateVolumesUnattached(c *gc.C) {
+	s.FakeConn.Zones = []google.AvailabilityZone{
+		google.NewZone("a-zone", google.StatusDown, "", ""),
+		google.NewZone("b-zone", google.StatusUp, "", ""),
+	}
+	source := s.volumeSource(c, nil)
+
+	volumes, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes, gc.HasLen, 1)
+	c.Check(volumes[0].VolumeId, gc.Equals, "b-zone--juju-2d02eeac-9dbb-11e4-89d3-123b93f75cba-volume-0")
+	c.Check(volumes[0].Size, gc.Equals, google.MinDiskSizeGB*1024)
+	c.Check(s.FakeConn.Calls[1].DiskSpec.PersistentDiskType, gc.Equals, google.DiskPersistentStandard)
+}
+
+func (s *storageProviderSuite) TestDescribeAndDestroyVolumes(c *gc.C) {
+	source := s.volumeSource(c, nil)
+	s.FakeConn.Disk = &google.Disk{Name: "disk-0", Zone: "a-zone", SizeGB: 10}
+
+	volumes, err := source.DescribeVolumes([]string{"a-zone--disk-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(volumes, jc.DeepEquals, []storage.Volume{{
+		VolumeId:   "a-zone--disk-0",
+		Serial:     "0Google_PersistentDisk_disk-0",
+		Size:       10 * 1024,
+		Persistent: true,
+	}})
+
+	errs := source.DestroyVolumes([]string{"a-zone--disk-0", "invalid"})
+	c.Assert(errs, gc.HasLen, 2)
+	c.Check(errs[0], jc.ErrorIsNil)
+	c.Check(errs[1], gc.ErrorMatches, `invalid volume ID "invalid"`)
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "RemoveDisk")
+	c.Check(s.FakeConn.Calls[1].ZoneName, gc.Equals, "a-zone")
+	c.Check(s.FakeConn.Calls[1].DiskName, gc.Equals, "disk-0")
+}
+
+func (s *storageProviderSuite) TestAttachVolumes(c *gc.C) {
+	source := s.volumeSource(c, nil)
+	params := []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("1"),
+			InstanceId: instance.Id("spam"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "a-zone--disk-0",
+	}}
+
+	attachments, err := source.AttachVolumes(params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(attachments, jc.DeepEquals, []storage.VolumeAttachment{{
+		Volume:  names.NewVolumeTag("0"),
+		Machine: names.NewMachineTag("1"),
+	}})
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "InstanceDisks")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AttachDisk")
+	c.Check(s.FakeConn.Calls[1].ZoneName, gc.Equals, "a-zone")
+	c.Check(s.FakeConn.Calls[1].DiskName, gc.Equals, "disk-0")
+	c.Check(s.FakeConn.Calls[1].ID, gc.Equals, "spam")
+}
+
+func (s *storageProviderSuite) TestAttachVolumesAlreadyAttached(c *gc.C) {
+	source := s.volumeSource(c, nil)
+	s.FakeConn.Attached = []*google.AttachedDisk{{DiskName: "disk-0", DeviceName: "disk-0"}}
+
+	_, err := source.AttachVolumes([]storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("1"),
+			InstanceId: instance.Id("spam"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "a-zone--disk-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+}
+
+func (s *storageProviderSuite) TestDetachVolumes(c *gc.C) {
+	source := s.volumeSource(c, nil)
+
+	err := source.DetachVolumes([]storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("1"),
+			InstanceId: instance.Id("spam"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "a-zone--disk-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "DetachDisk")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "a-zone")
+	c.Check(s.FakeConn.Calls[0].DiskName, gc.Equals, "disk-0")
+	c.Check(s.FakeConn.Calls[0].ID, gc.Equals, "spam")
+}
@@ -31,8 +31,18 @@ type gceConnection interface {
 	Ports(fwname string) ([]network.PortRange, error)
 	OpenPorts(fwname string, ports ...network.PortRange) error
 	ClosePorts(fwname string, ports ...network.PortRange) error
+	IngressRules(fwname string) ([]network.IngressRule, error)
+	OpenIngressRules(fwname string, rules ...network.IngressRule) error
+	CloseIngressRules(fwname string, rules ...network.IngressRule) error
 
 	AvailabilityZones(region string) ([]google.AvailabilityZone, error)
+
+	CreateDisk(zone string, spec google.DiskSpec) (*google.Disk, error)
+	Disk(zone, name string) (*google.Disk, error)
+	RemoveDisk(zone, name string) error
+	AttachDisk(zone, diskName, instanceId string, readonly bool) (*google.AttachedDisk, error)
+	DetachDisk(zone, instanceId, diskName string) error
+	InstanceDisks(zone, instanceId string) ([]*google.AttachedDisk, error)
 }
 
 type environ struct {
@@ -145,13 +155,13 @@ var destroyEnv = common.Destroy
 // Destroy shuts down all known machines and destroys the rest of the
 // known environment.
 func (env *environ) Destroy() error {
-	ports, err := env.Ports()
+	rules, err := env.IngressRules()
 	if err != nil {
 		return errors.Trace(err)
 	}
 
-	if len(ports) > 0 {
-		if err := env.ClosePorts(ports); err != nil {
+	if len(rules) > 0 {
+		if err := env.CloseIngressRules(rules); err != nil {
 			return errors.Trace(err)
 		}
 	}
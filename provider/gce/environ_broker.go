@@ -177,6 +177,7 @@ func (env *environ) newRawInstance(args environs.StartInstanceParams, spec *inst
 		NetworkInterfaces: []string{"ExternalNAT"},
 		Metadata:          metadata,
 		Tags:              tags,
+		Preemptible:       args.Constraints.HasSpotLifecycle(),
 		// Network is omitted (left empty).
 	}
 
@@ -185,10 +186,35 @@ func (env *environ) newRawInstance(args environs.StartInstanceParams, spec *inst
 		return nil, errors.Trace(err)
 	}
 
+	if instSpec.Preemptible {
+		// A preemptible instance previously started for the machine
+		// keeps its name after being preempted, until it is removed.
+		if err := env.removeTerminatedInstance(machineID); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	inst, err := env.gce.AddInstance(instSpec, zones...)
 	return inst, errors.Trace(err)
 }
 
+// removeTerminatedInstance removes the instance with the given name
+// if it has been terminated, as happens when GCE preempts it.
+func (env *environ) removeTerminatedInstance(id string) error {
+	prefix := common.MachineFullName(env, "")
+	terminated, err := env.gce.Instances(prefix, google.StatusTerminated)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, inst := range terminated {
+		if inst.ID == id {
+			logger.Infof("removing terminated instance %q", id)
+			return errors.Trace(env.gce.RemoveInstances(prefix, id))
+		}
+	}
+	return nil
+}
+
 // getMetadata builds the raw "user-defined" metadata for the new
 // instance (relative to the provided args) and returns it.
 func getMetadata(args environs.StartInstanceParams) (map[string]string, error) {
@@ -249,13 +275,20 @@ func getDisks(spec *instances.InstanceSpec, cons constraints.Value) []google.Dis
 // the given instance and relative to the provided spec and returns it.
 func (env *environ) getHardwareCharacteristics(spec *instances.InstanceSpec, inst *environInstance) *instance.HardwareCharacteristics {
 	rootDiskMB := inst.base.RootDiskGB() * 1024
+	// Preemptible instances are reported as spot instances, which
+	// they are in all but name.
+	lifecycle := instance.LifecycleOnDemand
+	if inst.base.Preemptible {
+		lifecycle = instance.LifecycleSpot
+	}
 	hwc := instance.HardwareCharacteristics{
-		Arch:             &spec.Image.Arch,
-		Mem:              &spec.InstanceType.Mem,
-		CpuCores:         &spec.InstanceType.CpuCores,
-		CpuPower:         spec.InstanceType.CpuPower,
-		RootDisk:         &rootDiskMB,
-		AvailabilityZone: &inst.base.ZoneName,
+		Arch:              &spec.Image.Arch,
+		Mem:               &spec.InstanceType.Mem,
+		CpuCores:          &spec.InstanceType.CpuCores,
+		CpuPower:          spec.InstanceType.CpuPower,
+		RootDisk:          &rootDiskMB,
+		AvailabilityZone:  &inst.base.ZoneName,
+		InstanceLifecycle: &lifecycle,
 		// Tags: not supported in GCE.
 	}
 	return &hwc
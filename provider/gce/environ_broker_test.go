@@ -8,6 +8,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
@@ -16,6 +17,7 @@ import (
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/gce"
+	"github.com/juju/juju/provider/gce/google"
 	"github.com/juju/juju/testing"
 )
 
@@ -166,6 +168,27 @@ func (s *environBrokerSuite) TestNewRawInstance(c *gc.C) {
 	c.Check(inst, gc.DeepEquals, s.BaseInstance)
 }
 
+func (s *environBrokerSuite) TestNewRawInstancePreemptible(c *gc.C) {
+	s.FakeConn.Inst = s.BaseInstance
+	s.FakeCommon.AZInstances = []common.AvailabilityZoneInstances{{
+		ZoneName:  "home-zone",
+		Instances: []instance.Id{s.Instance.Id()},
+	}}
+	s.StartInstArgs.Constraints = constraints.MustParse("instance-lifecycle=spot")
+
+	_, err := gce.NewRawInstance(s.Env, s.StartInstArgs, s.spec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Any terminated instance left over for the machine is looked for
+	// before the new instance is added.
+	called, calls := s.FakeConn.WasCalled("Instances")
+	c.Assert(called, jc.IsTrue)
+	c.Check(calls[len(calls)-1].Statuses, jc.DeepEquals, []string{google.StatusTerminated})
+	called, calls = s.FakeConn.WasCalled("AddInstance")
+	c.Assert(called, jc.IsTrue)
+	c.Check(calls[0].InstanceSpec.Preemptible, jc.IsTrue)
+}
+
 func (s *environBrokerSuite) TestGetMetadata(c *gc.C) {
 	metadata, err := gce.GetMetadata(s.StartInstArgs)
 
@@ -194,6 +217,7 @@ func (s *environBrokerSuite) TestGetHardwareCharacteristics(c *gc.C) {
 	c.Check(*hwc.CpuPower, gc.Equals, uint64(275))
 	c.Check(*hwc.Mem, gc.Equals, uint64(3750))
 	c.Check(*hwc.RootDisk, gc.Equals, uint64(15360))
+	c.Check(*hwc.InstanceLifecycle, gc.Equals, instance.LifecycleOnDemand)
 }
 
 func (s *environBrokerSuite) TestAllInstances(c *gc.C) {
@@ -64,3 +64,27 @@ func (env *environ) Ports() ([]network.PortRange, error) {
 	ports, err := env.gce.Ports(env.globalFirewallName())
 	return ports, errors.Trace(err)
 }
+
+// OpenIngressRules opens the given ingress rules for the whole
+// environment. Must only be used if the environment was setup with
+// the FwGlobal firewall mode.
+func (env *environ) OpenIngressRules(rules []network.IngressRule) error {
+	err := env.gce.OpenIngressRules(env.globalFirewallName(), rules...)
+	return errors.Trace(err)
+}
+
+// CloseIngressRules closes the given ingress rules for the whole
+// environment. Must only be used if the environment was setup with
+// the FwGlobal firewall mode.
+func (env *environ) CloseIngressRules(rules []network.IngressRule) error {
+	err := env.gce.CloseIngressRules(env.globalFirewallName(), rules...)
+	return errors.Trace(err)
+}
+
+// IngressRules returns the ingress rules opened for the whole
+// environment. Must only be used if the environment was setup with
+// the FwGlobal firewall mode.
+func (env *environ) IngressRules() ([]network.IngressRule, error) {
+	rules, err := env.gce.IngressRules(env.globalFirewallName())
+	return rules, errors.Trace(err)
+}
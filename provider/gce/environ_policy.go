@@ -64,9 +64,14 @@ func (env *environ) lookupArchitectures() ([]string, error) {
 	return archList, errors.Trace(err)
 }
 
+// unsupportedConstraints lists the constraints not supported by GCE.
+// Preemptible instances, which are requested with
+// instance-lifecycle=spot, are sold at a fixed price, so
+// max-price is not supported.
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.Networks,
+	constraints.MaxPrice,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
@@ -8,17 +8,23 @@ import (
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider/gce/google"
+	"github.com/juju/juju/storage"
 )
 
 var (
-	Provider          environs.EnvironProvider = providerInstance
-	NewInstance                                = newInstance
-	CheckInstanceType                          = checkInstanceType
-	GetMetadata                                = getMetadata
-	GetDisks                                   = getDisks
-	ConfigImmutable                            = configImmutableFields
+	Provider            environs.EnvironProvider = providerInstance
+	NewInstance                                  = newInstance
+	CheckInstanceType                            = checkInstanceType
+	GetMetadata                                  = getMetadata
+	GetDisks                                     = getDisks
+	ConfigImmutable                              = configImmutableFields
+	StorageProviderType                          = storageProviderType
 )
 
+func StorageProvider() storage.Provider {
+	return &storageProvider{}
+}
+
 func ExposeInstBase(inst *environInstance) *google.Instance {
 	return inst.base
 }
@@ -35,6 +35,10 @@ type rawConnectionWrapper interface {
 	// the named firewall and returns it. If the firewall is not found,
 	// errors.NotFound is returned.
 	GetFirewall(projectID, name string) (*compute.Firewall, error)
+	// ListFirewalls sends an API request to GCE for the information
+	// about the firewalls whose names start with the provided prefix,
+	// and returns them.
+	ListFirewalls(projectID, prefix string) ([]*compute.Firewall, error)
 	// AddFirewall requests GCE to add a firewall with the provided info.
 	// If the firewall already exists then an error will be returned.
 	// The call blocks until the firewall is added or the request fails.
@@ -52,6 +56,26 @@ type rawConnectionWrapper interface {
 	// GCE region. If none are found the the list is empty. Any failure in
 	// the low-level request is returned as an error.
 	ListAvailabilityZones(projectID, region string) ([]*compute.Zone, error)
+	// CreateDisk sends a request to GCE to create a persistent disk in
+	// the given zone, with the provided disk data. The call blocks
+	// until the disk is created or the request fails.
+	CreateDisk(projectID, zone string, spec *compute.Disk) error
+	// GetDisk sends a request to the GCE API for info about the named
+	// disk in the given zone. If the disk is not found, errors.NotFound
+	// is returned.
+	GetDisk(projectID, zone, id string) (*compute.Disk, error)
+	// RemoveDisk sends a request to the GCE API to remove the named
+	// disk in the given zone. The call blocks until the disk is
+	// removed or the request fails.
+	RemoveDisk(projectID, zone, id string) error
+	// AttachDisk sends a request to the GCE API to attach a disk to
+	// the identified instance. The call blocks until the disk is
+	// attached or the request fails.
+	AttachDisk(projectID, zone, instanceId string, disk *compute.AttachedDisk) error
+	// DetachDisk sends a request to the GCE API to detach the disk
+	// with the given device name from the identified instance. The
+	// call blocks until the disk is detached or the request fails.
+	DetachDisk(projectID, zone, instanceId, deviceName string) error
 }
 
 // TODO(ericsnow) Add specific error types for common failures
@@ -0,0 +1,105 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"google.golang.org/api/compute/v1"
+)
+
+// CreateDisk creates a new persistent disk in the given zone based on
+// the spec's data and returns it. The call blocks until the disk is
+// created or the request fails.
+func (gce *Connection) CreateDisk(zone string, spec DiskSpec) (*Disk, error) {
+	raw, err := spec.newDetached(zone)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := gce.raw.CreateDisk(gce.projectID, zone, raw); err != nil {
+		return nil, errors.Annotatef(err, "creating disk %q", spec.Name)
+	}
+	return gce.Disk(zone, spec.Name)
+}
+
+// Disk gets the up-to-date info about the named disk in the given
+// zone and returns it. If the disk does not exist, errors.NotFound
+// is returned.
+func (gce *Connection) Disk(zone, name string) (*Disk, error) {
+	raw, err := gce.raw.GetDisk(gce.projectID, zone, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newDisk(raw), nil
+}
+
+// RemoveDisk removes the named disk from the given zone. If the disk
+// does not exist then this is a noop.
+func (gce *Connection) RemoveDisk(zone, name string) error {
+	err := gce.raw.RemoveDisk(gce.projectID, zone, name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// AttachDisk attaches the named disk to the identified instance, both
+// in the given zone, and returns the attachment. The disk is exposed
+// to the instance using its name as the device name.
+func (gce *Connection) AttachDisk(zone, diskName, instanceId string, readonly bool) (*AttachedDisk, error) {
+	mode := diskModeRW
+	if readonly {
+		mode = diskModeRO
+	}
+	raw := &compute.AttachedDisk{
+		Type:       diskTypePersistent,
+		Mode:       mode,
+		Source:     fmt.Sprintf(partialDisk, zone, diskName),
+		DeviceName: diskName,
+	}
+	if err := gce.raw.AttachDisk(gce.projectID, zone, instanceId, raw); err != nil {
+		return nil, errors.Annotatef(err, "attaching disk %q to %q", diskName, instanceId)
+	}
+	return newAttachedDisk(raw), nil
+}
+
+// DetachDisk detaches the named disk from the identified instance,
+// both in the given zone. If the disk is not attached to the instance
+// then this is a noop.
+func (gce *Connection) DetachDisk(zone, instanceId, diskName string) error {
+	attached, err := gce.InstanceDisks(zone, instanceId)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	for _, disk := range attached {
+		if disk.DiskName != diskName {
+			continue
+		}
+		err := gce.raw.DetachDisk(gce.projectID, zone, instanceId, disk.DeviceName)
+		if err != nil && !errors.IsNotFound(err) {
+			return errors.Annotatef(err, "detaching disk %q from %q", diskName, instanceId)
+		}
+	}
+	return nil
+}
+
+// InstanceDisks returns the disks attached to the identified instance
+// in the given zone.
+func (gce *Connection) InstanceDisks(zone, instanceId string) ([]*AttachedDisk, error) {
+	raw, err := gce.raw.GetInstance(gce.projectID, zone, instanceId)
+	if err != nil {
+		return nil, errors.Trace(convertRawAPIError(errors.Cause(err)))
+	}
+	var results []*AttachedDisk
+	for _, disk := range raw.Disks {
+		if disk.Type != diskTypePersistent || disk.Boot {
+			continue
+		}
+		results = append(results, newAttachedDisk(disk))
+	}
+	return results, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"google.golang.org/api/compute/v1"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/gce/google"
+)
+
+func (s *connSuite) TestConnectionCreateDisk(c *gc.C) {
+	s.FakeConn.Disk = &compute.Disk{
+		Name:   "juju-disk",
+		Zone:   "https://www.googleapis.com/compute/v1/projects/spam/zones/a-zone",
+		SizeGb: 20,
+		Type:   "https://www.googleapis.com/compute/v1/projects/spam/zones/a-zone/diskTypes/pd-ssd",
+		Status: "READY",
+	}
+
+	disk, err := s.Conn.CreateDisk("a-zone", google.DiskSpec{
+		Name:               "juju-disk",
+		SizeHintGB:         20,
+		PersistentDiskType: google.DiskPersistentSSD,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(disk, jc.DeepEquals, &google.Disk{
+		Name:   "juju-disk",
+		Zone:   "a-zone",
+		SizeGB: 20,
+		Type:   google.DiskPersistentSSD,
+		Status: "READY",
+	})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "CreateDisk")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "a-zone")
+	c.Check(s.FakeConn.Calls[0].Disk, jc.DeepEquals, &compute.Disk{
+		Name:   "juju-disk",
+		SizeGb: 20,
+		Type:   "zones/a-zone/diskTypes/pd-ssd",
+	})
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "GetDisk")
+	c.Check(s.FakeConn.Calls[1].ID, gc.Equals, "juju-disk")
+}
+
+func (s *connSuite) TestConnectionCreateDiskDefaultType(c *gc.C) {
+	s.FakeConn.Disk = &compute.Disk{Name: "juju-disk"}
+
+	_, err := s.Conn.CreateDisk("a-zone", google.DiskSpec{
+		Name:       "juju-disk",
+		SizeHintGB: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls[0].Disk.Type, gc.Equals, "zones/a-zone/diskTypes/pd-standard")
+	c.Check(s.FakeConn.Calls[0].Disk.SizeGb, gc.Equals, int64(google.MinDiskSizeGB))
+}
+
+func (s *connSuite) TestConnectionCreateDiskScratch(c *gc.C) {
+	_, err := s.Conn.CreateDisk("a-zone", google.DiskSpec{
+		Name:    "juju-disk",
+		Scratch: true,
+	})
+	c.Assert(err, gc.ErrorMatches, "scratch disks cannot be created detached")
+	c.Check(s.FakeConn.Calls, gc.HasLen, 0)
+}
+
+func (s *connSuite) TestConnectionRemoveDiskNotFound(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("disk")
+
+	err := s.Conn.RemoveDisk("a-zone", "juju-disk")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "RemoveDisk")
+}
+
+func (s *connSuite) TestConnectionAttachDisk(c *gc.C) {
+	attached, err := s.Conn.AttachDisk("a-zone", "juju-disk", "spam", false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(attached, jc.DeepEquals, &google.AttachedDisk{
+		DiskName:   "juju-disk",
+		DeviceName: "juju-disk",
+	})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "AttachDisk")
+	c.Check(s.FakeConn.Calls[0].ID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[0].Attached, jc.DeepEquals, &compute.AttachedDisk{
+		Type:       "PERSISTENT",
+		Mode:       "READ_WRITE",
+		Source:     "zones/a-zone/disks/juju-disk",
+		DeviceName: "juju-disk",
+	})
+}
+
+func (s *connSuite) TestConnectionDetachDisk(c *gc.C) {
+	s.RawInstanceFull.Disks = append(s.RawInstanceFull.Disks, &compute.AttachedDisk{
+		Type:       "PERSISTENT",
+		Mode:       "READ_WRITE",
+		Source:     "https://www.googleapis.com/compute/v1/projects/spam/zones/a-zone/disks/juju-disk",
+		DeviceName: "juju-device",
+	})
+	s.FakeConn.Instance = &s.RawInstanceFull
+
+	err := s.Conn.DetachDisk("a-zone", "spam", "juju-disk")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetInstance")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "DetachDisk")
+	c.Check(s.FakeConn.Calls[1].ID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "juju-device")
+}
+
+func (s *connSuite) TestConnectionDetachDiskNotAttached(c *gc.C) {
+	s.FakeConn.Instance = &s.RawInstanceFull
+
+	err := s.Conn.DetachDisk("a-zone", "spam", "juju-disk")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetInstance")
+}
@@ -4,11 +4,33 @@
 package google
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
+	"sort"
+	"strings"
+
 	"github.com/juju/errors"
+	"google.golang.org/api/compute/v1"
 
 	"github.com/juju/juju/network"
 )
 
+// sourceFirewallInfix separates the name of a firewall from the
+// source CIDR in the names of the firewalls that restrict access
+// to its targets to that CIDR.
+const sourceFirewallInfix = "-src-"
+
+const (
+	// maxFirewallNameLength is the longest name GCE
+	// accepts for a firewall.
+	maxFirewallNameLength = 63
+
+	// sourceFirewallHashLength is the number of hex digits of the
+	// hash of the firewall name and source CIDR used in the names
+	// of source firewalls.
+	sourceFirewallHashLength = 8
+)
+
 // Ports build a list of all open port ranges for a given firewall name
 // (within the Connection's project) and returns it. If the firewall
 // does not exist then the list will be empty and no error is returned.
@@ -20,7 +42,11 @@ func (gce Connection) Ports(fwname string) ([]network.PortRange, error) {
 	if err != nil {
 		return nil, errors.Annotate(err, "while getting ports from GCE")
 	}
+	return firewallPorts(firewall)
+}
 
+// firewallPorts returns the port ranges opened by the firewall.
+func firewallPorts(firewall *compute.Firewall) ([]network.PortRange, error) {
 	var ports []network.PortRange
 	for _, allowed := range firewall.Allowed {
 		for _, portRangeStr := range allowed.Ports {
@@ -32,7 +58,6 @@ func (gce Connection) Ports(fwname string) ([]network.PortRange, error) {
 			ports = append(ports, portRange)
 		}
 	}
-
 	return ports, nil
 }
 
@@ -43,10 +68,14 @@ func (gce Connection) Ports(fwname string) ([]network.PortRange, error) {
 // ports it already has open. The call blocks until the ports are
 // opened or the request fails.
 func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error {
+	return gce.openPorts(fwname, fwname, network.AnySourceCIDR, ports)
+}
+
+func (gce Connection) openPorts(name, target, sourceCIDR string, ports []network.PortRange) error {
 	// TODO(ericsnow) Short-circuit if ports is empty.
 
 	// Compose the full set of open ports.
-	currentPorts, err := gce.Ports(fwname)
+	currentPorts, err := gce.Ports(name)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -59,7 +88,7 @@ func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error
 	// Send the request, depending on the current ports.
 	if currentPortsSet.IsEmpty() {
 		// Create a new firewall.
-		firewall := firewallSpec(fwname, inputPortsSet)
+		firewall := sourceFirewallSpec(name, target, sourceCIDR, inputPortsSet)
 		if err := gce.raw.AddFirewall(gce.projectID, firewall); err != nil {
 			return errors.Annotatef(err, "opening port(s) %+v", ports)
 		}
@@ -68,8 +97,8 @@ func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error
 
 	// Update an existing firewall.
 	newPortsSet := currentPortsSet.Union(inputPortsSet)
-	firewall := firewallSpec(fwname, newPortsSet)
-	if err := gce.raw.UpdateFirewall(gce.projectID, fwname, firewall); err != nil {
+	firewall := sourceFirewallSpec(name, target, sourceCIDR, newPortsSet)
+	if err := gce.raw.UpdateFirewall(gce.projectID, name, firewall); err != nil {
 		return errors.Annotatef(err, "opening port(s) %+v", ports)
 	}
 	return nil
@@ -82,8 +111,12 @@ func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error
 // match the provided port ranges. The call blocks until the ports are
 // closed or the request fails.
 func (gce Connection) ClosePorts(fwname string, ports ...network.PortRange) error {
+	return gce.closePorts(fwname, fwname, network.AnySourceCIDR, ports)
+}
+
+func (gce Connection) closePorts(name, target, sourceCIDR string, ports []network.PortRange) error {
 	// Compose the full set of open ports.
-	currentPorts, err := gce.Ports(fwname)
+	currentPorts, err := gce.Ports(name)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -98,16 +131,135 @@ func (gce Connection) ClosePorts(fwname string, ports ...network.PortRange) erro
 	if newPortsSet.IsEmpty() {
 		// Delete a firewall.
 		// TODO(ericsnow) Handle case where firewall does not exist.
-		if err := gce.raw.RemoveFirewall(gce.projectID, fwname); err != nil {
+		if err := gce.raw.RemoveFirewall(gce.projectID, name); err != nil {
 			return errors.Annotatef(err, "closing port(s) %+v", ports)
 		}
 		return nil
 	}
 
 	// Update an existing firewall.
-	firewall := firewallSpec(fwname, newPortsSet)
-	if err := gce.raw.UpdateFirewall(gce.projectID, fwname, firewall); err != nil {
+	firewall := sourceFirewallSpec(name, target, sourceCIDR, newPortsSet)
+	if err := gce.raw.UpdateFirewall(gce.projectID, name, firewall); err != nil {
 		return errors.Annotatef(err, "closing port(s) %+v", ports)
 	}
 	return nil
 }
+
+// IngressRules builds a list of all the ingress rules opened for the
+// targets of the given firewall name (within the Connection's project)
+// and returns it. Ports opened with OpenPorts are reported as rules
+// with network.AnySourceCIDR as their source.
+func (gce Connection) IngressRules(fwname string) ([]network.IngressRule, error) {
+	prefix := sourceFirewallPrefix(fwname)
+	listPrefix := fwname
+	if len(prefix) < len(listPrefix) {
+		listPrefix = prefix
+	}
+	firewalls, err := gce.raw.ListFirewalls(gce.projectID, listPrefix)
+	if err != nil {
+		return nil, errors.Annotate(err, "while getting ingress rules from GCE")
+	}
+	var rules []network.IngressRule
+	for _, firewall := range firewalls {
+		if firewall.Name != fwname && !isSourceFirewall(firewall, prefix, fwname) {
+			continue
+		}
+		ports, err := firewallPorts(firewall)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		rules = append(rules, network.NewIngressRules(ports, firewall.SourceRanges...)...)
+	}
+	network.SortIngressRules(rules)
+	return rules, nil
+}
+
+// OpenIngressRules sends requests to the GCE API to open the provided
+// ingress rules for the targets of the named firewall. Rules allowing
+// access from any address are opened on the named firewall itself, as
+// with OpenPorts; each other source CIDR has a firewall of its own.
+// The call blocks until the rules are opened or a request fails.
+func (gce Connection) OpenIngressRules(fwname string, rules ...network.IngressRule) error {
+	byCIDR := rulesBySourceCIDR(rules)
+	for _, cidr := range sortedKeys(byCIDR) {
+		name := sourceFirewallName(fwname, cidr)
+		if err := gce.openPorts(name, fwname, cidr, byCIDR[cidr]); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// CloseIngressRules sends requests to the GCE API to close the
+// provided ingress rules for the targets of the named firewall. Any
+// firewall left with no ports is removed. The call blocks until the
+// rules are closed or a request fails.
+func (gce Connection) CloseIngressRules(fwname string, rules ...network.IngressRule) error {
+	byCIDR := rulesBySourceCIDR(rules)
+	for _, cidr := range sortedKeys(byCIDR) {
+		name := sourceFirewallName(fwname, cidr)
+		if err := gce.closePorts(name, fwname, cidr, byCIDR[cidr]); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// sourceFirewallName returns the name of the firewall that allows
+// access from the source CIDR to the targets of the named firewall.
+func sourceFirewallName(fwname, sourceCIDR string) string {
+	if sourceCIDR == network.AnySourceCIDR {
+		return fwname
+	}
+	// Firewall names are limited in length, so the CIDR
+	// is identified by a short hash rather than spelled out.
+	// The prefix may be truncated, so the hash covers the
+	// firewall name too: otherwise long names sharing the
+	// prefix would share source firewalls.
+	hash := sha1.Sum([]byte(fwname + "\n" + sourceCIDR))
+	return sourceFirewallPrefix(fwname) + hex.EncodeToString(hash[:])[:sourceFirewallHashLength]
+}
+
+// sourceFirewallPrefix returns the prefix shared by the names of the
+// source firewalls of the named firewall. The prefix is truncated to
+// leave room for the hash, so it may be shared with other firewalls.
+func sourceFirewallPrefix(fwname string) string {
+	prefix := fwname + sourceFirewallInfix
+	if max := maxFirewallNameLength - sourceFirewallHashLength; len(prefix) > max {
+		prefix = prefix[:max]
+	}
+	return prefix
+}
+
+// isSourceFirewall reports whether the firewall is one of the source
+// firewalls of the named firewall.
+func isSourceFirewall(firewall *compute.Firewall, prefix, fwname string) bool {
+	if !strings.HasPrefix(firewall.Name, prefix) {
+		return false
+	}
+	for _, target := range firewall.TargetTags {
+		if target == fwname {
+			return true
+		}
+	}
+	return false
+}
+
+// rulesBySourceCIDR returns the port ranges of the rules,
+// keyed by source CIDR.
+func rulesBySourceCIDR(rules []network.IngressRule) map[string][]network.PortRange {
+	byCIDR := make(map[string][]network.PortRange)
+	for _, rule := range rules {
+		byCIDR[rule.SourceCIDR] = append(byCIDR[rule.SourceCIDR], rule.PortRange)
+	}
+	return byCIDR
+}
+
+func sortedKeys(m map[string][]network.PortRange) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
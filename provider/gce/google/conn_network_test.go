@@ -173,3 +173,162 @@ func (s *connSuite) TestConnectionClosePortsUpdate(c *gc.C) {
 		}},
 	})
 }
+
+func (s *connSuite) TestConnectionIngressRules(c *gc.C) {
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:         "spam",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80-81"},
+		}},
+	}, {
+		Name:         "spam-src-d442b225",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"3306"},
+		}},
+	}, {
+		Name:         "spam-eggs",
+		TargetTags:   []string{"spam-eggs"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"22"},
+		}},
+	}}
+
+	rules, err := s.Conn.IngressRules("spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(rules, jc.DeepEquals, []network.IngressRule{
+		{network.PortRange{80, 81, "tcp"}, "0.0.0.0/0"},
+		{network.PortRange{3306, 3306, "tcp"}, "10.0.0.0/8"},
+	})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListFirewalls")
+	c.Check(s.FakeConn.Calls[0].Prefix, gc.Equals, "spam")
+}
+
+func (s *connSuite) TestConnectionOpenIngressRules(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("spam")
+
+	rule := network.IngressRule{
+		PortRange: network.PortRange{
+			FromPort: 3306,
+			ToPort:   3306,
+			Protocol: "tcp",
+		},
+		SourceCIDR: "10.0.0.0/8",
+	}
+	err := s.Conn.OpenIngressRules("spam", rule)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetFirewall")
+	c.Check(s.FakeConn.Calls[0].Name, gc.Equals, "spam-src-d442b225")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddFirewall")
+	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
+		Name:         "spam-src-d442b225",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"3306"},
+		}},
+	})
+}
+
+func (s *connSuite) TestConnectionCloseIngressRules(c *gc.C) {
+	s.FakeConn.Firewall = &compute.Firewall{
+		Name:         "spam-src-d442b225",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"3306"},
+		}},
+	}
+
+	rule := network.IngressRule{
+		PortRange: network.PortRange{
+			FromPort: 3306,
+			ToPort:   3306,
+			Protocol: "tcp",
+		},
+		SourceCIDR: "10.0.0.0/8",
+	}
+	err := s.Conn.CloseIngressRules("spam", rule)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetFirewall")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "RemoveFirewall")
+	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "spam-src-d442b225")
+}
+
+func (s *connSuite) TestConnectionOpenIngressRulesLongName(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("spam")
+
+	fwname := "juju-2d3b6a4e-5c70-4c42-8b9e-3ea57b5fd1a3-machine-1234"
+	rule := network.IngressRule{
+		PortRange: network.PortRange{
+			FromPort: 3306,
+			ToPort:   3306,
+			Protocol: "tcp",
+		},
+		SourceCIDR: "2001:db8:1234:5678::/64",
+	}
+	err := s.Conn.OpenIngressRules(fwname, rule)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddFirewall")
+	firewall := s.FakeConn.Calls[1].Firewall
+	c.Check(len(firewall.Name) <= 63, jc.IsTrue, gc.Commentf("%q", firewall.Name))
+	c.Check(firewall.TargetTags, jc.DeepEquals, []string{fwname})
+
+	// The rule is reported under the shortened name, but a firewall
+	// sharing the truncated prefix for another target is not.
+	other := *firewall
+	other.Name = firewall.Name[:len(firewall.Name)-1] + "0"
+	other.TargetTags = []string{fwname + "5"}
+	s.FakeConn.Calls = nil
+	s.FakeConn.Err = nil
+	s.FakeConn.Firewalls = []*compute.Firewall{firewall, &other}
+
+	rules, err := s.Conn.IngressRules(fwname)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rules, jc.DeepEquals, []network.IngressRule{rule})
+	c.Check(s.FakeConn.Calls[0].Prefix, gc.Equals, fwname)
+}
+
+func (s *connSuite) TestConnectionOpenIngressRulesLongNamesDistinct(c *gc.C) {
+	// The names share the prefix kept in source firewall names.
+	prefix := "juju-2d3b6a4e-5c70-4c42-8b9e-3ea57b5fd1a3-machine-12345"
+	rule := network.IngressRule{
+		PortRange: network.PortRange{
+			FromPort: 3306,
+			ToPort:   3306,
+			Protocol: "tcp",
+		},
+		SourceCIDR: "10.0.0.0/8",
+	}
+	var firewalls []*compute.Firewall
+	for _, fwname := range []string{prefix + "6", prefix + "7"} {
+		s.FakeConn.Calls = nil
+		s.FakeConn.Err = errors.NotFoundf("spam")
+		err := s.Conn.OpenIngressRules(fwname, rule)
+		c.Assert(err, jc.ErrorIsNil)
+
+		c.Assert(s.FakeConn.Calls, gc.HasLen, 2)
+		c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddFirewall")
+		firewall := s.FakeConn.Calls[1].Firewall
+		c.Check(firewall.TargetTags, jc.DeepEquals, []string{fwname})
+		firewalls = append(firewalls, firewall)
+	}
+	c.Check(firewalls[0].Name, gc.Not(gc.Equals), firewalls[1].Name)
+}
@@ -4,6 +4,10 @@
 package google
 
 import (
+	"fmt"
+	"path"
+
+	"github.com/juju/errors"
 	"google.golang.org/api/compute/v1"
 )
 
@@ -19,6 +23,20 @@ const (
 	diskModeRO = "READ_ONLY"
 )
 
+// DiskType is the type of a persistent disk.
+type DiskType string
+
+// The persistent disk types supported by GCE.
+const (
+	DiskPersistentStandard DiskType = "pd-standard"
+	DiskPersistentSSD      DiskType = "pd-ssd"
+)
+
+const (
+	partialDiskType = "zones/%s/diskTypes/%s"
+	partialDisk     = "zones/%s/disks/%s"
+)
+
 // MinDiskSizeGB is the minimum/default size (in megabytes) for
 // GCE disks.
 //
@@ -50,6 +68,16 @@ type DiskSpec struct {
 	// AutoDelete indicates that the attached disk should be removed
 	// when the instance to which it is attached is removed.
 	AutoDelete bool
+	// Name is the name of a disk created independently of an
+	// instance. (detached only)
+	Name string
+	// PersistentDiskType is the type of a persistent disk created
+	// independently of an instance, defaulting to pd-standard.
+	// (detached only)
+	PersistentDiskType DiskType
+	// Description is a free-form description of a disk created
+	// independently of an instance. (detached only)
+	Description string
 }
 
 // TooSmall checks the spec's size hint and indicates whether or not
@@ -100,3 +128,78 @@ func (ds *DiskSpec) newAttached() *compute.AttachedDisk {
 	}
 	return &disk
 }
+
+// newDetached builds a compute.Disk, for creation in the given zone
+// independently of any instance, using the information in the disk
+// spec and returns it.
+func (ds *DiskSpec) newDetached(zone string) (*compute.Disk, error) {
+	if ds.Scratch {
+		return nil, errors.New("scratch disks cannot be created detached")
+	}
+	if ds.Name == "" {
+		return nil, errors.New("detached disks must have a name")
+	}
+	diskType := ds.PersistentDiskType
+	if diskType == "" {
+		diskType = DiskPersistentStandard
+	}
+	disk := compute.Disk{
+		Name:        ds.Name,
+		Description: ds.Description,
+		SizeGb:      int64(ds.SizeGB()),
+		Type:        fmt.Sprintf(partialDiskType, zone, diskType),
+		SourceImage: ds.ImageURL,
+	}
+	return &disk, nil
+}
+
+// Disk holds the information about a persistent disk.
+type Disk struct {
+	// Name is the name of the disk, unique within its zone.
+	Name string
+	// Zone is the name of the zone in which the disk resides.
+	Zone string
+	// SizeGB is the size of the disk in Gigabytes.
+	SizeGB uint64
+	// Type is the persistent disk type.
+	Type DiskType
+	// Status is the disk's status (e.g. "READY").
+	Status string
+	// Users holds the names of the instances to which the disk is
+	// attached.
+	Users []string
+}
+
+func newDisk(raw *compute.Disk) *Disk {
+	disk := Disk{
+		Name:   raw.Name,
+		Zone:   path.Base(raw.Zone),
+		SizeGB: uint64(raw.SizeGb),
+		Type:   DiskType(path.Base(raw.Type)),
+		Status: raw.Status,
+	}
+	for _, user := range raw.Users {
+		disk.Users = append(disk.Users, path.Base(user))
+	}
+	return &disk
+}
+
+// AttachedDisk holds the information about a disk's attachment to
+// an instance.
+type AttachedDisk struct {
+	// DiskName is the name of the attached disk.
+	DiskName string
+	// DeviceName is the name by which the disk is exposed to the
+	// instance's operating system, under /dev/disk/by-id/google-*.
+	DeviceName string
+	// Readonly indicates that the disk is attached read-only.
+	Readonly bool
+}
+
+func newAttachedDisk(raw *compute.AttachedDisk) *AttachedDisk {
+	return &AttachedDisk{
+		DiskName:   path.Base(raw.Source),
+		DeviceName: raw.DeviceName,
+		Readonly:   raw.Mode == diskModeRO,
+	}
+}
@@ -36,6 +36,22 @@ func (s *instanceSuite) TestNewInstanceNoSpec(c *gc.C) {
 	c.Check(spec, gc.IsNil)
 }
 
+func (s *instanceSuite) TestNewInstancePreemptible(c *gc.C) {
+	raw := s.RawInstanceFull
+	raw.Scheduling = &compute.Scheduling{Preemptible: true}
+	inst := google.NewInstanceRaw(&raw, nil)
+
+	c.Check(inst.Preemptible, jc.IsTrue)
+}
+
+func (s *instanceSuite) TestInstanceSpecPreemptible(c *gc.C) {
+	spec := s.InstanceSpec
+	c.Check(spec.Summary().Preemptible, jc.IsFalse)
+
+	spec.Preemptible = true
+	c.Check(spec.Summary().Preemptible, jc.IsTrue)
+}
+
 func (s *instanceSuite) TestInstanceRootDiskGB(c *gc.C) {
 	size := s.Instance.RootDiskGB()
 
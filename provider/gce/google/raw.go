@@ -130,6 +130,16 @@ func (rc *rawConn) GetFirewall(projectID, name string) (*compute.Firewall, error
 	return firewallList.Items[0], nil
 }
 
+func (rc *rawConn) ListFirewalls(projectID, prefix string) ([]*compute.Firewall, error) {
+	call := rc.Firewalls.List(projectID)
+	call = call.Filter("name eq " + prefix + ".*")
+	firewallList, err := call.Do()
+	if err != nil {
+		return nil, errors.Annotate(err, "while listing firewalls from GCE")
+	}
+	return firewallList.Items, nil
+}
+
 func (rc *rawConn) AddFirewall(projectID string, firewall *compute.Firewall) error {
 	call := rc.Firewalls.Insert(projectID, firewall)
 	operation, err := call.Do()
@@ -187,6 +197,56 @@ func (rc *rawConn) ListAvailabilityZones(projectID, region string) ([]*compute.Z
 	return results, nil
 }
 
+func (rc *rawConn) CreateDisk(projectID, zone string, spec *compute.Disk) error {
+	call := rc.Disks.Insert(projectID, zone, spec)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Annotate(err, "sending new disk request")
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) GetDisk(projectID, zone, id string) (*compute.Disk, error) {
+	call := rc.Disks.Get(projectID, zone, id)
+	disk, err := call.Do()
+	return disk, errors.Trace(convertRawAPIError(err))
+}
+
+func (rc *rawConn) RemoveDisk(projectID, zone, id string) error {
+	call := rc.Disks.Delete(projectID, zone, id)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(convertRawAPIError(err))
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(convertRawAPIError(err))
+}
+
+func (rc *rawConn) AttachDisk(projectID, zone, instanceId string, disk *compute.AttachedDisk) error {
+	call := rc.Instances.AttachDisk(projectID, zone, instanceId, disk)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(convertRawAPIError(err))
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) DetachDisk(projectID, zone, instanceId, deviceName string) error {
+	call := rc.Instances.DetachDisk(projectID, zone, instanceId, deviceName)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(convertRawAPIError(err))
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(convertRawAPIError(err))
+}
+
 type waitError struct {
 	op    *compute.Operation
 	cause error
@@ -153,6 +153,8 @@ type fakeCall struct {
 	Instance  *compute.Instance
 	InstValue compute.Instance
 	Firewall  *compute.Firewall
+	Disk      *compute.Disk
+	Attached  *compute.AttachedDisk
 }
 
 type fakeConn struct {
@@ -162,7 +164,9 @@ type fakeConn struct {
 	Instance   *compute.Instance
 	Instances  []*compute.Instance
 	Firewall   *compute.Firewall
+	Firewalls  []*compute.Firewall
 	Zones      []*compute.Zone
+	Disk       *compute.Disk
 	Err        error
 	FailOnCall int
 }
@@ -261,6 +265,21 @@ func (rc *fakeConn) GetFirewall(projectID, name string) (*compute.Firewall, erro
 	return rc.Firewall, err
 }
 
+func (rc *fakeConn) ListFirewalls(projectID, prefix string) ([]*compute.Firewall, error) {
+	call := fakeCall{
+		FuncName:  "ListFirewalls",
+		ProjectID: projectID,
+		Prefix:    prefix,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return rc.Firewalls, err
+}
+
 func (rc *fakeConn) AddFirewall(projectID string, firewall *compute.Firewall) error {
 	call := fakeCall{
 		FuncName:  "AddFirewall",
@@ -321,3 +340,85 @@ func (rc *fakeConn) ListAvailabilityZones(projectID, region string) ([]*compute.
 	}
 	return rc.Zones, err
 }
+
+func (rc *fakeConn) CreateDisk(projectID, zone string, spec *compute.Disk) error {
+	call := fakeCall{
+		FuncName:  "CreateDisk",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		Disk:      spec,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) GetDisk(projectID, zone, id string) (*compute.Disk, error) {
+	call := fakeCall{
+		FuncName:  "GetDisk",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		ID:        id,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return rc.Disk, err
+}
+
+func (rc *fakeConn) RemoveDisk(projectID, zone, id string) error {
+	call := fakeCall{
+		FuncName:  "RemoveDisk",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		ID:        id,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) AttachDisk(projectID, zone, instanceId string, disk *compute.AttachedDisk) error {
+	call := fakeCall{
+		FuncName:  "AttachDisk",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		ID:        instanceId,
+		Attached:  disk,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) DetachDisk(projectID, zone, instanceId, deviceName string) error {
+	call := fakeCall{
+		FuncName:  "DetachDisk",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		ID:        instanceId,
+		Name:      deviceName,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
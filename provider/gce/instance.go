@@ -87,3 +87,30 @@ func (inst *environInstance) Ports(machineID string) ([]network.PortRange, error
 	ports, err := env.gce.Ports(name)
 	return ports, errors.Trace(err)
 }
+
+// OpenIngressRules opens the given ingress rules on the instance,
+// which should have been started with the given machine id.
+func (inst *environInstance) OpenIngressRules(machineID string, rules []network.IngressRule) error {
+	name := common.MachineFullName(inst.env, machineID)
+	env := inst.env.getSnapshot()
+	err := env.gce.OpenIngressRules(name, rules...)
+	return errors.Trace(err)
+}
+
+// CloseIngressRules closes the given ingress rules on the instance,
+// which should have been started with the given machine id.
+func (inst *environInstance) CloseIngressRules(machineID string, rules []network.IngressRule) error {
+	name := common.MachineFullName(inst.env, machineID)
+	env := inst.env.getSnapshot()
+	err := env.gce.CloseIngressRules(name, rules...)
+	return errors.Trace(err)
+}
+
+// IngressRules returns the ingress rules opened on the instance,
+// which should have been started with the given machine id.
+func (inst *environInstance) IngressRules(machineID string) ([]network.IngressRule, error) {
+	name := common.MachineFullName(inst.env, machineID)
+	env := inst.env.getSnapshot()
+	rules, err := env.gce.IngressRules(name)
+	return rules, errors.Trace(err)
+}
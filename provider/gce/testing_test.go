@@ -421,7 +421,10 @@ type fakeConnCall struct {
 	InstanceSpec google.InstanceSpec
 	FirewallName string
 	PortRanges   []network.PortRange
+	IngressRules []network.IngressRule
 	Region       string
+	DiskSpec     google.DiskSpec
+	DiskName     string
 }
 
 type fakeConn struct {
@@ -430,7 +433,10 @@ type fakeConn struct {
 	Inst       *google.Instance
 	Insts      []google.Instance
 	PortRanges []network.PortRange
+	Rules      []network.IngressRule
 	Zones      []google.AvailabilityZone
+	Disk       *google.Disk
+	Attached   []*google.AttachedDisk
 	Err        error
 	FailOnCall int
 }
@@ -511,6 +517,32 @@ func (fc *fakeConn) ClosePorts(fwname string, ports ...network.PortRange) error
 	return fc.err()
 }
 
+func (fc *fakeConn) IngressRules(fwname string) ([]network.IngressRule, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:     "IngressRules",
+		FirewallName: fwname,
+	})
+	return fc.Rules, fc.err()
+}
+
+func (fc *fakeConn) OpenIngressRules(fwname string, rules ...network.IngressRule) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:     "OpenIngressRules",
+		FirewallName: fwname,
+		IngressRules: rules,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) CloseIngressRules(fwname string, rules ...network.IngressRule) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:     "CloseIngressRules",
+		FirewallName: fwname,
+		IngressRules: rules,
+	})
+	return fc.err()
+}
+
 func (fc *fakeConn) AvailabilityZones(region string) ([]google.AvailabilityZone, error) {
 	fc.Calls = append(fc.Calls, fakeConnCall{
 		FuncName: "AvailabilityZones",
@@ -519,6 +551,71 @@ func (fc *fakeConn) AvailabilityZones(region string) ([]google.AvailabilityZone,
 	return fc.Zones, fc.err()
 }
 
+func (fc *fakeConn) CreateDisk(zone string, spec google.DiskSpec) (*google.Disk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "CreateDisk",
+		ZoneName: zone,
+		DiskSpec: spec,
+	})
+	return &google.Disk{
+		Name:   spec.Name,
+		Zone:   zone,
+		SizeGB: spec.SizeGB(),
+		Type:   spec.PersistentDiskType,
+	}, fc.err()
+}
+
+func (fc *fakeConn) Disk(zone, name string) (*google.Disk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "Disk",
+		ZoneName: zone,
+		DiskName: name,
+	})
+	return fc.Disk, fc.err()
+}
+
+func (fc *fakeConn) RemoveDisk(zone, name string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "RemoveDisk",
+		ZoneName: zone,
+		DiskName: name,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) AttachDisk(zone, diskName, instanceId string, readonly bool) (*google.AttachedDisk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "AttachDisk",
+		ZoneName: zone,
+		DiskName: diskName,
+		ID:       instanceId,
+	})
+	return &google.AttachedDisk{
+		DiskName:   diskName,
+		DeviceName: diskName,
+		Readonly:   readonly,
+	}, fc.err()
+}
+
+func (fc *fakeConn) DetachDisk(zone, instanceId, diskName string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "DetachDisk",
+		ZoneName: zone,
+		DiskName: diskName,
+		ID:       instanceId,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) InstanceDisks(zone, instanceId string) ([]*google.AttachedDisk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "InstanceDisks",
+		ZoneName: zone,
+		ID:       instanceId,
+	})
+	return fc.Attached, fc.err()
+}
+
 func (fc *fakeConn) WasCalled(funcName string) (bool, []fakeConnCall) {
 	var calls []fakeConnCall
 	called := false
@@ -61,6 +61,8 @@ func (env *joyentEnviron) machineFullName(machineId string) string {
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
+	constraints.InstanceLifecycle,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
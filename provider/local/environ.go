@@ -344,6 +344,8 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.InstanceLifecycle,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -689,6 +689,8 @@ func (environ *maasEnviron) startNode(node gomaasapi.MAASObject, series string,
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
+	constraints.InstanceLifecycle,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
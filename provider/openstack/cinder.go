@@ -0,0 +1,434 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/set"
+	"launchpad.net/goose/client"
+	gooseerrors "launchpad.net/goose/errors"
+	goosehttp "launchpad.net/goose/http"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	CinderProviderType = storage.ProviderType("cinder")
+
+	// Config attributes
+
+	// The Cinder volume type to create volumes with. If unset, the
+	// cloud's default volume type is used.
+	CinderVolumeType = "volume-type"
+
+	// The availability zone in which volumes will be created. If
+	// unset, Cinder chooses the zone.
+	CinderAvailabilityZone = "availability-zone"
+)
+
+const (
+	// cinderServiceType is the keystone catalog service type for
+	// version 2 of the Cinder API.
+	cinderServiceType = "volumev2"
+
+	// computeServiceType is the keystone catalog service type for Nova.
+	computeServiceType = "compute"
+
+	// Cinder volume statuses.
+	volumeStatusAvailable = "available"
+	volumeStatusInUse     = "in-use"
+	volumeStatusError     = "error"
+)
+
+// volumeAttempt is the strategy used to wait for newly created
+// volumes to become available for attachment.
+var volumeAttempt = utils.AttemptStrategy{
+	Total: 2 * time.Minute,
+	Delay: time.Second,
+}
+
+// cinderProvider creates volume sources which use OpenStack Cinder
+// volumes, attached to instances through Nova.
+type cinderProvider struct{}
+
+var _ storage.Provider = (*cinderProvider)(nil)
+
+var cinderConfigOptions = set.NewStrings(
+	CinderVolumeType,
+	CinderAvailabilityZone,
+)
+
+// ValidateConfig is defined on the Provider interface.
+func (p *cinderProvider) ValidateConfig(providerConfig *storage.Config) error {
+	for attr, value := range providerConfig.Attrs() {
+		if !cinderConfigOptions.Contains(attr) {
+			return errors.Errorf("unknown provider config option %q", attr)
+		}
+		if _, ok := value.(string); !ok {
+			return errors.Errorf("expected string for %q, got %T", attr, value)
+		}
+	}
+	return nil
+}
+
+// Supports is defined on the Provider interface.
+func (p *cinderProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (p *cinderProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is defined on the Provider interface.
+func (p *cinderProvider) Dynamic() bool {
+	return true
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *cinderProvider) VolumeSource(environConfig *config.Config, providerConfig *storage.Config) (storage.VolumeSource, error) {
+	if err := p.ValidateConfig(providerConfig); err != nil {
+		return nil, err
+	}
+	stor, err := newOpenstackStorage(environConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &cinderVolumeSource{
+		storage: stor,
+		envName: environConfig.Name(),
+	}, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (p *cinderProvider) FilesystemSource(environConfig *config.Config, providerConfig *storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// CinderVolume describes a Cinder volume.
+type CinderVolume struct {
+	Id               string            `json:"id,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	Status           string            `json:"status,omitempty"`
+	Size             int               `json:"size"`
+	VolumeType       string            `json:"volume_type,omitempty"`
+	AvailabilityZone string            `json:"availability_zone,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// VolumeAttachment describes the attachment of a volume to a Nova
+// server.
+type VolumeAttachment struct {
+	Id       string `json:"id,omitempty"`
+	ServerId string `json:"serverId,omitempty"`
+	VolumeId string `json:"volumeId"`
+	Device   string `json:"device,omitempty"`
+}
+
+// OpenstackStorage is the subset of the Cinder and Nova APIs used to
+// manage volumes and their attachments.
+type OpenstackStorage interface {
+	CreateVolume(CinderVolume) (*CinderVolume, error)
+	GetVolume(volumeId string) (*CinderVolume, error)
+	DeleteVolume(volumeId string) error
+	ListVolumeAttachments(serverId string) ([]VolumeAttachment, error)
+	AttachVolume(serverId, volumeId string) (*VolumeAttachment, error)
+	DetachVolume(serverId, attachmentId string) error
+}
+
+// newOpenstackStorage returns an OpenstackStorage for the environment
+// with the given configuration.
+var newOpenstackStorage = func(environConfig *config.Config) (OpenstackStorage, error) {
+	env, err := providerInstance.Open(environConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	e := env.(*environ)
+	if err := authenticateClient(e); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &openstackStorageAdapter{e.client}, nil
+}
+
+type cinderVolumeSource struct {
+	storage OpenstackStorage
+	envName string
+}
+
+var _ storage.VolumeSource = (*cinderVolumeSource)(nil)
+
+// CreateVolumes is specified on the storage.VolumeSource interface.
+func (s *cinderVolumeSource) CreateVolumes(params []storage.VolumeParams) (_ []storage.Volume, _ []storage.VolumeAttachment, err error) {
+	volumes := make([]storage.Volume, 0, len(params))
+
+	// If there's an error, we delete any ones that are created.
+	defer func() {
+		if err != nil && len(volumes) > 0 {
+			volIds := make([]string, len(volumes))
+			for i, v := range volumes {
+				volIds[i] = v.VolumeId
+			}
+			for i, volErr := range s.DestroyVolumes(volIds) {
+				if volErr != nil {
+					logger.Warningf("error cleaning up volume %v: %v", volumes[i].Tag, volErr)
+				}
+			}
+		}
+	}()
+
+	for _, p := range params {
+		if err := s.ValidateVolumeParams(p); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	for _, p := range params {
+		vol := CinderVolume{
+			Name: fmt.Sprintf("juju-%s-%s", s.envName, p.Tag),
+			Size: int(mibToGib(p.Size)),
+		}
+		vol.VolumeType, _ = p.Attributes[CinderVolumeType].(string)
+		vol.AvailabilityZone, _ = p.Attributes[CinderAvailabilityZone].(string)
+		created, err := s.storage.CreateVolume(vol)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "creating volume %s", p.Tag.Id())
+		}
+		volumes = append(volumes, storage.Volume{
+			Tag:        p.Tag,
+			VolumeId:   created.Id,
+			Size:       gibToMib(uint64(created.Size)),
+			Persistent: true,
+		})
+	}
+	// Cinder volumes are always persistent, so their attachments
+	// are created independently.
+	return volumes, nil, nil
+}
+
+// DescribeVolumes is specified on the storage.VolumeSource interface.
+func (s *cinderVolumeSource) DescribeVolumes(volIds []string) ([]storage.Volume, error) {
+	vols := make([]storage.Volume, len(volIds))
+	for i, volumeId := range volIds {
+		vol, err := s.storage.GetVolume(volumeId)
+		if err != nil {
+			return nil, errors.Annotatef(err, "describing volume %q", volumeId)
+		}
+		vols[i] = storage.Volume{
+			VolumeId:   vol.Id,
+			Size:       gibToMib(uint64(vol.Size)),
+			Persistent: true,
+		}
+	}
+	return vols, nil
+}
+
+// DestroyVolumes is specified on the storage.VolumeSource interface.
+func (s *cinderVolumeSource) DestroyVolumes(volIds []string) []error {
+	results := make([]error, len(volIds))
+	for i, volumeId := range volIds {
+		err := s.storage.DeleteVolume(volumeId)
+		if err != nil && !errors.IsNotFound(err) {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+		}
+	}
+	return results
+}
+
+// ValidateVolumeParams is specified on the storage.VolumeSource interface.
+func (s *cinderVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	for _, attr := range []string{CinderVolumeType, CinderAvailabilityZone} {
+		if value, ok := params.Attributes[attr]; ok {
+			if _, ok := value.(string); !ok {
+				return errors.Errorf("expected string for %q, got %T", attr, value)
+			}
+		}
+	}
+	return nil
+}
+
+// AttachVolumes is specified on the storage.VolumeSource interface.
+func (s *cinderVolumeSource) AttachVolumes(attachParams []storage.VolumeAttachmentParams) ([]storage.VolumeAttachment, error) {
+	attachments := make([]storage.VolumeAttachment, len(attachParams))
+	for i, p := range attachParams {
+		if p.InstanceId == "" {
+			return nil, errors.Errorf("attaching volume %s: machine %s not provisioned", p.Volume.Id(), p.Machine.Id())
+		}
+		attachment, err := s.attachOneVolume(string(p.InstanceId), p.VolumeId)
+		if err != nil {
+			return nil, errors.Annotatef(err, "attaching %v to %v", p.VolumeId, p.InstanceId)
+		}
+		attachments[i] = storage.VolumeAttachment{
+			Volume:     p.Volume,
+			Machine:    p.Machine,
+			DeviceName: strings.TrimPrefix(attachment.Device, "/dev/"),
+		}
+	}
+	return attachments, nil
+}
+
+// attachOneVolume attaches the volume to the server, returning the
+// existing attachment if the volume is already attached to it.
+func (s *cinderVolumeSource) attachOneVolume(serverId, volumeId string) (*VolumeAttachment, error) {
+	existing, err := s.storage.ListVolumeAttachments(serverId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, a := range existing {
+		if a.VolumeId == volumeId {
+			return &a, nil
+		}
+	}
+	if err := s.waitVolumeAvailable(volumeId); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s.storage.AttachVolume(serverId, volumeId)
+}
+
+// waitVolumeAvailable waits for a newly created volume to become
+// available for attachment.
+func (s *cinderVolumeSource) waitVolumeAvailable(volumeId string) error {
+	var status string
+	for a := volumeAttempt.Start(); a.Next(); {
+		vol, err := s.storage.GetVolume(volumeId)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		status = vol.Status
+		switch status {
+		case volumeStatusAvailable:
+			return nil
+		case volumeStatusError:
+			return errors.Errorf("volume %q has status %q", volumeId, status)
+		}
+	}
+	return errors.Errorf("timed out waiting for volume %q to become available, status %q", volumeId, status)
+}
+
+// DetachVolumes is specified on the storage.VolumeSource interface.
+func (s *cinderVolumeSource) DetachVolumes(attachParams []storage.VolumeAttachmentParams) error {
+	for _, p := range attachParams {
+		if err := s.detachOneVolume(string(p.InstanceId), p.VolumeId); err != nil {
+			return errors.Annotatef(err, "detaching %v from %v", p.VolumeId, p.InstanceId)
+		}
+	}
+	return nil
+}
+
+func (s *cinderVolumeSource) detachOneVolume(serverId, volumeId string) error {
+	attachments, err := s.storage.ListVolumeAttachments(serverId)
+	if errors.IsNotFound(err) {
+		// The server has gone, taking its attachments with it.
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	for _, a := range attachments {
+		if a.VolumeId != volumeId {
+			continue
+		}
+		err := s.storage.DetachVolume(serverId, a.Id)
+		if err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// openstackStorageAdapter implements OpenstackStorage by making
+// requests to the Cinder and Nova APIs.
+type openstackStorageAdapter struct {
+	client client.Client
+}
+
+func (a *openstackStorageAdapter) send(method, svcType, apiCall string, req, resp interface{}, expected ...int) error {
+	err := a.client.SendRequest(method, svcType, apiCall, &goosehttp.RequestData{
+		ReqValue:       req,
+		RespValue:      resp,
+		ExpectedStatus: expected,
+	})
+	if gooseerrors.IsNotFound(err) {
+		return errors.NewNotFound(err, "")
+	}
+	return err
+}
+
+// CreateVolume is specified on the OpenstackStorage interface.
+func (a *openstackStorageAdapter) CreateVolume(vol CinderVolume) (*CinderVolume, error) {
+	req := struct {
+		Volume CinderVolume `json:"volume"`
+	}{vol}
+	var resp struct {
+		Volume CinderVolume `json:"volume"`
+	}
+	if err := a.send("POST", cinderServiceType, "volumes", &req, &resp, http.StatusAccepted); err != nil {
+		return nil, errors.Annotate(err, "failed to create volume")
+	}
+	return &resp.Volume, nil
+}
+
+// GetVolume is specified on the OpenstackStorage interface.
+func (a *openstackStorageAdapter) GetVolume(volumeId string) (*CinderVolume, error) {
+	var resp struct {
+		Volume CinderVolume `json:"volume"`
+	}
+	if err := a.send("GET", cinderServiceType, "volumes/"+volumeId, nil, &resp); err != nil {
+		return nil, errors.Annotatef(err, "failed to get volume %q", volumeId)
+	}
+	return &resp.Volume, nil
+}
+
+// DeleteVolume is specified on the OpenstackStorage interface.
+func (a *openstackStorageAdapter) DeleteVolume(volumeId string) error {
+	err := a.send("DELETE", cinderServiceType, "volumes/"+volumeId, nil, nil, http.StatusAccepted)
+	return errors.Annotatef(err, "failed to delete volume %q", volumeId)
+}
+
+// ListVolumeAttachments is specified on the OpenstackStorage interface.
+func (a *openstackStorageAdapter) ListVolumeAttachments(serverId string) ([]VolumeAttachment, error) {
+	var resp struct {
+		VolumeAttachments []VolumeAttachment `json:"volumeAttachments"`
+	}
+	url := fmt.Sprintf("servers/%s/os-volume_attachments", serverId)
+	if err := a.send("GET", computeServiceType, url, nil, &resp); err != nil {
+		return nil, errors.Annotatef(err, "failed to list volume attachments for server %q", serverId)
+	}
+	return resp.VolumeAttachments, nil
+}
+
+// AttachVolume is specified on the OpenstackStorage interface.
+func (a *openstackStorageAdapter) AttachVolume(serverId, volumeId string) (*VolumeAttachment, error) {
+	req := struct {
+		VolumeAttachment VolumeAttachment `json:"volumeAttachment"`
+	}{VolumeAttachment{VolumeId: volumeId}}
+	var resp struct {
+		VolumeAttachment VolumeAttachment `json:"volumeAttachment"`
+	}
+	url := fmt.Sprintf("servers/%s/os-volume_attachments", serverId)
+	if err := a.send("POST", computeServiceType, url, &req, &resp); err != nil {
+		return nil, errors.Annotatef(err, "failed to attach volume %q to server %q", volumeId, serverId)
+	}
+	return &resp.VolumeAttachment, nil
+}
+
+// DetachVolume is specified on the OpenstackStorage interface.
+func (a *openstackStorageAdapter) DetachVolume(serverId, attachmentId string) error {
+	url := fmt.Sprintf("servers/%s/os-volume_attachments/%s", serverId, attachmentId)
+	err := a.send("DELETE", computeServiceType, url, nil, nil, http.StatusAccepted)
+	return errors.Annotatef(err, "failed to detach volume from server %q", serverId)
+}
+
+func mibToGib(m uint64) uint64 {
+	return (m + 1023) / 1024
+}
+
+func gibToMib(g uint64) uint64 {
+	return g * 1024
+}
@@ -0,0 +1,261 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	jujuerrors "github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/goose/client"
+	"launchpad.net/goose/identity"
+	"launchpad.net/goose/testservices/identityservice"
+
+	"github.com/juju/juju/provider/openstack"
+)
+
+// cinderDouble serves the parts of the Cinder API, and the Nova
+// volume attachment API, used by the cinder storage provider. The
+// goose service double provides neither, so cinderDouble is served
+// alongside it and registered in its service catalog.
+type cinderDouble struct {
+	mu          sync.Mutex
+	nextId      int
+	volumes     map[string]openstack.CinderVolume
+	attachments map[string][]openstack.VolumeAttachment
+}
+
+// cinderEndpoints implements identityservice.ServiceProvider.
+type cinderEndpoints struct {
+	url    string
+	region string
+}
+
+func (e cinderEndpoints) Endpoints() []identityservice.Endpoint {
+	return []identityservice.Endpoint{{
+		AdminURL:    e.url,
+		InternalURL: e.url,
+		PublicURL:   e.url,
+		Region:      e.region,
+	}}
+}
+
+// openstackStorage starts a cinderDouble, and returns it along with
+// the OpenstackStorage used by the cinder provider for s.env. The
+// Nova attachment API is served for the given server ids only.
+func (s *localServerSuite) openstackStorage(c *gc.C, serverIds ...string) (openstack.OpenstackStorage, *cinderDouble) {
+	double := &cinderDouble{
+		volumes:     make(map[string]openstack.CinderVolume),
+		attachments: make(map[string][]openstack.VolumeAttachment),
+	}
+	s.srv.Mux.HandleFunc("/cinder/volumes", double.handleVolumes)
+	s.srv.Mux.HandleFunc("/cinder/volumes/", double.handleVolumes)
+	s.srv.Service.Identity.RegisterServiceProvider("cinder", "volumev2", cinderEndpoints{
+		url:    s.srv.Server.URL + "/cinder",
+		region: s.cred.Region,
+	})
+
+	cl := client.NewClient(s.cred, identity.AuthUserPass, nil)
+	err := cl.Authenticate()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, serverId := range serverIds {
+		attachmentsURL, err := cl.MakeServiceURL("compute", []string{"servers", serverId, "os-volume_attachments"})
+		c.Assert(err, jc.ErrorIsNil)
+		u, err := url.Parse(attachmentsURL)
+		c.Assert(err, jc.ErrorIsNil)
+		handler := double.attachmentsHandler(serverId)
+		s.srv.Mux.HandleFunc(u.Path, handler)
+		s.srv.Mux.HandleFunc(u.Path+"/", handler)
+	}
+
+	stor, err := (*openstack.NewOpenstackStorage)(s.env.Config())
+	c.Assert(err, jc.ErrorIsNil)
+	return stor, double
+}
+
+func (d *cinderDouble) volumeCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.volumes)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeNotFound(w http.ResponseWriter, what string) {
+	writeJSON(w, http.StatusNotFound, map[string]interface{}{
+		"itemNotFound": map[string]interface{}{
+			"code":    http.StatusNotFound,
+			"message": what + " could not be found",
+		},
+	})
+}
+
+func (d *cinderDouble) handleVolumes(w http.ResponseWriter, req *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	id := strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, "/cinder/volumes"), "/")
+	type volumeBody struct {
+		Volume openstack.CinderVolume `json:"volume"`
+	}
+	switch {
+	case req.Method == "POST" && id == "":
+		var body volumeBody
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body.Volume.Id = fmt.Sprintf("vol-%d", d.nextId)
+		body.Volume.Status = "available"
+		d.nextId++
+		d.volumes[body.Volume.Id] = body.Volume
+		writeJSON(w, http.StatusAccepted, body)
+	case req.Method == "GET" && id != "":
+		vol, ok := d.volumes[id]
+		if !ok {
+			writeNotFound(w, "volume "+id)
+			return
+		}
+		writeJSON(w, http.StatusOK, volumeBody{vol})
+	case req.Method == "DELETE" && id != "":
+		if _, ok := d.volumes[id]; !ok {
+			writeNotFound(w, "volume "+id)
+			return
+		}
+		delete(d.volumes, id)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func (d *cinderDouble) attachmentsHandler(serverId string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if serverId == "missing" {
+			writeNotFound(w, "server "+serverId)
+			return
+		}
+		parts := strings.SplitN(req.URL.Path, "/os-volume_attachments", 2)
+		id := strings.TrimPrefix(parts[1], "/")
+		type attachmentBody struct {
+			VolumeAttachment openstack.VolumeAttachment `json:"volumeAttachment"`
+		}
+		switch {
+		case req.Method == "GET" && id == "":
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"volumeAttachments": d.attachments[serverId],
+			})
+		case req.Method == "POST" && id == "":
+			var body attachmentBody
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if _, ok := d.volumes[body.VolumeAttachment.VolumeId]; !ok {
+				writeNotFound(w, "volume "+body.VolumeAttachment.VolumeId)
+				return
+			}
+			attachment := openstack.VolumeAttachment{
+				Id:       body.VolumeAttachment.VolumeId,
+				ServerId: serverId,
+				VolumeId: body.VolumeAttachment.VolumeId,
+				Device:   fmt.Sprintf("/dev/vd%c", 'b'+len(d.attachments[serverId])),
+			}
+			d.attachments[serverId] = append(d.attachments[serverId], attachment)
+			writeJSON(w, http.StatusOK, attachmentBody{attachment})
+		case req.Method == "DELETE" && id != "":
+			attachments := d.attachments[serverId]
+			for i, a := range attachments {
+				if a.Id == id {
+					d.attachments[serverId] = append(attachments[:i], attachments[i+1:]...)
+					w.WriteHeader(http.StatusAccepted)
+					return
+				}
+			}
+			writeNotFound(w, "attachment "+id)
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}
+}
+
+func (s *localServerSuite) TestOpenstackStorageVolumes(c *gc.C) {
+	stor, double := s.openstackStorage(c)
+
+	vol, err := stor.CreateVolume(openstack.CinderVolume{
+		Name:             "juju-testenv-volume-0",
+		Size:             2,
+		VolumeType:       "ssd",
+		AvailabilityZone: "zone-a",
+		Metadata:         map[string]string{"juju-env-uuid": "deadbeef"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vol, jc.DeepEquals, &openstack.CinderVolume{
+		Id:               "vol-0",
+		Name:             "juju-testenv-volume-0",
+		Status:           "available",
+		Size:             2,
+		VolumeType:       "ssd",
+		AvailabilityZone: "zone-a",
+		Metadata:         map[string]string{"juju-env-uuid": "deadbeef"},
+	})
+	c.Assert(double.volumeCount(), gc.Equals, 1)
+
+	got, err := stor.GetVolume("vol-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, vol)
+
+	err = stor.DeleteVolume("vol-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(double.volumeCount(), gc.Equals, 0)
+
+	_, err = stor.GetVolume("vol-0")
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `failed to get volume "vol-0": .*`)
+	err = stor.DeleteVolume("vol-0")
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotFound)
+}
+
+func (s *localServerSuite) TestOpenstackStorageVolumeAttachments(c *gc.C) {
+	stor, _ := s.openstackStorage(c, "server-0", "missing")
+	vol, err := stor.CreateVolume(openstack.CinderVolume{Size: 1})
+	c.Assert(err, jc.ErrorIsNil)
+
+	attachment, err := stor.AttachVolume("server-0", vol.Id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachment, jc.DeepEquals, &openstack.VolumeAttachment{
+		Id:       vol.Id,
+		ServerId: "server-0",
+		VolumeId: vol.Id,
+		Device:   "/dev/vdb",
+	})
+
+	attachments, err := stor.ListVolumeAttachments("server-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, jc.DeepEquals, []openstack.VolumeAttachment{*attachment})
+
+	err = stor.DetachVolume("server-0", attachment.Id)
+	c.Assert(err, jc.ErrorIsNil)
+	attachments, err = stor.ListVolumeAttachments("server-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 0)
+
+	err = stor.DetachVolume("server-0", attachment.Id)
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotFound)
+	_, err = stor.AttachVolume("server-0", "vol-42")
+	c.Assert(err, gc.ErrorMatches, `failed to attach volume "vol-42" to server "server-0": .*`)
+	_, err = stor.ListVolumeAttachments("missing")
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotFound)
+}
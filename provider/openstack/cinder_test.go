@@ -0,0 +1,256 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack_test
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/openstack"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+type cinderSuite struct {
+	testing.BaseSuite
+	stor *fakeOpenstackStorage
+}
+
+var _ = gc.Suite(&cinderSuite{})
+
+func (s *cinderSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.stor = &fakeOpenstackStorage{
+		volumes:     make(map[string]*openstack.CinderVolume),
+		attachments: make(map[string][]openstack.VolumeAttachment),
+	}
+	s.PatchValue(openstack.NewOpenstackStorage, func(*config.Config) (openstack.OpenstackStorage, error) {
+		return s.stor, nil
+	})
+	s.PatchValue(openstack.VolumeAttempt, utils.AttemptStrategy{})
+}
+
+func (s *cinderSuite) volumeSource(c *gc.C, attrs map[string]interface{}) storage.VolumeSource {
+	cfg, err := storage.NewConfig("cinder", openstack.CinderProviderType, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := openstack.CinderProvider().VolumeSource(testing.EnvironConfig(c), cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	return source
+}
+
+func (s *cinderSuite) TestValidateConfig(c *gc.C) {
+	p := openstack.CinderProvider()
+	cfg, err := storage.NewConfig("foo", openstack.CinderProviderType, map[string]interface{}{
+		"volume-type":       "ssd",
+		"availability-zone": "nova",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("foo", openstack.CinderProviderType, map[string]interface{}{
+		"invalid": "config",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), gc.ErrorMatches, `unknown provider config option "invalid"`)
+}
+
+func (s *cinderSuite) TestSupports(c *gc.C) {
+	p := openstack.CinderProvider()
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeEnviron)
+	c.Assert(p.Dynamic(), jc.IsTrue)
+}
+
+func (s *cinderSuite) TestCreateVolumes(c *gc.C) {
+	source := s.volumeSource(c, map[string]interface{}{
+		"volume-type":       "ssd",
+		"availability-zone": "zone-a",
+	})
+	volumes, attachments, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		Size:     1500,
+		Provider: openstack.CinderProviderType,
+		Attributes: map[string]interface{}{
+			"volume-type":       "ssd",
+			"availability-zone": "zone-a",
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 0)
+	c.Assert(volumes, jc.DeepEquals, []storage.Volume{{
+		Tag:        names.NewVolumeTag("0"),
+		VolumeId:   "vol-0",
+		Size:       2048,
+		Persistent: true,
+	}})
+	vol := s.stor.volumes["vol-0"]
+	c.Assert(vol.Name, gc.Equals, "juju-testenv-volume-0")
+	c.Assert(vol.Size, gc.Equals, 2)
+	c.Assert(vol.VolumeType, gc.Equals, "ssd")
+	c.Assert(vol.AvailabilityZone, gc.Equals, "zone-a")
+}
+
+func (s *cinderSuite) TestCreateVolumesCleansUpOnError(c *gc.C) {
+	source := s.volumeSource(c, nil)
+	s.stor.createErrors = map[int]error{1: errors.New("quota exceeded")}
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}, {
+		Tag:  names.NewVolumeTag("1"),
+		Size: 1024,
+	}})
+	c.Assert(err, gc.ErrorMatches, "creating volume 1: quota exceeded")
+	c.Assert(s.stor.volumes, gc.HasLen, 0)
+}
+
+func (s *cinderSuite) TestDescribeAndDestroyVolumes(c *gc.C) {
+	source := s.volumeSource(c, nil)
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	volumes, err := source.DescribeVolumes([]string{"vol-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes, jc.DeepEquals, []storage.Volume{{
+		VolumeId:   "vol-0",
+		Size:       1024,
+		Persistent: true,
+	}})
+
+	errs := source.DestroyVolumes([]string{"vol-0", "vol-missing"})
+	c.Assert(errs, jc.DeepEquals, []error{nil, nil})
+	c.Assert(s.stor.volumes, gc.HasLen, 0)
+}
+
+func (s *cinderSuite) TestAttachAndDetachVolumes(c *gc.C) {
+	source := s.volumeSource(c, nil)
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	params := []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Provider:   openstack.CinderProviderType,
+			Machine:    names.NewMachineTag("1"),
+			InstanceId: instance.Id("server-1"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "vol-0",
+	}}
+	expected := []storage.VolumeAttachment{{
+		Volume:     names.NewVolumeTag("0"),
+		Machine:    names.NewMachineTag("1"),
+		DeviceName: "vdb",
+	}}
+	attachments, err := source.AttachVolumes(params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, jc.DeepEquals, expected)
+
+	// Attaching again returns the existing attachment.
+	attachments, err = source.AttachVolumes(params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, jc.DeepEquals, expected)
+	c.Assert(s.stor.attachments["server-1"], gc.HasLen, 1)
+
+	err = source.DetachVolumes(params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.stor.attachments["server-1"], gc.HasLen, 0)
+
+	// Detaching a volume that is not attached is not an error.
+	err = source.DetachVolumes(params)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *cinderSuite) TestAttachVolumesUnavailable(c *gc.C) {
+	source := s.volumeSource(c, nil)
+	s.stor.volumes["vol-0"] = &openstack.CinderVolume{Id: "vol-0", Status: "error"}
+	_, err := source.AttachVolumes([]storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("1"),
+			InstanceId: instance.Id("server-1"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "vol-0",
+	}})
+	c.Assert(err, gc.ErrorMatches, `attaching vol-0 to server-1: volume "vol-0" has status "error"`)
+}
+
+// fakeOpenstackStorage is an in-memory implementation of the Cinder
+// and Nova volume APIs; the goose test double does not provide Cinder.
+type fakeOpenstackStorage struct {
+	nextId       int
+	createErrors map[int]error
+	volumes      map[string]*openstack.CinderVolume
+	attachments  map[string][]openstack.VolumeAttachment
+}
+
+func (f *fakeOpenstackStorage) CreateVolume(vol openstack.CinderVolume) (*openstack.CinderVolume, error) {
+	id := f.nextId
+	f.nextId++
+	if err := f.createErrors[id]; err != nil {
+		return nil, err
+	}
+	vol.Id = fmt.Sprintf("vol-%d", id)
+	vol.Status = "available"
+	f.volumes[vol.Id] = &vol
+	return &vol, nil
+}
+
+func (f *fakeOpenstackStorage) GetVolume(volumeId string) (*openstack.CinderVolume, error) {
+	vol, ok := f.volumes[volumeId]
+	if !ok {
+		return nil, errors.NotFoundf("volume %q", volumeId)
+	}
+	return vol, nil
+}
+
+func (f *fakeOpenstackStorage) DeleteVolume(volumeId string) error {
+	if _, ok := f.volumes[volumeId]; !ok {
+		return errors.NotFoundf("volume %q", volumeId)
+	}
+	delete(f.volumes, volumeId)
+	return nil
+}
+
+func (f *fakeOpenstackStorage) ListVolumeAttachments(serverId string) ([]openstack.VolumeAttachment, error) {
+	return f.attachments[serverId], nil
+}
+
+func (f *fakeOpenstackStorage) AttachVolume(serverId, volumeId string) (*openstack.VolumeAttachment, error) {
+	attachments := f.attachments[serverId]
+	attachment := openstack.VolumeAttachment{
+		Id:       volumeId,
+		ServerId: serverId,
+		VolumeId: volumeId,
+		Device:   fmt.Sprintf("/dev/vd%c", 'b'+len(attachments)),
+	}
+	f.attachments[serverId] = append(attachments, attachment)
+	f.volumes[volumeId].Status = "in-use"
+	return &attachment, nil
+}
+
+func (f *fakeOpenstackStorage) DetachVolume(serverId, attachmentId string) error {
+	attachments := f.attachments[serverId]
+	for i, a := range attachments {
+		if a.Id == attachmentId {
+			f.attachments[serverId] = append(attachments[:i], attachments[i+1:]...)
+			f.volumes[a.VolumeId].Status = "available"
+			return nil
+		}
+	}
+	return errors.NotFoundf("attachment %q", attachmentId)
+}
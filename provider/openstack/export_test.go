@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/environs/storage"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	jujustorage "github.com/juju/juju/storage"
 )
 
 // This provides the content for code accessing test:///... URLs. This allows
@@ -68,7 +69,13 @@ func InstanceFloatingIP(inst instance.Instance) *nova.FloatingIP {
 	return inst.(*openstackInstance).floatingIP
 }
 
+func CinderProvider() jujustorage.Provider {
+	return &cinderProvider{}
+}
+
 var (
+	NewOpenstackStorage         = &newOpenstackStorage
+	VolumeAttempt               = &volumeAttempt
 	NovaListAvailabilityZones   = &novaListAvailabilityZones
 	AvailabilityZoneAllocations = &availabilityZoneAllocations
 )
@@ -350,8 +357,9 @@ func ResolveNetwork(e environs.Environ, networkName string) (string, error) {
 	return e.(*environ).resolveNetwork(networkName)
 }
 
-var PortsToRuleInfo = portsToRuleInfo
+var RulesToRuleInfo = rulesToRuleInfo
 var RuleMatchesPortRange = ruleMatchesPortRange
+var RuleMatchesIngressRule = ruleMatchesIngressRule
 
 var MakeServiceURL = &makeServiceURL
 var ProviderInstance = providerInstance
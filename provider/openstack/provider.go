@@ -500,42 +500,61 @@ func convertNovaAddresses(publicIP string, addresses map[string][]nova.IPAddress
 // TODO: following 30 lines nearly verbatim from environs/ec2
 
 func (inst *openstackInstance) OpenPorts(machineId string, ports []network.PortRange) error {
+	return inst.OpenIngressRules(machineId, network.NewIngressRules(ports))
+}
+
+func (inst *openstackInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+	return inst.CloseIngressRules(machineId, network.NewIngressRules(ports))
+}
+
+func (inst *openstackInstance) Ports(machineId string) ([]network.PortRange, error) {
+	if inst.e.Config().FirewallMode() != config.FwInstance {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
+			inst.e.Config().FirewallMode())
+	}
+	name := inst.e.machineGroupName(machineId)
+	portRanges, err := inst.e.portsInGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	return portRanges, nil
+}
+
+// OpenIngressRules is specified on instance.IngressRulesInstance.
+func (inst *openstackInstance) OpenIngressRules(machineId string, rules []network.IngressRule) error {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
-	if err := inst.e.openPortsInGroup(name, ports); err != nil {
+	if err := inst.e.openRulesInGroup(name, rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in security group %s: %v", name, ports)
+	logger.Infof("opened ports in security group %s: %v", name, rules)
 	return nil
 }
 
-func (inst *openstackInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+// CloseIngressRules is specified on instance.IngressRulesInstance.
+func (inst *openstackInstance) CloseIngressRules(machineId string, rules []network.IngressRule) error {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
-	if err := inst.e.closePortsInGroup(name, ports); err != nil {
+	if err := inst.e.closeRulesInGroup(name, rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in security group %s: %v", name, ports)
+	logger.Infof("closed ports in security group %s: %v", name, rules)
 	return nil
 }
 
-func (inst *openstackInstance) Ports(machineId string) ([]network.PortRange, error) {
+// IngressRules is specified on instance.IngressRulesInstance.
+func (inst *openstackInstance) IngressRules(machineId string) ([]network.IngressRule, error) {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
 			inst.e.Config().FirewallMode())
 	}
-	name := inst.e.machineGroupName(machineId)
-	portRanges, err := inst.e.portsInGroup(name)
-	if err != nil {
-		return nil, err
-	}
-	return portRanges, nil
+	return inst.e.rulesInGroup(inst.e.machineGroupName(machineId))
 }
 
 func (e *environ) ecfg() *environConfig {
@@ -575,6 +594,8 @@ func (e *environ) SupportedArchitectures() ([]string, error) {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.InstanceLifecycle,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -1309,29 +1330,28 @@ func (e *environ) machinesFilter() *nova.Filter {
 	return filter
 }
 
-// portsToRuleInfo maps port ranges to nova rules
-func portsToRuleInfo(groupId string, ports []network.PortRange) []nova.RuleInfo {
-	rules := make([]nova.RuleInfo, len(ports))
-	for i, portRange := range ports {
-		rules[i] = nova.RuleInfo{
+// rulesToRuleInfo maps ingress rules to nova rules
+func rulesToRuleInfo(groupId string, rules []network.IngressRule) []nova.RuleInfo {
+	ruleInfos := make([]nova.RuleInfo, len(rules))
+	for i, rule := range rules {
+		ruleInfos[i] = nova.RuleInfo{
 			ParentGroupId: groupId,
-			FromPort:      portRange.FromPort,
-			ToPort:        portRange.ToPort,
-			IPProtocol:    portRange.Protocol,
-			Cidr:          "0.0.0.0/0",
+			FromPort:      rule.FromPort,
+			ToPort:        rule.ToPort,
+			IPProtocol:    rule.Protocol,
+			Cidr:          rule.SourceCIDR,
 		}
 	}
-	return rules
+	return ruleInfos
 }
 
-func (e *environ) openPortsInGroup(name string, portRanges []network.PortRange) error {
+func (e *environ) openRulesInGroup(name string, rules []network.IngressRule) error {
 	novaclient := e.nova()
 	group, err := novaclient.SecurityGroupByName(name)
 	if err != nil {
 		return err
 	}
-	rules := portsToRuleInfo(group.Id, portRanges)
-	for _, rule := range rules {
+	for _, rule := range rulesToRuleInfo(group.Id, rules) {
 		_, err := novaclient.CreateSecurityGroupRule(rule)
 		if err != nil {
 			// TODO: if err is not rule already exists, raise?
@@ -1351,8 +1371,15 @@ func ruleMatchesPortRange(rule nova.SecurityGroupRule, portRange network.PortRan
 		*rule.ToPort == portRange.ToPort
 }
 
-func (e *environ) closePortsInGroup(name string, portRanges []network.PortRange) error {
-	if len(portRanges) == 0 {
+// ruleMatchesIngressRule checks if supplied nova security group rule
+// matches the ingress rule's port range and source CIDR.
+func ruleMatchesIngressRule(rule nova.SecurityGroupRule, ingressRule network.IngressRule) bool {
+	return ruleMatchesPortRange(rule, ingressRule.PortRange) &&
+		rule.IPRange["cidr"] == ingressRule.SourceCIDR
+}
+
+func (e *environ) closeRulesInGroup(name string, rules []network.IngressRule) error {
+	if len(rules) == 0 {
 		return nil
 	}
 	novaclient := e.nova()
@@ -1361,9 +1388,9 @@ func (e *environ) closePortsInGroup(name string, portRanges []network.PortRange)
 		return err
 	}
 	// TODO: Hey look ma, it's quadratic
-	for _, portRange := range portRanges {
+	for _, rule := range rules {
 		for _, p := range (*group).Rules {
-			if !ruleMatchesPortRange(p, portRange) {
+			if !ruleMatchesIngressRule(p, rule) {
 				continue
 			}
 			err := novaclient.DeleteSecurityGroupRule(p.Id)
@@ -1376,54 +1403,95 @@ func (e *environ) closePortsInGroup(name string, portRanges []network.PortRange)
 	return nil
 }
 
-func (e *environ) portsInGroup(name string) (portRanges []network.PortRange, err error) {
+func (e *environ) rulesInGroup(name string) (rules []network.IngressRule, err error) {
 	group, err := e.nova().SecurityGroupByName(name)
 	if err != nil {
 		return nil, err
 	}
 	for _, p := range (*group).Rules {
-		portRanges = append(portRanges, network.PortRange{
-			Protocol: *p.IPProtocol,
-			FromPort: *p.FromPort,
-			ToPort:   *p.ToPort,
+		cidr := p.IPRange["cidr"]
+		if cidr == "" {
+			// Rules granting access to other security
+			// groups are not ingress rules.
+			continue
+		}
+		rules = append(rules, network.IngressRule{
+			PortRange: network.PortRange{
+				Protocol: *p.IPProtocol,
+				FromPort: *p.FromPort,
+				ToPort:   *p.ToPort,
+			},
+			SourceCIDR: cidr,
 		})
 	}
-	network.SortPortRanges(portRanges)
+	network.SortIngressRules(rules)
+	return rules, nil
+}
+
+func (e *environ) portsInGroup(name string) (portRanges []network.PortRange, err error) {
+	rules, err := e.rulesInGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.SourceCIDR == network.AnySourceCIDR {
+			portRanges = append(portRanges, rule.PortRange)
+		}
+	}
 	return portRanges, nil
 }
 
+func (e *environ) OpenPorts(ports []network.PortRange) error {
+	return e.OpenIngressRules(network.NewIngressRules(ports))
+}
+
+func (e *environ) ClosePorts(ports []network.PortRange) error {
+	return e.CloseIngressRules(network.NewIngressRules(ports))
+}
+
+func (e *environ) Ports() ([]network.PortRange, error) {
+	if e.Config().FirewallMode() != config.FwGlobal {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment",
+			e.Config().FirewallMode())
+	}
+	return e.portsInGroup(e.globalGroupName())
+}
+
 // TODO: following 30 lines nearly verbatim from environs/ec2
 
-func (e *environ) OpenPorts(ports []network.PortRange) error {
+// OpenIngressRules is specified on environs.IngressRules.
+func (e *environ) OpenIngressRules(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on environment",
 			e.Config().FirewallMode())
 	}
-	if err := e.openPortsInGroup(e.globalGroupName(), ports); err != nil {
+	if err := e.openRulesInGroup(e.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in global group: %v", ports)
+	logger.Infof("opened ports in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) ClosePorts(ports []network.PortRange) error {
+// CloseIngressRules is specified on environs.IngressRules.
+func (e *environ) CloseIngressRules(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on environment",
 			e.Config().FirewallMode())
 	}
-	if err := e.closePortsInGroup(e.globalGroupName(), ports); err != nil {
+	if err := e.closeRulesInGroup(e.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in global group: %v", ports)
+	logger.Infof("closed ports in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) Ports() ([]network.PortRange, error) {
+// IngressRules is specified on environs.IngressRules.
+func (e *environ) IngressRules() ([]network.IngressRule, error) {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment",
 			e.Config().FirewallMode())
 	}
-	return e.portsInGroup(e.globalGroupName())
+	return e.rulesInGroup(e.globalGroupName())
 }
 
 func (e *environ) Provider() environs.EnvironProvider {
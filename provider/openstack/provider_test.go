@@ -211,12 +211,13 @@ func (t *localTests) TestGetServerAddresses(c *gc.C) {
 	}
 }
 
-func (*localTests) TestPortsToRuleInfo(c *gc.C) {
+func (*localTests) TestRulesToRuleInfo(c *gc.C) {
 	groupId := "groupid"
 	testCases := []struct {
-		about    string
-		ports    []network.PortRange
-		expected []nova.RuleInfo
+		about       string
+		ports       []network.PortRange
+		sourceCIDRs []string
+		expected    []nova.RuleInfo
 	}{{
 		about: "single port",
 		ports: []network.PortRange{{
@@ -269,11 +270,32 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			Cidr:          "0.0.0.0/0",
 			ParentGroupId: groupId,
 		}},
+	}, {
+		about: "source CIDRs",
+		ports: []network.PortRange{{
+			FromPort: 3306,
+			ToPort:   3306,
+			Protocol: "tcp",
+		}},
+		sourceCIDRs: []string{"10.0.0.0/8", "203.0.113.4/32"},
+		expected: []nova.RuleInfo{{
+			IPProtocol:    "tcp",
+			FromPort:      3306,
+			ToPort:        3306,
+			Cidr:          "10.0.0.0/8",
+			ParentGroupId: groupId,
+		}, {
+			IPProtocol:    "tcp",
+			FromPort:      3306,
+			ToPort:        3306,
+			Cidr:          "203.0.113.4/32",
+			ParentGroupId: groupId,
+		}},
 	}}
 
 	for i, t := range testCases {
 		c.Logf("test %d: %s", i, t.about)
-		rules := openstack.PortsToRuleInfo(groupId, t.ports)
+		rules := openstack.RulesToRuleInfo(groupId, network.NewIngressRules(t.ports, t.sourceCIDRs...))
 		c.Check(len(rules), gc.Equals, len(t.expected))
 		c.Check(rules, gc.DeepEquals, t.expected)
 	}
@@ -349,6 +371,20 @@ func (*localTests) TestRuleMatchesPortRange(c *gc.C) {
 	}
 }
 
+func (*localTests) TestRuleMatchesIngressRule(c *gc.C) {
+	proto_tcp := "tcp"
+	port_80 := 80
+	rule := nova.SecurityGroupRule{
+		IPProtocol: &proto_tcp,
+		FromPort:   &port_80,
+		ToPort:     &port_80,
+		IPRange:    map[string]string{"cidr": "10.0.0.0/8"},
+	}
+	portRange := network.PortRange{80, 80, "tcp"}
+	c.Check(openstack.RuleMatchesIngressRule(rule, network.IngressRule{portRange, "10.0.0.0/8"}), jc.IsTrue)
+	c.Check(openstack.RuleMatchesIngressRule(rule, network.IngressRule{portRange, network.AnySourceCIDR}), jc.IsFalse)
+}
+
 func (t *localTests) TestPrepareSetsControlBucket(c *gc.C) {
 	attrs := testing.FakeConfig().Merge(testing.Attrs{
 		"type": "openstack",
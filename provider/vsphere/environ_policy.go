@@ -71,6 +71,8 @@ func (env *environ) lookupArchitectures() ([]string, error) {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.Networks,
+	constraints.InstanceLifecycle,
+	constraints.MaxPrice,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
@@ -297,6 +297,7 @@ func (st *State) addMachineOps(template MachineTemplate) (*machineDoc, []txn.Op,
 				CpuPower:   template.HardwareCharacteristics.CpuPower,
 				Tags:       template.HardwareCharacteristics.Tags,
 				AvailZone:  template.HardwareCharacteristics.AvailabilityZone,
+				Lifecycle:  template.HardwareCharacteristics.InstanceLifecycle,
 			},
 		})
 	}
@@ -511,6 +512,28 @@ func (st *State) insertNewMachineOps(mdoc *machineDoc, template MachineTemplate)
 		})
 	}
 
+	// Attach existing filesystems and volumes.
+	for tag, params := range template.FilesystemAttachments {
+		filesystemOps = append(filesystemOps, txn.Op{
+			C:      filesystemsC,
+			Id:     tag.Id(),
+			Assert: isAliveDoc,
+		})
+		fsAttachments = append(fsAttachments, filesystemAttachmentTemplate{
+			tag, params,
+		})
+	}
+	for tag, params := range template.VolumeAttachments {
+		volumeOps = append(volumeOps, txn.Op{
+			C:      volumesC,
+			Id:     tag.Id(),
+			Assert: isAliveDoc,
+		})
+		volumeAttachments = append(volumeAttachments, volumeAttachmentTemplate{
+			tag, params,
+		})
+	}
+
 	if len(fsAttachments) > 0 {
 		attachmentOps := createMachineFilesystemAttachmentsOps(mdoc.Id, fsAttachments)
 		prereqOps = append(prereqOps, filesystemOps...)
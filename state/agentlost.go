@@ -0,0 +1,218 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// degradedKey is the status data key marking a service status that was
+// set because the agents of some of the service's units are lost.
+const degradedKey = "agent-lost-degraded"
+
+// SetAgentLost records that the machine's agent has failed to signal
+// its presence for longer than the environment's grace period. The
+// status the agent held is kept in the status history, and is restored
+// by ClearAgentLost.
+func (m *Machine) SetAgentLost(info string) error {
+	err := setStatusLost(m.st, m.globalKey(), info, txn.Op{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: notDeadDoc,
+	})
+	return errors.Annotatef(err, "cannot mark agent of machine %q lost", m)
+}
+
+// ClearAgentLost restores the status the machine's agent held before
+// it was marked lost. It does nothing if the agent is not lost.
+func (m *Machine) ClearAgentLost() error {
+	err := restoreStatusBeforeLost(m.st, m.globalKey(), StatusStarted, txn.Op{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: notDeadDoc,
+	})
+	return errors.Annotatef(err, "cannot clear lost agent of machine %q", m)
+}
+
+// SetAgentLost records that the unit's agent has failed to signal
+// its presence for longer than the environment's grace period. The
+// status the agent held is kept in the status history, and is restored
+// by ClearAgentLost.
+func (u *Unit) SetAgentLost(info string) error {
+	err := setStatusLost(u.st, u.globalAgentKey(), info, txn.Op{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: notDeadDoc,
+	})
+	return errors.Annotatef(err, "cannot mark agent of unit %q lost", u)
+}
+
+// ClearAgentLost restores the status the unit's agent held before
+// it was marked lost. It does nothing if the agent is not lost.
+func (u *Unit) ClearAgentLost() error {
+	err := restoreStatusBeforeLost(u.st, u.globalAgentKey(), StatusIdle, txn.Op{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: notDeadDoc,
+	})
+	return errors.Annotatef(err, "cannot clear lost agent of unit %q", u)
+}
+
+// SetDegraded sets the status of the service to blocked, with the
+// given message, to draw attention to units whose agents are lost.
+// Any status previously set for the service is kept in the status
+// history, and is restored by ClearDegraded.
+func (s *Service) SetDegraded(info string) error {
+	oldDoc, err := getStatus(s.st, s.globalKey())
+	exists := true
+	if IsStatusNotFound(err) {
+		exists = false
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if oldDoc.StatusData[degradedKey] == true && oldDoc.StatusInfo == info {
+		return nil
+	}
+	now := nowToTheSecond()
+	doc := statusDoc{
+		EnvUUID:    s.st.EnvironUUID(),
+		Status:     StatusBlocked,
+		StatusInfo: info,
+		StatusData: map[string]interface{}{degradedKey: true},
+		Updated:    &now,
+	}
+	statusOp := updateStatusOp(s.st, s.globalKey(), doc)
+	if !exists {
+		statusOp = createStatusOp(s.st, s.globalKey(), doc)
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+	}, statusOp}
+	if err := s.st.runTransaction(ops); err != nil {
+		return errors.Errorf("cannot mark service %q degraded: %v", s, onAbort(err, errNotAlive))
+	}
+	// Only the status that preceded degradation is recorded, so
+	// that it can be restored.
+	if exists && oldDoc.StatusData[degradedKey] != true {
+		if err := updateStatusHistory(oldDoc, s.globalKey(), s.st); err != nil {
+			logger.Errorf("could not record status history before degrading service %q: %v", s, err)
+		}
+	}
+	return nil
+}
+
+// IsDegraded reports whether the service's status was set by SetDegraded.
+func (s *Service) IsDegraded() (bool, error) {
+	doc, err := getStatus(s.st, s.globalKey())
+	if IsStatusNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return doc.StatusData[degradedKey] == true, nil
+}
+
+// ClearDegraded restores the status the service held before it was
+// degraded; if no status was set, the service's status is once again
+// derived from its units. It does nothing if the service's status was
+// not set by SetDegraded, or has since been replaced.
+func (s *Service) ClearDegraded() error {
+	degraded, err := s.IsDegraded()
+	if err != nil || !degraded {
+		return errors.Trace(err)
+	}
+	history, err := s.StatusHistory(1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var statusOp txn.Op
+	if len(history) == 0 {
+		statusOp = removeStatusOp(s.st, s.globalKey())
+	} else {
+		now := nowToTheSecond()
+		statusOp = updateStatusOp(s.st, s.globalKey(), statusDoc{
+			EnvUUID:    s.st.EnvironUUID(),
+			Status:     history[0].Status,
+			StatusInfo: history[0].Message,
+			StatusData: history[0].Data,
+			Updated:    &now,
+		})
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+	}, statusOp}
+	if err := s.st.runTransaction(ops); err != nil {
+		return errors.Errorf("cannot clear degraded service %q: %v", s, onAbort(err, errNotAlive))
+	}
+	return nil
+}
+
+// setStatusLost sets the status with the given global key to lost,
+// recording the previous status in the status history. The assert op
+// is added to the transaction to ensure that the entity is not dead.
+func setStatusLost(st *State, globalKey, info string, assert txn.Op) error {
+	oldDoc, err := getStatus(st, globalKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if oldDoc.Status == StatusLost {
+		return nil
+	}
+	now := nowToTheSecond()
+	doc := statusDoc{
+		EnvUUID:    st.EnvironUUID(),
+		Status:     StatusLost,
+		StatusInfo: info,
+		Updated:    &now,
+	}
+	ops := []txn.Op{assert, updateStatusOp(st, globalKey, doc)}
+	if err := st.runTransaction(ops); err != nil {
+		return onAbort(err, ErrDead)
+	}
+	if err := updateStatusHistory(oldDoc, globalKey, st); err != nil {
+		logger.Errorf("could not record status history before change to %q: %v", StatusLost, err)
+	}
+	return nil
+}
+
+// restoreStatusBeforeLost replaces a lost status with the given global
+// key with the status that preceded it, or the given fallback status if
+// no history was recorded.
+func restoreStatusBeforeLost(st *State, globalKey string, fallback Status, assert txn.Op) error {
+	oldDoc, err := getStatus(st, globalKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if oldDoc.Status != StatusLost {
+		return nil
+	}
+	now := nowToTheSecond()
+	doc := statusDoc{
+		EnvUUID: st.EnvironUUID(),
+		Status:  fallback,
+		Updated: &now,
+	}
+	history, err := statusHistory(1, globalKey, st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(history) > 0 && history[0].Status != StatusLost {
+		doc.Status = history[0].Status
+		doc.StatusInfo = history[0].Message
+		doc.StatusData = history[0].Data
+	}
+	ops := []txn.Op{assert, updateStatusOp(st, globalKey, doc)}
+	if err := st.runTransaction(ops); err != nil {
+		return onAbort(err, ErrDead)
+	}
+	if err := updateStatusHistory(oldDoc, globalKey, st); err != nil {
+		logger.Errorf("could not record status history before change from %q: %v", StatusLost, err)
+	}
+	return nil
+}
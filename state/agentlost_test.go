@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type AgentLostSuite struct {
+	ConnSuite
+	service *state.Service
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&AgentLostSuite{})
+
+func (s *AgentLostSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit = unit
+}
+
+func (s *AgentLostSuite) TestMachineAgentLost(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.SetAgentLost("gone")
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := machine.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusLost)
+	c.Assert(status.Message, gc.Equals, "gone")
+	history, err := machine.StatusHistory(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history[0].Status, gc.Equals, state.StatusStarted)
+
+	err = machine.ClearAgentLost()
+	c.Assert(err, jc.ErrorIsNil)
+	status, err = machine.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusStarted)
+	history, err = machine.StatusHistory(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history[0].Status, gc.Equals, state.StatusLost)
+}
+
+func (s *AgentLostSuite) TestUnitAgentLost(c *gc.C) {
+	err := s.unit.SetAgentStatus(state.StatusExecuting, "running config-changed hook", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.SetAgentLost("gone")
+	c.Assert(err, jc.ErrorIsNil)
+	// Marking an agent lost twice has no further effect.
+	err = s.unit.SetAgentLost("gone")
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := s.unit.AgentStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusLost)
+
+	err = s.unit.ClearAgentLost()
+	c.Assert(err, jc.ErrorIsNil)
+	status, err = s.unit.AgentStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusExecuting)
+	c.Assert(status.Message, gc.Equals, "running config-changed hook")
+}
+
+func (s *AgentLostSuite) TestClearAgentLostNotLost(c *gc.C) {
+	err := s.unit.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.ClearAgentLost()
+	c.Assert(err, jc.ErrorIsNil)
+	history, err := s.unit.Agent().(*state.UnitAgent).StatusHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, h := range history {
+		c.Check(h.Status, gc.Not(gc.Equals), state.StatusLost)
+	}
+}
+
+func (s *AgentLostSuite) TestServiceDegradedDerived(c *gc.C) {
+	err := s.service.SetDegraded("degraded: agents lost for dummy/0")
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := s.service.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusBlocked)
+	c.Assert(status.Message, gc.Equals, "degraded: agents lost for dummy/0")
+	degraded, err := s.service.IsDegraded()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(degraded, jc.IsTrue)
+
+	err = s.service.ClearDegraded()
+	c.Assert(err, jc.ErrorIsNil)
+	degraded, err = s.service.IsDegraded()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(degraded, jc.IsFalse)
+	status, err = s.service.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Not(gc.Equals), state.StatusBlocked)
+}
+
+func (s *AgentLostSuite) TestServiceDegradedRestoresStatus(c *gc.C) {
+	err := s.service.SetStatus(state.StatusActive, "serving", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetDegraded("degraded: agents lost for dummy/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetDegraded("degraded: agents lost for dummy/0, dummy/1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.service.ClearDegraded()
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := s.service.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusActive)
+	c.Assert(status.Message, gc.Equals, "serving")
+}
+
+func (s *AgentLostSuite) TestClearDegradedKeepsNewerStatus(c *gc.C) {
+	err := s.service.SetDegraded("degraded: agents lost for dummy/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetStatus(state.StatusMaintenance, "upgrading", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.service.ClearDegraded()
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := s.service.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusMaintenance)
+}
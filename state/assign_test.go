@@ -14,6 +14,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage/poolmanager"
@@ -373,6 +374,83 @@ func (s *AssignSuite) TestAssignUnitToNewMachine(c *gc.C) {
 	s.assertAssignedUnit(c, unit)
 }
 
+// zonePrechecker is a state.Prechecker that rejects placement in
+// the unavailable zone.
+type zonePrechecker struct {
+	unavailable string
+}
+
+func (p *zonePrechecker) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+	if placement == "zone="+p.unavailable {
+		return fmt.Errorf("availability zone %q is unavailable", p.unavailable)
+	}
+	return nil
+}
+
+func (s *AssignSuite) assignUnitToNewMachineInZones(c *gc.C, zones ...string) (*state.Machine, error) {
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	if err := unit.AssignToNewMachineInZones(zones); err != nil {
+		return nil, err
+	}
+	machineId := s.assertAssignedUnit(c, unit)
+	machine, err := s.State.Machine(state.TopParentId(machineId))
+	c.Assert(err, jc.ErrorIsNil)
+	return machine, nil
+}
+
+func (s *AssignSuite) TestAssignUnitToNewMachineInZones(c *gc.C) {
+	prechecker := &zonePrechecker{}
+	s.policy.GetPrechecker = func(*config.Config) (state.Prechecker, error) {
+		return prechecker, nil
+	}
+
+	// Units are spread across the zones, preferring those listed first.
+	for i, expect := range []string{"zone=a", "zone=b", "zone=a"} {
+		c.Logf("unit %d", i)
+		machine, err := s.assignUnitToNewMachineInZones(c, "a", "b")
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(machine.Placement(), gc.Equals, expect)
+	}
+
+	// Zones rejected by the provider are skipped.
+	prechecker.unavailable = "b"
+	machine, err := s.assignUnitToNewMachineInZones(c, "a", "b")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Placement(), gc.Equals, "zone=a")
+
+	_, err = s.assignUnitToNewMachineInZones(c, "b")
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/4" to new machine in zones b: availability zone "b" is unavailable`)
+}
+
+func (s *AssignSuite) TestAssignUnitToNewMachineInZonesContainerConstraint(c *gc.C) {
+	err := s.wordpress.SetConstraints(constraints.MustParse("container=lxc"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToNewMachineInZones([]string{"a"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The zone applies to the container's new host machine.
+	machineId := s.assertAssignedUnit(c, unit)
+	container, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(container.Placement(), gc.Equals, "")
+	host, err := s.State.Machine(state.ParentId(machineId))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(host.Placement(), gc.Equals, "zone=a")
+}
+
+func (s *AssignSuite) TestAssignUnitToNewMachineInZonesAntiAffinity(c *gc.C) {
+	err := s.wordpress.SetPlacementPolicy(state.PlacementPolicy{AntiAffinity: state.AntiAffinityZone})
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.assignUnitToNewMachineInZones(c, "a")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Placement(), gc.Equals, "zone=a")
+	_, err = s.assignUnitToNewMachineInZones(c, "a")
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/1" to new machine in zones a: placement policy forbids units of service "wordpress" in the same availability zone, but zone "a" hosts unit "wordpress/0"`)
+}
+
 func (s *AssignSuite) assertAssignUnitToNewMachineContainerConstraint(c *gc.C) {
 	unit, err := s.wordpress.AddUnit()
 	c.Assert(err, jc.ErrorIsNil)
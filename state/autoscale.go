@@ -0,0 +1,361 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// AutoscalePolicy defines how the number of units of a service is
+// adjusted according to a metric reported by its units.
+type AutoscalePolicy struct {
+	// Metric is the name of the metric, declared by the service's
+	// charm, that the policy tracks.
+	Metric string
+
+	// Threshold is the value of the metric, averaged across the
+	// service's units, that the policy aims to keep each unit at.
+	// Units are added while the average is above the threshold, and
+	// removed while a unit fewer would keep it at or below it.
+	Threshold float64
+
+	// Window is the period over which the metric is averaged.
+	Window time.Duration
+
+	// MinUnits and MaxUnits bound the number of units the policy
+	// will scale the service to.
+	MinUnits int
+	MaxUnits int
+
+	// Cooldown is the minimum time between scaling decisions.
+	Cooldown time.Duration
+}
+
+// Validate returns an error if the policy is not valid.
+func (p AutoscalePolicy) Validate() error {
+	if p.Metric == "" {
+		return errors.NotValidf("empty metric")
+	}
+	if p.Threshold <= 0 {
+		return errors.NotValidf("threshold %v", p.Threshold)
+	}
+	if p.Window <= 0 {
+		return errors.NotValidf("window %v", p.Window)
+	}
+	if p.Cooldown < 0 {
+		return errors.NotValidf("cooldown %v", p.Cooldown)
+	}
+	if p.MinUnits < 0 {
+		return errors.NotValidf("minimum units %d", p.MinUnits)
+	}
+	if p.MaxUnits < 1 || p.MaxUnits < p.MinUnits {
+		return errors.NotValidf("maximum units %d", p.MaxUnits)
+	}
+	return nil
+}
+
+// autoscalePolicyDoc holds the autoscale policy of a service. It is
+// removed along with the service.
+type autoscalePolicyDoc struct {
+	DocID       string        `bson:"_id"`
+	EnvUUID     string        `bson:"env-uuid"`
+	ServiceName string        `bson:"servicename"`
+	Metric      string        `bson:"metric"`
+	Threshold   float64       `bson:"threshold"`
+	Window      time.Duration `bson:"window"`
+	MinUnits    int           `bson:"minunits"`
+	MaxUnits    int           `bson:"maxunits"`
+	Cooldown    time.Duration `bson:"cooldown"`
+}
+
+func (doc *autoscalePolicyDoc) policy() AutoscalePolicy {
+	return AutoscalePolicy{
+		Metric:    doc.Metric,
+		Threshold: doc.Threshold,
+		Window:    doc.Window,
+		MinUnits:  doc.MinUnits,
+		MaxUnits:  doc.MaxUnits,
+		Cooldown:  doc.Cooldown,
+	}
+}
+
+// SetAutoscalePolicy sets the policy by which the number of units of
+// the service is adjusted, replacing any existing policy.
+func (s *Service) SetAutoscalePolicy(p AutoscalePolicy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set autoscale policy for service %q", s)
+	if err := p.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if s.doc.Subordinate {
+		return errors.Errorf("service is subordinate")
+	}
+	ch, _, err := s.Charm()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if metrics := ch.Metrics(); metrics == nil {
+		return errors.Errorf("charm %q does not declare any metrics", ch.URL())
+	} else if _, ok := metrics.Metrics[p.Metric]; !ok {
+		return errors.Errorf("charm %q does not declare metric %q", ch.URL(), p.Metric)
+	}
+
+	docID := s.st.docID(s.doc.Name)
+	doc := autoscalePolicyDoc{
+		DocID:       docID,
+		EnvUUID:     s.st.EnvironUUID(),
+		ServiceName: s.doc.Name,
+		Metric:      p.Metric,
+		Threshold:   p.Threshold,
+		Window:      p.Window,
+		MinUnits:    p.MinUnits,
+		MaxUnits:    p.MaxUnits,
+		Cooldown:    p.Cooldown,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if alive, err := isAlive(s.st, servicesC, s.doc.DocID); err != nil {
+				return nil, errors.Trace(err)
+			} else if !alive {
+				return nil, errors.New("service is no longer alive")
+			}
+		}
+		ops := []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		if _, err := s.AutoscalePolicy(); errors.IsNotFound(err) {
+			return append(ops, txn.Op{
+				C:      autoscalePoliciesC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:      autoscalePoliciesC,
+			Id:     docID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"metric", doc.Metric},
+				{"threshold", doc.Threshold},
+				{"window", doc.Window},
+				{"minunits", doc.MinUnits},
+				{"maxunits", doc.MaxUnits},
+				{"cooldown", doc.Cooldown},
+			}}},
+		}), nil
+	}
+	return s.st.run(buildTxn)
+}
+
+// AutoscalePolicy returns the service's autoscale policy. It returns
+// an error that satisfies errors.IsNotFound if the service is not
+// autoscaled.
+func (s *Service) AutoscalePolicy() (AutoscalePolicy, error) {
+	policies, closer := s.st.getCollection(autoscalePoliciesC)
+	defer closer()
+
+	var doc autoscalePolicyDoc
+	err := policies.FindId(s.doc.Name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return AutoscalePolicy{}, errors.NotFoundf("autoscale policy for service %q", s)
+	} else if err != nil {
+		return AutoscalePolicy{}, errors.Annotatef(err, "cannot get autoscale policy for service %q", s)
+	}
+	return doc.policy(), nil
+}
+
+// ClearAutoscalePolicy removes the service's autoscale policy, if it
+// has one. The history of scaling decisions is kept.
+func (s *Service) ClearAutoscalePolicy() error {
+	ops := []txn.Op{removeAutoscalePolicyOp(s.st, s.doc.Name)}
+	err := s.st.runTransaction(ops)
+	return errors.Annotatef(err, "cannot clear autoscale policy for service %q", s)
+}
+
+// removeAutoscalePolicyOp returns the operation required to remove the
+// named service's autoscale policy, if it exists.
+func removeAutoscalePolicyOp(st *State, serviceName string) txn.Op {
+	return txn.Op{
+		C:      autoscalePoliciesC,
+		Id:     st.docID(serviceName),
+		Remove: true,
+	}
+}
+
+// AutoscalePolicies returns the autoscale policies of all services in
+// the environment, keyed by service name.
+func (st *State) AutoscalePolicies() (map[string]AutoscalePolicy, error) {
+	policies, closer := st.getCollection(autoscalePoliciesC)
+	defer closer()
+
+	var docs []autoscalePolicyDoc
+	if err := policies.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get autoscale policies")
+	}
+	result := make(map[string]AutoscalePolicy)
+	for _, doc := range docs {
+		result[doc.ServiceName] = doc.policy()
+	}
+	return result, nil
+}
+
+// UnitMetricAverages returns the mean value of the named metric, as
+// reported by each of the service's units since the given time, keyed
+// by unit name. Units that have not reported the metric, or whose
+// values are not numeric, are omitted.
+func (s *Service) UnitMetricAverages(metric string, since time.Time) (map[string]float64, error) {
+	metrics, closer := s.st.getCollection(metricsC)
+	defer closer()
+
+	// The metrics collection is not environment-aware.
+	sel := bson.D{
+		{"env-uuid", s.st.EnvironUUID()},
+		{"unit", bson.D{{"$regex", "^" + s.doc.Name + "/"}}},
+		{"metrics", bson.D{{"$elemMatch", bson.D{
+			{"key", metric},
+			{"time", bson.D{{"$gte", since}}},
+		}}}},
+	}
+	var doc metricBatchDoc
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	iter := metrics.Find(sel).Iter()
+	for iter.Next(&doc) {
+		for _, m := range doc.Metrics {
+			if m.Key != metric || m.Time.Before(since) {
+				continue
+			}
+			value, err := strconv.ParseFloat(m.Value, 64)
+			if err != nil {
+				continue
+			}
+			sums[doc.Unit] += value
+			counts[doc.Unit]++
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, errors.Annotatef(err, "cannot get metrics for service %q", s)
+	}
+	result := make(map[string]float64)
+	for unit, sum := range sums {
+		result[unit] = sum / float64(counts[unit])
+	}
+	return result, nil
+}
+
+// AutoscaleDecision records a change to the number of units of a
+// service made by its autoscale policy.
+type AutoscaleDecision struct {
+	// Time is when the decision was made.
+	Time time.Time
+
+	// Metric is the metric tracked by the policy, and Value its
+	// average across the service's units.
+	Metric string
+	Value  float64
+
+	// FromUnits and ToUnits hold the number of units before and
+	// after the decision.
+	FromUnits int
+	ToUnits   int
+
+	// Reason describes why the decision was made.
+	Reason string
+}
+
+type autoscaleDecisionDoc struct {
+	Id          int       `bson:"_id"`
+	EnvUUID     string    `bson:"env-uuid"`
+	ServiceName string    `bson:"servicename"`
+	Time        time.Time `bson:"time"`
+	Metric      string    `bson:"metric"`
+	Value       float64   `bson:"value"`
+	FromUnits   int       `bson:"fromunits"`
+	ToUnits     int       `bson:"tounits"`
+	Reason      string    `bson:"reason"`
+}
+
+// RecordAutoscaleDecision adds the given decision to the service's
+// autoscale history.
+func (s *Service) RecordAutoscaleDecision(d AutoscaleDecision) error {
+	id, err := s.st.sequence("autoscaledecision")
+	if err != nil {
+		return errors.Annotatef(err, "cannot record autoscale decision for service %q", s)
+	}
+	ops := []txn.Op{{
+		C:      autoscaleHistoryC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: &autoscaleDecisionDoc{
+			EnvUUID:     s.st.EnvironUUID(),
+			ServiceName: s.doc.Name,
+			Time:        d.Time,
+			Metric:      d.Metric,
+			Value:       d.Value,
+			FromUnits:   d.FromUnits,
+			ToUnits:     d.ToUnits,
+			Reason:      d.Reason,
+		},
+	}}
+	err = s.st.runTransaction(ops)
+	return errors.Annotatef(err, "cannot record autoscale decision for service %q", s)
+}
+
+// AutoscaleHistory returns at most <size> of the most recent scaling
+// decisions made for the service, newest first.
+func (s *Service) AutoscaleHistory(size int) ([]AutoscaleDecision, error) {
+	history, closer := s.st.getCollection(autoscaleHistoryC)
+	defer closer()
+
+	var docs []autoscaleDecisionDoc
+	err := history.Find(bson.D{{"servicename", s.doc.Name}}).Sort("-_id").Limit(size).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get autoscale history for service %q", s)
+	}
+	result := make([]AutoscaleDecision, len(docs))
+	for i, doc := range docs {
+		result[i] = AutoscaleDecision{
+			Time:      doc.Time.UTC(),
+			Metric:    doc.Metric,
+			Value:     doc.Value,
+			FromUnits: doc.FromUnits,
+			ToUnits:   doc.ToUnits,
+			Reason:    doc.Reason,
+		}
+	}
+	return result, nil
+}
+
+// hasAutoscaleHistory reports whether any scaling decisions have been
+// recorded for the named service. If that cannot be determined, it
+// reports true, so that any history is cleaned up with the service.
+func hasAutoscaleHistory(st *State, serviceName string) bool {
+	history, closer := st.getCollection(autoscaleHistoryC)
+	defer closer()
+	n, err := history.Find(bson.D{{"servicename", serviceName}}).Count()
+	return err != nil || n > 0
+}
+
+// cleanupAutoscaleHistory removes the autoscale history of the named
+// service, which has been removed.
+func (st *State) cleanupAutoscaleHistory(serviceName string) error {
+	// The history is not otherwise referenced, nor watched, and so
+	// can safely be removed directly.
+	history, closer := st.getCollection(autoscaleHistoryC)
+	defer closer()
+	if _, err := history.RemoveAll(bson.D{{"servicename", serviceName}}); err != nil {
+		return errors.Annotatef(err, "cannot remove autoscale history for service %q", serviceName)
+	}
+	return nil
+}
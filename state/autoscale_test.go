@@ -0,0 +1,188 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type AutoscaleSuite struct {
+	ConnSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&AutoscaleSuite{})
+
+func (s *AutoscaleSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "metered", s.AddTestingCharm(c, "metered"))
+}
+
+var testPolicy = state.AutoscalePolicy{
+	Metric:    "pings",
+	Threshold: 100,
+	Window:    5 * time.Minute,
+	MinUnits:  1,
+	MaxUnits:  5,
+	Cooldown:  10 * time.Minute,
+}
+
+func (s *AutoscaleSuite) TestSetAutoscalePolicy(c *gc.C) {
+	_, err := s.service.AutoscalePolicy()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.service.SetAutoscalePolicy(testPolicy)
+	c.Assert(err, jc.ErrorIsNil)
+	policy, err := s.service.AutoscalePolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, testPolicy)
+
+	// Setting a policy again replaces it.
+	updated := testPolicy
+	updated.MaxUnits = 10
+	err = s.service.SetAutoscalePolicy(updated)
+	c.Assert(err, jc.ErrorIsNil)
+	policies, err := s.State.AutoscalePolicies()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policies, jc.DeepEquals, map[string]state.AutoscalePolicy{"metered": updated})
+
+	err = s.service.ClearAutoscalePolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.service.AutoscalePolicy()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Clearing an absent policy is not an error.
+	err = s.service.ClearAutoscalePolicy()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AutoscaleSuite) TestSetAutoscalePolicyInvalid(c *gc.C) {
+	for i, test := range []struct {
+		change func(*state.AutoscalePolicy)
+		err    string
+	}{{
+		change: func(p *state.AutoscalePolicy) { p.Metric = "" },
+		err:    "empty metric not valid",
+	}, {
+		change: func(p *state.AutoscalePolicy) { p.Metric = "foo" },
+		err:    `charm "local:quantal/metered-1" does not declare metric "foo"`,
+	}, {
+		change: func(p *state.AutoscalePolicy) { p.Threshold = 0 },
+		err:    "threshold 0 not valid",
+	}, {
+		change: func(p *state.AutoscalePolicy) { p.Window = 0 },
+		err:    "window 0 not valid",
+	}, {
+		change: func(p *state.AutoscalePolicy) { p.Cooldown = -time.Second },
+		err:    "cooldown -1s not valid",
+	}, {
+		change: func(p *state.AutoscalePolicy) { p.MinUnits = -1 },
+		err:    "minimum units -1 not valid",
+	}, {
+		change: func(p *state.AutoscalePolicy) { p.MinUnits, p.MaxUnits = 3, 2 },
+		err:    "maximum units 2 not valid",
+	}} {
+		c.Logf("test %d", i)
+		policy := testPolicy
+		test.change(&policy)
+		err := s.service.SetAutoscalePolicy(policy)
+		c.Check(err, gc.ErrorMatches, `cannot set autoscale policy for service "metered": `+test.err)
+	}
+}
+
+func (s *AutoscaleSuite) TestSetAutoscalePolicyNoMetrics(c *gc.C) {
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	err := mysql.SetAutoscalePolicy(testPolicy)
+	c.Assert(err, gc.ErrorMatches, `cannot set autoscale policy for service "mysql": charm "local:quantal/mysql-1" does not declare any metrics`)
+}
+
+func (s *AutoscaleSuite) TestUnitMetricAverages(c *gc.C) {
+	now := time.Now().Round(time.Second).UTC()
+	old := now.Add(-time.Hour)
+	unit0 := s.factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+	unit1 := s.factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+	s.factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+	for _, m := range []struct {
+		unit  *state.Unit
+		time  time.Time
+		value string
+	}{
+		{unit0, now, "10"},
+		{unit0, now, "20"},
+		{unit0, old, "1000"},
+		{unit1, now, "5"},
+	} {
+		s.factory.MakeMetric(c, &factory.MetricParams{
+			Unit:    m.unit,
+			Time:    &m.time,
+			Metrics: []state.Metric{{"pings", m.value, m.time}},
+		})
+	}
+
+	averages, err := s.service.UnitMetricAverages("pings", now.Add(-time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(averages, jc.DeepEquals, map[string]float64{
+		"metered/0": 15,
+		"metered/1": 5,
+	})
+}
+
+func (s *AutoscaleSuite) TestAutoscaleHistory(c *gc.C) {
+	t0 := time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC)
+	decisions := []state.AutoscaleDecision{{
+		Time:      t0,
+		Metric:    "pings",
+		Value:     150,
+		FromUnits: 1,
+		ToUnits:   2,
+		Reason:    "scaling up",
+	}, {
+		Time:      t0.Add(time.Hour),
+		Metric:    "pings",
+		Value:     20,
+		FromUnits: 2,
+		ToUnits:   1,
+		Reason:    "scaling down",
+	}}
+	for _, d := range decisions {
+		err := s.service.RecordAutoscaleDecision(d)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	history, err := s.service.AutoscaleHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, jc.DeepEquals, []state.AutoscaleDecision{decisions[1], decisions[0]})
+
+	history, err = s.service.AutoscaleHistory(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, jc.DeepEquals, []state.AutoscaleDecision{decisions[1]})
+}
+
+func (s *AutoscaleSuite) TestRemoveServiceRemovesAutoscale(c *gc.C) {
+	err := s.service.SetAutoscalePolicy(testPolicy)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.RecordAutoscaleDecision(state.AutoscaleDecision{Time: time.Now(), ToUnits: 1})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+
+	policies, err := s.State.AutoscalePolicies()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policies, gc.HasLen, 0)
+
+	// A new service of the same name starts without history.
+	s.service = s.AddTestingService(c, "metered", s.AddTestingCharm(c, "metered"))
+	history, err := s.service.AutoscaleHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 0)
+}
@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -29,6 +30,14 @@ type Block interface {
 
 	// Message returns explanation that accompanies this block.
 	Message() string
+
+	// Expiry returns the time after which this block no longer
+	// applies, or the zero time if it does not expire.
+	Expiry() time.Time
+
+	// CreatedBy returns the name of the user who switched this
+	// block on, if known.
+	CreatedBy() string
 }
 
 // BlockType specifies block type for enum benefit.
@@ -85,17 +94,41 @@ func ParseBlockType(str string) BlockType {
 	panic(fmt.Sprintf("unknown block type %v", str))
 }
 
+// BlockArgs holds the details of a block being switched on.
+type BlockArgs struct {
+	// Message explains why the block is in place.
+	Message string
+
+	// Entity is the service or machine that the block applies to.
+	// If nil, the block applies to the whole environment.
+	Entity names.Tag
+
+	// Expiry, if not zero, is the time after which the block no
+	// longer applies.
+	Expiry time.Time
+
+	// CreatedBy is the name of the user switching the block on.
+	CreatedBy string
+}
+
+// blockNow returns the time against which block expiry is checked.
+var blockNow = nowToTheSecond
+
 type block struct {
 	doc blockDoc
 }
 
 // blockDoc records information about an environment block.
+// Tag is the environment's tag for blocks on the whole
+// environment, or the tag of the service or machine blocked.
 type blockDoc struct {
-	DocID   string    `bson:"_id"`
-	EnvUUID string    `bson:"env-uuid"`
-	Tag     string    `bson:"tag"`
-	Type    BlockType `bson:"type"`
-	Message string    `bson:"message,omitempty"`
+	DocID     string    `bson:"_id"`
+	EnvUUID   string    `bson:"env-uuid"`
+	Tag       string    `bson:"tag"`
+	Type      BlockType `bson:"type"`
+	Message   string    `bson:"message,omitempty"`
+	Expiry    time.Time `bson:"expiry,omitempty"`
+	CreatedBy string    `bson:"created-by,omitempty"`
 }
 
 // Implementation for Block.Id().
@@ -122,16 +155,58 @@ func (b *block) Type() BlockType {
 	return b.doc.Type
 }
 
+// Implementation for Block.Expiry().
+func (b *block) Expiry() time.Time {
+	return b.doc.Expiry
+}
+
+// Implementation for Block.CreatedBy().
+func (b *block) CreatedBy() string {
+	return b.doc.CreatedBy
+}
+
+// expired reports whether the block no longer applies at the given time.
+func (doc *blockDoc) expired(now time.Time) bool {
+	return !doc.Expiry.IsZero() && !doc.Expiry.After(now)
+}
+
 // SwitchBlockOn enables block of specified type for the
 // current environment.
 func (st *State) SwitchBlockOn(t BlockType, msg string) error {
-	return setEnvironmentBlock(st, t, msg)
+	return st.SwitchBlockOnWithArgs(t, BlockArgs{Message: msg})
+}
+
+// SwitchBlockOnWithArgs enables block of specified type for the
+// environment, or for the service or machine given in args.
+func (st *State) SwitchBlockOnWithArgs(t BlockType, args BlockArgs) error {
+	tag, err := st.blockEntityTag(t, args.Entity)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !args.Expiry.IsZero() {
+		if !args.Expiry.After(blockNow()) {
+			return errors.Errorf("block expiry %v is in the past", args.Expiry)
+		}
+		args.Expiry = args.Expiry.Round(time.Second).UTC()
+	}
+	return setBlock(st, t, tag, args)
 }
 
 // SwitchBlockOff disables block of specified type for the
 // current environment.
 func (st *State) SwitchBlockOff(t BlockType) error {
-	return removeEnvironmentBlock(st, t)
+	return removeBlock(st, t, st.EnvironTag())
+}
+
+// SwitchBlockOffForEntity disables block of specified type for the
+// given service or machine. Blocks on the whole environment are not
+// affected.
+func (st *State) SwitchBlockOffForEntity(t BlockType, entity names.Tag) error {
+	tag, err := st.blockEntityTag(t, entity)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return removeBlock(st, t, tag)
 }
 
 // GetBlockForType returns the Block of the specified type for the current environment
@@ -139,24 +214,24 @@ func (st *State) SwitchBlockOff(t BlockType) error {
 //     not found -> nil, false, nil
 //     found -> block, true, nil
 //     error -> nil, false, err
+// Expired blocks and blocks on individual entities are not returned.
 func (st *State) GetBlockForType(t BlockType) (Block, bool, error) {
-	all, closer := st.getCollection(blocksC)
-	defer closer()
-
-	doc := blockDoc{}
-	err := all.Find(bson.D{{"type", t}}).One(&doc)
+	return st.GetBlockForEntity(t, st.EnvironTag())
+}
 
-	switch err {
-	case nil:
-		return &block{doc}, true, nil
-	case mgo.ErrNotFound:
-		return nil, false, nil
-	default:
-		return nil, false, errors.Annotatef(err, "cannot get block of type %v", t.String())
+// GetBlockForEntity returns the Block of the specified type for the
+// given service or machine, as GetBlockForType does for the environment.
+// Blocks on the whole environment are not returned.
+func (st *State) GetBlockForEntity(t BlockType, entity names.Tag) (Block, bool, error) {
+	doc, exists, err := st.blockDoc(t, entity)
+	if err != nil || !exists || doc.expired(blockNow()) {
+		return nil, false, err
 	}
+	return &block{*doc}, true, nil
 }
 
-// AllBlocks returns all blocks in the environment.
+// AllBlocks returns all blocks in the environment, including blocks on
+// individual services and machines. Expired blocks are not returned.
 func (st *State) AllBlocks() ([]Block, error) {
 	blocksCollection, closer := st.getCollection(blocksC)
 	defer closer()
@@ -166,28 +241,94 @@ func (st *State) AllBlocks() ([]Block, error) {
 	if err != nil {
 		return nil, errors.Annotatef(err, "cannot get all blocks")
 	}
-	blocks := make([]Block, len(bdocs))
-	for i, doc := range bdocs {
-		blocks[i] = &block{doc}
+	now := blockNow()
+	var blocks []Block
+	for _, doc := range bdocs {
+		if doc.expired(now) {
+			continue
+		}
+		blocks = append(blocks, &block{doc})
 	}
 	return blocks, nil
 }
 
-// setEnvironmentBlock updates the blocks collection with the
+// blockDoc returns the document for the block of the specified type on
+// the given entity, whether or not the block has expired.
+func (st *State) blockDoc(t BlockType, entity names.Tag) (*blockDoc, bool, error) {
+	all, closer := st.getCollection(blocksC)
+	defer closer()
+
+	doc := blockDoc{}
+	err := all.Find(bson.D{{"type", t}, {"tag", entity.String()}}).One(&doc)
+
+	switch err {
+	case nil:
+		return &doc, true, nil
+	case mgo.ErrNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, errors.Annotatef(err, "cannot get block %v", blockName(st, t, entity))
+	}
+}
+
+// blockEntityTag returns the tag recorded for a block of the specified
+// type on the given entity: the environment's tag if entity is nil.
+// Only services and machines can be blocked individually, and only
+// from being removed or changed.
+func (st *State) blockEntityTag(t BlockType, entity names.Tag) (names.Tag, error) {
+	if entity == nil || entity == names.Tag(st.EnvironTag()) {
+		return st.EnvironTag(), nil
+	}
+	switch entity.(type) {
+	case names.ServiceTag, names.MachineTag:
+	default:
+		return nil, errors.NotValidf("block on %v", entity)
+	}
+	if t == DestroyBlock {
+		return nil, errors.NotValidf("block %v on %v", t.String(), entity)
+	}
+	return entity, nil
+}
+
+// blockName returns a description of the block of the specified type
+// on the given entity, for use in error messages.
+func blockName(st *State, t BlockType, entity names.Tag) string {
+	if entity == names.Tag(st.EnvironTag()) {
+		return t.String()
+	}
+	return fmt.Sprintf("%v on %v", t.String(), entity)
+}
+
+// setBlock updates the blocks collection with the
 // specified block.
-// Only one instance of each block type can exist in environment.
-func setEnvironmentBlock(st *State, t BlockType, msg string) error {
+// Only one instance of each block type can exist for the
+// environment, and for each service or machine.
+func setBlock(st *State, t BlockType, entity names.Tag, args BlockArgs) error {
 	buildTxn := func(attempt int) ([]txn.Op, error) {
-		_, exists, err := st.GetBlockForType(t)
+		doc, exists, err := st.blockDoc(t, entity)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		// Cannot create blocks of the same type more than once per environment.
-		// Cannot update current blocks.
+		var ops []txn.Op
 		if exists {
-			return nil, errors.Errorf("block %v is already ON", t.String())
+			// Cannot create blocks of the same type more than once per entity.
+			// Cannot update current blocks.
+			if !doc.expired(blockNow()) {
+				return nil, errors.Errorf("block %v is already ON", blockName(st, t, entity))
+			}
+			// An expired block is replaced.
+			ops = append(ops, txn.Op{
+				C:      blocksC,
+				Id:     doc.DocID,
+				Assert: txn.DocExists,
+				Remove: true,
+			})
+		}
+		createOps, err := createBlockOps(st, t, entity, args)
+		if err != nil {
+			return nil, errors.Trace(err)
 		}
-		return createEnvironmentBlockOps(st, t, msg)
+		return append(ops, createOps...), nil
 	}
 	return st.run(buildTxn)
 }
@@ -201,17 +342,19 @@ func newBlockId(st *State) (string, error) {
 	return fmt.Sprint(seq), nil
 }
 
-func createEnvironmentBlockOps(st *State, t BlockType, msg string) ([]txn.Op, error) {
+func createBlockOps(st *State, t BlockType, entity names.Tag, args BlockArgs) ([]txn.Op, error) {
 	id, err := newBlockId(st)
 	if err != nil {
 		return nil, errors.Annotatef(err, "getting new block id")
 	}
 	newDoc := blockDoc{
-		DocID:   st.docID(id),
-		EnvUUID: st.EnvironUUID(),
-		Tag:     st.EnvironTag().String(),
-		Type:    t,
-		Message: msg,
+		DocID:     st.docID(id),
+		EnvUUID:   st.EnvironUUID(),
+		Tag:       entity.String(),
+		Type:      t,
+		Message:   args.Message,
+		Expiry:    args.Expiry,
+		CreatedBy: args.CreatedBy,
 	}
 	insertOp := txn.Op{
 		C:      blocksC,
@@ -222,24 +365,27 @@ func createEnvironmentBlockOps(st *State, t BlockType, msg string) ([]txn.Op, er
 	return []txn.Op{insertOp}, nil
 }
 
-func removeEnvironmentBlock(st *State, t BlockType) error {
+func removeBlock(st *State, t BlockType, entity names.Tag) error {
 	buildTxn := func(attempt int) ([]txn.Op, error) {
-		return removeEnvironmentBlockOps(st, t)
+		return removeBlockOps(st, t, entity)
 	}
 	return st.run(buildTxn)
 }
 
-func removeEnvironmentBlockOps(st *State, t BlockType) ([]txn.Op, error) {
-	tBlock, exists, err := st.GetBlockForType(t)
+// removeBlockOps returns the operations to remove the block of the
+// specified type on the given entity. An expired block is already off,
+// and is left to be replaced when the block is next switched on.
+func removeBlockOps(st *State, t BlockType, entity names.Tag) ([]txn.Op, error) {
+	doc, exists, err := st.blockDoc(t, entity)
 	if err != nil {
-		return nil, errors.Annotatef(err, "removing block %v", t.String())
+		return nil, errors.Annotatef(err, "removing block %v", blockName(st, t, entity))
 	}
-	if exists {
+	if exists && !doc.expired(blockNow()) {
 		return []txn.Op{txn.Op{
 			C:      blocksC,
-			Id:     tBlock.Id(),
+			Id:     doc.DocID,
 			Remove: true,
 		}}, nil
 	}
-	return nil, errors.Errorf("block %v is already OFF", t.String())
+	return nil, errors.Errorf("block %v is already OFF", blockName(st, t, entity))
 }
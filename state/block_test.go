@@ -5,6 +5,7 @@ package state_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -159,3 +160,99 @@ func (s *blockSuite) TestConcurrentBlocked(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	assertEnvHasBlock(c, s.State, t, msg)
 }
+
+func (s *blockSuite) TestEntityBlocked(c *gc.C) {
+	service := names.NewServiceTag("postgresql")
+	err := s.State.SwitchBlockOnWithArgs(state.RemoveBlock, state.BlockArgs{
+		Message:   "keep the data",
+		Entity:    service,
+		CreatedBy: "bob",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	b, found, err := s.State.GetBlockForEntity(state.RemoveBlock, service)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.IsTrue)
+	tag, err := b.Tag()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tag, gc.Equals, names.Tag(service))
+	c.Assert(b.Message(), gc.Equals, "keep the data")
+	c.Assert(b.CreatedBy(), gc.Equals, "bob")
+	c.Assert(b.Expiry().IsZero(), jc.IsTrue)
+
+	// The environment and other entities are not blocked.
+	s.assertNoTypedBlock(c, state.RemoveBlock)
+	_, found, err = s.State.GetBlockForEntity(state.RemoveBlock, names.NewServiceTag("mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.IsFalse)
+
+	// An environment block of the same type can be added alongside.
+	s.assertSwitchedOn(c, state.RemoveBlock)
+	all, err := s.State.AllBlocks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 2)
+
+	err = s.State.SwitchBlockOnWithArgs(state.RemoveBlock, state.BlockArgs{Entity: service})
+	c.Assert(err, gc.ErrorMatches, "block BlockRemove on service-postgresql is already ON")
+
+	err = s.State.SwitchBlockOffForEntity(state.RemoveBlock, service)
+	c.Assert(err, jc.ErrorIsNil)
+	assertEnvHasBlock(c, s.State, state.RemoveBlock, "")
+	err = s.State.SwitchBlockOffForEntity(state.RemoveBlock, service)
+	c.Assert(err, gc.ErrorMatches, "block BlockRemove on service-postgresql is already OFF")
+}
+
+func (s *blockSuite) TestEntityBlockInvalid(c *gc.C) {
+	err := s.State.SwitchBlockOnWithArgs(state.RemoveBlock, state.BlockArgs{
+		Entity: names.NewUnitTag("postgresql/0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "block on unit-postgresql-0 not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	err = s.State.SwitchBlockOnWithArgs(state.DestroyBlock, state.BlockArgs{
+		Entity: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "block BlockDestroy on machine-0 not valid")
+	assertNoEnvBlock(c, s.State)
+}
+
+func (s *blockSuite) TestBlockExpiry(c *gc.C) {
+	now := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.PatchValue(state.BlockNow, func() time.Time { return now })
+	machine := names.NewMachineTag("0")
+	expiry := now.Add(time.Hour)
+	err := s.State.SwitchBlockOnWithArgs(state.ChangeBlock, state.BlockArgs{
+		Entity: machine,
+		Expiry: expiry,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	b, found, err := s.State.GetBlockForEntity(state.ChangeBlock, machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.IsTrue)
+	c.Assert(b.Expiry().Equal(expiry), jc.IsTrue)
+
+	// Once expired, the block no longer applies, and can be replaced.
+	now = expiry
+	_, found, err = s.State.GetBlockForEntity(state.ChangeBlock, machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.IsFalse)
+	assertNoEnvBlock(c, s.State)
+	err = s.State.SwitchBlockOffForEntity(state.ChangeBlock, machine)
+	c.Assert(err, gc.ErrorMatches, "block BlockChange on machine-0 is already OFF")
+
+	err = s.State.SwitchBlockOnWithArgs(state.ChangeBlock, state.BlockArgs{
+		Entity:  machine,
+		Message: "again",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	all, err := s.State.AllBlocks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+	c.Assert(all[0].Message(), gc.Equals, "again")
+	c.Assert(all[0].Expiry().IsZero(), jc.IsTrue)
+
+	err = s.State.SwitchBlockOnWithArgs(state.ChangeBlock, state.BlockArgs{
+		Expiry: now,
+	})
+	c.Assert(err, gc.ErrorMatches, "block expiry .* is in the past")
+}
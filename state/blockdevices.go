@@ -39,6 +39,7 @@ type BlockDeviceInfo struct {
 	FilesystemType string `bson:"fstype,omitempty"`
 	InUse          bool   `bson:"inuse"`
 	MountPoint     string `bson:"mountpoint,omitempty"`
+	Partitioned    bool   `bson:"partitioned,omitempty"`
 }
 
 // WatchBlockDevices returns a new NotifyWatcher watching for
@@ -62,6 +62,8 @@ var multiEnvCollections = set.NewStrings(
 	actionNotificationsC,
 	actionsC,
 	annotationsC,
+	autoscaleHistoryC,
+	autoscalePoliciesC,
 	blockDevicesC,
 	blocksC,
 	charmsC,
@@ -71,6 +73,7 @@ var multiEnvCollections = set.NewStrings(
 	envUsersC,
 	filesystemsC,
 	filesystemAttachmentsC,
+	firewallStatusesC,
 	instanceDataC,
 	ipaddressesC,
 	machinesC,
@@ -79,14 +82,19 @@ var multiEnvCollections = set.NewStrings(
 	networkInterfacesC,
 	networksC,
 	openedPortsC,
+	placementPoliciesC,
 	rebootC,
 	relationScopesC,
 	relationsC,
+	remoteServicesC,
 	requestedNetworksC,
+	selfHealEventsC,
 	sequenceC,
+	serviceOffersC,
 	servicesC,
 	settingsC,
 	settingsrefsC,
+	snapshotsC,
 	statusesC,
 	statusesHistoryC,
 	storageAttachmentsC,
@@ -96,6 +104,8 @@ var multiEnvCollections = set.NewStrings(
 	unitsC,
 	volumesC,
 	volumeAttachmentsC,
+	webhooksC,
+	webhookDeliveriesC,
 )
 
 func newStateCollection(coll *mgo.Collection, envUUID string) stateCollection {
@@ -17,44 +17,50 @@ import (
 
 // constraintsDoc is the mongodb representation of a constraints.Value.
 type constraintsDoc struct {
-	EnvUUID      string `bson:"env-uuid"`
-	Arch         *string
-	CpuCores     *uint64
-	CpuPower     *uint64
-	Mem          *uint64
-	RootDisk     *uint64
-	InstanceType *string
-	Container    *instance.ContainerType
-	Tags         *[]string `bson:",omitempty"`
-	Networks     *[]string `bson:",omitempty"`
+	EnvUUID           string `bson:"env-uuid"`
+	Arch              *string
+	CpuCores          *uint64
+	CpuPower          *uint64
+	Mem               *uint64
+	RootDisk          *uint64
+	InstanceType      *string
+	Container         *instance.ContainerType
+	Tags              *[]string `bson:",omitempty"`
+	Networks          *[]string `bson:",omitempty"`
+	InstanceLifecycle *string   `bson:",omitempty"`
+	MaxPrice          *string   `bson:",omitempty"`
 }
 
 func (doc constraintsDoc) value() constraints.Value {
 	return constraints.Value{
-		Arch:         doc.Arch,
-		CpuCores:     doc.CpuCores,
-		CpuPower:     doc.CpuPower,
-		Mem:          doc.Mem,
-		RootDisk:     doc.RootDisk,
-		InstanceType: doc.InstanceType,
-		Container:    doc.Container,
-		Tags:         doc.Tags,
-		Networks:     doc.Networks,
+		Arch:              doc.Arch,
+		CpuCores:          doc.CpuCores,
+		CpuPower:          doc.CpuPower,
+		Mem:               doc.Mem,
+		RootDisk:          doc.RootDisk,
+		InstanceType:      doc.InstanceType,
+		Container:         doc.Container,
+		Tags:              doc.Tags,
+		Networks:          doc.Networks,
+		InstanceLifecycle: doc.InstanceLifecycle,
+		MaxPrice:          doc.MaxPrice,
 	}
 }
 
 func newConstraintsDoc(st *State, cons constraints.Value) constraintsDoc {
 	return constraintsDoc{
-		EnvUUID:      st.EnvironUUID(),
-		Arch:         cons.Arch,
-		CpuCores:     cons.CpuCores,
-		CpuPower:     cons.CpuPower,
-		Mem:          cons.Mem,
-		RootDisk:     cons.RootDisk,
-		InstanceType: cons.InstanceType,
-		Container:    cons.Container,
-		Tags:         cons.Tags,
-		Networks:     cons.Networks,
+		EnvUUID:           st.EnvironUUID(),
+		Arch:              cons.Arch,
+		CpuCores:          cons.CpuCores,
+		CpuPower:          cons.CpuPower,
+		Mem:               cons.Mem,
+		RootDisk:          cons.RootDisk,
+		InstanceType:      cons.InstanceType,
+		Container:         cons.Container,
+		Tags:              cons.Tags,
+		Networks:          cons.Networks,
+		InstanceLifecycle: cons.InstanceLifecycle,
+		MaxPrice:          cons.MaxPrice,
 	}
 }
 
@@ -0,0 +1,172 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/constraints"
+)
+
+// DryRun returns a State for the same environment whose transactions
+// are checked, but never applied: each operation's assertion is
+// evaluated against the current contents of the database, and the
+// transaction is reported as aborted if any of them fails. Operations
+// on the returned State therefore run the same precondition checks as
+// they would on st, and fail in the same way, without changing the
+// environment. Sequence numbers are read rather than incremented.
+//
+// Only the results of an operation's checks are meaningful; anything
+// that reads back what an operation would have written will not find
+// it. The returned State shares st's connection and watchers, and
+// must not be closed.
+func (st *State) DryRun() *State {
+	dry := &State{
+		mongoInfo:  st.mongoInfo,
+		policy:     st.policy,
+		db:         st.db,
+		watcher:    st.watcher,
+		pwatcher:   st.pwatcher,
+		environTag: st.environTag,
+		serverTag:  st.serverTag,
+		dryRun:     true,
+	}
+	dry.transactionRunner = &multiEnvRunner{
+		rawRunner:      &dryRunRunner{db: st.db},
+		envUUID:        st.EnvironUUID(),
+		assertEnvAlive: txnAssertEnvIsAlive,
+	}
+	dry.LeasePersistor = NewLeasePersistor(leaseC, dry.runTransaction, dry.getCollection)
+	return dry
+}
+
+// CheckConstraints validates cons, as setting them on a service or
+// machine would. It returns the attributes of cons that the environment
+// does not support, and which would be ignored, along with cons combined
+// with the environment constraints, as used to provision new machines.
+func (st *State) CheckConstraints(cons constraints.Value) (constraints.Value, []string, error) {
+	unsupported, err := st.validateConstraints(cons)
+	if len(unsupported) == 0 && err != nil {
+		return constraints.Value{}, nil, errors.Trace(err)
+	}
+	resolved, err := st.resolveConstraints(cons)
+	if err != nil {
+		return constraints.Value{}, nil, errors.Trace(err)
+	}
+	return resolved, unsupported, nil
+}
+
+// CheckPlacement returns an error if the environment would refuse to
+// provision a new machine with the given series, constraints and
+// placement directive.
+func (st *State) CheckPlacement(series string, cons constraints.Value, placement string) error {
+	return st.precheckInstance(series, cons, placement)
+}
+
+// PlannedCharm returns a Charm with the given URL, metadata and config
+// that has not been added to the environment, so that the operations of
+// a dry run can be checked against a charm before it is added. Nothing
+// but its URL, metadata and config is known.
+func (st *State) PlannedCharm(curl *charm.URL, meta *charm.Meta, config *charm.Config) *Charm {
+	if config == nil {
+		config = charm.NewConfig()
+	}
+	return newCharm(st, &charmDoc{
+		DocID:   st.docID(curl.String()),
+		URL:     curl,
+		EnvUUID: st.EnvironUUID(),
+		Meta:    meta,
+		Config:  config,
+	})
+}
+
+// dryRunRunner is a jujutxn.Runner that checks the assertions of the
+// transactions it is given without running them.
+type dryRunRunner struct {
+	db *mgo.Database
+}
+
+// maxDryRunAttempts mirrors the number of attempts made by the
+// jujutxn runner, so that transaction sources that compute a more
+// specific error on a later attempt get the chance to do so.
+const maxDryRunAttempts = 3
+
+// RunTransaction is part of the jujutxn.Runner interface. It returns
+// txn.ErrAborted if any of the operations' assertions does not hold.
+func (r *dryRunRunner) RunTransaction(ops []txn.Op) error {
+	for _, op := range ops {
+		ok, err := r.checkAssert(op)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return txn.ErrAborted
+		}
+	}
+	return nil
+}
+
+// Run is part of the jujutxn.Runner interface.
+func (r *dryRunRunner) Run(transactions jujutxn.TransactionSource) error {
+	for i := 0; i < maxDryRunAttempts; i++ {
+		ops, err := transactions(i)
+		if err == jujutxn.ErrTransientFailure {
+			continue
+		}
+		if err == jujutxn.ErrNoOperations {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.RunTransaction(ops); err != txn.ErrAborted {
+			return err
+		}
+	}
+	return jujutxn.ErrExcessiveContention
+}
+
+// ResumeTransactions is part of the jujutxn.Runner interface. There
+// is never anything to resume.
+func (r *dryRunRunner) ResumeTransactions() error {
+	return nil
+}
+
+// checkAssert reports whether the operation's assertion holds for the
+// document it refers to.
+func (r *dryRunRunner) checkAssert(op txn.Op) (bool, error) {
+	coll := r.db.C(op.C)
+	switch op.Assert {
+	case nil:
+		return true, nil
+	case txn.DocExists:
+		n, err := coll.FindId(op.Id).Count()
+		return n > 0, err
+	case txn.DocMissing:
+		n, err := coll.FindId(op.Id).Count()
+		return n == 0, err
+	}
+	query := bson.D{{"$and", []interface{}{
+		bson.D{{"_id", op.Id}},
+		op.Assert,
+	}}}
+	n, err := coll.Find(query).Count()
+	return n > 0, err
+}
+
+// peekSequence returns the value the named sequence would next return,
+// without incrementing it.
+func (st *State) peekSequence(name string) (int, error) {
+	var doc sequenceDoc
+	err := st.db.C(sequenceC).FindId(st.docID(name)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return 0, nil
+	}
+	return doc.Counter, err
+}
@@ -0,0 +1,116 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testcharms"
+)
+
+type DryRunSuite struct {
+	ConnSuite
+	service *state.Service
+	dry     *state.State
+}
+
+var _ = gc.Suite(&DryRunSuite{})
+
+func (s *DryRunSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.dry = s.State.DryRun()
+}
+
+func (s *DryRunSuite) TestAddService(c *gc.C) {
+	ch := s.AddTestingCharm(c, "mysql")
+	owner := s.Owner.String()
+	_, err := s.dry.AddService("mysql", owner, ch, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Service("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.dry.AddService("wordpress", owner, ch, nil, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add service "wordpress": service already exists`)
+}
+
+func (s *DryRunSuite) TestAddServicePlannedCharm(c *gc.C) {
+	dir := testcharms.Repo.CharmDir("mysql")
+	curl := charm.MustParseURL("cs:quantal/mysql-1")
+	ch := s.State.PlannedCharm(curl, dir.Meta(), dir.Config())
+	c.Assert(ch.URL(), jc.DeepEquals, curl)
+	c.Assert(ch.Meta(), jc.DeepEquals, dir.Meta())
+	c.Assert(ch.Config(), jc.DeepEquals, dir.Config())
+
+	owner := s.Owner.String()
+	_, err := s.dry.AddService("mysql", owner, ch, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.dry.AddService("wordpress", owner, ch, nil, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add service "wordpress": service already exists`)
+
+	// The charm was not added.
+	_, err = s.State.Charm(curl)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *DryRunSuite) TestAddRelation(c *gc.C) {
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	planned, err := s.dry.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EndpointsRelation(eps...)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// The relation sequence was not incremented.
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rel.Id(), gc.Equals, planned.Id())
+
+	_, err = s.dry.AddRelation(eps...)
+	c.Assert(err, gc.ErrorMatches, `cannot add relation "wordpress:db mysql:server": relation already exists`)
+}
+
+func (s *DryRunSuite) TestDestroyService(c *gc.C) {
+	service, err := s.dry.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.Life(), gc.Equals, state.Alive)
+}
+
+func (s *DryRunSuite) TestSetConstraints(c *gc.C) {
+	service, err := s.dry.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.SetConstraints(constraints.MustParse("mem=8G"))
+	c.Assert(err, jc.ErrorIsNil)
+	cons, err := s.service.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(&cons, jc.Satisfies, constraints.IsEmpty)
+}
+
+func (s *DryRunSuite) TestAssertionsChecked(c *gc.C) {
+	service, err := s.dry.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.SetConstraints(constraints.MustParse("mem=8G"))
+	c.Assert(err, gc.ErrorMatches, `cannot set constraints: not found or not alive`)
+}
+
+func (s *DryRunSuite) TestCheckConstraints(c *gc.C) {
+	err := s.State.SetEnvironConstraints(constraints.MustParse("mem=2G"))
+	c.Assert(err, jc.ErrorIsNil)
+	cons, unsupported, err := s.State.CheckConstraints(constraints.MustParse("cpu-cores=2"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unsupported, gc.HasLen, 0)
+	c.Assert(cons, gc.DeepEquals, constraints.MustParse("mem=2G cpu-cores=2"))
+}
@@ -0,0 +1,355 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// EventKind identifies the kind of an environment event.
+type EventKind string
+
+const (
+	// EventWorkloadStatus is recorded when a unit's workload
+	// status changes.
+	EventWorkloadStatus EventKind = "workload"
+
+	// EventAgentStatus is recorded when a unit agent's status
+	// changes.
+	EventAgentStatus EventKind = "agent"
+
+	// EventMachineStatus is recorded when a machine agent's status
+	// changes.
+	EventMachineStatus EventKind = "machine"
+
+	// EventInstanceStatus is recorded when the provider specific
+	// status of a machine's instance changes.
+	EventInstanceStatus EventKind = "instance"
+
+	// EventServiceStatus is recorded when a service's status
+	// changes.
+	EventServiceStatus EventKind = "service"
+
+	// EventHookFailed is recorded when a unit agent enters the
+	// error state because a hook failed.
+	EventHookFailed EventKind = "hook-failed"
+
+	// EventActionFinished is recorded when an action completes,
+	// fails or is cancelled.
+	EventActionFinished EventKind = "action"
+)
+
+// Event describes something that happened to an entity in
+// the environment.
+type Event struct {
+	// Time is when the event occurred.
+	Time time.Time
+
+	// Kind identifies what happened.
+	Kind EventKind
+
+	// Entity is the tag of the entity the event relates to.
+	Entity names.Tag
+
+	// Status holds the status the entity entered, or the
+	// final status of an action.
+	Status string
+
+	// Message holds any additional information about the event.
+	Message string
+}
+
+// EventFilter restricts the events returned by EnvironmentEvents.
+type EventFilter struct {
+	// EntityPrefixes, if not empty, restricts events to those
+	// for the entities named by the prefixes and the entities
+	// beneath them. So "machine-3" matches machine 3 and its
+	// containers but not machine 30, and "unit-mysql" matches
+	// the units of mysql but not those of mysql-ha.
+	EntityPrefixes []string
+
+	// From, if not zero, excludes events that occurred before it.
+	From time.Time
+
+	// To, if not zero, excludes events that occurred after it.
+	To time.Time
+
+	// Limit, if positive, restricts the result to the most
+	// recent Limit events.
+	Limit int
+}
+
+// entityRegex returns a regular expression matching the ids of the
+// entities selected by the filter, where each kind of entity has its
+// ids prefixed as given by keyPrefixes; for example, with the unit
+// kind mapped to "u#", "unit-mysql" selects ids starting "u#mysql/".
+// Kinds not in keyPrefixes are never selected. It returns false if
+// no entity could match.
+func (f EventFilter) entityRegex(keyPrefixes map[string]string) (string, bool) {
+	var alternatives []string
+	if len(f.EntityPrefixes) == 0 {
+		for _, keyPrefix := range keyPrefixes {
+			alternatives = append(alternatives, regexp.QuoteMeta(keyPrefix))
+		}
+	}
+	for _, prefix := range f.EntityPrefixes {
+		prefix = strings.TrimRight(prefix, "-")
+		kind, id := prefix, ""
+		if i := strings.Index(prefix, "-"); i >= 0 {
+			kind, id = prefix[:i], prefix[i+1:]
+		}
+		keyPrefix, ok := keyPrefixes[kind]
+		if !ok {
+			continue
+		}
+		alternative := regexp.QuoteMeta(keyPrefix)
+		if id != "" {
+			alternative += entityIdRegex(kind, id)
+		}
+		alternatives = append(alternatives, alternative)
+	}
+	if len(alternatives) == 0 {
+		return "", false
+	}
+	return "^(" + strings.Join(alternatives, "|") + ")", true
+}
+
+// entityIdRegex returns a regular expression matching the ids of the
+// entities of the given kind selected by the id part of a tag. The
+// match must end at a separator, so that machine-3 does not select
+// machine-30, nor unit-mysql the units of mysql-ha.
+func entityIdRegex(kind, id string) string {
+	switch kind {
+	case names.MachineTagKind:
+		// Machine tags replace all the "/" separators of
+		// container ids with "-"; machine ids hold no other
+		// "-", so it may also end the match.
+		return strings.Replace(regexp.QuoteMeta(id), "-", "/", -1) + "($|[-/#])"
+	case names.UnitTagKind:
+		// Unit tags replace the "/" before the unit number
+		// with "-"; any other id names the unit's service.
+		if i := strings.LastIndex(id, "-"); i >= 0 && isNumber(id[i+1:]) {
+			return regexp.QuoteMeta(id[:i]+"/"+id[i+1:]) + "($|#)"
+		}
+		return regexp.QuoteMeta(id) + "/"
+	}
+	return regexp.QuoteMeta(id) + "($|#)"
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// query returns a selector for documents whose timeField lies in
+// the filter's time range, and whose idField matches entityRegex.
+func (f EventFilter) query(timeField, idField, entityRegex string) bson.D {
+	cond := bson.M{"$ne": nil}
+	if !f.From.IsZero() {
+		cond["$gte"] = f.From
+	}
+	if !f.To.IsZero() {
+		cond["$lte"] = f.To
+	}
+	return bson.D{
+		{timeField, cond},
+		{idField, bson.RegEx{Pattern: entityRegex}},
+	}
+}
+
+// find returns the query for the documents selected by sel,
+// newest first and limited to the filter's Limit.
+func (f EventFilter) find(coll stateCollection, sel bson.D, timeField string) *mgo.Query {
+	query := coll.Find(sel).Sort("-" + timeField)
+	if f.Limit > 0 {
+		query = query.Limit(f.Limit)
+	}
+	return query
+}
+
+// statusKeyPrefixes maps the kinds of entity that have status
+// to the prefixes of their global keys.
+var statusKeyPrefixes = map[string]string{
+	names.MachineTagKind: "m#",
+	names.UnitTagKind:    "u#",
+	names.ServiceTagKind: "s#",
+}
+
+// EnvironmentEvents returns a timeline of the status changes of the
+// environment's machines, units and services, along with hook
+// failures and finished actions, ordered from oldest to newest.
+// Each collection is queried for at most filter.Limit of its most
+// recent events, which are then merged.
+func (st *State) EnvironmentEvents(filter EventFilter) ([]Event, error) {
+	var events []Event
+	statusEvents, err := st.statusEvents(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	events = append(events, statusEvents...)
+	actionEvents, err := st.actionEvents(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	events = append(events, actionEvents...)
+
+	sort.Stable(eventsByTime(events))
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[len(events)-filter.Limit:]
+	}
+	return events, nil
+}
+
+// statusEvents returns the events for both historical and current
+// statuses; a status history entry records the status an entity
+// held until it was replaced.
+func (st *State) statusEvents(filter EventFilter) ([]Event, error) {
+	keyRegex, ok := filter.entityRegex(statusKeyPrefixes)
+	if !ok {
+		return nil, nil
+	}
+	historyColl, closer := st.getCollection(statusesHistoryC)
+	defer closer()
+
+	var events []Event
+	var historyDocs []historicalStatusDoc
+	if err := filter.find(historyColl, filter.query("updated", "entityid", keyRegex), "updated").All(&historyDocs); err != nil {
+		return nil, errors.Annotate(err, "cannot get status history")
+	}
+	for _, doc := range historyDocs {
+		if event, ok := statusEvent(doc.EntityId, doc.Status, doc.StatusInfo, doc.Updated); ok {
+			events = append(events, event)
+		}
+	}
+
+	statusColl, closer := st.getCollection(statusesC)
+	defer closer()
+
+	var currentDocs []struct {
+		DocID      string `bson:"_id"`
+		Status     Status
+		StatusInfo string
+		Updated    *time.Time
+	}
+	// Status documents are keyed by environment UUID and global key.
+	docIDRegex := "^" + regexp.QuoteMeta(st.docID("")) + strings.TrimPrefix(keyRegex, "^")
+	if err := filter.find(statusColl, filter.query("updated", "_id", docIDRegex), "updated").All(&currentDocs); err != nil {
+		return nil, errors.Annotate(err, "cannot get statuses")
+	}
+	for _, doc := range currentDocs {
+		globalKey := st.localID(doc.DocID)
+		if event, ok := statusEvent(globalKey, doc.Status, doc.StatusInfo, doc.Updated); ok {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// statusEvent returns the event corresponding to an entity, identified
+// by its global key, entering a status. It returns false if the key is
+// not that of a machine, unit or service, or no time was recorded.
+func statusEvent(globalKey string, status Status, info string, updated *time.Time) (Event, bool) {
+	if updated == nil || len(globalKey) < 3 || globalKey[1] != '#' {
+		return Event{}, false
+	}
+	event := Event{
+		Time:    *updated,
+		Status:  string(status),
+		Message: info,
+	}
+	id := globalKey[2:]
+	switch globalKey[0] {
+	case 'm':
+		event.Kind = EventMachineStatus
+		if strings.HasSuffix(id, "#instance") {
+			event.Kind = EventInstanceStatus
+			id = strings.TrimSuffix(id, "#instance")
+		}
+		if !names.IsValidMachine(id) {
+			return Event{}, false
+		}
+		event.Entity = names.NewMachineTag(id)
+	case 's':
+		if !names.IsValidService(id) {
+			return Event{}, false
+		}
+		event.Kind = EventServiceStatus
+		event.Entity = names.NewServiceTag(id)
+	case 'u':
+		event.Kind = EventAgentStatus
+		if strings.HasSuffix(id, "#charm") {
+			event.Kind = EventWorkloadStatus
+			id = strings.TrimSuffix(id, "#charm")
+		}
+		if !names.IsValidUnit(id) {
+			return Event{}, false
+		}
+		event.Entity = names.NewUnitTag(id)
+		if event.Kind == EventAgentStatus && status == StatusError {
+			event.Kind = EventHookFailed
+		}
+	default:
+		return Event{}, false
+	}
+	return event, true
+}
+
+// actionEvents returns the events for actions that have finished.
+func (st *State) actionEvents(filter EventFilter) ([]Event, error) {
+	// Actions are received by units, identified by name.
+	receiverRegex, ok := filter.entityRegex(map[string]string{names.UnitTagKind: ""})
+	if !ok {
+		return nil, nil
+	}
+	actionsColl, closer := st.getCollection(actionsC)
+	defer closer()
+
+	query := filter.query("completed", "receiver", receiverRegex)
+	query = append(query, bson.DocElem{"status", bson.D{{"$in", []ActionStatus{
+		ActionCompleted, ActionFailed, ActionCancelled,
+	}}}})
+	var docs []actionDoc
+	if err := filter.find(actionsColl, query, "completed").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get actions")
+	}
+	var events []Event
+	for _, doc := range docs {
+		if !names.IsValidUnit(doc.Receiver) {
+			continue
+		}
+		event := Event{
+			Time:    doc.Completed,
+			Kind:    EventActionFinished,
+			Entity:  names.NewUnitTag(doc.Receiver),
+			Status:  string(doc.Status),
+			Message: doc.Name,
+		}
+		if doc.Message != "" {
+			event.Message += ": " + doc.Message
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+type eventsByTime []Event
+
+func (e eventsByTime) Len() int           { return len(e) }
+func (e eventsByTime) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e eventsByTime) Less(i, j int) bool { return e[i].Time.Before(e[j].Time) }
@@ -0,0 +1,194 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type EventsSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&EventsSuite{})
+
+func (s *EventsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	ch := s.AddTestingCharm(c, "dummy")
+	service := s.AddTestingService(c, "dummy", ch)
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := service.CharmURL()
+	err = unit.SetCharmURL(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit = unit
+}
+
+func hasEventKind(events []state.Event, kind state.EventKind) bool {
+	for _, event := range events {
+		if event.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *EventsSuite) TestEnvironmentEventsOrdered(c *gc.C) {
+	err := s.unit.SetAgentStatus(state.StatusError, `hook failed: "install"`, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	events, err := s.State.EnvironmentEvents(state.EventFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.Not(gc.HasLen), 0)
+	for i := 1; i < len(events); i++ {
+		c.Assert(events[i].Time.Before(events[i-1].Time), jc.IsFalse)
+	}
+	c.Assert(hasEventKind(events, state.EventHookFailed), jc.IsTrue)
+	c.Assert(hasEventKind(events, state.EventWorkloadStatus), jc.IsTrue)
+}
+
+func (s *EventsSuite) TestEnvironmentEventsHookFailed(c *gc.C) {
+	err := s.unit.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetAgentStatus(state.StatusError, `hook failed: "install"`, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	events, err := s.State.EnvironmentEvents(state.EventFilter{
+		EntityPrefixes: []string{s.unit.Tag().String()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	var failed []state.Event
+	for _, event := range events {
+		c.Check(event.Entity, gc.Equals, s.unit.Tag())
+		if event.Kind == state.EventHookFailed {
+			failed = append(failed, event)
+		}
+	}
+	c.Assert(failed, gc.HasLen, 1)
+	c.Assert(failed[0].Status, gc.Equals, "error")
+	c.Assert(failed[0].Message, gc.Equals, `hook failed: "install"`)
+}
+
+func (s *EventsSuite) TestEnvironmentEventsActions(c *gc.C) {
+	action, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err = action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action.Finish(state.ActionResults{
+		Status:  state.ActionFailed,
+		Message: "out of disk",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	events, err := s.State.EnvironmentEvents(state.EventFilter{
+		EntityPrefixes: []string{"unit-"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	var actions []state.Event
+	for _, event := range events {
+		if event.Kind == state.EventActionFinished {
+			actions = append(actions, event)
+		}
+	}
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Entity, gc.Equals, s.unit.Tag())
+	c.Assert(actions[0].Status, gc.Equals, "failed")
+	c.Assert(actions[0].Message, gc.Equals, "snapshot: out of disk")
+}
+
+func (s *EventsSuite) TestEnvironmentEventsTimeRange(c *gc.C) {
+	events, err := s.State.EnvironmentEvents(state.EventFilter{
+		To: time.Now().Add(-time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 0)
+
+	events, err = s.State.EnvironmentEvents(state.EventFilter{
+		From: time.Now().Add(-time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.Not(gc.HasLen), 0)
+}
+
+func (s *EventsSuite) TestEnvironmentEventsLimit(c *gc.C) {
+	all, err := s.State.EnvironmentEvents(state.EventFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(len(all) > 1, jc.IsTrue)
+
+	events, err := s.State.EnvironmentEvents(state.EventFilter{Limit: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 1)
+	c.Assert(events[0].Time, gc.Equals, all[len(all)-1].Time)
+}
+
+func (s *EventsSuite) TestEnvironmentEventsEntityAnchored(c *gc.C) {
+	var machines []*state.Machine
+	for i := 0; i < 31; i++ {
+		machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+		machines = append(machines, machine)
+	}
+	c.Assert(machines[30].Id(), gc.Equals, "30")
+	for _, machine := range []*state.Machine{machines[3], machines[30]} {
+		err := machine.SetStatus(state.StatusStarted, "", nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	events, err := s.State.EnvironmentEvents(state.EventFilter{
+		EntityPrefixes: []string{"machine-3"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.Not(gc.HasLen), 0)
+	for _, event := range events {
+		c.Check(event.Entity, gc.Equals, machines[3].Tag())
+	}
+
+	events, err = s.State.EnvironmentEvents(state.EventFilter{
+		EntityPrefixes: []string{"unit-dummy"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.Not(gc.HasLen), 0)
+	for _, event := range events {
+		c.Check(event.Entity, gc.Equals, s.unit.Tag())
+	}
+}
+
+func (s *EventsSuite) TestEnvironmentEventsHyphenatedService(c *gc.C) {
+	ch := s.AddTestingCharm(c, "dummy")
+	service := s.AddTestingService(c, "dummy-ha", ch)
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.SetStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, prefix := range []string{"unit-dummy", "service-dummy"} {
+		events, err := s.State.EnvironmentEvents(state.EventFilter{
+			EntityPrefixes: []string{prefix},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		for _, event := range events {
+			c.Check(event.Entity, gc.Not(gc.Equals), unit.Tag())
+			c.Check(event.Entity, gc.Not(gc.Equals), service.Tag())
+		}
+	}
+
+	events, err := s.State.EnvironmentEvents(state.EventFilter{
+		EntityPrefixes: []string{"unit-dummy-ha", "service-dummy-ha"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.Not(gc.HasLen), 0)
+	for _, event := range events {
+		if event.Entity != unit.Tag() {
+			c.Check(event.Entity, gc.Equals, service.Tag())
+		}
+	}
+}
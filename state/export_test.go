@@ -50,6 +50,8 @@ var (
 	AddVolumeOp            = (*State).addVolumeOp
 	CombineMeterStatus     = combineMeterStatus
 	NewStatusNotFound      = newStatusNotFound
+	BlockNow               = &blockNow
+	MaxWebhookDeliveries   = &maxWebhookDeliveries
 )
 
 type (
@@ -6,10 +6,12 @@ package state
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/juju/storage"
 	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
@@ -79,6 +81,11 @@ type FilesystemAttachment interface {
 	// if it has not already been made. Params returns true if the returned
 	// parameters are usable for creating an attachment, otherwise false.
 	Params() (FilesystemAttachmentParams, bool)
+
+	// Usage returns the filesystem's space and inode usage, as most
+	// recently reported by the machine agent. Usage returns true if
+	// usage has been reported, otherwise false.
+	Usage() (FilesystemUsage, bool)
 }
 
 type filesystem struct {
@@ -111,6 +118,7 @@ type filesystemAttachmentDoc struct {
 	Life       Life                        `bson:"life"`
 	Info       *FilesystemAttachmentInfo   `bson:"info,omitempty"`
 	Params     *FilesystemAttachmentParams `bson:"params,omitempty"`
+	Usage      *FilesystemUsage            `bson:"usage,omitempty"`
 }
 
 // FilesystemParams records parameters for provisioning a new filesystem.
@@ -121,6 +129,10 @@ type FilesystemParams struct {
 
 	Pool string `bson:"pool"`
 	Size uint64 `bson:"size"`
+
+	// Snapshot, if non-empty, is the ID of the snapshot from which
+	// the filesystem's backing volume is to be created.
+	Snapshot string `bson:"snapshot,omitempty"`
 }
 
 // FilesystemInfo describes information about a filesystem.
@@ -142,6 +154,30 @@ type FilesystemAttachmentInfo struct {
 	MountPoint string `bson:"mountpoint"`
 }
 
+// FilesystemUsage describes the space and inode usage of a filesystem
+// attached to a machine.
+type FilesystemUsage struct {
+	UsedBytes  uint64    `bson:"usedbytes"`
+	FreeBytes  uint64    `bson:"freebytes"`
+	UsedInodes uint64    `bson:"usedinodes"`
+	FreeInodes uint64    `bson:"freeinodes"`
+	Updated    time.Time `bson:"updated"`
+
+	// ThresholdExceeded records whether the space used exceeded
+	// the environment's storage usage threshold when reported.
+	ThresholdExceeded bool `bson:"thresholdexceeded,omitempty"`
+}
+
+// PercentUsed returns the percentage of the filesystem's space
+// that is used.
+func (u FilesystemUsage) PercentUsed() int {
+	total := u.UsedBytes + u.FreeBytes
+	if total == 0 {
+		return 0
+	}
+	return int(u.UsedBytes * 100 / total)
+}
+
 // FilesystemAttachmentParams records parameters for attaching a filesystem to a
 // machine.
 type FilesystemAttachmentParams struct {
@@ -228,6 +264,14 @@ func (f *filesystemAttachment) Params() (FilesystemAttachmentParams, bool) {
 	return *f.doc.Params, true
 }
 
+// Usage is required to implement FilesystemAttachment.
+func (f *filesystemAttachment) Usage() (FilesystemUsage, bool) {
+	if f.doc.Usage == nil {
+		return FilesystemUsage{}, false
+	}
+	return *f.doc.Usage, true
+}
+
 // Filesystem returns the Filesystem with the specified name.
 func (st *State) Filesystem(tag names.FilesystemTag) (Filesystem, error) {
 	coll, cleanup := st.getCollection(filesystemsC)
@@ -291,15 +335,33 @@ func (st *State) FilesystemAttachment(machine names.MachineTag, filesystem names
 // MachineFilesystemAttachments returns all of the FilesystemAttachments for the
 // specified machine.
 func (st *State) MachineFilesystemAttachments(machine names.MachineTag) ([]FilesystemAttachment, error) {
+	attachments, err := st.filesystemAttachments(bson.D{{"machineid", machine.Id()}})
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting filesystem attachments for machine %q", machine.Id())
+	}
+	return attachments, nil
+}
+
+// FilesystemAttachments returns all of the FilesystemAttachments for the
+// specified filesystem.
+func (st *State) FilesystemAttachments(filesystem names.FilesystemTag) ([]FilesystemAttachment, error) {
+	attachments, err := st.filesystemAttachments(bson.D{{"filesystemid", filesystem.Id()}})
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting filesystem attachments for filesystem %q", filesystem.Id())
+	}
+	return attachments, nil
+}
+
+func (st *State) filesystemAttachments(query bson.D) ([]FilesystemAttachment, error) {
 	coll, cleanup := st.getCollection(filesystemAttachmentsC)
 	defer cleanup()
 
 	var docs []filesystemAttachmentDoc
-	err := coll.Find(bson.D{{"machineid", machine.Id()}}).All(&docs)
+	err := coll.Find(query).All(&docs)
 	if err == mgo.ErrNotFound {
 		return nil, nil
 	} else if err != nil {
-		return nil, errors.Annotatef(err, "getting filesystem attachments for machine %q", machine.Id())
+		return nil, errors.Trace(err)
 	}
 	attachments := make([]FilesystemAttachment, len(docs))
 	for i, doc := range docs {
@@ -367,9 +429,10 @@ func (st *State) addFilesystemOps(params FilesystemParams, machineId string) ([]
 	if !provider.Supports(storage.StorageKindFilesystem) {
 		var volumeOp txn.Op
 		volumeParams := VolumeParams{
-			params.storage,
-			params.Pool,
-			params.Size,
+			storage:  params.storage,
+			Pool:     params.Pool,
+			Size:     params.Size,
+			Snapshot: params.Snapshot,
 		}
 		volumeOp, volumeTag, err = st.addVolumeOp(volumeParams, machineId)
 		if err != nil {
@@ -377,6 +440,10 @@ func (st *State) addFilesystemOps(params FilesystemParams, machineId string) ([]
 		}
 		volumeId = volumeTag.Id()
 		ops = append(ops, volumeOp)
+	} else if params.Snapshot != "" {
+		return nil, names.FilesystemTag{}, names.VolumeTag{}, errors.NotSupportedf(
+			"restoring snapshot into %q filesystem", params.Pool,
+		)
 	}
 
 	id, err := newFilesystemId(st, machineId)
@@ -406,7 +473,11 @@ func (st *State) filesystemParamsWithDefaults(params FilesystemParams) (Filesyst
 	if err != nil {
 		return FilesystemParams{}, errors.Trace(err)
 	}
-	poolName, err := defaultStoragePool(envConfig, storage.StorageKindFilesystem)
+	serviceDefaults, err := storageServiceDefaults(st, params.storage)
+	if err != nil {
+		return FilesystemParams{}, errors.Annotate(err, "getting service storage defaults")
+	}
+	poolName, err := defaultStoragePool(envConfig, serviceDefaults, storage.StorageKindFilesystem)
 	if err != nil {
 		return FilesystemParams{}, errors.Annotate(err, "getting default filesystem storage pool")
 	}
@@ -545,6 +616,41 @@ func (st *State) SetFilesystemAttachmentInfo(
 	return st.run(buildTxn)
 }
 
+// SetFilesystemAttachmentUsage records the most recently reported space
+// and inode usage of the specified filesystem attachment. The attachment
+// must be provisioned and not dead.
+func (st *State) SetFilesystemAttachmentUsage(
+	machineTag names.MachineTag,
+	filesystemTag names.FilesystemTag,
+	usage FilesystemUsage,
+) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set usage for filesystem attachment %s:%s", filesystemTag.Id(), machineTag.Id())
+	if usage.Updated.IsZero() {
+		usage.Updated = nowToTheSecond()
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		fsa, err := st.FilesystemAttachment(machineTag, filesystemTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if fsa.Life() == Dead {
+			return nil, errors.Errorf("filesystem attachment is dead")
+		}
+		if _, err := fsa.Info(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:  filesystemAttachmentsC,
+			Id: filesystemAttachmentId(machineTag.Id(), filesystemTag.Id()),
+			Assert: append(notDeadDoc,
+				bson.DocElem{"info", bson.D{{"$exists", true}}},
+			),
+			Update: bson.D{{"$set", bson.D{{"usage", &usage}}}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
 func setFilesystemAttachmentInfoOps(
 	machine names.MachineTag,
 	filesystem names.FilesystemTag,
@@ -567,3 +673,66 @@ func setFilesystemAttachmentInfoOps(
 		Update: update,
 	}}
 }
+
+// destroyFilesystemAttachmentOps returns txn.Ops to destroy the attachment
+// of the specified filesystem to the specified machine, so that the
+// filesystem will be detached by the storage provisioner. No operations
+// are returned if the attachment does not exist, or is not alive.
+func destroyFilesystemAttachmentOps(st *State, machine names.MachineTag, filesystem names.FilesystemTag) ([]txn.Op, error) {
+	att, err := st.FilesystemAttachment(machine, filesystem)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if att.Life() != Alive {
+		return nil, nil
+	}
+	return []txn.Op{{
+		C:      filesystemAttachmentsC,
+		Id:     filesystemAttachmentId(machine.Id(), filesystem.Id()),
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+	}}, nil
+}
+
+// RemoveFilesystemAttachment removes the attachment of the specified
+// filesystem to the specified machine from state. The attachment must
+// not be alive; the storage provisioner calls this once it has detached
+// the filesystem. If the filesystem is backed by a volume, the volume's
+// attachment to the machine is then destroyed.
+func (st *State) RemoveFilesystemAttachment(machine names.MachineTag, filesystem names.FilesystemTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove attachment of filesystem %q to machine %q", filesystem.Id(), machine.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		att, err := st.FilesystemAttachment(machine, filesystem)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if att.Life() == Alive {
+			return nil, errors.New("filesystem attachment is alive")
+		}
+		ops := []txn.Op{{
+			C:      filesystemAttachmentsC,
+			Id:     filesystemAttachmentId(machine.Id(), filesystem.Id()),
+			Assert: bson.D{{"life", bson.D{{"$ne", Alive}}}},
+			Remove: true,
+		}}
+		f, err := st.Filesystem(filesystem)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if volumeTag, err := f.Volume(); err == nil {
+			volumeOps, err := destroyVolumeAttachmentOps(st, machine, volumeTag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, volumeOps...)
+		} else if err != ErrNoBackingVolume {
+			return nil, errors.Trace(err)
+		}
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
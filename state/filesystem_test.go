@@ -34,10 +34,27 @@ func (s *FilesystemStateSuite) TestAddServiceNoPool(c *gc.C) {
 		"data": makeStorageCons("", 1024, 1),
 	}
 	_, err := s.State.AddService("storage-filesystem", s.Owner.String(), ch, nil, storage)
-	// TODO(axw) implement support for default filesystem pool.
 	c.Assert(err, gc.ErrorMatches, `cannot add service "storage-filesystem": finding default pool for "data" storage: no storage pool specifed and no default available`)
 }
 
+func (s *FilesystemStateSuite) TestAddServiceDefaultPool(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"storage-default-filesystem-source": "rootfs",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	ch := s.AddTestingCharm(c, "storage-filesystem")
+	storage := map[string]state.StorageConstraints{
+		"data": makeStorageCons("", 1024, 1),
+	}
+	service, err := s.State.AddService("storage-filesystem", s.Owner.String(), ch, nil, storage)
+	c.Assert(err, jc.ErrorIsNil)
+	cons, err := service.StorageConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, map[string]state.StorageConstraints{
+		"data": makeStorageCons("", 1024, 1),
+	})
+}
+
 func (s *FilesystemStateSuite) TestAddFilesystemWithoutBackingVolume(c *gc.C) {
 	s.addUnitWithFilesystem(c, "rootfs", false)
 }
@@ -211,6 +228,43 @@ func (s *FilesystemStateSuite) TestFilesystemInfo(c *gc.C) {
 	s.assertFilesystemAttachmentInfo(c, machineTag, filesystemTag, filesystemAttachmentInfo)
 }
 
+func (s *FilesystemStateSuite) TestSetFilesystemAttachmentUsage(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "filesystem", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	assignedMachineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machineTag := names.NewMachineTag(assignedMachineId)
+	filesystem, err := s.State.StorageInstanceFilesystem(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	filesystemTag := filesystem.FilesystemTag()
+
+	usage := state.FilesystemUsage{
+		UsedBytes:  300,
+		FreeBytes:  700,
+		UsedInodes: 10,
+		FreeInodes: 90,
+	}
+	err = s.State.SetFilesystemAttachmentUsage(machineTag, filesystemTag, usage)
+	c.Assert(err, gc.ErrorMatches, `cannot set usage for filesystem attachment 0/0:0: filesystem attachment "0/0" on "0" not provisioned`)
+
+	err = s.State.SetFilesystemAttachmentInfo(
+		machineTag, filesystemTag, state.FilesystemAttachmentInfo{MountPoint: "/srv"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetFilesystemAttachmentUsage(machineTag, filesystemTag, usage)
+	c.Assert(err, jc.ErrorIsNil)
+
+	attachment, err := s.State.FilesystemAttachment(machineTag, filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	reported, ok := attachment.Usage()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(reported.Updated.IsZero(), jc.IsFalse)
+	reported.Updated = usage.Updated
+	c.Assert(reported, jc.DeepEquals, usage)
+	c.Assert(reported.PercentUsed(), gc.Equals, 30)
+}
+
 func (s *FilesystemStateSuite) TestVolumeBackedFilesystemScope(c *gc.C) {
 	_, unit, storageTag := s.setupSingleStorage(c, "filesystem", "environscoped-block")
 	err := s.State.AssignUnit(unit, state.AssignCleanEmpty)
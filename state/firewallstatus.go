@@ -0,0 +1,271 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// FirewallStatus records the result of the most recent comparison,
+// made by the firewaller, of the ingress rules that Juju expects to
+// be open for a machine (or, in global firewall mode, the whole
+// environment) with those the provider reports as open.
+type FirewallStatus struct {
+	doc firewallStatusDoc
+}
+
+type firewallStatusDoc struct {
+	DocID    string           `bson:"_id"`
+	EnvUUID  string           `bson:"env-uuid"`
+	Entity   string           `bson:"entity"`
+	Checked  time.Time        `bson:"checked"`
+	Expected []ingressRuleDoc `bson:"expected"`
+	Actual   []ingressRuleDoc `bson:"actual"`
+
+	// The following fields describe the most recently detected
+	// drift, and are retained when later checks detect none.
+	DriftDetected   time.Time        `bson:"drift-detected,omitempty"`
+	DriftUnexpected []ingressRuleDoc `bson:"drift-unexpected,omitempty"`
+	DriftMissing    []ingressRuleDoc `bson:"drift-missing,omitempty"`
+}
+
+type ingressRuleDoc struct {
+	FromPort   int    `bson:"fromport"`
+	ToPort     int    `bson:"toport"`
+	Protocol   string `bson:"protocol"`
+	SourceCIDR string `bson:"sourcecidr"`
+}
+
+func newIngressRuleDocs(rules []network.IngressRule) []ingressRuleDoc {
+	if len(rules) == 0 {
+		return nil
+	}
+	docs := make([]ingressRuleDoc, len(rules))
+	for i, rule := range rules {
+		docs[i] = ingressRuleDoc{
+			FromPort:   rule.FromPort,
+			ToPort:     rule.ToPort,
+			Protocol:   rule.Protocol,
+			SourceCIDR: rule.SourceCIDR,
+		}
+	}
+	return docs
+}
+
+func ingressRulesFromDocs(docs []ingressRuleDoc) []network.IngressRule {
+	if len(docs) == 0 {
+		return nil
+	}
+	rules := make([]network.IngressRule, len(docs))
+	for i, doc := range docs {
+		rules[i] = network.IngressRule{
+			PortRange: network.PortRange{
+				FromPort: doc.FromPort,
+				ToPort:   doc.ToPort,
+				Protocol: doc.Protocol,
+			},
+			SourceCIDR: doc.SourceCIDR,
+		}
+	}
+	return rules
+}
+
+// Entity returns the tag of the machine or environment whose
+// firewall was checked.
+func (f *FirewallStatus) Entity() (names.Tag, error) {
+	return names.ParseTag(f.doc.Entity)
+}
+
+// Checked returns when the firewall was last checked.
+func (f *FirewallStatus) Checked() time.Time {
+	return f.doc.Checked
+}
+
+// Expected returns the ingress rules that Juju expected to be open.
+func (f *FirewallStatus) Expected() []network.IngressRule {
+	return ingressRulesFromDocs(f.doc.Expected)
+}
+
+// Actual returns the ingress rules that the provider reported as open.
+func (f *FirewallStatus) Actual() []network.IngressRule {
+	return ingressRulesFromDocs(f.doc.Actual)
+}
+
+// Unexpected returns the ingress rules that the provider reported as
+// open but Juju did not expect to be open when last checked.
+func (f *FirewallStatus) Unexpected() []network.IngressRule {
+	return diffIngressRules(f.Actual(), f.Expected())
+}
+
+// Missing returns the ingress rules that Juju expected to be open
+// but the provider did not report as open when last checked.
+func (f *FirewallStatus) Missing() []network.IngressRule {
+	return diffIngressRules(f.Expected(), f.Actual())
+}
+
+// DriftDetected returns when drift between the expected and actual
+// ingress rules was most recently detected. It returns the zero time
+// if no drift has ever been detected.
+func (f *FirewallStatus) DriftDetected() time.Time {
+	return f.doc.DriftDetected
+}
+
+// DriftUnexpected returns the unexpected ingress rules found when
+// drift was most recently detected.
+func (f *FirewallStatus) DriftUnexpected() []network.IngressRule {
+	return ingressRulesFromDocs(f.doc.DriftUnexpected)
+}
+
+// DriftMissing returns the missing ingress rules found when drift
+// was most recently detected.
+func (f *FirewallStatus) DriftMissing() []network.IngressRule {
+	return ingressRulesFromDocs(f.doc.DriftMissing)
+}
+
+// diffIngressRules returns the rules in a that are not in b.
+func diffIngressRules(a, b []network.IngressRule) []network.IngressRule {
+	inB := make(map[network.IngressRule]bool)
+	for _, rule := range b {
+		inB[rule] = true
+	}
+	var missing []network.IngressRule
+	for _, rule := range a {
+		if !inB[rule] {
+			missing = append(missing, rule)
+		}
+	}
+	return missing
+}
+
+// SetFirewallStatus records the ingress rules that Juju expected to be
+// open for the machine or environment with the given tag, and those
+// that the provider reported as open. Any difference between the two
+// is also recorded as the most recently detected drift.
+func (st *State) SetFirewallStatus(tag names.Tag, expected, actual []network.IngressRule) error {
+	switch tag.(type) {
+	case names.MachineTag, names.EnvironTag:
+	default:
+		return errors.NotValidf("firewall status entity %q", tag)
+	}
+	for _, rules := range [][]network.IngressRule{expected, actual} {
+		for _, rule := range rules {
+			if err := rule.Validate(); err != nil {
+				return errors.Annotatef(err, "cannot set firewall status for %q", tag)
+			}
+		}
+	}
+	expected = append([]network.IngressRule(nil), expected...)
+	actual = append([]network.IngressRule(nil), actual...)
+	network.SortIngressRules(expected)
+	network.SortIngressRules(actual)
+
+	now := nowToTheSecond()
+	update := bson.D{
+		{"checked", now},
+		{"expected", newIngressRuleDocs(expected)},
+		{"actual", newIngressRuleDocs(actual)},
+	}
+	unexpected := diffIngressRules(actual, expected)
+	missing := diffIngressRules(expected, actual)
+	if len(unexpected) > 0 || len(missing) > 0 {
+		update = append(update,
+			bson.DocElem{"drift-detected", now},
+			bson.DocElem{"drift-unexpected", newIngressRuleDocs(unexpected)},
+			bson.DocElem{"drift-missing", newIngressRuleDocs(missing)},
+		)
+	}
+
+	docID := st.docID(tag.String())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		statuses, closer := st.getCollection(firewallStatusesC)
+		defer closer()
+		n, err := statuses.FindId(docID).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if n > 0 {
+			return []txn.Op{{
+				C:      firewallStatusesC,
+				Id:     docID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", update}},
+			}}, nil
+		}
+		doc := firewallStatusDoc{
+			DocID:    docID,
+			EnvUUID:  st.EnvironUUID(),
+			Entity:   tag.String(),
+			Checked:  now,
+			Expected: newIngressRuleDocs(expected),
+			Actual:   newIngressRuleDocs(actual),
+		}
+		if len(unexpected) > 0 || len(missing) > 0 {
+			doc.DriftDetected = now
+			doc.DriftUnexpected = newIngressRuleDocs(unexpected)
+			doc.DriftMissing = newIngressRuleDocs(missing)
+		}
+		return []txn.Op{{
+			C:      firewallStatusesC,
+			Id:     docID,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set firewall status for %q", tag)
+	}
+	return nil
+}
+
+// FirewallStatus returns the firewall status recorded for the machine
+// or environment with the given tag.
+func (st *State) FirewallStatus(tag names.Tag) (*FirewallStatus, error) {
+	statuses, closer := st.getCollection(firewallStatusesC)
+	defer closer()
+
+	var doc firewallStatusDoc
+	err := statuses.FindId(tag.String()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("firewall status for %q", tag)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get firewall status for %q", tag)
+	}
+	return &FirewallStatus{doc: doc}, nil
+}
+
+// AllFirewallStatuses returns the firewall statuses recorded for all
+// machines in the environment, and for the environment itself.
+func (st *State) AllFirewallStatuses() ([]*FirewallStatus, error) {
+	statuses, closer := st.getCollection(firewallStatusesC)
+	defer closer()
+
+	var docs []firewallStatusDoc
+	if err := statuses.Find(nil).Sort("entity").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get firewall statuses")
+	}
+	result := make([]*FirewallStatus, len(docs))
+	for i, doc := range docs {
+		result[i] = &FirewallStatus{doc: doc}
+	}
+	return result, nil
+}
+
+// removeFirewallStatusOp returns the operation needed to remove the
+// firewall status recorded for the entity with the given tag.
+func removeFirewallStatusOp(st *State, tag names.Tag) txn.Op {
+	return txn.Op{
+		C:      firewallStatusesC,
+		Id:     st.docID(tag.String()),
+		Remove: true,
+	}
+}
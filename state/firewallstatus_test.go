@@ -0,0 +1,109 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type FirewallStatusSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&FirewallStatusSuite{})
+
+var (
+	httpRule  = network.IngressRule{network.MustParsePortRange("80/tcp"), network.AnySourceCIDR}
+	httpsRule = network.IngressRule{network.MustParsePortRange("443/tcp"), "10.0.0.0/8"}
+	sshRule   = network.IngressRule{network.MustParsePortRange("22/tcp"), network.AnySourceCIDR}
+)
+
+func (s *FirewallStatusSuite) TestSetFirewallStatus(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetFirewallStatus(machine.Tag(),
+		[]network.IngressRule{httpsRule, httpRule},
+		[]network.IngressRule{httpRule, sshRule},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := s.State.FirewallStatus(machine.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	tag, err := status.Entity()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tag, gc.Equals, machine.Tag())
+	c.Assert(status.Checked().IsZero(), jc.IsFalse)
+	c.Assert(status.Expected(), jc.DeepEquals, []network.IngressRule{httpRule, httpsRule})
+	c.Assert(status.Actual(), jc.DeepEquals, []network.IngressRule{sshRule, httpRule})
+	c.Assert(status.Unexpected(), jc.DeepEquals, []network.IngressRule{sshRule})
+	c.Assert(status.Missing(), jc.DeepEquals, []network.IngressRule{httpsRule})
+	c.Assert(status.DriftDetected(), gc.Equals, status.Checked())
+	c.Assert(status.DriftUnexpected(), jc.DeepEquals, []network.IngressRule{sshRule})
+	c.Assert(status.DriftMissing(), jc.DeepEquals, []network.IngressRule{httpsRule})
+
+	// A later check finding no drift retains the drift last detected.
+	err = s.State.SetFirewallStatus(machine.Tag(),
+		[]network.IngressRule{httpRule},
+		[]network.IngressRule{httpRule},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	status, err = s.State.FirewallStatus(machine.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Expected(), jc.DeepEquals, []network.IngressRule{httpRule})
+	c.Assert(status.Unexpected(), gc.HasLen, 0)
+	c.Assert(status.Missing(), gc.HasLen, 0)
+	c.Assert(status.DriftDetected().IsZero(), jc.IsFalse)
+	c.Assert(status.DriftUnexpected(), jc.DeepEquals, []network.IngressRule{sshRule})
+	c.Assert(status.DriftMissing(), jc.DeepEquals, []network.IngressRule{httpsRule})
+}
+
+func (s *FirewallStatusSuite) TestSetFirewallStatusInvalid(c *gc.C) {
+	err := s.State.SetFirewallStatus(names.NewUnitTag("mysql/0"), nil, nil)
+	c.Assert(err, gc.ErrorMatches, `firewall status entity "unit-mysql-0" not valid`)
+
+	bad := network.IngressRule{network.MustParsePortRange("80/tcp"), "10.0.0.0"}
+	err = s.State.SetFirewallStatus(names.NewMachineTag("0"), nil, []network.IngressRule{bad})
+	c.Assert(err, gc.ErrorMatches, `cannot set firewall status for "machine-0": invalid source CIDR "10.0.0.0"`)
+}
+
+func (s *FirewallStatusSuite) TestAllFirewallStatuses(c *gc.C) {
+	envTag := s.State.EnvironTag()
+	err := s.State.SetFirewallStatus(envTag, []network.IngressRule{httpRule}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetFirewallStatus(names.NewMachineTag("1"), nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	statuses, err := s.State.AllFirewallStatuses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses, gc.HasLen, 2)
+	tag, err := statuses[0].Entity()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tag, gc.Equals, envTag)
+	c.Assert(statuses[0].Missing(), jc.DeepEquals, []network.IngressRule{httpRule})
+	tag, err = statuses[1].Entity()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tag, gc.Equals, names.NewMachineTag("1"))
+}
+
+func (s *FirewallStatusSuite) TestMachineRemoveRemovesFirewallStatus(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetFirewallStatus(machine.Tag(), []network.IngressRule{httpRule}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.FirewallStatus(machine.Tag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -161,6 +161,12 @@ func (m *Machine) globalKey() string {
 	return machineGlobalKey(m.doc.Id)
 }
 
+// machineInstanceGlobalKey returns the global database key for the
+// instance status history of the identified machine.
+func machineInstanceGlobalKey(id string) string {
+	return machineGlobalKey(id) + "#instance"
+}
+
 // instanceData holds attributes relevant to a provisioned machine.
 type instanceData struct {
 	DocID      string      `bson:"_id"`
@@ -175,17 +181,19 @@ type instanceData struct {
 	CpuPower   *uint64     `bson:"cpupower,omitempty"`
 	Tags       *[]string   `bson:"tags,omitempty"`
 	AvailZone  *string     `bson:"availzone,omitempty"`
+	Lifecycle  *string     `bson:"lifecycle,omitempty"`
 }
 
 func hardwareCharacteristics(instData instanceData) *instance.HardwareCharacteristics {
 	return &instance.HardwareCharacteristics{
-		Arch:             instData.Arch,
-		Mem:              instData.Mem,
-		RootDisk:         instData.RootDisk,
-		CpuCores:         instData.CpuCores,
-		CpuPower:         instData.CpuPower,
-		Tags:             instData.Tags,
-		AvailabilityZone: instData.AvailZone,
+		Arch:              instData.Arch,
+		Mem:               instData.Mem,
+		RootDisk:          instData.RootDisk,
+		CpuCores:          instData.CpuCores,
+		CpuPower:          instData.CpuPower,
+		Tags:              instData.Tags,
+		AvailabilityZone:  instData.AvailZone,
+		InstanceLifecycle: instData.Lifecycle,
 	}
 }
 
@@ -661,6 +669,7 @@ func (m *Machine) Remove() (err error) {
 			Remove: true,
 		},
 		removeStatusOp(m.st, m.globalKey()),
+		removeFirewallStatusOp(m.st, m.Tag()),
 		removeConstraintsOp(m.st, m.globalKey()),
 		removeRequestedNetworksOp(m.st, m.globalKey()),
 		annotationRemoveOp(m.st, m.globalKey()),
@@ -777,6 +786,10 @@ func (m *Machine) InstanceStatus() (string, error) {
 func (m *Machine) SetInstanceStatus(status string) (err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot set instance status for machine %q", m)
 
+	oldStatus, err := m.InstanceStatus()
+	if err != nil && !errors.IsNotProvisioned(err) {
+		return err
+	}
 	ops := []txn.Op{
 		{
 			C:      instanceDataC,
@@ -787,6 +800,9 @@ func (m *Machine) SetInstanceStatus(status string) (err error) {
 	}
 
 	if err = m.st.runTransaction(ops); err == nil {
+		if status != oldStatus {
+			m.recordInstanceStatus(status)
+		}
 		return nil
 	} else if err != txn.ErrAborted {
 		return err
@@ -794,6 +810,22 @@ func (m *Machine) SetInstanceStatus(status string) (err error) {
 	return errors.NotProvisionedf("machine %v", m.Id())
 }
 
+// recordInstanceStatus adds the given instance status to the
+// machine's instance status history. Unlike agent statuses, the
+// instance status has no record of when it was set, so the history
+// records each new status as it is set rather than the old one.
+func (m *Machine) recordInstanceStatus(status string) {
+	timestamp := nowToTheSecond()
+	doc := statusDoc{
+		EnvUUID: m.st.EnvironUUID(),
+		Status:  Status(status),
+		Updated: &timestamp,
+	}
+	if err := updateStatusHistory(doc, machineInstanceGlobalKey(m.doc.Id), m.st); err != nil {
+		logger.Errorf("could not record instance status history for machine %q: %v", m, err)
+	}
+}
+
 // AvailabilityZone returns the provier-specific instance availability
 // zone in which the machine was provisioned.
 func (m *Machine) AvailabilityZone() (string, error) {
@@ -866,6 +898,7 @@ func (m *Machine) SetProvisioned(id instance.Id, nonce string, characteristics *
 		CpuPower:   characteristics.CpuPower,
 		Tags:       characteristics.Tags,
 		AvailZone:  characteristics.AvailabilityZone,
+		Lifecycle:  characteristics.InstanceLifecycle,
 	}
 
 	ops := []txn.Op{
@@ -939,6 +972,70 @@ func (m *Machine) SetInstanceInfo(
 	return m.SetProvisioned(id, nonce, characteristics)
 }
 
+// ResetProvisioned discards the machine's instance id, nonce, hardware
+// characteristics and provider addresses, so that a new instance may be
+// started for it. It is used when the provider has terminated the
+// machine's instance, as may happen at any time to spot instances. The
+// machine is left with an error status, marked as transient so that the
+// provisioner will retry provisioning it, holding the given reason.
+func (m *Machine) ResetProvisioned(reason string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot reset instance data for machine %q", m)
+
+	instData, err := getInstanceData(m.st, m.Id())
+	if errors.IsNotFound(err) {
+		return errors.NotProvisionedf("machine %v", m.Id())
+	} else if err != nil {
+		return err
+	}
+	oldStatus, err := getStatus(m.st, m.globalKey())
+	if err != nil && !IsStatusNotFound(err) {
+		return err
+	}
+	statusDoc, err := newMachineStatusDoc(StatusError, reason, map[string]interface{}{
+		"transient": true,
+	}, false)
+	if err != nil {
+		return err
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: append(isAliveDoc, bson.DocElem{"nonce", m.doc.Nonce}),
+		Update: bson.D{{"$set", bson.D{
+			{"nonce", ""},
+			{"addresses", []address{}},
+			{"machineaddresses", []address{}},
+		}}},
+	}, {
+		C:      instanceDataC,
+		Id:     m.doc.DocID,
+		Assert: bson.D{{"instanceid", instData.InstanceId}},
+		Remove: true,
+	},
+		updateStatusOp(m.st, m.globalKey(), statusDoc.statusDoc),
+	}
+	if err = m.st.runTransaction(ops); err == txn.ErrAborted {
+		if err := m.Refresh(); err != nil {
+			return err
+		}
+		if m.doc.Life != Alive {
+			return errNotAlive
+		}
+		return fmt.Errorf("instance data changed concurrently")
+	} else if err != nil {
+		return err
+	}
+	m.doc.Nonce = ""
+	m.doc.Addresses = nil
+	m.doc.MachineAddresses = nil
+	if oldStatus.Status != "" {
+		if err := updateStatusHistory(oldStatus, m.globalKey(), m.st); err != nil {
+			logger.Errorf("could not record status history before change to %q: %v", StatusError, err)
+		}
+	}
+	return nil
+}
+
 func mergedAddresses(machineAddresses, providerAddresses []address) []network.Address {
 	merged := make([]network.Address, 0, len(providerAddresses)+len(machineAddresses))
 	providerValues := set.NewStrings()
@@ -1282,9 +1379,16 @@ func (m *Machine) Status() (StatusInfo, error) {
 
 // SetStatus sets the status of the machine.
 func (m *Machine) SetStatus(status Status, info string, data map[string]interface{}) error {
+	oldDoc, err := getStatus(m.st, m.globalKey())
+	if IsStatusNotFound(err) {
+		logger.Debugf("there is no state for %q yet", m.globalKey())
+	} else if err != nil {
+		logger.Debugf("cannot get state for %q yet", m.globalKey())
+	}
+
 	// If a machine is not yet provisioned, we allow its status
 	// to be set back to pending (when a retry is to occur).
-	_, err := m.InstanceId()
+	_, err = m.InstanceId()
 	allowPending := errors.IsNotProvisioned(err)
 	doc, err := newMachineStatusDoc(status, info, data, allowPending)
 	if err != nil {
@@ -1300,9 +1404,28 @@ func (m *Machine) SetStatus(status Status, info string, data map[string]interfac
 	if err = m.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set status of machine %q: %v", m, onAbort(err, errNotAlive))
 	}
+
+	if oldDoc.Status != "" {
+		if err := updateStatusHistory(oldDoc, m.globalKey(), m.st); err != nil {
+			logger.Errorf("could not record status history before change to %q: %v", status, err)
+		}
+	}
 	return nil
 }
 
+// StatusHistory returns a slice of at most <size> StatusInfo items
+// representing past statuses of the machine's agent.
+func (m *Machine) StatusHistory(size int) ([]StatusInfo, error) {
+	return statusHistory(size, m.globalKey(), m.st)
+}
+
+// InstanceStatusHistory returns a slice of at most <size> StatusInfo
+// items representing the provider specific statuses of the machine's
+// instance, including the current one.
+func (m *Machine) InstanceStatusHistory(size int) ([]StatusInfo, error) {
+	return statusHistory(size, machineInstanceGlobalKey(m.doc.Id), m.st)
+}
+
 // Clean returns true if the machine does not have any deployed units or containers.
 func (m *Machine) Clean() bool {
 	return m.doc.Clean
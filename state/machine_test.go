@@ -1010,6 +1010,47 @@ func (s *MachineSuite) TestMachineSetProvisionedWhenNotAlive(c *gc.C) {
 	})
 }
 
+func (s *MachineSuite) TestMachineResetProvisioned(c *gc.C) {
+	err := s.machine.ResetProvisioned("instance terminated")
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	spot := instance.LifecycleSpot
+	err = s.machine.SetProvisioned("umbrella/0", "fake_nonce", &instance.HardwareCharacteristics{
+		InstanceLifecycle: &spot,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	hc, err := s.machine.HardwareCharacteristics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hc.IsSpot(), jc.IsTrue)
+	err = s.machine.SetAddresses(network.NewAddress("10.0.0.1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.ResetProvisioned("instance terminated")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.CheckProvisioned("fake_nonce"), jc.IsFalse)
+	c.Assert(s.machine.Addresses(), gc.HasLen, 0)
+	_, err = s.machine.InstanceId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+	_, err = s.machine.HardwareCharacteristics()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// The machine is marked for the provisioner to retry.
+	statusInfo, err := s.machine.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Equals, state.StatusError)
+	c.Assert(statusInfo.Message, gc.Equals, "instance terminated")
+	c.Assert(statusInfo.Data, jc.DeepEquals, map[string]interface{}{"transient": true})
+
+	// The machine may be provisioned again.
+	err = s.machine.SetProvisioned("umbrella/1", "new_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := s.machine.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, instance.Id("umbrella/1"))
+}
+
 func (s *MachineSuite) TestMachineSetInstanceStatus(c *gc.C) {
 	// Machine needs to be provisioned first.
 	err := s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
@@ -1026,6 +1067,37 @@ func (s *MachineSuite) TestMachineSetInstanceStatus(c *gc.C) {
 	c.Assert(status, gc.DeepEquals, "ALIVE")
 }
 
+func (s *MachineSuite) TestMachineInstanceStatusHistory(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, status := range []string{"pending", "running", "running", "stopped"} {
+		err = s.machine.SetInstanceStatus(status)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	history, err := s.machine.InstanceStatusHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	var statuses []state.Status
+	for _, info := range history {
+		statuses = append(statuses, info.Status)
+	}
+	// Unchanged statuses are not recorded, and the newest is first.
+	c.Assert(statuses, jc.DeepEquals, []state.Status{"stopped", "running", "pending"})
+}
+
+func (s *MachineSuite) TestMachineStatusHistory(c *gc.C) {
+	err := s.machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetStatus(state.StatusError, "oops", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.machine.StatusHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Status, gc.Equals, state.StatusStarted)
+	c.Assert(history[1].Status, gc.Equals, state.StatusPending)
+}
+
 func (s *MachineSuite) TestNotProvisionedMachineSetInstanceStatus(c *gc.C) {
 	err := s.machine.SetInstanceStatus("ALIVE")
 	c.Assert(err, gc.ErrorMatches, ".* not provisioned")
@@ -0,0 +1,233 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// ServiceOfferParams defines a service endpoint offered for use by
+// other environments hosted by the same state server.
+type ServiceOfferParams struct {
+	// OfferName is the name by which other environments consume the
+	// offer. If empty, the service name is used.
+	OfferName string
+
+	// ServiceName is the name of the offered service.
+	ServiceName string
+
+	// Endpoints holds the names of the offered relation endpoints of
+	// the service. If empty, all of the service's provider and
+	// requirer endpoints are offered.
+	Endpoints []string
+}
+
+// ServiceOffer represents a service whose endpoints are offered
+// for relating to services in other environments.
+type ServiceOffer struct {
+	st  *State
+	doc serviceOfferDoc
+}
+
+type serviceOfferDoc struct {
+	DocID       string   `bson:"_id"`
+	Name        string   `bson:"name"`
+	EnvUUID     string   `bson:"env-uuid"`
+	ServiceName string   `bson:"servicename"`
+	Endpoints   []string `bson:"endpoints"`
+}
+
+// Name returns the name by which the offer is consumed.
+func (o *ServiceOffer) Name() string {
+	return o.doc.Name
+}
+
+// ServiceName returns the name of the offered service.
+func (o *ServiceOffer) ServiceName() string {
+	return o.doc.ServiceName
+}
+
+// EndpointNames returns the names of the offered relation endpoints.
+func (o *ServiceOffer) EndpointNames() []string {
+	return o.doc.Endpoints
+}
+
+// Endpoints returns the offered relation endpoints.
+func (o *ServiceOffer) Endpoints() ([]Endpoint, error) {
+	svc, err := o.st.Service(o.doc.ServiceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	eps := make([]Endpoint, len(o.doc.Endpoints))
+	for i, name := range o.doc.Endpoints {
+		if eps[i], err = svc.Endpoint(name); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return eps, nil
+}
+
+// Endpoint returns the offered relation endpoint with the given name.
+func (o *ServiceOffer) Endpoint(name string) (Endpoint, error) {
+	for _, epName := range o.doc.Endpoints {
+		if epName == name {
+			svc, err := o.st.Service(o.doc.ServiceName)
+			if err != nil {
+				return Endpoint{}, errors.Trace(err)
+			}
+			return svc.Endpoint(name)
+		}
+	}
+	return Endpoint{}, errors.NotFoundf("endpoint %q of offer %q", name, o.doc.Name)
+}
+
+// AddServiceOffer offers the endpoints of a service for relating to
+// services in other environments.
+func (st *State) AddServiceOffer(p ServiceOfferParams) (_ *ServiceOffer, err error) {
+	if p.OfferName == "" {
+		p.OfferName = p.ServiceName
+	}
+	defer errors.DeferredAnnotatef(&err, "cannot add offer %q", p.OfferName)
+	if !names.IsValidService(p.OfferName) {
+		return nil, errors.NotValidf("offer name")
+	}
+	svc, err := st.Service(p.ServiceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !svc.IsPrincipal() {
+		return nil, errors.Errorf("subordinate service %q cannot be offered", p.ServiceName)
+	}
+	all, err := svc.Endpoints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var endpoints []string
+	for _, ep := range all {
+		if len(p.Endpoints) > 0 || ep.Role == charm.RolePeer || ep.IsImplicit() {
+			continue
+		}
+		if ep.Scope == charm.ScopeGlobal {
+			endpoints = append(endpoints, ep.Name)
+		}
+	}
+	for _, name := range p.Endpoints {
+		ep, err := svc.Endpoint(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if ep.Role == charm.RolePeer {
+			return nil, errors.Errorf("peer endpoint %q cannot be offered", name)
+		}
+		if ep.Scope == charm.ScopeContainer {
+			return nil, errors.Errorf("container scoped endpoint %q cannot be offered", name)
+		}
+		endpoints = append(endpoints, name)
+	}
+	if len(endpoints) == 0 {
+		return nil, errors.Errorf("service %q has no endpoints to offer", p.ServiceName)
+	}
+	doc := serviceOfferDoc{
+		DocID:       st.docID(p.OfferName),
+		Name:        p.OfferName,
+		EnvUUID:     st.EnvironUUID(),
+		ServiceName: p.ServiceName,
+		Endpoints:   endpoints,
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     svc.doc.DocID,
+		Assert: isAliveDoc,
+	}, {
+		C:      serviceOffersC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		if _, err := st.ServiceOffer(p.OfferName); err == nil {
+			return nil, errors.AlreadyExistsf("offer %q", p.OfferName)
+		}
+		return nil, errors.Errorf("service %q is not alive", p.ServiceName)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ServiceOffer{st: st, doc: doc}, nil
+}
+
+// ServiceOffer returns the offer with the given name.
+func (st *State) ServiceOffer(name string) (*ServiceOffer, error) {
+	offers, closer := st.getCollection(serviceOffersC)
+	defer closer()
+
+	var doc serviceOfferDoc
+	err := offers.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("offer %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get offer %q", name)
+	}
+	return &ServiceOffer{st: st, doc: doc}, nil
+}
+
+// AllServiceOffers returns all the offers made in the environment.
+func (st *State) AllServiceOffers() ([]*ServiceOffer, error) {
+	offers, closer := st.getCollection(serviceOffersC)
+	defer closer()
+
+	var docs []serviceOfferDoc
+	if err := offers.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get offers")
+	}
+	result := make([]*ServiceOffer, len(docs))
+	for i, doc := range docs {
+		result[i] = &ServiceOffer{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// RemoveServiceOffer withdraws the offer with the given name. Existing
+// relations established through the offer are not affected.
+func (st *State) RemoveServiceOffer(name string) error {
+	ops := []txn.Op{{
+		C:      serviceOffersC,
+		Id:     st.docID(name),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("offer %q", name)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot remove offer %q", name)
+	}
+	return nil
+}
+
+// removeServiceOffersOps returns the operations needed to withdraw
+// all offers of the named service.
+func removeServiceOffersOps(st *State, serviceName string) ([]txn.Op, error) {
+	offers, closer := st.getCollection(serviceOffersC)
+	defer closer()
+
+	var docs []serviceOfferDoc
+	err := offers.Find(bson.D{{"servicename", serviceName}}).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      serviceOffersC,
+			Id:     doc.DocID,
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
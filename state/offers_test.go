@@ -0,0 +1,121 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ServiceOfferSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ServiceOfferSuite{})
+
+func (s *ServiceOfferSuite) TestAddServiceOffer(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	offer, err := s.State.AddServiceOffer(state.ServiceOfferParams{
+		OfferName:   "blog",
+		ServiceName: "wordpress",
+		Endpoints:   []string{"url"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offer.Name(), gc.Equals, "blog")
+	c.Assert(offer.ServiceName(), gc.Equals, "wordpress")
+	c.Assert(offer.EndpointNames(), jc.DeepEquals, []string{"url"})
+	eps, err := offer.Endpoints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(eps, gc.HasLen, 1)
+	c.Assert(eps[0].Interface, gc.Equals, "http")
+
+	offer, err = s.State.ServiceOffer("blog")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offer.ServiceName(), gc.Equals, "wordpress")
+
+	_, err = s.State.AddServiceOffer(state.ServiceOfferParams{
+		OfferName:   "blog",
+		ServiceName: "wordpress",
+	})
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *ServiceOfferSuite) TestAddServiceOfferDefaultEndpoints(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	offer, err := s.State.AddServiceOffer(state.ServiceOfferParams{ServiceName: "wordpress"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offer.Name(), gc.Equals, "wordpress")
+	// Container scoped and implicit endpoints are not offered.
+	c.Assert(offer.EndpointNames(), jc.DeepEquals, []string{"cache", "db", "url"})
+}
+
+func (s *ServiceOfferSuite) TestAddServiceOfferInvalid(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "riak", s.AddTestingCharm(c, "riak"))
+	for i, test := range []struct {
+		params state.ServiceOfferParams
+		err    string
+	}{{
+		params: state.ServiceOfferParams{ServiceName: "mysql"},
+		err:    `cannot add offer "mysql": service "mysql" not found`,
+	}, {
+		params: state.ServiceOfferParams{OfferName: "Blog", ServiceName: "wordpress"},
+		err:    `cannot add offer "Blog": offer name not valid`,
+	}, {
+		params: state.ServiceOfferParams{ServiceName: "wordpress", Endpoints: []string{"logging-dir"}},
+		err:    `cannot add offer "wordpress": container scoped endpoint "logging-dir" cannot be offered`,
+	}, {
+		params: state.ServiceOfferParams{ServiceName: "riak", Endpoints: []string{"ring"}},
+		err:    `cannot add offer "riak": peer endpoint "ring" cannot be offered`,
+	}, {
+		params: state.ServiceOfferParams{ServiceName: "wordpress", Endpoints: []string{"foo"}},
+		err:    `cannot add offer "wordpress": service "wordpress" has no "foo" relation`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.State.AddServiceOffer(test.params)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ServiceOfferSuite) TestAllServiceOffers(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, err := s.State.AddServiceOffer(state.ServiceOfferParams{ServiceName: "wordpress"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddServiceOffer(state.ServiceOfferParams{OfferName: "db", ServiceName: "mysql"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	offers, err := s.State.AllServiceOffers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offers, gc.HasLen, 2)
+	c.Assert(offers[0].Name(), gc.Equals, "db")
+	c.Assert(offers[1].Name(), gc.Equals, "wordpress")
+}
+
+func (s *ServiceOfferSuite) TestRemoveServiceOffer(c *gc.C) {
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, err := s.State.AddServiceOffer(state.ServiceOfferParams{ServiceName: "mysql"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveServiceOffer("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ServiceOffer("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.RemoveServiceOffer("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ServiceOfferSuite) TestServiceDestroyRemovesOffers(c *gc.C) {
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, err := s.State.AddServiceOffer(state.ServiceOfferParams{ServiceName: "mysql"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ServiceOffer("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -181,7 +181,9 @@ var indexes = []struct {
 	{storageAttachmentsC, []string{"env-uuid", "unitid"}, false, false},
 	{volumesC, []string{"env-uuid", "storageid"}, false, false},
 	{filesystemsC, []string{"env-uuid", "storageid"}, false, false},
+	{snapshotsC, []string{"env-uuid", "volumeid"}, false, false},
 	{statusesHistoryC, []string{"env-uuid", "entityid"}, false, false},
+	{webhookDeliveriesC, []string{"env-uuid", "webhook-id"}, false, false},
 }
 
 // The capped collection used for transaction logs defaults to 10MB.
@@ -0,0 +1,555 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// AntiAffinity describes the scope within which no two units of a
+// service may be placed.
+type AntiAffinity string
+
+const (
+	// AntiAffinityNone places no restriction on co-locating units.
+	AntiAffinityNone AntiAffinity = ""
+
+	// AntiAffinityMachine prevents two units of a service from being
+	// placed on the same host machine, including its containers.
+	AntiAffinityMachine AntiAffinity = "machine"
+
+	// AntiAffinityZone prevents two units of a service from being
+	// placed in the same availability zone. Only machines whose zone
+	// is known, because they have been provisioned, are considered;
+	// units assigned to new machines are spread across zones by the
+	// provider, where it supports doing so.
+	AntiAffinityZone AntiAffinity = "zone"
+)
+
+// PlacementPolicy restricts the number of units of a service and the
+// machines they may be assigned to.
+type PlacementPolicy struct {
+	// MaxUnits, if non-zero, is the maximum number of units the
+	// service may have, including those that are dying.
+	MaxUnits int
+
+	// AntiAffinity is the scope within which no two units of the
+	// service may be placed.
+	AntiAffinity AntiAffinity
+
+	// AffinityService, if set, names a service with which the units
+	// of this service must be co-located: units may only be assigned
+	// to machines, or containers on machines, that host a unit of
+	// that service.
+	AffinityService string
+}
+
+// Validate returns an error if the policy is not valid.
+func (p PlacementPolicy) Validate() error {
+	if p.MaxUnits < 0 {
+		return errors.NotValidf("maximum units %d", p.MaxUnits)
+	}
+	switch p.AntiAffinity {
+	case AntiAffinityNone, AntiAffinityMachine, AntiAffinityZone:
+	default:
+		return errors.NotValidf("anti-affinity %q", p.AntiAffinity)
+	}
+	if p.AffinityService != "" && !names.IsValidService(p.AffinityService) {
+		return errors.NotValidf("affinity service name %q", p.AffinityService)
+	}
+	return nil
+}
+
+// placementPolicyDoc holds the placement policy of a service. It is
+// removed along with the service.
+type placementPolicyDoc struct {
+	DocID           string `bson:"_id"`
+	EnvUUID         string `bson:"env-uuid"`
+	ServiceName     string `bson:"servicename"`
+	MaxUnits        int    `bson:"maxunits"`
+	AntiAffinity    string `bson:"antiaffinity"`
+	AffinityService string `bson:"affinityservice"`
+}
+
+func (doc *placementPolicyDoc) policy() PlacementPolicy {
+	return PlacementPolicy{
+		MaxUnits:        doc.MaxUnits,
+		AntiAffinity:    AntiAffinity(doc.AntiAffinity),
+		AffinityService: doc.AffinityService,
+	}
+}
+
+// SetPlacementPolicy sets the policy restricting the placement of the
+// service's units, replacing any existing policy. The policy is
+// enforced when units are added and assigned to machines; units that
+// are already assigned are not moved.
+func (s *Service) SetPlacementPolicy(p PlacementPolicy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set placement policy for service %q", s)
+	if err := p.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if s.doc.Subordinate {
+		return errors.Errorf("service is subordinate")
+	}
+	if p.AffinityService == s.doc.Name {
+		return errors.Errorf("service cannot have affinity with itself")
+	}
+
+	docID := s.st.docID(s.doc.Name)
+	doc := placementPolicyDoc{
+		DocID:           docID,
+		EnvUUID:         s.st.EnvironUUID(),
+		ServiceName:     s.doc.Name,
+		MaxUnits:        p.MaxUnits,
+		AntiAffinity:    string(p.AntiAffinity),
+		AffinityService: p.AffinityService,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); errors.IsNotFound(err) {
+				return nil, errors.New("service is no longer alive")
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if s.doc.Life != Alive {
+			return nil, errors.New("service is no longer alive")
+		}
+		serviceAssert := isAliveDoc
+		if p.MaxUnits > 0 {
+			if s.doc.UnitCount > p.MaxUnits {
+				return nil, errors.Errorf(
+					"service has %d units, more than the maximum of %d",
+					s.doc.UnitCount, p.MaxUnits,
+				)
+			}
+			serviceAssert = append(serviceAssert, bson.DocElem{
+				"unitcount", bson.D{{"$lte", p.MaxUnits}},
+			})
+		}
+		ops := []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: serviceAssert,
+		}}
+		if _, err := s.PlacementPolicy(); errors.IsNotFound(err) {
+			return append(ops, txn.Op{
+				C:      placementPoliciesC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:      placementPoliciesC,
+			Id:     docID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"maxunits", doc.MaxUnits},
+				{"antiaffinity", doc.AntiAffinity},
+				{"affinityservice", doc.AffinityService},
+			}}},
+		}), nil
+	}
+	return s.st.run(buildTxn)
+}
+
+// PlacementPolicy returns the service's placement policy. It returns
+// an error that satisfies errors.IsNotFound if the service has none.
+func (s *Service) PlacementPolicy() (PlacementPolicy, error) {
+	doc, err := getPlacementPolicyDoc(s.st, s.doc.Name)
+	if err != nil {
+		return PlacementPolicy{}, err
+	}
+	return doc.policy(), nil
+}
+
+func getPlacementPolicyDoc(st *State, serviceName string) (*placementPolicyDoc, error) {
+	policies, closer := st.getCollection(placementPoliciesC)
+	defer closer()
+
+	var doc placementPolicyDoc
+	err := policies.FindId(serviceName).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("placement policy for service %q", serviceName)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get placement policy for service %q", serviceName)
+	}
+	return &doc, nil
+}
+
+// ClearPlacementPolicy removes the service's placement policy, if it
+// has one.
+func (s *Service) ClearPlacementPolicy() error {
+	ops := []txn.Op{removePlacementPolicyOp(s.st, s.doc.Name)}
+	err := s.st.runTransaction(ops)
+	return errors.Annotatef(err, "cannot clear placement policy for service %q", s)
+}
+
+// removePlacementPolicyOp returns the operation required to remove the
+// named service's placement policy, if it exists.
+func removePlacementPolicyOp(st *State, serviceName string) txn.Op {
+	return txn.Op{
+		C:      placementPoliciesC,
+		Id:     st.docID(serviceName),
+		Remove: true,
+	}
+}
+
+// maxUnitsAsserts returns the assertions on the service document, and
+// the operations, that ensure a unit may be added to the service without
+// exceeding the maximum number of units allowed by its placement policy,
+// or an error if it may not.
+func (s *Service) maxUnitsAsserts() (bson.D, []txn.Op, error) {
+	doc, err := getPlacementPolicyDoc(s.st, s.doc.Name)
+	if errors.IsNotFound(err) {
+		return nil, []txn.Op{{
+			C:      placementPoliciesC,
+			Id:     s.st.docID(s.doc.Name),
+			Assert: txn.DocMissing,
+		}}, nil
+	} else if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      placementPoliciesC,
+		Id:     doc.DocID,
+		Assert: bson.D{{"maxunits", doc.MaxUnits}},
+	}}
+	if doc.MaxUnits == 0 {
+		return nil, ops, nil
+	}
+	if s.doc.UnitCount >= doc.MaxUnits {
+		return nil, nil, errors.Errorf("placement policy allows at most %d units", doc.MaxUnits)
+	}
+	asserts := bson.D{{"unitcount", bson.D{{"$lt", doc.MaxUnits}}}}
+	return asserts, ops, nil
+}
+
+// placementPolicyError is returned when assigning a unit to a machine
+// would violate the placement policy of the unit's service.
+type placementPolicyError struct {
+	msg string
+}
+
+func (e *placementPolicyError) Error() string {
+	return e.msg
+}
+
+func placementPolicyErrorf(format string, args ...interface{}) error {
+	return &placementPolicyError{fmt.Sprintf(format, args...)}
+}
+
+// isPlacementPolicyError returns whether err was returned because a
+// placement policy was violated.
+func isPlacementPolicyError(err error) bool {
+	_, ok := err.(*placementPolicyError)
+	return ok
+}
+
+// IsPlacementPolicyError returns whether err was caused by the
+// placement policy of a service being violated.
+func IsPlacementPolicyError(err error) bool {
+	return isPlacementPolicyError(errors.Cause(err))
+}
+
+// checkPlacementPolicy returns an error satisfying isPlacementPolicyError
+// if assigning the unit to the given machine would violate the placement
+// policy of its service. Units placed in containers are considered to
+// be on the container's top level host machine. Otherwise it returns
+// the operations that assert the policy, and the unit assignments it
+// was checked against, are unchanged when the unit is assigned.
+func (u *Unit) checkPlacementPolicy(m *Machine) ([]txn.Op, error) {
+	policy, err := getPlacementPolicyDoc(u.st, u.doc.Service)
+	if errors.IsNotFound(err) {
+		return []txn.Op{{
+			C:      placementPoliciesC,
+			Id:     u.st.docID(u.doc.Service),
+			Assert: txn.DocMissing,
+		}}, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:  placementPoliciesC,
+		Id: policy.DocID,
+		Assert: bson.D{
+			{"antiaffinity", policy.AntiAffinity},
+			{"affinityservice", policy.AffinityService},
+		},
+	}}
+	hostId := TopParentId(m.Id())
+	if policy.AffinityService != "" {
+		hosts, err := serviceHosts(u.st, policy.AffinityService, "")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		unit, ok := hosts[hostId]
+		if !ok {
+			return nil, placementPolicyErrorf(
+				"placement policy requires units of service %q to be placed with service %q, but machine %s hosts no units of %q",
+				u.doc.Service, policy.AffinityService, hostId, policy.AffinityService,
+			)
+		}
+		// The unit of the affinity service must remain on the host.
+		ops = append(ops, txn.Op{
+			C:      unitsC,
+			Id:     unit.doc.DocID,
+			Assert: bson.D{{"machineid", unit.doc.MachineId}},
+		})
+	}
+	if AntiAffinity(policy.AntiAffinity) == AntiAffinityNone {
+		return ops, nil
+	}
+
+	// Both kinds of anti-affinity depend on where the service's
+	// other units are, so none of them may be assigned, and no
+	// units added, before this unit is.
+	service, err := u.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := allUnits(u.st, u.doc.Service)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, txn.Op{
+		C:      servicesC,
+		Id:     service.doc.DocID,
+		Assert: bson.D{{"unitcount", service.doc.UnitCount}},
+	})
+	for _, unit := range units {
+		if unit.Name() == u.doc.Name {
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      unitsC,
+			Id:     unit.doc.DocID,
+			Assert: bson.D{{"machineid", unit.doc.MachineId}},
+		})
+	}
+	hosts, err := unitHosts(units, u.doc.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch AntiAffinity(policy.AntiAffinity) {
+	case AntiAffinityMachine:
+		if unit, ok := hosts[hostId]; ok {
+			return nil, placementPolicyErrorf(
+				"placement policy forbids units of service %q on the same machine, but machine %s hosts unit %q",
+				u.doc.Service, hostId, unit.Name(),
+			)
+		}
+	case AntiAffinityZone:
+		zone, err := machineZone(u.st, hostId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		} else if zone == "" {
+			// The machine's zone is not yet known.
+			break
+		}
+		for otherHostId, unit := range hosts {
+			otherZone, err := machineZone(u.st, otherHostId)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if otherZone == zone {
+				return nil, placementPolicyErrorf(
+					"placement policy forbids units of service %q in the same availability zone, but zone %q hosts unit %q",
+					u.doc.Service, zone, unit.Name(),
+				)
+			}
+		}
+	}
+	return ops, nil
+}
+
+// checkNewMachinePlacementPolicy returns an error satisfying
+// isPlacementPolicyError if the unit's service's placement policy
+// prevents it from being assigned to a new machine.
+func (u *Unit) checkNewMachinePlacementPolicy() error {
+	policy, err := getPlacementPolicyDoc(u.st, u.doc.Service)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if policy.AffinityService != "" {
+		return placementPolicyErrorf(
+			"placement policy requires units of service %q to be placed on a machine hosting service %q",
+			u.doc.Service, policy.AffinityService,
+		)
+	}
+	return nil
+}
+
+// checkReplacementPlacementPolicy returns an error satisfying
+// isPlacementPolicyError if the unit's service's placement policy
+// prevents it from being moved to a new machine along with the given
+// principal units, which share its current machine. Otherwise it
+// returns the operations that assert the policy is unchanged when the
+// units are moved. The new machine's availability zone is not yet
+// known, so zone anti-affinity always holds.
+func (u *Unit) checkReplacementPlacementPolicy(principals []string) ([]txn.Op, error) {
+	policy, err := getPlacementPolicyDoc(u.st, u.doc.Service)
+	if errors.IsNotFound(err) {
+		return []txn.Op{{
+			C:      placementPoliciesC,
+			Id:     u.st.docID(u.doc.Service),
+			Assert: txn.DocMissing,
+		}}, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:  placementPoliciesC,
+		Id: policy.DocID,
+		Assert: bson.D{
+			{"antiaffinity", policy.AntiAffinity},
+			{"affinityservice", policy.AffinityService},
+		},
+	}}
+	affinityMoved := false
+	for _, unitName := range principals {
+		if unitName == u.doc.Name {
+			continue
+		}
+		serviceName, err := names.UnitService(unitName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if serviceName == policy.AffinityService {
+			affinityMoved = true
+		}
+		if serviceName == u.doc.Service && AntiAffinity(policy.AntiAffinity) == AntiAffinityMachine {
+			return nil, placementPolicyErrorf(
+				"placement policy forbids units of service %q on the same machine, but unit %q would be moved with unit %q",
+				u.doc.Service, u.doc.Name, unitName,
+			)
+		}
+	}
+	if policy.AffinityService != "" && !affinityMoved {
+		return nil, placementPolicyErrorf(
+			"placement policy requires units of service %q to be placed with service %q, but no unit of %q would be moved with unit %q",
+			u.doc.Service, policy.AffinityService, policy.AffinityService, u.doc.Name,
+		)
+	}
+	return ops, nil
+}
+
+// serviceHosts returns the top level host machines of the named
+// service's assigned units, mapped to one of the units placed on
+// each. The unit named by exclude is ignored.
+func serviceHosts(st *State, serviceName, exclude string) (map[string]*Unit, error) {
+	units, err := allUnits(st, serviceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return unitHosts(units, exclude)
+}
+
+// unitHosts returns the top level host machines of the given units,
+// mapped to one of the units placed on each. Unassigned units, and
+// the unit named by exclude, are ignored.
+func unitHosts(units []*Unit, exclude string) (map[string]*Unit, error) {
+	hosts := make(map[string]*Unit)
+	for _, unit := range units {
+		if unit.Name() == exclude {
+			continue
+		}
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		hosts[TopParentId(machineId)] = unit
+	}
+	return hosts, nil
+}
+
+// machineZone returns the availability zone of the identified machine,
+// or "" if it is not known.
+func machineZone(st *State, machineId string) (string, error) {
+	m, err := st.Machine(machineId)
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	zone, err := m.AvailabilityZone()
+	if errors.IsNotProvisioned(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	return zone, nil
+}
+
+// serviceZones returns the names of the named service's assigned units,
+// keyed by the availability zone of each unit's top level host machine.
+// A machine's zone is the one it was provisioned in or, if it has not
+// yet been provisioned, the one requested by its placement directive.
+// Units whose zone is not known, and the unit named by exclude, are
+// ignored.
+func serviceZones(st *State, serviceName, exclude string) (map[string][]string, error) {
+	units, err := allUnits(st, serviceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	zones := make(map[string][]string)
+	hostZones := make(map[string]string)
+	for _, unit := range units {
+		if unit.Name() == exclude {
+			continue
+		}
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		hostId := TopParentId(machineId)
+		zone, ok := hostZones[hostId]
+		if !ok {
+			if zone, err = intendedMachineZone(st, hostId); err != nil {
+				return nil, errors.Trace(err)
+			}
+			hostZones[hostId] = zone
+		}
+		if zone != "" {
+			zones[zone] = append(zones[zone], unit.Name())
+		}
+	}
+	return zones, nil
+}
+
+// intendedMachineZone returns the availability zone of the identified
+// machine or, if it is not yet known, the zone requested by the
+// machine's placement directive, if any.
+func intendedMachineZone(st *State, machineId string) (string, error) {
+	zone, err := machineZone(st, machineId)
+	if err != nil || zone != "" {
+		return zone, err
+	}
+	m, err := st.Machine(machineId)
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	if placement := m.Placement(); strings.HasPrefix(placement, "zone=") {
+		return strings.TrimPrefix(placement, "zone="), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,300 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type PlacementPolicySuite struct {
+	ConnSuite
+	wordpress *state.Service
+}
+
+var _ = gc.Suite(&PlacementPolicySuite{})
+
+func (s *PlacementPolicySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *PlacementPolicySuite) setPolicy(c *gc.C, policy state.PlacementPolicy) {
+	err := s.wordpress.SetPlacementPolicy(policy)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *PlacementPolicySuite) addMachine(c *gc.C, zone string) *state.Machine {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	if zone != "" {
+		err = m.SetProvisioned(instance.Id("i-"+m.Id()), "fake_nonce", &instance.HardwareCharacteristics{
+			AvailabilityZone: &zone,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return m
+}
+
+func (s *PlacementPolicySuite) TestSetPlacementPolicy(c *gc.C) {
+	_, err := s.wordpress.PlacementPolicy()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	policy := state.PlacementPolicy{
+		MaxUnits:        3,
+		AntiAffinity:    state.AntiAffinityMachine,
+		AffinityService: "mysql",
+	}
+	s.setPolicy(c, policy)
+	result, err := s.wordpress.PlacementPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, policy)
+
+	// Setting a policy again replaces it.
+	s.setPolicy(c, state.PlacementPolicy{AntiAffinity: state.AntiAffinityZone})
+	result, err = s.wordpress.PlacementPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, state.PlacementPolicy{AntiAffinity: state.AntiAffinityZone})
+
+	err = s.wordpress.ClearPlacementPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.wordpress.PlacementPolicy()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Clearing an absent policy is not an error.
+	err = s.wordpress.ClearPlacementPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *PlacementPolicySuite) TestSetPlacementPolicyInvalid(c *gc.C) {
+	for i, test := range []struct {
+		policy state.PlacementPolicy
+		err    string
+	}{{
+		policy: state.PlacementPolicy{MaxUnits: -1},
+		err:    "maximum units -1 not valid",
+	}, {
+		policy: state.PlacementPolicy{AntiAffinity: "rack"},
+		err:    `anti-affinity "rack" not valid`,
+	}, {
+		policy: state.PlacementPolicy{AffinityService: "no/good"},
+		err:    `affinity service name "no/good" not valid`,
+	}, {
+		policy: state.PlacementPolicy{AffinityService: "wordpress"},
+		err:    "service cannot have affinity with itself",
+	}} {
+		c.Logf("test %d", i)
+		err := s.wordpress.SetPlacementPolicy(test.policy)
+		c.Check(err, gc.ErrorMatches, `cannot set placement policy for service "wordpress": `+test.err)
+	}
+}
+
+func (s *PlacementPolicySuite) TestSetPlacementPolicySubordinate(c *gc.C) {
+	logging := s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+	err := logging.SetPlacementPolicy(state.PlacementPolicy{MaxUnits: 1})
+	c.Assert(err, gc.ErrorMatches, `cannot set placement policy for service "logging": service is subordinate`)
+}
+
+func (s *PlacementPolicySuite) TestSetPlacementPolicyTooManyUnits(c *gc.C) {
+	for i := 0; i < 2; i++ {
+		_, err := s.wordpress.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := s.wordpress.SetPlacementPolicy(state.PlacementPolicy{MaxUnits: 1})
+	c.Assert(err, gc.ErrorMatches, `cannot set placement policy for service "wordpress": service has 2 units, more than the maximum of 1`)
+}
+
+func (s *PlacementPolicySuite) TestMaxUnits(c *gc.C) {
+	s.setPolicy(c, state.PlacementPolicy{MaxUnits: 2})
+	for i := 0; i < 2; i++ {
+		_, err := s.wordpress.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err := s.wordpress.AddUnit()
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to service "wordpress": placement policy allows at most 2 units`)
+
+	// Once a unit is removed, another may be added.
+	unit, err := s.State.Unit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *PlacementPolicySuite) TestMachineAntiAffinity(c *gc.C) {
+	s.setPolicy(c, state.PlacementPolicy{AntiAffinity: state.AntiAffinityMachine})
+	m := s.addMachine(c, "")
+	unit0, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit0.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit1, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit1.AssignToMachine(m)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/1" to machine 0: placement policy forbids units of service "wordpress" on the same machine, but machine 0 hosts unit "wordpress/0"`)
+
+	// Containers on the machine are considered to be on the machine.
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, m.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit1.AssignToMachine(container)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/1" to machine 0/lxc/0: placement policy forbids .* machine 0 hosts unit "wordpress/0"`)
+
+	// Other machines may be used.
+	other := s.addMachine(c, "")
+	err = unit1.AssignToMachine(other)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *PlacementPolicySuite) TestMachineAntiAffinityConcurrentAssign(c *gc.C) {
+	s.setPolicy(c, state.PlacementPolicy{AntiAffinity: state.AntiAffinityMachine})
+	m := s.addMachine(c, "")
+	unit0, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	unit1, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := unit0.AssignToMachine(m)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err = unit1.AssignToMachine(m)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/1" to machine 0: placement policy forbids .* machine 0 hosts unit "wordpress/0"`)
+	err = unit1.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = unit1.AssignedMachineId()
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+}
+
+func (s *PlacementPolicySuite) TestZoneAntiAffinityConcurrentAssign(c *gc.C) {
+	s.setPolicy(c, state.PlacementPolicy{AntiAffinity: state.AntiAffinityZone})
+	zone1a := s.addMachine(c, "zone1")
+	zone1b := s.addMachine(c, "zone1")
+	unit0, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	unit1, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := unit0.AssignToMachine(zone1a)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err = unit1.AssignToMachine(zone1b)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/1" to machine 1: placement policy forbids .* zone "zone1" hosts unit "wordpress/0"`)
+}
+
+func (s *PlacementPolicySuite) TestServiceAffinityConcurrentUnassign(c *gc.C) {
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.setPolicy(c, state.PlacementPolicy{AffinityService: "mysql"})
+	withMysql := s.addMachine(c, "")
+	mysqlUnit, err := mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysqlUnit.AssignToMachine(withMysql)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := mysqlUnit.UnassignFromMachine()
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err = unit.AssignToMachine(withMysql)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: placement policy requires .* machine 0 hosts no units of "mysql"`)
+}
+
+func (s *PlacementPolicySuite) TestZoneAntiAffinity(c *gc.C) {
+	s.setPolicy(c, state.PlacementPolicy{AntiAffinity: state.AntiAffinityZone})
+	zone1a := s.addMachine(c, "zone1")
+	zone1b := s.addMachine(c, "zone1")
+	zone2 := s.addMachine(c, "zone2")
+	unknown := s.addMachine(c, "")
+
+	unit0, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit0.AssignToMachine(zone1a)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit1, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit1.AssignToMachine(zone1b)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/1" to machine 1: placement policy forbids units of service "wordpress" in the same availability zone, but zone "zone1" hosts unit "wordpress/0"`)
+	err = unit1.AssignToMachine(zone2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Machines whose zone is not known are not restricted.
+	unit2, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit2.AssignToMachine(unknown)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *PlacementPolicySuite) TestServiceAffinity(c *gc.C) {
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.setPolicy(c, state.PlacementPolicy{AffinityService: "mysql"})
+	withMysql := s.addMachine(c, "")
+	without := s.addMachine(c, "")
+	mysqlUnit, err := mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysqlUnit.AssignToMachine(withMysql)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(without)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 1: placement policy requires units of service "wordpress" to be placed with service "mysql", but machine 1 hosts no units of "mysql"`)
+	err = unit.AssignToNewMachine()
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to new machine: placement policy requires units of service "wordpress" to be placed on a machine hosting service "mysql"`)
+	err = s.State.AssignUnit(unit, state.AssignClean)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine: .* placement policy requires units of service "wordpress" to be placed on a machine hosting service "mysql"`)
+
+	err = unit.AssignToMachine(withMysql)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *PlacementPolicySuite) TestAssignToCleanMachineSkipsViolatingMachines(c *gc.C) {
+	s.setPolicy(c, state.PlacementPolicy{AntiAffinity: state.AntiAffinityZone})
+	zone1a := s.addMachine(c, "zone1")
+	s.addMachine(c, "zone1")
+	zone2 := s.addMachine(c, "zone2")
+
+	unit0, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit0.AssignToMachine(zone1a)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit1, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := unit1.AssignToCleanMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, zone2.Id())
+
+	// With no suitable clean machines left, none is chosen.
+	unit2, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = unit2.AssignToCleanMachine()
+	c.Assert(err, gc.ErrorMatches, "all eligible machines in use")
+}
+
+func (s *PlacementPolicySuite) TestRemoveServiceRemovesPlacementPolicy(c *gc.C) {
+	s.setPolicy(c, state.PlacementPolicy{MaxUnits: 1})
+	err := s.wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err = s.wordpress.PlacementPolicy()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
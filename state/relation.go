@@ -147,7 +147,7 @@ func (r *Relation) destroyOps(ignoreService string) (ops []txn.Op, isRemove bool
 		return nil, false, errAlreadyDying
 	}
 	if r.doc.UnitCount == 0 {
-		removeOps, err := r.removeOps(ignoreService, nil)
+		removeOps, err := r.removeOps(ignoreService, "")
 		if err != nil {
 			return nil, false, err
 		}
@@ -163,16 +163,21 @@ func (r *Relation) destroyOps(ignoreService string) (ops []txn.Op, isRemove bool
 
 // removeOps returns the operations necessary to remove the relation. If
 // ignoreService is not empty, no operations affecting that service will be
-// included; if departingUnit is not nil, this implies that the relation's
-// services may be Dying and otherwise unreferenced, and may thus require
-// removal themselves.
-func (r *Relation) removeOps(ignoreService string, departingUnit *Unit) ([]txn.Op, error) {
+// included; if departingUnitName is not empty, this implies that the
+// relation's services may be Dying and otherwise unreferenced, and may thus
+// require removal themselves. The departing unit may be a remote unit.
+func (r *Relation) removeOps(ignoreService string, departingUnitName string) ([]txn.Op, error) {
 	relOp := txn.Op{
 		C:      relationsC,
 		Id:     r.doc.DocID,
 		Remove: true,
 	}
-	if departingUnit != nil {
+	var departingService string
+	if departingUnitName != "" {
+		var err error
+		if departingService, err = names.UnitService(departingUnitName); err != nil {
+			return nil, err
+		}
 		relOp.Assert = bson.D{{"life", Dying}, {"unitcount", 1}}
 	} else {
 		relOp.Assert = bson.D{{"life", Alive}, {"unitcount", 0}}
@@ -182,14 +187,26 @@ func (r *Relation) removeOps(ignoreService string, departingUnit *Unit) ([]txn.O
 		if ep.ServiceName == ignoreService {
 			continue
 		}
+		remote, err := isRemoteService(r.st, ep.ServiceName)
+		if err != nil {
+			return nil, err
+		}
+		if remote {
+			op, err := r.removeRemoteServiceRefOp(ep.ServiceName, departingUnitName != "")
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, op)
+			continue
+		}
 		var asserts bson.D
 		hasRelation := bson.D{{"relationcount", bson.D{{"$gt", 0}}}}
-		if departingUnit == nil {
+		if departingUnitName == "" {
 			// We're constructing a destroy operation, either of the relation
 			// or one of its services, and can therefore be assured that both
 			// services are Alive.
 			asserts = append(hasRelation, isAliveDoc...)
-		} else if ep.ServiceName == departingUnit.ServiceName() {
+		} else if ep.ServiceName == departingService {
 			// This service must have at least one unit -- the one that's
 			// departing the relation -- so it cannot be ready for removal.
 			cannotDieYet := bson.D{{"unitcount", bson.D{{"$gt", 0}}}}
@@ -227,6 +244,41 @@ func (r *Relation) removeOps(ignoreService string, departingUnit *Unit) ([]txn.O
 	return append(ops, cleanupOp), nil
 }
 
+// removeRemoteServiceRefOp returns the operation necessary to remove the
+// relation's reference to the named remote service, or to remove the
+// remote service itself if it is Dying and this is its last reference.
+// Remote services have no units to keep them alive, so this is true
+// even when the departing unit is one of the remote service's own.
+func (r *Relation) removeRemoteServiceRefOp(name string, departing bool) (txn.Op, error) {
+	op := txn.Op{
+		C:      remoteServicesC,
+		Id:     r.st.docID(name),
+		Update: bson.D{{"$inc", bson.D{{"relationcount", -1}}}},
+	}
+	if !departing {
+		// We're constructing a destroy operation, and can therefore
+		// be assured that the remote service is Alive.
+		op.Assert = append(bson.D{{"relationcount", bson.D{{"$gt", 0}}}}, isAliveDoc...)
+		return op, nil
+	}
+	remoteServices, closer := r.st.getCollection(remoteServicesC)
+	defer closer()
+
+	hasLastRef := bson.D{{"life", Dying}, {"relationcount", 1}}
+	removable := append(bson.D{{"_id", name}}, hasLastRef...)
+	svc := &RemoteService{st: r.st}
+	if err := remoteServices.Find(removable).One(&svc.doc); err == nil {
+		return svc.removeOps(hasLastRef), nil
+	} else if err != mgo.ErrNotFound {
+		return txn.Op{}, err
+	}
+	op.Assert = bson.D{{"$or", []bson.D{
+		{{"life", Alive}},
+		{{"relationcount", bson.D{{"$gt", 1}}}},
+	}}}
+	return op, nil
+}
+
 // Id returns the integer internal relation key. This is exposed
 // because the unit agent needs to expose a value derived from this
 // (as JUJU_RELATION_ID) to allow relation hooks to differentiate
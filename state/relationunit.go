@@ -226,7 +226,7 @@ func (ru *RelationUnit) subordinateOps() ([]txn.Op, string, error) {
 		if err != nil {
 			return nil, "", err
 		}
-		_, ops, err := service.addUnitOps(unitName, nil)
+		_, ops, err := service.addUnitOps(unitName, nil, nil)
 		return ops, "", err
 	} else if err != nil {
 		return nil, "", err
@@ -335,7 +335,7 @@ func (ru *RelationUnit) LeaveScope() error {
 				Update: bson.D{{"$inc", bson.D{{"unitcount", -1}}}},
 			})
 		} else {
-			relOps, err := ru.relation.removeOps("", ru.unit)
+			relOps, err := ru.relation.removeOps("", ru.unit.Name())
 			if err != nil {
 				return nil, err
 			}
@@ -0,0 +1,243 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// RemoteRelationUnit represents a unit of a remote service in a relation.
+// Remote units have no presence in the environment other than their
+// relation scope and settings, which are maintained on their behalf by
+// the remote relations worker.
+type RemoteRelationUnit struct {
+	st       *State
+	relation *Relation
+	unitName string
+	endpoint Endpoint
+	scope    string
+}
+
+// RemoteUnit returns a RemoteRelationUnit for the unit of a remote
+// service with the supplied name.
+func (r *Relation) RemoteUnit(unitName string) (*RemoteRelationUnit, error) {
+	serviceName, err := names.UnitService(unitName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ep, err := r.Endpoint(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if remote, err := isRemoteService(r.st, serviceName); err != nil {
+		return nil, err
+	} else if !remote {
+		return nil, errors.Errorf("service %q is not a remote service", serviceName)
+	}
+	return &RemoteRelationUnit{
+		st:       r.st,
+		relation: r,
+		unitName: unitName,
+		endpoint: ep,
+		scope:    "r#" + strconv.Itoa(r.doc.Id),
+	}, nil
+}
+
+// UnitName returns the name of the remote unit.
+func (ru *RemoteRelationUnit) UnitName() string {
+	return ru.unitName
+}
+
+// key returns the key for the remote unit within the relation in the
+// settings and relationScopes collections.
+func (ru *RemoteRelationUnit) key() string {
+	return strings.Join([]string{ru.scope, string(ru.endpoint.Role), ru.unitName}, "#")
+}
+
+// InScope returns whether the remote unit has entered scope and not
+// left it.
+func (ru *RemoteRelationUnit) InScope() (bool, error) {
+	relationScopes, closer := ru.st.getCollection(relationScopesC)
+	defer closer()
+
+	count, err := relationScopes.FindId(ru.key()).Count()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// EnterScope ensures that the remote unit has entered its scope in the
+// relation, with the supplied settings. When the remote unit has already
+// entered its relation scope, EnterScope will report success but make no
+// changes to state; use ReplaceSettings to update the settings of a
+// remote unit in scope.
+func (ru *RemoteRelationUnit) EnterScope(settings map[string]interface{}) error {
+	key := ru.key()
+	if inScope, err := ru.InScope(); err != nil {
+		return err
+	} else if inScope {
+		return nil
+	}
+	ops := []txn.Op{{
+		C:      relationsC,
+		Id:     ru.relation.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$inc", bson.D{{"unitcount", 1}}}},
+	}}
+	settingsColl, closer := ru.st.getCollection(settingsC)
+	defer closer()
+	if count, err := settingsColl.FindId(key).Count(); err != nil {
+		return err
+	} else if count == 0 {
+		ops = append(ops, createSettingsOp(ru.st, key, settings))
+	} else {
+		rop, _, err := replaceSettingsOp(ru.st, key, settings)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, rop)
+	}
+	rsDocID := ru.st.docID(key)
+	ops = append(ops, txn.Op{
+		C:      relationScopesC,
+		Id:     rsDocID,
+		Assert: txn.DocMissing,
+		Insert: relationScopeDoc{
+			DocID:   rsDocID,
+			Key:     key,
+			EnvUUID: ru.st.EnvironUUID(),
+		},
+	})
+	if err := ru.st.runTransaction(ops); err != txn.ErrAborted {
+		return err
+	}
+	if inScope, err := ru.InScope(); err != nil {
+		return err
+	} else if inScope {
+		return nil
+	}
+	return ErrCannotEnterScope
+}
+
+// ReplaceSettings replaces the settings of the remote unit within the
+// relation.
+func (ru *RemoteRelationUnit) ReplaceSettings(settings map[string]interface{}) error {
+	node, err := readSettings(ru.st, ru.key())
+	if err != nil {
+		return errors.Annotatef(err, "cannot replace settings for remote unit %q in relation %q", ru.unitName, ru.relation)
+	}
+	for key := range node.Map() {
+		if _, ok := settings[key]; !ok {
+			node.Delete(key)
+		}
+	}
+	node.Update(settings)
+	if _, err := node.Write(); err != nil {
+		return errors.Annotatef(err, "cannot replace settings for remote unit %q in relation %q", ru.unitName, ru.relation)
+	}
+	return nil
+}
+
+// Settings returns the settings of the remote unit within the relation.
+func (ru *RemoteRelationUnit) Settings() (map[string]interface{}, error) {
+	node, err := readSettings(ru.st, ru.key())
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read settings for remote unit %q in relation %q", ru.unitName, ru.relation)
+	}
+	return node.Map(), nil
+}
+
+// LeaveScope signals that the remote unit has left its scope in the
+// relation. If the relation is dying when its last member unit leaves,
+// it is removed immediately. It is not an error to leave a scope that
+// the remote unit is not, or never was, a member of.
+func (ru *RemoteRelationUnit) LeaveScope() error {
+	relationScopes, closer := ru.st.getCollection(relationScopesC)
+	defer closer()
+
+	key := ru.key()
+	desc := fmt.Sprintf("remote unit %q in relation %q", ru.unitName, ru.relation)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := ru.relation.Refresh(); errors.IsNotFound(err) {
+				return nil, jujutxn.ErrNoOperations
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		count, err := relationScopes.FindId(key).Count()
+		if err != nil {
+			return nil, fmt.Errorf("cannot examine scope for %s: %v", desc, err)
+		} else if count == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		ops := []txn.Op{{
+			C:      relationScopesC,
+			Id:     ru.st.docID(key),
+			Assert: txn.DocExists,
+			Remove: true,
+		}}
+		if ru.relation.doc.Life == Alive {
+			ops = append(ops, txn.Op{
+				C:      relationsC,
+				Id:     ru.relation.doc.DocID,
+				Assert: bson.D{{"life", Alive}},
+				Update: bson.D{{"$inc", bson.D{{"unitcount", -1}}}},
+			})
+		} else if ru.relation.doc.UnitCount > 1 {
+			ops = append(ops, txn.Op{
+				C:      relationsC,
+				Id:     ru.relation.doc.DocID,
+				Assert: bson.D{{"unitcount", bson.D{{"$gt", 1}}}},
+				Update: bson.D{{"$inc", bson.D{{"unitcount", -1}}}},
+			})
+		} else {
+			relOps, err := ru.relation.removeOps("", ru.unitName)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, relOps...)
+		}
+		return ops, nil
+	}
+	if err := ru.st.run(buildTxn); err != nil {
+		return fmt.Errorf("cannot leave scope for %s: %v", desc, err)
+	}
+	return nil
+}
+
+// UnitsInScope returns the names of the units of the named service,
+// local or remote, that are in scope in the relation. It is intended
+// for relations with remote services, and so for relations of global
+// scope only.
+func (r *Relation) UnitsInScope(serviceName string) ([]string, error) {
+	ep, err := r.Endpoint(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	relationScopes, closer := r.st.getCollection(relationScopesC)
+	defer closer()
+
+	prefix := fmt.Sprintf("r#%d#%s#%s/", r.doc.Id, ep.Role, serviceName)
+	sel := bson.D{{"key", bson.D{{"$regex", "^" + regexp.QuoteMeta(prefix)}}}}
+	var docs []relationScopeDoc
+	if err := relationScopes.Find(sel).Sort("key").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get units in scope of relation %q", r)
+	}
+	unitNames := make([]string, len(docs))
+	for i, doc := range docs {
+		unitNames[i] = doc.unitName()
+	}
+	return unitNames, nil
+}
@@ -0,0 +1,369 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// RemoteService represents, in the environment consuming it, a service
+// offered by another environment hosted by the same state server. Local
+// services may be related to a remote service exactly as to any other;
+// the units of the offered service appear in those relations as remote
+// units, whose settings are synchronised between the environments by
+// the remote relations worker.
+type RemoteService struct {
+	st  *State
+	doc remoteServiceDoc
+}
+
+// remoteServiceDoc represents the internal state of a remote service
+// in MongoDB.
+type remoteServiceDoc struct {
+	DocID         string           `bson:"_id"`
+	Name          string           `bson:"name"`
+	EnvUUID       string           `bson:"env-uuid"`
+	SourceEnvUUID string           `bson:"source-env-uuid"`
+	OfferName     string           `bson:"offer-name"`
+	Endpoints     []charm.Relation `bson:"endpoints"`
+	Life          Life             `bson:"life"`
+	RelationCount int              `bson:"relationcount"`
+}
+
+// AddRemoteServiceParams defines the parameters for adding a remote
+// service.
+type AddRemoteServiceParams struct {
+	// Name is the name of the remote service in this environment.
+	Name string
+
+	// SourceEnvUUID is the UUID of the environment offering the
+	// service.
+	SourceEnvUUID string
+
+	// OfferName is the name of the offer in the source environment.
+	// It is empty for remote services standing in, in the offering
+	// environment, for the services of consuming environments.
+	OfferName string
+
+	// Endpoints holds the offered relation endpoints.
+	Endpoints []charm.Relation
+}
+
+// Name returns the name of the remote service.
+func (s *RemoteService) Name() string {
+	return s.doc.Name
+}
+
+// String returns the name of the remote service.
+func (s *RemoteService) String() string {
+	return s.doc.Name
+}
+
+// Tag returns a name identifying the remote service.
+func (s *RemoteService) Tag() names.Tag {
+	return names.NewServiceTag(s.doc.Name)
+}
+
+// SourceEnvironTag returns the tag of the environment offering the
+// service.
+func (s *RemoteService) SourceEnvironTag() names.EnvironTag {
+	return names.NewEnvironTag(s.doc.SourceEnvUUID)
+}
+
+// OfferName returns the name of the offer in the source environment.
+func (s *RemoteService) OfferName() string {
+	return s.doc.OfferName
+}
+
+// Life returns whether the remote service is Alive or Dying.
+func (s *RemoteService) Life() Life {
+	return s.doc.Life
+}
+
+// Endpoints returns the remote service's relation endpoints.
+func (s *RemoteService) Endpoints() ([]Endpoint, error) {
+	eps := make([]Endpoint, len(s.doc.Endpoints))
+	for i, rel := range s.doc.Endpoints {
+		eps[i] = Endpoint{
+			ServiceName: s.doc.Name,
+			Relation:    rel,
+		}
+	}
+	sort.Sort(epSlice(eps))
+	return eps, nil
+}
+
+// Endpoint returns the relation endpoint with the supplied name, if it
+// exists.
+func (s *RemoteService) Endpoint(relationName string) (Endpoint, error) {
+	for _, rel := range s.doc.Endpoints {
+		if rel.Name == relationName {
+			return Endpoint{ServiceName: s.doc.Name, Relation: rel}, nil
+		}
+	}
+	return Endpoint{}, fmt.Errorf("remote service %q has no %q relation", s, relationName)
+}
+
+// Relations returns the relations in which the remote service
+// participates.
+func (s *RemoteService) Relations() ([]*Relation, error) {
+	return serviceRelations(s.st, s.doc.Name)
+}
+
+// Refresh refreshes the contents of the remote service from the
+// underlying state. It returns an error that satisfies errors.IsNotFound
+// if the remote service has been removed.
+func (s *RemoteService) Refresh() error {
+	remoteServices, closer := s.st.getCollection(remoteServicesC)
+	defer closer()
+
+	err := remoteServices.FindId(s.doc.DocID).One(&s.doc)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("remote service %q", s)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot refresh remote service %q", s)
+	}
+	return nil
+}
+
+// Destroy ensures that the remote service and all its relations will be
+// removed at some point; if no relation involving the remote service has
+// any units in scope, they are all removed immediately.
+func (s *RemoteService) Destroy() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot destroy remote service %q", s)
+	defer func() {
+		if err == nil {
+			// This is a white lie; the document might actually be removed.
+			s.doc.Life = Dying
+		}
+	}()
+	svc := &RemoteService{st: s.st, doc: s.doc}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := svc.Refresh(); errors.IsNotFound(err) {
+				return nil, jujutxn.ErrNoOperations
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		switch ops, err := svc.destroyOps(); err {
+		case errRefresh:
+		case errAlreadyDying:
+			return nil, jujutxn.ErrNoOperations
+		case nil:
+			return ops, nil
+		default:
+			return nil, err
+		}
+		return nil, jujutxn.ErrTransientFailure
+	}
+	return s.st.run(buildTxn)
+}
+
+// destroyOps returns the operations required to destroy the remote
+// service. If it returns errRefresh, the remote service should be
+// refreshed and the destruction operations recalculated.
+func (s *RemoteService) destroyOps() ([]txn.Op, error) {
+	if s.doc.Life == Dying {
+		return nil, errAlreadyDying
+	}
+	rels, err := s.Relations()
+	if err != nil {
+		return nil, err
+	}
+	if len(rels) != s.doc.RelationCount {
+		return nil, errRefresh
+	}
+	var ops []txn.Op
+	removeCount := 0
+	for _, rel := range rels {
+		relOps, isRemove, err := rel.destroyOps(s.doc.Name)
+		if err == errAlreadyDying {
+			relOps = []txn.Op{{
+				C:      relationsC,
+				Id:     rel.doc.DocID,
+				Assert: bson.D{{"life", Dying}},
+			}}
+		} else if err != nil {
+			return nil, err
+		}
+		if isRemove {
+			removeCount++
+		}
+		ops = append(ops, relOps...)
+	}
+	// If all the remote service's relations will be removed, it can also
+	// be removed; otherwise, it will be removed with the last relation
+	// referencing it.
+	if s.doc.RelationCount == removeCount {
+		hasLastRefs := bson.D{{"life", Alive}, {"relationcount", removeCount}}
+		return append(ops, s.removeOps(hasLastRefs)), nil
+	}
+	update := bson.D{{"$set", bson.D{{"life", Dying}}}}
+	if removeCount != 0 {
+		decref := bson.D{{"$inc", bson.D{{"relationcount", -removeCount}}}}
+		update = append(update, decref...)
+	}
+	return append(ops, txn.Op{
+		C:      remoteServicesC,
+		Id:     s.doc.DocID,
+		Assert: bson.D{{"life", Alive}, {"relationcount", s.doc.RelationCount}},
+		Update: update,
+	}), nil
+}
+
+// removeOps returns the operation required to remove the remote
+// service. Supplied asserts will be included in the operation.
+func (s *RemoteService) removeOps(asserts bson.D) txn.Op {
+	return txn.Op{
+		C:      remoteServicesC,
+		Id:     s.doc.DocID,
+		Assert: asserts,
+		Remove: true,
+	}
+}
+
+// AddRemoteService creates a new remote service, consuming the offer
+// with the given name from the source environment. The name must be
+// unique among both the services and the remote services of the
+// environment.
+func (st *State) AddRemoteService(p AddRemoteServiceParams) (_ *RemoteService, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add remote service %q", p.Name)
+	if !names.IsValidService(p.Name) {
+		return nil, errors.Errorf("invalid name")
+	}
+	if !names.IsValidEnvironment(p.SourceEnvUUID) {
+		return nil, errors.NotValidf("source environment UUID %q", p.SourceEnvUUID)
+	}
+	if p.SourceEnvUUID == st.EnvironUUID() {
+		return nil, errors.Errorf("cannot consume an offer from the same environment")
+	}
+	if len(p.Endpoints) == 0 {
+		return nil, errors.Errorf("no endpoints")
+	}
+	for _, ep := range p.Endpoints {
+		if ep.Role == charm.RolePeer || ep.Scope != charm.ScopeGlobal {
+			return nil, errors.Errorf("endpoint %q cannot be consumed", ep.Name)
+		}
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	} else if env.Life() != Alive {
+		return nil, errors.Errorf("environment is no longer alive")
+	}
+	docID := st.docID(p.Name)
+	doc := remoteServiceDoc{
+		DocID:         docID,
+		Name:          p.Name,
+		EnvUUID:       st.EnvironUUID(),
+		SourceEnvUUID: p.SourceEnvUUID,
+		OfferName:     p.OfferName,
+		Endpoints:     p.Endpoints,
+		Life:          Alive,
+	}
+	ops := []txn.Op{
+		env.assertAliveOp(),
+		{
+			C:      servicesC,
+			Id:     docID,
+			Assert: txn.DocMissing,
+		}, {
+			C:      remoteServicesC,
+			Id:     docID,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		},
+	}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		err := env.Refresh()
+		if (err == nil && env.Life() != Alive) || errors.IsNotFound(err) {
+			return nil, errors.Errorf("environment is no longer alive")
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return nil, errors.Errorf("service already exists")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &RemoteService{st: st, doc: doc}, nil
+}
+
+// RemoteService returns the remote service with the given name.
+func (st *State) RemoteService(name string) (*RemoteService, error) {
+	if !names.IsValidService(name) {
+		return nil, errors.Errorf("%q is not a valid service name", name)
+	}
+	remoteServices, closer := st.getCollection(remoteServicesC)
+	defer closer()
+
+	var doc remoteServiceDoc
+	err := remoteServices.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("remote service %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get remote service %q", name)
+	}
+	return &RemoteService{st: st, doc: doc}, nil
+}
+
+// AllRemoteServices returns all the remote services consumed by the
+// environment.
+func (st *State) AllRemoteServices() ([]*RemoteService, error) {
+	remoteServices, closer := st.getCollection(remoteServicesC)
+	defer closer()
+
+	var docs []remoteServiceDoc
+	if err := remoteServices.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get remote services")
+	}
+	result := make([]*RemoteService, len(docs))
+	for i, doc := range docs {
+		result[i] = &RemoteService{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// ProxyEnvironUUIDs returns the UUIDs of the environments in which
+// remote services stand in for services of this environment, because
+// those services have been related to services offered there.
+func (st *State) ProxyEnvironUUIDs() ([]string, error) {
+	remoteServices, closer := st.getRawCollection(remoteServicesC)
+	defer closer()
+
+	var uuids []string
+	err := remoteServices.Find(bson.D{
+		{"source-env-uuid", st.EnvironUUID()},
+		{"offer-name", ""},
+	}).Distinct("env-uuid", &uuids)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get environments of proxy services")
+	}
+	sort.Strings(uuids)
+	return uuids, nil
+}
+
+// isRemoteService returns whether the named service is a remote
+// service.
+func isRemoteService(st *State, name string) (bool, error) {
+	remoteServices, closer := st.getCollection(remoteServicesC)
+	defer closer()
+
+	n, err := remoteServices.FindId(name).Count()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return n > 0, nil
+}
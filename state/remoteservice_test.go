@@ -0,0 +1,232 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/state"
+)
+
+type RemoteServiceSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&RemoteServiceSuite{})
+
+const sourceEnvUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+var mysqlServerRelation = charm.Relation{
+	Name:      "server",
+	Role:      charm.RoleProvider,
+	Interface: "mysql",
+	Scope:     charm.ScopeGlobal,
+}
+
+func (s *RemoteServiceSuite) addRemoteMySQL(c *gc.C) *state.RemoteService {
+	svc, err := s.State.AddRemoteService(state.AddRemoteServiceParams{
+		Name:          "mysql",
+		SourceEnvUUID: sourceEnvUUID,
+		OfferName:     "db",
+		Endpoints:     []charm.Relation{mysqlServerRelation},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return svc
+}
+
+func (s *RemoteServiceSuite) addRelation(c *gc.C) *state.Relation {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.addRemoteMySQL(c)
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	return rel
+}
+
+func (s *RemoteServiceSuite) TestAddRemoteService(c *gc.C) {
+	s.addRemoteMySQL(c)
+	svc, err := s.State.RemoteService("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.Name(), gc.Equals, "mysql")
+	c.Assert(svc.SourceEnvironTag().Id(), gc.Equals, sourceEnvUUID)
+	c.Assert(svc.OfferName(), gc.Equals, "db")
+	c.Assert(svc.Life(), gc.Equals, state.Alive)
+	ep, err := svc.Endpoint("server")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ep, jc.DeepEquals, state.Endpoint{ServiceName: "mysql", Relation: mysqlServerRelation})
+
+	all, err := s.State.AllRemoteServices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+	c.Assert(all[0].Name(), gc.Equals, "mysql")
+}
+
+func (s *RemoteServiceSuite) TestAddRemoteServiceNameClash(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := s.State.AddRemoteService(state.AddRemoteServiceParams{
+		Name:          "wordpress",
+		SourceEnvUUID: sourceEnvUUID,
+		Endpoints:     []charm.Relation{mysqlServerRelation},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add remote service "wordpress": service already exists`)
+
+	s.addRemoteMySQL(c)
+	_, err = s.State.AddService("mysql", s.Owner.String(), s.AddTestingCharm(c, "mysql"), nil, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add service "mysql": remote service already exists`)
+}
+
+func (s *RemoteServiceSuite) TestAddRemoteServiceInvalid(c *gc.C) {
+	_, err := s.State.AddRemoteService(state.AddRemoteServiceParams{
+		Name:          "mysql",
+		SourceEnvUUID: s.State.EnvironUUID(),
+		Endpoints:     []charm.Relation{mysqlServerRelation},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add remote service "mysql": cannot consume an offer from the same environment`)
+
+	_, err = s.State.AddRemoteService(state.AddRemoteServiceParams{
+		Name:          "mysql",
+		SourceEnvUUID: sourceEnvUUID,
+		Endpoints: []charm.Relation{{
+			Name:      "cluster",
+			Role:      charm.RolePeer,
+			Interface: "mysql-ha",
+			Scope:     charm.ScopeGlobal,
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add remote service "mysql": endpoint "cluster" cannot be consumed`)
+}
+
+func (s *RemoteServiceSuite) TestAddRelation(c *gc.C) {
+	rel := s.addRelation(c)
+	c.Assert(rel.String(), gc.Equals, "wordpress:db mysql:server")
+
+	svc, err := s.State.RemoteService("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rels, err := svc.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 1)
+	c.Assert(rels[0].Id(), gc.Equals, rel.Id())
+}
+
+func (s *RemoteServiceSuite) TestRemoteUnitScope(c *gc.C) {
+	rel := s.addRelation(c)
+	ru, err := rel.RemoteUnit("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = ru.EnterScope(map[string]interface{}{"host": "10.0.0.1"})
+	c.Assert(err, jc.ErrorIsNil)
+	inScope, err := ru.InScope()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(inScope, jc.IsTrue)
+	units, err := rel.UnitsInScope("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, jc.DeepEquals, []string{"mysql/0"})
+
+	err = ru.ReplaceSettings(map[string]interface{}{"user": "admin"})
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := ru.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{"user": "admin"})
+
+	// Local units read the remote unit's settings as any other.
+	wordpress, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	localRU, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err = localRU.ReadSettings("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{"user": "admin"})
+
+	err = ru.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	units, err = rel.UnitsInScope("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+}
+
+func (s *RemoteServiceSuite) TestRemoteUnitNotRemote(c *gc.C) {
+	rel := s.addRelation(c)
+	_, err := rel.RemoteUnit("wordpress/0")
+	c.Assert(err, gc.ErrorMatches, `service "wordpress" is not a remote service`)
+}
+
+func (s *RemoteServiceSuite) TestDestroyRemoteService(c *gc.C) {
+	rel := s.addRelation(c)
+	ru, err := rel.RemoteUnit("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// With a unit in scope, the remote service and its relation
+	// remain until the unit leaves.
+	svc, err := s.State.RemoteService("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.Life(), gc.Equals, state.Dying)
+	err = rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rel.Life(), gc.Equals, state.Dying)
+
+	err = ru.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = svc.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoteServiceSuite) TestDestroyLocalServiceDecrefsRemoteService(c *gc.C) {
+	rel := s.addRelation(c)
+	wordpress, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// The remote service has no relations left, and can be
+	// removed immediately.
+	svc, err := s.State.RemoteService("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.RemoteService("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoteServiceSuite) TestProxyEnvironUUIDs(c *gc.C) {
+	otherState := s.factory.MakeEnvironment(c, nil)
+	defer otherState.Close()
+
+	// A remote service consumed from another environment does not
+	// stand in for a service of that environment.
+	s.addRemoteMySQL(c)
+	uuids, err := s.State.ProxyEnvironUUIDs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uuids, gc.HasLen, 0)
+
+	_, err = s.State.AddRemoteService(state.AddRemoteServiceParams{
+		Name:          "wordpress-proxy",
+		SourceEnvUUID: otherState.EnvironUUID(),
+		Endpoints: []charm.Relation{{
+			Name:      "db",
+			Role:      charm.RoleRequirer,
+			Interface: "mysql",
+			Scope:     charm.ScopeGlobal,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	uuids, err = otherState.ProxyEnvironUUIDs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uuids, jc.DeepEquals, []string{s.State.EnvironUUID()})
+}
@@ -0,0 +1,239 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// InstanceStatusMissing is the instance status recorded for a machine
+// whose instance, once seen running, is no longer reported by the
+// provider.
+const InstanceStatusMissing = "missing"
+
+// IsStateless returns whether the service has been marked stateless,
+// allowing its units to be moved to replacement machines when the
+// machines they are assigned to fail. See SetStateless.
+func (s *Service) IsStateless() bool {
+	return s.doc.Stateless
+}
+
+// SetStateless marks the service as stateless, or not. The units of
+// stateless services are moved to replacement machines when the
+// machines they are assigned to fail, if the environment's self-heal
+// setting is enabled.
+func (s *Service) SetStateless(stateless bool) error {
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"stateless", stateless}}}},
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return fmt.Errorf("cannot set stateless flag for service %q to %v: %v", s, stateless, onAbort(err, errNotAlive))
+	}
+	s.doc.Stateless = stateless
+	return nil
+}
+
+// SelfHealEvent records the replacement of a failed machine.
+type SelfHealEvent struct {
+	// Time is when the machine was replaced.
+	Time time.Time
+
+	// MachineId is the id of the failed machine, and ReplacementId
+	// that of the machine that replaced it.
+	MachineId     string
+	ReplacementId string
+
+	// Units holds the names of the units moved to the replacement
+	// machine.
+	Units []string
+
+	// Reason describes why the machine was replaced.
+	Reason string
+}
+
+type selfHealEventDoc struct {
+	Id            int       `bson:"_id"`
+	EnvUUID       string    `bson:"env-uuid"`
+	Time          time.Time `bson:"time"`
+	MachineId     string    `bson:"machineid"`
+	ReplacementId string    `bson:"replacementid"`
+	Units         []string  `bson:"units"`
+	Reason        string    `bson:"reason"`
+}
+
+// ReplaceMachine replaces the given failed machine with a new one,
+// with the same series, jobs and constraints, moves the units assigned
+// to the failed machine to the new one, and queues the failed machine
+// for destruction. The replacement is recorded, with the given reason,
+// in the environment's self-heal history.
+//
+// Only machines hosting units, all of them belonging to stateless
+// services and without storage, may be replaced; containers, machines
+// hosting containers and manually provisioned machines may not. Nor
+// may a machine be replaced if the placement policy of any of its
+// units' services would not hold on the new machine; the returned
+// error then satisfies IsPlacementPolicyError.
+func (st *State) ReplaceMachine(m *Machine, reason string) (_ *Machine, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot replace machine %s", m)
+	if m.Life() != Alive {
+		return nil, errNotAlive
+	}
+	if m.IsManager() {
+		return nil, errors.New("machine is required by the environment")
+	}
+	if m.ContainerType() != "" {
+		return nil, errors.New("machine is a container")
+	}
+	if manual, err := m.IsManual(); err != nil {
+		return nil, errors.Trace(err)
+	} else if manual {
+		return nil, errors.New("machine was manually provisioned")
+	}
+	if containers, err := m.Containers(); err != nil {
+		return nil, errors.Trace(err)
+	} else if len(containers) > 0 {
+		return nil, errors.New("machine hosts containers")
+	}
+	if len(m.doc.Principals) == 0 {
+		return nil, errors.New("machine has no units to move")
+	}
+	var policyOps []txn.Op
+	checked := make(map[string]bool)
+	for _, unitName := range m.doc.Principals {
+		unit, err := st.checkUnitReplaceable(unitName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// The policy holds, or not, for all the service's units.
+		if checked[unit.doc.Service] {
+			continue
+		}
+		checked[unit.doc.Service] = true
+		ops, err := unit.checkReplacementPlacementPolicy(m.doc.Principals)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		policyOps = append(policyOps, ops...)
+	}
+	cons, err := m.Constraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Any placement directive is not carried over, as it may refer
+	// to the failed instance's availability zone.
+	template := MachineTemplate{
+		Series:      m.doc.Series,
+		Constraints: cons,
+		Jobs:        m.doc.Jobs,
+		principals:  m.doc.Principals,
+		Dirty:       true,
+	}
+	mdoc, ops, err := st.addMachineOps(template)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, unitName := range m.doc.Principals {
+		ops = append(ops, txn.Op{
+			C:      unitsC,
+			Id:     st.docID(unitName),
+			Assert: append(isAliveDoc, bson.DocElem{"machineid", m.doc.Id}),
+			Update: bson.D{{"$set", bson.D{{"machineid", mdoc.Id}}}},
+		})
+	}
+	id, err := st.sequence("selfhealevent")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, txn.Op{
+		C:  machinesC,
+		Id: m.doc.DocID,
+		Assert: append(isAliveDoc,
+			bson.DocElem{"principals", m.doc.Principals},
+			hasNoContainersTerm,
+		),
+		Update: bson.D{{"$set", bson.D{{"principals", []string{}}}}},
+	}, st.newCleanupOp(cleanupForceDestroyedMachine, m.doc.Id), txn.Op{
+		C:      selfHealEventsC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: &selfHealEventDoc{
+			EnvUUID:       st.EnvironUUID(),
+			Time:          nowToTheSecond(),
+			MachineId:     m.doc.Id,
+			ReplacementId: mdoc.Id,
+			Units:         m.doc.Principals,
+			Reason:        reason,
+		},
+	}, env.assertAliveOp())
+	ops = append(ops, policyOps...)
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.New("machine, its units or their placement policies changed while replacing it")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	m.doc.Principals = nil
+	return newMachine(st, mdoc), nil
+}
+
+// checkUnitReplaceable returns the named unit, or an error if it may
+// not be moved to a replacement machine.
+func (st *State) checkUnitReplaceable(unitName string) (*Unit, error) {
+	unit, err := st.Unit(unitName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	service, err := unit.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !service.IsStateless() {
+		return nil, errors.Errorf("unit %q belongs to service %q, which is not stateless", unitName, service)
+	}
+	attachments, err := st.UnitStorageAttachments(names.NewUnitTag(unitName))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(attachments) > 0 {
+		return nil, errors.Errorf("unit %q has storage attached", unitName)
+	}
+	return unit, nil
+}
+
+// SelfHealHistory returns at most <size> of the most recent machine
+// replacements made in the environment, newest first.
+func (st *State) SelfHealHistory(size int) ([]SelfHealEvent, error) {
+	history, closer := st.getCollection(selfHealEventsC)
+	defer closer()
+
+	var docs []selfHealEventDoc
+	err := history.Find(nil).Sort("-_id").Limit(size).All(&docs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get self-heal history")
+	}
+	result := make([]SelfHealEvent, len(docs))
+	for i, doc := range docs {
+		result[i] = SelfHealEvent{
+			Time:          doc.Time.UTC(),
+			MachineId:     doc.MachineId,
+			ReplacementId: doc.ReplacementId,
+			Units:         doc.Units,
+			Reason:        doc.Reason,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,219 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+)
+
+type SelfHealSuite struct {
+	ConnSuite
+	service *state.Service
+	machine *state.Machine
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&SelfHealSuite{})
+
+func (s *SelfHealSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.machine, err = s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("mem=4G"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit, err = s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SelfHealSuite) TestSetStateless(c *gc.C) {
+	c.Assert(s.service.IsStateless(), jc.IsFalse)
+	err := s.service.SetStateless(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.IsStateless(), jc.IsTrue)
+
+	service, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.IsStateless(), jc.IsTrue)
+
+	err = service.SetStateless(false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.IsStateless(), jc.IsFalse)
+}
+
+func (s *SelfHealSuite) TestReplaceMachine(c *gc.C) {
+	err := s.service.SetStateless(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	replacement, err := s.State.ReplaceMachine(s.machine, "instance missing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(replacement.Id(), gc.Not(gc.Equals), s.machine.Id())
+	c.Assert(replacement.Series(), gc.Equals, "quantal")
+	c.Assert(replacement.Jobs(), jc.DeepEquals, []state.MachineJob{state.JobHostUnits})
+	cons, err := replacement.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, gc.DeepEquals, constraints.MustParse("mem=4G"))
+
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := s.unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, replacement.Id())
+	units, err := replacement.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+	c.Assert(units[0].Name(), gc.Equals, "wordpress/0")
+
+	// The failed machine is destroyed, leaving its instance for the
+	// provisioner to stop.
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Life(), gc.Equals, state.Dead)
+	units, err = s.machine.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+
+	history, err := s.State.SelfHealHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Time.IsZero(), jc.IsFalse)
+	c.Assert(history[0], jc.DeepEquals, state.SelfHealEvent{
+		Time:          history[0].Time,
+		MachineId:     s.machine.Id(),
+		ReplacementId: replacement.Id(),
+		Units:         []string{"wordpress/0"},
+		Reason:        "instance missing",
+	})
+}
+
+func (s *SelfHealSuite) TestReplaceMachineNotStateless(c *gc.C) {
+	_, err := s.State.ReplaceMachine(s.machine, "agent lost")
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 0: unit "wordpress/0" belongs to service "wordpress", which is not stateless`)
+	s.assertNotReplaced(c)
+}
+
+func (s *SelfHealSuite) TestReplaceMachineNoUnits(c *gc.C) {
+	err := s.unit.UnassignFromMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ReplaceMachine(s.machine, "agent lost")
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 0: machine has no units to move`)
+}
+
+func (s *SelfHealSuite) TestReplaceMachineUnitsChanged(c *gc.C) {
+	err := s.service.SetStateless(true)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The machine's units are read from the stale document, which no
+	// longer matches.
+	_, err = s.State.ReplaceMachine(s.machine, "agent lost")
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 0: machine, its units or their placement policies changed while replacing it`)
+	s.assertNotReplaced(c)
+}
+
+func (s *SelfHealSuite) TestReplaceMachineAffinityNotMoved(c *gc.C) {
+	err := s.service.SetStateless(true)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	err = s.service.SetPlacementPolicy(state.PlacementPolicy{AffinityService: "mysql"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.ReplaceMachine(s.machine, "agent lost")
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 0: placement policy requires units of service "wordpress" to be placed with service "mysql", but no unit of "mysql" would be moved with unit "wordpress/0"`)
+	c.Assert(err, jc.Satisfies, state.IsPlacementPolicyError)
+	s.assertNotReplaced(c)
+}
+
+func (s *SelfHealSuite) TestReplaceMachineAffinityMoved(c *gc.C) {
+	err := s.service.SetStateless(true)
+	c.Assert(err, jc.ErrorIsNil)
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	err = mysql.SetStateless(true)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetPlacementPolicy(state.PlacementPolicy{AffinityService: "mysql"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The units of both services are moved to the new machine.
+	replacement, err := s.State.ReplaceMachine(s.machine, "agent lost")
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := replacement.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 2)
+}
+
+func (s *SelfHealSuite) TestReplaceMachineAntiAffinity(c *gc.C) {
+	err := s.service.SetStateless(true)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetPlacementPolicy(state.PlacementPolicy{AntiAffinity: state.AntiAffinityMachine})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.ReplaceMachine(s.machine, "agent lost")
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 0: placement policy forbids units of service "wordpress" on the same machine, but unit "wordpress/0" would be moved with unit "wordpress/1"`)
+	c.Assert(err, jc.Satisfies, state.IsPlacementPolicyError)
+	s.assertNotReplaced(c)
+
+	// Anti-affinity within availability zones holds, as the new
+	// machine's zone is not yet known.
+	err = s.service.SetPlacementPolicy(state.PlacementPolicy{AntiAffinity: state.AntiAffinityZone})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ReplaceMachine(s.machine, "agent lost")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SelfHealSuite) TestReplaceMachinePlacementPolicyChanged(c *gc.C) {
+	err := s.service.SetStateless(true)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := s.service.SetPlacementPolicy(state.PlacementPolicy{AffinityService: "mysql"})
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	_, err = s.State.ReplaceMachine(s.machine, "agent lost")
+	c.Assert(err, gc.ErrorMatches, `cannot replace machine 0: machine, its units or their placement policies changed while replacing it`)
+	s.assertNotReplaced(c)
+}
+
+func (s *SelfHealSuite) assertNotReplaced(c *gc.C) {
+	err := s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := s.unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, s.machine.Id())
+	history, err := s.State.SelfHealHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 0)
+}
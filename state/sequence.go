@@ -18,6 +18,9 @@ type sequenceDoc struct {
 }
 
 func (s *State) sequence(name string) (int, error) {
+	if s.dryRun {
+		return s.peekSequence(name)
+	}
 	query := s.db.C(sequenceC).FindId(s.docID(name))
 	inc := mgo.Change{
 		Update: bson.M{
@@ -20,6 +20,8 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/storage"
 )
 
 // Service represents the state of a service.
@@ -47,6 +49,19 @@ type serviceDoc struct {
 	OwnerTag          string     `bson:"ownertag"`
 	TxnRevno          int64      `bson:"txn-revno"`
 	MetricCredentials []byte     `bson:"metric-credentials"`
+
+	// StorageDefaults records the service's storage pool defaults,
+	// which override those of the environment.
+	StorageDefaults *StoragePoolDefaults `bson:"storage-defaults,omitempty"`
+
+	// ExposedSourceCIDRs restricts the addresses from which an
+	// exposed service may be accessed. If empty, the service may
+	// be accessed from any address.
+	ExposedSourceCIDRs []string `bson:"exposed-source-cidrs,omitempty"`
+
+	// Stateless records whether the service's units may be moved to
+	// replacement machines when the machines they are assigned to fail.
+	Stateless bool `bson:"stateless,omitempty"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -149,6 +164,12 @@ func (s *Service) destroyOps() ([]txn.Op, error) {
 		return nil, errRefresh
 	}
 	ops := []txn.Op{minUnitsRemoveOp(s.st, s.doc.Name)}
+	// A dying service can no longer be consumed by other environments.
+	offerOps, err := removeServiceOffersOps(s.st, s.doc.Name)
+	if err != nil {
+		return nil, err
+	}
+	ops = append(ops, offerOps...)
 	removeCount := 0
 	for _, rel := range rels {
 		relOps, isRemove, err := rel.destroyOps(s.doc.Name)
@@ -230,6 +251,12 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		removeConstraintsOp(s.st, s.globalKey()),
 		annotationRemoveOp(s.st, s.globalKey()),
 		removeLeadershipSettingsOp(s.Tag().Id()),
+		removeStatusOp(s.st, s.globalKey()),
+		removeAutoscalePolicyOp(s.st, s.doc.Name),
+		removePlacementPolicyOp(s.st, s.doc.Name),
+	}
+	if hasAutoscaleHistory(s.st, s.doc.Name) {
+		ops = append(ops, s.st.newCleanupOp(cleanupAutoscaleHistory, s.doc.Name))
 	}
 	return ops
 }
@@ -241,29 +268,65 @@ func (s *Service) IsExposed() bool {
 	return s.doc.Exposed
 }
 
-// SetExposed marks the service as exposed.
+// ExposedSourceCIDRs returns the CIDRs of the addresses from which
+// the service's open ports may be accessed, if it is exposed. If no
+// CIDRs are returned, they may be accessed from any address.
+// See SetExposedTo.
+func (s *Service) ExposedSourceCIDRs() []string {
+	return s.doc.ExposedSourceCIDRs
+}
+
+// SetExposed marks the service as exposed to any address.
 // See ClearExposed and IsExposed.
 func (s *Service) SetExposed() error {
-	return s.setExposed(true)
+	return s.setExposed(true, nil)
+}
+
+// SetExposedTo marks the service as exposed only to addresses
+// within the specified source CIDRs. If no CIDRs are specified,
+// it is equivalent to SetExposed.
+// See ClearExposed, IsExposed and ExposedSourceCIDRs.
+func (s *Service) SetExposedTo(sourceCIDRs []string) error {
+	if err := network.ValidateSourceCIDRs(sourceCIDRs); err != nil {
+		return errors.Annotatef(err, "cannot set exposed flag for service %q", s)
+	}
+	if len(sourceCIDRs) == 0 {
+		sourceCIDRs = nil
+	}
+	return s.setExposed(true, sourceCIDRs)
 }
 
-// ClearExposed removes the exposed flag from the service.
+// ClearExposed removes the exposed flag, and any source CIDRs,
+// from the service.
 // See SetExposed and IsExposed.
 func (s *Service) ClearExposed() error {
-	return s.setExposed(false)
+	return s.setExposed(false, nil)
 }
 
-func (s *Service) setExposed(exposed bool) (err error) {
+func (s *Service) setExposed(exposed bool, sourceCIDRs []string) (err error) {
+	var update bson.D
+	if len(sourceCIDRs) > 0 {
+		update = bson.D{{"$set", bson.D{
+			{"exposed", exposed},
+			{"exposed-source-cidrs", sourceCIDRs},
+		}}}
+	} else {
+		update = bson.D{
+			{"$set", bson.D{{"exposed", exposed}}},
+			{"$unset", bson.D{{"exposed-source-cidrs", nil}}},
+		}
+	}
 	ops := []txn.Op{{
 		C:      servicesC,
 		Id:     s.doc.DocID,
 		Assert: isAliveDoc,
-		Update: bson.D{{"$set", bson.D{{"exposed", exposed}}}},
+		Update: update,
 	}}
 	if err := s.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set exposed flag for service %q to %v: %v", s, exposed, onAbort(err, errNotAlive))
 	}
 	s.doc.Exposed = exposed
+	s.doc.ExposedSourceCIDRs = sourceCIDRs
 	return nil
 }
 
@@ -573,20 +636,30 @@ func (s *Service) newUnitName() (string, error) {
 // necessary to create that unit. The principalName param must be non-empty if
 // and only if s is a subordinate service. Only one subordinate of a given
 // service will be assigned to a given principal. The asserts param can be used
-// to include additional assertions for the service document.
-func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []txn.Op, error) {
+// to include additional assertions for the service document. The attachStorage
+// param identifies detached storage instances to attach to the new unit.
+func (s *Service) addUnitOps(principalName string, attachStorage []names.StorageTag, asserts bson.D) (string, []txn.Op, error) {
 	if s.doc.Subordinate && principalName == "" {
 		return "", nil, fmt.Errorf("service is a subordinate")
 	} else if !s.doc.Subordinate && principalName != "" {
 		return "", nil, fmt.Errorf("service is not a subordinate")
 	}
+	var policyOps []txn.Op
+	if !s.doc.Subordinate {
+		maxUnitsAsserts, ops, err := s.maxUnitsAsserts()
+		if err != nil {
+			return "", nil, err
+		}
+		asserts = append(asserts, maxUnitsAsserts...)
+		policyOps = ops
+	}
 	name, err := s.newUnitName()
 	if err != nil {
 		return "", nil, err
 	}
 
 	// Create instances of the charm's declared stores.
-	storageOps, numStorageAttachments, err := s.unitStorageOps(name)
+	storageOps, numStorageAttachments, err := s.unitStorageOps(name, attachStorage)
 	if err != nil {
 		return "", nil, errors.Trace(err)
 	}
@@ -635,6 +708,7 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 		},
 	}
 	ops = append(ops, storageOps...)
+	ops = append(ops, policyOps...)
 
 	if s.doc.Subordinate {
 		ops = append(ops, txn.Op{
@@ -663,7 +737,12 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 // instances and attachments for a new unit. unitStorageOps
 // returns the number of initial storage attachments, to
 // initialise the unit's storage attachment refcount.
-func (s *Service) unitStorageOps(unitName string) (ops []txn.Op, numStorageAttachments int, err error) {
+//
+// Detached storage instances may be attached to the new unit
+// in place of creating new storage instances; these count
+// towards the number of storage instances specified in the
+// service's storage constraints.
+func (s *Service) unitStorageOps(unitName string, attachStorage []names.StorageTag) (ops []txn.Op, numStorageAttachments int, err error) {
 	cons, err := s.StorageConstraints()
 	if err != nil {
 		return nil, -1, err
@@ -674,11 +753,31 @@ func (s *Service) unitStorageOps(unitName string) (ops []txn.Op, numStorageAttac
 	}
 	meta := charm.Meta()
 	tag := names.NewUnitTag(unitName)
+	if len(attachStorage) > 0 {
+		attachOps, attached, err := attachStorageOps(s.st, attachStorage, tag, meta)
+		if err != nil {
+			return nil, -1, errors.Trace(err)
+		}
+		ops = append(ops, attachOps...)
+		numStorageAttachments += len(attachStorage)
+		remaining := make(map[string]StorageConstraints)
+		for name, c := range cons {
+			if n := attached[name]; n >= c.Count {
+				c.Count = 0
+			} else {
+				c.Count -= n
+			}
+			remaining[name] = c
+		}
+		cons = remaining
+	}
 	// TODO(wallyworld) - record constraints info in data model - size and pool name
-	ops, numStorageAttachments, err = createStorageOps(s.st, tag, meta, cons)
+	createOps, numCreated, err := createStorageOps(s.st, tag, meta, cons)
 	if err != nil {
 		return nil, -1, errors.Trace(err)
 	}
+	ops = append(ops, createOps...)
+	numStorageAttachments += numCreated
 	return ops, numStorageAttachments, nil
 }
 
@@ -696,8 +795,16 @@ func (s *Service) GetOwnerTag() string {
 
 // AddUnit adds a new principal unit to the service.
 func (s *Service) AddUnit() (unit *Unit, err error) {
+	return s.AddUnitWithStorage(nil)
+}
+
+// AddUnitWithStorage adds a new principal unit to the service, attaching
+// the specified detached storage instances to it in place of creating new
+// storage instances. The unit must be assigned to a new machine for the
+// storage to be attached.
+func (s *Service) AddUnitWithStorage(attachStorage []names.StorageTag) (unit *Unit, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot add unit to service %q", s)
-	name, ops, err := s.addUnitOps("", nil)
+	name, ops, err := s.addUnitOps("", attachStorage, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -707,6 +814,12 @@ func (s *Service) AddUnit() (unit *Unit, err error) {
 		} else if !alive {
 			return nil, fmt.Errorf("service is not alive")
 		}
+		if err := s.Refresh(); err != nil {
+			return nil, err
+		}
+		if _, _, err := s.maxUnitsAsserts(); err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("inconsistent state")
 	} else if err != nil {
 		return nil, err
@@ -948,6 +1061,70 @@ func (s *Service) StorageConstraints() (map[string]StorageConstraints, error) {
 	return readStorageConstraints(s.st, s.globalKey())
 }
 
+// StoragePoolDefaults records the storage pools from which a service's
+// storage is provisioned when its storage constraints do not specify a
+// pool. Empty fields defer to the environment's defaults.
+type StoragePoolDefaults struct {
+	Block      string `bson:"block,omitempty"`
+	Filesystem string `bson:"filesystem,omitempty"`
+}
+
+// StorageDefaults returns the service's storage pool defaults,
+// or nil if none have been set.
+func (s *Service) StorageDefaults() *StoragePoolDefaults {
+	return s.doc.StorageDefaults
+}
+
+// SetStorageDefaults updates the service's storage pool defaults.
+// Each specified pool must exist and support the corresponding kind
+// of storage. Setting empty defaults clears the service's defaults,
+// so that the environment's defaults apply.
+func (s *Service) SetStorageDefaults(defaults StoragePoolDefaults) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set storage defaults for service %q", s.doc.Name)
+	if defaults.Block != "" {
+		if err := validateStoragePool(s.st, defaults.Block, storage.StorageKindBlock, nil); err != nil {
+			return errors.Annotate(err, "validating block pool")
+		}
+	}
+	if defaults.Filesystem != "" {
+		if err := validateStoragePool(s.st, defaults.Filesystem, storage.StorageKindFilesystem, nil); err != nil {
+			return errors.Annotate(err, "validating filesystem pool")
+		}
+	}
+	var update bson.D
+	var newDefaults *StoragePoolDefaults
+	if defaults == (StoragePoolDefaults{}) {
+		update = bson.D{{"$unset", bson.D{{"storage-defaults", nil}}}}
+	} else {
+		newDefaults = &defaults
+		update = bson.D{{"$set", bson.D{{"storage-defaults", newDefaults}}}}
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			alive, err := isAlive(s.st, servicesC, s.doc.DocID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			} else if !alive {
+				return nil, errNotAlive
+			}
+		}
+		return []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: isAliveDoc,
+			Update: update,
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		if err == errNotAlive {
+			return errors.New("service " + err.Error())
+		}
+		return err
+	}
+	s.doc.StorageDefaults = newDefaults
+	return nil
+}
+
 // settingsIncRefOp returns an operation that increments the ref count
 // of the service settings identified by serviceName and curl. If
 // canCreate is false, a missing document will be treated as an error;
@@ -1056,6 +1233,50 @@ func (s *Service) Status() (StatusInfo, error) {
 	}, nil
 }
 
+// SetStatus sets the status of the service. Only unit leaders should
+// set the status of their service; once set, the recorded status is
+// used in preference to one derived from the units.
+func (s *Service) SetStatus(status Status, info string, data map[string]interface{}) error {
+	oldDoc, err := getStatus(s.st, s.globalKey())
+	exists := true
+	if IsStatusNotFound(err) {
+		logger.Debugf("there is no state for %q yet", s.globalKey())
+		exists = false
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+
+	doc, err := newServiceStatusDoc(s.doc.Name, status, info, data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	statusOp := updateStatusOp(s.st, s.globalKey(), doc.statusDoc)
+	if !exists {
+		statusOp = createStatusOp(s.st, s.globalKey(), doc.statusDoc)
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+	}, statusOp}
+	if err := s.st.runTransaction(ops); err != nil {
+		return errors.Errorf("cannot set status of service %q: %v", s, onAbort(err, errNotAlive))
+	}
+
+	if oldDoc.Status != "" {
+		if err := updateStatusHistory(oldDoc, s.globalKey(), s.st); err != nil {
+			logger.Errorf("could not record status history before change to %q: %v", status, err)
+		}
+	}
+	return nil
+}
+
+// StatusHistory returns a slice of at most <size> StatusInfo items
+// representing past statuses set for the service.
+func (s *Service) StatusHistory(size int) ([]StatusInfo, error) {
+	return statusHistory(size, s.globalKey(), s.st)
+}
+
 func (s *Service) deriveStatus() (StatusInfo, error) {
 	units, err := s.AllUnits()
 	if err != nil {
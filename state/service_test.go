@@ -1017,6 +1017,45 @@ func (s *ServiceSuite) TestServiceExposed(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, notAliveErr)
 }
 
+func (s *ServiceSuite) TestServiceExposedTo(c *gc.C) {
+	c.Assert(s.mysql.ExposedSourceCIDRs(), gc.HasLen, 0)
+
+	cidrs := []string{"10.0.0.0/8", "203.0.113.4/32"}
+	err := s.mysql.SetExposedTo(cidrs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	c.Assert(s.mysql.ExposedSourceCIDRs(), jc.DeepEquals, cidrs)
+
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	c.Assert(s.mysql.ExposedSourceCIDRs(), jc.DeepEquals, cidrs)
+
+	// Exposing the service to any address clears the CIDRs.
+	err = s.mysql.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	c.Assert(s.mysql.ExposedSourceCIDRs(), gc.HasLen, 0)
+
+	// As does unexposing it.
+	err = s.mysql.SetExposedTo(cidrs)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+	c.Assert(s.mysql.ExposedSourceCIDRs(), gc.HasLen, 0)
+}
+
+func (s *ServiceSuite) TestServiceExposedToInvalidCIDR(c *gc.C) {
+	err := s.mysql.SetExposedTo([]string{"10.0.0.0/8", "10.0.0.1"})
+	c.Assert(err, gc.ErrorMatches, `cannot set exposed flag for service "mysql": invalid source CIDR "10.0.0.1"`)
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+}
+
 func (s *ServiceSuite) TestAddUnit(c *gc.C) {
 	// Check that principal units can be added on their own.
 	unitZero, err := s.mysql.AddUnit()
@@ -1794,3 +1833,26 @@ func (s *ServiceSuite) TestStatus(c *gc.C) {
 		s.testStatus(c, t.status1, t.status2, t.expected)
 	}
 }
+
+func (s *ServiceSuite) TestSetStatus(c *gc.C) {
+	err := s.mysql.SetStatus(state.StatusMaintenance, "installing", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetStatus(state.StatusActive, "ready", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	statusInfo, err := s.mysql.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Equals, state.StatusActive)
+	c.Assert(statusInfo.Message, gc.Equals, "ready")
+
+	history, err := s.mysql.StatusHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Status, gc.Equals, state.StatusMaintenance)
+	c.Assert(history[0].Message, gc.Equals, "installing")
+}
+
+func (s *ServiceSuite) TestSetStatusInvalid(c *gc.C) {
+	err := s.mysql.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set invalid status "started"`)
+}
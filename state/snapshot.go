@@ -0,0 +1,371 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// Snapshot describes a point-in-time copy of a volume, from which new
+// volumes may be created.
+type Snapshot interface {
+	// Id returns the unique ID of the snapshot. Snapshots of
+	// machine-scoped volumes are scoped to the same machine,
+	// and have IDs of the form "<machine-id>/<n>".
+	Id() string
+
+	// Volume returns the tag of the volume that the snapshot
+	// was taken of.
+	Volume() names.VolumeTag
+
+	// Life returns the life of the snapshot.
+	Life() Life
+
+	// Pool returns the name of the storage pool that the snapshotted
+	// volume was provisioned from. Volumes restored from the snapshot
+	// must be provisioned from the same pool.
+	Pool() string
+
+	// Size returns the size of the snapshotted volume, in MiB.
+	// Volumes restored from the snapshot must be at least this
+	// size.
+	Size() uint64
+
+	// Info returns the snapshot's SnapshotInfo, or a NotProvisioned
+	// error if the snapshot has not yet been taken.
+	Info() (SnapshotInfo, error)
+
+	// Failure returns the reason that the snapshot most recently
+	// failed to be taken or destroyed, or the empty string if it
+	// did not.
+	Failure() string
+}
+
+type snapshot struct {
+	doc snapshotDoc
+}
+
+// snapshotDoc records information about a volume snapshot.
+type snapshotDoc struct {
+	DocID   string        `bson:"_id"`
+	Name    string        `bson:"name"`
+	EnvUUID string        `bson:"env-uuid"`
+	Life    Life          `bson:"life"`
+	Volume  string        `bson:"volumeid"`
+	Pool    string        `bson:"pool"`
+	Size    uint64        `bson:"size"`
+	Info    *SnapshotInfo `bson:"info,omitempty"`
+	Failure string        `bson:"failure,omitempty"`
+}
+
+// SnapshotInfo describes information about a snapshot that has
+// been taken.
+type SnapshotInfo struct {
+	SnapshotId string `bson:"snapshotid"`
+}
+
+// Id is required to implement Snapshot.
+func (s *snapshot) Id() string {
+	return s.doc.Name
+}
+
+// Volume is required to implement Snapshot.
+func (s *snapshot) Volume() names.VolumeTag {
+	return names.NewVolumeTag(s.doc.Volume)
+}
+
+// Life is required to implement Snapshot.
+func (s *snapshot) Life() Life {
+	return s.doc.Life
+}
+
+// Pool is required to implement Snapshot.
+func (s *snapshot) Pool() string {
+	return s.doc.Pool
+}
+
+// Size is required to implement Snapshot.
+func (s *snapshot) Size() uint64 {
+	return s.doc.Size
+}
+
+// Info is required to implement Snapshot.
+func (s *snapshot) Info() (SnapshotInfo, error) {
+	if s.doc.Info == nil {
+		return SnapshotInfo{}, errors.NotProvisionedf("snapshot %q", s.doc.Name)
+	}
+	return *s.doc.Info, nil
+}
+
+// Failure is required to implement Snapshot.
+func (s *snapshot) Failure() string {
+	return s.doc.Failure
+}
+
+// Snapshot returns the Snapshot with the specified ID.
+func (st *State) Snapshot(id string) (Snapshot, error) {
+	coll, cleanup := st.getCollection(snapshotsC)
+	defer cleanup()
+
+	var s snapshot
+	err := coll.FindId(id).One(&s.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("snapshot %q", id)
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get snapshot")
+	}
+	return &s, nil
+}
+
+// AllSnapshots returns all Snapshots in the environment.
+func (st *State) AllSnapshots() ([]Snapshot, error) {
+	coll, cleanup := st.getCollection(snapshotsC)
+	defer cleanup()
+
+	var docs []snapshotDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get snapshots")
+	}
+	snapshots := make([]Snapshot, len(docs))
+	for i, doc := range docs {
+		snapshots[i] = &snapshot{doc}
+	}
+	return snapshots, nil
+}
+
+// newSnapshotName returns a unique snapshot name, scoped to the same
+// machine as the volume it is taken of, if any.
+func newSnapshotName(st *State, volume names.VolumeTag) (string, error) {
+	seq, err := st.sequence("snapshot")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	id := fmt.Sprint(seq)
+	if machineTag, ok := names.VolumeMachine(volume); ok {
+		id = machineTag.Id() + "/" + id
+	}
+	return id, nil
+}
+
+// AddSnapshot requests that a snapshot be taken of the specified volume,
+// and returns the new Snapshot. The volume must be alive and provisioned.
+// The snapshot is taken by the storage provisioner responsible for the
+// volume.
+func (st *State) AddSnapshot(volumeTag names.VolumeTag) (_ Snapshot, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot snapshot volume %q", volumeTag.Id())
+	var doc snapshotDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		v, err := st.Volume(volumeTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.Life() != Alive {
+			return nil, errors.New("volume is not alive")
+		}
+		info, err := v.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		name, err := newSnapshotName(st, volumeTag)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot generate snapshot name")
+		}
+		doc = snapshotDoc{
+			Name:   name,
+			Volume: volumeTag.Id(),
+			Pool:   info.Pool,
+			Size:   info.Size,
+		}
+		return []txn.Op{{
+			C:      volumesC,
+			Id:     volumeTag.Id(),
+			Assert: append(isAliveDoc, bson.DocElem{"info", bson.D{{"$exists", true}}}),
+		}, {
+			C:      snapshotsC,
+			Id:     name,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, err
+	}
+	return &snapshot{doc}, nil
+}
+
+// SetSnapshotInfo records the SnapshotInfo for the specified snapshot,
+// once it has been taken.
+func (st *State) SetSnapshotInfo(id string, info SnapshotInfo) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set info for snapshot %q", id)
+	if info.SnapshotId == "" {
+		return errors.New("snapshot ID not set")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := st.Snapshot(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if oldInfo, err := s.Info(); err == nil {
+			if oldInfo == info {
+				return nil, jujutxn.ErrNoOperations
+			}
+			return nil, errors.Errorf(
+				"cannot change snapshot ID from %q to %q",
+				oldInfo.SnapshotId, info.SnapshotId,
+			)
+		}
+		return []txn.Op{{
+			C:      snapshotsC,
+			Id:     id,
+			Assert: append(notDeadDoc, bson.DocElem{"info", bson.D{{"$exists", false}}}),
+			Update: bson.D{{"$set", bson.D{{"info", &info}}}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// SetSnapshotFailure records the reason that the snapshot with the
+// specified ID could not be taken or destroyed. The storage provisioner
+// does not retry taking a snapshot that has failed; the snapshot should
+// be destroyed, and another taken.
+func (st *State) SetSnapshotFailure(id, reason string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set failure for snapshot %q", id)
+	if reason == "" {
+		return errors.New("failure reason not set")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := st.Snapshot(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.Failure() == reason {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      snapshotsC,
+			Id:     id,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"failure", reason}}}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// DestroySnapshot ensures that the snapshot with the specified ID is
+// no longer alive. The snapshot is subsequently destroyed by the storage
+// provisioner responsible for it, and then removed from state.
+//
+// Snapshots that have not yet been taken are not removed immediately,
+// as the storage provisioner may be in the process of taking them; the
+// provisioner removes them once it observes that they are Dying.
+func (st *State) DestroySnapshot(id string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot destroy snapshot %q", id)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := st.Snapshot(id)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.Life() != Alive {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      snapshotsC,
+			Id:     id,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// RemoveSnapshot removes the snapshot with the specified ID from state.
+// The snapshot must not be alive; the storage provisioner calls this once
+// it has destroyed the snapshot in the storage provider.
+func (st *State) RemoveSnapshot(id string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove snapshot %q", id)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := st.Snapshot(id)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.Life() == Alive {
+			return nil, errors.New("snapshot is alive")
+		}
+		return []txn.Op{{
+			C:      snapshotsC,
+			Id:     id,
+			Assert: bson.D{{"life", bson.D{{"$ne", Alive}}}},
+			Remove: true,
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// validateVolumeSnapshot checks that a volume with the specified
+// parameters may be created from the snapshot with the specified ID,
+// on the machine with the specified ID ("" for environment-scoped
+// volumes).
+func validateVolumeSnapshot(st *State, params VolumeParams, machineId string) error {
+	s, err := st.Snapshot(params.Snapshot)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if s.Life() != Alive {
+		return errors.Errorf("snapshot %q is not alive", s.Id())
+	}
+	if _, err := s.Info(); err != nil {
+		return errors.Annotatef(err, "snapshot %q", s.Id())
+	}
+	if params.Pool != s.Pool() {
+		return errors.Errorf(
+			"snapshot %q was taken from pool %q, cannot restore to pool %q",
+			s.Id(), s.Pool(), params.Pool,
+		)
+	}
+	if params.Size < s.Size() {
+		return errors.Errorf(
+			"snapshot %q requires a volume of at least %dMiB, %dMiB specified",
+			s.Id(), s.Size(), params.Size,
+		)
+	}
+	if snapshotMachine, ok := names.VolumeMachine(s.Volume()); ok {
+		if snapshotMachine.Id() != machineId {
+			return errors.Errorf(
+				"snapshot %q can only be restored on machine %q",
+				s.Id(), snapshotMachine.Id(),
+			)
+		}
+	}
+	return nil
+}
+
+// snapshotStorageConstraints fills in the pool and size of the
+// storage constraints from the snapshot they specify, if any.
+func snapshotStorageConstraints(st *State, cons StorageConstraints) (StorageConstraints, error) {
+	if cons.Snapshot == "" {
+		return cons, nil
+	}
+	s, err := st.Snapshot(cons.Snapshot)
+	if err != nil {
+		return cons, errors.Trace(err)
+	}
+	if cons.Pool == "" {
+		cons.Pool = s.Pool()
+	}
+	if cons.Size == 0 {
+		cons.Size = s.Size()
+	}
+	return cons, nil
+}
@@ -0,0 +1,227 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type SnapshotStateSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&SnapshotStateSuite{})
+
+// setupProvisionedVolume adds a unit with a single volume from the
+// specified pool, assigns it to a new machine, and marks the volume
+// provisioned.
+func (s *SnapshotStateSuite) setupProvisionedVolume(c *gc.C, pool string) (*state.Unit, names.VolumeTag) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", pool)
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{
+		VolumeId: "vol-123", Size: 1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return u, volume.VolumeTag()
+}
+
+func (s *SnapshotStateSuite) TestAddSnapshotVolumeNotProvisioned(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddSnapshot(volume.VolumeTag())
+	c.Assert(err, gc.ErrorMatches, `cannot snapshot volume "0/0": volume "0/0" not provisioned`)
+}
+
+func (s *SnapshotStateSuite) TestAddSnapshot(c *gc.C) {
+	_, volumeTag := s.setupProvisionedVolume(c, "loop-pool")
+
+	snapshot, err := s.State.AddSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Id(), gc.Equals, "0/0")
+	c.Assert(snapshot.Volume(), gc.Equals, volumeTag)
+	c.Assert(snapshot.Life(), gc.Equals, state.Alive)
+	c.Assert(snapshot.Pool(), gc.Equals, "loop-pool")
+	c.Assert(snapshot.Size(), gc.Equals, uint64(1024))
+	_, err = snapshot.Info()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	snapshots, err := s.State.AllSnapshots()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshots, gc.HasLen, 1)
+	c.Assert(snapshots[0].Id(), gc.Equals, "0/0")
+}
+
+func (s *SnapshotStateSuite) TestSetSnapshotInfo(c *gc.C) {
+	_, volumeTag := s.setupProvisionedVolume(c, "loop-pool")
+	snapshot, err := s.State.AddSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetSnapshotInfo(snapshot.Id(), state.SnapshotInfo{SnapshotId: "snap-123"})
+	c.Assert(err, jc.ErrorIsNil)
+	snapshot, err = s.State.Snapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := snapshot.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, state.SnapshotInfo{SnapshotId: "snap-123"})
+
+	// Setting the same info again is a no-op; changing it is an error.
+	err = s.State.SetSnapshotInfo(snapshot.Id(), state.SnapshotInfo{SnapshotId: "snap-123"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetSnapshotInfo(snapshot.Id(), state.SnapshotInfo{SnapshotId: "snap-456"})
+	c.Assert(err, gc.ErrorMatches, `cannot set info for snapshot "0/0": cannot change snapshot ID from "snap-123" to "snap-456"`)
+}
+
+func (s *SnapshotStateSuite) TestSetSnapshotFailure(c *gc.C) {
+	_, volumeTag := s.setupProvisionedVolume(c, "loop-pool")
+	snapshot, err := s.State.AddSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Failure(), gc.Equals, "")
+
+	err = s.State.SetSnapshotFailure(snapshot.Id(), "")
+	c.Assert(err, gc.ErrorMatches, `cannot set failure for snapshot "0/0": failure reason not set`)
+	err = s.State.SetSnapshotFailure(snapshot.Id(), "no can do")
+	c.Assert(err, jc.ErrorIsNil)
+	snapshot, err = s.State.Snapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Failure(), gc.Equals, "no can do")
+
+	// A failed snapshot may still be destroyed and removed.
+	err = s.State.DestroySnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetSnapshotFailure(snapshot.Id(), "no can do")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SnapshotStateSuite) TestDestroyRemoveSnapshot(c *gc.C) {
+	_, volumeTag := s.setupProvisionedVolume(c, "loop-pool")
+	snapshot, err := s.State.AddSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveSnapshot(snapshot.Id())
+	c.Assert(err, gc.ErrorMatches, `cannot remove snapshot "0/0": snapshot is alive`)
+
+	err = s.State.DestroySnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	snapshot, err = s.State.Snapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Life(), gc.Equals, state.Dying)
+
+	err = s.State.RemoveSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Snapshot(snapshot.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Destroying or removing a removed snapshot is a no-op.
+	err = s.State.DestroySnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveSnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SnapshotStateSuite) TestWatchMachineSnapshots(c *gc.C) {
+	_, volumeTag := s.setupProvisionedVolume(c, "loop-pool")
+
+	w := s.State.WatchMachineSnapshots(names.NewMachineTag("0"))
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	snapshot, err := s.State.AddSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+
+	err = s.State.DestroySnapshot(snapshot.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("0/0")
+	wc.AssertNoChange()
+}
+
+func (s *SnapshotStateSuite) addSnapshotTaken(c *gc.C, volumeTag names.VolumeTag) state.Snapshot {
+	snapshot, err := s.State.AddSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetSnapshotInfo(snapshot.Id(), state.SnapshotInfo{SnapshotId: "snap-123"})
+	c.Assert(err, jc.ErrorIsNil)
+	return snapshot
+}
+
+func (s *SnapshotStateSuite) TestAddServiceFromSnapshot(c *gc.C) {
+	_, volumeTag := s.setupProvisionedVolume(c, "environscoped-block")
+	snapshot := s.addSnapshotTaken(c, volumeTag)
+	c.Assert(snapshot.Id(), gc.Equals, "0")
+
+	// Pool and size are taken from the snapshot.
+	ch := s.AddTestingCharm(c, "storage-block")
+	service := s.AddTestingServiceWithStorage(c, "restored", ch, map[string]state.StorageConstraints{
+		"data": {Count: 1, Snapshot: snapshot.Id()},
+	})
+	cons, err := service.StorageConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons["data"], jc.DeepEquals, state.StorageConstraints{
+		Pool: "environscoped-block", Size: 1024, Count: 1, Snapshot: "0",
+	})
+
+	restored, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(restored, state.AssignNew)
+	c.Assert(err, jc.ErrorIsNil)
+
+	volume, err := s.State.StorageInstanceVolume(names.NewStorageTag("data/1"))
+	c.Assert(err, jc.ErrorIsNil)
+	params, ok := volume.Params()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(params.Snapshot, gc.Equals, "0")
+	c.Assert(params.Pool, gc.Equals, "environscoped-block")
+	c.Assert(params.Size, gc.Equals, uint64(1024))
+}
+
+func (s *SnapshotStateSuite) TestAddServiceFromMachineScopedSnapshot(c *gc.C) {
+	_, volumeTag := s.setupProvisionedVolume(c, "loop-pool")
+	snapshot := s.addSnapshotTaken(c, volumeTag)
+
+	// The snapshot is of a machine-scoped volume,
+	// so it can only be restored on the same machine.
+	ch := s.AddTestingCharm(c, "storage-block")
+	service := s.AddTestingServiceWithStorage(c, "restored", ch, map[string]state.StorageConstraints{
+		"data": {Count: 1, Snapshot: snapshot.Id()},
+	})
+	other, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(other, state.AssignNew)
+	c.Assert(err, gc.ErrorMatches, `.*snapshot "0/0" can only be restored on machine "0"`)
+}
+
+func (s *SnapshotStateSuite) TestAddServiceFromSnapshotValidation(c *gc.C) {
+	_, volumeTag := s.setupProvisionedVolume(c, "loop-pool")
+	snapshot, err := s.State.AddSnapshot(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddTestingCharm(c, "storage-block")
+	addService := func(cons state.StorageConstraints) error {
+		_, err := s.State.AddService("restored", "user-test-admin@local", ch, nil, map[string]state.StorageConstraints{
+			"data": cons,
+		})
+		return err
+	}
+	err = addService(state.StorageConstraints{Count: 1, Snapshot: "99"})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "restored": storage "data": snapshot "99" not found`)
+	err = addService(state.StorageConstraints{Count: 1, Size: 512, Snapshot: snapshot.Id()})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "restored": charm "storage-block" store "data": snapshot "0/0" requires at least 1.0GB, 512MB specified`)
+}
@@ -88,6 +88,7 @@ const (
 	volumeAttachmentsC     = "volumeattachments"
 	filesystemsC           = "filesystems"
 	filesystemAttachmentsC = "filesystemAttachments"
+	snapshotsC             = "snapshots"
 
 	// leaseC is used to store lease tokens
 	leaseC = "lease"
@@ -114,6 +115,35 @@ const (
 	// blocksC is used to identify collection of environment blocks.
 	blocksC = "blocks"
 
+	// webhooksC holds the webhooks notified of status transitions,
+	// and webhookDeliveriesC the log of notifications sent to them.
+	webhooksC          = "webhooks"
+	webhookDeliveriesC = "webhookdeliveries"
+
+	// firewallStatusesC holds the most recent comparison of the
+	// ingress rules expected and actually open for each machine,
+	// or for the environment in global firewall mode.
+	firewallStatusesC = "firewallstatuses"
+
+	// serviceOffersC holds the service endpoints offered to other
+	// environments, and remoteServicesC the services consumed from
+	// other environments through those offers.
+	serviceOffersC  = "serviceoffers"
+	remoteServicesC = "remoteservices"
+
+	// autoscalePoliciesC holds the policies by which services are
+	// scaled according to their metrics, and autoscaleHistoryC the
+	// scaling decisions made under them.
+	autoscalePoliciesC = "autoscalepolicies"
+	autoscaleHistoryC  = "autoscalehistory"
+
+	// placementPoliciesC holds the policies restricting the number
+	// of units of services and the machines they may be placed on.
+	placementPoliciesC = "placementpolicies"
+
+	// selfHealEventsC records the replacement of failed machines.
+	selfHealEventsC = "selfhealevents"
+
 	// The following mongo collections are used as unique key restraints. The
 	// _id field of each collection is a concatenation of multiple fields
 	// that form a compound index.
@@ -140,6 +170,8 @@ type State struct {
 	allManager *storeManager
 	environTag names.EnvironTag
 	serverTag  names.EnvironTag
+	// dryRun is true if the State was returned by DryRun.
+	dryRun bool
 }
 
 // StateServingInfo holds information needed by a state server.
@@ -1252,6 +1284,11 @@ func (st *State) AddService(
 	} else if exists {
 		return nil, errors.Errorf("service already exists")
 	}
+	if remote, err := isRemoteService(st, name); err != nil {
+		return nil, errors.Trace(err)
+	} else if remote {
+		return nil, errors.Errorf("remote service already exists")
+	}
 	env, err := st.Environment()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -1301,6 +1338,11 @@ func (st *State) AddService(
 				RefCount: 1,
 				EnvUUID:  st.EnvironUUID()},
 		},
+		{
+			C:      remoteServicesC,
+			Id:     serviceID,
+			Assert: txn.DocMissing,
+		},
 		{
 			C:      servicesC,
 			Id:     serviceID,
@@ -1326,9 +1368,11 @@ func (st *State) AddService(
 	} else if err != nil {
 		return nil, errors.Trace(err)
 	}
-	// Refresh to pick the txn-revno.
-	if err = svc.Refresh(); err != nil {
-		return nil, errors.Trace(err)
+	// Refresh to pick the txn-revno. Nothing was written in a dry run.
+	if !st.dryRun {
+		if err = svc.Refresh(); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 	return svc, nil
 }
@@ -1730,6 +1774,12 @@ func containerScopeOk(st *State, ep1, ep2 Endpoint) bool {
 	return subordinateCount >= 1
 }
 
+// endpointer is implemented by both services and remote services.
+type endpointer interface {
+	Endpoint(relationName string) (Endpoint, error)
+	Endpoints() ([]Endpoint, error)
+}
+
 // endpoints returns all endpoints that could be intended by the
 // supplied endpoint name, and which cause the filter param to
 // return true.
@@ -1743,10 +1793,19 @@ func (st *State) endpoints(name string, filter func(ep Endpoint) bool) ([]Endpoi
 	} else {
 		return nil, errors.Errorf("invalid endpoint %q", name)
 	}
-	svc, err := st.Service(svcName)
-	if err != nil {
+	var svc endpointer
+	if local, err := st.Service(svcName); err == nil {
+		svc = local
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	} else if remote, remoteErr := st.RemoteService(svcName); remoteErr == nil {
+		// The endpoints are those of a service consumed from
+		// another environment.
+		svc = remote
+	} else {
 		return nil, errors.Trace(err)
 	}
+	var err error
 	eps := []Endpoint{}
 	if relName != "" {
 		ep, err := svc.Endpoint(relName)
@@ -1810,10 +1869,19 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 		var ops []txn.Op
 		var subordinateCount int
 		series := map[string]bool{}
+		var remoteCount int
 		for _, ep := range eps {
 			svc, err := st.Service(ep.ServiceName)
 			if errors.IsNotFound(err) {
-				return nil, errors.Errorf("service %q does not exist", ep.ServiceName)
+				remoteOp, err := st.addRemoteRelationOp(ep)
+				if errors.IsNotFound(err) {
+					return nil, errors.Errorf("service %q does not exist", ep.ServiceName)
+				} else if err != nil {
+					return nil, errors.Trace(err)
+				}
+				remoteCount++
+				ops = append(ops, remoteOp)
+				continue
 			} else if err != nil {
 				return nil, errors.Trace(err)
 			} else if svc.doc.Life != Alive {
@@ -1843,6 +1911,11 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 		if eps[0].Scope == charm.ScopeContainer && subordinateCount < 1 {
 			return nil, errors.Errorf("container scoped relation requires at least one subordinate service")
 		}
+		if remoteCount > 1 {
+			return nil, errors.Errorf("cannot relate two remote services")
+		} else if remoteCount > 0 && eps[0].Scope == charm.ScopeContainer {
+			return nil, errors.Errorf("remote service relations cannot be container scoped")
+		}
 
 		// Create a new unique id if that has not already been done, and add
 		// an operation to create the relation document.
@@ -1875,6 +1948,30 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 	return nil, errors.Trace(err)
 }
 
+// addRemoteRelationOp returns the operation needed to add a relation
+// to the remote service defining the supplied endpoint. It returns an
+// error satisfying errors.IsNotFound if there is no such remote service.
+func (st *State) addRemoteRelationOp(ep Endpoint) (txn.Op, error) {
+	svc, err := st.RemoteService(ep.ServiceName)
+	if err != nil {
+		return txn.Op{}, errors.Trace(err)
+	} else if svc.doc.Life != Alive {
+		return txn.Op{}, errors.Errorf("remote service %q is not alive", ep.ServiceName)
+	}
+	remoteEp, err := svc.Endpoint(ep.Name)
+	if err != nil {
+		return txn.Op{}, errors.Trace(err)
+	} else if remoteEp.Interface != ep.Interface || remoteEp.Role != ep.Role {
+		return txn.Op{}, errors.Errorf("%q does not implement %q", ep.ServiceName, ep)
+	}
+	return txn.Op{
+		C:      remoteServicesC,
+		Id:     st.docID(ep.ServiceName),
+		Assert: isAliveDoc,
+		Update: bson.D{{"$inc", bson.D{{"relationcount", 1}}}},
+	}, nil
+}
+
 // EndpointsRelation returns the existing relation with the given endpoints.
 func (st *State) EndpointsRelation(endpoints ...Endpoint) (*Relation, error) {
 	return st.KeyRelation(relationKey(endpoints))
@@ -15,8 +15,10 @@ import (
 var (
 	_ StatusSetter = (*Machine)(nil)
 	_ StatusSetter = (*Unit)(nil)
+	_ StatusSetter = (*Service)(nil)
 	_ StatusGetter = (*Machine)(nil)
 	_ StatusGetter = (*Unit)(nil)
+	_ StatusGetter = (*Service)(nil)
 )
 
 // Status represents the status of an entity.
@@ -572,7 +574,8 @@ func removeStatusOp(st *State, globalKey string) txn.Op {
 }
 
 // PruneStatusHistory removes status history entries until
-// only the maxLogsPerEntity newest records per unit remain.
+// only the maxLogsPerEntity newest records per entity (unit, unit
+// agent, machine, machine instance or service) remain.
 func PruneStatusHistory(st *State, maxLogsPerEntity int) error {
 	historyColl, closer := st.getCollection(statusesHistoryC)
 	defer closer()
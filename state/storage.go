@@ -40,8 +40,10 @@ type StorageInstance interface {
 	Kind() StorageKind
 
 	// Owner returns the tag of the service or unit that owns this storage
-	// instance.
-	Owner() names.Tag
+	// instance, and a boolean indicating whether or not there is an owner.
+	// Storage instances that have been detached from their owning unit
+	// have no owner until they are attached to another unit.
+	Owner() (names.Tag, bool)
 
 	// StorageName returns the name of the storage, as defined in the charm
 	// storage metadata. This does not uniquely identify storage instances,
@@ -95,14 +97,17 @@ func (s *storageInstance) Kind() StorageKind {
 	return s.doc.Kind
 }
 
-func (s *storageInstance) Owner() names.Tag {
+func (s *storageInstance) Owner() (names.Tag, bool) {
+	if s.doc.Owner == "" {
+		return nil, false
+	}
 	tag, err := names.ParseTag(s.doc.Owner)
 	if err != nil {
 		// This should be impossible; we do not expose
 		// a means of modifying the owner tag.
 		panic(err)
 	}
-	return tag
+	return tag, true
 }
 
 func (s *storageInstance) StorageName() string {
@@ -124,6 +129,11 @@ type storageInstanceDoc struct {
 	Owner           string      `bson:"owner"`
 	StorageName     string      `bson:"storagename"`
 	AttachmentCount int         `bson:"attachmentcount"`
+
+	// Releasing records that the storage instance has been detached
+	// from its owning unit, but remains attached to it until the unit
+	// is removed, at which point it is released rather than removed.
+	Releasing bool `bson:"releasing,omitempty"`
 }
 
 type storageAttachment struct {
@@ -538,6 +548,16 @@ func (st *State) RemoveStorageAttachment(storage names.StorageTag, unit names.Un
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		if _, ok := inst.Owner(); !ok || inst.doc.Releasing {
+			// The storage instance has been detached from the
+			// unit, so we must detach its volume or filesystem
+			// from the unit's machine.
+			detachOps, err := detachStorageMachineOps(st, inst, unit)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, detachOps...)
+		}
 		return ops, nil
 	}
 	return st.run(buildTxn)
@@ -572,10 +592,19 @@ func removeStorageAttachmentOps(s *storageAttachment, si *storageInstance) ([]tx
 		// This may be the last reference, but the storage instance is
 		// still alive. The storage instance will be removed when its
 		// Destroy method is called, if it has no attachments.
-		decrefOp.Assert = bson.D{
+		//
+		// The storage instance must not be detached concurrently,
+		// or its volume or filesystem would not be detached from
+		// the unit's machine.
+		releasing := bson.D{{"releasing", bson.D{{"$ne", true}}}}
+		if si.doc.Releasing {
+			releasing = bson.D{{"releasing", true}}
+		}
+		decrefOp.Assert = append(bson.D{
 			{"life", Alive},
 			{"attachmentcount", bson.D{{"$gt", 0}}},
-		}
+			{"owner", si.doc.Owner},
+		}, releasing...)
 	} else {
 		// If it's not the last reference when we checked, we want to
 		// allow for concurrent attachment removals but want to ensure
@@ -590,22 +619,371 @@ func removeStorageAttachmentOps(s *storageAttachment, si *storageInstance) ([]tx
 	return ops, nil
 }
 
+// DetachStorage detaches the storage instance with the specified tag
+// from the unit that owns it, without destroying the storage instance.
+// The unit relinquishes ownership of the storage instance, which will
+// then outlive the unit, and may later be attached to a new unit of a
+// service with Service.AddUnitWithStorage.
+//
+// The storage attachment is destroyed, causing the unit to run the
+// storage-detaching hook. Once the storage attachment has been removed,
+// the storage instance's volume or filesystem is detached from the
+// unit's machine.
+//
+// If the unit is alive and would be left with fewer instances of the
+// storage than its charm requires, the storage instance is instead
+// marked as releasing: it remains attached to the unit, and is
+// released rather than removed when the unit is removed.
+func (st *State) DetachStorage(tag names.StorageTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot detach storage %q", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		s, err := st.storageInstance(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.doc.Life != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+		owner, ok := s.Owner()
+		if !ok || s.doc.Releasing {
+			// The storage has already been detached.
+			return nil, jujutxn.ErrNoOperations
+		}
+		unitTag, ok := owner.(names.UnitTag)
+		if !ok {
+			return nil, errors.Errorf("storage is owned by %s, not a unit", names.ReadableString(owner))
+		}
+		countMinOps, err := detachStorageCountMinOps(st, s, unitTag)
+		if err == errStorageRequired {
+			return []txn.Op{{
+				C:  storageInstancesC,
+				Id: s.doc.Id,
+				Assert: append(bson.D{
+					{"owner", s.doc.Owner},
+					{"releasing", bson.D{{"$ne", true}}},
+				}, isAliveDoc...),
+				Update: bson.D{{"$set", bson.D{{"releasing", true}}}},
+			}}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:  storageInstancesC,
+			Id: s.doc.Id,
+			Assert: append(bson.D{
+				{"owner", s.doc.Owner},
+			}, isAliveDoc...),
+			Update: bson.D{{"$set", bson.D{{"owner", ""}}}},
+		}}
+		ops = append(ops, countMinOps...)
+		att, err := st.storageAttachment(tag, unitTag)
+		if errors.IsNotFound(err) {
+			// The storage attachment has already been removed,
+			// so we must detach the volume or filesystem from
+			// the unit's machine here.
+			detachOps, err := detachStorageMachineOps(st, s, unitTag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, detachOps...)
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		} else if att.doc.Life == Alive {
+			ops = append(ops, destroyStorageAttachmentOps(att)...)
+		}
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
+
+// errStorageRequired is returned by detachStorageCountMinOps if the
+// storage instance is required by the live unit that owns it.
+var errStorageRequired = errors.New("storage required by unit")
+
+// detachStorageCountMinOps returns txn.Ops asserting that, once the
+// specified storage instance is detached, the unit that owns it still
+// has the minimum number of instances of that storage required by its
+// charm, or errStorageRequired if it would not. Storage may be detached
+// from a unit that is not alive without restriction.
+func detachStorageCountMinOps(st *State, s *storageInstance, unitTag names.UnitTag) ([]txn.Op, error) {
+	u, err := st.Unit(unitTag.Id())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if u.Life() != Alive {
+		return nil, nil
+	}
+	service, err := u.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	curl, _ := service.CharmURL()
+	ch, err := st.Charm(curl)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting charm")
+	}
+	charmStorage, ok := ch.Meta().Storage[s.StorageName()]
+	if !ok || charmStorage.CountMin == 0 {
+		return nil, nil
+	}
+
+	storageCollection, closer := st.getCollection(storageInstancesC)
+	defer closer()
+	var others []storageInstanceDoc
+	err = storageCollection.Find(bson.D{
+		{"owner", unitTag.String()},
+		{"storagename", s.StorageName()},
+		{"life", Alive},
+		{"releasing", bson.D{{"$ne", true}}},
+		{"id", bson.D{{"$ne", s.doc.Id}}},
+	}).All(&others)
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting storage instances for %s", names.ReadableString(unitTag))
+	}
+	if len(others) < charmStorage.CountMin {
+		return nil, errStorageRequired
+	}
+	// The unit must remain alive, and the remaining instances
+	// attached to it, for the minimum to be met.
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: isAliveDoc,
+	}}
+	for _, doc := range others {
+		ops = append(ops, txn.Op{
+			C:  storageInstancesC,
+			Id: doc.Id,
+			Assert: append(bson.D{
+				{"owner", unitTag.String()},
+				{"releasing", bson.D{{"$ne", true}}},
+			}, isAliveDoc...),
+		})
+	}
+	return ops, nil
+}
+
+// detachStorageMachineOps returns txn.Ops to detach the volume or
+// filesystem assigned to the specified detached storage instance
+// from the machine that the specified unit is assigned to.
+func detachStorageMachineOps(st *State, si *storageInstance, unitTag names.UnitTag) ([]txn.Op, error) {
+	u, err := st.Unit(unitTag.Id())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineId, err := u.AssignedMachineId()
+	if errors.IsNotAssigned(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineTag := names.NewMachineTag(machineId)
+	switch si.doc.Kind {
+	case StorageKindBlock:
+		v, err := st.StorageInstanceVolume(si.StorageTag())
+		if errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return destroyVolumeAttachmentOps(st, machineTag, v.VolumeTag())
+	case StorageKindFilesystem:
+		// The filesystem's backing volume, if any, is detached
+		// once the filesystem attachment has been removed.
+		f, err := st.StorageInstanceFilesystem(si.StorageTag())
+		if errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return destroyFilesystemAttachmentOps(st, machineTag, f.FilesystemTag())
+	}
+	return nil, errors.Errorf("invalid storage kind %v", si.doc.Kind)
+}
+
+// attachStorageOps returns txn.Ops to attach the specified detached
+// storage instances to the new unit with the specified tag, transferring
+// ownership of the storage instances to the unit. The number of storage
+// instances attached is returned, keyed by storage name.
+func attachStorageOps(
+	st *State,
+	storageTags []names.StorageTag,
+	unitTag names.UnitTag,
+	charmMeta *charm.Meta,
+) (ops []txn.Op, attached map[string]uint64, err error) {
+	attached = make(map[string]uint64)
+	for _, tag := range storageTags {
+		s, err := st.storageInstance(tag)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if err := validateAttachStorage(st, s, charmMeta); err != nil {
+			return nil, nil, errors.Annotatef(err, "cannot attach storage %q", tag.Id())
+		}
+		ops = append(ops, txn.Op{
+			C:  storageInstancesC,
+			Id: s.doc.Id,
+			Assert: append(bson.D{
+				{"owner", ""},
+				{"attachmentcount", 0},
+			}, isAliveDoc...),
+			Update: bson.D{{"$set", bson.D{
+				{"owner", unitTag.String()},
+				{"attachmentcount", 1},
+			}}},
+		}, createStorageAttachmentOp(tag, unitTag))
+		attached[s.doc.StorageName]++
+	}
+	for name, n := range attached {
+		charmStorage := charmMeta.Storage[name]
+		if charmStorage.CountMax >= 0 && n > uint64(charmStorage.CountMax) {
+			return nil, nil, errors.Errorf(
+				"cannot attach %d instances of storage %q, charm allows at most %d",
+				n, name, charmStorage.CountMax,
+			)
+		}
+	}
+	return ops, attached, nil
+}
+
+// validateAttachStorage checks that the specified storage instance may be
+// attached to a new unit running the charm with the specified metadata.
+func validateAttachStorage(st *State, s *storageInstance, charmMeta *charm.Meta) error {
+	if s.doc.Life != Alive {
+		return errors.New("storage is not alive")
+	}
+	if owner, ok := s.Owner(); ok {
+		return errors.Errorf("storage is owned by %s", names.ReadableString(owner))
+	}
+	if s.doc.AttachmentCount != 0 {
+		return errors.New("storage is still being detached")
+	}
+	charmStorage, ok := charmMeta.Storage[s.doc.StorageName]
+	if !ok {
+		return errors.NotFoundf("charm storage %q", s.doc.StorageName)
+	}
+	if charmStorage.Shared {
+		return errors.NotSupportedf("attaching shared storage")
+	}
+	var kind StorageKind
+	switch charmStorage.Type {
+	case charm.StorageBlock:
+		kind = StorageKindBlock
+	case charm.StorageFilesystem:
+		kind = StorageKindFilesystem
+	}
+	if kind != s.doc.Kind {
+		return errors.Errorf("charm storage %q has incompatible type %q", s.doc.StorageName, charmStorage.Type)
+	}
+	switch s.doc.Kind {
+	case StorageKindBlock:
+		v, err := st.StorageInstanceVolume(s.StorageTag())
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		return validateAttachVolume(st, v.VolumeTag())
+	case StorageKindFilesystem:
+		f, err := st.StorageInstanceFilesystem(s.StorageTag())
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if machineTag, ok := names.FilesystemMachine(f.FilesystemTag()); ok {
+			return errors.Errorf(
+				"filesystem %q is bound to machine %q",
+				f.FilesystemTag().Id(), machineTag.Id(),
+			)
+		}
+		attachments, err := st.FilesystemAttachments(f.FilesystemTag())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(attachments) > 0 {
+			return errors.Errorf(
+				"filesystem %q is still attached to machine %q",
+				f.FilesystemTag().Id(), attachments[0].Machine().Id(),
+			)
+		}
+		if volumeTag, err := f.Volume(); err == nil {
+			return validateAttachVolume(st, volumeTag)
+		} else if err != ErrNoBackingVolume {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// validateAttachVolume checks that the volume with the specified tag
+// may be attached to a new machine.
+func validateAttachVolume(st *State, volumeTag names.VolumeTag) error {
+	if machineTag, ok := names.VolumeMachine(volumeTag); ok {
+		return errors.Errorf(
+			"volume %q is bound to machine %q",
+			volumeTag.Id(), machineTag.Id(),
+		)
+	}
+	attachments, err := st.VolumeAttachments(volumeTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(attachments) > 0 {
+		return errors.Errorf(
+			"volume %q is still attached to machine %q",
+			volumeTag.Id(), attachments[0].Machine().Id(),
+		)
+	}
+	return nil
+}
+
 // removeStorageInstancesOps returns the transaction operations to remove all
-// storage instances owned by the specified entity.
+// storage instances owned by the specified entity. Storage instances that
+// are being released by the entity are released rather than removed, so
+// that they outlive it.
 func removeStorageInstancesOps(st *State, owner names.Tag) ([]txn.Op, error) {
 	coll, closer := st.getCollection(storageInstancesC)
 	defer closer()
 
 	var docs []storageInstanceDoc
-	err := coll.Find(bson.D{{"owner", owner.String()}}).Select(bson.D{{"id", true}}).All(&docs)
+	err := coll.Find(bson.D{{"owner", owner.String()}}).Select(bson.D{
+		{"id", true},
+		{"releasing", true},
+	}).All(&docs)
 	if err != nil {
 		return nil, errors.Annotatef(err, "cannot get storage instances for %s", owner)
 	}
 	ops := make([]txn.Op, len(docs))
 	for i, doc := range docs {
+		if doc.Releasing {
+			ops[i] = txn.Op{
+				C:  storageInstancesC,
+				Id: doc.Id,
+				Assert: bson.D{
+					{"owner", owner.String()},
+					{"releasing", true},
+				},
+				Update: bson.D{
+					{"$set", bson.D{{"owner", ""}}},
+					{"$unset", bson.D{{"releasing", nil}}},
+				},
+			}
+			continue
+		}
+		// The storage instance must not have been detached, or
+		// marked for release, since we read it.
 		ops[i] = txn.Op{
-			C:      storageInstancesC,
-			Id:     doc.Id,
+			C:  storageInstancesC,
+			Id: doc.Id,
+			Assert: bson.D{
+				{"owner", owner.String()},
+				{"releasing", bson.D{{"$ne", true}}},
+			},
 			Remove: true,
 		}
 	}
@@ -631,6 +1009,10 @@ type StorageConstraints struct {
 
 	// Count is the required number of storage instances.
 	Count uint64 `bson:"count"`
+
+	// Snapshot, if non-empty, is the ID of the snapshot from which
+	// the storage instances' volumes are to be created.
+	Snapshot string `bson:"snapshot,omitempty"`
 }
 
 func createStorageConstraintsOp(key string, cons map[string]StorageConstraints) txn.Op {
@@ -679,7 +1061,7 @@ func storageKind(storageType charm.StorageType) storage.StorageKind {
 }
 
 func validateStorageConstraints(st *State, allCons map[string]StorageConstraints, charmMeta *charm.Meta) error {
-	err := validateStorageConstraintsAgainstCharm(st, allCons, charmMeta)
+	err := validateStorageConstraintsAgainstCharm(st, nil, allCons, charmMeta)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -695,9 +1077,14 @@ func validateStorageConstraints(st *State, allCons map[string]StorageConstraints
 
 func validateStorageConstraintsAgainstCharm(
 	st *State,
+	serviceDefaults *StoragePoolDefaults,
 	allCons map[string]StorageConstraints,
 	charmMeta *charm.Meta,
 ) error {
+	conf, err := st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
 	for name, cons := range allCons {
 		charmStorage, ok := charmMeta.Storage[name]
 		if !ok {
@@ -731,9 +1118,41 @@ func validateStorageConstraintsAgainstCharm(
 			)
 		}
 		kind := storageKind(charmStorage.Type)
+		if cons.Pool == "" {
+			// The pool is resolved when the storage is
+			// provisioned; validate the current default.
+			poolName, err := defaultStoragePool(conf, serviceDefaults, kind)
+			if err != nil {
+				return errors.Annotatef(err, "finding default pool for %q storage", name)
+			}
+			cons.Pool = poolName
+		}
 		if err := validateStoragePool(st, cons.Pool, kind, nil); err != nil {
 			return err
 		}
+		if cons.Snapshot != "" {
+			// The machine is not known until the unit is
+			// assigned, so machine scope is checked when
+			// the volume is created.
+			snapshot, err := st.Snapshot(cons.Snapshot)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if cons.Pool != snapshot.Pool() {
+				return errors.Errorf(
+					"charm %q store %q: snapshot %q was taken from pool %q, %q specified",
+					charmMeta.Name, name, cons.Snapshot, snapshot.Pool(), cons.Pool,
+				)
+			}
+			if cons.Size < snapshot.Size() {
+				return errors.Errorf(
+					"charm %q store %q: snapshot %q requires at least %s, %s specified",
+					charmMeta.Name, name, cons.Snapshot,
+					humanize.Bytes(snapshot.Size()*humanize.MByte),
+					humanize.Bytes(cons.Size*humanize.MByte),
+				)
+			}
+		}
 	}
 	return nil
 }
@@ -828,12 +1247,48 @@ func poolStorageProvider(st *State, poolName string) (storage.ProviderType, stor
 	return providerType, provider, nil
 }
 
+// storageServiceDefaults returns the storage pool defaults of the
+// service that owns the specified storage instance, either directly
+// or through one of its units. If the storage instance does not
+// exist or has no owner, nil is returned.
+func storageServiceDefaults(st *State, tag names.StorageTag) (*StoragePoolDefaults, error) {
+	if tag == (names.StorageTag{}) {
+		return nil, nil
+	}
+	s, err := st.storageInstance(tag)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	owner, ok := s.Owner()
+	if !ok {
+		return nil, nil
+	}
+	var serviceName string
+	switch owner := owner.(type) {
+	case names.ServiceTag:
+		serviceName = owner.Id()
+	case names.UnitTag:
+		serviceName = names.UnitService(owner.Id())
+	default:
+		return nil, nil
+	}
+	service, err := st.Service(serviceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return service.StorageDefaults(), nil
+}
+
 // ErrNoDefaultStoragePool is returned when a storage pool is required but none
 // is specified nor available as a default.
 var ErrNoDefaultStoragePool = fmt.Errorf("no storage pool specifed and no default available")
 
 // addDefaultStorageConstraints fills in default constraint values, replacing any empty/missing values
-// in the specified constraints.
+// in the specified constraints. The default pool is not recorded, so that
+// it is resolved when the storage is provisioned; this way any storage pool
+// defaults set on the service later are honoured.
 func addDefaultStorageConstraints(st *State, allCons map[string]StorageConstraints, charmMeta *charm.Meta) error {
 	conf, err := st.EnvironConfig()
 	if err != nil {
@@ -844,20 +1299,28 @@ func addDefaultStorageConstraints(st *State, allCons map[string]StorageConstrain
 		allCons = make(map[string]StorageConstraints)
 	}
 	for name, charmStorage := range charmMeta.Storage {
-		cons, err := storageConstraintsWithDefaults(conf, charmStorage, name, allCons[name])
+		cons, err := snapshotStorageConstraints(st, allCons[name])
+		if err != nil {
+			return errors.Annotatef(err, "storage %q", name)
+		}
+		withDefaults, err := storageConstraintsWithDefaults(conf, nil, charmStorage, name, cons)
 		if err != nil {
 			return errors.Trace(err)
 		}
+		withDefaults.Pool = cons.Pool
 		// Replace in case pool or size were updated.
-		allCons[name] = cons
+		allCons[name] = withDefaults
 	}
 	return nil
 }
 
 // storageConstraintsWithDefaults returns a constraints
-// derived from cons, with any defaults filled in.
+// derived from cons, with any defaults filled in. The
+// service's storage pool defaults, if non-nil, take
+// precedence over those of the environment.
 func storageConstraintsWithDefaults(
 	cfg *config.Config,
+	serviceDefaults *StoragePoolDefaults,
 	charmStorage charm.Storage,
 	name string,
 	cons StorageConstraints,
@@ -865,7 +1328,7 @@ func storageConstraintsWithDefaults(
 	withDefaults := cons
 	if cons.Pool == "" {
 		kind := storageKind(charmStorage.Type)
-		poolName, err := defaultStoragePool(cfg, kind)
+		poolName, err := defaultStoragePool(cfg, serviceDefaults, kind)
 		if err != nil {
 			return withDefaults, errors.Annotatef(err, "finding default pool for %q storage", name)
 		}
@@ -885,15 +1348,27 @@ func storageConstraintsWithDefaults(
 }
 
 // defaultStoragePool returns the default storage pool for the environment.
-// The default pool is either user specified, or one that is registered by the provider itself.
-func defaultStoragePool(cfg *config.Config, kind storage.StorageKind) (string, error) {
+// The default pool is either specified by the service's storage pool
+// defaults, user specified in environment config, or one that is registered
+// by the provider itself.
+func defaultStoragePool(cfg *config.Config, serviceDefaults *StoragePoolDefaults, kind storage.StorageKind) (string, error) {
 	switch kind {
 	case storage.StorageKindBlock:
+		if serviceDefaults != nil && serviceDefaults.Block != "" {
+			return serviceDefaults.Block, nil
+		}
 		defaultPool, ok := cfg.StorageDefaultBlockSource()
 		if !ok {
 			defaultPool = string(provider.LoopProviderType)
 		}
 		return defaultPool, nil
+	case storage.StorageKindFilesystem:
+		if serviceDefaults != nil && serviceDefaults.Filesystem != "" {
+			return serviceDefaults.Filesystem, nil
+		}
+		if defaultPool, ok := cfg.StorageDefaultFilesystemSource(); ok {
+			return defaultPool, nil
+		}
 	}
 	return "", ErrNoDefaultStoragePool
 }
@@ -920,21 +1395,27 @@ func (st *State) AddStorageForUnit(
 	if err != nil {
 		return errors.Trace(err)
 	}
+	service, err := u.Service()
+	if err != nil {
+		return errors.Trace(err)
+	}
 	completeCons, err := storageConstraintsWithDefaults(
 		conf,
+		service.StorageDefaults(),
 		charmMeta.Storage[name],
 		name, cons,
 	)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	completeCons.Pool = cons.Pool
 
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		err := u.Refresh()
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		err = st.validateUnitStorage(charmMeta, u, name, completeCons)
+		err = st.validateUnitStorage(charmMeta, service.StorageDefaults(), u, name, completeCons)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -951,7 +1432,8 @@ func (st *State) AddStorageForUnit(
 }
 
 func (st *State) validateUnitStorage(
-	charmMeta *charm.Meta, u *Unit, name string, cons StorageConstraints,
+	charmMeta *charm.Meta, serviceDefaults *StoragePoolDefaults,
+	u *Unit, name string, cons StorageConstraints,
 ) error {
 	// Storage directive may provide storage instance count
 	// which combined with existing storage instance may exceed
@@ -965,6 +1447,7 @@ func (st *State) validateUnitStorage(
 
 	err = validateStorageConstraintsAgainstCharm(
 		st,
+		serviceDefaults,
 		map[string]StorageConstraints{name: cons},
 		charmMeta)
 	if err != nil {
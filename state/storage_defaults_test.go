@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type StorageDefaultsSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&StorageDefaultsSuite{})
+
+func (s *StorageDefaultsSuite) TestSetStorageDefaults(c *gc.C) {
+	service, _, _ := s.setupSingleStorage(c, "block", "loop-pool")
+	c.Assert(service.StorageDefaults(), gc.IsNil)
+
+	defaults := state.StoragePoolDefaults{Block: "loop-pool", Filesystem: "rootfs"}
+	err := service.SetStorageDefaults(defaults)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.StorageDefaults(), jc.DeepEquals, &defaults)
+
+	err = service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.StorageDefaults(), jc.DeepEquals, &defaults)
+
+	// Setting empty defaults clears the service's defaults.
+	err = service.SetStorageDefaults(state.StoragePoolDefaults{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.StorageDefaults(), gc.IsNil)
+	err = service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.StorageDefaults(), gc.IsNil)
+}
+
+func (s *StorageDefaultsSuite) TestSetStorageDefaultsValidation(c *gc.C) {
+	service, _, _ := s.setupSingleStorage(c, "block", "loop-pool")
+
+	err := service.SetStorageDefaults(state.StoragePoolDefaults{Block: "ebs-fast"})
+	c.Assert(err, gc.ErrorMatches, `cannot set storage defaults for service "storage-block": validating block pool: pool "ebs-fast" not found`)
+
+	err = service.SetStorageDefaults(state.StoragePoolDefaults{Block: "rootfs"})
+	c.Assert(err, gc.ErrorMatches, `cannot set storage defaults for service "storage-block": validating block pool: "rootfs" provider does not support "block" storage`)
+	c.Assert(service.StorageDefaults(), gc.IsNil)
+}
+
+func (s *StorageDefaultsSuite) TestSetStorageDefaultsServiceNotAlive(c *gc.C) {
+	// The service has a unit, so destroying it leaves it Dying.
+	service, _, _ := s.setupSingleStorage(c, "block", "loop-pool")
+	err := service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = service.SetStorageDefaults(state.StoragePoolDefaults{Block: "loop-pool"})
+	c.Assert(err, gc.ErrorMatches, `cannot set storage defaults for service "storage-block": service not found or not alive`)
+}
+
+func (s *StorageDefaultsSuite) TestAddStorageForUnitServiceDefaults(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"storage-default-block-source": "ebs-fast",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	service, u, _ := s.setupSingleStorage(c, "block", "loop-pool")
+	ch, _, err := service.Charm()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The environment's default pool does not exist, so
+	// adding storage without specifying a pool fails.
+	err = s.State.AddStorageForUnit(ch.Meta(), u, "allecto", state.StorageConstraints{Count: 1})
+	c.Assert(err, gc.ErrorMatches, `.*pool "ebs-fast" not found`)
+
+	// The service's defaults take precedence over the environment's.
+	err = service.SetStorageDefaults(state.StoragePoolDefaults{Block: "loop-pool"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddStorageForUnit(ch.Meta(), u, "allecto", state.StorageConstraints{Count: 1})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StorageDefaultsSuite) TestServiceDefaultsSetAfterDeploy(c *gc.C) {
+	// No pool is specified, so the environment's default ("loop")
+	// would be used if the service's defaults were not honoured.
+	service, u, storageTag := s.setupSingleStorage(c, "block", "")
+	cons, err := service.StorageConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons["data"].Pool, gc.Equals, "")
+
+	err = service.SetStorageDefaults(state.StoragePoolDefaults{Block: "loop-pool"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(u, state.AssignNew)
+	c.Assert(err, jc.ErrorIsNil)
+
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeParams, ok := volume.Params()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(volumeParams.Pool, gc.Equals, "loop-pool")
+}
@@ -0,0 +1,289 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type StorageDetachSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&StorageDetachSuite{})
+
+// setupAssignedStorage adds a unit with a single environment-scoped
+// volume, and assigns it to a new machine.
+func (s *StorageDetachSuite) setupAssignedStorage(c *gc.C) (*state.Service, *state.Unit, names.StorageTag) {
+	service, u, storageTag := s.setupSingleStorage(c, "block", "environscoped-block")
+	err := s.State.AssignUnit(u, state.AssignNew)
+	c.Assert(err, jc.ErrorIsNil)
+	return service, u, storageTag
+}
+
+// removeStorageAttachment destroys the unit and removes its attachment
+// to the specified storage, simulating the uniter.
+func (s *StorageDetachSuite) removeStorageAttachment(c *gc.C, u *state.Unit, storageTag names.StorageTag) {
+	err := u.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.EnsureStorageAttachmentDead(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// removeUnit removes the unit once its storage attachments
+// have been removed, simulating the uniter.
+func (s *StorageDetachSuite) removeUnit(c *gc.C, u *state.Unit) {
+	err := u.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// setupDetachedVolume adds a unit with a single environment-scoped
+// volume, assigns it to a new machine, detaches the storage from the
+// unit and then removes the unit, simulating the uniter and storage
+// provisioner.
+func (s *StorageDetachSuite) setupDetachedVolume(c *gc.C) (*state.Service, *state.Unit, names.StorageTag, names.VolumeTag) {
+	service, u, storageTag := s.setupAssignedStorage(c)
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.DetachStorage(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	s.removeStorageAttachment(c, u, storageTag)
+	s.removeUnit(c, u)
+	err = s.State.RemoveVolumeAttachment(names.NewMachineTag("0"), volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+	return service, u, storageTag, volume.VolumeTag()
+}
+
+func (s *StorageDetachSuite) TestDetachStorage(c *gc.C) {
+	_, u, storageTag := s.setupAssignedStorage(c)
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	machineTag := names.NewMachineTag("0")
+
+	// The charm requires the storage, so it remains
+	// attached to the unit while the unit is alive.
+	err = s.State.DetachStorage(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	owner, ok := si.Owner()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(owner, gc.Equals, u.Tag())
+	c.Assert(si.Life(), gc.Equals, state.Alive)
+	att, err := s.State.StorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(att.Life(), gc.Equals, state.Alive)
+
+	// Detaching again is a no-op.
+	err = s.State.DetachStorage(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The volume remains attached to the machine until the
+	// storage attachment has been removed.
+	volumeAttachment, err := s.State.VolumeAttachment(machineTag, volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeAttachment.Life(), gc.Equals, state.Alive)
+
+	s.removeStorageAttachment(c, u, storageTag)
+	volumeAttachment, err = s.State.VolumeAttachment(machineTag, volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeAttachment.Life(), gc.Equals, state.Dying)
+
+	// The storage instance is released, not removed,
+	// when the unit is removed.
+	s.removeUnit(c, u)
+	si, err = s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = si.Owner()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(si.Life(), gc.Equals, state.Alive)
+
+	// The storage instance and volume outlive the attachments.
+	err = s.State.RemoveVolumeAttachment(machineTag, volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.VolumeAttachment(machineTag, volume.VolumeTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Volume(volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StorageDetachSuite) TestDetachStorageDyingUnit(c *gc.C) {
+	_, u, storageTag := s.setupAssignedStorage(c)
+	err := u.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The unit is not alive, so the storage is detached immediately.
+	err = s.State.DetachStorage(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := si.Owner()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(si.Life(), gc.Equals, state.Alive)
+	att, err := s.State.StorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(att.Life(), gc.Equals, state.Dying)
+}
+
+func (s *StorageDetachSuite) TestDetachStorageConcurrentUnitRemoval(c *gc.C) {
+	_, u, storageTag := s.setupAssignedStorage(c)
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	s.removeStorageAttachment(c, u, storageTag)
+	err = u.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := s.State.DetachStorage(storageTag)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	// The storage is detached before the unit is removed,
+	// so it is not removed with the unit.
+	err = u.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := si.Owner()
+	c.Assert(ok, jc.IsFalse)
+
+	// The storage attachment had already been removed, so the
+	// volume is detached from the machine when the storage is.
+	volumeAttachment, err := s.State.VolumeAttachment(names.NewMachineTag("0"), volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeAttachment.Life(), gc.Equals, state.Dying)
+}
+
+func (s *StorageDetachSuite) TestRemoveUnitRemovesStorage(c *gc.C) {
+	_, u, storageTag := s.setupAssignedStorage(c)
+	s.removeStorageAttachment(c, u, storageTag)
+	s.removeUnit(c, u)
+	_, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StorageDetachSuite) TestDetachStorageOptional(c *gc.C) {
+	ch := s.AddTestingCharm(c, "storage-block")
+	service := s.AddTestingServiceWithStorage(c, "storage-block", ch, map[string]state.StorageConstraints{
+		"data":    makeStorageCons("environscoped-block", 1024, 1),
+		"allecto": makeStorageCons("environscoped-block", 1024, 1),
+	})
+	u, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	attachments, err := s.State.UnitStorageAttachments(u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	var storageTag names.StorageTag
+	for _, att := range attachments {
+		si, err := s.State.StorageInstance(att.StorageInstance())
+		c.Assert(err, jc.ErrorIsNil)
+		if si.StorageName() == "allecto" {
+			storageTag = si.StorageTag()
+		}
+	}
+	c.Assert(storageTag.Id(), gc.Matches, "allecto/.*")
+
+	// The charm requires no instances of "allecto", so it may
+	// be detached from a live unit.
+	err = s.State.DetachStorage(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := si.Owner()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *StorageDetachSuite) TestRemoveVolumeAttachmentAlive(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "environscoped-block")
+	err := s.State.AssignUnit(u, state.AssignNew)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveVolumeAttachment(names.NewMachineTag("0"), volume.VolumeTag())
+	c.Assert(err, gc.ErrorMatches, `cannot remove attachment of volume "0" to machine "0": volume attachment is alive`)
+}
+
+func (s *StorageDetachSuite) TestAddUnitWithStorage(c *gc.C) {
+	service, _, storageTag, volumeTag := s.setupDetachedVolume(c)
+
+	u, err := service.AddUnitWithStorage([]names.StorageTag{storageTag})
+	c.Assert(err, jc.ErrorIsNil)
+	si, err := s.State.StorageInstance(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	owner, ok := si.Owner()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(owner, gc.Equals, u.Tag())
+	_, err = s.State.StorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The attached storage satisfies the service's storage
+	// constraints, so no new storage instance is created.
+	_, err = s.State.StorageInstance(names.NewStorageTag("data/1"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// The existing volume is attached to the unit's new machine.
+	err = s.State.AssignUnit(u, state.AssignNew)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := u.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Not(gc.Equals), "0")
+	_, err = s.State.VolumeAttachment(names.NewMachineTag(machineId), volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StorageDetachSuite) TestAddUnitWithStorageOwned(c *gc.C) {
+	service, _, storageTag := s.setupSingleStorage(c, "block", "environscoped-block")
+	_, err := service.AddUnitWithStorage([]names.StorageTag{storageTag})
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to service "storage-block": cannot attach storage "data/0": storage is owned by unit storage-block/0`)
+}
+
+func (s *StorageDetachSuite) TestAddUnitWithStorageReleasing(c *gc.C) {
+	service, _, storageTag := s.setupAssignedStorage(c)
+	err := s.State.DetachStorage(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The storage remains owned by the unit until it is removed.
+	_, err = service.AddUnitWithStorage([]names.StorageTag{storageTag})
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to service "storage-block": cannot attach storage "data/0": storage is owned by unit storage-block/0`)
+}
+
+func (s *StorageDetachSuite) TestAddUnitWithStorageStillDetaching(c *gc.C) {
+	service, u, storageTag := s.setupAssignedStorage(c)
+	err := u.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.DetachStorage(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = service.AddUnitWithStorage([]names.StorageTag{storageTag})
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to service "storage-block": cannot attach storage "data/0": storage is still being detached`)
+
+	// Once the storage attachment is removed, the volume must
+	// still be detached from the machine.
+	err = s.State.EnsureStorageAttachmentDead(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveStorageAttachment(storageTag, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = service.AddUnitWithStorage([]names.StorageTag{storageTag})
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to service "storage-block": cannot attach storage "data/0": volume "0" is still attached to machine "0"`)
+}
+
+func (s *StorageDetachSuite) TestAddUnitWithStorageIncompatibleCharm(c *gc.C) {
+	_, _, storageTag, _ := s.setupDetachedVolume(c)
+	ch := s.AddTestingCharm(c, "storage-filesystem")
+	service := s.AddTestingServiceWithStorage(c, "storage-filesystem", ch, map[string]state.StorageConstraints{
+		"data": makeStorageCons("environscoped", 1024, 1),
+	})
+	_, err := service.AddUnitWithStorage([]names.StorageTag{storageTag})
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to service "storage-filesystem": cannot attach storage "data/0": charm storage "data" has incompatible type "filesystem"`)
+}
@@ -154,8 +154,8 @@ func (s *StorageStateSuite) TestAddServiceStorageConstraintsDefaultPool(c *gc.C)
 		"data": makeStorageCons("", 2048, 1),
 	}
 	expectedCons := map[string]state.StorageConstraints{
-		"data":    makeStorageCons("loop-pool", 2048, 1),
-		"allecto": makeStorageCons("loop-pool", 1024, 0),
+		"data":    makeStorageCons("", 2048, 1),
+		"allecto": makeStorageCons("", 1024, 0),
 	}
 	s.assertAddServiceStorageConstraintsDefaults(c, "loop-pool", storageCons, expectedCons)
 }
@@ -165,8 +165,8 @@ func (s *StorageStateSuite) TestAddServiceStorageConstraintsNoUserDefaultPool(c
 		"data": makeStorageCons("", 2048, 1),
 	}
 	expectedCons := map[string]state.StorageConstraints{
-		"data":    makeStorageCons("loop", 2048, 1),
-		"allecto": makeStorageCons("loop", 1024, 0),
+		"data":    makeStorageCons("", 2048, 1),
+		"allecto": makeStorageCons("", 1024, 0),
 	}
 	s.assertAddServiceStorageConstraintsDefaults(c, "", storageCons, expectedCons)
 }
@@ -177,7 +177,7 @@ func (s *StorageStateSuite) TestAddServiceStorageConstraintsDefaultSizeFallback(
 	}
 	expectedCons := map[string]state.StorageConstraints{
 		"data":    makeStorageCons("loop-pool", 1024, 1),
-		"allecto": makeStorageCons("loop-pool", 1024, 0),
+		"allecto": makeStorageCons("", 1024, 0),
 	}
 	s.assertAddServiceStorageConstraintsDefaults(c, "loop-pool", storageCons, expectedCons)
 }
@@ -188,7 +188,7 @@ func (s *StorageStateSuite) TestAddServiceStorageConstraintsDefaultSizeFromCharm
 	}
 	expectedCons := map[string]state.StorageConstraints{
 		"multi1to10": makeStorageCons("loop", 1024, 3),
-		"multi2up":   makeStorageCons("loop", 2048, 2),
+		"multi2up":   makeStorageCons("", 2048, 2),
 	}
 	ch := s.AddTestingCharm(c, "storage-block2")
 	service, err := s.State.AddService("storage-block2", "user-test-admin@local", ch, nil, storageCons)
@@ -262,7 +262,9 @@ func (s *StorageStateSuite) TestAllStorageInstances(c *gc.C) {
 	for _, one := range all {
 		c.Assert(one.Kind(), gc.DeepEquals, state.StorageKindBlock)
 		c.Assert(nameSet.Contains(one.StorageName()), jc.IsTrue)
-		c.Assert(ownerSet.Contains(one.Owner().String()), jc.IsTrue)
+		owner, ok := one.Owner()
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(ownerSet.Contains(owner.String()), jc.IsTrue)
 	}
 }
 
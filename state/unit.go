@@ -6,6 +6,8 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -1156,6 +1158,8 @@ var (
 // - unitNotAliveErr when the unit is not alive.
 // - alreadyAssignedErr when the unit has already been assigned
 // - inUseErr when the machine already has a unit assigned (if unused is true)
+// - an error satisfying isPlacementPolicyError when the service's placement
+// policy does not allow the unit to be placed on the machine.
 func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 	if u.doc.Series != m.doc.Series {
 		return fmt.Errorf("series does not match")
@@ -1169,19 +1173,7 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 	if u.doc.Principal != "" {
 		return fmt.Errorf("unit is a subordinate")
 	}
-	canHost := false
-	for _, j := range m.doc.Jobs {
-		if j == JobHostUnits {
-			canHost = true
-			break
-		}
-	}
-	if !canHost {
-		return fmt.Errorf("machine %q cannot host units", m)
-	}
-	// assignToMachine implies assignment to an existing machine,
-	// which is only permitted if unit placement is supported.
-	if err := u.st.supportsUnitPlacement(); err != nil {
+	if err := u.checkMachineHostsUnits(m); err != nil {
 		return err
 	}
 	assert := append(isAliveDoc, bson.D{
@@ -1194,43 +1186,104 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 	if unused {
 		massert = append(massert, bson.D{{"clean", bson.D{{"$ne", false}}}}...)
 	}
-	ops := []txn.Op{{
-		C:      unitsC,
-		Id:     u.doc.DocID,
-		Assert: assert,
-		Update: bson.D{{"$set", bson.D{{"machineid", m.doc.Id}}}},
-	}, {
-		C:      machinesC,
-		Id:     m.doc.DocID,
-		Assert: massert,
-		Update: bson.D{{"$addToSet", bson.D{{"principals", u.doc.Name}}}, {"$set", bson.D{{"clean", false}}}},
-	}}
-	err = u.st.runTransaction(ops)
-	if err == nil {
-		u.doc.MachineId = m.doc.Id
-		m.doc.Clean = false
-		return nil
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			u0, err := u.st.Unit(u.Name())
+			if err != nil {
+				return nil, err
+			}
+			m0, err := u.st.Machine(m.Id())
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case u0.Life() != Alive:
+				return nil, unitNotAliveErr
+			case m0.Life() != Alive:
+				return nil, machineNotAliveErr
+			case u0.doc.MachineId != "":
+				return nil, alreadyAssignedErr
+			case unused && !m0.doc.Clean:
+				return nil, inUseErr
+			}
+			// The placement policy, or the assignments it was
+			// checked against, changed; check it again.
+		}
+		// The policy is checked for each attempt, and asserted
+		// by the transaction, so that concurrent assignments of
+		// the service's units cannot both satisfy it.
+		policyOps, err := u.checkPlacementPolicy(m)
+		if err != nil {
+			return nil, err
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: assert,
+			Update: bson.D{{"$set", bson.D{{"machineid", m.doc.Id}}}},
+		}, {
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: massert,
+			Update: bson.D{{"$addToSet", bson.D{{"principals", u.doc.Name}}}, {"$set", bson.D{{"clean", false}}}},
+		}}
+		return append(ops, policyOps...), nil
 	}
-	if err != txn.ErrAborted {
+	if err := u.st.run(buildTxn); err != nil {
 		return err
 	}
-	u0, err := u.st.Unit(u.Name())
-	if err != nil {
-		return err
+	u.doc.MachineId = m.doc.Id
+	m.doc.Clean = false
+	return nil
+}
+
+// checkMachineHostsUnits returns an error if units may not be assigned
+// to the machine, because it lacks the JobHostUnits job, or because the
+// environment does not support placing units on existing machines.
+func (u *Unit) checkMachineHostsUnits(m *Machine) error {
+	canHost := false
+	for _, j := range m.doc.Jobs {
+		if j == JobHostUnits {
+			canHost = true
+			break
+		}
+	}
+	if !canHost {
+		return fmt.Errorf("machine %q cannot host units", m)
 	}
-	m0, err := u.st.Machine(m.Id())
+	// assignToMachine implies assignment to an existing machine,
+	// which is only permitted if unit placement is supported.
+	return u.st.supportsUnitPlacement()
+}
+
+// CheckAssignToMachine returns the error with which assigning a new
+// unit, with the given name and series, to the machine would fail, or
+// nil if it would succeed, without changing the environment. The unit
+// need not exist. The same checks are run as by AssignToMachine: of the
+// machine's series, life and jobs, and of the placement policy of the
+// unit's service.
+func (st *State) CheckAssignToMachine(unitName, series string, m *Machine) (err error) {
+	serviceName, err := names.UnitService(unitName)
 	if err != nil {
+		return errors.Trace(err)
+	}
+	u := &Unit{st: st, doc: unitDoc{
+		Name:    unitName,
+		Service: serviceName,
+		Series:  series,
+	}}
+	defer assignContextf(&err, u, fmt.Sprintf("machine %s", m))
+	if u.doc.Series != m.doc.Series {
+		return fmt.Errorf("series does not match")
+	}
+	if err := u.checkMachineHostsUnits(m); err != nil {
 		return err
 	}
-	switch {
-	case u0.Life() != Alive:
-		return unitNotAliveErr
-	case m0.Life() != Alive:
+	if m.doc.Life != Alive {
 		return machineNotAliveErr
-	case u0.doc.MachineId != "" || !unused:
-		return alreadyAssignedErr
 	}
-	return inUseErr
+	_, err = u.checkPlacementPolicy(m)
+	return err
 }
 
 func assignContextf(err *error, unit *Unit, target string) {
@@ -1246,8 +1299,10 @@ func (u *Unit) AssignToMachine(m *Machine) (err error) {
 }
 
 // assignToNewMachine assigns the unit to a machine created according to
-// the supplied params, with the supplied constraints.
-func (u *Unit) assignToNewMachine(template MachineTemplate, parentId string, containerType instance.ContainerType) error {
+// the supplied params, with the supplied constraints. Any policyOps,
+// as returned by checkPlacementPolicy for the parent machine, are run
+// in the same transaction.
+func (u *Unit) assignToNewMachine(template MachineTemplate, parentId string, containerType instance.ContainerType, policyOps ...txn.Op) error {
 	template.principals = []string{u.doc.Name}
 	template.Dirty = true
 
@@ -1267,6 +1322,8 @@ func (u *Unit) assignToNewMachine(template MachineTemplate, parentId string, con
 		// regardless of its child.
 		parentParams := template
 		parentParams.Jobs = []MachineJob{JobHostUnits}
+		// Any placement directive applies to the new parent machine.
+		template.Placement = ""
 		mdoc, ops, err = u.st.addMachineInsideNewMachineOps(template, parentParams, containerType)
 	default:
 		// Container type is specified but no parent id.
@@ -1296,6 +1353,7 @@ func (u *Unit) assignToNewMachine(template MachineTemplate, parentId string, con
 		Assert: asserts,
 		Update: bson.D{{"$set", bson.D{{"machineid", mdoc.Id}}}},
 	})
+	ops = append(ops, policyOps...)
 
 	err = u.st.runTransaction(ops)
 	if err == nil {
@@ -1313,6 +1371,8 @@ func (u *Unit) assignToNewMachine(template MachineTemplate, parentId string, con
 	//  * the unit has been assigned to a different machine
 	//  * the parent machine we want to create a container on was
 	//  clean but became dirty
+	//  * the placement policy, or the assignments it was checked
+	//  against, changed
 	unit, err := u.st.Unit(u.Name())
 	if err != nil {
 		return err
@@ -1340,6 +1400,11 @@ func (u *Unit) assignToNewMachine(template MachineTemplate, parentId string, con
 	if len(containers) > 0 {
 		return machineNotCleanErr
 	}
+	if len(policyOps) > 0 {
+		if _, err := unit.checkPlacementPolicy(m); err != nil {
+			return err
+		}
+	}
 	return fmt.Errorf("cannot add container within machine: transaction aborted for unknown reason")
 }
 
@@ -1370,6 +1435,9 @@ func (u *Unit) AssignToNewMachineOrContainer() (err error) {
 	if err != nil {
 		return err
 	}
+	if err := u.checkNewMachinePlacementPolicy(); err != nil {
+		return err
+	}
 	if !cons.HasContainer() {
 		return u.AssignToNewMachine()
 	}
@@ -1392,6 +1460,14 @@ func (u *Unit) AssignToNewMachineOrContainer() (err error) {
 	} else if err != nil {
 		return err
 	}
+	policyOps, err := u.checkPlacementPolicy(newMachine(u.st, &host))
+	if isPlacementPolicyError(err) {
+		// The clean machine is in the same availability zone as
+		// another unit of the service, so create a new one.
+		return u.AssignToNewMachine()
+	} else if err != nil {
+		return err
+	}
 	svc, err := u.Service()
 	if err != nil {
 		return err
@@ -1406,10 +1482,11 @@ func (u *Unit) AssignToNewMachineOrContainer() (err error) {
 		Jobs:              []MachineJob{JobHostUnits},
 		RequestedNetworks: requestedNetworks,
 	}
-	err = u.assignToNewMachine(template, host.Id, *cons.Container)
-	if err == machineNotCleanErr {
-		// The clean machine was used before we got a chance to use it so just
-		// stick the unit on a new machine.
+	err = u.assignToNewMachine(template, host.Id, *cons.Container, policyOps...)
+	if err == machineNotCleanErr || isPlacementPolicyError(err) {
+		// The clean machine was used, or another unit of the service
+		// placed in its zone, before we got a chance to use it so
+		// just stick the unit on a new machine.
 		return u.AssignToNewMachine()
 	}
 	return err
@@ -1420,9 +1497,89 @@ func (u *Unit) AssignToNewMachineOrContainer() (err error) {
 // time of unit creation.
 func (u *Unit) AssignToNewMachine() (err error) {
 	defer assignContextf(&err, u, "new machine")
+	return u.assignToNewMachineWithPlacement("")
+}
+
+// AssignToNewMachineInZones assigns the unit to a new machine in one of
+// the given availability zones, with constraints determined as for
+// AssignToNewMachine. The zone is chosen to spread the service's units
+// evenly across the given zones, preferring zones earlier in the list;
+// zones rejected by the provider, such as those that are unavailable,
+// are skipped.
+func (u *Unit) AssignToNewMachineInZones(zones []string) (err error) {
+	defer assignContextf(&err, u, fmt.Sprintf("new machine in zones %s", strings.Join(zones, ", ")))
+	if len(zones) == 0 {
+		return errors.New("no availability zones specified")
+	}
+	zone, err := u.chooseZone(zones)
+	if err != nil {
+		return err
+	}
+	return u.assignToNewMachineWithPlacement("zone=" + zone)
+}
+
+// chooseZone returns the zone, of those given, in which the unit's new
+// machine should be placed.
+func (u *Unit) chooseZone(zones []string) (string, error) {
+	population, err := serviceZones(u.st, u.doc.Service, u.doc.Name)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	policy, err := getPlacementPolicyDoc(u.st, u.doc.Service)
+	if errors.IsNotFound(err) {
+		policy = &placementPolicyDoc{}
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	cons, err := u.Constraints()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	candidates := make([]string, len(zones))
+	copy(candidates, zones)
+	sort.Stable(byZonePopulation{candidates, population})
+	var lastErr error
+	for _, zone := range candidates {
+		if units := population[zone]; len(units) > 0 && AntiAffinity(policy.AntiAffinity) == AntiAffinityZone {
+			lastErr = placementPolicyErrorf(
+				"placement policy forbids units of service %q in the same availability zone, but zone %q hosts unit %q",
+				u.doc.Service, zone, units[0],
+			)
+			continue
+		}
+		if err := u.st.precheckInstance(u.doc.Series, *cons, "zone="+zone); err != nil {
+			unitLogger.Debugf("cannot place unit %q in availability zone %q: %v", u, zone, err)
+			lastErr = err
+			continue
+		}
+		return zone, nil
+	}
+	return "", lastErr
+}
+
+// byZonePopulation sorts zone names by the number of units placed in
+// each.
+type byZonePopulation struct {
+	zones      []string
+	population map[string][]string
+}
+
+func (b byZonePopulation) Len() int      { return len(b.zones) }
+func (b byZonePopulation) Swap(i, j int) { b.zones[i], b.zones[j] = b.zones[j], b.zones[i] }
+func (b byZonePopulation) Less(i, j int) bool {
+	return len(b.population[b.zones[i]]) < len(b.population[b.zones[j]])
+}
+
+// assignToNewMachineWithPlacement implements AssignToNewMachine and
+// AssignToNewMachineInZones, creating the new machine (or, if the unit
+// requires a container, its host) with the given placement directive.
+func (u *Unit) assignToNewMachineWithPlacement(placement string) error {
 	if u.doc.Principal != "" {
 		return fmt.Errorf("unit is a subordinate")
 	}
+	if err := u.checkNewMachinePlacementPolicy(); err != nil {
+		return err
+	}
 	// Get the ops necessary to create a new machine, and the machine doc that
 	// will be added with those operations (which includes the machine id).
 	cons, err := u.Constraints()
@@ -1455,6 +1612,7 @@ func (u *Unit) AssignToNewMachine() (err error) {
 		VolumeAttachments:     storageParams.volumeAttachments,
 		Filesystems:           storageParams.filesystems,
 		FilesystemAttachments: storageParams.filesystemAttachments,
+		Placement:             placement,
 	}
 	return u.assignToNewMachine(template, "", containerType)
 }
@@ -1502,60 +1660,67 @@ func (u *Unit) newMachineStorageParams() (*storageParams, error) {
 		}
 
 		charmStorage := ch.Meta().Storage[storage.StorageName()]
+		owner, _ := storage.Owner()
 
 		switch storage.Kind() {
 		case StorageKindBlock:
 			volumeAttachmentParams := VolumeAttachmentParams{
 				charmStorage.ReadOnly,
 			}
-			if storage.Owner() == u.Tag() {
+			// The storage instance may be owned by the service, in
+			// which case there should be a (shared) volume already,
+			// or it may have been attached to the unit after being
+			// detached from another unit. In either case, we will
+			// just add an attachment to the existing volume.
+			volume, err := u.st.StorageInstanceVolume(storage.StorageTag())
+			if err == nil {
+				volumeAttachments[volume.VolumeTag()] = volumeAttachmentParams
+			} else if errors.IsNotFound(err) && owner == u.Tag() {
 				// The storage instance is owned by the unit, so we'll need
 				// to create a volume.
 				cons := allCons[storage.StorageName()]
 				volumeParams := VolumeParams{
-					storage: storage.StorageTag(),
-					Pool:    cons.Pool,
-					Size:    cons.Size,
+					storage:  storage.StorageTag(),
+					Pool:     cons.Pool,
+					Size:     cons.Size,
+					Snapshot: cons.Snapshot,
 				}
 				volumes = append(volumes, MachineVolumeParams{
 					volumeParams, volumeAttachmentParams,
 				})
 			} else {
-				// The storage instance is owned by the service, so there
-				// should be a (shared) volume already, for which we will
-				// just add an attachment.
-				volume, err := u.st.StorageInstanceVolume(storage.StorageTag())
-				if err != nil {
-					return nil, errors.Annotatef(err, "getting volume for storage %q", storage.Tag().Id())
-				}
-				volumeAttachments[volume.VolumeTag()] = volumeAttachmentParams
+				return nil, errors.Annotatef(err, "getting volume for storage %q", storage.Tag().Id())
 			}
 		case StorageKindFilesystem:
 			filesystemAttachmentParams := FilesystemAttachmentParams{
 				charmStorage.Location,
 				charmStorage.ReadOnly,
 			}
-			if storage.Owner() == u.Tag() {
+			// As with volumes, the storage instance may already have
+			// a filesystem, to which we will just add an attachment.
+			filesystem, err := u.st.StorageInstanceFilesystem(storage.StorageTag())
+			if err == nil {
+				filesystemAttachments[filesystem.FilesystemTag()] = filesystemAttachmentParams
+				if volumeTag, err := filesystem.Volume(); err == nil {
+					volumeAttachments[volumeTag] = VolumeAttachmentParams{}
+				} else if err != ErrNoBackingVolume {
+					return nil, errors.Trace(err)
+				}
+			} else if errors.IsNotFound(err) && owner == u.Tag() {
 				// The storage instance is owned by the unit, so we'll need
 				// to create a filesystem.
 				cons := allCons[storage.StorageName()]
 				filesystemParams := FilesystemParams{
-					storage: storage.StorageTag(),
-					Pool:    cons.Pool,
-					Size:    cons.Size,
+					storage:  storage.StorageTag(),
+					Pool:     cons.Pool,
+					Size:     cons.Size,
+					Snapshot: cons.Snapshot,
 				}
 				filesystems = append(filesystems, MachineFilesystemParams{
 					filesystemParams, filesystemAttachmentParams,
 				})
 			} else {
-				// The storage instance is owned by the service, so there
-				// should be a (shared) filesystem already, for which we will
-				// just add an attachment.
-				filesystem, err := u.st.StorageInstanceFilesystem(storage.StorageTag())
-				if err != nil {
-					return nil, errors.Annotatef(err, "getting filesystem for storage %q", storage.Tag().Id())
-				}
-				filesystemAttachments[filesystem.FilesystemTag()] = filesystemAttachmentParams
+				return nil, errors.Annotatef(err, "getting filesystem for storage %q", storage.Tag().Id())
 			}
 		default:
 			return nil, errors.Errorf("invalid storage kind %v", storage.Kind())
@@ -1791,7 +1956,7 @@ func (u *Unit) assignToCleanMaybeEmptyMachine(requireEmpty bool) (m *Machine, er
 		if err == nil {
 			return m, nil
 		}
-		if err != inUseErr && err != machineNotAliveErr {
+		if err != inUseErr && err != machineNotAliveErr && !isPlacementPolicyError(err) {
 			assignContextf(&err, u, context)
 			return nil, err
 		}
@@ -34,6 +34,11 @@ func (s *instanceSuite) TestAvailabilityZone(c *gc.C) {
 }
 
 func (s *instanceSuite) TestAvailabilityZoneUnsupported(c *gc.C) {
+	// The dummy environ supports zones, so hide them.
+	env := struct{ environs.Environ }{s.Environ}
+	s.PatchValue(utils.PatchedGetEnvironment, func(st *state.State) (environs.Environ, error) {
+		return env, nil
+	})
 	_, err := utils.AvailabilityZone(s.State, "id-1")
 	c.Check(err, jc.Satisfies, errors.IsNotSupported)
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
@@ -43,6 +44,16 @@ type Volume interface {
 	// if it has not already been provisioned. Params returns true if the
 	// returned parameters are usable for provisioning, otherwise false.
 	Params() (VolumeParams, bool)
+
+	// PendingResize returns the size, in MiB, that the volume has been
+	// requested to grow to, if the resize has not yet been carried out.
+	// PendingResize returns true if there is a pending resize, otherwise
+	// false.
+	PendingResize() (uint64, bool)
+
+	// ResizeFailure returns the reason that the most recent request
+	// to resize the volume failed, or the empty string if it did not.
+	ResizeFailure() string
 }
 
 // VolumeAttachment describes an attachment of a volume to a machine.
@@ -86,6 +97,14 @@ type volumeDoc struct {
 	StorageId string        `bson:"storageid,omitempty"`
 	Info      *VolumeInfo   `bson:"info,omitempty"`
 	Params    *VolumeParams `bson:"params,omitempty"`
+
+	// PendingSize, if non-zero, is the size in MiB that the
+	// volume has been requested to grow to.
+	PendingSize uint64 `bson:"pendingsize,omitempty"`
+
+	// ResizeFailure, if non-empty, is the reason that the most
+	// recent request to resize the volume failed.
+	ResizeFailure string `bson:"resizefailure,omitempty"`
 }
 
 // volumeAttachmentDoc records information about a volume attachment.
@@ -108,6 +127,10 @@ type VolumeParams struct {
 
 	Pool string `bson:"pool"`
 	Size uint64 `bson:"size"`
+
+	// Snapshot, if non-empty, is the ID of the snapshot from
+	// which the volume is to be created.
+	Snapshot string `bson:"snapshot,omitempty"`
 }
 
 // VolumeInfo describes information about a volume.
@@ -171,6 +194,19 @@ func (v *volume) Params() (VolumeParams, bool) {
 	return *v.doc.Params, true
 }
 
+// PendingResize is required to implement Volume.
+func (v *volume) PendingResize() (uint64, bool) {
+	if v.doc.PendingSize == 0 {
+		return 0, false
+	}
+	return v.doc.PendingSize, true
+}
+
+// ResizeFailure is required to implement Volume.
+func (v *volume) ResizeFailure() string {
+	return v.doc.ResizeFailure
+}
+
 // Volume is required to implement VolumeAttachment.
 func (v *volumeAttachment) Volume() names.VolumeTag {
 	return names.NewVolumeTag(v.doc.Volume)
@@ -361,7 +397,11 @@ func (st *State) volumeParamsWithDefaults(params VolumeParams) (VolumeParams, er
 	if err != nil {
 		return VolumeParams{}, errors.Trace(err)
 	}
-	poolName, err := defaultStoragePool(envConfig, storage.StorageKindBlock)
+	serviceDefaults, err := storageServiceDefaults(st, params.storage)
+	if err != nil {
+		return VolumeParams{}, errors.Annotate(err, "getting service storage defaults")
+	}
+	poolName, err := defaultStoragePool(envConfig, serviceDefaults, storage.StorageKindBlock)
 	if err != nil {
 		return VolumeParams{}, errors.Annotate(err, "getting default block storage pool")
 	}
@@ -378,6 +418,11 @@ func (st *State) validateVolumeParams(params VolumeParams, machineId string) (ma
 	if params.Size == 0 {
 		return "", errors.New("invalid size 0")
 	}
+	if params.Snapshot != "" {
+		if err := validateVolumeSnapshot(st, params, machineId); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
 	return machineId, nil
 }
 
@@ -517,7 +562,13 @@ func (st *State) SetVolumeInfo(tag names.VolumeTag, info VolumeInfo) (err error)
 				return nil, err
 			}
 		}
-		return setVolumeInfoOps(tag, info, unsetParams), nil
+		// Once the volume has grown to the requested
+		// size, the pending resize is complete.
+		var clearResize bool
+		if size, ok := v.PendingResize(); ok && info.Size >= size {
+			clearResize = true
+		}
+		return setVolumeInfoOps(tag, info, unsetParams, clearResize), nil
 	}
 	return st.run(buildTxn)
 }
@@ -538,15 +589,22 @@ func validateVolumeInfoChange(newInfo, oldInfo VolumeInfo) error {
 	return nil
 }
 
-func setVolumeInfoOps(tag names.VolumeTag, info VolumeInfo, unsetParams bool) []txn.Op {
+func setVolumeInfoOps(tag names.VolumeTag, info VolumeInfo, unsetParams, clearResize bool) []txn.Op {
 	asserts := isAliveDoc
 	update := bson.D{
 		{"$set", bson.D{{"info", &info}}},
 	}
+	var unset bson.D
 	if unsetParams {
 		asserts = append(asserts, bson.DocElem{"info", bson.D{{"$exists", false}}})
 		asserts = append(asserts, bson.DocElem{"params", bson.D{{"$exists", true}}})
-		update = append(update, bson.DocElem{"$unset", bson.D{{"params", nil}}})
+		unset = append(unset, bson.DocElem{"params", nil})
+	}
+	if clearResize {
+		unset = append(unset, bson.DocElem{"pendingsize", nil})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.DocElem{"$unset", unset})
 	}
 	return []txn.Op{{
 		C:      volumesC,
@@ -556,6 +614,122 @@ func setVolumeInfoOps(tag names.VolumeTag, info VolumeInfo, unsetParams bool) []
 	}}
 }
 
+// ResizeVolume requests that the specified volume be grown to the
+// given size, in MiB. The volume must be alive and provisioned, and
+// the requested size must be larger than the volume's current size.
+// The resize is carried out by the storage provisioner responsible
+// for the volume.
+func (st *State) ResizeVolume(tag names.VolumeTag, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot resize volume %q", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		v, err := st.Volume(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.Life() != Alive {
+			return nil, errors.New("volume is not alive")
+		}
+		info, err := v.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if size <= info.Size {
+			return nil, errors.Errorf(
+				"requested size %dMiB is not larger than current size %dMiB",
+				size, info.Size,
+			)
+		}
+		if pendingSize, ok := v.PendingResize(); ok && pendingSize == size {
+			return nil, jujutxn.ErrNoOperations
+		}
+		asserts := append(isAliveDoc, bson.DocElem{"info.size", info.Size})
+		return []txn.Op{{
+			C:      volumesC,
+			Id:     tag.Id(),
+			Assert: asserts,
+			Update: bson.D{
+				{"$set", bson.D{{"pendingsize", size}}},
+				{"$unset", bson.D{{"resizefailure", nil}}},
+			},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// FailVolumeResize clears the pending request to resize the specified
+// volume, recording the reason that it could not be carried out. The
+// failure is cleared when the volume is next requested to be resized.
+func (st *State) FailVolumeResize(tag names.VolumeTag, reason string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot fail resize of volume %q", tag.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		v, err := st.Volume(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		size, ok := v.PendingResize()
+		if !ok {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      volumesC,
+			Id:     tag.Id(),
+			Assert: bson.D{{"pendingsize", size}},
+			Update: bson.D{
+				{"$set", bson.D{{"resizefailure", reason}}},
+				{"$unset", bson.D{{"pendingsize", nil}}},
+			},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
+// destroyVolumeAttachmentOps returns txn.Ops to destroy the attachment
+// of the specified volume to the specified machine, so that the volume
+// will be detached by the storage provisioner. No operations are
+// returned if the attachment does not exist, or is not alive.
+func destroyVolumeAttachmentOps(st *State, machine names.MachineTag, volume names.VolumeTag) ([]txn.Op, error) {
+	att, err := st.VolumeAttachment(machine, volume)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if att.Life() != Alive {
+		return nil, nil
+	}
+	return []txn.Op{{
+		C:      volumeAttachmentsC,
+		Id:     volumeAttachmentId(machine.Id(), volume.Id()),
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+	}}, nil
+}
+
+// RemoveVolumeAttachment removes the attachment of the specified volume
+// to the specified machine from state. The attachment must not be alive;
+// the storage provisioner calls this once it has detached the volume.
+func (st *State) RemoveVolumeAttachment(machine names.MachineTag, volume names.VolumeTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove attachment of volume %q to machine %q", volume.Id(), machine.Id())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		att, err := st.VolumeAttachment(machine, volume)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if att.Life() == Alive {
+			return nil, errors.New("volume attachment is alive")
+		}
+		return []txn.Op{{
+			C:      volumeAttachmentsC,
+			Id:     volumeAttachmentId(machine.Id(), volume.Id()),
+			Assert: bson.D{{"life", bson.D{{"$ne", Alive}}}},
+			Remove: true,
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
 // AllVolumes returns all Volumes scoped to the environment.
 func (st *State) AllVolumes() ([]Volume, error) {
 	coll, cleanup := st.getCollection(volumesC)
@@ -86,12 +86,10 @@ func (s *VolumeStateSuite) TestAddServiceNoUserDefaultPool(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cons, jc.DeepEquals, map[string]state.StorageConstraints{
 		"data": state.StorageConstraints{
-			Pool:  "loop",
 			Size:  1024,
 			Count: 1,
 		},
 		"allecto": state.StorageConstraints{
-			Pool:  "loop",
 			Size:  1024,
 			Count: 0,
 		},
@@ -117,16 +115,25 @@ func (s *VolumeStateSuite) TestAddServiceDefaultPool(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cons, jc.DeepEquals, map[string]state.StorageConstraints{
 		"data": state.StorageConstraints{
-			Pool:  "default-block",
 			Size:  1024,
 			Count: 1,
 		},
 		"allecto": state.StorageConstraints{
-			Pool:  "default-block",
 			Size:  1024,
 			Count: 0,
 		},
 	})
+
+	// The default pool is resolved when the volume is created.
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignNew)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err := s.State.StorageInstanceVolume(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	volumeParams, ok := volume.Params()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(volumeParams.Pool, gc.Equals, "default-block")
 }
 
 func (s *VolumeStateSuite) TestSetVolumeInfo(c *gc.C) {
@@ -238,9 +245,114 @@ func (s *VolumeStateSuite) TestWatchVolumeAttachment(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	wc.AssertOneChange()
 
-	// volume attachment will NOT react to volume changes
+	// volume attachment will react to volume changes, since
+	// the volume's size and serial are exposed to the unit.
 	err = s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{VolumeId: "vol-123"})
 	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *VolumeStateSuite) TestResizeVolume(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeTag := volume.VolumeTag()
+
+	err = s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, gc.ErrorMatches, `cannot resize volume "0/0": volume "0/0" not provisioned`)
+
+	err = s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{VolumeId: "vol-123", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ResizeVolume(volumeTag, 1024)
+	c.Assert(err, gc.ErrorMatches, `cannot resize volume "0/0": requested size 1024MiB is not larger than current size 1024MiB`)
+
+	err = s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err = s.State.Volume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	size, ok := volume.PendingResize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, uint64(2048))
+
+	// Setting the info with the new size completes the resize.
+	err = s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{
+		VolumeId: "vol-123", Size: 2048, Pool: "loop-pool",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err = s.State.Volume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = volume.PendingResize()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *VolumeStateSuite) TestFailVolumeResize(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err := s.State.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeTag := volume.VolumeTag()
+	err = s.State.SetVolumeInfo(volumeTag, state.VolumeInfo{VolumeId: "vol-123", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// There is no pending resize, so there is nothing to fail.
+	err = s.State.FailVolumeResize(volumeTag, "no can do")
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err = s.State.Volume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volume.ResizeFailure(), gc.Equals, "")
+
+	err = s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.FailVolumeResize(volumeTag, "no can do")
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err = s.State.Volume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := volume.PendingResize()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(volume.ResizeFailure(), gc.Equals, "no can do")
+
+	// Requesting another resize clears the failure.
+	err = s.State.ResizeVolume(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err = s.State.Volume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	size, ok := volume.PendingResize()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(size, gc.Equals, uint64(2048))
+	c.Assert(volume.ResizeFailure(), gc.Equals, "")
+}
+
+func (s *VolumeStateSuite) TestWatchMachineVolumeResizes(c *gc.C) {
+	service := s.setupMixedScopeStorageService(c, "block")
+	u, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.WatchMachineVolumeResizes(names.NewMachineTag("0"))
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	for _, id := range []string{"0", "0/1"} {
+		tag := names.NewVolumeTag(id)
+		err = s.State.SetVolumeInfo(tag, state.VolumeInfo{VolumeId: id, Size: 1024})
+		c.Assert(err, jc.ErrorIsNil)
+		err = s.State.ResizeVolume(tag, 2048)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	// only the machine-scoped volume is reported.
+	wc.AssertChangeInSingleEvent("0/1")
+	wc.AssertNoChange()
+
+	err = s.State.SetVolumeInfo(names.NewVolumeTag("0/1"), state.VolumeInfo{
+		VolumeId: "0/1", Size: 2048, Pool: "machinescoped",
+	})
+	c.Assert(err, jc.ErrorIsNil)
 	wc.AssertNoChange()
 }
 
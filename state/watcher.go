@@ -212,6 +212,19 @@ func (st *State) WatchMachineFilesystems(m names.MachineTag) StringsWatcher {
 	return st.watchMachineStorage(m, filesystemsC)
 }
 
+// WatchEnvironSnapshots returns a StringsWatcher that notifies of changes
+// to the lifecycles of all snapshots of environment-scoped volumes.
+func (st *State) WatchEnvironSnapshots() StringsWatcher {
+	return st.watchEnvironMachineStorage(snapshotsC)
+}
+
+// WatchMachineSnapshots returns a StringsWatcher that notifies of changes
+// to the lifecycles of all snapshots of volumes scoped to the specified
+// machine.
+func (st *State) WatchMachineSnapshots(m names.MachineTag) StringsWatcher {
+	return st.watchMachineStorage(m, snapshotsC)
+}
+
 func (st *State) watchMachineStorage(m names.MachineTag, collection string) StringsWatcher {
 	pattern := fmt.Sprintf("^%s/%s$", st.docID(m.Id()), names.NumberSnippet)
 	members := bson.D{{"_id", bson.D{{"$regex", pattern}}}}
@@ -226,6 +239,23 @@ func (st *State) watchMachineStorage(m names.MachineTag, collection string) Stri
 	return newLifecycleWatcher(st, collection, members, filter, nil)
 }
 
+// WatchEnvironVolumeResizes returns a StringsWatcher that notifies of
+// requests to resize environment-scoped volumes.
+func (st *State) WatchEnvironVolumeResizes() StringsWatcher {
+	return newVolumeResizeWatcher(st, func(id string) bool {
+		return !strings.Contains(id, "/")
+	})
+}
+
+// WatchMachineVolumeResizes returns a StringsWatcher that notifies of
+// requests to resize volumes scoped to the specified machine.
+func (st *State) WatchMachineVolumeResizes(m names.MachineTag) StringsWatcher {
+	prefix := m.Id() + "/"
+	return newVolumeResizeWatcher(st, func(id string) bool {
+		return strings.HasPrefix(id, prefix)
+	})
+}
+
 // WatchEnvironVolumeAttachments returns a StringsWatcher that notifies of
 // changes to the lifecycles of all volume attachments related to environ-
 // scoped volumes.
@@ -650,6 +680,116 @@ func (w *minUnitsWatcher) Changes() <-chan []string {
 	return w.out
 }
 
+// volumeResizeWatcher notifies about requests to resize volumes. The
+// first event returned by the watcher is the set of volume names with
+// pending resizes; subsequent events are generated when the requested
+// size of a volume changes.
+type volumeResizeWatcher struct {
+	commonWatcher
+	filter func(string) bool
+	known  map[string]uint64
+	out    chan []string
+}
+
+var _ Watcher = (*volumeResizeWatcher)(nil)
+
+func newVolumeResizeWatcher(st *State, filter func(string) bool) StringsWatcher {
+	w := &volumeResizeWatcher{
+		commonWatcher: commonWatcher{st: st},
+		filter:        filter,
+		known:         make(map[string]uint64),
+		out:           make(chan []string),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+func (w *volumeResizeWatcher) initial() (set.Strings, error) {
+	volumeNames := make(set.Strings)
+	var doc volumeDoc
+	volumes, closer := w.st.getCollection(volumesC)
+	defer closer()
+
+	iter := volumes.Find(bson.D{{"pendingsize", bson.D{{"$gt", 0}}}}).Iter()
+	for iter.Next(&doc) {
+		if !w.filter(doc.Name) {
+			continue
+		}
+		w.known[doc.Name] = doc.PendingSize
+		volumeNames.Add(doc.Name)
+	}
+	return volumeNames, iter.Close()
+}
+
+func (w *volumeResizeWatcher) merge(volumeNames set.Strings, change watcher.Change) error {
+	volumeName := w.st.localID(change.Id.(string))
+	if !w.filter(volumeName) {
+		return nil
+	}
+	if change.Revno == -1 {
+		delete(w.known, volumeName)
+		volumeNames.Remove(volumeName)
+		return nil
+	}
+	var doc volumeDoc
+	volumes, closer := w.st.getCollection(volumesC)
+	defer closer()
+	if err := volumes.FindId(change.Id).One(&doc); err == mgo.ErrNotFound {
+		delete(w.known, volumeName)
+		volumeNames.Remove(volumeName)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	pendingSize, known := w.known[volumeName]
+	if doc.PendingSize == 0 {
+		delete(w.known, volumeName)
+		return nil
+	}
+	w.known[volumeName] = doc.PendingSize
+	if !known || doc.PendingSize != pendingSize {
+		volumeNames.Add(volumeName)
+	}
+	return nil
+}
+
+func (w *volumeResizeWatcher) loop() (err error) {
+	ch := make(chan watcher.Change)
+	w.st.watcher.WatchCollectionWithFilter(volumesC, ch, w.st.isForStateEnv)
+	defer w.st.watcher.UnwatchCollection(volumesC, ch)
+	volumeNames, err := w.initial()
+	if err != nil {
+		return err
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case change := <-ch:
+			if err = w.merge(volumeNames, change); err != nil {
+				return err
+			}
+			if !volumeNames.IsEmpty() {
+				out = w.out
+			}
+		case out <- volumeNames.Values():
+			out = nil
+			volumeNames = set.NewStrings()
+		}
+	}
+}
+
+func (w *volumeResizeWatcher) Changes() <-chan []string {
+	return w.out
+}
+
 func (st *State) isForStateEnv(id interface{}) bool {
 	_, err := st.strictLocalID(id.(string))
 	return err == nil
@@ -1419,10 +1559,14 @@ func (st *State) WatchAPIHostPorts() NotifyWatcher {
 }
 
 // WatchVolumeAttachment returns a watcher for observing changes
-// to a volume attachment.
+// to a volume attachment, or to the volume that it attaches (for
+// example, when the volume is resized).
 func (st *State) WatchVolumeAttachment(m names.MachineTag, v names.VolumeTag) NotifyWatcher {
 	id := volumeAttachmentId(m.Id(), v.Id())
-	return newEntityWatcher(st, volumeAttachmentsC, st.docID(id))
+	return newDocWatcher(st, []docKey{
+		{volumeAttachmentsC, st.docID(id)},
+		{volumesC, st.docID(v.Id())},
+	})
 }
 
 // WatchFilesystemAttachment returns a watcher for observing changes
@@ -0,0 +1,308 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// WebhookParams defines a webhook to be notified of status
+// transitions in the environment.
+type WebhookParams struct {
+	// URL is the http or https URL that notifications are POSTed to.
+	URL string
+
+	// EntityPattern, if not empty, is a shell pattern (as understood
+	// by path.Match) restricting notifications to entities whose tags
+	// match it; for example "unit-mysql-*" or "machine-*".
+	EntityPattern string
+
+	// Statuses, if not empty, restricts notifications to transitions
+	// into one of the given statuses.
+	Statuses []Status
+}
+
+// Validate returns an error if the webhook parameters are not valid.
+func (p WebhookParams) Validate() error {
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return errors.NotValidf("webhook URL %q", p.URL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return errors.NotValidf("webhook URL %q", p.URL)
+	}
+	if _, err := path.Match(p.EntityPattern, ""); err != nil {
+		return errors.NotValidf("entity pattern %q", p.EntityPattern)
+	}
+	return nil
+}
+
+// Webhook represents a URL registered to be notified of status
+// transitions in the environment.
+type Webhook struct {
+	st  *State
+	doc webhookDoc
+}
+
+type webhookDoc struct {
+	DocID         string    `bson:"_id"`
+	Id            string    `bson:"id"`
+	EnvUUID       string    `bson:"env-uuid"`
+	URL           string    `bson:"url"`
+	EntityPattern string    `bson:"entity-pattern,omitempty"`
+	Statuses      []Status  `bson:"statuses,omitempty"`
+	Created       time.Time `bson:"created"`
+}
+
+// Id returns the webhook's id, unique within the environment.
+func (w *Webhook) Id() string {
+	return w.doc.Id
+}
+
+// URL returns the URL that notifications are POSTed to.
+func (w *Webhook) URL() string {
+	return w.doc.URL
+}
+
+// EntityPattern returns the pattern that entity tags must match
+// for notifications to be sent.
+func (w *Webhook) EntityPattern() string {
+	return w.doc.EntityPattern
+}
+
+// Statuses returns the statuses that trigger notifications; if
+// empty, all transitions do.
+func (w *Webhook) Statuses() []Status {
+	return w.doc.Statuses
+}
+
+// Created returns when the webhook was registered.
+func (w *Webhook) Created() time.Time {
+	return w.doc.Created
+}
+
+// Matches reports whether a transition of the entity with the given
+// tag into the given status should be notified to the webhook.
+func (w *Webhook) Matches(entityTag string, status Status) bool {
+	if w.doc.EntityPattern != "" {
+		if ok, _ := path.Match(w.doc.EntityPattern, entityTag); !ok {
+			return false
+		}
+	}
+	if len(w.doc.Statuses) == 0 {
+		return true
+	}
+	for _, s := range w.doc.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// AddWebhook registers a new webhook in the environment.
+func (st *State) AddWebhook(p WebhookParams) (*Webhook, error) {
+	if err := p.Validate(); err != nil {
+		return nil, errors.Annotate(err, "cannot add webhook")
+	}
+	seq, err := st.sequence("webhook")
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot add webhook")
+	}
+	id := fmt.Sprint(seq)
+	doc := webhookDoc{
+		DocID:         st.docID(id),
+		Id:            id,
+		EnvUUID:       st.EnvironUUID(),
+		URL:           p.URL,
+		EntityPattern: p.EntityPattern,
+		Statuses:      p.Statuses,
+		Created:       nowToTheSecond(),
+	}
+	ops := []txn.Op{{
+		C:      webhooksC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return nil, errors.Annotate(err, "cannot add webhook")
+	}
+	return &Webhook{st: st, doc: doc}, nil
+}
+
+// Webhook returns the webhook with the given id.
+func (st *State) Webhook(id string) (*Webhook, error) {
+	webhooks, closer := st.getCollection(webhooksC)
+	defer closer()
+
+	var doc webhookDoc
+	err := webhooks.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("webhook %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get webhook %q", id)
+	}
+	return &Webhook{st: st, doc: doc}, nil
+}
+
+// AllWebhooks returns all webhooks registered in the environment.
+func (st *State) AllWebhooks() ([]*Webhook, error) {
+	webhooks, closer := st.getCollection(webhooksC)
+	defer closer()
+
+	var docs []webhookDoc
+	if err := webhooks.Find(nil).Sort("created").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get webhooks")
+	}
+	result := make([]*Webhook, len(docs))
+	for i, doc := range docs {
+		result[i] = &Webhook{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// RemoveWebhook removes the webhook with the given id, along with
+// its delivery log.
+func (st *State) RemoveWebhook(id string) error {
+	ops := []txn.Op{{
+		C:      webhooksC,
+		Id:     st.docID(id),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("webhook %q", id)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot remove webhook %q", id)
+	}
+	deliveries, closer := st.getCollection(webhookDeliveriesC)
+	defer closer()
+	if _, err := deliveries.RemoveAll(bson.D{{"webhook-id", id}}); err != nil {
+		return errors.Annotatef(err, "cannot remove deliveries for webhook %q", id)
+	}
+	return nil
+}
+
+// WebhookDelivery records an attempt to notify a webhook of a
+// status transition.
+type WebhookDelivery struct {
+	// Entity is the tag of the entity whose status changed.
+	Entity string
+
+	// Status is the status the entity entered.
+	Status Status
+
+	// Time is when delivery finished, whether or not it succeeded.
+	Time time.Time
+
+	// Attempts is the number of POST requests that were made.
+	Attempts int
+
+	// ResponseCode is the HTTP status code of the last response,
+	// or zero if no response was received.
+	ResponseCode int
+
+	// Error describes why delivery failed, and is empty if it
+	// succeeded.
+	Error string
+}
+
+type webhookDeliveryDoc struct {
+	Id           int       `bson:"_id"`
+	EnvUUID      string    `bson:"env-uuid"`
+	WebhookId    string    `bson:"webhook-id"`
+	Entity       string    `bson:"entity"`
+	Status       Status    `bson:"status"`
+	Time         time.Time `bson:"time"`
+	Attempts     int       `bson:"attempts"`
+	ResponseCode int       `bson:"response-code"`
+	Error        string    `bson:"error,omitempty"`
+}
+
+// maxWebhookDeliveries is the number of deliveries retained in each
+// webhook's log; older deliveries are pruned as new ones are recorded.
+var maxWebhookDeliveries = 100
+
+// RecordDelivery adds the given delivery to the webhook's log,
+// pruning the oldest deliveries if the log is full.
+func (w *Webhook) RecordDelivery(d WebhookDelivery) error {
+	id, err := w.st.sequence("webhookdelivery")
+	if err != nil {
+		return errors.Annotatef(err, "cannot record delivery for webhook %q", w.doc.Id)
+	}
+	ops := []txn.Op{{
+		C:      webhookDeliveriesC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: &webhookDeliveryDoc{
+			EnvUUID:      w.st.EnvironUUID(),
+			WebhookId:    w.doc.Id,
+			Entity:       d.Entity,
+			Status:       d.Status,
+			Time:         d.Time,
+			Attempts:     d.Attempts,
+			ResponseCode: d.ResponseCode,
+			Error:        d.Error,
+		},
+	}}
+	if err := w.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot record delivery for webhook %q", w.doc.Id)
+	}
+	return errors.Annotatef(w.pruneDeliveries(maxWebhookDeliveries), "cannot prune deliveries for webhook %q", w.doc.Id)
+}
+
+// pruneDeliveries removes all but the newest <keep> deliveries
+// from the webhook's log.
+func (w *Webhook) pruneDeliveries(keep int) error {
+	deliveries, closer := w.st.getCollection(webhookDeliveriesC)
+	defer closer()
+
+	var oldest struct {
+		Id int `bson:"_id"`
+	}
+	sel := bson.D{{"webhook-id", w.doc.Id}}
+	err := deliveries.Find(sel).Sort("-_id").Skip(keep).Select(bson.D{{"_id", 1}}).One(&oldest)
+	if err == mgo.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = deliveries.RemoveAll(append(sel, bson.DocElem{"_id", bson.D{{"$lte", oldest.Id}}}))
+	return errors.Trace(err)
+}
+
+// Deliveries returns at most <size> of the most recent deliveries
+// to the webhook, newest first.
+func (w *Webhook) Deliveries(size int) ([]WebhookDelivery, error) {
+	deliveries, closer := w.st.getCollection(webhookDeliveriesC)
+	defer closer()
+
+	var docs []webhookDeliveryDoc
+	err := deliveries.Find(bson.D{{"webhook-id", w.doc.Id}}).Sort("-_id").Limit(size).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get deliveries for webhook %q", w.doc.Id)
+	}
+	result := make([]WebhookDelivery, len(docs))
+	for i, doc := range docs {
+		result[i] = WebhookDelivery{
+			Entity:       doc.Entity,
+			Status:       doc.Status,
+			Time:         doc.Time.UTC(),
+			Attempts:     doc.Attempts,
+			ResponseCode: doc.ResponseCode,
+			Error:        doc.Error,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type WebhooksSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&WebhooksSuite{})
+
+func (s *WebhooksSuite) TestAddWebhook(c *gc.C) {
+	webhook, err := s.State.AddWebhook(state.WebhookParams{
+		URL:           "https://example.com/hook",
+		EntityPattern: "unit-mysql-*",
+		Statuses:      []state.Status{state.StatusError, state.StatusLost},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhook.Id(), gc.Equals, "0")
+	c.Assert(webhook.URL(), gc.Equals, "https://example.com/hook")
+	c.Assert(webhook.EntityPattern(), gc.Equals, "unit-mysql-*")
+	c.Assert(webhook.Statuses(), jc.DeepEquals, []state.Status{state.StatusError, state.StatusLost})
+
+	other, err := s.State.Webhook(webhook.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(other.URL(), gc.Equals, webhook.URL())
+
+	all, err := s.State.AllWebhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+	c.Assert(all[0].Id(), gc.Equals, webhook.Id())
+}
+
+func (s *WebhooksSuite) TestAddWebhookInvalid(c *gc.C) {
+	for i, test := range []struct {
+		params state.WebhookParams
+		err    string
+	}{{
+		params: state.WebhookParams{URL: "ftp://example.com"},
+		err:    `cannot add webhook: webhook URL "ftp://example.com" not valid`,
+	}, {
+		params: state.WebhookParams{URL: "http://"},
+		err:    `cannot add webhook: webhook URL "http://" not valid`,
+	}, {
+		params: state.WebhookParams{URL: "http://example.com", EntityPattern: "unit-["},
+		err:    `cannot add webhook: entity pattern "unit-\[" not valid`,
+	}} {
+		c.Logf("test %d: %+v", i, test.params)
+		_, err := s.State.AddWebhook(test.params)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *WebhooksSuite) TestMatches(c *gc.C) {
+	webhook, err := s.State.AddWebhook(state.WebhookParams{
+		URL:           "http://example.com",
+		EntityPattern: "unit-mysql-*",
+		Statuses:      []state.Status{state.StatusError},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(webhook.Matches("unit-mysql-0", state.StatusError), jc.IsTrue)
+	c.Check(webhook.Matches("unit-mysql-0", state.StatusIdle), jc.IsFalse)
+	c.Check(webhook.Matches("unit-wordpress-0", state.StatusError), jc.IsFalse)
+
+	all, err := s.State.AddWebhook(state.WebhookParams{URL: "http://example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(all.Matches("machine-0", state.StatusStarted), jc.IsTrue)
+}
+
+func (s *WebhooksSuite) TestRemoveWebhook(c *gc.C) {
+	webhook, err := s.State.AddWebhook(state.WebhookParams{URL: "http://example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = webhook.RecordDelivery(state.WebhookDelivery{Entity: "machine-0"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveWebhook(webhook.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Webhook(webhook.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	deliveries, err := webhook.Deliveries(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deliveries, gc.HasLen, 0)
+
+	err = s.State.RemoveWebhook(webhook.Id())
+	c.Assert(err, gc.ErrorMatches, `webhook "0" not found`)
+}
+
+func (s *WebhooksSuite) TestDeliveries(c *gc.C) {
+	webhook, err := s.State.AddWebhook(state.WebhookParams{URL: "http://example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	now := time.Now().Round(time.Second).UTC()
+	first := state.WebhookDelivery{
+		Entity:   "unit-mysql-0",
+		Status:   state.StatusError,
+		Time:     now,
+		Attempts: 5,
+		Error:    "connection refused",
+	}
+	second := state.WebhookDelivery{
+		Entity:       "machine-1",
+		Status:       state.StatusDown,
+		Time:         now.Add(time.Second),
+		Attempts:     1,
+		ResponseCode: 200,
+	}
+	c.Assert(webhook.RecordDelivery(first), jc.ErrorIsNil)
+	c.Assert(webhook.RecordDelivery(second), jc.ErrorIsNil)
+
+	deliveries, err := webhook.Deliveries(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deliveries, jc.DeepEquals, []state.WebhookDelivery{second, first})
+
+	deliveries, err = webhook.Deliveries(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deliveries, jc.DeepEquals, []state.WebhookDelivery{second})
+}
+
+func (s *WebhooksSuite) TestRecordDeliveryPrunesLog(c *gc.C) {
+	s.PatchValue(state.MaxWebhookDeliveries, 3)
+	webhook, err := s.State.AddWebhook(state.WebhookParams{URL: "http://example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := s.State.AddWebhook(state.WebhookParams{URL: "http://example.com/other"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.RecordDelivery(state.WebhookDelivery{Entity: "machine-0"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i := 0; i < 5; i++ {
+		err := webhook.RecordDelivery(state.WebhookDelivery{Attempts: i})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	deliveries, err := webhook.Deliveries(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deliveries, gc.HasLen, 3)
+	for i, d := range deliveries {
+		c.Assert(d.Attempts, gc.Equals, 4-i)
+	}
+
+	// Other webhooks' logs are unaffected.
+	deliveries, err = other.Deliveries(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deliveries, gc.HasLen, 1)
+}
@@ -41,4 +41,8 @@ type BlockDevice struct {
 
 	// MountPoint is the path at which the block devices is mounted.
 	MountPoint string `yaml:"mountpoint,omitempty"`
+
+	// Partitioned indicates that the block device has partitions.
+	// Partitions are not reported as block devices themselves.
+	Partitioned bool `yaml:"partitioned,omitempty"`
 }
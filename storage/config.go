@@ -20,6 +20,14 @@ const (
 	// constructed.
 	ConfigStorageDir = "storage-dir"
 
+	// ConfigBlockDevices holds the block devices present on the
+	// machine of a machine-scoped storage source, as reported by
+	// the machine's disk manager. The value is a []BlockDevice.
+	//
+	// ConfigBlockDevices is set by the storage provisioner when
+	// creating volumes, so should not be relied upon otherwise.
+	ConfigBlockDevices = "block-devices"
+
 	// Persistent is true if storage survives the lifecycle of the
 	// machine to which it is attached.
 	Persistent = "persistent"
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/names"
 	"github.com/juju/utils"
 )
 
@@ -27,6 +28,10 @@ type Constraints struct {
 
 	// Count is the number of instances of the storage to create.
 	Count uint64
+
+	// Snapshot is the ID of the volume snapshot from which to
+	// create the storage, or "" if the storage should be empty.
+	Snapshot string
 }
 
 var (
@@ -35,6 +40,10 @@ var (
 	sizeRE  = regexp.MustCompile("^-?[0-9]+(?:\\.[0-9]+)?[MGTPEZY](?:i?B)?$")
 )
 
+// snapshotPrefix is the prefix of the storage constraints field that
+// identifies a snapshot from which to create storage.
+const snapshotPrefix = "snap:"
+
 // ParseConstraints parses the specified string and creates a
 // Constraints structure.
 //
@@ -53,6 +62,10 @@ var (
 //    create. SIZE is a floating point number and multiplier from
 //    the set (M, G, T, P, E, Z, Y), which are all treated as
 //    powers of 1024.
+//
+// Additionally, "snap:SNAPSHOT" may be specified to create the storage
+// from the volume snapshot with the ID SNAPSHOT. If POOL or SIZE are
+// unspecified, they default to those of the snapshotted volume.
 func ParseConstraints(s string) (Constraints, error) {
 	var cons Constraints
 	fields := strings.Split(s, ",")
@@ -60,6 +73,13 @@ func ParseConstraints(s string) (Constraints, error) {
 		if field == "" {
 			continue
 		}
+		if snapshot, ok, err := parseSnapshot(field); ok {
+			if err != nil {
+				return cons, errors.Annotate(err, "cannot parse snapshot")
+			}
+			cons.Snapshot = snapshot
+			continue
+		}
 		if IsValidPoolName(field) {
 			if cons.Pool != "" {
 				logger.Warningf("pool name is already set to %q, ignoring %q", cons.Pool, field)
@@ -84,7 +104,7 @@ func ParseConstraints(s string) (Constraints, error) {
 		}
 		logger.Warningf("ignoring unknown storage constraint %q", field)
 	}
-	if cons.Count == 0 && cons.Size == 0 && cons.Pool == "" {
+	if cons.Count == 0 && cons.Size == 0 && cons.Pool == "" && cons.Snapshot == "" {
 		return Constraints{}, errors.New("storage constraints require at least one field to be specified")
 	}
 	if cons.Count == 0 {
@@ -118,6 +138,19 @@ bad:
 	return 0, true, errors.Errorf("count must be greater than zero, got %q", s)
 }
 
+func parseSnapshot(s string) (string, bool, error) {
+	if !strings.HasPrefix(s, snapshotPrefix) {
+		return "", false, nil
+	}
+	id := s[len(snapshotPrefix):]
+	// Snapshot IDs have the same format as the IDs
+	// of the volumes they are taken of.
+	if !names.IsValidVolume(id) {
+		return "", true, errors.NotValidf("snapshot ID %q", id)
+	}
+	return id, true, nil
+}
+
 func parseSize(s string) (uint64, bool, error) {
 	if !sizeRE.MatchString(s) {
 		return 0, false, nil
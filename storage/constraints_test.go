@@ -63,6 +63,21 @@ func (s *ConstraintsSuite) TestParseConstraintsOptions(c *gc.C) {
 	})
 }
 
+func (s *ConstraintsSuite) TestParseConstraintsSnapshot(c *gc.C) {
+	s.testParse(c, "snap:0", storage.Constraints{
+		Count:    1,
+		Snapshot: "0",
+	})
+	s.testParse(c, "ebs,snap:1/2,10G", storage.Constraints{
+		Pool:     "ebs",
+		Count:    1,
+		Size:     10 * 1024,
+		Snapshot: "1/2",
+	})
+	s.testParseError(c, "snap:", `cannot parse snapshot: snapshot ID "" not valid`)
+	s.testParseError(c, "p,snap:foo", `cannot parse snapshot: snapshot ID "foo" not valid`)
+}
+
 func (s *ConstraintsSuite) TestParseConstraintsCountRange(c *gc.C) {
 	s.testParseError(c, "p,0,100M", `cannot parse count: count must be greater than zero, got "0"`)
 	s.testParseError(c, "p,00,100M", `cannot parse count: count must be greater than zero, got "00"`)
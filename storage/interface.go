@@ -115,6 +115,30 @@ type VolumeSource interface {
 	DetachVolumes(params []VolumeAttachmentParams) error
 }
 
+// VolumeResizer is an optional interface that may be implemented by a
+// VolumeSource whose volumes can be grown after they are provisioned.
+type VolumeResizer interface {
+	// ResizeVolumes grows the volumes with the specified parameters,
+	// returning the resulting volume details. The volumes may be in
+	// use while they are resized; it is the responsibility of the
+	// charm to grow any filesystem on the volume.
+	ResizeVolumes(params []VolumeResizeParams) ([]Volume, error)
+}
+
+// VolumeSnapshotter is an optional interface that may be implemented by
+// a VolumeSource whose volumes can be snapshotted. A VolumeSource that
+// implements VolumeSnapshotter must also support creating volumes from
+// snapshots, as specified by VolumeParams.SnapshotId.
+type VolumeSnapshotter interface {
+	// CreateSnapshots takes snapshots of volumes with the specified
+	// parameters.
+	CreateSnapshots(params []SnapshotParams) ([]Snapshot, error)
+
+	// DestroySnapshots destroys the snapshots with the specified
+	// provider snapshot IDs.
+	DestroySnapshots(snapshotIds []string) []error
+}
+
 // FilesystemSource provides an interface for creating, destroying and
 // describing filesystems in the environment. A FilesystemSource is
 // configured in a particular way, and corresponds to a storage "pool".
@@ -163,6 +187,12 @@ type VolumeParams struct {
 	// from the storage pool configuration.
 	Attributes map[string]interface{}
 
+	// SnapshotId, if non-empty, is the provider-supplied ID of the
+	// snapshot from which the volume should be created. SnapshotId
+	// will only be set for volume sources that implement the
+	// VolumeSnapshotter interface.
+	SnapshotId string
+
 	// Attachment identifies the machine that the volume should be attached
 	// to initially, or nil if the volume should not be attached to any
 	// machine. Some providers, such as MAAS, do not support dynamic
@@ -196,6 +226,53 @@ type VolumeAttachmentParams struct {
 	VolumeId string
 }
 
+// VolumeResizeParams is a set of parameters for resizing a volume.
+type VolumeResizeParams struct {
+	// Tag is the unique tag assigned by Juju for the volume.
+	Tag names.VolumeTag
+
+	// VolumeId is the unique provider-supplied ID for the volume.
+	VolumeId string
+
+	// Size is the minimum size to grow the volume to, in MiB.
+	Size uint64
+
+	// Provider is the name of the storage provider that manages
+	// the volume.
+	Provider ProviderType
+
+	// Attributes is the set of provider-specific attributes that
+	// the volume was created with, derived from the storage pool
+	// configuration.
+	Attributes map[string]interface{}
+}
+
+// SnapshotParams is a set of parameters for snapshotting a volume.
+type SnapshotParams struct {
+	// Id is the unique ID assigned by Juju to the snapshot.
+	Id string
+
+	// Volume is the unique tag assigned by Juju for the volume
+	// that is to be snapshotted.
+	Volume names.VolumeTag
+
+	// VolumeId is the unique provider-supplied ID for the volume
+	// that is to be snapshotted.
+	VolumeId string
+
+	// Size is the size of the volume, in MiB.
+	Size uint64
+
+	// Provider is the name of the storage provider that manages
+	// the volume.
+	Provider ProviderType
+
+	// Attributes is the set of provider-specific attributes that
+	// the volume was created with, derived from the storage pool
+	// configuration.
+	Attributes map[string]interface{}
+}
+
 // AttachmentParams describes the parameters for attaching a volume or
 // filesystem to a machine.
 type AttachmentParams struct {
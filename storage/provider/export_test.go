@@ -24,6 +24,19 @@ func LoopProvider(run func(string, ...string) (string, error)) storage.Provider
 	return &loopProvider{run}
 }
 
+func LVMVolumeSource(
+	volumeGroup string,
+	devices []string,
+	blockDevices []storage.BlockDevice,
+	run func(string, ...string) (string, error),
+) storage.VolumeSource {
+	return &lvmVolumeSource{run, volumeGroup, devices, blockDevices}
+}
+
+func LVMProvider(run func(string, ...string) (string, error)) storage.Provider {
+	return &lvmProvider{run}
+}
+
 func NewMockManagedFilesystemSource(
 	run func(string, ...string) (string, error),
 	volumeBlockDevices map[names.VolumeTag]storage.BlockDevice,
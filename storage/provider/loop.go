@@ -115,6 +115,17 @@ func (lvs *loopVolumeSource) CreateVolumes(args []storage.VolumeParams) ([]stora
 func (lvs *loopVolumeSource) createVolume(params storage.VolumeParams) (storage.Volume, error) {
 	volumeId := params.Tag.String()
 	loopFilePath := lvs.volumeFilePath(volumeId)
+	if params.SnapshotId != "" {
+		if !isLoopSnapshotId(params.SnapshotId) {
+			return storage.Volume{}, errors.Errorf("invalid loop snapshot ID %q", params.SnapshotId)
+		}
+		snapshotFilePath := lvs.volumeFilePath(params.SnapshotId)
+		if err := copyBlockFile(lvs.run, snapshotFilePath, loopFilePath); err != nil {
+			return storage.Volume{}, errors.Annotate(err, "could not restore snapshot")
+		}
+		// Fall through to grow the restored file to the
+		// requested size, if it is larger than the snapshot.
+	}
 	if err := createBlockFile(lvs.run, loopFilePath, params.Size); err != nil {
 		return storage.Volume{}, errors.Annotate(err, "could not create block file")
 	}
@@ -210,6 +221,120 @@ func (lvs *loopVolumeSource) DetachVolumes([]storage.VolumeAttachmentParams) err
 	return errors.NotSupportedf("detaching loop devices")
 }
 
+var _ storage.VolumeResizer = (*loopVolumeSource)(nil)
+
+// ResizeVolumes is defined on the VolumeResizer interface.
+func (lvs *loopVolumeSource) ResizeVolumes(args []storage.VolumeResizeParams) ([]storage.Volume, error) {
+	volumes := make([]storage.Volume, len(args))
+	for i, arg := range args {
+		if err := lvs.resizeVolume(arg); err != nil {
+			return nil, errors.Annotatef(err, "resizing volume %v", arg.Tag.Id())
+		}
+		volumes[i] = storage.Volume{
+			Tag:      arg.Tag,
+			VolumeId: arg.VolumeId,
+			Size:     arg.Size,
+		}
+	}
+	return volumes, nil
+}
+
+func (lvs *loopVolumeSource) resizeVolume(arg storage.VolumeResizeParams) error {
+	if _, err := names.ParseVolumeTag(arg.VolumeId); err != nil {
+		return errors.Errorf("invalid loop volume ID %q", arg.VolumeId)
+	}
+	loopFilePath := lvs.volumeFilePath(arg.VolumeId)
+	// fallocate extends the file if it is smaller than the
+	// requested size, and leaves the existing content intact.
+	if err := createBlockFile(lvs.run, loopFilePath, arg.Size); err != nil {
+		return errors.Trace(err)
+	}
+	deviceNames, err := associatedLoopDevices(lvs.run, loopFilePath)
+	if err != nil {
+		return errors.Annotate(err, "locating loop device")
+	}
+	for _, deviceName := range deviceNames {
+		if err := refreshLoopDeviceCapacity(lvs.run, deviceName); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+var _ storage.VolumeSnapshotter = (*loopVolumeSource)(nil)
+
+// CreateSnapshots is defined on the VolumeSnapshotter interface.
+//
+// Loop volume snapshots are sparse copies of the volumes' backing files.
+// The volumes are not quiesced while they are copied, so the snapshots
+// are only as consistent as the data would be after a power failure.
+func (lvs *loopVolumeSource) CreateSnapshots(args []storage.SnapshotParams) ([]storage.Snapshot, error) {
+	snapshots := make([]storage.Snapshot, len(args))
+	for i, arg := range args {
+		snapshotId, err := lvs.createSnapshot(arg)
+		if err != nil {
+			return nil, errors.Annotatef(err, "snapshotting volume %v", arg.Volume.Id())
+		}
+		snapshots[i] = storage.Snapshot{
+			Id:         arg.Id,
+			Volume:     arg.Volume,
+			SnapshotId: snapshotId,
+			Size:       arg.Size,
+		}
+	}
+	return snapshots, nil
+}
+
+func (lvs *loopVolumeSource) createSnapshot(arg storage.SnapshotParams) (string, error) {
+	if _, err := names.ParseVolumeTag(arg.VolumeId); err != nil {
+		return "", errors.Errorf("invalid loop volume ID %q", arg.VolumeId)
+	}
+	snapshotId := loopSnapshotPrefix + strings.Replace(arg.Id, "/", "-", -1)
+	volumeFilePath := lvs.volumeFilePath(arg.VolumeId)
+	snapshotFilePath := lvs.volumeFilePath(snapshotId)
+	if err := copyBlockFile(lvs.run, volumeFilePath, snapshotFilePath); err != nil {
+		return "", errors.Trace(err)
+	}
+	return snapshotId, nil
+}
+
+// DestroySnapshots is defined on the VolumeSnapshotter interface.
+func (lvs *loopVolumeSource) DestroySnapshots(snapshotIds []string) []error {
+	results := make([]error, len(snapshotIds))
+	for i, snapshotId := range snapshotIds {
+		if !isLoopSnapshotId(snapshotId) {
+			results[i] = errors.Errorf("invalid loop snapshot ID %q", snapshotId)
+			continue
+		}
+		err := os.Remove(lvs.volumeFilePath(snapshotId))
+		if err != nil && !os.IsNotExist(err) {
+			results[i] = errors.Annotatef(err, "destroying %q", snapshotId)
+		}
+	}
+	return results
+}
+
+// loopSnapshotPrefix is the prefix of the IDs of loop volume snapshots,
+// which are also the names of the snapshots' files in the storage
+// directory.
+const loopSnapshotPrefix = "snapshot-"
+
+func isLoopSnapshotId(snapshotId string) bool {
+	return strings.HasPrefix(snapshotId, loopSnapshotPrefix) &&
+		len(snapshotId) > len(loopSnapshotPrefix) &&
+		!strings.ContainsAny(snapshotId, `/\`)
+}
+
+// copyBlockFile makes a sparse copy of the file at the source path
+// at the destination path.
+func copyBlockFile(run runCommandFunc, sourcePath, destPath string) error {
+	_, err := run("cp", "--sparse=always", sourcePath, destPath)
+	if err != nil {
+		return errors.Annotatef(err, "copying %q to %q", sourcePath, destPath)
+	}
+	return nil
+}
+
 // createBlockFile creates a file at the specified path, with the
 // given size in mebibytes.
 func createBlockFile(run runCommandFunc, filePath string, sizeInMiB uint64) error {
@@ -245,6 +370,16 @@ func detachLoopDevice(run runCommandFunc, deviceName string) error {
 	return err
 }
 
+// refreshLoopDeviceCapacity causes the loop device with the specified
+// name to reread the size of its backing file.
+func refreshLoopDeviceCapacity(run runCommandFunc, deviceName string) error {
+	_, err := run("losetup", "-c", path.Join("/dev", deviceName))
+	if err != nil {
+		return errors.Annotatef(err, "refreshing capacity of loop device %q", deviceName)
+	}
+	return nil
+}
+
 // associatedLoopDevices returns the device names of the loop devices
 // associated with the specified file path.
 func associatedLoopDevices(run runCommandFunc, filePath string) ([]string, error) {
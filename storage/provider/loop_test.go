@@ -5,6 +5,7 @@ package provider_test
 
 import (
 	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	"github.com/juju/errors"
@@ -189,3 +190,95 @@ func (s *loopSuite) TestDetachVolumes(c *gc.C) {
 	err := source.DetachVolumes(nil)
 	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
 }
+
+func (s *loopSuite) TestResizeVolumes(c *gc.C) {
+	source := s.loopVolumeSource(c)
+	fileName := filepath.Join(s.storageDir, "volume-0")
+	s.commands.expect("fallocate", "-l", "4MiB", fileName)
+	cmd := s.commands.expect("losetup", "-j", fileName)
+	cmd.respond("/dev/loop0: foo", nil)
+	s.commands.expect("losetup", "-c", "/dev/loop0")
+
+	c.Assert(source, gc.Implements, new(storage.VolumeResizer))
+	volumes, err := source.(storage.VolumeResizer).ResizeVolumes([]storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		Size:     4,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes, jc.DeepEquals, []storage.Volume{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "volume-0",
+		Size:     4,
+	}})
+}
+
+func (s *loopSuite) TestResizeVolumesInvalidVolumeId(c *gc.C) {
+	source := s.loopVolumeSource(c)
+	_, err := source.(storage.VolumeResizer).ResizeVolumes([]storage.VolumeResizeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "../super/important/stuff",
+		Size:     4,
+	}})
+	c.Assert(err, gc.ErrorMatches, `resizing volume 0: invalid loop volume ID "\.\./super/important/stuff"`)
+}
+
+func (s *loopSuite) TestCreateSnapshots(c *gc.C) {
+	source := s.loopVolumeSource(c)
+	s.commands.expect(
+		"cp", "--sparse=always",
+		filepath.Join(s.storageDir, "volume-0-1"),
+		filepath.Join(s.storageDir, "snapshot-0-2"),
+	)
+
+	c.Assert(source, gc.Implements, new(storage.VolumeSnapshotter))
+	snapshots, err := source.(storage.VolumeSnapshotter).CreateSnapshots([]storage.SnapshotParams{{
+		Id:       "0/2",
+		Volume:   names.NewVolumeTag("0/1"),
+		VolumeId: "volume-0-1",
+		Size:     4,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshots, jc.DeepEquals, []storage.Snapshot{{
+		Id:         "0/2",
+		Volume:     names.NewVolumeTag("0/1"),
+		SnapshotId: "snapshot-0-2",
+		Size:       4,
+	}})
+}
+
+func (s *loopSuite) TestDestroySnapshots(c *gc.C) {
+	source := s.loopVolumeSource(c)
+	fileName := filepath.Join(s.storageDir, "snapshot-0-2")
+	err := ioutil.WriteFile(fileName, nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	errs := source.(storage.VolumeSnapshotter).DestroySnapshots([]string{
+		"snapshot-0-2", "snapshot-0-3", "../super/important/stuff",
+	})
+	c.Assert(errs, gc.HasLen, 3)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], jc.ErrorIsNil)
+	c.Assert(errs[2], gc.ErrorMatches, `invalid loop snapshot ID "\.\./super/important/stuff"`)
+	_, err = os.Stat(fileName)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *loopSuite) TestCreateVolumesFromSnapshot(c *gc.C) {
+	source := s.loopVolumeSource(c)
+	fileName := filepath.Join(s.storageDir, "volume-0-3")
+	s.commands.expect("cp", "--sparse=always", filepath.Join(s.storageDir, "snapshot-0-2"), fileName)
+	s.commands.expect("fallocate", "-l", "8MiB", fileName)
+
+	volumes, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0/3"),
+		Size:       8,
+		SnapshotId: "snapshot-0-2",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes, jc.DeepEquals, []storage.Volume{{
+		Tag:      names.NewVolumeTag("0/3"),
+		VolumeId: "volume-0-3",
+		Size:     8,
+	}})
+}
@@ -0,0 +1,356 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// LVM provider types.
+	LVMProviderType = storage.ProviderType("lvm")
+
+	// LVMVolumeGroup is the name of the LVM volume group from which
+	// logical volumes are carved. If the volume group does not exist,
+	// it is created on the devices specified by LVMDevices.
+	LVMVolumeGroup = "volume-group"
+
+	// LVMDevices is a comma-separated list of the names of block
+	// devices on which to create the volume group, as reported by
+	// the machine's disk manager (e.g. "sdb,sdc"). The devices must
+	// be unused, unmounted and unpartitioned.
+	LVMDevices = "devices"
+
+	// defaultLVMVolumeGroup is the name of the volume group used
+	// if none is specified in the pool configuration.
+	defaultLVMVolumeGroup = "juju"
+)
+
+// lvmVolumeGroupRE matches valid LVM volume group names.
+var lvmVolumeGroupRE = regexp.MustCompile(`^[a-zA-Z0-9+_.][a-zA-Z0-9+_.-]*$`)
+
+// lvmProvider creates volume sources which manage logical volumes
+// in an LVM volume group on the machine's spare block devices.
+type lvmProvider struct {
+	// run is a function type used for running commands on the local machine.
+	run runCommandFunc
+}
+
+var _ storage.Provider = (*lvmProvider)(nil)
+
+// ValidateConfig is defined on the Provider interface.
+func (p *lvmProvider) ValidateConfig(cfg *storage.Config) error {
+	_, _, err := lvmConfig(cfg)
+	return err
+}
+
+// lvmConfig returns the volume group name and device names
+// from the specified storage config.
+func lvmConfig(cfg *storage.Config) (volumeGroup string, devices []string, _ error) {
+	attrs := cfg.Attrs()
+	volumeGroup = defaultLVMVolumeGroup
+	if v, ok := attrs[LVMVolumeGroup]; ok {
+		s, ok := v.(string)
+		if !ok {
+			return "", nil, errors.Errorf("expected string for %q, got %T", LVMVolumeGroup, v)
+		}
+		if !lvmVolumeGroupRE.MatchString(s) {
+			return "", nil, errors.NotValidf("volume group name %q", s)
+		}
+		volumeGroup = s
+	}
+	if v, ok := attrs[LVMDevices]; ok {
+		s, ok := v.(string)
+		if !ok {
+			return "", nil, errors.Errorf("expected string for %q, got %T", LVMDevices, v)
+		}
+		seen := set.NewStrings()
+		for _, deviceName := range strings.Split(s, ",") {
+			deviceName = strings.TrimSpace(deviceName)
+			if deviceName == "" {
+				continue
+			}
+			if strings.ContainsAny(deviceName, `/\ `) {
+				return "", nil, errors.NotValidf("device name %q", deviceName)
+			}
+			if seen.Contains(deviceName) {
+				continue
+			}
+			seen.Add(deviceName)
+			devices = append(devices, deviceName)
+		}
+	}
+	return volumeGroup, devices, nil
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *lvmProvider) VolumeSource(
+	environConfig *config.Config,
+	sourceConfig *storage.Config,
+) (storage.VolumeSource, error) {
+	volumeGroup, devices, err := lvmConfig(sourceConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	blockDevices, _ := sourceConfig.Attrs()[storage.ConfigBlockDevices].([]storage.BlockDevice)
+	return &lvmVolumeSource{p.run, volumeGroup, devices, blockDevices}, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (p *lvmProvider) FilesystemSource(
+	environConfig *config.Config,
+	providerConfig *storage.Config,
+) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// Supports is defined on the Provider interface.
+func (*lvmProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (*lvmProvider) Scope() storage.Scope {
+	return storage.ScopeMachine
+}
+
+// Dynamic is defined on the Provider interface.
+func (*lvmProvider) Dynamic() bool {
+	return true
+}
+
+// lvmVolumeSource creates, destroys and attaches logical volumes in
+// an LVM volume group. The volume group is created on first use, on
+// the configured block devices.
+type lvmVolumeSource struct {
+	run         runCommandFunc
+	volumeGroup string
+	devices     []string
+
+	// blockDevices holds the block devices on the machine, as
+	// reported by its disk manager. The configured devices are
+	// resolved against these before the volume group is created.
+	blockDevices []storage.BlockDevice
+}
+
+var _ storage.VolumeSource = (*lvmVolumeSource)(nil)
+
+// CreateVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) CreateVolumes(args []storage.VolumeParams) ([]storage.Volume, []storage.VolumeAttachment, error) {
+	if len(args) == 0 {
+		return nil, nil, nil
+	}
+	if err := lvs.ensureVolumeGroup(); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	volumes := make([]storage.Volume, len(args))
+	for i, arg := range args {
+		volume, err := lvs.createVolume(arg)
+		if err != nil {
+			return nil, nil, errors.Annotate(err, "creating volume")
+		}
+		volumes[i] = volume
+	}
+	return volumes, nil, nil
+}
+
+func (lvs *lvmVolumeSource) createVolume(params storage.VolumeParams) (storage.Volume, error) {
+	// Logical volume names may not contain "/", so we
+	// use the tag's string form as the logical volume name.
+	volumeId := params.Tag.String()
+	_, err := lvs.run(
+		"lvcreate", "--yes",
+		"-L", fmt.Sprintf("%dm", params.Size),
+		"-n", volumeId,
+		lvs.volumeGroup,
+	)
+	if err != nil {
+		return storage.Volume{}, errors.Annotatef(err, "creating logical volume %q", volumeId)
+	}
+	return storage.Volume{
+		Tag:      params.Tag,
+		VolumeId: volumeId,
+		Size:     params.Size,
+	}, nil
+}
+
+// ensureVolumeGroup creates the volume group on the configured devices,
+// if it does not already exist.
+func (lvs *lvmVolumeSource) ensureVolumeGroup() error {
+	stdout, err := lvs.run("vgs", "--noheadings", "-o", "vg_name")
+	if err != nil {
+		return errors.Annotate(err, "listing volume groups")
+	}
+	for _, line := range strings.Split(stdout, "\n") {
+		if strings.TrimSpace(line) == lvs.volumeGroup {
+			return nil
+		}
+	}
+	if len(lvs.devices) == 0 {
+		return errors.Errorf(
+			"volume group %q does not exist, and no devices specified",
+			lvs.volumeGroup,
+		)
+	}
+	devicePaths := make([]string, len(lvs.devices))
+	for i, deviceName := range lvs.devices {
+		devicePath, err := lvs.devicePath(deviceName)
+		if err != nil {
+			return errors.Annotatef(err, "cannot create volume group %q", lvs.volumeGroup)
+		}
+		devicePaths[i] = devicePath
+	}
+	if _, err := lvs.run("pvcreate", devicePaths...); err != nil {
+		return errors.Annotate(err, "creating physical volumes")
+	}
+	args := append([]string{lvs.volumeGroup}, devicePaths...)
+	if _, err := lvs.run("vgcreate", args...); err != nil {
+		return errors.Annotatef(err, "creating volume group %q", lvs.volumeGroup)
+	}
+	return nil
+}
+
+// devicePath returns the path to the named block device, after checking
+// that the machine's disk manager reports it as safe to create a physical
+// volume on: physical volumes are only ever created on whole, unused disks.
+func (lvs *lvmVolumeSource) devicePath(deviceName string) (string, error) {
+	for _, dev := range lvs.blockDevices {
+		if dev.DeviceName != deviceName {
+			continue
+		}
+		switch {
+		case dev.InUse:
+			return "", errors.Errorf("block device %q is in use", deviceName)
+		case dev.MountPoint != "":
+			return "", errors.Errorf("block device %q is mounted at %q", deviceName, dev.MountPoint)
+		case dev.Partitioned:
+			return "", errors.Errorf("block device %q is partitioned", deviceName)
+		case dev.FilesystemType != "":
+			return "", errors.Errorf("block device %q contains a filesystem (%s)", deviceName, dev.FilesystemType)
+		}
+		return storage.BlockDevicePath(dev)
+	}
+	return "", errors.NotFoundf("block device %q", deviceName)
+}
+
+// DescribeVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) DescribeVolumes(volumeIds []string) ([]storage.Volume, error) {
+	stdout, err := lvs.run(
+		"lvs", "--noheadings", "--units", "m", "--nosuffix",
+		"-o", "lv_name,lv_size", lvs.volumeGroup,
+	)
+	if err != nil {
+		return nil, errors.Annotate(err, "listing logical volumes")
+	}
+	sizes := make(map[string]uint64)
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		size, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, errors.Annotatef(err, "parsing size of logical volume %q", fields[0])
+		}
+		sizes[fields[0]] = uint64(size)
+	}
+	volumes := make([]storage.Volume, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		size, ok := sizes[volumeId]
+		if !ok {
+			return nil, errors.NotFoundf("logical volume %q", volumeId)
+		}
+		volumes[i] = storage.Volume{VolumeId: volumeId, Size: size}
+	}
+	return volumes, nil
+}
+
+// DestroyVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) DestroyVolumes(volumeIds []string) []error {
+	results := make([]error, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		if _, err := names.ParseVolumeTag(volumeId); err != nil {
+			results[i] = errors.Errorf("invalid lvm volume ID %q", volumeId)
+			continue
+		}
+		if _, err := lvs.run("lvremove", "-f", lvs.logicalVolume(volumeId)); err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+		}
+	}
+	return results
+}
+
+// ValidateVolumeParams is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	// ValidateVolumeParams may be called on a machine other than the
+	// machine where the logical volume will be created, so we cannot
+	// check available space until we get to CreateVolumes.
+	return nil
+}
+
+// AttachVolumes is defined on the VolumeSource interface.
+//
+// Logical volumes are attached by activating them, which makes their
+// device-mapper devices available on the machine.
+func (lvs *lvmVolumeSource) AttachVolumes(args []storage.VolumeAttachmentParams) ([]storage.VolumeAttachment, error) {
+	attachments := make([]storage.VolumeAttachment, len(args))
+	for i, arg := range args {
+		attachment, err := lvs.attachVolume(arg)
+		if err != nil {
+			return nil, errors.Annotatef(err, "attaching volume %v", arg.Volume.Id())
+		}
+		attachments[i] = attachment
+	}
+	return attachments, nil
+}
+
+func (lvs *lvmVolumeSource) attachVolume(arg storage.VolumeAttachmentParams) (storage.VolumeAttachment, error) {
+	logicalVolume := lvs.logicalVolume(arg.VolumeId)
+	if _, err := lvs.run("lvchange", "-ay", logicalVolume); err != nil {
+		return storage.VolumeAttachment{}, errors.Annotatef(err, "activating logical volume %q", logicalVolume)
+	}
+	// The disk manager reports the kernel names of device-mapper
+	// devices (e.g. "dm-0"), so we must record the same name for
+	// the block device to be matched with the attachment.
+	stdout, err := lvs.run("readlink", "-f", path.Join("/dev", logicalVolume))
+	if err != nil {
+		return storage.VolumeAttachment{}, errors.Annotatef(err, "resolving device for logical volume %q", logicalVolume)
+	}
+	return storage.VolumeAttachment{
+		Volume:     arg.Volume,
+		Machine:    arg.Machine,
+		DeviceName: filepath.Base(strings.TrimSpace(stdout)),
+	}, nil
+}
+
+// DetachVolumes is defined on the VolumeSource interface.
+//
+// Logical volumes are detached by deactivating them.
+func (lvs *lvmVolumeSource) DetachVolumes(args []storage.VolumeAttachmentParams) error {
+	for _, arg := range args {
+		logicalVolume := lvs.logicalVolume(arg.VolumeId)
+		if _, err := lvs.run("lvchange", "-an", logicalVolume); err != nil {
+			return errors.Annotatef(err, "deactivating logical volume %q", logicalVolume)
+		}
+	}
+	return nil
+}
+
+// logicalVolume returns the "<volume group>/<logical volume>" name
+// of the logical volume with the specified volume ID.
+func (lvs *lvmVolumeSource) logicalVolume(volumeId string) string {
+	return lvs.volumeGroup + "/" + volumeId
+}
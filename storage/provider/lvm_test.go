@@ -0,0 +1,269 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&lvmSuite{})
+
+type lvmSuite struct {
+	testing.BaseSuite
+	commands *mockRunCommand
+}
+
+func (s *lvmSuite) TearDownTest(c *gc.C) {
+	if s.commands != nil {
+		s.commands.assertDrained()
+	}
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *lvmSuite) lvmProvider(c *gc.C) storage.Provider {
+	s.commands = &mockRunCommand{c: c}
+	return provider.LVMProvider(s.commands.run)
+}
+
+func (s *lvmSuite) lvmVolumeSource(c *gc.C, devices ...string) storage.VolumeSource {
+	return s.lvmVolumeSourceOn(c, devices, []storage.BlockDevice{
+		{DeviceName: "sda", InUse: true, Partitioned: true},
+		{DeviceName: "sdb", Size: 10240},
+		{DeviceName: "sdc", Size: 10240},
+	})
+}
+
+func (s *lvmSuite) lvmVolumeSourceOn(c *gc.C, devices []string, blockDevices []storage.BlockDevice) storage.VolumeSource {
+	s.commands = &mockRunCommand{c: c}
+	return provider.LVMVolumeSource("juju", devices, blockDevices, s.commands.run)
+}
+
+func (s *lvmSuite) TestValidateConfig(c *gc.C) {
+	p := s.lvmProvider(c)
+	validate := func(attrs map[string]interface{}) error {
+		cfg, err := storage.NewConfig("name", provider.LVMProviderType, attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		return p.ValidateConfig(cfg)
+	}
+	c.Assert(validate(map[string]interface{}{}), jc.ErrorIsNil)
+	c.Assert(validate(map[string]interface{}{
+		"volume-group": "data-vg",
+		"devices":      "sdb, sdc",
+	}), jc.ErrorIsNil)
+	c.Assert(validate(map[string]interface{}{
+		"volume-group": "-bad",
+	}), gc.ErrorMatches, `volume group name "-bad" not valid`)
+	c.Assert(validate(map[string]interface{}{
+		"devices": "sdb,/dev/sdc",
+	}), gc.ErrorMatches, `device name "/dev/sdc" not valid`)
+	c.Assert(validate(map[string]interface{}{
+		"devices": 123,
+	}), gc.ErrorMatches, `expected string for "devices", got int`)
+}
+
+func (s *lvmSuite) TestSupports(c *gc.C) {
+	p := s.lvmProvider(c)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+}
+
+func (s *lvmSuite) TestScope(c *gc.C) {
+	p := s.lvmProvider(c)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeMachine)
+}
+
+func (s *lvmSuite) TestCreateVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c, "sdb", "sdc")
+	cmd := s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+	cmd.respond("  ubuntu-vg\n", nil)
+	s.commands.expect("pvcreate", "/dev/sdb", "/dev/sdc")
+	s.commands.expect("vgcreate", "juju", "/dev/sdb", "/dev/sdc")
+	s.commands.expect("lvcreate", "--yes", "-L", "1024m", "-n", "volume-0-0", "juju")
+	s.commands.expect("lvcreate", "--yes", "-L", "2048m", "-n", "volume-0-1", "juju")
+
+	volumes, volumeAttachments, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0/0"),
+		Size: 1024,
+	}, {
+		Tag:  names.NewVolumeTag("0/1"),
+		Size: 2048,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	// volume attachments always deferred to AttachVolumes
+	c.Assert(volumeAttachments, gc.HasLen, 0)
+	c.Assert(volumes, jc.DeepEquals, []storage.Volume{{
+		Tag:      names.NewVolumeTag("0/0"),
+		VolumeId: "volume-0-0",
+		Size:     1024,
+	}, {
+		Tag:      names.NewVolumeTag("0/1"),
+		VolumeId: "volume-0-1",
+		Size:     2048,
+	}})
+}
+
+func (s *lvmSuite) TestCreateVolumesMachineBlockDevices(c *gc.C) {
+	p := s.lvmProvider(c)
+	cfg, err := storage.NewConfig("lvm", provider.LVMProviderType, map[string]interface{}{
+		"devices": "sdb",
+		storage.ConfigBlockDevices: []storage.BlockDevice{
+			{DeviceName: "sdb", Serial: "ata-WDC_123", Size: 10240},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := p.VolumeSource(nil, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+	s.commands.expect("pvcreate", "/dev/disk/by-id/ata-WDC_123")
+	s.commands.expect("vgcreate", "juju", "/dev/disk/by-id/ata-WDC_123")
+	s.commands.expect("lvcreate", "--yes", "-L", "1024m", "-n", "volume-0-0", "juju")
+
+	_, _, err = source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0/0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *lvmSuite) TestCreateVolumesUnsafeDevices(c *gc.C) {
+	for _, test := range []struct {
+		device storage.BlockDevice
+		err    string
+	}{{
+		device: storage.BlockDevice{DeviceName: "sdb", InUse: true},
+		err:    `block device "sdb" is in use`,
+	}, {
+		device: storage.BlockDevice{DeviceName: "sdb", MountPoint: "/srv"},
+		err:    `block device "sdb" is mounted at "/srv"`,
+	}, {
+		device: storage.BlockDevice{DeviceName: "sdb", Partitioned: true},
+		err:    `block device "sdb" is partitioned`,
+	}, {
+		device: storage.BlockDevice{DeviceName: "sdb", FilesystemType: "ext4"},
+		err:    `block device "sdb" contains a filesystem \(ext4\)`,
+	}, {
+		device: storage.BlockDevice{DeviceName: "sdc"},
+		err:    `block device "sdb" not found`,
+	}} {
+		source := s.lvmVolumeSourceOn(c, []string{"sdb"}, []storage.BlockDevice{test.device})
+		s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+
+		_, _, err := source.CreateVolumes([]storage.VolumeParams{{
+			Tag:  names.NewVolumeTag("0/0"),
+			Size: 1024,
+		}})
+		c.Check(err, gc.ErrorMatches, `cannot create volume group "juju": `+test.err)
+		s.commands.assertDrained()
+	}
+}
+
+func (s *lvmSuite) TestCreateVolumesExistingVolumeGroup(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	cmd := s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+	cmd.respond("  ubuntu-vg\n  juju\n", nil)
+	s.commands.expect("lvcreate", "--yes", "-L", "1024m", "-n", "volume-0-0", "juju")
+
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0/0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *lvmSuite) TestCreateVolumesNoDevices(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0/0"),
+		Size: 1024,
+	}})
+	c.Assert(err, gc.ErrorMatches, `volume group "juju" does not exist, and no devices specified`)
+}
+
+func (s *lvmSuite) TestCreateVolumesFails(c *gc.C) {
+	source := s.lvmVolumeSource(c, "sdb")
+	cmd := s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+	cmd.respond("juju\n", nil)
+	cmd = s.commands.expect("lvcreate", "--yes", "-L", "1024m", "-n", "volume-0-0", "juju")
+	cmd.respond("", errors.New("insufficient free space"))
+
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0/0"),
+		Size: 1024,
+	}})
+	c.Assert(err, gc.ErrorMatches, `creating volume: creating logical volume "volume-0-0": insufficient free space`)
+}
+
+func (s *lvmSuite) TestDescribeVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	cmd := s.commands.expect(
+		"lvs", "--noheadings", "--units", "m", "--nosuffix",
+		"-o", "lv_name,lv_size", "juju",
+	)
+	cmd.respond("  volume-0-0 1024.00\n  volume-0-1 2048.00\n", nil)
+
+	volumes, err := source.DescribeVolumes([]string{"volume-0-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes, jc.DeepEquals, []storage.Volume{{
+		VolumeId: "volume-0-1",
+		Size:     2048,
+	}})
+}
+
+func (s *lvmSuite) TestDestroyVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("lvremove", "-f", "juju/volume-0-0")
+	cmd := s.commands.expect("lvremove", "-f", "juju/volume-0-1")
+	cmd.respond("", errors.New("in use"))
+
+	errs := source.DestroyVolumes([]string{"volume-0-0", "volume-0-1", "../etc"})
+	c.Assert(errs, gc.HasLen, 3)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], gc.ErrorMatches, `destroying "volume-0-1": in use`)
+	c.Assert(errs[2], gc.ErrorMatches, `invalid lvm volume ID "../etc"`)
+}
+
+func (s *lvmSuite) TestAttachVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("lvchange", "-ay", "juju/volume-0-0")
+	cmd := s.commands.expect("readlink", "-f", "/dev/juju/volume-0-0")
+	cmd.respond("/dev/dm-2\n", nil)
+
+	attachments, err := source.AttachVolumes([]storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0/0"),
+		VolumeId: "volume-0-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, jc.DeepEquals, []storage.VolumeAttachment{{
+		Volume:     names.NewVolumeTag("0/0"),
+		Machine:    names.NewMachineTag("0"),
+		DeviceName: "dm-2",
+	}})
+}
+
+func (s *lvmSuite) TestDetachVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c)
+	s.commands.expect("lvchange", "-an", "juju/volume-0-0")
+
+	err := source.DetachVolumes([]storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0/0"),
+		VolumeId: "volume-0-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+}
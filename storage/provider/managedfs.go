@@ -82,9 +82,18 @@ func (s *managedFilesystemSource) createFilesystem(arg storage.FilesystemParams)
 	if err != nil {
 		return storage.Filesystem{}, errors.Trace(err)
 	}
-	devicePath := s.devicePath(blockDevice)
-	if err := createFilesystem(s.run, devicePath); err != nil {
-		return storage.Filesystem{}, errors.Trace(err)
+	if blockDevice.FilesystemType != "" {
+		// The volume already contains a filesystem, e.g. because
+		// it was restored from a snapshot; leave it intact.
+		logger.Debugf(
+			"volume %s already has a %s filesystem, not creating one",
+			arg.Volume.Id(), blockDevice.FilesystemType,
+		)
+	} else {
+		devicePath := s.devicePath(blockDevice)
+		if err := createFilesystem(s.run, devicePath); err != nil {
+			return storage.Filesystem{}, errors.Trace(err)
+		}
 	}
 	return storage.Filesystem{
 		arg.Tag,
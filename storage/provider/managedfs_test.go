@@ -85,6 +85,28 @@ func (s *managedfsSuite) TestCreateFilesystems(c *gc.C) {
 	}})
 }
 
+func (s *managedfsSuite) TestCreateFilesystemsExistingFilesystem(c *gc.C) {
+	source := s.initSource(c)
+	s.blockDevices[names.NewVolumeTag("0")] = storage.BlockDevice{
+		DeviceName:     "sda",
+		Size:           2,
+		FilesystemType: "ext4",
+	}
+	// No mkfs is expected, as the volume already has a filesystem.
+	filesystems, err := source.CreateFilesystems([]storage.FilesystemParams{{
+		Tag:    names.NewFilesystemTag("0/0"),
+		Volume: names.NewVolumeTag("0"),
+		Size:   2,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(filesystems, jc.DeepEquals, []storage.Filesystem{{
+		Tag:          names.NewFilesystemTag("0/0"),
+		Volume:       names.NewVolumeTag("0"),
+		FilesystemId: "filesystem-0-0",
+		Size:         2,
+	}})
+}
+
 func (s *managedfsSuite) TestCreateFilesystemsNoBlockDevice(c *gc.C) {
 	source := s.initSource(c)
 	_, err := source.CreateFilesystems([]storage.FilesystemParams{{
@@ -0,0 +1,22 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import "github.com/juju/names"
+
+// Snapshot describes a point-in-time copy of a volume.
+type Snapshot struct {
+	// Id is the unique ID assigned by Juju to the snapshot.
+	Id string
+
+	// Volume is the tag of the volume that the snapshot was taken of.
+	Volume names.VolumeTag
+
+	// SnapshotId is a unique provider-supplied ID for the snapshot.
+	SnapshotId string
+
+	// Size is the size of the volume the snapshot was taken of, in MiB.
+	// A volume restored from the snapshot must be at least this size.
+	Size uint64
+}
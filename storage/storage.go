@@ -47,4 +47,9 @@ type StorageAttachmentInfo struct {
 	// for a filesystem-kind storage attachment, and the device path
 	// for a block-kind.
 	Location string
+
+	// Size is the size of a block-kind storage attachment's volume,
+	// in MiB. The size of a block device may change over its life
+	// if the volume is resized.
+	Size uint64
 }
@@ -0,0 +1,229 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package agentlost implements a worker that marks the agents of
+// machines and units as lost when they have failed to signal their
+// presence for longer than the environment's grace period.
+package agentlost
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.agentlost")
+
+// DefaultCheckInterval is how often agent presence is checked.
+const DefaultCheckInterval = 30 * time.Second
+
+// lostInfo is the message recorded with a lost status.
+const lostInfo = "agent is not communicating with the server"
+
+type lostWorker struct {
+	st            *state.State
+	checkInterval time.Duration
+
+	// absentSince records when each agent, keyed by the tag of its
+	// entity, was first seen not to be alive.
+	absentSince map[string]time.Time
+}
+
+// New returns a worker that periodically checks the presence of
+// machine and unit agents, marking them lost once they have been
+// absent for longer than the environment's agent-lost-grace-period,
+// and restoring their previous status when they return. If the
+// environment's agent-lost-degrades-service setting is true, services
+// with lost unit agents are marked degraded.
+func New(st *state.State, checkInterval time.Duration) worker.Worker {
+	w := &lostWorker{
+		st:            st,
+		checkInterval: checkInterval,
+		absentSince:   make(map[string]time.Time),
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+func (w *lostWorker) loop(stopCh <-chan struct{}) error {
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(w.checkInterval):
+			if err := w.check(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// agent is implemented by the machines and units whose agents'
+// presence is checked.
+type agent interface {
+	Tag() names.Tag
+	AgentPresence() (bool, error)
+	SetAgentLost(info string) error
+	ClearAgentLost() error
+}
+
+func (w *lostWorker) check() error {
+	cfg, err := w.st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	grace := cfg.AgentLostGracePeriod()
+	seen := make(map[string]bool)
+
+	machines, err := w.st.AllMachines()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, m := range machines {
+		canBeLost, isLost := machineLostStatus(m)
+		if !canBeLost {
+			continue
+		}
+		seen[m.Tag().String()] = true
+		if _, err := w.checkAgent(m, isLost, grace); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	services, err := w.st.AllServices()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, s := range services {
+		units, err := s.AllUnits()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var lostUnits []string
+		for _, u := range units {
+			canBeLost, isLost := unitLostStatus(u)
+			if !canBeLost {
+				continue
+			}
+			seen[u.Tag().String()] = true
+			lost, err := w.checkAgent(u, isLost, grace)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if lost {
+				lostUnits = append(lostUnits, u.Name())
+			}
+		}
+		if err := updateServiceDegraded(s, lostUnits, cfg.AgentLostDegradesService()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	for tag := range w.absentSince {
+		if !seen[tag] {
+			delete(w.absentSince, tag)
+		}
+	}
+	return nil
+}
+
+// checkAgent marks the agent lost if it has been absent for longer
+// than the grace period, or clears a lost status if it is present.
+// It reports whether the agent is lost; isLost holds whether it was
+// already marked lost.
+func (w *lostWorker) checkAgent(a agent, isLost bool, grace time.Duration) (bool, error) {
+	tag := a.Tag().String()
+	alive, err := a.AgentPresence()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if alive {
+		delete(w.absentSince, tag)
+		if err := a.ClearAgentLost(); err != nil && !errors.IsNotFound(err) {
+			return false, errors.Trace(err)
+		}
+		return false, nil
+	}
+	since, ok := w.absentSince[tag]
+	if !ok {
+		w.absentSince[tag] = time.Now()
+		return isLost, nil
+	}
+	if isLost {
+		return true, nil
+	}
+	if time.Since(since) < grace {
+		return false, nil
+	}
+	if err := a.SetAgentLost(lostInfo); err != nil {
+		if errors.IsNotFound(err) || errors.Cause(err) == state.ErrDead {
+			return false, nil
+		}
+		return false, errors.Trace(err)
+	}
+	logger.Debugf("agent of %s is lost", tag)
+	return true, nil
+}
+
+// machineLostStatus reports whether the machine's agent is expected
+// to be signalling its presence, and whether it is marked lost.
+func machineLostStatus(m *state.Machine) (canBeLost, isLost bool) {
+	if m.Life() == state.Dead {
+		return false, false
+	}
+	if _, err := m.InstanceId(); err != nil {
+		return false, false
+	}
+	status, err := m.Status()
+	if err != nil || status.Status == state.StatusPending {
+		return false, false
+	}
+	return true, status.Status == state.StatusLost
+}
+
+// unitLostStatus reports whether the unit's agent is expected to be
+// signalling its presence, and whether it is marked lost.
+func unitLostStatus(u *state.Unit) (canBeLost, isLost bool) {
+	if u.Life() == state.Dead {
+		return false, false
+	}
+	status, err := u.AgentStatus()
+	if err != nil {
+		return false, false
+	}
+	switch status.Status {
+	case state.StatusAllocating, state.StatusPending, state.StatusInstalling:
+		return false, false
+	}
+	return true, status.Status == state.StatusLost
+}
+
+// updateServiceDegraded marks the service degraded if any of its units'
+// agents are lost and degrade is true, and clears it otherwise.
+func updateServiceDegraded(s *state.Service, lostUnits []string, degrade bool) error {
+	if s.Life() != state.Alive {
+		return nil
+	}
+	if !degrade || len(lostUnits) == 0 {
+		err := s.ClearDegraded()
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	sort.Strings(lostUnits)
+	info := fmt.Sprintf("degraded: agents lost for %s", strings.Join(lostUnits, ", "))
+	err := s.SetDegraded(info)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
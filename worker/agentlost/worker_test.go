@@ -0,0 +1,112 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentlost_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/agentlost"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type agentLostSuite struct {
+	testing.JujuConnSuite
+	service *state.Service
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&agentLostSuite{})
+
+func (s *agentLostSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		config.AgentLostGracePeriodKey:     1,
+		config.AgentLostDegradesServiceKey: true,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit = unit
+}
+
+func (s *agentLostSuite) startWorker(c *gc.C) {
+	w := agentlost.New(s.State, 10*time.Millisecond)
+	s.AddCleanup(func(c *gc.C) { c.Assert(worker.Stop(w), jc.ErrorIsNil) })
+}
+
+func (s *agentLostSuite) waitAgentStatus(c *gc.C, expect state.Status) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		s.State.StartSync()
+		status, err := s.unit.AgentStatus()
+		c.Assert(err, jc.ErrorIsNil)
+		if status.Status == expect {
+			return
+		}
+	}
+	c.Fatalf("timed out waiting for agent status %q", expect)
+}
+
+func (s *agentLostSuite) TestMarksLostAndRecovers(c *gc.C) {
+	s.startWorker(c)
+	s.waitAgentStatus(c, state.StatusLost)
+
+	status, err := s.service.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusBlocked)
+	c.Assert(status.Message, gc.Equals, "degraded: agents lost for wordpress/0")
+
+	pinger, err := s.unit.SetAgentPresence()
+	c.Assert(err, jc.ErrorIsNil)
+	defer pinger.Stop()
+	s.waitAgentStatus(c, state.StatusIdle)
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		degraded, err := s.service.IsDegraded()
+		c.Assert(err, jc.ErrorIsNil)
+		if !degraded {
+			return
+		}
+	}
+	c.Fatalf("service still degraded")
+}
+
+func (s *agentLostSuite) TestGracePeriod(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		config.AgentLostGracePeriodKey: 3600,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.startWorker(c)
+
+	time.Sleep(coretesting.ShortWait)
+	status, err := s.unit.AgentStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusIdle)
+}
+
+func (s *agentLostSuite) TestAllocatingUnitNotLost(c *gc.C) {
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.startWorker(c)
+	s.waitAgentStatus(c, state.StatusLost)
+
+	status, err := unit.AgentStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, state.StatusAllocating)
+}
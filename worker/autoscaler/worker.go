@@ -0,0 +1,242 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package autoscaler implements a worker that adds and destroys the
+// units of services according to their autoscale policies, which track
+// the metrics reported by the units with add-metric.
+package autoscaler
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.autoscaler")
+
+// DefaultCheckInterval is how often the autoscale policies are
+// evaluated.
+const DefaultCheckInterval = time.Minute
+
+type autoscaler struct {
+	st            *state.State
+	checkInterval time.Duration
+}
+
+// New returns a worker that periodically evaluates the autoscale
+// policies of the environment's services, adding or destroying units
+// to keep the tracked metric, averaged across each service's units,
+// at its policy's threshold. Each change is recorded in the service's
+// autoscale history.
+func New(st *state.State, checkInterval time.Duration) worker.Worker {
+	w := &autoscaler{
+		st:            st,
+		checkInterval: checkInterval,
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+func (w *autoscaler) loop(stopCh <-chan struct{}) error {
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(w.checkInterval):
+			if err := w.check(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+func (w *autoscaler) check() error {
+	policies, err := w.st.AutoscalePolicies()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for serviceName, policy := range policies {
+		// A failure to scale one service should not prevent the
+		// others from being scaled.
+		if err := w.scale(serviceName, policy); err != nil {
+			logger.Errorf("cannot autoscale service %q: %v", serviceName, err)
+		}
+	}
+	return nil
+}
+
+// scale evaluates the policy of the named service, adding or destroying
+// units as required.
+func (w *autoscaler) scale(serviceName string, policy state.AutoscalePolicy) error {
+	service, err := w.st.Service(serviceName)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if service.Life() != state.Alive {
+		return nil
+	}
+	now := time.Now()
+	history, err := service.AutoscaleHistory(1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(history) > 0 && now.Sub(history[0].Time) < policy.Cooldown {
+		return nil
+	}
+
+	units, err := aliveUnits(service)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	averages, err := service.UnitMetricAverages(policy.Metric, now.Add(-policy.Window))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var sum float64
+	var reporting int
+	for _, unit := range units {
+		if value, ok := averages[unit.Name()]; ok {
+			sum += value
+			reporting++
+		}
+	}
+	var average float64
+	if reporting > 0 {
+		average = sum / float64(reporting)
+	}
+	current := len(units)
+	desired, reason := DesiredUnits(policy, service.MinUnits(), current, average, reporting > 0)
+	if desired == current {
+		return nil
+	}
+
+	logger.Infof("scaling service %q from %d to %d units: %s", serviceName, current, desired, reason)
+	if desired > current {
+		err = addUnits(w.st, service, desired-current)
+	} else {
+		err = destroyUnits(units, current-desired)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return service.RecordAutoscaleDecision(state.AutoscaleDecision{
+		Time:      now,
+		Metric:    policy.Metric,
+		Value:     average,
+		FromUnits: current,
+		ToUnits:   desired,
+		Reason:    reason,
+	})
+}
+
+// DesiredUnits returns the number of units a service with the given
+// policy, minimum units and current number of units should have, along
+// with the reason for any change. The average holds the policy's metric
+// averaged across the units, and is only meaningful if haveMetrics is
+// true; without metrics, the number of units is only kept within the
+// policy's bounds.
+func DesiredUnits(policy state.AutoscalePolicy, minUnits, current int, average float64, haveMetrics bool) (int, string) {
+	lower, upper := policy.MinUnits, policy.MaxUnits
+	if minUnits > lower {
+		// The service's minimum units, maintained by the minunits
+		// worker, takes precedence.
+		lower = minUnits
+	}
+	if lower > upper {
+		upper = lower
+	}
+	desired := current
+	var reason string
+	if haveMetrics && current > 0 {
+		// The number of units at which the average would be at or
+		// below the threshold, if the load were spread evenly.
+		desired = int(math.Ceil(average * float64(current) / policy.Threshold))
+		if desired > current {
+			reason = fmt.Sprintf("average %s %v above threshold %v", policy.Metric, average, policy.Threshold)
+		} else if desired < current {
+			reason = fmt.Sprintf("average %s %v below threshold %v", policy.Metric, average, policy.Threshold)
+		}
+	}
+	if desired < lower || desired > upper {
+		if desired < lower {
+			desired = lower
+		} else {
+			desired = upper
+		}
+		if reason == "" {
+			reason = fmt.Sprintf("unit count outside bounds %d-%d", lower, upper)
+		} else {
+			reason += fmt.Sprintf(", limited to %d units", desired)
+		}
+	}
+	if desired == current {
+		return current, ""
+	}
+	return desired, reason
+}
+
+// aliveUnits returns the service's alive units.
+func aliveUnits(service *state.Service) ([]*state.Unit, error) {
+	units, err := service.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []*state.Unit
+	for _, unit := range units {
+		if unit.Life() == state.Alive {
+			result = append(result, unit)
+		}
+	}
+	return result, nil
+}
+
+// addUnits adds n units to the service, assigning each to a new
+// machine, as the minunits worker does.
+func addUnits(st *state.State, service *state.Service, n int) error {
+	for i := 0; i < n; i++ {
+		unit, err := service.AddUnit()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := st.AssignUnit(unit, state.AssignNew); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// destroyUnits destroys the n most recently added of the given units.
+func destroyUnits(units []*state.Unit, n int) error {
+	sort.Sort(byUnitNumber(units))
+	for _, unit := range units[len(units)-n:] {
+		if err := unit.Destroy(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+type byUnitNumber []*state.Unit
+
+func (u byUnitNumber) Len() int      { return len(u) }
+func (u byUnitNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u byUnitNumber) Less(i, j int) bool {
+	return unitNumber(u[i]) < unitNumber(u[j])
+}
+
+func unitNumber(unit *state.Unit) int {
+	name := unit.Name()
+	n, _ := strconv.Atoi(name[strings.LastIndex(name, "/")+1:])
+	return n
+}
@@ -0,0 +1,201 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autoscaler_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/autoscaler"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type autoscalerSuite struct {
+	testing.JujuConnSuite
+	service *state.Service
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&autoscalerSuite{})
+
+func (s *autoscalerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "metered", s.AddTestingCharm(c, "metered"))
+	s.unit = s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service, SetCharmURL: true})
+	err := s.service.SetAutoscalePolicy(state.AutoscalePolicy{
+		Metric:    "pings",
+		Threshold: 100,
+		Window:    time.Hour,
+		MinUnits:  1,
+		MaxUnits:  3,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *autoscalerSuite) startWorker(c *gc.C) worker.Worker {
+	w := autoscaler.New(s.State, 10*time.Millisecond)
+	s.AddCleanup(func(c *gc.C) { worker.Stop(w) })
+	return w
+}
+
+func (s *autoscalerSuite) addMetric(c *gc.C, unit *state.Unit, value string, age time.Duration) {
+	t := time.Now().Add(-age).Round(time.Second).UTC()
+	s.Factory.MakeMetric(c, &factory.MetricParams{
+		Unit:    unit,
+		Time:    &t,
+		Metrics: []state.Metric{{"pings", value, t}},
+	})
+}
+
+func (s *autoscalerSuite) waitAliveUnits(c *gc.C, expect int) []*state.Unit {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		units, err := s.service.AllUnits()
+		c.Assert(err, jc.ErrorIsNil)
+		var alive []*state.Unit
+		for _, unit := range units {
+			if unit.Life() == state.Alive {
+				alive = append(alive, unit)
+			}
+		}
+		if len(alive) == expect {
+			return alive
+		}
+	}
+	c.Fatalf("timed out waiting for %d alive units", expect)
+	return nil
+}
+
+func (s *autoscalerSuite) TestScalesUpAndDown(c *gc.C) {
+	s.addMetric(c, s.unit, "250", 30*time.Minute)
+	w := s.startWorker(c)
+	s.waitAliveUnits(c, 3)
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+
+	history, err := s.service.AutoscaleHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].FromUnits, gc.Equals, 1)
+	c.Assert(history[0].ToUnits, gc.Equals, 3)
+	c.Assert(history[0].Value, gc.Equals, 250.0)
+	c.Assert(history[0].Reason, gc.Equals, "average pings 250 above threshold 100")
+
+	// With the units reporting little load, the service is scaled
+	// back down to its policy's minimum, destroying the newest units.
+	err = s.service.SetAutoscalePolicy(state.AutoscalePolicy{
+		Metric:    "pings",
+		Threshold: 100,
+		Window:    10 * time.Minute,
+		MinUnits:  1,
+		MaxUnits:  3,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := s.service.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := s.service.CharmURL()
+	for _, unit := range units {
+		err := unit.SetCharmURL(curl)
+		c.Assert(err, jc.ErrorIsNil)
+		s.addMetric(c, unit, "10", 0)
+	}
+	s.startWorker(c)
+	alive := s.waitAliveUnits(c, 1)
+	c.Assert(alive[0].Name(), gc.Equals, "metered/0")
+}
+
+func (s *autoscalerSuite) TestCooldown(c *gc.C) {
+	err := s.service.SetAutoscalePolicy(state.AutoscalePolicy{
+		Metric:    "pings",
+		Threshold: 100,
+		Window:    time.Hour,
+		MinUnits:  1,
+		MaxUnits:  3,
+		Cooldown:  time.Hour,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.RecordAutoscaleDecision(state.AutoscaleDecision{Time: time.Now(), FromUnits: 0, ToUnits: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	s.addMetric(c, s.unit, "250", 0)
+	s.startWorker(c)
+
+	time.Sleep(coretesting.ShortWait)
+	units, err := s.service.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+}
+
+func (s *autoscalerSuite) TestDesiredUnits(c *gc.C) {
+	policy := state.AutoscalePolicy{
+		Metric:    "pings",
+		Threshold: 100,
+		MinUnits:  2,
+		MaxUnits:  5,
+	}
+	for i, test := range []struct {
+		minUnits    int
+		current     int
+		average     float64
+		haveMetrics bool
+		desired     int
+		reason      string
+	}{{
+		current:     2,
+		average:     90,
+		haveMetrics: true,
+		desired:     2,
+	}, {
+		current:     2,
+		average:     150,
+		haveMetrics: true,
+		desired:     3,
+		reason:      "average pings 150 above threshold 100",
+	}, {
+		current:     4,
+		average:     40,
+		haveMetrics: true,
+		desired:     2,
+		reason:      "average pings 40 below threshold 100",
+	}, {
+		current:     4,
+		average:     500,
+		haveMetrics: true,
+		desired:     5,
+		reason:      "average pings 500 above threshold 100, limited to 5 units",
+	}, {
+		current:     3,
+		average:     10,
+		haveMetrics: true,
+		desired:     2,
+		reason:      "average pings 10 below threshold 100, limited to 2 units",
+	}, {
+		current: 1,
+		desired: 2,
+		reason:  "unit count outside bounds 2-5",
+	}, {
+		current: 7,
+		desired: 5,
+		reason:  "unit count outside bounds 2-5",
+	}, {
+		minUnits:    6,
+		current:     6,
+		average:     10,
+		haveMetrics: true,
+		desired:     6,
+	}} {
+		c.Logf("test %d", i)
+		desired, reason := autoscaler.DesiredUnits(policy, test.minUnits, test.current, test.average, test.haveMetrics)
+		c.Check(desired, gc.Equals, test.desired)
+		c.Check(reason, gc.Equals, test.reason)
+	}
+}
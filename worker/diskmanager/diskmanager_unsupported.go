@@ -6,6 +6,9 @@
 package diskmanager
 
 import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/version"
 )
@@ -19,10 +22,15 @@ func listBlockDevices() ([]storage.BlockDevice, error) {
 	return nil, nil
 }
 
+func filesystemUsage(string) (params.StorageUsage, error) {
+	return params.StorageUsage{}, errors.NotSupportedf("filesystem usage")
+}
+
 func init() {
 	logger.Infof(
 		"block device support has not been implemented for %s",
 		version.Current.OS,
 	)
 	DefaultListBlockDevices = listBlockDevices
+	DefaultFilesystemUsage = filesystemUsage
 }
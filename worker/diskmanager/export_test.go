@@ -4,7 +4,8 @@
 package diskmanager
 
 var (
-	ListBlockDevices = listBlockDevices
-	BlockDeviceInUse = &blockDeviceInUse
-	DoWork           = doWork
+	ListBlockDevices      = listBlockDevices
+	BlockDeviceInUse      = &blockDeviceInUse
+	DoWork                = doWork
+	ReportFilesystemUsage = reportFilesystemUsage
 )
@@ -0,0 +1,76 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskmanager
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker"
+)
+
+// filesystemUsagePeriod is the time period between reports
+// of the usage of attached filesystems.
+const filesystemUsagePeriod = time.Minute
+
+// FilesystemUsageAccessor is an interface that is supplied to
+// NewFilesystemUsageWorker for listing the filesystems attached
+// to the local host, and recording their usage.
+type FilesystemUsageAccessor interface {
+	FilesystemAttachments() ([]params.FilesystemAttachment, error)
+	SetFilesystemUsage([]params.FilesystemUsage) error
+}
+
+// FilesystemUsageFunc is the type of a function that is supplied to
+// NewFilesystemUsageWorker for obtaining the usage of the filesystem
+// mounted at the specified path on the local host.
+type FilesystemUsageFunc func(mountPoint string) (params.StorageUsage, error)
+
+// DefaultFilesystemUsage is the default function for obtaining
+// filesystem usage for the operating system of the local host.
+var DefaultFilesystemUsage FilesystemUsageFunc
+
+// NewFilesystemUsageWorker returns a worker that periodically reports
+// the usage of the filesystems attached to the machine.
+func NewFilesystemUsageWorker(f FilesystemUsageFunc, a FilesystemUsageAccessor) worker.Worker {
+	w := func(stop <-chan struct{}) error {
+		return reportFilesystemUsage(f, a)
+	}
+	return worker.NewPeriodicWorker(w, filesystemUsagePeriod)
+}
+
+func reportFilesystemUsage(usagef FilesystemUsageFunc, a FilesystemUsageAccessor) error {
+	attachments, err := a.FilesystemAttachments()
+	if err != nil {
+		return errors.Annotate(err, "getting filesystem attachments")
+	}
+	usage := make([]params.FilesystemUsage, 0, len(attachments))
+	for _, att := range attachments {
+		u, err := usagef(att.MountPoint)
+		if err != nil {
+			// The filesystem may have been detached since
+			// the attachments were listed; try again later.
+			logger.Warningf(
+				"cannot get usage of filesystem %s: %v",
+				att.FilesystemTag, err,
+			)
+			continue
+		}
+		usage = append(usage, params.FilesystemUsage{
+			FilesystemTag: att.FilesystemTag,
+			MachineTag:    att.MachineTag,
+			Usage:         u,
+		})
+	}
+	if len(usage) == 0 {
+		return nil
+	}
+	logger.Debugf("filesystem usage: %v", usage)
+	if err := a.SetFilesystemUsage(usage); err != nil {
+		return errors.Annotate(err, "setting filesystem usage")
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskmanager_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/diskmanager"
+)
+
+var _ = gc.Suite(&FilesystemUsageWorkerSuite{})
+
+type FilesystemUsageWorkerSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *FilesystemUsageWorkerSuite) TestWorker(c *gc.C) {
+	done := make(chan struct{})
+	accessor := &mockFilesystemUsageAccessor{
+		attachments: []params.FilesystemAttachment{{
+			FilesystemTag: "filesystem-0",
+			MachineTag:    "machine-0",
+			MountPoint:    "/srv",
+		}},
+		setUsage: func([]params.FilesystemUsage) error {
+			close(done)
+			return nil
+		},
+	}
+	usagef := func(string) (params.StorageUsage, error) {
+		return params.StorageUsage{}, nil
+	}
+
+	w := diskmanager.NewFilesystemUsageWorker(usagef, accessor)
+	defer w.Wait()
+	defer w.Kill()
+
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for filesystem usage to be reported")
+	}
+}
+
+func (s *FilesystemUsageWorkerSuite) TestReportFilesystemUsage(c *gc.C) {
+	var usageSet []params.FilesystemUsage
+	accessor := &mockFilesystemUsageAccessor{
+		attachments: []params.FilesystemAttachment{{
+			FilesystemTag: "filesystem-0",
+			MachineTag:    "machine-0",
+			MountPoint:    "/srv/a",
+		}, {
+			FilesystemTag: "filesystem-1",
+			MachineTag:    "machine-0",
+			MountPoint:    "/srv/b",
+		}},
+		setUsage: func(usage []params.FilesystemUsage) error {
+			usageSet = usage
+			return nil
+		},
+	}
+	usagef := func(mountPoint string) (params.StorageUsage, error) {
+		if mountPoint == "/srv/b" {
+			return params.StorageUsage{}, errors.New("no such file or directory")
+		}
+		return params.StorageUsage{UsedBytes: 1024, FreeBytes: 3072, UsedInodes: 1, FreeInodes: 99}, nil
+	}
+
+	err := diskmanager.ReportFilesystemUsage(usagef, accessor)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Filesystems whose usage cannot be determined are skipped.
+	c.Assert(usageSet, jc.DeepEquals, []params.FilesystemUsage{{
+		FilesystemTag: "filesystem-0",
+		MachineTag:    "machine-0",
+		Usage:         params.StorageUsage{UsedBytes: 1024, FreeBytes: 3072, UsedInodes: 1, FreeInodes: 99},
+	}})
+}
+
+func (s *FilesystemUsageWorkerSuite) TestReportFilesystemUsageNoAttachments(c *gc.C) {
+	accessor := &mockFilesystemUsageAccessor{
+		setUsage: func([]params.FilesystemUsage) error {
+			c.Fatalf("unexpected call to SetFilesystemUsage")
+			return nil
+		},
+	}
+	usagef := func(string) (params.StorageUsage, error) {
+		return params.StorageUsage{}, nil
+	}
+	err := diskmanager.ReportFilesystemUsage(usagef, accessor)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type mockFilesystemUsageAccessor struct {
+	attachments []params.FilesystemAttachment
+	setUsage    func([]params.FilesystemUsage) error
+}
+
+func (m *mockFilesystemUsageAccessor) FilesystemAttachments() ([]params.FilesystemAttachment, error) {
+	return m.attachments, nil
+}
+
+func (m *mockFilesystemUsageAccessor) SetFilesystemUsage(usage []params.FilesystemUsage) error {
+	return m.setUsage(usage)
+}
@@ -57,6 +57,7 @@ func listBlockDevices() ([]storage.BlockDevice, error) {
 	}
 
 	blockDeviceMap := make(map[string]storage.BlockDevice)
+	var partitions []string
 	s := bufio.NewScanner(bytes.NewReader(output))
 	for s.Scan() {
 		pairs := pairsRE.FindAllStringSubmatch(s.Text(), -1)
@@ -94,6 +95,7 @@ func listBlockDevices() ([]storage.BlockDevice, error) {
 		// partition will remain available (and we don't model hierarchy).
 		if deviceType == partitionType {
 			logger.Debugf("ignoring partition: %+v", dev)
+			partitions = append(partitions, dev.DeviceName)
 			continue
 		}
 
@@ -113,12 +115,34 @@ func listBlockDevices() ([]storage.BlockDevice, error) {
 			// "in use" so the device cannot be used.
 			dev.InUse = true
 		}
+		if err := addHardwareInfo(&dev); err != nil {
+			logger.Errorf(
+				"error getting hardware info for %q: %v", dev.DeviceName, err,
+			)
+		}
 		blockDeviceMap[dev.DeviceName] = dev
 	}
 	if err := s.Err(); err != nil {
 		return nil, errors.Annotate(err, "cannot parse lsblk output")
 	}
 
+	// Record which devices have partitions, so that they are not
+	// mistaken for empty disks. The kernel names partitions after
+	// the device that contains them (e.g. "sda1", "nvme0n1p1").
+	for _, partition := range partitions {
+		var parent string
+		for name := range blockDeviceMap {
+			if len(name) > len(parent) && strings.HasPrefix(partition, name) {
+				parent = name
+			}
+		}
+		if parent != "" {
+			dev := blockDeviceMap[parent]
+			dev.Partitioned = true
+			blockDeviceMap[parent] = dev
+		}
+	}
+
 	blockDevices := make([]storage.BlockDevice, 0, len(blockDeviceMap))
 	for _, dev := range blockDeviceMap {
 		blockDevices = append(blockDevices, dev)
@@ -126,6 +150,33 @@ func listBlockDevices() ([]storage.BlockDevice, error) {
 	return blockDevices, nil
 }
 
+// addHardwareInfo adds the serial number of the block device, as
+// reported by udev, to dev. Storage providers identify the volumes
+// they create by serial where they can, so that the volumes can be
+// matched to block devices regardless of the kernel's naming.
+func addHardwareInfo(dev *storage.BlockDevice) error {
+	logger.Debugf("executing udevadm for %q", dev.DeviceName)
+	output, err := exec.Command(
+		"udevadm", "info",
+		"-q", "property",
+		"--name="+dev.DeviceName,
+	).Output()
+	if err != nil {
+		return errors.Annotate(err, "udevadm failed")
+	}
+	s := bufio.NewScanner(bytes.NewReader(output))
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasPrefix(line, "ID_SERIAL=") {
+			dev.Serial = strings.TrimPrefix(line, "ID_SERIAL=")
+		}
+	}
+	if err := s.Err(); err != nil {
+		return errors.Annotate(err, "cannot parse udevadm output")
+	}
+	return nil
+}
+
 // blockDeviceInUse checks if the specified block device
 // is in use by attempting to open the device exclusively.
 //
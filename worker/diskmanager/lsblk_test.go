@@ -29,6 +29,7 @@ func (s *ListBlockDevicesSuite) SetUpTest(c *gc.C) {
 	s.PatchValue(diskmanager.BlockDeviceInUse, func(storage.BlockDevice) (bool, error) {
 		return false, nil
 	})
+	testing.PatchExecutable(c, s, "udevadm", `#!/bin/bash --norc`)
 }
 
 func (s *ListBlockDevicesSuite) TestListBlockDevices(c *gc.C) {
@@ -71,6 +72,50 @@ EOF`)
 	}})
 }
 
+func (s *ListBlockDevicesSuite) TestListBlockDevicesSerial(c *gc.C) {
+	testing.PatchExecutable(c, s, "lsblk", `#!/bin/bash --norc
+cat <<EOF
+KNAME="sda" SIZE="240057409536" LABEL="" UUID=""
+KNAME="sdb" SIZE="10737418240" LABEL="" UUID=""
+EOF`)
+	testing.PatchExecutable(c, s, "udevadm", `#!/bin/bash --norc
+if [ "$4" = "--name=sdb" ]; then
+cat <<EOF
+DEVNAME=/dev/sdb
+ID_BUS=scsi
+ID_SERIAL=0Google_PersistentDisk_juju-volume-0
+ID_SERIAL_SHORT=juju-volume-0
+EOF
+fi`)
+
+	devices, err := diskmanager.ListBlockDevices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(devices, jc.SameContents, []storage.BlockDevice{{
+		DeviceName: "sda",
+		Size:       228936,
+	}, {
+		DeviceName: "sdb",
+		Size:       10240,
+		Serial:     "0Google_PersistentDisk_juju-volume-0",
+	}})
+}
+
+func (s *ListBlockDevicesSuite) TestListBlockDevicesUdevadmError(c *gc.C) {
+	testing.PatchExecutable(c, s, "lsblk", `#!/bin/bash --norc
+cat <<EOF
+KNAME="sda" SIZE="240057409536" LABEL="" UUID=""
+EOF`)
+	testing.PatchExecutableThrowError(c, s, "udevadm", 1)
+
+	// The device is still listed, without a serial.
+	devices, err := diskmanager.ListBlockDevices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(devices, jc.DeepEquals, []storage.BlockDevice{{
+		DeviceName: "sda",
+		Size:       228936,
+	}})
+}
+
 func (s *ListBlockDevicesSuite) TestListBlockDevicesLsblkError(c *gc.C) {
 	testing.PatchExecutableThrowError(c, s, "lsblk", 123)
 	devices, err := diskmanager.ListBlockDevices()
@@ -143,7 +188,28 @@ EOF`)
 	devices, err := diskmanager.ListBlockDevices()
 	c.Assert(err, gc.IsNil)
 	c.Assert(devices, gc.DeepEquals, []storage.BlockDevice{{
-		DeviceName: "sda",
+		DeviceName:  "sda",
+		Size:        228936,
+		Partitioned: true,
+	}})
+}
+
+func (s *ListBlockDevicesSuite) TestListBlockDevicesNVMePartitions(c *gc.C) {
+	testing.PatchExecutable(c, s, "lsblk", `#!/bin/bash --norc
+cat <<EOF
+KNAME="nvme0n1" SIZE="240057409536" LABEL="" UUID="" TYPE="disk"
+KNAME="nvme0n1p1" SIZE="254803968" LABEL="" UUID="" TYPE="part"
+KNAME="nvme1n1" SIZE="240057409536" LABEL="" UUID="" TYPE="disk"
+EOF`)
+
+	devices, err := diskmanager.ListBlockDevices()
+	c.Assert(err, gc.IsNil)
+	c.Assert(devices, jc.SameContents, []storage.BlockDevice{{
+		DeviceName:  "nvme0n1",
+		Size:        228936,
+		Partitioned: true,
+	}, {
+		DeviceName: "nvme1n1",
 		Size:       228936,
 	}})
 }
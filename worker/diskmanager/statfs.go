@@ -0,0 +1,32 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build linux
+
+package diskmanager
+
+import (
+	"syscall"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+func init() {
+	DefaultFilesystemUsage = filesystemUsage
+}
+
+func filesystemUsage(mountPoint string) (params.StorageUsage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(mountPoint, &st); err != nil {
+		return params.StorageUsage{}, errors.Annotatef(err, "getting filesystem statistics for %q", mountPoint)
+	}
+	blockSize := uint64(st.Bsize)
+	return params.StorageUsage{
+		UsedBytes:  (st.Blocks - st.Bfree) * blockSize,
+		FreeBytes:  st.Bavail * blockSize,
+		UsedInodes: st.Files - st.Ffree,
+		FreeInodes: st.Ffree,
+	}, nil
+}
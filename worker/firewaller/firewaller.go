@@ -5,6 +5,7 @@ package firewaller
 
 import (
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -39,7 +40,7 @@ type Firewaller struct {
 	serviceds       map[names.ServiceTag]*serviceData
 	exposedChange   chan *exposedChange
 	globalMode      bool
-	globalPortRef   map[network.PortRange]int
+	globalRuleRef   map[network.IngressRule]int
 	machinePorts    map[names.MachineTag]machineRanges
 }
 
@@ -88,7 +89,7 @@ func NewFirewaller(st *apifirewaller.State) (_ worker.Worker, err error) {
 	switch fw.environ.Config().FirewallMode() {
 	case config.FwGlobal:
 		fw.globalMode = true
-		fw.globalPortRef = make(map[network.PortRange]int)
+		fw.globalRuleRef = make(map[network.IngressRule]int)
 	case config.FwNone:
 		logger.Warningf("stopping firewaller - firewall-mode is %q", config.FwNone)
 		return nil, errors.Errorf("firewaller is disabled when firewall-mode is %q", config.FwNone)
@@ -107,6 +108,7 @@ func (fw *Firewaller) loop() error {
 	defer fw.stopWatchers()
 
 	var reconciled bool
+	var reconcileTimer <-chan time.Time
 
 	portsChange := fw.portsWatcher.Changes()
 	for {
@@ -134,16 +136,16 @@ func (fw *Firewaller) loop() error {
 			}
 			if !reconciled {
 				reconciled = true
-				var err error
-				if fw.globalMode {
-					err = fw.reconcileGlobal()
-				} else {
-					err = fw.reconcileInstances()
-				}
-				if err != nil {
+				if err := fw.reconcile(); err != nil {
 					return err
 				}
+				reconcileTimer = fw.reconcileAfter()
 			}
+		case <-reconcileTimer:
+			if err := fw.reconcile(); err != nil {
+				return err
+			}
+			reconcileTimer = fw.reconcileAfter()
 		case change, ok := <-portsChange:
 			if !ok {
 				return watcher.EnsureErr(fw.portsWatcher)
@@ -163,6 +165,7 @@ func (fw *Firewaller) loop() error {
 			}
 		case change := <-fw.exposedChange:
 			change.serviced.exposed = change.exposed
+			change.serviced.sourceCIDRs = change.sourceCIDRs
 			unitds := []*unitData{}
 			for _, unitd := range change.serviced.unitds {
 				unitds = append(unitds, unitd)
@@ -181,7 +184,7 @@ func (fw *Firewaller) startMachine(tag names.MachineTag) error {
 		fw:           fw,
 		tag:          tag,
 		unitds:       make(map[names.UnitTag]*unitData),
-		openedPorts:  make([]network.PortRange, 0),
+		openedRules:  make([]network.IngressRule, 0),
 		definedPorts: make(map[network.PortRange]names.UnitTag),
 	}
 	m, err := machined.machine()
@@ -272,26 +275,48 @@ func (fw *Firewaller) startService(service *apifirewaller.Service) error {
 	if err != nil {
 		return err
 	}
+	sourceCIDRs, err := service.ExposedSourceCIDRs()
+	if err != nil {
+		return err
+	}
 	serviced := &serviceData{
-		fw:      fw,
-		service: service,
-		exposed: exposed,
-		unitds:  make(map[names.UnitTag]*unitData),
+		fw:          fw,
+		service:     service,
+		exposed:     exposed,
+		sourceCIDRs: sourceCIDRs,
+		unitds:      make(map[names.UnitTag]*unitData),
 	}
 	fw.serviceds[service.Tag()] = serviced
-	go serviced.watchLoop(serviced.exposed)
+	go serviced.watchLoop(serviced.exposed, serviced.sourceCIDRs)
 	return nil
 }
 
+// reconcile compares the ports that should be open, according to the
+// watched machines, units and services, with those the provider reports
+// as open, and corrects and records any drift between them. It is run
+// once all machines have been started, and then periodically.
+func (fw *Firewaller) reconcile() error {
+	if fw.globalMode {
+		return fw.reconcileGlobal()
+	}
+	return fw.reconcileInstances()
+}
+
+// reconcileAfter returns a channel that receives when the environment's
+// firewall-reconcile-interval has elapsed.
+func (fw *Firewaller) reconcileAfter() <-chan time.Time {
+	return time.After(fw.environ.Config().FirewallReconcileInterval())
+}
+
 // reconcileGlobal compares the initially started watcher for machines,
 // units and services with the opened and closed ports globally and
 // opens and closes the appropriate ports for the whole environment.
 func (fw *Firewaller) reconcileGlobal() error {
-	initialPortRanges, err := fw.environ.Ports()
+	initialRules, err := fw.globalIngressRules()
 	if err != nil {
 		return err
 	}
-	collector := make(map[network.PortRange]bool)
+	collector := make(map[network.IngressRule]bool)
 	for _, machined := range fw.machineds {
 		for portRange, unitTag := range machined.definedPorts {
 			unitd, known := machined.unitds[unitTag]
@@ -299,31 +324,38 @@ func (fw *Firewaller) reconcileGlobal() error {
 				delete(machined.unitds, unitTag)
 				continue
 			}
-			if unitd.serviced.exposed {
-				collector[portRange] = true
+			for _, rule := range unitd.serviced.ingressRules(portRange) {
+				collector[rule] = true
 			}
 		}
 	}
-	wantedPorts := []network.PortRange{}
-	for port := range collector {
-		wantedPorts = append(wantedPorts, port)
+	wantedRules := []network.IngressRule{}
+	for rule := range collector {
+		wantedRules = append(wantedRules, rule)
 	}
-	// Check which ports to open or to close.
-	toOpen := diffRanges(wantedPorts, initialPortRanges)
-	toClose := diffRanges(initialPortRanges, wantedPorts)
+	// Check which rules to open or to close.
+	toOpen := diffRules(wantedRules, initialRules)
+	toClose := diffRules(initialRules, wantedRules)
 	if len(toOpen) > 0 {
 		logger.Infof("opening global ports %v", toOpen)
-		if err := fw.environ.OpenPorts(toOpen); err != nil {
+		if err := fw.openGlobalRules(toOpen); err != nil {
 			return err
 		}
-		network.SortPortRanges(toOpen)
+		network.SortIngressRules(toOpen)
 	}
 	if len(toClose) > 0 {
 		logger.Infof("closing global ports %v", toClose)
-		if err := fw.environ.ClosePorts(toClose); err != nil {
+		if err := fw.closeGlobalRules(toClose); err != nil {
 			return err
 		}
-		network.SortPortRanges(toClose)
+		network.SortIngressRules(toClose)
+	}
+	envTag, err := fw.st.EnvironTag()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := fw.st.SetFirewallStatus(envTag, wantedRules, initialRules); err != nil {
+		return errors.Annotate(err, "cannot record environment firewall status")
 	}
 	return nil
 }
@@ -343,41 +375,46 @@ func (fw *Firewaller) reconcileInstances() error {
 			return err
 		}
 		instanceId, err := m.InstanceId()
-		if err != nil {
+		if params.IsCodeNotProvisioned(err) {
+			continue
+		} else if err != nil {
 			return err
 		}
 		instances, err := fw.environ.Instances([]instance.Id{instanceId})
 		if err == environs.ErrNoInstances {
-			return nil
+			continue
 		} else if err != nil {
 			return err
 		}
 		machineId := machined.tag.Id()
-		initialPortRanges, err := instances[0].Ports(machineId)
+		initialRules, err := instanceIngressRules(instances[0], machineId)
 		if err != nil {
 			return err
 		}
 
-		// Check which ports to open or to close.
-		toOpen := diffRanges(machined.openedPorts, initialPortRanges)
-		toClose := diffRanges(initialPortRanges, machined.openedPorts)
+		// Check which rules to open or to close.
+		toOpen := diffRules(machined.openedRules, initialRules)
+		toClose := diffRules(initialRules, machined.openedRules)
 		if len(toOpen) > 0 {
 			logger.Infof("opening instance port ranges %v for %q",
 				toOpen, machined.tag)
-			if err := instances[0].OpenPorts(machineId, toOpen); err != nil {
+			if err := openInstanceRules(instances[0], machineId, toOpen); err != nil {
 				// TODO(mue) Add local retry logic.
 				return err
 			}
-			network.SortPortRanges(toOpen)
+			network.SortIngressRules(toOpen)
 		}
 		if len(toClose) > 0 {
 			logger.Infof("closing instance port ranges %v for %q",
 				toClose, machined.tag)
-			if err := instances[0].ClosePorts(machineId, toClose); err != nil {
+			if err := closeInstanceRules(instances[0], machineId, toClose); err != nil {
 				// TODO(mue) Add local retry logic.
 				return err
 			}
-			network.SortPortRanges(toClose)
+			network.SortIngressRules(toClose)
+		}
+		if err := fw.st.SetFirewallStatus(machined.tag, machined.openedRules, initialRules); err != nil {
+			return errors.Annotatef(err, "cannot record firewall status for %q", machined.tag)
 		}
 	}
 	return nil
@@ -498,68 +535,66 @@ func (fw *Firewaller) flushUnits(unitds []*unitData) error {
 
 // flushMachine opens and closes ports for the passed machine.
 func (fw *Firewaller) flushMachine(machined *machineData) error {
-	// Gather ports to open and close.
-	want := []network.PortRange{}
+	// Gather rules to open and close.
+	want := []network.IngressRule{}
 	for portRange, unitTag := range machined.definedPorts {
 		unitd, known := machined.unitds[unitTag]
 		if !known {
 			delete(machined.unitds, unitTag)
 			continue
 		}
-		if unitd.serviced.exposed {
-			want = append(want, portRange)
-		}
+		want = append(want, unitd.serviced.ingressRules(portRange)...)
 	}
-	toOpen := diffRanges(want, machined.openedPorts)
-	toClose := diffRanges(machined.openedPorts, want)
-	machined.openedPorts = want
+	toOpen := diffRules(want, machined.openedRules)
+	toClose := diffRules(machined.openedRules, want)
+	machined.openedRules = want
 	if fw.globalMode {
-		return fw.flushGlobalPorts(toOpen, toClose)
+		return fw.flushGlobalRules(toOpen, toClose)
 	}
-	return fw.flushInstancePorts(machined, toOpen, toClose)
+	return fw.flushInstanceRules(machined, toOpen, toClose)
 }
 
-// flushGlobalPorts opens and closes global ports in the environment.
-// It keeps a reference count for ports so that only 0-to-1 and 1-to-0 events
+// flushGlobalRules opens and closes global ingress rules in the environment.
+// It keeps a reference count for rules so that only 0-to-1 and 1-to-0 events
 // modify the environment.
-func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []network.PortRange) error {
-	// Filter which ports are really to open or close.
-	var toOpen, toClose []network.PortRange
-	for _, portRange := range rawOpen {
-		if fw.globalPortRef[portRange] == 0 {
-			toOpen = append(toOpen, portRange)
+func (fw *Firewaller) flushGlobalRules(rawOpen, rawClose []network.IngressRule) error {
+	// Filter which rules are really to open or close.
+	var toOpen, toClose []network.IngressRule
+	for _, rule := range rawOpen {
+		if fw.globalRuleRef[rule] == 0 {
+			toOpen = append(toOpen, rule)
 		}
-		fw.globalPortRef[portRange]++
+		fw.globalRuleRef[rule]++
 	}
-	for _, portRange := range rawClose {
-		fw.globalPortRef[portRange]--
-		if fw.globalPortRef[portRange] == 0 {
-			toClose = append(toClose, portRange)
-			delete(fw.globalPortRef, portRange)
+	for _, rule := range rawClose {
+		fw.globalRuleRef[rule]--
+		if fw.globalRuleRef[rule] == 0 {
+			toClose = append(toClose, rule)
+			delete(fw.globalRuleRef, rule)
 		}
 	}
-	// Open and close the ports.
+	// Open and close the rules.
 	if len(toOpen) > 0 {
-		if err := fw.environ.OpenPorts(toOpen); err != nil {
+		if err := fw.openGlobalRules(toOpen); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toOpen)
+		network.SortIngressRules(toOpen)
 		logger.Infof("opened port ranges %v in environment", toOpen)
 	}
 	if len(toClose) > 0 {
-		if err := fw.environ.ClosePorts(toClose); err != nil {
+		if err := fw.closeGlobalRules(toClose); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toClose)
+		network.SortIngressRules(toClose)
 		logger.Infof("closed port ranges %v in environment", toClose)
 	}
 	return nil
 }
 
-// flushInstancePorts opens and closes ports global on the machine.
-func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose []network.PortRange) error {
+// flushInstanceRules opens and closes ingress rules on the machine.
+func (fw *Firewaller) flushInstanceRules(machined *machineData, toOpen, toClose []network.IngressRule) error {
 	// If there's nothing to do, do nothing.
 	// This is important because when a machine is first created,
 	// it will have no instance id but also no open ports -
@@ -583,26 +618,120 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 	if err != nil {
 		return err
 	}
-	// Open and close the ports.
+	// Open and close the rules.
 	if len(toOpen) > 0 {
-		if err := instances[0].OpenPorts(machineId, toOpen); err != nil {
+		if err := openInstanceRules(instances[0], machineId, toOpen); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toOpen)
+		network.SortIngressRules(toOpen)
 		logger.Infof("opened port ranges %v on %q", toOpen, machined.tag)
 	}
 	if len(toClose) > 0 {
-		if err := instances[0].ClosePorts(machineId, toClose); err != nil {
+		if err := closeInstanceRules(instances[0], machineId, toClose); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toClose)
+		network.SortIngressRules(toClose)
 		logger.Infof("closed port ranges %v on %q", toClose, machined.tag)
 	}
 	return nil
 }
 
+// globalIngressRules returns the ingress rules opened for the whole
+// environment. If the environment does not support ingress rules,
+// its opened ports are reported as accessible from any address.
+func (fw *Firewaller) globalIngressRules() ([]network.IngressRule, error) {
+	if env, ok := environs.SupportsIngressRules(fw.environ); ok {
+		return env.IngressRules()
+	}
+	ports, err := fw.environ.Ports()
+	if err != nil {
+		return nil, err
+	}
+	return network.NewIngressRules(ports), nil
+}
+
+// openGlobalRules opens the ingress rules for the whole environment.
+func (fw *Firewaller) openGlobalRules(rules []network.IngressRule) error {
+	if env, ok := environs.SupportsIngressRules(fw.environ); ok {
+		return env.OpenIngressRules(rules)
+	}
+	ports := unrestrictedPorts(rules, true)
+	if len(ports) == 0 {
+		return nil
+	}
+	return fw.environ.OpenPorts(ports)
+}
+
+// closeGlobalRules closes the ingress rules for the whole environment.
+func (fw *Firewaller) closeGlobalRules(rules []network.IngressRule) error {
+	if env, ok := environs.SupportsIngressRules(fw.environ); ok {
+		return env.CloseIngressRules(rules)
+	}
+	ports := unrestrictedPorts(rules, false)
+	if len(ports) == 0 {
+		return nil
+	}
+	return fw.environ.ClosePorts(ports)
+}
+
+// instanceIngressRules returns the ingress rules opened on the
+// instance. If the instance does not support ingress rules, its
+// opened ports are reported as accessible from any address.
+func instanceIngressRules(inst instance.Instance, machineId string) ([]network.IngressRule, error) {
+	if inst, ok := inst.(instance.IngressRulesInstance); ok {
+		return inst.IngressRules(machineId)
+	}
+	ports, err := inst.Ports(machineId)
+	if err != nil {
+		return nil, err
+	}
+	return network.NewIngressRules(ports), nil
+}
+
+// openInstanceRules opens the ingress rules on the instance.
+func openInstanceRules(inst instance.Instance, machineId string, rules []network.IngressRule) error {
+	if inst, ok := inst.(instance.IngressRulesInstance); ok {
+		return inst.OpenIngressRules(machineId, rules)
+	}
+	ports := unrestrictedPorts(rules, true)
+	if len(ports) == 0 {
+		return nil
+	}
+	return inst.OpenPorts(machineId, ports)
+}
+
+// closeInstanceRules closes the ingress rules on the instance.
+func closeInstanceRules(inst instance.Instance, machineId string, rules []network.IngressRule) error {
+	if inst, ok := inst.(instance.IngressRulesInstance); ok {
+		return inst.CloseIngressRules(machineId, rules)
+	}
+	ports := unrestrictedPorts(rules, false)
+	if len(ports) == 0 {
+		return nil
+	}
+	return inst.ClosePorts(machineId, ports)
+}
+
+// unrestrictedPorts returns the port ranges of the rules that allow
+// access from any address, for providers that cannot restrict access
+// by source address. Rules restricted to specific source CIDRs are
+// never opened on such providers, so that services exposed only to
+// those CIDRs are not made accessible from any address; if opening
+// is true, an error is logged for each of them.
+func unrestrictedPorts(rules []network.IngressRule, opening bool) []network.PortRange {
+	var ports []network.PortRange
+	for _, rule := range rules {
+		if rule.SourceCIDR == network.AnySourceCIDR {
+			ports = append(ports, rule.PortRange)
+		} else if opening {
+			logger.Errorf("cannot open port range %v: provider does not support source CIDRs", rule)
+		}
+	}
+	return ports
+}
+
 // machineLifeChanged starts watching new machines when the firewaller
 // is starting, or when new machines come to life, and stops watching
 // machines that are dying.
@@ -712,7 +841,7 @@ type machineData struct {
 	fw          *Firewaller
 	tag         names.MachineTag
 	unitds      map[names.UnitTag]*unitData
-	openedPorts []network.PortRange
+	openedRules []network.IngressRule
 	// ports defined by units on this machine
 	definedPorts map[network.PortRange]names.UnitTag
 }
@@ -762,23 +891,37 @@ type unitData struct {
 	machined *machineData
 }
 
-// exposedChange contains the changed exposed flag for one specific service.
+// exposedChange contains the changed exposed flag and source CIDRs
+// for one specific service.
 type exposedChange struct {
-	serviced *serviceData
-	exposed  bool
+	serviced    *serviceData
+	exposed     bool
+	sourceCIDRs []string
 }
 
 // serviceData holds service details and watches exposure changes.
 type serviceData struct {
-	tomb    tomb.Tomb
-	fw      *Firewaller
-	service *apifirewaller.Service
-	exposed bool
-	unitds  map[names.UnitTag]*unitData
+	tomb        tomb.Tomb
+	fw          *Firewaller
+	service     *apifirewaller.Service
+	exposed     bool
+	sourceCIDRs []string
+	unitds      map[names.UnitTag]*unitData
+}
+
+// ingressRules returns the ingress rules needed for the given port
+// range, opened by one of the service's units, to be accessible.
+// No rules are needed if the service is not exposed.
+func (sd *serviceData) ingressRules(portRange network.PortRange) []network.IngressRule {
+	if !sd.exposed {
+		return nil
+	}
+	return network.NewIngressRules([]network.PortRange{portRange}, sd.sourceCIDRs...)
 }
 
-// watchLoop watches the service's exposed flag for changes.
-func (sd *serviceData) watchLoop(exposed bool) {
+// watchLoop watches the service's exposed flag and source CIDRs
+// for changes.
+func (sd *serviceData) watchLoop(exposed bool, sourceCIDRs []string) {
 	defer sd.tomb.Done()
 	w, err := sd.service.Watch()
 	if err != nil {
@@ -806,12 +949,17 @@ func (sd *serviceData) watchLoop(exposed bool) {
 				sd.fw.tomb.Kill(err)
 				return
 			}
-			if change == exposed {
+			changeCIDRs, err := sd.service.ExposedSourceCIDRs()
+			if err != nil {
+				sd.fw.tomb.Kill(err)
+				return
+			}
+			if change == exposed && stringsEqual(changeCIDRs, sourceCIDRs) {
 				continue
 			}
-			exposed = change
+			exposed, sourceCIDRs = change, changeCIDRs
 			select {
-			case sd.fw.exposedChange <- &exposedChange{sd, change}:
+			case sd.fw.exposedChange <- &exposedChange{sd, change, changeCIDRs}:
 			case <-sd.tomb.Dying():
 				return
 			}
@@ -825,8 +973,8 @@ func (sd *serviceData) Stop() error {
 	return sd.tomb.Wait()
 }
 
-// diffRanges returns all the port rangess that exist in A but not B.
-func diffRanges(A, B []network.PortRange) (missing []network.PortRange) {
+// diffRules returns all the ingress rules that exist in A but not B.
+func diffRules(A, B []network.IngressRule) (missing []network.IngressRule) {
 next:
 	for _, a := range A {
 		for _, b := range B {
@@ -839,6 +987,20 @@ next:
 	return
 }
 
+// stringsEqual reports whether a and b hold the same strings
+// in the same order.
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // parsePortsKey parses a ports document global key coming from the
 // ports watcher (e.g. "42:juju-public") and returns the machine and
 // network tags from its components (in the last example "machine-42"
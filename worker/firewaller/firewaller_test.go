@@ -7,12 +7,15 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/juju/errors"
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
 	apifirewaller "github.com/juju/juju/api/firewaller"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju"
@@ -113,6 +116,65 @@ func (s *firewallerBaseSuite) assertEnvironPorts(c *gc.C, expected []network.Por
 	}
 }
 
+// assertIngressRules retrieves the ingress rules opened on the instance,
+// or on the environment if inst is nil, and compares them to the expected.
+func (s *firewallerBaseSuite) assertIngressRules(c *gc.C, inst instance.Instance, machineId string, expected []network.IngressRule) {
+	s.BackingState.StartSync()
+	start := time.Now()
+	for {
+		var got []network.IngressRule
+		var err error
+		if inst == nil {
+			got, err = s.Environ.(environs.IngressRulesEnviron).IngressRules()
+		} else {
+			got, err = inst.(instance.IngressRulesInstance).IngressRules(machineId)
+		}
+		if err != nil {
+			c.Fatal(err)
+			return
+		}
+		network.SortIngressRules(got)
+		network.SortIngressRules(expected)
+		if reflect.DeepEqual(got, expected) {
+			c.Succeed()
+			return
+		}
+		if time.Since(start) > coretesting.LongWait {
+			c.Fatalf("timed out: expected %q; got %q", expected, got)
+			return
+		}
+		time.Sleep(coretesting.ShortWait)
+	}
+}
+
+// setReconcileInterval sets the environment's firewall reconcile
+// interval, in seconds.
+func (s *firewallerBaseSuite) setReconcileInterval(c *gc.C, seconds int) {
+	attrs := map[string]interface{}{config.FirewallReconcileIntervalKey: seconds}
+	err := s.State.UpdateEnvironConfig(attrs, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// assertFirewallDrift waits for the firewall status of the given
+// machine or environment to record the expected drift.
+func (s *firewallerBaseSuite) assertFirewallDrift(c *gc.C, tag names.Tag, unexpected, missing []network.IngressRule) {
+	start := time.Now()
+	for {
+		status, err := s.State.FirewallStatus(tag)
+		if err == nil &&
+			reflect.DeepEqual(status.DriftUnexpected(), unexpected) &&
+			reflect.DeepEqual(status.DriftMissing(), missing) {
+			return
+		} else if err != nil && !errors.IsNotFound(err) {
+			c.Fatal(err)
+		}
+		if time.Since(start) > coretesting.LongWait {
+			c.Fatalf("timed out waiting for firewall drift of %q: unexpected %v; missing %v", tag, unexpected, missing)
+		}
+		time.Sleep(coretesting.ShortWait)
+	}
+}
+
 func (s *firewallerBaseSuite) addUnit(c *gc.C, svc *state.Service) (*state.Unit, *state.Machine) {
 	units, err := juju.AddUnits(s.State, svc, 1, "")
 	c.Assert(err, jc.ErrorIsNil)
@@ -372,6 +434,35 @@ func (s *InstanceModeSuite) TestStartWithUnexposedService(c *gc.C) {
 	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
 }
 
+func (s *InstanceModeSuite) TestReconcileDrift(c *gc.C) {
+	s.setReconcileInterval(c, 1)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err := svc.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
+
+	// Close a port behind the firewaller's back; the next
+	// reconciliation reopens it and records the drift.
+	err = inst.ClosePorts(m.Id(), []network.PortRange{{80, 80, "tcp"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertFirewallDrift(c, m.Tag(),
+		nil,
+		[]network.IngressRule{{network.PortRange{80, 80, "tcp"}, network.AnySourceCIDR}},
+	)
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
+}
+
 func (s *InstanceModeSuite) TestSetClearExposedService(c *gc.C) {
 	fw, err := firewaller.NewFirewaller(s.firewaller)
 	c.Assert(err, jc.ErrorIsNil)
@@ -402,6 +493,47 @@ func (s *InstanceModeSuite) TestSetClearExposedService(c *gc.C) {
 	s.assertPorts(c, inst, m.Id(), nil)
 }
 
+func (s *InstanceModeSuite) TestExposedServiceToSourceCIDRs(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = svc.SetExposedTo([]string{"10.0.0.0/8", "203.0.113.4/32"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"},
+		{network.PortRange{80, 80, "tcp"}, "203.0.113.4/32"},
+	})
+	// The port is not accessible from any address.
+	s.assertPorts(c, inst, m.Id(), nil)
+
+	// Changing the source CIDRs replaces the rules.
+	err = svc.SetExposedTo([]string{"10.0.0.0/8"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"},
+	})
+
+	// Exposing the service to any address opens the port.
+	err = svc.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, network.AnySourceCIDR},
+	})
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
+
+	err = svc.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, inst, m.Id(), nil)
+}
+
 func (s *InstanceModeSuite) TestRemoveUnit(c *gc.C) {
 	fw, err := firewaller.NewFirewaller(s.firewaller)
 	c.Assert(err, jc.ErrorIsNil)
@@ -636,6 +768,41 @@ func (s *GlobalModeSuite) TestGlobalMode(c *gc.C) {
 	s.assertEnvironPorts(c, nil)
 }
 
+func (s *GlobalModeSuite) TestGlobalModeSourceCIDRs(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc1 := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc1.SetExposedTo([]string{"10.0.0.0/8"})
+	c.Assert(err, jc.ErrorIsNil)
+	u1, m1 := s.addUnit(c, svc1)
+	s.startInstance(c, m1)
+	err = u1.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	svc2 := s.AddTestingService(c, "moinmoin", s.charm)
+	err = svc2.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u2, m2 := s.addUnit(c, svc2)
+	s.startInstance(c, m2)
+	err = u2.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertIngressRules(c, nil, "", []network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"},
+		{network.PortRange{80, 80, "tcp"}, network.AnySourceCIDR},
+	})
+
+	// Unexposing one service leaves the other's rule in place.
+	err = svc2.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, nil, "", []network.IngressRule{
+		{network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"},
+	})
+	s.assertEnvironPorts(c, nil)
+}
+
 func (s *GlobalModeSuite) TestStartWithUnexposedService(c *gc.C) {
 	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -662,6 +829,35 @@ func (s *GlobalModeSuite) TestStartWithUnexposedService(c *gc.C) {
 	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp"}})
 }
 
+func (s *GlobalModeSuite) TestReconcileDrift(c *gc.C) {
+	s.setReconcileInterval(c, 1)
+
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, svc)
+	s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp"}})
+
+	// Open a port behind the firewaller's back; the next
+	// reconciliation closes it and records the drift.
+	err = s.Environ.OpenPorts([]network.PortRange{{22, 22, "tcp"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertFirewallDrift(c, s.State.EnvironTag(),
+		[]network.IngressRule{{network.PortRange{22, 22, "tcp"}, network.AnySourceCIDR}},
+		nil,
+	)
+	s.assertEnvironPorts(c, []network.PortRange{{80, 80, "tcp"}})
+}
+
 func (s *GlobalModeSuite) TestRestart(c *gc.C) {
 	// Start firewaller and open ports.
 	fw, err := firewaller.NewFirewaller(s.firewaller)
@@ -83,9 +83,12 @@ func (a *aggregator) loop() error {
 			insts, err := a.environ.Instances(ids)
 			for i, req := range reqs {
 				var reply instanceInfoReply
-				if err != nil && err != environs.ErrPartialInstances {
+				switch {
+				case err == environs.ErrNoInstances:
+					reply.info, reply.err = a.instInfo(req.instId, nil)
+				case err != nil && err != environs.ErrPartialInstances:
 					reply.err = err
-				} else {
+				default:
 					reply.info, reply.err = a.instInfo(req.instId, insts[i])
 				}
 				req.reply <- reply
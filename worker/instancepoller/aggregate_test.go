@@ -209,6 +209,17 @@ func (s *aggregateSuite) TestPartialErrResponse(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *aggregateSuite) TestNoInstancesErrResponse(c *gc.C) {
+	testGetter := new(testInstanceGetter)
+	testGetter.err = environs.ErrNoInstances
+
+	aggregator := newAggregator(testGetter)
+	_, err := aggregator.instanceInfo("foo")
+
+	c.Assert(err, gc.ErrorMatches, "instance foo not found")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *aggregateSuite) TestAddressesError(c *gc.C) {
 	testGetter := new(testInstanceGetter)
 	instance1 := testGetter.newTestInstance("foo", "foobar", []string{"127.0.0.1", "192.168.1.1"})
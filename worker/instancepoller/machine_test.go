@@ -174,6 +174,82 @@ func (s *machineSuite) TestSinglePollWhenInstancInfoUnimplemented(c *gc.C) {
 	c.Assert(count, gc.Equals, int32(1))
 }
 
+func (s *machineSuite) TestResetsTerminatedSpotInstance(c *gc.C) {
+	s.PatchValue(&ShortPoll, coretesting.ShortWait/10)
+	s.PatchValue(&LongPoll, coretesting.ShortWait/10)
+	spot := instance.LifecycleSpot
+	context := &testMachineContext{
+		getInstanceInfo: instanceInfoGetter(c, "i1234", nil, "", errors.NotFoundf("instance i1234")),
+		dyingc:          make(chan struct{}),
+	}
+	m := &testMachine{
+		id:         "99",
+		instanceId: "i1234",
+		instStatus: "running",
+		refresh:    func() error { return nil },
+		life:       state.Alive,
+		hardware:   &instance.HardwareCharacteristics{InstanceLifecycle: &spot},
+	}
+	died := make(chan machine)
+
+	go runMachine(context, m, nil, died)
+	time.Sleep(coretesting.ShortWait)
+
+	killMachineLoop(c, m, context.dyingc, died)
+	c.Assert(context.killAllErr, gc.Equals, nil)
+	c.Assert(m.resetReason, gc.Equals, `spot instance "i1234" terminated by provider`)
+}
+
+func (s *machineSuite) TestDoesNotResetMissingInstance(c *gc.C) {
+	s.PatchValue(&ShortPoll, coretesting.ShortWait/10)
+	s.PatchValue(&LongPoll, coretesting.ShortWait/10)
+	spot := instance.LifecycleSpot
+	onDemand := instance.LifecycleOnDemand
+	for i, test := range []struct {
+		about        string
+		hardware     *instance.HardwareCharacteristics
+		instStatus   string
+		expectStatus string
+	}{{
+		about:        "on-demand instance",
+		hardware:     &instance.HardwareCharacteristics{InstanceLifecycle: &onDemand},
+		instStatus:   "running",
+		expectStatus: state.InstanceStatusMissing,
+	}, {
+		about:        "unknown hardware",
+		instStatus:   "running",
+		expectStatus: state.InstanceStatusMissing,
+	}, {
+		about:    "spot instance never seen",
+		hardware: &instance.HardwareCharacteristics{InstanceLifecycle: &spot},
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		context := &testMachineContext{
+			getInstanceInfo: instanceInfoGetter(c, "i1234", nil, "", errors.NotFoundf("instance i1234")),
+			dyingc:          make(chan struct{}),
+		}
+		m := &testMachine{
+			id:         "99",
+			instanceId: "i1234",
+			instStatus: test.instStatus,
+			refresh:    func() error { return nil },
+			life:       state.Alive,
+			hardware:   test.hardware,
+		}
+		died := make(chan machine)
+
+		go runMachine(context, m, nil, died)
+		time.Sleep(coretesting.ShortWait)
+
+		killMachineLoop(c, m, context.dyingc, died)
+		c.Check(context.killAllErr, gc.Equals, nil)
+		c.Check(m.resetReason, gc.Equals, "")
+		status, err := m.InstanceStatus()
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(status, gc.Equals, test.expectStatus)
+	}
+}
+
 func (*machineSuite) TestChangedRefreshes(c *gc.C) {
 	context := &testMachineContext{
 		getInstanceInfo: instanceInfoGetter(c, "i1234", testAddrs, "running", nil),
@@ -328,11 +404,13 @@ type testMachine struct {
 	status          state.Status
 	refresh         func() error
 	setAddressesErr error
+	hardware        *instance.HardwareCharacteristics
 	// mu protects the following fields.
 	mu              sync.Mutex
 	life            state.Life
 	addresses       []network.Address
 	setAddressCount int
+	resetReason     string
 }
 
 func (m *testMachine) Id() string {
@@ -400,6 +478,23 @@ func (m *testMachine) Refresh() error {
 	return m.refresh()
 }
 
+func (m *testMachine) HardwareCharacteristics() (*instance.HardwareCharacteristics, error) {
+	if m.hardware == nil {
+		return nil, errors.NotFoundf("instance data for machine %v", m.Id())
+	}
+	return m.hardware, nil
+}
+
+func (m *testMachine) ResetProvisioned(reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.instanceId = ""
+	m.instStatus = ""
+	m.addresses = nil
+	m.resetReason = reason
+	return nil
+}
+
 func (m *testMachine) Life() state.Life {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -43,6 +43,8 @@ type machine interface {
 	Life() state.Life
 	Status() (state.StatusInfo, error)
 	IsManual() (bool, error)
+	HardwareCharacteristics() (*instance.HardwareCharacteristics, error)
+	ResetProvisioned(reason string) error
 }
 
 type instanceInfo struct {
@@ -50,6 +52,11 @@ type instanceInfo struct {
 	status    string
 }
 
+// errInstanceTerminated is returned by pollInstanceInfo when the
+// machine's spot instance has been terminated by the provider, and
+// the machine has been reset so that a new instance is provisioned.
+var errInstanceTerminated = errors.New("instance terminated by provider")
+
 type machineContext interface {
 	killAll(err error)
 	instanceInfo(id instance.Id) (instanceInfo, error)
@@ -180,6 +187,10 @@ func machineLoop(context machineContext, m machine, changed <-chan struct{}) err
 	for {
 		if pollInstance {
 			instInfo, err := pollInstanceInfo(context, m)
+			terminated := err == errInstanceTerminated
+			if terminated {
+				err = errors.NotProvisionedf("machine %v", m.Id())
+			}
 			if err != nil && !errors.IsNotProvisioned(err) {
 				// If the provider doesn't implement Addresses/Status now,
 				// it never will until we're upgraded, so don't bother
@@ -210,6 +221,11 @@ func machineLoop(context machineContext, m machine, changed <-chan struct{}) err
 				// until we do.
 				pollInterval = time.Duration(float64(pollInterval) * ShortPollBackoff)
 			}
+			if terminated {
+				// A new instance will be provisioned for the machine;
+				// look for it as we would for any new machine.
+				pollInterval = ShortPoll
+			}
 			pollInstance = false
 		}
 		select {
@@ -245,6 +261,14 @@ func pollInstanceInfo(context machineContext, m machine) (instInfo instanceInfo,
 		if errors.IsNotImplemented(err) {
 			return instInfo, err
 		}
+		if errors.IsNotFound(err) {
+			if terminated, err := resetTerminatedInstance(m, instId); err != nil {
+				logger.Errorf("cannot check for termination of instance %q: %v", instId, err)
+			} else if terminated {
+				return instanceInfo{}, errInstanceTerminated
+			}
+			markInstanceMissing(m, instId)
+		}
 		logger.Warningf("cannot get instance info for instance %q: %v", instId, err)
 		return instInfo, nil
 	}
@@ -271,6 +295,49 @@ func pollInstanceInfo(context machineContext, m machine) (instInfo instanceInfo,
 	return instInfo, err
 }
 
+// resetTerminatedInstance checks whether the given instance, which the
+// provider no longer reports, is a spot instance that the provider has
+// terminated. If so, the machine is reset so that the provisioner will
+// start a new instance for it, and its units are deployed again rather
+// than being left on a dead machine. Instances that have never been
+// seen running are not considered terminated, as the provider may
+// simply not be reporting them yet.
+func resetTerminatedInstance(m machine, instId instance.Id) (bool, error) {
+	hc, err := m.HardwareCharacteristics()
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	if !hc.IsSpot() {
+		return false, nil
+	}
+	if status, err := m.InstanceStatus(); err != nil || status == "" {
+		return false, nil
+	}
+	logger.Infof("spot instance %q of machine %q has been terminated by the provider", instId, m.Id())
+	reason := fmt.Sprintf("spot instance %q terminated by provider", instId)
+	if err := m.ResetProvisioned(reason); err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
+// markInstanceMissing records that the given instance, once seen
+// running, is no longer reported by the provider, so that the machine
+// may be replaced if self-healing is enabled. The instance status is
+// updated as usual should the instance be reported again.
+func markInstanceMissing(m machine, instId instance.Id) {
+	status, err := m.InstanceStatus()
+	if err != nil || status == "" || status == state.InstanceStatusMissing {
+		return
+	}
+	logger.Infof("machine %q instance %q is missing", m.Id(), instId)
+	if err := m.SetInstanceStatus(state.InstanceStatusMissing); err != nil {
+		logger.Errorf("cannot set instance status on %q: %v", m, err)
+	}
+}
+
 // addressesEqual compares the addresses of the machine and the instance information.
 func addressesEqual(a0, a1 []network.Address) bool {
 	if len(a0) != len(a1) {
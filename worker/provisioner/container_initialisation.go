@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
+	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
@@ -273,6 +274,16 @@ func (cs *ContainerSetup) getContainerArtifacts(
 			logger.Errorf("failed to create new kvm broker")
 			return nil, nil, nil, err
 		}
+	case instance.LXD:
+		initialiser = lxd.NewContainerInitialiser()
+		broker, err = NewLxdBroker(cs.provisioner, cs.config, managerConfig)
+		if err != nil {
+			logger.Errorf("failed to create new lxd broker")
+			return nil, nil, nil, err
+		}
+
+		// LXD containers must have the same architecture as the host.
+		toolsFinder = hostArchToolsFinder{toolsFinder}
 	default:
 		return nil, nil, nil, fmt.Errorf("unknown container type: %v", containerType)
 	}
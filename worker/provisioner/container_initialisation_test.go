@@ -174,7 +174,7 @@ func (s *ContainerSetupSuite) TestContainerProvisionerStarted(c *gc.C) {
 			Constraints: s.defaultConstraints,
 		})
 		c.Assert(err, jc.ErrorIsNil)
-		err = m.SetSupportedContainers([]instance.ContainerType{instance.LXC, instance.KVM})
+		err = m.SetSupportedContainers([]instance.ContainerType{instance.LXC, instance.KVM, instance.LXD})
 		c.Assert(err, jc.ErrorIsNil)
 		err = m.SetAgentVersion(version.Current)
 		c.Assert(err, jc.ErrorIsNil)
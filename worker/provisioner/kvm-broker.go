@@ -73,6 +73,7 @@ func (broker *kvmBroker) StartInstance(args environs.StartInstanceParams) (*envi
 
 	series := args.Tools.OneSeries()
 	args.InstanceConfig.MachineContainerType = instance.KVM
+	args.InstanceConfig.Constraints = args.Constraints
 	args.InstanceConfig.Tools = args.Tools[0]
 
 	config, err := broker.api.ContainerConfig()
@@ -108,6 +108,7 @@ func (broker *lxcBroker) StartInstance(args environs.StartInstanceParams) (*envi
 
 	series := archTools.OneSeries()
 	args.InstanceConfig.MachineContainerType = instance.LXC
+	args.InstanceConfig.Constraints = args.Constraints
 	args.InstanceConfig.Tools = archTools[0]
 
 	config, err := broker.api.ContainerConfig()
@@ -153,6 +153,28 @@ func (s *lxcBrokerSuite) TestStartInstance(c *gc.C) {
 	c.Assert(string(containerConfigContents), gc.Not(jc.Contains), "lxc.aa_profile = lxc-container-default-with-mounting")
 }
 
+func (s *lxcBrokerSuite) TestStartInstanceWithConstraints(c *gc.C) {
+	instanceConfig := s.instanceConfig(c, "1/lxc/0")
+	possibleTools := coretools.List{&coretools.Tools{
+		Version: version.MustParseBinary("2.3.4-quantal-amd64"),
+		URL:     "http://tools.testing.invalid/2.3.4-quantal-amd64.tgz",
+	}}
+	result, err := s.broker.StartInstance(environs.StartInstanceParams{
+		Constraints:    constraints.MustParse("mem=512M cpu-cores=1"),
+		Tools:          possibleTools,
+		InstanceConfig: instanceConfig,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*result.Hardware.Mem, gc.Equals, uint64(512))
+	c.Assert(*result.Hardware.CpuCores, gc.Equals, uint64(1))
+
+	// The container's config limits its memory and CPU.
+	containerConfigContents, err := ioutil.ReadFile(filepath.Join(s.LxcDir, string(result.Instance.Id()), "config"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(containerConfigContents), jc.Contains, "lxc.cgroup.memory.limit_in_bytes = 512M\n")
+	c.Assert(string(containerConfigContents), jc.Contains, "lxc.cgroup.cpu.cfs_quota_us = 100000\n")
+}
+
 func (s *lxcBrokerSuite) TestStartInstanceWithStorage(c *gc.C) {
 	s.allowLXCLoopMounts = true
 	machineId := "1/lxc/0"
@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+)
+
+var lxdLogger = loggo.GetLogger("juju.provisioner.lxd")
+
+var _ environs.InstanceBroker = (*lxdBroker)(nil)
+
+func NewLxdBroker(
+	api APICalls,
+	agentConfig agent.Config,
+	managerConfig container.ManagerConfig,
+) (environs.InstanceBroker, error) {
+	manager, err := lxd.NewContainerManager(managerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &lxdBroker{
+		manager:     manager,
+		api:         api,
+		agentConfig: agentConfig,
+	}, nil
+}
+
+type lxdBroker struct {
+	manager     container.Manager
+	api         APICalls
+	agentConfig agent.Config
+}
+
+// StartInstance is specified in the Broker interface.
+func (broker *lxdBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	if args.InstanceConfig.HasNetworks() {
+		return nil, errors.New("starting lxd containers with networks is not supported yet")
+	}
+	machineId := args.InstanceConfig.MachineId
+	lxdLogger.Infof("starting lxd container for machineId: %s", machineId)
+
+	bridgeDevice := broker.agentConfig.Value(agent.LxcBridge)
+	if bridgeDevice == "" {
+		bridgeDevice = lxd.DefaultLxdBridge
+	}
+
+	allocatedInfo, err := maybeAllocateStaticIP(
+		machineId, bridgeDevice, broker.api, args.NetworkInfo,
+	)
+	if err != nil {
+		// It's fine, just ignore it. The effect will be that the
+		// container won't have a static address configured.
+		logger.Infof("not allocating static IP for container %q: %v", machineId, err)
+	} else {
+		args.NetworkInfo = allocatedInfo
+	}
+
+	network := container.BridgeNetworkConfig(bridgeDevice, args.NetworkInfo)
+
+	series := args.Tools.OneSeries()
+	args.InstanceConfig.MachineContainerType = instance.LXD
+	args.InstanceConfig.Constraints = args.Constraints
+	args.InstanceConfig.Tools = args.Tools[0]
+
+	config, err := broker.api.ContainerConfig()
+	if err != nil {
+		lxdLogger.Errorf("failed to get container config: %v", err)
+		return nil, err
+	}
+
+	if err := instancecfg.PopulateInstanceConfig(
+		args.InstanceConfig,
+		config.ProviderType,
+		config.AuthorizedKeys,
+		config.SSLHostnameVerification,
+		config.Proxy,
+		config.AptProxy,
+		config.AptMirror,
+		config.PreferIPv6,
+		config.EnableOSRefreshUpdate,
+		config.EnableOSUpgrade,
+	); err != nil {
+		lxdLogger.Errorf("failed to populate machine config: %v", err)
+		return nil, err
+	}
+
+	storageConfig := &container.StorageConfig{}
+	inst, hardware, err := broker.manager.CreateContainer(args.InstanceConfig, series, network, storageConfig)
+	if err != nil {
+		lxdLogger.Errorf("failed to start container: %v", err)
+		return nil, err
+	}
+	lxdLogger.Infof("started lxd container for machineId: %s, %s, %s", machineId, inst.Id(), hardware.String())
+	return &environs.StartInstanceResult{
+		Instance: inst,
+		Hardware: hardware,
+	}, nil
+}
+
+// StopInstances shuts down the given instances.
+func (broker *lxdBroker) StopInstances(ids ...instance.Id) error {
+	for _, id := range ids {
+		lxdLogger.Infof("stopping lxd container for instance: %s", id)
+		if err := broker.manager.DestroyContainer(id); err != nil {
+			lxdLogger.Errorf("container did not stop: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// AllInstances only returns running containers.
+func (broker *lxdBroker) AllInstances() (result []instance.Instance, err error) {
+	return broker.manager.ListContainers()
+}
@@ -502,11 +502,11 @@ func constructStartInstanceParams(
 			return environs.StartInstanceParams{}, errors.Errorf("volume attachment params specifies instance ID")
 		}
 		volumes[i] = storage.VolumeParams{
-			volumeTag,
-			v.Size,
-			storage.ProviderType(v.Provider),
-			v.Attributes,
-			&storage.VolumeAttachmentParams{
+			Tag:        volumeTag,
+			Size:       v.Size,
+			Provider:   storage.ProviderType(v.Provider),
+			Attributes: v.Attributes,
+			Attachment: &storage.VolumeAttachmentParams{
 				AttachmentParams: storage.AttachmentParams{
 					Machine: machineTag,
 				},
@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package remoterelations implements a worker that synchronises the
+// relations between local services and services consumed from other
+// environments hosted by the same state server.
+package remoterelations
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.remoterelations")
+
+// DefaultSyncInterval is how often relations are synchronised.
+const DefaultSyncInterval = 30 * time.Second
+
+// RemoteRelationsFacade exposes the remote relations functionality
+// needed by the worker.
+type RemoteRelationsFacade interface {
+	RemoteRelations() ([]params.RemoteRelation, error)
+	PublishRelations([]params.RemoteRelation) ([]params.RemoteRelationUnitsResult, error)
+	SetRemoteUnits([]params.RemoteRelationUnits) ([]params.ErrorResult, error)
+}
+
+// New returns a worker that periodically publishes each relation with
+// a remote service, and the local units in scope in it, to the
+// environment offering the remote service; and places in scope the
+// units of the offered service reported in return, as units of the
+// remote service.
+func New(facade RemoteRelationsFacade, syncInterval time.Duration) worker.Worker {
+	return worker.NewPeriodicWorker(func(stop <-chan struct{}) error {
+		return sync(facade)
+	}, syncInterval)
+}
+
+// sync synchronises all relations with remote services. A failure to
+// synchronise one relation does not prevent the others from being
+// synchronised. The relations are published even if there are none,
+// so that the counterparts of removed relations are destroyed.
+func sync(facade RemoteRelationsFacade) error {
+	relations, err := facade.RemoteRelations()
+	if err != nil {
+		return errors.Annotate(err, "cannot get remote relations")
+	}
+	results, err := facade.PublishRelations(relations)
+	if err != nil {
+		return errors.Annotate(err, "cannot publish remote relations")
+	}
+	var args []params.RemoteRelationUnits
+	for i, result := range results {
+		if result.Error != nil {
+			logger.Errorf("cannot publish relation %q: %v", relations[i].Key, result.Error)
+			continue
+		}
+		args = append(args, params.RemoteRelationUnits{
+			Key:   relations[i].Key,
+			Units: result.Units,
+		})
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	errResults, err := facade.SetRemoteUnits(args)
+	if err != nil {
+		return errors.Annotate(err, "cannot set remote units")
+	}
+	for i, result := range errResults {
+		if result.Error != nil {
+			logger.Errorf("cannot set remote units of relation %q: %v", args[i].Key, result.Error)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package remoterelations_test
+
+import (
+	"errors"
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/remoterelations"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type workerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+type fakeFacade struct {
+	relations []params.RemoteRelation
+	published chan []params.RemoteRelation
+	set       chan []params.RemoteRelationUnits
+}
+
+func newFakeFacade(relations ...params.RemoteRelation) *fakeFacade {
+	return &fakeFacade{
+		relations: relations,
+		published: make(chan []params.RemoteRelation, 10),
+		set:       make(chan []params.RemoteRelationUnits, 10),
+	}
+}
+
+func (f *fakeFacade) RemoteRelations() ([]params.RemoteRelation, error) {
+	return f.relations, nil
+}
+
+func (f *fakeFacade) PublishRelations(relations []params.RemoteRelation) ([]params.RemoteRelationUnitsResult, error) {
+	f.published <- relations
+	results := make([]params.RemoteRelationUnitsResult, len(relations))
+	for i, rel := range relations {
+		if rel.OfferName == "bad" {
+			results[i].Error = &params.Error{Message: "offer not found"}
+			continue
+		}
+		results[i].Units = []params.RemoteRelationUnit{{
+			Unit:     rel.RemoteService + "/0",
+			Settings: params.Settings{"host": "10.0.0.1"},
+		}}
+	}
+	return results, nil
+}
+
+func (f *fakeFacade) SetRemoteUnits(args []params.RemoteRelationUnits) ([]params.ErrorResult, error) {
+	f.set <- args
+	return make([]params.ErrorResult, len(args)), nil
+}
+
+func (s *workerSuite) TestSync(c *gc.C) {
+	facade := newFakeFacade(params.RemoteRelation{
+		Key:           "wordpress:db mysql:server",
+		OfferName:     "db",
+		RemoteService: "mysql",
+	}, params.RemoteRelation{
+		Key:           "wordpress:cache memcached:cache",
+		OfferName:     "bad",
+		RemoteService: "memcached",
+	})
+	w := remoterelations.New(facade, time.Hour)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	select {
+	case published := <-facade.published:
+		c.Assert(published, jc.DeepEquals, facade.relations)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("relations not published")
+	}
+	// Only the relation published successfully has its units set.
+	select {
+	case args := <-facade.set:
+		c.Assert(args, jc.DeepEquals, []params.RemoteRelationUnits{{
+			Key: "wordpress:db mysql:server",
+			Units: []params.RemoteRelationUnit{{
+				Unit:     "mysql/0",
+				Settings: params.Settings{"host": "10.0.0.1"},
+			}},
+		}})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("remote units not set")
+	}
+}
+
+func (s *workerSuite) TestNoRemoteRelations(c *gc.C) {
+	facade := newFakeFacade()
+	w := remoterelations.New(facade, time.Hour)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	// The empty list is published, so that the counterparts of
+	// removed relations are destroyed; but no units are set.
+	select {
+	case published := <-facade.published:
+		c.Assert(published, gc.HasLen, 0)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("relations not published")
+	}
+	select {
+	case <-facade.set:
+		c.Fatalf("unexpected set remote units")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+type errorFacade struct {
+	*fakeFacade
+}
+
+func (f errorFacade) RemoteRelations() ([]params.RemoteRelation, error) {
+	return nil, errors.New("boom")
+}
+
+func (s *workerSuite) TestRemoteRelationsError(c *gc.C) {
+	w := remoterelations.New(errorFacade{newFakeFacade()}, time.Hour)
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, "cannot get remote relations: boom")
+}
@@ -0,0 +1,209 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package selfhealer implements a worker that replaces machines whose
+// instances have disappeared, or whose agents have been lost, moving
+// the units of stateless services they host to the new machines.
+package selfhealer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.selfhealer")
+
+// DefaultCheckInterval is how often machines are checked for failure.
+const DefaultCheckInterval = 30 * time.Second
+
+type selfHealer struct {
+	st            *state.State
+	checkInterval time.Duration
+
+	// failedSince records when each machine, keyed by its id, was
+	// first seen to have failed.
+	failedSince map[string]time.Time
+}
+
+// New returns a worker that, when the environment's self-heal setting
+// is enabled, periodically checks for machines whose instances are
+// missing or whose agents are lost. Once a machine has failed for
+// longer than the environment's self-heal-grace-period, and all the
+// units it hosts belong to stateless services, it is replaced with a
+// new machine with the same constraints, to which its units are moved.
+func New(st *state.State, checkInterval time.Duration) worker.Worker {
+	w := &selfHealer{
+		st:            st,
+		checkInterval: checkInterval,
+		failedSince:   make(map[string]time.Time),
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+func (w *selfHealer) loop(stopCh <-chan struct{}) error {
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(w.checkInterval):
+			if err := w.check(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+func (w *selfHealer) check() error {
+	cfg, err := w.st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !cfg.SelfHeal() {
+		w.failedSince = make(map[string]time.Time)
+		return nil
+	}
+	grace := cfg.SelfHealGracePeriod()
+
+	machines, err := w.st.AllMachines()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	failed := make(map[string]time.Time)
+	for _, m := range machines {
+		reason, err := failure(m)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if reason == "" {
+			continue
+		}
+		since, ok := w.failedSince[m.Id()]
+		if !ok {
+			since = time.Now()
+		}
+		failed[m.Id()] = since
+		if time.Since(since) < grace {
+			continue
+		}
+		// A failure to replace one machine should not prevent the
+		// others from being replaced.
+		if err := w.replace(m, reason); err != nil {
+			logger.Errorf("cannot replace machine %s: %v", m, err)
+		}
+	}
+	w.failedSince = failed
+	return nil
+}
+
+// failure returns why the machine is considered to have failed, or
+// the empty string if it has not.
+func failure(m *state.Machine) (string, error) {
+	if m.Life() != state.Alive || m.IsManager() || m.ContainerType() != "" {
+		return "", nil
+	}
+	instId, err := m.InstanceId()
+	if errors.IsNotProvisioned(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	instStatus, err := m.InstanceStatus()
+	if err != nil && !errors.IsNotProvisioned(err) {
+		return "", errors.Trace(err)
+	}
+	if instStatus == state.InstanceStatusMissing {
+		return fmt.Sprintf("instance %q is missing", instId), nil
+	}
+	status, err := m.Status()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if status.Status == state.StatusLost {
+		return "agent is lost", nil
+	}
+	return "", nil
+}
+
+// replace replaces the machine if it hosts units, all of them
+// belonging to stateless services whose placement policies allow it,
+// and no containers. Manually provisioned machines, and machines that
+// may not be removed or changed because of a block, are never
+// replaced.
+func (w *selfHealer) replace(m *state.Machine, reason string) error {
+	if manual, err := m.IsManual(); err != nil || manual {
+		return errors.Trace(err)
+	}
+	containers, err := m.Containers()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(containers) > 0 {
+		logger.Debugf("not replacing machine %s: it hosts containers", m)
+		return nil
+	}
+	units, err := m.Units()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var services []*state.Service
+	for _, unit := range units {
+		if !unit.IsPrincipal() {
+			continue
+		}
+		service, err := unit.Service()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !service.IsStateless() {
+			logger.Debugf("not replacing machine %s: service %q is not stateless", m, service)
+			return nil
+		}
+		services = append(services, service)
+	}
+	if len(services) == 0 {
+		return nil
+	}
+	if blocked, err := w.blocked(m, services); err != nil {
+		return errors.Trace(err)
+	} else if blocked {
+		logger.Debugf("not replacing machine %s: it, or the services of its units, may not be changed", m)
+		return nil
+	}
+	replacement, err := w.st.ReplaceMachine(m, reason)
+	if state.IsPlacementPolicyError(err) {
+		logger.Debugf("not replacing machine %s: %v", m, err)
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("replaced machine %s with machine %s: %s", m, replacement, reason)
+	return nil
+}
+
+// blocked returns whether replacing the machine is prevented by a
+// block: on removals or changes in the environment or to the machine,
+// which would be destroyed, or on changes to the given services, whose
+// units would be moved.
+func (w *selfHealer) blocked(m *state.Machine, services []*state.Service) (bool, error) {
+	for _, t := range []state.BlockType{state.RemoveBlock, state.ChangeBlock} {
+		if _, blocked, err := w.st.GetBlockForType(t); err != nil || blocked {
+			return blocked, errors.Trace(err)
+		}
+		if _, blocked, err := w.st.GetBlockForEntity(t, m.Tag()); err != nil || blocked {
+			return blocked, errors.Trace(err)
+		}
+	}
+	for _, service := range services {
+		if _, blocked, err := w.st.GetBlockForEntity(state.ChangeBlock, service.Tag()); err != nil || blocked {
+			return blocked, errors.Trace(err)
+		}
+	}
+	return false, nil
+}
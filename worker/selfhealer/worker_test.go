@@ -0,0 +1,169 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package selfhealer_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/selfhealer"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type selfHealerSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&selfHealerSuite{})
+
+func (s *selfHealerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		config.SelfHealKey:            true,
+		config.SelfHealGracePeriodKey: 1,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *selfHealerSuite) startWorker(c *gc.C) {
+	w := selfhealer.New(s.State, 10*time.Millisecond)
+	s.AddCleanup(func(c *gc.C) { c.Assert(worker.Stop(w), jc.ErrorIsNil) })
+}
+
+// addFailedUnit adds a unit of the named service to a new machine,
+// whose instance is then reported missing.
+func (s *selfHealerSuite) addFailedUnit(c *gc.C, serviceName string, stateless bool) (*state.Machine, *state.Unit) {
+	service := s.Factory.MakeService(c, &factory.ServiceParams{
+		Name:  serviceName,
+		Charm: s.AddTestingCharm(c, "wordpress"),
+	})
+	err := service.SetStateless(stateless)
+	c.Assert(err, jc.ErrorIsNil)
+	machine := s.Factory.MakeMachine(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: service, Machine: machine})
+	err = machine.SetInstanceStatus(state.InstanceStatusMissing)
+	c.Assert(err, jc.ErrorIsNil)
+	return machine, unit
+}
+
+func (s *selfHealerSuite) waitReplaced(c *gc.C, machine *state.Machine, unit *state.Unit) string {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		err := unit.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		machineId, err := unit.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		if machineId != machine.Id() {
+			return machineId
+		}
+	}
+	c.Fatalf("timed out waiting for machine %s to be replaced", machine)
+	return ""
+}
+
+func (s *selfHealerSuite) TestReplacesFailedMachine(c *gc.C) {
+	machine, unit := s.addFailedUnit(c, "stateless", true)
+	statefulMachine, statefulUnit := s.addFailedUnit(c, "stateful", false)
+	s.startWorker(c)
+
+	replacementId := s.waitReplaced(c, machine, unit)
+	replacement, err := s.State.Machine(replacementId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(replacement.Life(), gc.Equals, state.Alive)
+	_, err = replacement.InstanceId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	history, err := s.State.SelfHealHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	instId, err := machine.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history[0].MachineId, gc.Equals, machine.Id())
+	c.Assert(history[0].ReplacementId, gc.Equals, replacementId)
+	c.Assert(history[0].Units, jc.DeepEquals, []string{unit.Name()})
+	c.Assert(history[0].Reason, gc.Equals, `instance "`+string(instId)+`" is missing`)
+
+	// The machine hosting a unit of a service that is not stateless
+	// failed at the same time, but is left alone.
+	err = statefulUnit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := statefulUnit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, statefulMachine.Id())
+}
+
+func (s *selfHealerSuite) TestReplacesMachineWithLostAgent(c *gc.C) {
+	machine, unit := s.addFailedUnit(c, "stateless", true)
+	err := machine.SetInstanceStatus("running")
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetAgentLost("agent is not communicating with the server")
+	c.Assert(err, jc.ErrorIsNil)
+	s.startWorker(c)
+
+	s.waitReplaced(c, machine, unit)
+	history, err := s.State.SelfHealHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Reason, gc.Equals, "agent is lost")
+}
+
+func (s *selfHealerSuite) assertNotReplaced(c *gc.C, machine *state.Machine, unit *state.Unit) {
+	err := unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, machine.Id())
+}
+
+func (s *selfHealerSuite) TestSkipsBlockedMachines(c *gc.C) {
+	removeBlocked, removeBlockedUnit := s.addFailedUnit(c, "remove-blocked", true)
+	err := s.State.SwitchBlockOnWithArgs(state.RemoveBlock, state.BlockArgs{Entity: removeBlocked.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+	changeBlocked, changeBlockedUnit := s.addFailedUnit(c, "change-blocked", true)
+	service, err := changeBlockedUnit.Service()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SwitchBlockOnWithArgs(state.ChangeBlock, state.BlockArgs{Entity: service.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+	// The last machine is checked after the others.
+	machine, unit := s.addFailedUnit(c, "stateless", true)
+	s.startWorker(c)
+
+	s.waitReplaced(c, machine, unit)
+	s.assertNotReplaced(c, removeBlocked, removeBlockedUnit)
+	s.assertNotReplaced(c, changeBlocked, changeBlockedUnit)
+}
+
+func (s *selfHealerSuite) TestSkipsMachinesViolatingPlacementPolicy(c *gc.C) {
+	affine, affineUnit := s.addFailedUnit(c, "affine", true)
+	s.Factory.MakeService(c, &factory.ServiceParams{
+		Name:  "other",
+		Charm: s.AddTestingCharm(c, "wordpress"),
+	})
+	service, err := affineUnit.Service()
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.SetPlacementPolicy(state.PlacementPolicy{AffinityService: "other"})
+	c.Assert(err, jc.ErrorIsNil)
+	machine, unit := s.addFailedUnit(c, "stateless", true)
+	s.startWorker(c)
+
+	s.waitReplaced(c, machine, unit)
+	s.assertNotReplaced(c, affine, affineUnit)
+	history, err := s.State.SelfHealHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+}
@@ -9,8 +9,30 @@ import (
 	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
 )
 
+// machineBlockDevices returns the block devices on the scoped machine,
+// from which machine-scoped volume sources may create volumes. No block
+// devices are returned for environment-scoped storage provisioners.
+func machineBlockDevices(ctx *context) ([]storage.BlockDevice, error) {
+	machineTag, ok := ctx.scope.(names.MachineTag)
+	if !ok {
+		return nil, nil
+	}
+	results, err := ctx.volumeAccessor.MachineBlockDevices([]names.MachineTag{machineTag})
+	if err != nil {
+		return nil, errors.Annotate(err, "getting machine block devices")
+	}
+	if results[0].Error != nil {
+		return nil, errors.Annotatef(
+			results[0].Error, "getting block devices for %s",
+			names.ReadableString(machineTag),
+		)
+	}
+	return results[0].Result, nil
+}
+
 // machineBlockDevicesChanged is called when the block devices of the scoped
 // machine have been seen to have changed. This triggers a refresh of all
 // block devices for attached volumes backing pending filesystems.
@@ -119,7 +119,8 @@ func removeAttachments(ctx *context, ids []params.MachineStorageId) error {
 var errNonDynamic = errors.New("non-dynamic storage provider")
 
 // volumeSource returns a volume source given a name, provider type,
-// environment config and storage directory.
+// environment config, storage directory and the block devices of the
+// machine, if known.
 //
 // TODO(axw) move this to the main storageprovisioner, and have
 // it watch for changes to storage source configurations, updating
@@ -128,10 +129,11 @@ var errNonDynamic = errors.New("non-dynamic storage provider")
 func volumeSource(
 	environConfig *config.Config,
 	baseStorageDir string,
+	blockDevices []storage.BlockDevice,
 	sourceName string,
 	providerType storage.ProviderType,
 ) (storage.VolumeSource, error) {
-	provider, sourceConfig, err := sourceParams(providerType, sourceName, baseStorageDir)
+	provider, sourceConfig, err := sourceParams(providerType, sourceName, baseStorageDir, blockDevices)
 	if err != nil {
 		return nil, errors.Annotatef(err, "getting storage source %q params", sourceName)
 	}
@@ -158,7 +160,7 @@ func filesystemSource(
 	sourceName string,
 	providerType storage.ProviderType,
 ) (storage.FilesystemSource, error) {
-	provider, sourceConfig, err := sourceParams(providerType, sourceName, baseStorageDir)
+	provider, sourceConfig, err := sourceParams(providerType, sourceName, baseStorageDir, nil)
 	if err != nil {
 		return nil, errors.Annotatef(err, "getting storage source %q params", sourceName)
 	}
@@ -169,7 +171,11 @@ func filesystemSource(
 	return source, nil
 }
 
-func sourceParams(providerType storage.ProviderType, sourceName, baseStorageDir string) (storage.Provider, *storage.Config, error) {
+func sourceParams(
+	providerType storage.ProviderType,
+	sourceName, baseStorageDir string,
+	blockDevices []storage.BlockDevice,
+) (storage.Provider, *storage.Config, error) {
 	provider, err := registry.StorageProvider(providerType)
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "getting provider")
@@ -179,6 +185,9 @@ func sourceParams(providerType storage.ProviderType, sourceName, baseStorageDir
 		storageDir := filepath.Join(baseStorageDir, sourceName)
 		attrs[storage.ConfigStorageDir] = storageDir
 	}
+	if blockDevices != nil {
+		attrs[storage.ConfigBlockDevices] = blockDevices
+	}
 	sourceConfig, err := storage.NewConfig(sourceName, providerType, attrs)
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "getting config")
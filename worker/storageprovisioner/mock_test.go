@@ -115,13 +115,22 @@ type mockVolumeAccessor struct {
 	volumesWatcher         *mockStringsWatcher
 	attachmentsWatcher     *mockAttachmentsWatcher
 	blockDevicesWatcher    *mockNotifyWatcher
+	resizesWatcher         *mockStringsWatcher
+	snapshotsWatcher       *mockStringsWatcher
 	provisionedMachines    map[string]instance.Id
 	provisionedVolumes     map[string]params.Volume
 	provisionedAttachments map[params.MachineStorageId]params.VolumeAttachment
 	blockDevices           map[params.MachineStorageId]storage.BlockDevice
+	machineBlockDevices    []storage.BlockDevice
+	pendingResizes         map[string]uint64
+	snapshots              map[string]params.SnapshotParams
 
 	setVolumeInfo           func([]params.Volume) ([]params.ErrorResult, error)
 	setVolumeAttachmentInfo func([]params.VolumeAttachment) ([]params.ErrorResult, error)
+	failVolumeResizes       func([]params.VolumeResizeFailure) ([]params.ErrorResult, error)
+	setSnapshotInfo         func([]params.Snapshot) ([]params.ErrorResult, error)
+	setSnapshotFailures     func([]params.SnapshotFailure) ([]params.ErrorResult, error)
+	removeSnapshots         func([]string) ([]params.ErrorResult, error)
 }
 
 func (w *mockVolumeAccessor) WatchVolumes() (apiwatcher.StringsWatcher, error) {
@@ -132,6 +141,14 @@ func (w *mockVolumeAccessor) WatchVolumeAttachments() (apiwatcher.MachineStorage
 	return w.attachmentsWatcher, nil
 }
 
+func (w *mockVolumeAccessor) WatchVolumeResizes() (apiwatcher.StringsWatcher, error) {
+	return w.resizesWatcher, nil
+}
+
+func (w *mockVolumeAccessor) WatchSnapshots() (apiwatcher.StringsWatcher, error) {
+	return w.snapshotsWatcher, nil
+}
+
 func (w *mockVolumeAccessor) WatchBlockDevices(tag names.MachineTag) (apiwatcher.NotifyWatcher, error) {
 	return w.blockDevicesWatcher, nil
 }
@@ -164,6 +181,14 @@ func (v *mockVolumeAccessor) VolumeAttachments(ids []params.MachineStorageId) ([
 	return result, nil
 }
 
+func (v *mockVolumeAccessor) MachineBlockDevices(tags []names.MachineTag) ([]params.BlockDevicesResult, error) {
+	result := make([]params.BlockDevicesResult, len(tags))
+	for i := range tags {
+		result[i].Result = v.machineBlockDevices
+	}
+	return result, nil
+}
+
 func (v *mockVolumeAccessor) VolumeBlockDevices(ids []params.MachineStorageId) ([]params.BlockDeviceResult, error) {
 	var result []params.BlockDeviceResult
 	for _, id := range ids {
@@ -227,6 +252,57 @@ func (v *mockVolumeAccessor) VolumeAttachmentParams(ids []params.MachineStorageI
 	return result, nil
 }
 
+func (v *mockVolumeAccessor) VolumeResizeParams(volumes []names.VolumeTag) ([]params.VolumeResizeParamsResult, error) {
+	var result []params.VolumeResizeParamsResult
+	for _, tag := range volumes {
+		size, ok := v.pendingResizes[tag.String()]
+		if !ok {
+			result = append(result, params.VolumeResizeParamsResult{
+				Error: common.ServerError(errors.NotFoundf("pending resize for volume %q", tag.Id())),
+			})
+			continue
+		}
+		result = append(result, params.VolumeResizeParamsResult{Result: params.VolumeResizeParams{
+			VolumeTag: tag.String(),
+			VolumeId:  v.provisionedVolumes[tag.String()].VolumeId,
+			Size:      size,
+			Provider:  "dummy",
+		}})
+	}
+	return result, nil
+}
+
+func (v *mockVolumeAccessor) SnapshotParams(ids []string) ([]params.SnapshotParamsResult, error) {
+	var result []params.SnapshotParamsResult
+	for _, id := range ids {
+		snapshotParams, ok := v.snapshots[id]
+		if !ok {
+			result = append(result, params.SnapshotParamsResult{
+				Error: common.ServerError(errors.NotFoundf("snapshot %q", id)),
+			})
+			continue
+		}
+		result = append(result, params.SnapshotParamsResult{Result: snapshotParams})
+	}
+	return result, nil
+}
+
+func (v *mockVolumeAccessor) SetSnapshotInfo(snapshots []params.Snapshot) ([]params.ErrorResult, error) {
+	return v.setSnapshotInfo(snapshots)
+}
+
+func (v *mockVolumeAccessor) SetSnapshotFailures(failures []params.SnapshotFailure) ([]params.ErrorResult, error) {
+	return v.setSnapshotFailures(failures)
+}
+
+func (v *mockVolumeAccessor) RemoveSnapshots(ids []string) ([]params.ErrorResult, error) {
+	return v.removeSnapshots(ids)
+}
+
+func (v *mockVolumeAccessor) FailVolumeResizes(failures []params.VolumeResizeFailure) ([]params.ErrorResult, error) {
+	return v.failVolumeResizes(failures)
+}
+
 func (v *mockVolumeAccessor) SetVolumeInfo(volumes []params.Volume) ([]params.ErrorResult, error) {
 	return v.setVolumeInfo(volumes)
 }
@@ -240,10 +316,14 @@ func newMockVolumeAccessor() *mockVolumeAccessor {
 		volumesWatcher:         &mockStringsWatcher{make(chan []string, 1)},
 		attachmentsWatcher:     &mockAttachmentsWatcher{make(chan []params.MachineStorageId, 1)},
 		blockDevicesWatcher:    &mockNotifyWatcher{make(chan struct{}, 1)},
+		resizesWatcher:         &mockStringsWatcher{make(chan []string, 1)},
+		snapshotsWatcher:       &mockStringsWatcher{make(chan []string, 1)},
 		provisionedMachines:    make(map[string]instance.Id),
 		provisionedVolumes:     make(map[string]params.Volume),
 		provisionedAttachments: make(map[params.MachineStorageId]params.VolumeAttachment),
 		blockDevices:           make(map[params.MachineStorageId]storage.BlockDevice),
+		pendingResizes:         make(map[string]uint64),
+		snapshots:              make(map[string]params.SnapshotParams),
 	}
 }
 
@@ -413,6 +493,14 @@ type dummyVolumeSource struct {
 	storage.VolumeSource
 }
 
+type dummyResizableVolumeSource struct {
+	dummyVolumeSource
+}
+
+type dummySnapshottingVolumeSource struct {
+	dummyVolumeSource
+}
+
 type dummyFilesystemSource struct {
 	storage.FilesystemSource
 }
@@ -482,6 +570,38 @@ func (*dummyVolumeSource) AttachVolumes(params []storage.VolumeAttachmentParams)
 	return volumeAttachments, nil
 }
 
+// ResizeVolumes grows volumes to the requested size.
+func (*dummyResizableVolumeSource) ResizeVolumes(params []storage.VolumeResizeParams) ([]storage.Volume, error) {
+	var volumes []storage.Volume
+	for _, p := range params {
+		volumes = append(volumes, storage.Volume{
+			Tag:      p.Tag,
+			VolumeId: p.VolumeId,
+			Size:     p.Size,
+		})
+	}
+	return volumes, nil
+}
+
+// CreateSnapshots takes snapshots of volumes.
+func (*dummySnapshottingVolumeSource) CreateSnapshots(params []storage.SnapshotParams) ([]storage.Snapshot, error) {
+	var snapshots []storage.Snapshot
+	for _, p := range params {
+		snapshots = append(snapshots, storage.Snapshot{
+			Id:         p.Id,
+			Volume:     p.Volume,
+			SnapshotId: "snap-" + p.VolumeId,
+			Size:       p.Size,
+		})
+	}
+	return snapshots, nil
+}
+
+// DestroySnapshots destroys snapshots.
+func (*dummySnapshottingVolumeSource) DestroySnapshots(snapshotIds []string) []error {
+	return make([]error, len(snapshotIds))
+}
+
 func (*dummyFilesystemSource) ValidateFilesystemParams(params storage.FilesystemParams) error {
 	return nil
 }
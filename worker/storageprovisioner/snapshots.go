@@ -0,0 +1,314 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+)
+
+// snapshotsChanged is called when the lifecycle states of the snapshots
+// with the provided IDs have been seen to have changed.
+func snapshotsChanged(ctx *context, changes []string) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	paramsResults, err := ctx.volumeAccessor.SnapshotParams(changes)
+	if err != nil {
+		return errors.Annotate(err, "getting snapshot parameters")
+	}
+	var alive []storage.SnapshotParams
+	var dying []params.SnapshotParams
+	var failures []params.SnapshotFailure
+	for i, result := range paramsResults {
+		if params.IsCodeNotFound(result.Error) {
+			// The snapshot has already been removed.
+			logger.Debugf("snapshot %q not found, nothing to do", changes[i])
+			continue
+		} else if result.Error != nil {
+			return errors.Annotatef(
+				result.Error, "getting parameters for snapshot %q", changes[i],
+			)
+		}
+		if result.Result.Life != params.Alive {
+			dying = append(dying, result.Result)
+			continue
+		}
+		if result.Result.SnapshotId != "" {
+			// The snapshot has already been taken.
+			continue
+		}
+		if result.Result.Failure != "" {
+			// The snapshot could not be taken, and is
+			// not retried; it may only be destroyed.
+			continue
+		}
+		snapshotParams, err := snapshotParamsFromParams(result.Result)
+		if err != nil {
+			return errors.Annotate(err, "getting snapshot parameters")
+		}
+		if snapshotParams.VolumeId == "" {
+			failures = appendSnapshotFailure(
+				failures, snapshotParams.Id,
+				errors.NotProvisionedf("volume %s", snapshotParams.Volume.Id()),
+			)
+			continue
+		}
+		alive = append(alive, snapshotParams)
+	}
+	if err := setSnapshotFailures(ctx, failures); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Debugf("snapshots to take: %v, to destroy: %v", len(alive), len(dying))
+	if err := processDyingSnapshots(ctx, dying); err != nil {
+		return errors.Annotate(err, "destroying snapshots")
+	}
+	if err := processAliveSnapshots(ctx, alive); err != nil {
+		return errors.Annotate(err, "taking snapshots")
+	}
+	return nil
+}
+
+// processAliveSnapshots takes the snapshots with the specified
+// parameters, and records the results in state.
+func processAliveSnapshots(ctx *context, snapshotParams []storage.SnapshotParams) error {
+	if len(snapshotParams) == 0 {
+		return nil
+	}
+	snapshots, failures, err := createSnapshots(ctx.environConfig, ctx.storageDir, snapshotParams)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := setSnapshotFailures(ctx, failures); err != nil {
+		return errors.Trace(err)
+	}
+	if len(snapshots) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.volumeAccessor.SetSnapshotInfo(snapshotsFromStorage(snapshots))
+	if err != nil {
+		return errors.Annotate(err, "publishing snapshots to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(
+				result.Error, "publishing snapshot %s to state",
+				snapshots[i].Id,
+			)
+		}
+	}
+	return nil
+}
+
+// processDyingSnapshots destroys the snapshots with the specified
+// parameters, and then removes them from state. Snapshots that were
+// never taken are removed from state immediately.
+func processDyingSnapshots(ctx *context, snapshotParams []params.SnapshotParams) error {
+	if len(snapshotParams) == 0 {
+		return nil
+	}
+	remove := make([]string, 0, len(snapshotParams))
+	destroy := make([]params.SnapshotParams, 0, len(snapshotParams))
+	for _, p := range snapshotParams {
+		if p.SnapshotId == "" {
+			remove = append(remove, p.Id)
+		} else {
+			destroy = append(destroy, p)
+		}
+	}
+	destroyed, failures, err := destroySnapshots(ctx.environConfig, ctx.storageDir, destroy)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := setSnapshotFailures(ctx, failures); err != nil {
+		return errors.Trace(err)
+	}
+	remove = append(remove, destroyed...)
+	if len(remove) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.volumeAccessor.RemoveSnapshots(remove)
+	if err != nil {
+		return errors.Annotate(err, "removing snapshots from state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(
+				result.Error, "removing snapshot %s from state", remove[i],
+			)
+		}
+	}
+	return nil
+}
+
+// createSnapshots takes snapshots with the specified parameters.
+// Snapshots whose sources do not support snapshotting, or which
+// fail to be taken, are omitted from the snapshots taken, and
+// returned as failures.
+func createSnapshots(
+	environConfig *config.Config,
+	baseStorageDir string,
+	args []storage.SnapshotParams,
+) ([]storage.Snapshot, []params.SnapshotFailure, error) {
+	var failures []params.SnapshotFailure
+	paramsBySource := make(map[string][]storage.SnapshotParams)
+	snapshotters := make(map[string]storage.VolumeSnapshotter)
+	for _, arg := range args {
+		sourceName := string(arg.Provider)
+		snapshotter, err := snapshotterForSource(
+			environConfig, baseStorageDir, sourceName, arg.Provider, snapshotters,
+		)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if snapshotter == nil {
+			failures = appendSnapshotFailure(failures, arg.Id, errors.NotSupportedf(
+				"snapshots with storage provider %q", arg.Provider,
+			))
+			continue
+		}
+		paramsBySource[sourceName] = append(paramsBySource[sourceName], arg)
+	}
+	var allSnapshots []storage.Snapshot
+	for sourceName, args := range paramsBySource {
+		snapshots, err := snapshotters[sourceName].CreateSnapshots(args)
+		if err != nil {
+			for _, arg := range args {
+				failures = appendSnapshotFailure(failures, arg.Id, err)
+			}
+			continue
+		}
+		allSnapshots = append(allSnapshots, snapshots...)
+	}
+	return allSnapshots, failures, nil
+}
+
+// destroySnapshots destroys the snapshots with the specified parameters,
+// returning the IDs of the snapshots that were destroyed. Snapshots that
+// fail to be destroyed are omitted from the results, and returned as
+// failures.
+func destroySnapshots(
+	environConfig *config.Config,
+	baseStorageDir string,
+	snapshotParams []params.SnapshotParams,
+) ([]string, []params.SnapshotFailure, error) {
+	var failures []params.SnapshotFailure
+	idsBySource := make(map[string][]string)
+	snapshotIdsBySource := make(map[string][]string)
+	snapshotters := make(map[string]storage.VolumeSnapshotter)
+	for _, p := range snapshotParams {
+		sourceName := p.Provider
+		snapshotter, err := snapshotterForSource(
+			environConfig, baseStorageDir, sourceName,
+			storage.ProviderType(p.Provider), snapshotters,
+		)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if snapshotter == nil {
+			failures = appendSnapshotFailure(failures, p.Id, errors.NotSupportedf(
+				"snapshots with storage provider %q", p.Provider,
+			))
+			continue
+		}
+		idsBySource[sourceName] = append(idsBySource[sourceName], p.Id)
+		snapshotIdsBySource[sourceName] = append(snapshotIdsBySource[sourceName], p.SnapshotId)
+	}
+	var destroyed []string
+	for sourceName, snapshotIds := range snapshotIdsBySource {
+		errs := snapshotters[sourceName].DestroySnapshots(snapshotIds)
+		for i, err := range errs {
+			id := idsBySource[sourceName][i]
+			if err != nil {
+				failures = appendSnapshotFailure(failures, id, err)
+				continue
+			}
+			destroyed = append(destroyed, id)
+		}
+	}
+	return destroyed, failures, nil
+}
+
+// appendSnapshotFailure logs the failure of the snapshot with the
+// specified ID, and appends it to the given failures.
+func appendSnapshotFailure(failures []params.SnapshotFailure, id string, err error) []params.SnapshotFailure {
+	logger.Errorf("snapshot %s failed: %v", id, err)
+	return append(failures, params.SnapshotFailure{Id: id, Reason: err.Error()})
+}
+
+// setSnapshotFailures records the reasons that snapshots could not be
+// taken or destroyed in state.
+func setSnapshotFailures(ctx *context, failures []params.SnapshotFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.volumeAccessor.SetSnapshotFailures(failures)
+	if err != nil {
+		return errors.Annotate(err, "publishing snapshot failures to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(
+				result.Error, "publishing failure of snapshot %s to state",
+				failures[i].Id,
+			)
+		}
+	}
+	return nil
+}
+
+// snapshotterForSource returns the VolumeSnapshotter for the named
+// source, caching it in the supplied map. If the source does not
+// support snapshots, a nil VolumeSnapshotter is returned.
+func snapshotterForSource(
+	environConfig *config.Config,
+	baseStorageDir string,
+	sourceName string,
+	providerType storage.ProviderType,
+	snapshotters map[string]storage.VolumeSnapshotter,
+) (storage.VolumeSnapshotter, error) {
+	if snapshotter, ok := snapshotters[sourceName]; ok {
+		return snapshotter, nil
+	}
+	volumeSource, err := volumeSource(
+		environConfig, baseStorageDir, nil, sourceName, providerType,
+	)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting volume source")
+	}
+	snapshotter, _ := volumeSource.(storage.VolumeSnapshotter)
+	snapshotters[sourceName] = snapshotter
+	return snapshotter, nil
+}
+
+func snapshotsFromStorage(in []storage.Snapshot) []params.Snapshot {
+	out := make([]params.Snapshot, len(in))
+	for i, s := range in {
+		out[i] = params.Snapshot{
+			Id:         s.Id,
+			VolumeTag:  s.Volume.String(),
+			SnapshotId: s.SnapshotId,
+		}
+	}
+	return out
+}
+
+func snapshotParamsFromParams(in params.SnapshotParams) (storage.SnapshotParams, error) {
+	volumeTag, err := names.ParseVolumeTag(in.VolumeTag)
+	if err != nil {
+		return storage.SnapshotParams{}, errors.Trace(err)
+	}
+	return storage.SnapshotParams{
+		Id:         in.Id,
+		Volume:     volumeTag,
+		VolumeId:   in.VolumeId,
+		Size:       in.Size,
+		Provider:   storage.ProviderType(in.Provider),
+		Attributes: in.Attributes,
+	}, nil
+}
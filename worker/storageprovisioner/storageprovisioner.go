@@ -38,6 +38,10 @@ type VolumeAccessor interface {
 	// that this storage provisioner is responsible for.
 	WatchVolumeAttachments() (apiwatcher.MachineStorageIdsWatcher, error)
 
+	// WatchVolumeResizes watches for requests to resize volumes that
+	// this storage provisioner is responsible for.
+	WatchVolumeResizes() (apiwatcher.StringsWatcher, error)
+
 	// Volumes returns details of volumes with the specified tags.
 	Volumes([]names.VolumeTag) ([]params.VolumeResult, error)
 
@@ -45,6 +49,10 @@ type VolumeAccessor interface {
 	// the specified volume attachment IDs.
 	VolumeBlockDevices([]params.MachineStorageId) ([]params.BlockDeviceResult, error)
 
+	// MachineBlockDevices returns details of all block devices on the
+	// specified machines.
+	MachineBlockDevices([]names.MachineTag) ([]params.BlockDevicesResult, error)
+
 	// VolumeAttachments returns details of volume attachments with
 	// the specified tags.
 	VolumeAttachments([]params.MachineStorageId) ([]params.VolumeAttachmentResult, error)
@@ -57,6 +65,33 @@ type VolumeAccessor interface {
 	// volume attachments with the specified tags.
 	VolumeAttachmentParams([]params.MachineStorageId) ([]params.VolumeAttachmentParamsResult, error)
 
+	// VolumeResizeParams returns the parameters for resizing the
+	// volumes with the specified tags.
+	VolumeResizeParams([]names.VolumeTag) ([]params.VolumeResizeParamsResult, error)
+
+	// FailVolumeResizes clears the pending requests to resize volumes,
+	// recording the reasons that they could not be carried out.
+	FailVolumeResizes([]params.VolumeResizeFailure) ([]params.ErrorResult, error)
+
+	// WatchSnapshots watches for changes to snapshots of volumes that
+	// this storage provisioner is responsible for.
+	WatchSnapshots() (apiwatcher.StringsWatcher, error)
+
+	// SnapshotParams returns the parameters for taking, or destroying,
+	// the snapshots with the specified IDs.
+	SnapshotParams([]string) ([]params.SnapshotParamsResult, error)
+
+	// SetSnapshotInfo records the details of newly taken snapshots.
+	SetSnapshotInfo([]params.Snapshot) ([]params.ErrorResult, error)
+
+	// SetSnapshotFailures records the reasons that snapshots could
+	// not be taken or destroyed.
+	SetSnapshotFailures([]params.SnapshotFailure) ([]params.ErrorResult, error)
+
+	// RemoveSnapshots removes the snapshots with the specified IDs
+	// from state.
+	RemoveSnapshots([]string) ([]params.ErrorResult, error)
+
 	// SetVolumeInfo records the details of newly provisioned volumes.
 	SetVolumeInfo([]params.Volume) ([]params.ErrorResult, error)
 
@@ -191,6 +226,10 @@ func (w *storageprovisioner) loop() error {
 	var filesystemsWatcher apiwatcher.StringsWatcher
 	var volumesChanges <-chan []string
 	var filesystemsChanges <-chan []string
+	var volumeResizesWatcher apiwatcher.StringsWatcher
+	var volumeResizesChanges <-chan []string
+	var snapshotsWatcher apiwatcher.StringsWatcher
+	var snapshotsChanges <-chan []string
 	var volumeAttachmentsWatcher apiwatcher.MachineStorageIdsWatcher
 	var filesystemAttachmentsWatcher apiwatcher.MachineStorageIdsWatcher
 	var volumeAttachmentsChanges <-chan []params.MachineStorageId
@@ -219,6 +258,8 @@ func (w *storageprovisioner) loop() error {
 	// The other watchers are started dynamically; stop only if started.
 	defer w.maybeStopWatcher(volumesWatcher)
 	defer w.maybeStopWatcher(volumeAttachmentsWatcher)
+	defer w.maybeStopWatcher(volumeResizesWatcher)
+	defer w.maybeStopWatcher(snapshotsWatcher)
 	defer w.maybeStopWatcher(filesystemsWatcher)
 	defer w.maybeStopWatcher(filesystemAttachmentsWatcher)
 
@@ -240,10 +281,20 @@ func (w *storageprovisioner) loop() error {
 		if err != nil {
 			return errors.Annotate(err, "watching filesystem attachments")
 		}
+		volumeResizesWatcher, err = w.volumes.WatchVolumeResizes()
+		if err != nil {
+			return errors.Annotate(err, "watching volume resizes")
+		}
+		snapshotsWatcher, err = w.volumes.WatchSnapshots()
+		if err != nil {
+			return errors.Annotate(err, "watching snapshots")
+		}
 		volumesChanges = volumesWatcher.Changes()
 		filesystemsChanges = filesystemsWatcher.Changes()
 		volumeAttachmentsChanges = volumeAttachmentsWatcher.Changes()
 		filesystemAttachmentsChanges = filesystemAttachmentsWatcher.Changes()
+		volumeResizesChanges = volumeResizesWatcher.Changes()
+		snapshotsChanges = snapshotsWatcher.Changes()
 		return nil
 	}
 
@@ -306,6 +357,20 @@ func (w *storageprovisioner) loop() error {
 			if err := volumeAttachmentsChanged(&ctx, changes); err != nil {
 				return errors.Trace(err)
 			}
+		case changes, ok := <-volumeResizesChanges:
+			if !ok {
+				return watcher.EnsureErr(volumeResizesWatcher)
+			}
+			if err := volumeResizesChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-snapshotsChanges:
+			if !ok {
+				return watcher.EnsureErr(snapshotsWatcher)
+			}
+			if err := snapshotsChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
 		case changes, ok := <-filesystemsChanges:
 			if !ok {
 				return watcher.EnsureErr(filesystemsWatcher)
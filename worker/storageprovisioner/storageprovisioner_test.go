@@ -483,6 +483,248 @@ func (s *storageProvisionerSuite) TestAttachVolumeBackedFilesystem(c *gc.C) {
 	}})
 }
 
+func (s *storageProvisionerSuite) TestVolumeResized(c *gc.C) {
+	s.provider.volumeSourceFunc = func(*config.Config, *storage.Config) (storage.VolumeSource, error) {
+		return &dummyResizableVolumeSource{}, nil
+	}
+
+	volumeInfoSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.provisionedVolumes["volume-1"] = params.Volume{
+		VolumeTag:  "volume-1",
+		VolumeId:   "id-1",
+		Serial:     "serial-1",
+		Size:       1024,
+		Persistent: true,
+	}
+	volumeAccessor.pendingResizes["volume-1"] = 2048
+	volumeAccessor.setVolumeInfo = func(volumes []params.Volume) ([]params.ErrorResult, error) {
+		defer close(volumeInfoSet)
+		// Only the size should have changed.
+		c.Assert(volumes, gc.DeepEquals, []params.Volume{{
+			VolumeTag:  "volume-1",
+			VolumeId:   "id-1",
+			Serial:     "serial-1",
+			Size:       2048,
+			Persistent: true,
+		}})
+		return nil, nil
+	}
+
+	environAccessor := newMockEnvironAccessor(c)
+	worker := storageprovisioner.NewStorageProvisioner(
+		coretesting.EnvironmentTag,
+		"storage-dir",
+		volumeAccessor,
+		newMockFilesystemAccessor(),
+		&mockLifecycleManager{},
+		environAccessor,
+	)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// Volume "2" has no pending resize, and should be ignored.
+	volumeAccessor.resizesWatcher.changes <- []string{"1", "2"}
+	environAccessor.watcher.changes <- struct{}{}
+	waitChannel(c, volumeInfoSet, "waiting for volume info to be set")
+}
+
+func (s *storageProvisionerSuite) TestVolumeResizeNotSupported(c *gc.C) {
+	resizesFailed := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.provisionedVolumes["volume-1"] = params.Volume{
+		VolumeTag: "volume-1",
+		VolumeId:  "id-1",
+		Size:      1024,
+	}
+	volumeAccessor.pendingResizes["volume-1"] = 2048
+	volumeAccessor.setVolumeInfo = func(volumes []params.Volume) ([]params.ErrorResult, error) {
+		c.Fatalf("unexpected call to SetVolumeInfo: %v", volumes)
+		return nil, nil
+	}
+	volumeAccessor.failVolumeResizes = func(failures []params.VolumeResizeFailure) ([]params.ErrorResult, error) {
+		defer close(resizesFailed)
+		c.Assert(failures, gc.DeepEquals, []params.VolumeResizeFailure{{
+			VolumeTag: "volume-1",
+			Reason:    `resizing volumes with storage provider "dummy" not supported`,
+		}})
+		return make([]params.ErrorResult, len(failures)), nil
+	}
+
+	environAccessor := newMockEnvironAccessor(c)
+	worker := storageprovisioner.NewStorageProvisioner(
+		coretesting.EnvironmentTag,
+		"storage-dir",
+		volumeAccessor,
+		newMockFilesystemAccessor(),
+		&mockLifecycleManager{},
+		environAccessor,
+	)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.resizesWatcher.changes <- []string{"1"}
+	environAccessor.watcher.changes <- struct{}{}
+	waitChannel(c, resizesFailed, "waiting for volume resize to fail")
+}
+
+func (s *storageProvisionerSuite) TestSnapshotTaken(c *gc.C) {
+	s.provider.volumeSourceFunc = func(*config.Config, *storage.Config) (storage.VolumeSource, error) {
+		return &dummySnapshottingVolumeSource{}, nil
+	}
+
+	snapshotInfoSet := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.snapshots["1"] = params.SnapshotParams{
+		Id:        "1",
+		Life:      params.Alive,
+		VolumeTag: "volume-1",
+		VolumeId:  "id-1",
+		Size:      1024,
+		Provider:  "dummy",
+	}
+	volumeAccessor.setSnapshotInfo = func(snapshots []params.Snapshot) ([]params.ErrorResult, error) {
+		defer close(snapshotInfoSet)
+		c.Assert(snapshots, gc.DeepEquals, []params.Snapshot{{
+			Id:         "1",
+			VolumeTag:  "volume-1",
+			SnapshotId: "snap-id-1",
+		}})
+		return nil, nil
+	}
+
+	environAccessor := newMockEnvironAccessor(c)
+	worker := storageprovisioner.NewStorageProvisioner(
+		coretesting.EnvironmentTag,
+		"storage-dir",
+		volumeAccessor,
+		newMockFilesystemAccessor(),
+		&mockLifecycleManager{},
+		environAccessor,
+	)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// Snapshot "2" has been removed, and should be ignored.
+	volumeAccessor.snapshotsWatcher.changes <- []string{"1", "2"}
+	environAccessor.watcher.changes <- struct{}{}
+	waitChannel(c, snapshotInfoSet, "waiting for snapshot info to be set")
+}
+
+func (s *storageProvisionerSuite) TestSnapshotFailed(c *gc.C) {
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.snapshots["1"] = params.SnapshotParams{
+		Id:        "1",
+		Life:      params.Alive,
+		VolumeTag: "volume-1",
+		VolumeId:  "id-1",
+		Size:      1024,
+		Provider:  "dummy",
+	}
+	volumeAccessor.snapshots["2"] = params.SnapshotParams{
+		Id:        "2",
+		Life:      params.Alive,
+		VolumeTag: "volume-2",
+		Size:      1024,
+		Provider:  "dummy",
+	}
+	volumeAccessor.snapshots["3"] = params.SnapshotParams{
+		Id:        "3",
+		Life:      params.Alive,
+		VolumeTag: "volume-1",
+		VolumeId:  "id-1",
+		Size:      1024,
+		Provider:  "dummy",
+		Failure:   "already failed",
+	}
+	volumeAccessor.setSnapshotInfo = func(snapshots []params.Snapshot) ([]params.ErrorResult, error) {
+		c.Fatalf("unexpected call to SetSnapshotInfo: %v", snapshots)
+		return nil, nil
+	}
+	failuresSet := make(chan []params.SnapshotFailure, 2)
+	volumeAccessor.setSnapshotFailures = func(failures []params.SnapshotFailure) ([]params.ErrorResult, error) {
+		failuresSet <- failures
+		return make([]params.ErrorResult, len(failures)), nil
+	}
+
+	environAccessor := newMockEnvironAccessor(c)
+	worker := storageprovisioner.NewStorageProvisioner(
+		coretesting.EnvironmentTag,
+		"storage-dir",
+		volumeAccessor,
+		newMockFilesystemAccessor(),
+		&mockLifecycleManager{},
+		environAccessor,
+	)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// Snapshot "3" has already failed, and should be ignored.
+	volumeAccessor.snapshotsWatcher.changes <- []string{"1", "2", "3"}
+	environAccessor.watcher.changes <- struct{}{}
+	var failures []params.SnapshotFailure
+	for len(failures) < 2 {
+		select {
+		case f := <-failuresSet:
+			failures = append(failures, f...)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for snapshot failures to be set")
+		}
+	}
+	c.Assert(failures, jc.SameContents, []params.SnapshotFailure{{
+		Id:     "1",
+		Reason: `snapshots with storage provider "dummy" not supported`,
+	}, {
+		Id:     "2",
+		Reason: "volume 2 not provisioned",
+	}})
+}
+
+func (s *storageProvisionerSuite) TestSnapshotDestroyed(c *gc.C) {
+	s.provider.volumeSourceFunc = func(*config.Config, *storage.Config) (storage.VolumeSource, error) {
+		return &dummySnapshottingVolumeSource{}, nil
+	}
+
+	removed := make(chan interface{})
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.snapshots["1"] = params.SnapshotParams{
+		Id:         "1",
+		Life:       params.Dying,
+		VolumeTag:  "volume-1",
+		Provider:   "dummy",
+		SnapshotId: "snap-id-1",
+	}
+	volumeAccessor.snapshots["2"] = params.SnapshotParams{
+		Id:        "2",
+		Life:      params.Dying,
+		VolumeTag: "volume-1",
+		Provider:  "dummy",
+	}
+	volumeAccessor.removeSnapshots = func(ids []string) ([]params.ErrorResult, error) {
+		defer close(removed)
+		// The snapshot that was never taken is removed
+		// without being destroyed.
+		c.Assert(ids, jc.SameContents, []string{"1", "2"})
+		return make([]params.ErrorResult, len(ids)), nil
+	}
+
+	environAccessor := newMockEnvironAccessor(c)
+	worker := storageprovisioner.NewStorageProvisioner(
+		coretesting.EnvironmentTag,
+		"storage-dir",
+		volumeAccessor,
+		newMockFilesystemAccessor(),
+		&mockLifecycleManager{},
+		environAccessor,
+	)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.snapshotsWatcher.changes <- []string{"1", "2"}
+	environAccessor.watcher.changes <- struct{}{}
+	waitChannel(c, removed, "waiting for snapshots to be removed")
+}
+
 func (s *storageProvisionerSuite) TestUpdateEnvironConfig(c *gc.C) {
 	volumeAccessor := newMockVolumeAccessor()
 	lifecycleManager := &mockLifecycleManager{}
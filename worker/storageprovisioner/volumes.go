@@ -108,6 +108,91 @@ func volumeAttachmentsChanged(ctx *context, ids []params.MachineStorageId) error
 	return nil
 }
 
+// volumeResizesChanged is called when requests to resize the volumes
+// with the provided IDs have been seen.
+func volumeResizesChanged(ctx *context, changes []string) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	tags := make([]names.VolumeTag, len(changes))
+	for i, change := range changes {
+		tags[i] = names.NewVolumeTag(change)
+	}
+	paramsResults, err := ctx.volumeAccessor.VolumeResizeParams(tags)
+	if err != nil {
+		return errors.Annotate(err, "getting volume resize parameters")
+	}
+	resizeParams := make([]storage.VolumeResizeParams, 0, len(paramsResults))
+	for i, result := range paramsResults {
+		if params.IsCodeNotFound(result.Error) {
+			// The resize has already been carried out.
+			logger.Debugf("volume %q has no pending resize, nothing to do", tags[i].Id())
+			continue
+		} else if result.Error != nil {
+			return errors.Annotatef(
+				result.Error, "getting resize parameters for volume %q", tags[i].Id(),
+			)
+		}
+		params, err := volumeResizeParamsFromParams(result.Result)
+		if err != nil {
+			return errors.Annotate(err, "getting volume resize parameters")
+		}
+		resizeParams = append(resizeParams, params)
+	}
+	if len(resizeParams) == 0 {
+		return nil
+	}
+	resized, failures, err := resizeVolumes(ctx.environConfig, ctx.storageDir, resizeParams)
+	if err != nil {
+		return errors.Annotate(err, "resizing volumes")
+	}
+	if err := failVolumeResizes(ctx, failures); err != nil {
+		return errors.Trace(err)
+	}
+	if len(resized) == 0 {
+		return nil
+	}
+
+	// Update the existing volume information with the new sizes,
+	// leaving the other properties intact.
+	resizedTags := make([]names.VolumeTag, len(resized))
+	for i, v := range resized {
+		resizedTags[i] = v.Tag
+	}
+	volumeResults, err := ctx.volumeAccessor.Volumes(resizedTags)
+	if err != nil {
+		return errors.Annotate(err, "getting volume information")
+	}
+	volumes := make([]storage.Volume, len(resized))
+	for i, result := range volumeResults {
+		if result.Error != nil {
+			return errors.Annotatef(
+				result.Error, "getting volume information for volume %q", resizedTags[i].Id(),
+			)
+		}
+		volume, err := volumeFromParams(result.Result)
+		if err != nil {
+			return errors.Annotate(err, "getting volume info")
+		}
+		volume.Size = resized[i].Size
+		volumes[i] = volume
+	}
+	errorResults, err := ctx.volumeAccessor.SetVolumeInfo(volumesFromStorage(volumes))
+	if err != nil {
+		return errors.Annotate(err, "publishing volumes to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(
+				result.Error, "publishing volume %s to state",
+				volumes[i].Tag.Id(),
+			)
+		}
+		ctx.volumes[volumes[i].Tag] = volumes[i]
+	}
+	return nil
+}
+
 // processDeadVolumes processes the VolumeResults for Dead volumes,
 // deprovisioning volumes and removing from state as necessary.
 func processDeadVolumes(ctx *context, tags []names.Tag, volumeResults []params.VolumeResult) error {
@@ -221,8 +306,12 @@ func processAliveVolumes(ctx *context, tags []names.Tag, volumeResults []params.
 		}
 		volumeParams = append(volumeParams, params)
 	}
+	blockDevices, err := machineBlockDevices(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	volumes, volumeAttachments, err := createVolumes(
-		ctx.environConfig, ctx.storageDir, volumeParams,
+		ctx.environConfig, ctx.storageDir, blockDevices, volumeParams,
 	)
 	if err != nil {
 		return errors.Annotate(err, "creating volumes")
@@ -357,10 +446,13 @@ func processPendingVolumeAttachments(ctx *context) error {
 	return nil
 }
 
-// createVolumes creates volumes with the specified parameters.
+// createVolumes creates volumes with the specified parameters. The
+// block devices of the machine, if any, are made available to the
+// volume sources.
 func createVolumes(
 	environConfig *config.Config,
 	baseStorageDir string,
+	blockDevices []storage.BlockDevice,
 	params []storage.VolumeParams,
 ) ([]storage.Volume, []storage.VolumeAttachment, error) {
 	// TODO(axw) later we may have multiple instantiations (sources)
@@ -376,7 +468,7 @@ func createVolumes(
 			continue
 		}
 		volumeSource, err := volumeSource(
-			environConfig, baseStorageDir, sourceName, params.Provider,
+			environConfig, baseStorageDir, blockDevices, sourceName, params.Provider,
 		)
 		if errors.Cause(err) == errNonDynamic {
 			volumeSource = nil
@@ -445,7 +537,7 @@ func createVolumeAttachments(
 			continue
 		}
 		volumeSource, err := volumeSource(
-			environConfig, baseStorageDir, sourceName, params.Provider,
+			environConfig, baseStorageDir, nil, sourceName, params.Provider,
 		)
 		if err != nil {
 			return nil, errors.Annotate(err, "getting volume source")
@@ -464,6 +556,79 @@ func createVolumeAttachments(
 	return allVolumeAttachments, nil
 }
 
+// failVolumeResizes clears the pending requests to resize volumes that
+// could not be resized, recording the reasons in state.
+func failVolumeResizes(ctx *context, failures []params.VolumeResizeFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.volumeAccessor.FailVolumeResizes(failures)
+	if err != nil {
+		return errors.Annotate(err, "publishing volume resize failures to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			return errors.Annotatef(
+				result.Error, "publishing resize failure of volume %s to state",
+				failures[i].VolumeTag,
+			)
+		}
+	}
+	return nil
+}
+
+// resizeVolumes resizes volumes with the specified parameters. Volumes
+// whose sources do not support resizing, or which fail to be resized,
+// are omitted from the resized volumes, and returned as failures.
+func resizeVolumes(
+	environConfig *config.Config,
+	baseStorageDir string,
+	args []storage.VolumeResizeParams,
+) ([]storage.Volume, []params.VolumeResizeFailure, error) {
+	var failures []params.VolumeResizeFailure
+	fail := func(tag names.VolumeTag, err error) {
+		logger.Errorf("cannot resize volume %s: %v", tag.Id(), err)
+		failures = append(failures, params.VolumeResizeFailure{
+			VolumeTag: tag.String(),
+			Reason:    err.Error(),
+		})
+	}
+	volumeResizers := make(map[string]storage.VolumeResizer)
+	paramsBySource := make(map[string][]storage.VolumeResizeParams)
+	for _, arg := range args {
+		sourceName := string(arg.Provider)
+		if _, ok := volumeResizers[sourceName]; !ok {
+			volumeSource, err := volumeSource(
+				environConfig, baseStorageDir, nil, sourceName, arg.Provider,
+			)
+			if err != nil {
+				return nil, nil, errors.Annotate(err, "getting volume source")
+			}
+			resizer, _ := volumeSource.(storage.VolumeResizer)
+			volumeResizers[sourceName] = resizer
+		}
+		if volumeResizers[sourceName] == nil {
+			fail(arg.Tag, errors.NotSupportedf(
+				"resizing volumes with storage provider %q", arg.Provider,
+			))
+			continue
+		}
+		paramsBySource[sourceName] = append(paramsBySource[sourceName], arg)
+	}
+	var allVolumes []storage.Volume
+	for sourceName, args := range paramsBySource {
+		volumes, err := volumeResizers[sourceName].ResizeVolumes(args)
+		if err != nil {
+			for _, arg := range args {
+				fail(arg.Tag, err)
+			}
+			continue
+		}
+		allVolumes = append(allVolumes, volumes...)
+	}
+	return allVolumes, failures, nil
+}
+
 func setVolumeAttachmentInfo(ctx *context, volumeAttachments []storage.VolumeAttachment) error {
 	if len(volumeAttachments) == 0 {
 		return nil
@@ -598,11 +763,26 @@ func volumeParamsFromParams(in params.VolumeParams) (storage.VolumeParams, error
 		}
 	}
 	return storage.VolumeParams{
-		volumeTag,
-		in.Size,
-		providerType,
-		in.Attributes,
-		attachment,
+		Tag:        volumeTag,
+		Size:       in.Size,
+		Provider:   providerType,
+		Attributes: in.Attributes,
+		SnapshotId: in.SnapshotId,
+		Attachment: attachment,
+	}, nil
+}
+
+func volumeResizeParamsFromParams(in params.VolumeResizeParams) (storage.VolumeResizeParams, error) {
+	volumeTag, err := names.ParseVolumeTag(in.VolumeTag)
+	if err != nil {
+		return storage.VolumeResizeParams{}, errors.Trace(err)
+	}
+	return storage.VolumeResizeParams{
+		Tag:        volumeTag,
+		VolumeId:   in.VolumeId,
+		Size:       in.Size,
+		Provider:   storage.ProviderType(in.Provider),
+		Attributes: in.Attributes,
 	}, nil
 }
 
@@ -235,6 +235,15 @@ func (ctx *HookContext) SetUnitStatus(status jujuc.StatusInfo) error {
 	)
 }
 
+func (ctx *HookContext) SetServiceStatus(status jujuc.StatusInfo) error {
+	logger.Debugf("[SERVICE-STATUS] %s %s", status.Status, status.Info)
+	return ctx.unit.SetServiceStatus(
+		params.Status(status.Status),
+		status.Info,
+		status.Data,
+	)
+}
+
 func (ctx *HookContext) HasExecutionSetUnitStatus() bool {
 	return ctx.hasRunStatusSet
 }
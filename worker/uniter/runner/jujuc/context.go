@@ -42,6 +42,10 @@ type Context interface {
 	// SetUnitStatus updates the unit's status.
 	SetUnitStatus(StatusInfo) error
 
+	// SetServiceStatus updates the status of the unit's service.
+	// Only the service's leader unit may do so.
+	SetServiceStatus(StatusInfo) error
+
 	// PublicAddress returns the executing unit's public address.
 	PublicAddress() (string, bool)
 
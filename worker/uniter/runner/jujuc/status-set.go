@@ -6,6 +6,7 @@ package jujuc
 import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
 )
@@ -16,6 +17,7 @@ type StatusSetCommand struct {
 	ctx     Context
 	status  string
 	message string
+	service bool
 }
 
 // NewStatusSetCommand makes a jujuc status-set command.
@@ -28,6 +30,9 @@ func (c *StatusSetCommand) Info() *cmd.Info {
 Sets the workload status of the charm. Message is optional.
 The "last updated" attribute of the status is set, even if the
 status and message are the same as what's already set.
+
+With --service, the status of the unit's service is set instead.
+Only the leader unit of the service may set its status.
 `
 	return &cmd.Info{
 		Name:    "status-set",
@@ -44,6 +49,10 @@ var validStatus = []params.Status{
 	params.StatusActive,
 }
 
+func (c *StatusSetCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.service, "service", false, "set the status of the service instead of the unit")
+}
+
 func (c *StatusSetCommand) Init(args []string) error {
 	if len(args) < 1 {
 		return errors.Errorf("invalid args, require <status> [message]")
@@ -67,8 +76,12 @@ func (c *StatusSetCommand) Init(args []string) error {
 }
 
 func (c *StatusSetCommand) Run(ctx *cmd.Context) error {
-	return c.ctx.SetUnitStatus(StatusInfo{
+	status := StatusInfo{
 		Status: c.status,
 		Info:   c.message,
-	})
+	}
+	if c.service {
+		return c.ctx.SetServiceStatus(status)
+	}
+	return c.ctx.SetUnitStatus(status)
 }
@@ -29,6 +29,7 @@ var statusSetInitTests = []struct {
 	{[]string{"maintenance"}, ""},
 	{[]string{"maintenance", ""}, ""},
 	{[]string{"maintenance", "hello"}, ""},
+	{[]string{"--service", "blocked", "hello"}, ""},
 	{[]string{}, `invalid args, require <status> \[message\]`},
 	{[]string{"maintenance", "hello", "extra"}, `unrecognized args: \["extra"\]`},
 	{[]string{"foo", "hello"}, `invalid status "foo", expected one of \[maintenance blocked waiting active\]`},
@@ -51,12 +52,19 @@ func (s *statusSetSuite) TestHelp(c *gc.C) {
 	ctx := testing.Context(c)
 	code := cmd.Main(com, ctx, []string{"--help"})
 	c.Assert(code, gc.Equals, 0)
-	c.Assert(bufferString(ctx.Stdout), gc.Equals, `usage: status-set <maintenance | blocked | waiting | active> [message]
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, `usage: status-set [options] <maintenance | blocked | waiting | active> [message]
 purpose: set status information
 
+options:
+--service  (= false)
+    set the status of the service instead of the unit
+
 Sets the workload status of the charm. Message is optional.
 The "last updated" attribute of the status is set, even if the
 status and message are the same as what's already set.
+
+With --service, the status of the unit's service is set instead.
+Only the leader unit of the service may set its status.
 `)
 	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
 }
@@ -81,3 +89,20 @@ func (s *statusSetSuite) TestStatus(c *gc.C) {
 		c.Assert(status.Info, gc.Equals, args[1])
 	}
 }
+
+func (s *statusSetSuite) TestServiceStatus(c *gc.C) {
+	hctx := s.GetStatusHookContext(c)
+	com, err := jujuc.NewCommand(hctx, cmdString("status-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--service", "blocked", "need db"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Assert(hctx.ServiceStatus(), jc.DeepEquals, jujuc.StatusInfo{
+		Status: "blocked",
+		Info:   "need db",
+	})
+	status, err := hctx.UnitStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*status, jc.DeepEquals, jujuc.StatusInfo{})
+}
@@ -121,6 +121,7 @@ type Context struct {
 	storageTag     names.StorageTag
 	storage        map[names.StorageTag]*ContextStorage
 	status         jujuc.StatusInfo
+	serviceStatus  jujuc.StatusInfo
 }
 
 func (c *Context) AddMetric(key, value string, created time.Time) error {
@@ -144,6 +145,15 @@ func (c *Context) SetUnitStatus(status jujuc.StatusInfo) error {
 	return nil
 }
 
+func (c *Context) SetServiceStatus(status jujuc.StatusInfo) error {
+	c.serviceStatus = status
+	return nil
+}
+
+func (c *Context) ServiceStatus() jujuc.StatusInfo {
+	return c.serviceStatus
+}
+
 func (c *Context) PublicAddress() (string, bool) {
 	return "gimli.minecraft.testing.invalid", true
 }
@@ -38,6 +38,13 @@ type storageHookQueue struct {
 	// hook has been executed.
 	attached bool
 
+	// size records the size of block storage, in MiB, when the
+	// storage-attached hook was most recently queued. If the
+	// storage's volume grows beyond this size, storage-attached
+	// is queued again so that the charm may make use of the new
+	// capacity.
+	size uint64
+
 	// hookInfo is the next hook.Info to return, if non-nil.
 	hookInfo *hook.Info
 
@@ -170,11 +177,19 @@ func (s *storageHookQueue) Update(attachment params.StorageAttachment) error {
 	switch attachment.Life {
 	case params.Alive:
 		if s.attached {
-			// Storage attachments currently do not change
-			// (apart from lifecycle) after being provisioned.
-			// We don't process unprovisioned storage here,
-			// so there's nothing to do.
-			return nil
+			// Storage attachments do not change (apart from
+			// lifecycle) after being provisioned, except for
+			// the size of block storage. If the size is not
+			// yet known (i.e. the storage was attached before
+			// the uniter started), then we just record it.
+			if s.size == 0 || attachment.Size <= s.size {
+				s.size = attachment.Size
+				return nil
+			}
+			logger.Debugf(
+				"storage %q resized from %dMiB to %dMiB",
+				s.storageTag.Id(), s.size, attachment.Size,
+			)
 		}
 	case params.Dying:
 		if !s.attached {
@@ -209,6 +224,7 @@ func (s *storageHookQueue) Update(attachment params.StorageAttachment) error {
 	}
 	if attachment.Life == params.Alive {
 		s.hookInfo.Kind = hooks.StorageAttached
+		s.size = attachment.Size
 	} else {
 		// TODO(axw) this should be Detaching, not Detached.
 		s.hookInfo.Kind = hooks.StorageDetached
@@ -62,6 +62,43 @@ func (s *storageHookQueueSuite) TestStorageHookQueueAlreadyAttached(c *gc.C) {
 	c.Assert(q.Empty(), jc.IsTrue)
 }
 
+func (s *storageHookQueueSuite) TestStorageHookQueueResized(c *gc.C) {
+	update := func(q storage.StorageHookQueue, size uint64) {
+		err := q.Update(params.StorageAttachment{
+			Life:     params.Alive,
+			Kind:     params.StorageKindBlock,
+			Location: "/dev/sdb",
+			Size:     size,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	q := newHookQueue(initiallyUnattached)
+	update(q, 1024)
+	q.Pop()
+	update(q, 1024)
+	c.Assert(q.Empty(), jc.IsTrue)
+
+	// Growing the storage reruns storage-attached.
+	update(q, 2048)
+	c.Assert(q.Empty(), jc.IsFalse)
+	c.Assert(q.Next(), gc.Equals, hook.Info{
+		Kind:      hooks.StorageAttached,
+		StorageId: "data/0",
+	})
+	q.Pop()
+	update(q, 2048)
+	c.Assert(q.Empty(), jc.IsTrue)
+
+	// The size of storage attached before the queue was
+	// created is recorded, and does not cause a hook.
+	q = newHookQueue(initiallyAttached)
+	update(q, 2048)
+	c.Assert(q.Empty(), jc.IsTrue)
+	update(q, 4096)
+	c.Assert(q.Empty(), jc.IsFalse)
+}
+
 func (s *storageHookQueueSuite) TestStorageHookQueueAttachedDetach(c *gc.C) {
 	q := newHookQueue(initiallyAttached)
 	updateHookQueue(c, q, params.Dying)
@@ -38,9 +38,8 @@ func (s *state) ValidateHook(hi hook.Info) (err error) {
 	}
 	switch hi.Kind {
 	case hooks.StorageAttached:
-		if s.attached {
-			return errors.New("storage already attached")
-		}
+		// storage-attached may be run for storage that is
+		// already attached, when the storage is resized.
 	case hooks.StorageDetached: // TODO(axw) this should be "detaching"
 		if !s.attached {
 			return errors.New("storage not attached")
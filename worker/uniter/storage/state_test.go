@@ -197,5 +197,6 @@ func (s *stateSuite) TestValidateHook(c *gc.C) {
 	assertValidates(false, hooks.StorageAttached)
 	assertValidates(true, hooks.StorageDetached)
 	assertValidateFails(false, hooks.StorageDetached, `inappropriate "storage-detached" hook for storage "data/0": storage not attached`)
-	assertValidateFails(true, hooks.StorageAttached, `inappropriate "storage-attached" hook for storage "data/0": storage already attached`)
+	// storage-attached is rerun for attached storage when it is resized.
+	assertValidates(true, hooks.StorageAttached)
 }
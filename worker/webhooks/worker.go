@@ -0,0 +1,371 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package webhooks implements a worker that notifies the webhooks
+// registered in an environment when the status of a machine, unit
+// or service changes.
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.webhooks")
+
+// Params specifies how notifications are delivered.
+type Params struct {
+	// MaxAttempts is the number of times a notification is POSTed
+	// before delivery is abandoned.
+	MaxAttempts int
+
+	// RetryDelay is the delay before the first retry; it doubles
+	// after each subsequent failed attempt.
+	RetryDelay time.Duration
+
+	// Timeout bounds each POST request.
+	Timeout time.Duration
+
+	// QueueSize is the number of notifications that may be waiting
+	// for delivery to each webhook; further notifications are
+	// dropped until the queue drains.
+	QueueSize int
+}
+
+const (
+	DefaultMaxAttempts = 5
+	DefaultRetryDelay  = 5 * time.Second
+	DefaultTimeout     = 30 * time.Second
+	DefaultQueueSize   = 100
+)
+
+// NewParams returns Params initialized with default values.
+func NewParams() Params {
+	return Params{
+		MaxAttempts: DefaultMaxAttempts,
+		RetryDelay:  DefaultRetryDelay,
+		Timeout:     DefaultTimeout,
+		QueueSize:   DefaultQueueSize,
+	}
+}
+
+// Notification is the JSON payload POSTed to a webhook when an
+// entity's status changes.
+type Notification struct {
+	WebhookId      string    `json:"webhook-id"`
+	EnvUUID        string    `json:"env-uuid"`
+	Entity         string    `json:"entity"`
+	Kind           string    `json:"kind"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous-status,omitempty"`
+	Message        string    `json:"message,omitempty"`
+	Time           time.Time `json:"time"`
+}
+
+// The kinds of status a notification can be sent for.
+const (
+	KindMachine  = "machine"
+	KindAgent    = "agent"
+	KindWorkload = "workload"
+	KindService  = "service"
+)
+
+type webhookWorker struct {
+	st        *state.State
+	params    Params
+	transport *http.Transport
+	client    *http.Client
+
+	// last holds the most recently seen status of each entity,
+	// keyed by kind and tag.
+	last map[statusKey]state.Status
+
+	// queues holds the notifications waiting for delivery to each
+	// webhook, keyed by webhook id. Each queue is drained by its
+	// own goroutine, so a slow webhook does not delay the others.
+	queues map[string]chan Notification
+
+	wg sync.WaitGroup
+}
+
+type statusKey struct {
+	kind string
+	tag  string
+}
+
+// New returns a worker that watches the environment for status
+// transitions, POSTing a Notification to each matching webhook.
+func New(st *state.State, params Params) worker.Worker {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	w := &webhookWorker{
+		st:        st,
+		params:    params,
+		transport: transport,
+		client:    &http.Client{Transport: transport, Timeout: params.Timeout},
+		last:      make(map[statusKey]state.Status),
+		queues:    make(map[string]chan Notification),
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+func (w *webhookWorker) loop(stopCh <-chan struct{}) error {
+	defer w.wg.Wait()
+	watcher := w.st.Watch()
+	defer watcher.Stop()
+
+	type nextResult struct {
+		deltas []multiwatcher.Delta
+		err    error
+	}
+	results := make(chan nextResult)
+	go func() {
+		for {
+			deltas, err := watcher.Next()
+			select {
+			case results <- nextResult{deltas, err}:
+			case <-stopCh:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// The first set of deltas describes the environment as it is
+	// when the worker starts; it is recorded without notifying.
+	initial := true
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case result := <-results:
+			if result.err != nil {
+				return errors.Trace(result.err)
+			}
+			notifications := w.transitions(result.deltas)
+			if initial {
+				initial = false
+				continue
+			}
+			if err := w.notify(notifications, stopCh); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// transitions records the statuses held in the given deltas and
+// returns a notification for each that differs from the status
+// previously seen for its entity.
+func (w *webhookWorker) transitions(deltas []multiwatcher.Delta) []Notification {
+	var notifications []Notification
+	check := func(kind string, tag names.Tag, status multiwatcher.Status, message string) {
+		key := statusKey{kind, tag.String()}
+		previous, seen := w.last[key]
+		current := state.Status(status)
+		if current == "" || seen && previous == current {
+			return
+		}
+		w.last[key] = current
+		notifications = append(notifications, Notification{
+			EnvUUID:        w.st.EnvironUUID(),
+			Entity:         tag.String(),
+			Kind:           kind,
+			Status:         string(current),
+			PreviousStatus: string(previous),
+			Message:        message,
+			Time:           time.Now().UTC(),
+		})
+	}
+	forget := func(tag names.Tag, kinds ...string) {
+		for _, kind := range kinds {
+			delete(w.last, statusKey{kind, tag.String()})
+		}
+	}
+	for _, delta := range deltas {
+		switch info := delta.Entity.(type) {
+		case *multiwatcher.MachineInfo:
+			tag := names.NewMachineTag(info.Id)
+			if delta.Removed {
+				forget(tag, KindMachine)
+				continue
+			}
+			check(KindMachine, tag, info.Status, info.StatusInfo)
+		case *multiwatcher.UnitInfo:
+			tag := names.NewUnitTag(info.Name)
+			if delta.Removed {
+				forget(tag, KindAgent, KindWorkload)
+				continue
+			}
+			check(KindAgent, tag, info.AgentStatus.Current, info.AgentStatus.Message)
+			check(KindWorkload, tag, info.WorkloadStatus.Current, info.WorkloadStatus.Message)
+		case *multiwatcher.ServiceInfo:
+			tag := names.NewServiceTag(info.Name)
+			if delta.Removed {
+				forget(tag, KindService)
+				continue
+			}
+			check(KindService, tag, info.Status.Current, info.Status.Message)
+		}
+	}
+	return notifications
+}
+
+// notify queues each notification for delivery to every webhook
+// that matches it. If a webhook's queue is full, the notification
+// is dropped for that webhook.
+func (w *webhookWorker) notify(notifications []Notification, stopCh <-chan struct{}) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	webhooks, err := w.st.AllWebhooks()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Stop delivering to webhooks that have been removed.
+	current := make(map[string]bool)
+	for _, webhook := range webhooks {
+		current[webhook.Id()] = true
+	}
+	for id, queue := range w.queues {
+		if !current[id] {
+			close(queue)
+			delete(w.queues, id)
+		}
+	}
+	for _, n := range notifications {
+		for _, webhook := range webhooks {
+			if !webhook.Matches(n.Entity, state.Status(n.Status)) {
+				continue
+			}
+			n.WebhookId = webhook.Id()
+			select {
+			case w.queue(webhook, stopCh) <- n:
+			default:
+				logger.Warningf("dropping notification of %s status %q: queue for webhook %s is full",
+					n.Entity, n.Status, webhook.Id())
+			}
+		}
+	}
+	return nil
+}
+
+// queue returns the delivery queue for the webhook, starting a
+// goroutine to drain it if it does not already exist.
+func (w *webhookWorker) queue(webhook *state.Webhook, stopCh <-chan struct{}) chan<- Notification {
+	if queue, ok := w.queues[webhook.Id()]; ok {
+		return queue
+	}
+	queue := make(chan Notification, w.params.QueueSize)
+	w.queues[webhook.Id()] = queue
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case n, ok := <-queue:
+				if !ok {
+					return
+				}
+				w.deliver(webhook, n, stopCh)
+			}
+		}
+	}()
+	return queue
+}
+
+// deliver POSTs the notification to the webhook, retrying with
+// exponential backoff until it succeeds, the maximum number of
+// attempts is reached, or the worker is stopped. The outcome is
+// recorded in the webhook's delivery log.
+func (w *webhookWorker) deliver(webhook *state.Webhook, n Notification, stopCh <-chan struct{}) {
+	body, err := json.Marshal(n)
+	if err != nil {
+		logger.Errorf("cannot marshal notification: %v", err)
+		return
+	}
+	delivery := state.WebhookDelivery{
+		Entity: n.Entity,
+		Status: state.Status(n.Status),
+	}
+	delay := w.params.RetryDelay
+	for {
+		delivery.Attempts++
+		delivery.ResponseCode, err = w.post(webhook.URL(), body, stopCh)
+		if err == nil {
+			delivery.Error = ""
+			break
+		}
+		delivery.Error = err.Error()
+		logger.Debugf("delivery of %s status %q to webhook %s failed (attempt %d): %v",
+			n.Entity, n.Status, webhook.Id(), delivery.Attempts, err)
+		select {
+		case <-stopCh:
+			// The request may have been cancelled; the
+			// failure is not the webhook's to record.
+			return
+		default:
+		}
+		if delivery.Attempts >= w.params.MaxAttempts {
+			logger.Warningf("giving up delivery of %s status %q to webhook %s: %v",
+				n.Entity, n.Status, webhook.Id(), err)
+			break
+		}
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	delivery.Time = time.Now().UTC()
+	if err := webhook.RecordDelivery(delivery); err != nil {
+		logger.Errorf("%v", err)
+	}
+}
+
+// post sends body to the given URL, returning the response code
+// and an error if no response was received or the response code
+// does not indicate success. The request is cancelled if the worker
+// is stopped.
+func (w *webhookWorker) post(url string, body []byte, stopCh <-chan struct{}) (int, error) {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stopCh:
+			w.transport.CancelRequest(req)
+		case <-done:
+		}
+	}()
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response %q", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
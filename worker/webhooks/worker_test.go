@@ -0,0 +1,228 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/webhooks"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type webhooksSuite struct {
+	testing.JujuConnSuite
+	unit          *state.Unit
+	server        *httptest.Server
+	notifications chan webhooks.Notification
+
+	// mu guards failures, which is read by the
+	// server's handler goroutines.
+	mu       sync.Mutex
+	failures int
+}
+
+var _ = gc.Suite(&webhooksSuite{})
+
+func (s *webhooksSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.setFailures(0)
+	s.notifications = make(chan webhooks.Notification, 10)
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit = unit
+}
+
+func (s *webhooksSuite) setFailures(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = n
+}
+
+// fail reports whether the request should fail,
+// consuming one of the remaining failures.
+func (s *webhooksSuite) fail() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return true
+	}
+	return false
+}
+
+func (s *webhooksSuite) handle(w http.ResponseWriter, req *http.Request) {
+	if s.fail() {
+		http.Error(w, "try again", http.StatusServiceUnavailable)
+		return
+	}
+	var n webhooks.Notification
+	if err := json.NewDecoder(req.Body).Decode(&n); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.notifications <- n
+}
+
+func (s *webhooksSuite) startWorker(c *gc.C) worker.Worker {
+	w := webhooks.New(s.State, webhooks.Params{
+		MaxAttempts: 3,
+		RetryDelay:  time.Millisecond,
+		Timeout:     coretesting.LongWait,
+		QueueSize:   10,
+	})
+	s.AddCleanup(func(c *gc.C) { c.Assert(worker.Stop(w), jc.ErrorIsNil) })
+	// Let the worker see the initial state of the environment.
+	s.State.StartSync()
+	time.Sleep(coretesting.ShortWait)
+	return w
+}
+
+func (s *webhooksSuite) waitNotification(c *gc.C) webhooks.Notification {
+	timeout := time.After(coretesting.LongWait)
+	for {
+		s.State.StartSync()
+		select {
+		case n := <-s.notifications:
+			return n
+		case <-time.After(coretesting.ShortWait):
+		case <-timeout:
+			c.Fatalf("timed out waiting for notification")
+		}
+	}
+}
+
+func (s *webhooksSuite) assertNoNotification(c *gc.C) {
+	s.State.StartSync()
+	select {
+	case n := <-s.notifications:
+		c.Fatalf("unexpected notification %#v", n)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *webhooksSuite) TestNotifiesMatchingTransition(c *gc.C) {
+	webhook, err := s.State.AddWebhook(state.WebhookParams{
+		URL:           s.server.URL,
+		EntityPattern: "unit-wordpress-*",
+		Statuses:      []state.Status{state.StatusError},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.startWorker(c)
+
+	err = s.unit.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoNotification(c)
+
+	err = s.unit.SetAgentStatus(state.StatusError, `hook failed: "install"`, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	n := s.waitNotification(c)
+	c.Assert(n.Time.IsZero(), jc.IsFalse)
+	n.Time = time.Time{}
+	c.Assert(n, jc.DeepEquals, webhooks.Notification{
+		WebhookId:      webhook.Id(),
+		EnvUUID:        s.State.EnvironUUID(),
+		Entity:         "unit-wordpress-0",
+		Kind:           webhooks.KindAgent,
+		Status:         "error",
+		PreviousStatus: "idle",
+		Message:        `hook failed: "install"`,
+	})
+	s.assertNoNotification(c)
+}
+
+func (s *webhooksSuite) TestRetriesAndRecordsDelivery(c *gc.C) {
+	webhook, err := s.State.AddWebhook(state.WebhookParams{
+		URL:      s.server.URL,
+		Statuses: []state.Status{state.StatusError},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.setFailures(2)
+	s.startWorker(c)
+
+	err = s.unit.SetAgentStatus(state.StatusError, "boom", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	n := s.waitNotification(c)
+	c.Assert(n.Entity, gc.Equals, "unit-wordpress-0")
+
+	var deliveries []state.WebhookDelivery
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		deliveries, err = webhook.Deliveries(10)
+		c.Assert(err, jc.ErrorIsNil)
+		if len(deliveries) > 0 {
+			break
+		}
+	}
+	c.Assert(deliveries, gc.HasLen, 1)
+	c.Assert(deliveries[0].Entity, gc.Equals, "unit-wordpress-0")
+	c.Assert(deliveries[0].Status, gc.Equals, state.StatusError)
+	c.Assert(deliveries[0].Attempts, gc.Equals, 3)
+	c.Assert(deliveries[0].ResponseCode, gc.Equals, http.StatusOK)
+	c.Assert(deliveries[0].Error, gc.Equals, "")
+}
+
+func (s *webhooksSuite) TestStopCancelsDelivery(c *gc.C) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		select {
+		case <-release:
+		case <-time.After(coretesting.LongWait):
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	webhook, err := s.State.AddWebhook(state.WebhookParams{
+		URL:      server.URL,
+		Statuses: []state.Status{state.StatusError},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	w := s.startWorker(c)
+
+	err = s.unit.SetAgentStatus(state.StatusError, "boom", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.State.StartSync()
+	select {
+	case <-started:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for delivery")
+	}
+
+	stopped := make(chan error)
+	go func() { stopped <- worker.Stop(w) }()
+	select {
+	case err := <-stopped:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.ShortWait * 10):
+		c.Fatalf("worker did not stop while a delivery was in progress")
+	}
+
+	// The cancelled delivery is not recorded.
+	deliveries, err := webhook.Deliveries(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deliveries, gc.HasLen, 0)
+}